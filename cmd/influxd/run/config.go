@@ -16,6 +16,7 @@ import (
 	"github.com/ayang64/reflux/monitor"
 	"github.com/ayang64/reflux/monitor/diagnostics"
 	"github.com/ayang64/reflux/pkg/tlsconfig"
+	"github.com/ayang64/reflux/services/audit"
 	"github.com/ayang64/reflux/services/collectd"
 	"github.com/ayang64/reflux/services/continuous_querier"
 	"github.com/ayang64/reflux/services/graphite"
@@ -24,8 +25,10 @@ import (
 	"github.com/ayang64/reflux/services/opentsdb"
 	"github.com/ayang64/reflux/services/precreator"
 	"github.com/ayang64/reflux/services/retention"
+	"github.com/ayang64/reflux/services/storage"
 	"github.com/ayang64/reflux/services/subscriber"
 	"github.com/ayang64/reflux/services/udp"
+	"github.com/ayang64/reflux/services/wire"
 	itoml "github.com/ayang64/reflux/toml"
 	"github.com/ayang64/reflux/tsdb"
 	"golang.org/x/text/encoding/unicode"
@@ -47,12 +50,15 @@ type Config struct {
 
 	Monitor        monitor.Config    `toml:"monitor"`
 	Subscriber     subscriber.Config `toml:"subscriber"`
+	Audit          audit.Config      `toml:"audit"`
 	HTTPD          httpd.Config      `toml:"http"`
 	Logging        logger.Config     `toml:"logging"`
 	GraphiteInputs []graphite.Config `toml:"graphite"`
 	CollectdInputs []collectd.Config `toml:"collectd"`
 	OpenTSDBInputs []opentsdb.Config `toml:"opentsdb"`
 	UDPInputs      []udp.Config      `toml:"udp"`
+	Wire           wire.Config       `toml:"wire"`
+	Storage        storage.Config    `toml:"storage"`
 
 	ContinuousQuery continuous_querier.Config `toml:"continuous_queries"`
 
@@ -76,6 +82,7 @@ func NewConfig() *Config {
 
 	c.Monitor = monitor.NewConfig()
 	c.Subscriber = subscriber.NewConfig()
+	c.Audit = audit.NewConfig()
 	c.HTTPD = httpd.NewConfig()
 	c.Logging = logger.NewConfig()
 
@@ -83,6 +90,8 @@ func NewConfig() *Config {
 	c.CollectdInputs = []collectd.Config{collectd.NewConfig()}
 	c.OpenTSDBInputs = []opentsdb.Config{opentsdb.NewConfig()}
 	c.UDPInputs = []udp.Config{udp.NewConfig()}
+	c.Wire = wire.NewConfig()
+	c.Storage = storage.NewConfig()
 
 	c.ContinuousQuery = continuous_querier.NewConfig()
 	c.Retention = retention.NewConfig()
@@ -109,6 +118,8 @@ func NewDemoConfig() (*Config, error) {
 	c.Meta.Dir = filepath.Join(homeDir, ".influxdb/meta")
 	c.Data.Dir = filepath.Join(homeDir, ".influxdb/data")
 	c.Data.WALDir = filepath.Join(homeDir, ".influxdb/wal")
+	c.Subscriber.QueueDir = filepath.Join(homeDir, ".influxdb/subscriber")
+	c.Audit.Path = filepath.Join(homeDir, ".influxdb/audit.log")
 
 	return c, nil
 }
@@ -177,6 +188,18 @@ func (c *Config) Validate() error {
 		return err
 	}
 
+	if err := c.Audit.Validate(); err != nil {
+		return fmt.Errorf("invalid audit config: %v", err)
+	}
+
+	if err := c.Wire.Validate(); err != nil {
+		return fmt.Errorf("invalid wire config: %v", err)
+	}
+
+	if err := c.Storage.Validate(); err != nil {
+		return fmt.Errorf("invalid storage config: %v", err)
+	}
+
 	for _, graphite := range c.GraphiteInputs {
 		if err := graphite.Validate(); err != nil {
 			return fmt.Errorf("invalid graphite config: %v", err)
@@ -222,7 +245,10 @@ func (c *Config) diagnosticsClients() map[string]diagnostics.Client {
 
 		"config-monitor":    c.Monitor,
 		"config-subscriber": c.Subscriber,
+		"config-audit":      c.Audit,
 		"config-httpd":      c.HTTPD,
+		"config-wire":       c.Wire,
+		"config-storage":    c.Storage,
 
 		"config-cqs": c.ContinuousQuery,
 	}
@@ -11,6 +11,7 @@ import (
 	"path/filepath"
 	"runtime"
 	"runtime/pprof"
+	"sync"
 	"time"
 
 	influxdb "github.com/ayang64/reflux"
@@ -20,6 +21,7 @@ import (
 	"github.com/ayang64/reflux/models"
 	"github.com/ayang64/reflux/monitor"
 	"github.com/ayang64/reflux/query"
+	"github.com/ayang64/reflux/services/audit"
 	"github.com/ayang64/reflux/services/collectd"
 	"github.com/ayang64/reflux/services/continuous_querier"
 	"github.com/ayang64/reflux/services/graphite"
@@ -34,6 +36,7 @@ import (
 	"github.com/ayang64/reflux/services/udp"
 	"github.com/ayang64/reflux/services/wire"
 	"github.com/ayang64/reflux/storage/reads"
+	"github.com/ayang64/reflux/task"
 	"github.com/ayang64/reflux/tcp"
 	"github.com/ayang64/reflux/tsdb"
 	client "github.com/influxdata/usage-client/v1"
@@ -80,8 +83,19 @@ type Server struct {
 	PointsWriter  *coordinator.PointsWriter
 	Subscriber    *subscriber.Service
 
+	// AuditLog records authentication attempts and executed statements.
+	AuditLog *audit.Service
+
+	// Tasks tracks long-running background work such as compactions,
+	// backups and retention enforcement.
+	Tasks *task.Manager
+
 	Services []Service
 
+	// cancel stops the services started by Open.
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
 	// These references are required for the tcp muxer.
 	SnapshotterService *snapshotter.Service
 
@@ -192,8 +206,13 @@ func NewServer(c *Config, buildInfo *BuildInfo) (*Server, error) {
 		return nil, err
 	}
 
+	if s.Tasks, err = task.NewManager(); err != nil {
+		return nil, err
+	}
+
 	s.TSDBStore = tsdb.NewStore(c.Data.Dir)
 	s.TSDBStore.EngineOptions.Config = c.Data
+	s.TSDBStore.EngineOptions.TaskManager = s.Tasks
 
 	// Copy TSDB configuration.
 	s.TSDBStore.EngineOptions.EngineVersion = c.Data.Engine
@@ -202,16 +221,31 @@ func NewServer(c *Config, buildInfo *BuildInfo) (*Server, error) {
 	// Create the Subscriber service
 	s.Subscriber = subscriber.NewService(c.Subscriber)
 
+	// Create the audit log
+	s.AuditLog = audit.NewService(c.Audit)
+	var auditLog coordinator.AuditLog
+	if c.Audit.Enabled {
+		auditLog = s.AuditLog
+		s.MetaClient.WithAuditLog(s.AuditLog)
+	}
+
 	// Initialize points writer.
 	s.PointsWriter = coordinator.NewPointsWriter()
 	s.PointsWriter.WriteTimeout = time.Duration(c.Coordinator.WriteTimeout)
 	s.PointsWriter.TSDBStore = s.TSDBStore
+	if c.Coordinator.MaxBatchIDs > 0 {
+		s.PointsWriter.BatchIDs = coordinator.NewBatchIDStore(filepath.Join(c.Meta.Dir, "batches.db"))
+		s.PointsWriter.BatchIDs.MaxBatchIDs = c.Coordinator.MaxBatchIDs
+	}
 
 	// Initialize query executor.
 	s.QueryExecutor = query.NewExecutor()
 	s.QueryExecutor.StatementExecutor = &coordinator.StatementExecutor{
 		MetaClient:  s.MetaClient,
 		TaskManager: s.QueryExecutor.TaskManager,
+		Tasks:       s.Tasks,
+		Subscriber:  s.Subscriber,
+		Quotas:      &quotaUsage{PointsWriter: s.PointsWriter, TaskManager: s.QueryExecutor.TaskManager},
 		TSDBStore:   s.TSDBStore,
 		ShardMapper: &coordinator.LocalShardMapper{
 			MetaClient: s.MetaClient,
@@ -219,14 +253,22 @@ func NewServer(c *Config, buildInfo *BuildInfo) (*Server, error) {
 		},
 		Monitor:           s.Monitor,
 		PointsWriter:      s.PointsWriter,
+		AuditLog:          auditLog,
 		MaxSelectPointN:   c.Coordinator.MaxSelectPointN,
 		MaxSelectSeriesN:  c.Coordinator.MaxSelectSeriesN,
 		MaxSelectBucketsN: c.Coordinator.MaxSelectBucketsN,
+		MaxJoinPointN:     c.Coordinator.MaxJoinPointN,
 	}
 
 	s.QueryExecutor.TaskManager.QueryTimeout = time.Duration(c.Coordinator.QueryTimeout)
 	s.QueryExecutor.TaskManager.LogQueriesAfter = time.Duration(c.Coordinator.LogQueriesAfter)
 	s.QueryExecutor.TaskManager.MaxConcurrentQueries = c.Coordinator.MaxConcurrentQueries
+	s.QueryExecutor.TaskManager.DatabaseMaxConcurrentQueries = func(database string) int {
+		if di := s.MetaClient.Database(database); di != nil && di.Quota != nil {
+			return di.Quota.MaxConcurrentQueries
+		}
+		return 0
+	}
 
 	// Initialize the monitor
 	s.Monitor.Version = s.buildInfo.Version
@@ -252,14 +294,13 @@ func (s *Server) Statistics(tags map[string]string) []models.Statistic {
 	return statistics
 }
 
-func (s *Server) NewSnapshotterService() (*snapshotter.Service, error) {
+func (s *Server) appendSnapshotterService() {
 	srv := snapshotter.NewService()
 	srv.TSDBStore = s.TSDBStore
 	srv.MetaClient = s.MetaClient
+	srv.Tasks = s.Tasks
 	s.Services = append(s.Services, srv)
 	s.SnapshotterService = srv
-
-	return srv, nil
 }
 
 // SetLogOutput sets the logger used for all messages. It must not be called
@@ -268,18 +309,21 @@ func (s *Server) SetLogOutput(w io.Writer) {
 	s.Logger = logger.New(w)
 }
 
-func (s *Server) appendMonitorService() {
-	s.Services = append(s.Services, s.Monitor)
-}
-
-func (s *Server) NewRetentionPolicyService() *retention.Service {
-	srv := retention.NewService(s.config.Retention)
+func (s *Server) appendRetentionPolicyService(c retention.Config) {
+	if !c.Enabled {
+		return
+	}
+	srv := retention.NewService(c)
 	srv.MetaClient = s.MetaClient
 	srv.TSDBStore = s.TSDBStore
-	return srv
+	srv.Tasks = s.Tasks
+	s.Services = append(s.Services, srv)
 }
 
-func (s *Server) NewHTTPDService() *httpd.Service {
+func (s *Server) appendHTTPDService(c httpd.Config) {
+	if !c.Enabled {
+		return
+	}
 	srv := httpd.NewService(c)
 	srv.Handler.MetaClient = s.MetaClient
 	authorizer := meta.NewQueryAuthorizer(s.MetaClient)
@@ -288,14 +332,18 @@ func (s *Server) NewHTTPDService() *httpd.Service {
 	srv.Handler.QueryExecutor = s.QueryExecutor
 	srv.Handler.Monitor = s.Monitor
 	srv.Handler.PointsWriter = s.PointsWriter
+	srv.Handler.Tasks = s.Tasks
+	if s.config.Audit.Enabled {
+		srv.Handler.AuditLog = s.AuditLog
+	}
 	srv.Handler.Version = s.buildInfo.Version
 	srv.Handler.BuildType = "OSS"
 	ss := storage.NewStore(s.TSDBStore, s.MetaClient)
 	srv.Handler.Store = ss
-	if s.config.HTTPD.FluxEnabled {
-		srv.Handler.Controller = control.NewController(s.MetaClient, reads.NewReader(ss), authorizer, s.config.HTTPD.AuthEnabled, s.Logger)
+	if c.FluxEnabled {
+		srv.Handler.Controller = control.NewController(s.MetaClient, reads.NewReader(ss), s.PointsWriter, authorizer, c.AuthEnabled, s.Logger)
 	}
-	return srv
+	s.Services = append(s.Services, srv)
 }
 
 func (s *Server) appendCollectdService(c collectd.Config) {
@@ -322,7 +370,10 @@ func (s *Server) appendOpenTSDBService(c opentsdb.Config) error {
 	return nil
 }
 
-func (s *Server) NewGraphiteService(c graphite.Config) *graphite.Service {
+func (s *Server) appendGraphiteService(c graphite.Config) error {
+	if !c.Enabled {
+		return nil
+	}
 	srv, err := graphite.NewService(c)
 	if err != nil {
 		return err
@@ -335,10 +386,13 @@ func (s *Server) NewGraphiteService(c graphite.Config) *graphite.Service {
 	return nil
 }
 
-func (s *Server) NewPrecreatorService() (*precreator.Service, error) {
-	srv := precreator.NewService(s.config.Precreator)
+func (s *Server) appendPrecreatorService(c precreator.Config) {
+	if !c.Enabled {
+		return
+	}
+	srv := precreator.NewService(c)
 	srv.MetaClient = s.MetaClient
-	return srv, nil
+	s.Services = append(s.Services, srv)
 }
 
 func (s *Server) appendUDPService(c udp.Config) {
@@ -351,50 +405,61 @@ func (s *Server) appendUDPService(c udp.Config) {
 	s.Services = append(s.Services, srv)
 }
 
-func (s *Server) NewContinuousQueryService() *continuous_querier.Service {
+func (s *Server) appendContinuousQueryService(c continuous_querier.Config) {
+	if !c.Enabled {
+		return
+	}
 	srv := continuous_querier.NewService(c)
 	srv.MetaClient = s.MetaClient
 	srv.QueryExecutor = s.QueryExecutor
 	srv.Monitor = s.Monitor
-	return srv
-}
+	srv.Tasks = s.Tasks
+	s.Services = append(s.Services, srv)
 
-// Err returns an error channel that multiplexes all out of band errors received from all services.
-func (s *Server) Err() <-chan error { return s.err }
+	// Track late-arriving writes so their windows can be re-run.
+	if c.LateDataHorizon > 0 {
+		s.PointsWriter.LateWrites = coordinator.NewLateWriteTracker()
+		srv.LateWrites = s.PointsWriter.LateWrites
+	}
 
-// Open opens the meta and data store and all services.
-func (s *Server) Open() error {
+	// Let BACKFILL CONTINUOUS QUERY run through the service.
+	if se, ok := s.QueryExecutor.StatementExecutor.(*coordinator.StatementExecutor); ok {
+		se.ContinuousQuerier = srv
+	}
+}
 
-	wireServer := wire.Server{
-		Network: "tcp",
-		Addr:    ":9999",
-		E:       query.NewExecutor(),
-		T:       s.TSDBStore,
+func (s *Server) appendWireService(c wire.Config) {
+	if !c.Enabled {
+		return
+	}
+	srv := wire.NewService(c)
+	srv.MetaClient = s.MetaClient
+	srv.QueryAuthorizer = meta.NewQueryAuthorizer(s.MetaClient)
+	srv.QueryExecutor = s.QueryExecutor
+	if s.config.Audit.Enabled {
+		srv.AuditLog = s.AuditLog
 	}
+	s.Services = append(s.Services, srv)
+}
 
-	wireServer.E.StatementExecutor = &coordinator.StatementExecutor{
-		MetaClient:  s.MetaClient,
-		TaskManager: s.QueryExecutor.TaskManager,
-		TSDBStore:   s.TSDBStore,
-		ShardMapper: &coordinator.LocalShardMapper{
-			MetaClient: s.MetaClient,
-			TSDBStore:  coordinator.LocalTSDBStore{Store: s.TSDBStore},
-		},
-		Monitor:           s.Monitor,
-		PointsWriter:      s.PointsWriter,
-		MaxSelectPointN:   s.config.Coordinator.MaxSelectPointN,
-		MaxSelectSeriesN:  s.config.Coordinator.MaxSelectSeriesN,
-		MaxSelectBucketsN: s.config.Coordinator.MaxSelectBucketsN,
+func (s *Server) appendStorageService(c storage.Config, mux *tcp.Mux) {
+	if !c.Enabled {
+		return
+	}
+	srv := storage.NewService(c)
+	srv.MetaClient = s.MetaClient
+	srv.Store = storage.NewStore(s.TSDBStore, s.MetaClient)
+	if c.BindAddress == "" {
+		srv.Listener = mux.Listen(storage.MuxHeader)
 	}
+	s.Services = append(s.Services, srv)
+}
 
-	go func() {
-		log.Printf("starting wire server!")
-		err := wireServer.Serve(context.TODO())
-		if err != nil {
-			log.Printf("err: %v", err)
-		}
-	}()
+// Err returns an error channel that multiplexes all out of band errors received from all services.
+func (s *Server) Err() <-chan error { return s.err }
 
+// Open opens the meta and data store and all services.
+func (s *Server) Open() error {
 	// Start profiling if requested.
 	if err := s.startProfile(); err != nil {
 		return err
@@ -411,54 +476,14 @@ func (s *Server) Open() error {
 	mux := tcp.NewMux()
 	go mux.Serve(ln)
 
-	type service interface{ Start(context.Context) error }
-
-	services := []service{}
-
-	// start with monitor service
-	services = append(services, s.Monitor)
-
-	// append pre creator service
-	if s.config.Precreator.Enabled {
-		svc, err := s.NewPrecreatorService()
-		if err != nil {
-			log.Fatal(err)
-		}
-		services = append(services, svc)
-	}
-
-	// append snapshotter service
-	{
-		svc, err := s.NewSnapshotterService()
-		if err != nil {
-			log.Fatal(err)
-		}
-		services = append(services, svc)
-	}
-
-	// append continuous query service
-	if s.config.ContinuousQuery.Enabled {
-		services = append(services, s.NewContinuousQueryService())
-	}
-
-	// append httpd service
-	if s.config.HTTPD.Enabled {
-		services = append(services, s.NewHTTPDService())
-	}
-
-	// append retention policy service
-	if s.config.Retention.Enabled {
-		services = append(services, s.NewRetentionPolicyService())
-	}
-
-	// append graphite services
-	for _, c := range s.config.GraphiteInputs {
-		if !c.Enabled {
-			continue
-		}
-
-	}
-
+	// Append services.
+	s.appendPrecreatorService(s.config.Precreator)
+	s.appendSnapshotterService()
+	s.appendContinuousQueryService(s.config.ContinuousQuery)
+	s.appendHTTPDService(s.config.HTTPD)
+	s.appendRetentionPolicyService(s.config.Retention)
+	s.appendWireService(s.config.Wire)
+	s.appendStorageService(s.config.Storage, mux)
 	for _, i := range s.config.GraphiteInputs {
 		if err := s.appendGraphiteService(i); err != nil {
 			return err
@@ -479,6 +504,7 @@ func (s *Server) Open() error {
 	s.Subscriber.MetaClient = s.MetaClient
 	s.PointsWriter.MetaClient = s.MetaClient
 	s.Monitor.MetaClient = s.MetaClient
+	s.AuditLog.PointsWriter = &auditPointsWriter{MetaClient: s.MetaClient, PointsWriter: s.PointsWriter}
 
 	s.SnapshotterService.Listener = mux.Listen(snapshotter.MuxHeader)
 
@@ -492,10 +518,10 @@ func (s *Server) Open() error {
 	}
 	s.PointsWriter.WithLogger(s.Logger)
 	s.Subscriber.WithLogger(s.Logger)
+	s.AuditLog.WithLogger(s.Logger)
 	for _, svc := range s.Services {
 		svc.WithLogger(s.Logger)
 	}
-	s.SnapshotterService.WithLogger(s.Logger)
 	s.Monitor.WithLogger(s.Logger)
 
 	// Open TSDB store.
@@ -503,9 +529,9 @@ func (s *Server) Open() error {
 		return fmt.Errorf("open tsdb store: %s", err)
 	}
 
-	// Open the subscriber service
-	if err := s.Subscriber.Open(); err != nil {
-		return fmt.Errorf("open subscriber: %s", err)
+	// Open the monitor.
+	if err := s.Monitor.Open(); err != nil {
+		return fmt.Errorf("open monitor: %s", err)
 	}
 
 	// Open the points writer service
@@ -513,11 +539,40 @@ func (s *Server) Open() error {
 		return fmt.Errorf("open points writer: %s", err)
 	}
 
-	s.PointsWriter.AddWriteSubscriber(s.Subscriber.Points())
+	// Open the audit log
+	if err := s.AuditLog.Open(); err != nil {
+		return fmt.Errorf("open audit log: %s", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
 
-	for _, service := range s.Services {
-		if err := service.Open(); err != nil {
-			return fmt.Errorf("open service: %s", err)
+	// Start the subscriber service
+	s.startService(ctx, "subscriber", s.Subscriber)
+	if s.config.Subscriber.QueueEnabled {
+		// Writes are queued for subscriptions before they return so none
+		// are dropped.
+		s.PointsWriter.AddDurableWriteSubscriber(s.Subscriber)
+	} else {
+		s.PointsWriter.AddWriteSubscriber(s.Subscriber.Points())
+	}
+
+	// Start storing audit events
+	s.startService(ctx, "audit", s.AuditLog)
+
+	for _, svc := range s.Services {
+		s.startService(ctx, fmt.Sprintf("%T", svc), svc)
+	}
+
+	// Wait for the services that listen for requests to be ready so callers
+	// can connect as soon as Open returns.
+	for _, svc := range s.Services {
+		if r, ok := svc.(interface{ Ready() <-chan struct{} }); ok {
+			select {
+			case <-r.Ready():
+			case err := <-s.err:
+				return err
+			}
 		}
 	}
 
@@ -529,6 +584,23 @@ func (s *Server) Open() error {
 	return nil
 }
 
+// startService runs svc until ctx is canceled. Errors returned by the
+// service are sent to the server's error channel.
+func (s *Server) startService(ctx context.Context, name string, svc interface {
+	Start(context.Context) error
+}) {
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		if err := svc.Start(ctx); err != nil && err != context.Canceled {
+			select {
+			case s.err <- fmt.Errorf("start %s: %s", name, err):
+			case <-s.closing:
+			}
+		}
+	}()
+}
+
 // Close shuts down the meta and data stores and all services.
 func (s *Server) Close() error {
 	s.stopProfile()
@@ -538,11 +610,21 @@ func (s *Server) Close() error {
 		s.Listener.Close()
 	}
 
-	// Close services to allow any inflight requests to complete
+	// Stop services to allow any inflight requests to complete
 	// and prevent new requests from being accepted.
-	for _, service := range s.Services {
-		service.Close()
+	if s.cancel != nil {
+		s.cancel()
 	}
+	for _, svc := range s.Services {
+		if c, ok := svc.(io.Closer); ok {
+			c.Close()
+		}
+	}
+
+	// Wait for the services to return from Start before closing the stores
+	// and clients they use.
+	close(s.closing)
+	s.wg.Wait()
 
 	s.config.deregisterDiagnostics(s.Monitor)
 
@@ -563,11 +645,17 @@ func (s *Server) Close() error {
 		s.Subscriber.Close()
 	}
 
+	if s.AuditLog != nil {
+		s.AuditLog.Close()
+	}
+
+	if s.Monitor != nil {
+		s.Monitor.Close()
+	}
+
 	if s.MetaClient != nil {
 		s.MetaClient.Close()
 	}
-
-	close(s.closing)
 	return nil
 }
 
@@ -642,8 +730,7 @@ func (s *Server) reportServer() {
 // Service represents a service attached to the server.
 type Service interface {
 	WithLogger(log *zap.Logger)
-	Open() error
-	Close() error
+	Start(ctx context.Context) error
 }
 
 // prof stores the file locations of active profiles.
@@ -699,6 +786,37 @@ func (s *Server) stopProfile() error {
 	return nil
 }
 
+// auditPointsWriter stores audit events, creating the audit database if it
+// does not exist.
+type auditPointsWriter struct {
+	MetaClient   *meta.Client
+	PointsWriter *coordinator.PointsWriter
+}
+
+func (pw *auditPointsWriter) WritePoints(database, retentionPolicy string, points models.Points) error {
+	if pw.MetaClient.Database(database) == nil {
+		if _, err := pw.MetaClient.CreateDatabase(database); err != nil {
+			return err
+		}
+	}
+	return pw.PointsWriter.WritePointsPrivileged(database, retentionPolicy, models.ConsistencyLevelAny, points)
+}
+
+// quotaUsage reports the write rates of the points writer and the running
+// queries of the task manager for SHOW QUOTAS.
+type quotaUsage struct {
+	PointsWriter *coordinator.PointsWriter
+	TaskManager  *query.TaskManager
+}
+
+func (u *quotaUsage) PointsPerSecond(database string) int64 {
+	return u.PointsWriter.PointsPerSecond(database)
+}
+
+func (u *quotaUsage) DatabaseQueries(database string) int {
+	return u.TaskManager.DatabaseQueries(database)
+}
+
 // monitorPointsWriter is a wrapper around `coordinator.PointsWriter` that helps
 // to prevent a circular dependency between the `cluster` and `monitor` packages.
 type monitorPointsWriter coordinator.PointsWriter
@@ -0,0 +1,106 @@
+package run_test
+
+import (
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ayang64/reflux/cmd/influxd/run"
+	"github.com/ayang64/reflux/services/httpd"
+)
+
+// Ensure the services are listening once Open returns and are stopped once
+// Close returns.
+func TestServer_OpenClose(t *testing.T) {
+	c, dir := newServerConfig(t)
+	defer os.RemoveAll(dir)
+
+	s, err := run.NewServer(c, &run.BuildInfo{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Open(); err != nil {
+		s.Close()
+		t.Fatal(err)
+	}
+
+	addr := httpdAddr(t, s)
+	resp, err := http.Get("http://" + addr + "/ping")
+	if err != nil {
+		s.Close()
+		t.Fatalf("httpd not listening after Open: %s", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNoContent {
+		s.Close()
+		t.Fatalf("unexpected status: %d", resp.StatusCode)
+	}
+
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if conn, err := net.Dial("tcp", addr); err == nil {
+		conn.Close()
+		t.Fatal("httpd still listening after Close")
+	}
+}
+
+// Ensure Open returns the error of a service that fails to start.
+func TestServer_Open_ServiceError(t *testing.T) {
+	c, dir := newServerConfig(t)
+	defer os.RemoveAll(dir)
+
+	// Take the address of the HTTP service so it cannot listen.
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	c.HTTPD.BindAddress = ln.Addr().String()
+
+	s, err := run.NewServer(c, &run.BuildInfo{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	if err := s.Open(); err == nil || !strings.Contains(err.Error(), "start *httpd.Service") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+// newServerConfig returns a config storing its data in a temporary directory
+// and listening on random local ports. The directory must be removed by the
+// caller.
+func newServerConfig(t *testing.T) (*run.Config, string) {
+	dir, err := ioutil.TempDir("", "influxd-run-")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := run.NewConfig()
+	c.BindAddress = "127.0.0.1:0"
+	c.ReportingDisabled = true
+	c.Meta.Dir = filepath.Join(dir, "meta")
+	c.Data.Dir = filepath.Join(dir, "data")
+	c.Data.WALDir = filepath.Join(dir, "wal")
+	c.HTTPD.BindAddress = "127.0.0.1:0"
+	c.HTTPD.LogEnabled = false
+	c.Monitor.StoreEnabled = false
+	return c, dir
+}
+
+// httpdAddr returns the address the HTTP service of s is listening on.
+func httpdAddr(t *testing.T, s *run.Server) string {
+	for _, svc := range s.Services {
+		if h, ok := svc.(*httpd.Service); ok {
+			return h.Addr().String()
+		}
+	}
+	t.Fatal("httpd service not found")
+	return ""
+}
@@ -1,5 +1,3 @@
-//+build ignore
-
 // Package help contains the help for the store command.
 package help
 
@@ -1,5 +1,3 @@
-//+build ignore
-
 // The store command displays detailed information about InfluxDB data files.
 package main
 
@@ -1,5 +1,3 @@
-//+build ignore
-
 package query
 
 import (
@@ -9,19 +7,22 @@ import (
 	"flag"
 	"fmt"
 	"io"
+	"net"
 	"os"
 	"path/filepath"
 	"strconv"
 	"strings"
 	"time"
 
-	"github.com/gogo/protobuf/types"
+	"github.com/ayang64/reflux/influxql"
 	"github.com/ayang64/reflux/models"
 	"github.com/ayang64/reflux/services/storage"
 	"github.com/ayang64/reflux/storage/reads/datatypes"
-	"github.com/ayang64/reflux/influxql"
+	"github.com/ayang64/reflux/tcp"
+	"github.com/gogo/protobuf/types"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
 )
 
 // Command represents the program execution for "store query".
@@ -32,21 +33,20 @@ type Command struct {
 	Logger *zap.Logger
 
 	addr            string
+	mux             bool
+	username        string
+	password        string
 	cpuProfile      string
 	memProfile      string
 	database        string
 	retentionPolicy string
 	startTime       int64
 	endTime         int64
-	limit           int64
-	slimit          int64
-	soffset         int64
-	desc            bool
 	silent          bool
 	expr            string
 	agg             string
 	groupArg        string
-	group           datatypes.ReadRequest_Group
+	group           datatypes.ReadGroupRequest_Group
 	groupKeys       string
 	keys            []string
 	hintsArg        string
@@ -88,18 +88,17 @@ func (cmd *Command) Run(args ...string) error {
 	fs.StringVar(&cmd.cpuProfile, "cpuprofile", "", "CPU profile name")
 	fs.StringVar(&cmd.memProfile, "memprofile", "", "memory profile name")
 	fs.StringVar(&cmd.addr, "addr", ":8082", "the RPC address")
+	fs.BoolVar(&cmd.mux, "mux", false, "Optional: connect through the multiplexed RPC bind address")
+	fs.StringVar(&cmd.username, "username", "", "Optional: the username to authenticate with")
+	fs.StringVar(&cmd.password, "password", "", "Optional: the password to authenticate with")
 	fs.StringVar(&cmd.database, "database", "", "the database to query")
 	fs.StringVar(&cmd.retentionPolicy, "retention", "", "Optional: the retention policy to query")
 	fs.StringVar(&start, "start", "", "Optional: the start time to query (RFC3339 format)")
 	fs.StringVar(&end, "end", "", "Optional: the end time to query (RFC3339 format)")
-	fs.Int64Var(&cmd.slimit, "slimit", 0, "Optional: limit number of series")
-	fs.Int64Var(&cmd.soffset, "soffset", 0, "Optional: start offset for series")
-	fs.Int64Var(&cmd.limit, "limit", 0, "Optional: limit number of values per series (-1 to return series only)")
-	fs.BoolVar(&cmd.desc, "desc", false, "Optional: return results in descending order")
 	fs.BoolVar(&cmd.silent, "silent", false, "silence output")
 	fs.StringVar(&cmd.expr, "expr", "", "InfluxQL conditional expression")
-	fs.StringVar(&cmd.agg, "agg", "", "aggregate functions (sum, count)")
-	fs.StringVar(&cmd.groupArg, "group", "none", "group operation (none,all,by,except,disable)")
+	fs.StringVar(&cmd.agg, "agg", "", "aggregate functions (sum, count); requires -group")
+	fs.StringVar(&cmd.groupArg, "group", "", "group operation (none,by); when empty, series are read without grouping")
 	fs.StringVar(&cmd.groupKeys, "group-keys", "", "comma-separated list of tags to specify series order")
 	fs.StringVar(&cmd.hintsArg, "hints", "none", "comma-separated list of read hints (none,no_points,no_series)")
 
@@ -145,7 +144,14 @@ func (cmd *Command) Run(args ...string) error {
 		return err
 	}
 
-	conn, err := grpc.Dial(cmd.addr, grpc.WithInsecure())
+	opts := []grpc.DialOption{grpc.WithInsecure()}
+	if cmd.mux {
+		opts = append(opts, grpc.WithContextDialer(func(ctx context.Context, addr string) (net.Conn, error) {
+			return tcp.Dial("tcp", addr, storage.MuxHeader)
+		}))
+	}
+
+	conn, err := grpc.Dial(cmd.addr, opts...)
 	if err != nil {
 		return err
 	}
@@ -163,6 +169,9 @@ func (cmd *Command) validate() error {
 	}
 
 	if cmd.agg != "" {
+		if cmd.groupArg == "" {
+			return errors.New("aggregate functions require a group operation")
+		}
 		agg, ok := datatypes.Aggregate_AggregateType_value[strings.ToUpper(cmd.agg)]
 		if !ok {
 			return errors.New("invalid aggregate function: " + cmd.agg)
@@ -170,14 +179,16 @@ func (cmd *Command) validate() error {
 		cmd.aggType = datatypes.Aggregate_AggregateType(agg)
 	}
 
-	group, ok := datatypes.ReadRequest_Group_value["GROUP_"+strings.ToUpper(cmd.groupArg)]
-	if !ok {
-		return errors.New("invalid group type: " + cmd.groupArg)
+	if cmd.groupArg != "" {
+		group, ok := datatypes.ReadGroupRequest_Group_value["GROUP_"+strings.ToUpper(cmd.groupArg)]
+		if !ok {
+			return errors.New("invalid group type: " + cmd.groupArg)
+		}
+		cmd.group = datatypes.ReadGroupRequest_Group(group)
 	}
-	cmd.group = datatypes.ReadRequest_Group(group)
 
 	for _, h := range strings.Split(cmd.hintsArg, ",") {
-		cmd.hints |= datatypes.HintFlags(datatypes.ReadRequest_HintFlags_value["HINT_"+strings.ToUpper(h)])
+		cmd.hints |= datatypes.HintFlags(datatypes.ReadGroupRequest_HintFlags_value["HINT_"+strings.ToUpper(h)])
 	}
 
 	return nil
@@ -189,30 +200,16 @@ func (cmd *Command) query(c datatypes.StorageClient) error {
 		RetentionPolicy: cmd.retentionPolicy,
 	}
 
-	var req datatypes.ReadRequest
-	if any, err := types.MarshalAny(&src); err != nil {
+	source, err := types.MarshalAny(&src)
+	if err != nil {
 		return err
-	} else {
-		req.ReadSource = any
-	}
-	req.TimestampRange.Start = cmd.startTime
-	req.TimestampRange.End = cmd.endTime
-	req.SeriesLimit = cmd.slimit
-	req.SeriesOffset = cmd.soffset
-	req.PointsLimit = cmd.limit
-	req.Descending = cmd.desc
-	req.Group = cmd.group
-	req.GroupKeys = cmd.keys
-	req.Hints = cmd.hints
-
-	if cmd.aggType != datatypes.AggregateTypeNone {
-		req.Aggregate = &datatypes.Aggregate{Type: cmd.aggType}
 	}
 
+	var predicate *datatypes.Predicate
 	if cmd.expr != "" {
 		expr, err := influxql.ParseExpr(cmd.expr)
 		if err != nil {
-			return nil
+			return err
 		}
 		fmt.Fprintln(cmd.Stdout, expr)
 		var v exprToNodeVisitor
@@ -221,10 +218,36 @@ func (cmd *Command) query(c datatypes.StorageClient) error {
 			return v.Err()
 		}
 
-		req.Predicate = &datatypes.Predicate{Root: v.nodes[0]}
+		predicate = &datatypes.Predicate{Root: v.nodes[0]}
 	}
 
-	stream, err := c.Read(context.Background(), &req)
+	ctx := context.Background()
+	if cmd.username != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, storage.AuthorizationKey, "Token "+cmd.username+":"+cmd.password)
+	}
+
+	var stream grpc.ClientStream
+	if cmd.groupArg == "" {
+		req := &datatypes.ReadFilterRequest{
+			ReadSource: source,
+			Range:      datatypes.TimestampRange{Start: cmd.startTime, End: cmd.endTime},
+			Predicate:  predicate,
+		}
+		stream, err = c.ReadFilter(ctx, req)
+	} else {
+		req := &datatypes.ReadGroupRequest{
+			ReadSource: source,
+			Range:      datatypes.TimestampRange{Start: cmd.startTime, End: cmd.endTime},
+			Predicate:  predicate,
+			GroupKeys:  cmd.keys,
+			Group:      cmd.group,
+			Hints:      cmd.hints,
+		}
+		if cmd.aggType != datatypes.AggregateTypeNone {
+			req.Aggregate = &datatypes.Aggregate{Type: cmd.aggType}
+		}
+		stream, err = c.ReadGroup(ctx, req)
+	}
 	if err != nil {
 		fmt.Fprintln(cmd.Stdout, err)
 		return err
@@ -456,7 +479,7 @@ func mapOpToComparison(op influxql.Token) datatypes.Node_Comparison {
 	case influxql.NEQ:
 		return datatypes.ComparisonNotEqual
 	case influxql.NEQREGEX:
-		return datatypes.ComparisonNotEqual
+		return datatypes.ComparisonNotRegex
 	case influxql.LT:
 		return datatypes.ComparisonLess
 	case influxql.LTE:
@@ -0,0 +1,411 @@
+package coordinator
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"sync"
+
+	"github.com/ayang64/reflux/tsdb"
+)
+
+const (
+	// DefaultMaxBatchIDs is the default number of committed batch IDs
+	// remembered for each database.
+	DefaultMaxBatchIDs = 10000
+
+	// MaxBatchIDLength is the maximum length of a batch ID in bytes.
+	MaxBatchIDLength = 256
+
+	// minBatchIDLogRecords is the number of records the batch ID log may
+	// have before it is compacted.
+	minBatchIDLogRecords = 1000
+)
+
+var (
+	// ErrInvalidBatchID is returned when a batch ID is longer than
+	// MaxBatchIDLength.
+	ErrInvalidBatchID = errors.New("batch id too long")
+
+	// ErrBatchIDStoreClosed is returned when a batch is written while the
+	// batch ID store is closed.
+	ErrBatchIDStoreClosed = errors.New("batch id store closed")
+)
+
+// batchIDs are the batch IDs remembered for a database, oldest first.
+type batchIDs struct {
+	ids   map[string]struct{}
+	order []string
+}
+
+type batchKey struct {
+	database string
+	id       string
+}
+
+// BatchIDStore remembers the IDs of the batches that were recently written to
+// each database so that a batch retried by a client is not written twice. The
+// IDs are appended to a log file so they survive restarts, and only the most
+// recent MaxBatchIDs of each database are kept. A MaxBatchIDs of zero keeps
+// every ID.
+//
+// The shards written by a batch that failed on other shards are kept in
+// memory, so that a retry of the batch only writes the shards that failed.
+// The most recent MaxBatchIDs of these batches are kept.
+type BatchIDStore struct {
+	mu      sync.Mutex
+	f       *os.File
+	n       int // the number of records in the log
+	dbs     map[string]*batchIDs
+	pending map[batchKey]*batchWrite
+
+	// partial holds the shards written by batches that were not committed,
+	// in the order the batches were first partially written.
+	partial      map[batchKey]map[uint64]struct{}
+	partialOrder []batchKey
+
+	path        string
+	MaxBatchIDs int
+}
+
+// NewBatchIDStore returns a new instance of BatchIDStore stored at path.
+func NewBatchIDStore(path string) *BatchIDStore {
+	return &BatchIDStore{
+		path:        path,
+		pending:     make(map[batchKey]*batchWrite),
+		partial:     make(map[batchKey]map[uint64]struct{}),
+		MaxBatchIDs: DefaultMaxBatchIDs,
+	}
+}
+
+// Open reads the batch IDs from the log and opens it for appending.
+func (s *BatchIDStore) Open() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, err := os.OpenFile(s.path, os.O_RDWR|os.O_CREATE, 0666)
+	if err != nil {
+		return err
+	}
+
+	s.dbs, s.n = make(map[string]*batchIDs), 0
+	r := bufio.NewReader(f)
+	var size int64
+	for {
+		database, id, n, err := readBatchID(r)
+		if err == io.EOF {
+			break
+		} else if err == io.ErrUnexpectedEOF {
+			// The last record was not completely written before a crash.
+			if err := f.Truncate(size); err != nil {
+				f.Close()
+				return err
+			}
+			break
+		} else if err != nil {
+			f.Close()
+			return err
+		}
+		size += int64(n)
+		s.add(database, id)
+		s.n++
+	}
+
+	if _, err := f.Seek(size, io.SeekStart); err != nil {
+		f.Close()
+		return err
+	}
+	s.f = f
+	return nil
+}
+
+// Close closes the log.
+func (s *BatchIDStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.f == nil {
+		return nil
+	}
+	err := s.f.Close()
+	s.f = nil
+	return err
+}
+
+// add remembers a batch ID and forgets the oldest IDs of its database beyond
+// the limit.
+func (s *BatchIDStore) add(database, id string) {
+	b := s.dbs[database]
+	if b == nil {
+		b = &batchIDs{ids: make(map[string]struct{})}
+		s.dbs[database] = b
+	}
+	if _, ok := b.ids[id]; ok {
+		return
+	}
+	b.ids[id] = struct{}{}
+	b.order = append(b.order, id)
+
+	if n := len(b.order) - s.MaxBatchIDs; s.MaxBatchIDs > 0 && n > 0 {
+		for _, id := range b.order[:n] {
+			delete(b.ids, id)
+		}
+		b.order = b.order[n:]
+	}
+}
+
+// contains returns true if the batch ID is remembered for the database.
+func (s *BatchIDStore) contains(database, id string) bool {
+	b := s.dbs[database]
+	if b == nil {
+		return false
+	}
+	_, ok := b.ids[id]
+	return ok
+}
+
+// batchWrite is a batch that is being written. Writes of the same batch wait
+// until it is done to learn whether it was committed.
+type batchWrite struct {
+	store     *BatchIDStore
+	key       batchKey
+	done      chan struct{}
+	committed bool
+
+	// written are the shards written by earlier writes of the batch.
+	written map[uint64]struct{}
+
+	// tracked is true once the batch is finished by track.
+	tracked bool
+}
+
+// begin starts writing a batch. If the batch was already committed, it
+// returns false. If the batch is being written, it waits for that write to
+// finish first.
+func (s *BatchIDStore) begin(ctx context.Context, database, id string) (*batchWrite, bool, error) {
+	if len(id) > MaxBatchIDLength {
+		return nil, false, ErrInvalidBatchID
+	}
+
+	key := batchKey{database: database, id: id}
+	for {
+		s.mu.Lock()
+		if s.f == nil {
+			s.mu.Unlock()
+			return nil, false, ErrBatchIDStoreClosed
+		}
+
+		if b := s.pending[key]; b != nil {
+			s.mu.Unlock()
+			select {
+			case <-b.done:
+				if b.committed {
+					return nil, false, nil
+				}
+				continue
+			case <-ctx.Done():
+				return nil, false, ctx.Err()
+			}
+		}
+
+		if s.contains(database, id) {
+			s.mu.Unlock()
+			return nil, false, nil
+		}
+
+		b := &batchWrite{store: s, key: key, done: make(chan struct{}), written: s.partial[key]}
+		s.pending[key] = b
+		s.mu.Unlock()
+		return b, true, nil
+	}
+}
+
+// skip returns true if the shard was written by an earlier write of the
+// batch.
+func (b *batchWrite) skip(shardID uint64) bool {
+	_, ok := b.written[shardID]
+	return ok
+}
+
+// finish ends the write of a batch. If commit is true the batch ID is
+// remembered and later writes of the batch are skipped. Otherwise the shards
+// in written are remembered and later writes of the batch skip them.
+func (b *batchWrite) finish(commit bool, written []uint64) error {
+	s := b.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var err error
+	if commit {
+		err = s.commit(b.key)
+	}
+	b.committed = commit && err == nil
+	if b.committed {
+		delete(s.partial, b.key)
+	} else if len(written) > 0 {
+		s.addPartial(b.key, written)
+	}
+	delete(s.pending, b.key)
+	close(b.done)
+	return err
+}
+
+// addPartial remembers the shards written by a batch that was not committed
+// and forgets the oldest of these batches beyond the limit.
+func (s *BatchIDStore) addPartial(key batchKey, written []uint64) {
+	shards := s.partial[key]
+	if shards == nil {
+		shards = make(map[uint64]struct{})
+		s.partial[key] = shards
+		s.partialOrder = append(s.partialOrder, key)
+	}
+	for _, id := range written {
+		shards[id] = struct{}{}
+	}
+
+	// Batches that were committed since are still in the order, so drop
+	// them once they make up most of it.
+	if len(s.partialOrder) > 2*len(s.partial) {
+		order := s.partialOrder[:0]
+		for _, k := range s.partialOrder {
+			if _, ok := s.partial[k]; ok {
+				order = append(order, k)
+			}
+		}
+		s.partialOrder = order
+	}
+	for s.MaxBatchIDs > 0 && len(s.partial) > s.MaxBatchIDs {
+		delete(s.partial, s.partialOrder[0])
+		s.partialOrder = s.partialOrder[1:]
+	}
+}
+
+// commit appends a batch ID to the log. The log is rewritten once most of
+// its records are for IDs that have been forgotten.
+func (s *BatchIDStore) commit(key batchKey) error {
+	if s.f == nil {
+		return ErrBatchIDStoreClosed
+	}
+
+	var buf bytes.Buffer
+	appendBatchID(&buf, key.database, key.id)
+	if _, err := s.f.Write(buf.Bytes()); err != nil {
+		return err
+	} else if err := s.f.Sync(); err != nil {
+		return err
+	}
+	s.add(key.database, key.id)
+	s.n++
+
+	var live int
+	for _, b := range s.dbs {
+		live += len(b.order)
+	}
+	if s.n > 2*live && s.n > minBatchIDLogRecords {
+		return s.compact()
+	}
+	return nil
+}
+
+// compact rewrites the log with only the remembered batch IDs.
+func (s *BatchIDStore) compact() error {
+	var buf bytes.Buffer
+	var n int
+	for database, b := range s.dbs {
+		for _, id := range b.order {
+			appendBatchID(&buf, database, id)
+			n++
+		}
+	}
+
+	tmp := s.path + ".tmp"
+	if err := ioutil.WriteFile(tmp, buf.Bytes(), 0666); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(tmp, os.O_RDWR, 0666)
+	if err != nil {
+		return err
+	} else if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	} else if err := os.Rename(tmp, s.path); err != nil {
+		f.Close()
+		return err
+	} else if _, err := f.Seek(0, io.SeekEnd); err != nil {
+		f.Close()
+		return err
+	}
+
+	s.f.Close()
+	s.f, s.n = f, n
+	return nil
+}
+
+// appendBatchID appends the record of a batch ID to buf.
+func appendBatchID(buf *bytes.Buffer, database, id string) {
+	var b [binary.MaxVarintLen64]byte
+	buf.Write(b[:binary.PutUvarint(b[:], uint64(len(database)))])
+	buf.WriteString(database)
+	buf.Write(b[:binary.PutUvarint(b[:], uint64(len(id)))])
+	buf.WriteString(id)
+}
+
+// readBatchID reads the record of a batch ID and returns its size.
+func readBatchID(r *bufio.Reader) (database, id string, n int, err error) {
+	readString := func() (string, error) {
+		l, err := binary.ReadUvarint(r)
+		if err != nil {
+			return "", err
+		}
+		var lb [binary.MaxVarintLen64]byte
+		n += binary.PutUvarint(lb[:], l)
+
+		b := make([]byte, l)
+		if _, err := io.ReadFull(r, b); err != nil {
+			return "", err
+		}
+		n += len(b)
+		return string(b), nil
+	}
+
+	if database, err = readString(); err != nil {
+		return "", "", 0, err
+	}
+	if id, err = readString(); err == io.EOF {
+		return "", "", 0, io.ErrUnexpectedEOF
+	} else if err != nil {
+		return "", "", 0, err
+	}
+	return database, id, n, nil
+}
+
+// track returns a channel that receives the results of the n shard writes of
+// a batch from ch. The batch is finished once every shard write is done,
+// even if the caller stops waiting for them. The batch is committed unless a
+// shard write failed without writing any points, in which case the shards
+// that were written are skipped when the batch is retried.
+func (b *batchWrite) track(ch <-chan shardWriteResult, n int, logErr func(error)) <-chan shardWriteResult {
+	b.tracked = true
+	out := make(chan shardWriteResult, n)
+	go func() {
+		commit := true
+		var written []uint64
+		for i := 0; i < n; i++ {
+			res := <-ch
+			if _, ok := res.err.(tsdb.PartialWriteError); res.err != nil && !ok {
+				commit = false
+			} else {
+				written = append(written, res.shardID)
+			}
+			out <- res
+		}
+		if err := b.finish(commit, written); err != nil {
+			logErr(err)
+		}
+	}()
+	return out
+}
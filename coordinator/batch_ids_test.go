@@ -0,0 +1,190 @@
+package coordinator_test
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ayang64/reflux"
+	"github.com/ayang64/reflux/coordinator"
+	"github.com/ayang64/reflux/models"
+	"github.com/ayang64/reflux/services/meta"
+)
+
+// batchPointsWriter returns a points writer with a batch ID store in dir that
+// counts the points written to the store.
+func batchPointsWriter(t *testing.T, dir string, maxBatchIDs int, writeFn func(shardID uint64, points []models.Point) error) *coordinator.PointsWriter {
+	ms := NewPointsWriterMetaClient()
+	ms.DatabaseFn = func(database string) *meta.DatabaseInfo {
+		return &meta.DatabaseInfo{Name: database}
+	}
+	ms.NodeIDFn = func() uint64 { return 1 }
+
+	w := coordinator.NewPointsWriter()
+	w.MetaClient = ms
+	w.TSDBStore = &fakeStore{
+		WriteFn: writeFn,
+	}
+	w.Node = &influxdb.Node{ID: 1}
+	w.BatchIDs = coordinator.NewBatchIDStore(filepath.Join(dir, "batches.db"))
+	w.BatchIDs.MaxBatchIDs = maxBatchIDs
+	if err := w.Open(); err != nil {
+		t.Fatal(err)
+	}
+	return w
+}
+
+func writeBatch(w *coordinator.PointsWriter, id string, points ...models.Point) error {
+	ctx := context.WithValue(context.Background(), coordinator.WriteBatchID, id)
+	if len(points) == 0 {
+		points = []models.Point{models.MustNewPoint("cpu", nil, models.Fields{"value": 1.0}, time.Now())}
+	}
+	return w.WritePointsPrivilegedWithContext(ctx, "db0", "myrp", models.ConsistencyLevelOne, points)
+}
+
+// Ensures a batch is only written once, even after a restart, and that only
+// the most recent batch IDs are remembered.
+func TestPointsWriter_WritePoints_BatchID(t *testing.T) {
+	dir, err := ioutil.TempDir("", "batch_ids")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	var written int64
+	count := func(shardID uint64, points []models.Point) error {
+		atomic.AddInt64(&written, int64(len(points)))
+		return nil
+	}
+
+	w := batchPointsWriter(t, dir, 2, count)
+	for _, id := range []string{"a", "a", "b"} {
+		if err := writeBatch(w, id); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if written != 2 {
+		t.Fatalf("unexpected points written: got %d, exp 2", written)
+	}
+	w.Close()
+
+	// The batch IDs survive a restart. Writing c forgets a.
+	w = batchPointsWriter(t, dir, 2, count)
+	defer w.Close()
+	for _, id := range []string{"a", "b", "c", "b", "a"} {
+		if err := writeBatch(w, id); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if written != 4 {
+		t.Fatalf("unexpected points written: got %d, exp 4", written)
+	}
+
+	if stats := w.Statistics(nil); stats[0].Values["writeDuplicate"] != int64(3) {
+		t.Fatalf("unexpected duplicate writes: %v", stats[0].Values["writeDuplicate"])
+	}
+}
+
+// Ensures a failed batch can be retried and a retry waits for the write of the
+// same batch that is in progress.
+func TestPointsWriter_WritePoints_BatchIDRetry(t *testing.T) {
+	dir, err := ioutil.TempDir("", "batch_ids")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	var written int64
+	release := make(chan struct{})
+	fail := errors.New("write failed")
+	w := batchPointsWriter(t, dir, 10, func(shardID uint64, points []models.Point) error {
+		if atomic.AddInt64(&written, 1) == 1 {
+			return fail
+		}
+		<-release
+		return nil
+	})
+	defer w.Close()
+
+	if err := writeBatch(w, "a"); err != fail {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	errs := make(chan error, 2)
+	for i := 0; i < 2; i++ {
+		go func() { errs <- writeBatch(w, "a") }()
+	}
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	for i := 0; i < 2; i++ {
+		if err := <-errs; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if written != 2 {
+		t.Fatalf("unexpected shard writes: got %d, exp 2", written)
+	}
+}
+
+// Ensures a retry of a batch that failed on one of its shards only writes the
+// shard that failed.
+func TestPointsWriter_WritePoints_BatchIDShardFailure(t *testing.T) {
+	dir, err := ioutil.TempDir("", "batch_ids")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	var mu sync.Mutex
+	var failed uint64
+	writes := make(map[uint64]int)
+	fail := errors.New("write failed")
+	w := batchPointsWriter(t, dir, 10, func(shardID uint64, points []models.Point) error {
+		mu.Lock()
+		defer mu.Unlock()
+		writes[shardID]++
+		if failed == 0 {
+			failed = shardID
+			return fail
+		}
+		return nil
+	})
+	defer w.Close()
+
+	// The points belong to the shards of two shard groups.
+	now := time.Now()
+	points := []models.Point{
+		models.MustNewPoint("cpu", nil, models.Fields{"value": 1.0}, now),
+		models.MustNewPoint("cpu", nil, models.Fields{"value": 2.0}, now.Add(time.Hour)),
+	}
+	if err := writeBatch(w, "a", points...); err != fail {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if err := writeBatch(w, "a", points...); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(writes) != 2 {
+		t.Fatalf("unexpected shards written: %v", writes)
+	}
+	for shardID, n := range writes {
+		exp := 1
+		if shardID == failed {
+			exp = 2
+		}
+		if n != exp {
+			t.Fatalf("unexpected writes of shard %d: got %d, exp %d", shardID, n, exp)
+		}
+	}
+}
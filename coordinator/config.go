@@ -25,6 +25,10 @@ const (
 	// DefaultMaxSelectSeriesN is the maximum number of series a SELECT can run.
 	// A value of zero will make the maximum series count unlimited.
 	DefaultMaxSelectSeriesN = 0
+
+	// DefaultMaxJoinPointN is the maximum number of rows a JOIN can hold in
+	// memory. A value of zero will make the maximum row count unlimited.
+	DefaultMaxJoinPointN = 1000000
 )
 
 // Config represents the configuration for the coordinator service.
@@ -36,6 +40,8 @@ type Config struct {
 	MaxSelectPointN      int           `toml:"max-select-point"`
 	MaxSelectSeriesN     int           `toml:"max-select-series"`
 	MaxSelectBucketsN    int           `toml:"max-select-buckets"`
+	MaxJoinPointN        int           `toml:"max-join-point"`
+	MaxBatchIDs          int           `toml:"max-batch-ids"`
 }
 
 // NewConfig returns an instance of Config with defaults.
@@ -46,6 +52,8 @@ func NewConfig() Config {
 		MaxConcurrentQueries: DefaultMaxConcurrentQueries,
 		MaxSelectPointN:      DefaultMaxSelectPointN,
 		MaxSelectSeriesN:     DefaultMaxSelectSeriesN,
+		MaxJoinPointN:        DefaultMaxJoinPointN,
+		MaxBatchIDs:          DefaultMaxBatchIDs,
 	}
 }
 
@@ -59,5 +67,7 @@ func (c Config) Diagnostics() (*diagnostics.Diagnostics, error) {
 		"max-select-point":       c.MaxSelectPointN,
 		"max-select-series":      c.MaxSelectSeriesN,
 		"max-select-buckets":     c.MaxSelectBucketsN,
+		"max-join-point":         c.MaxJoinPointN,
+		"max-batch-ids":          c.MaxBatchIDs,
 	}), nil
 }
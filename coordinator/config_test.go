@@ -13,6 +13,7 @@ func TestConfig_Parse(t *testing.T) {
 	var c coordinator.Config
 	if _, err := toml.Decode(`
 write-timeout = "20s"
+max-join-point = 500
 `, &c); err != nil {
 		t.Fatal(err)
 	}
@@ -20,5 +21,7 @@ write-timeout = "20s"
 	// Validate configuration.
 	if time.Duration(c.WriteTimeout) != 20*time.Second {
 		t.Fatalf("unexpected write timeout s: %s", c.WriteTimeout)
+	} else if c.MaxJoinPointN != 500 {
+		t.Fatalf("unexpected max join points: %d", c.MaxJoinPointN)
 	}
 }
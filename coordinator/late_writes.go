@@ -0,0 +1,125 @@
+package coordinator
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ayang64/reflux/models"
+)
+
+// DirtyRange is the range of timestamps of the late points written to a
+// measurement since the ranges were last drained.
+type DirtyRange struct {
+	Database    string
+	Measurement string
+	Min, Max    time.Time
+}
+
+type dirtyKey struct {
+	database    string
+	measurement string
+}
+
+// LateWriteTracker records the time ranges of points that are written behind
+// the watermark of their database. The continuous query service moves the
+// watermark forward as it computes windows so that it can re-run the windows
+// that points arrived in after they were computed.
+type LateWriteTracker struct {
+	mu         sync.RWMutex
+	watermarks map[string]int64
+	ranges     map[dirtyKey][2]int64
+}
+
+// NewLateWriteTracker returns a new instance of LateWriteTracker.
+func NewLateWriteTracker() *LateWriteTracker {
+	return &LateWriteTracker{
+		watermarks: make(map[string]int64),
+		ranges:     make(map[dirtyKey][2]int64),
+	}
+}
+
+// SetWatermark moves the watermark of the database forward to t. Points
+// written to the database with a timestamp before the watermark are tracked.
+// The watermark never moves backwards.
+func (l *LateWriteTracker) SetWatermark(database string, t time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if wm, ok := l.watermarks[database]; !ok || t.UnixNano() > wm {
+		l.watermarks[database] = t.UnixNano()
+	}
+}
+
+// Track records the points that were written to the database behind its
+// watermark. Databases without a watermark are ignored.
+func (l *LateWriteTracker) Track(database string, points []models.Point) {
+	l.mu.RLock()
+	wm, ok := l.watermarks[database]
+	l.mu.RUnlock()
+	if !ok {
+		return
+	}
+
+	var late map[string][2]int64
+	for _, p := range points {
+		ts := p.UnixNano()
+		if ts >= wm {
+			continue
+		}
+		if late == nil {
+			late = make(map[string][2]int64)
+		}
+		name := string(p.Name())
+		if r, ok := late[name]; !ok {
+			late[name] = [2]int64{ts, ts}
+		} else if ts < r[0] {
+			late[name] = [2]int64{ts, r[1]}
+		} else if ts > r[1] {
+			late[name] = [2]int64{r[0], ts}
+		}
+	}
+	if len(late) == 0 {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for name, r := range late {
+		key := dirtyKey{database: database, measurement: name}
+		if cur, ok := l.ranges[key]; ok {
+			if cur[0] < r[0] {
+				r[0] = cur[0]
+			}
+			if cur[1] > r[1] {
+				r[1] = cur[1]
+			}
+		}
+		l.ranges[key] = r
+	}
+}
+
+// Drain returns the dirty ranges recorded since the last call and resets
+// them. The ranges are sorted by database and measurement.
+func (l *LateWriteTracker) Drain() []DirtyRange {
+	l.mu.Lock()
+	ranges := l.ranges
+	l.ranges = make(map[dirtyKey][2]int64)
+	l.mu.Unlock()
+
+	a := make([]DirtyRange, 0, len(ranges))
+	for key, r := range ranges {
+		a = append(a, DirtyRange{
+			Database:    key.database,
+			Measurement: key.measurement,
+			Min:         time.Unix(0, r[0]).UTC(),
+			Max:         time.Unix(0, r[1]).UTC(),
+		})
+	}
+	sort.Slice(a, func(i, j int) bool {
+		if a[i].Database != a[j].Database {
+			return a[i].Database < a[j].Database
+		}
+		return a[i].Measurement < a[j].Measurement
+	})
+	return a
+}
@@ -0,0 +1,39 @@
+package coordinator_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ayang64/reflux/coordinator"
+	"github.com/ayang64/reflux/models"
+)
+
+func TestLateWriteTracker(t *testing.T) {
+	l := coordinator.NewLateWriteTracker()
+	point := func(name string, sec int64) models.Point {
+		return models.MustNewPoint(name, nil, models.Fields{"value": 1.0}, time.Unix(sec, 0))
+	}
+
+	// Databases without a watermark are not tracked.
+	l.Track("db0", []models.Point{point("cpu", 10)})
+
+	l.SetWatermark("db0", time.Unix(100, 0))
+	l.SetWatermark("db0", time.Unix(50, 0)) // never moves backwards
+	l.Track("db0", []models.Point{point("cpu", 40), point("cpu", 100), point("mem", 99)})
+	l.Track("db0", []models.Point{point("cpu", 20), point("cpu", 30)})
+	l.Track("db1", []models.Point{point("cpu", 10)})
+
+	exp := []coordinator.DirtyRange{
+		{Database: "db0", Measurement: "cpu", Min: time.Unix(20, 0).UTC(), Max: time.Unix(40, 0).UTC()},
+		{Database: "db0", Measurement: "mem", Min: time.Unix(99, 0).UTC(), Max: time.Unix(99, 0).UTC()},
+	}
+	if got := l.Drain(); !reflect.DeepEqual(got, exp) {
+		t.Fatalf("unexpected ranges:\n\texp=%v\n\tgot=%v", exp, got)
+	}
+
+	// Draining resets the ranges.
+	if got := l.Drain(); len(got) != 0 {
+		t.Fatalf("unexpected ranges after drain: %v", got)
+	}
+}
@@ -0,0 +1,133 @@
+package coordinator
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync/atomic"
+
+	"github.com/ayang64/reflux/influxql"
+	"github.com/ayang64/reflux/logger"
+	"github.com/ayang64/reflux/models"
+	"github.com/ayang64/reflux/services/meta"
+	"github.com/ayang64/reflux/tsdb"
+	"go.uber.org/zap"
+)
+
+// maxSchemaViolations is the number of violations described by the error of
+// a single write.
+const maxSchemaViolations = 10
+
+// schemaCache holds the compiled measurement schemas of a database so that
+// their regexes are not compiled on every write.
+type schemaCache struct {
+	infos   []meta.MeasurementSchemaInfo
+	schemas tsdb.MeasurementSchemas
+}
+
+// measurementSchemas returns the compiled measurement schemas of a database.
+func (w *PointsWriter) measurementSchemas(database string) tsdb.MeasurementSchemas {
+	di := w.MetaClient.Database(database)
+	if di == nil || len(di.MeasurementSchemas) == 0 {
+		return nil
+	}
+
+	w.schemaMu.RLock()
+	c := w.schemas[database]
+	w.schemaMu.RUnlock()
+	if c != nil && reflect.DeepEqual(c.infos, di.MeasurementSchemas) {
+		return c.schemas
+	}
+
+	schemas := make(tsdb.MeasurementSchemas, len(di.MeasurementSchemas))
+	for _, msi := range di.MeasurementSchemas {
+		schemas[msi.Name] = newMeasurementSchema(msi)
+	}
+
+	w.schemaMu.Lock()
+	if w.schemas == nil {
+		w.schemas = make(map[string]*schemaCache)
+	}
+	w.schemas[database] = &schemaCache{infos: di.MeasurementSchemas, schemas: schemas}
+	w.schemaMu.Unlock()
+	return schemas
+}
+
+// newMeasurementSchema compiles the schema of a measurement stored in meta.
+func newMeasurementSchema(msi meta.MeasurementSchemaInfo) *tsdb.MeasurementSchema {
+	schema := &tsdb.MeasurementSchema{WarnOnly: msi.WarnOnly}
+	if len(msi.Fields) > 0 {
+		schema.Fields = make(map[string]influxql.DataType, len(msi.Fields))
+		for _, f := range msi.Fields {
+			schema.Fields[f.Name] = f.Type
+		}
+	}
+	if len(msi.Tags) > 0 {
+		schema.Tags = make([]tsdb.TagSchema, len(msi.Tags))
+		for i, t := range msi.Tags {
+			schema.Tags[i] = tsdb.TagSchema{
+				Key:       t.Key,
+				Required:  t.Required,
+				MaxValues: t.MaxValues,
+			}
+			// The regex was validated when the schema was created.
+			if t.Regex != "" {
+				schema.Tags[i].Values, _ = regexp.Compile(t.Regex)
+			}
+		}
+	}
+	return schema
+}
+
+// validateSchemas returns the points that match the schemas of their
+// measurements. Points that violate a schema in warn only mode are logged and
+// kept. If any points are dropped, the error describes why each of the first
+// few were dropped by their position in the write and their series key.
+// Every dropped point is added to rejected.
+func (w *PointsWriter) validateSchemas(database string, schemas tsdb.MeasurementSchemas, points []models.Point, rejected *tsdb.RejectedPoints) ([]models.Point, error) {
+	var (
+		kept       = make([]models.Point, 0, len(points))
+		violations []string
+		dropped    int
+	)
+	for i, p := range points {
+		schema := schemas[string(p.Name())]
+		if schema == nil {
+			kept = append(kept, p)
+			continue
+		}
+
+		err := schema.Validate(p)
+		if err == nil {
+			kept = append(kept, p)
+			continue
+		}
+		atomic.AddInt64(&w.stats.SchemaViolations, 1)
+
+		if schema.WarnOnly {
+			w.Logger.Warn("Point violates measurement schema",
+				logger.Database(database),
+				zap.String("key", string(p.Key())),
+				zap.Error(err))
+			kept = append(kept, p)
+			continue
+		}
+
+		dropped++
+		rejected.Add(p, tsdb.RejectSchemaViolation, err.Error())
+		if len(violations) < maxSchemaViolations {
+			violations = append(violations, fmt.Sprintf("point %d (%s): %s", i+1, p.Key(), err))
+		}
+	}
+
+	if dropped == 0 {
+		return points, nil
+	}
+
+	reason := fmt.Sprintf("%s: %s", tsdb.ErrMeasurementSchemaViolation, strings.Join(violations, "; "))
+	if n := dropped - len(violations); n > 0 {
+		reason += fmt.Sprintf(" (and %d more)", n)
+	}
+	return kept, tsdb.PartialWriteError{Reason: reason, Dropped: dropped}
+}
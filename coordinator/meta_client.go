@@ -12,21 +12,41 @@ type MetaClient interface {
 	CreateContinuousQuery(database, name, query string) error
 	CreateDatabase(name string) (*meta.DatabaseInfo, error)
 	CreateDatabaseWithRetentionPolicy(name string, spec *meta.RetentionPolicySpec) (*meta.DatabaseInfo, error)
+	CreateDownsamplePolicy(database string, dpi meta.DownsamplePolicyInfo) error
+	CreateMeasurementSchema(database string, msi meta.MeasurementSchemaInfo) error
 	CreateRetentionPolicy(database string, spec *meta.RetentionPolicySpec, makeDefault bool) (*meta.RetentionPolicyInfo, error)
-	CreateSubscription(database, rp, name, mode string, destinations []string) error
+	CreateRole(name string) error
+	CreateSecurityPolicy(pi meta.SecurityPolicyInfo) error
+	CreateSubscriptionWithFilter(database, rp, name, mode string, destinations, fields []string, condition string) error
+	CreateToken(username, description string, privileges map[string]influxql.Privilege, expiresAt time.Time) (*meta.TokenInfo, string, error)
 	CreateUser(name, password string, admin bool) (meta.User, error)
 	Database(name string) *meta.DatabaseInfo
 	Databases() []meta.DatabaseInfo
 	DropShard(id uint64) error
 	DropContinuousQuery(database, name string) error
 	DropDatabase(name string) error
+	DropDownsamplePolicy(database, name string) error
+	DropMeasurementSchema(database, name string) error
+	DropQuota(database string) error
 	DropRetentionPolicy(database, name string) error
+	DropRole(name string) error
+	DropSecurityPolicy(database, user, role string) error
 	DropSubscription(database, rp, name string) error
 	DropUser(name string) error
+	EffectivePrivileges(username string) ([]meta.PrivilegeInfo, error)
+	GrantRole(name, username string) error
+	GrantRolePrivilege(name string, p meta.PrivilegeInfo) error
 	RetentionPolicy(database, name string) (rpi *meta.RetentionPolicyInfo, err error)
+	RevokeRole(name, username string) error
+	RevokeRolePrivilege(name string, p meta.PrivilegeInfo) error
+	RevokeToken(id string) error
+	Roles() []meta.RoleInfo
+	SecurityPolicies() []meta.SecurityPolicyInfo
 	SetAdminPrivilege(username string, admin bool) error
 	SetPrivilege(username, database string, p influxql.Privilege) error
+	SetQuota(database string, qi meta.QuotaInfo) error
 	ShardGroupsByTimeRange(database, policy string, min, max time.Time) (a []meta.ShardGroupInfo, err error)
+	Tokens() []meta.TokenInfo
 	TruncateShardGroups(t time.Time) error
 	UpdateRetentionPolicy(database, name string, rpu *meta.RetentionPolicyUpdate, makeDefault bool) error
 	UpdateUser(name, password string) error
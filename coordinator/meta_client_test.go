@@ -12,8 +12,13 @@ type MetaClient struct {
 	CreateContinuousQueryFn             func(database, name, query string) error
 	CreateDatabaseFn                    func(name string) (*meta.DatabaseInfo, error)
 	CreateDatabaseWithRetentionPolicyFn func(name string, spec *meta.RetentionPolicySpec) (*meta.DatabaseInfo, error)
+	CreateDownsamplePolicyFn            func(database string, dpi meta.DownsamplePolicyInfo) error
+	CreateMeasurementSchemaFn           func(database string, msi meta.MeasurementSchemaInfo) error
 	CreateRetentionPolicyFn             func(database string, spec *meta.RetentionPolicySpec, makeDefault bool) (*meta.RetentionPolicyInfo, error)
-	CreateSubscriptionFn                func(database, rp, name, mode string, destinations []string) error
+	CreateRoleFn                        func(name string) error
+	CreateSecurityPolicyFn              func(pi meta.SecurityPolicyInfo) error
+	CreateSubscriptionWithFilterFn      func(database, rp, name, mode string, destinations, fields []string, condition string) error
+	CreateTokenFn                       func(username, description string, privileges map[string]influxql.Privilege, expiresAt time.Time) (*meta.TokenInfo, string, error)
 	CreateUserFn                        func(name, password string, admin bool) (meta.User, error)
 	DatabaseFn                          func(name string) *meta.DatabaseInfo
 	DatabasesFn                         func() []meta.DatabaseInfo
@@ -23,15 +28,30 @@ type MetaClient struct {
 	DeleteMetaNodeFn                    func(id uint64) error
 	DropContinuousQueryFn               func(database, name string) error
 	DropDatabaseFn                      func(name string) error
+	DropDownsamplePolicyFn              func(database, name string) error
+	DropMeasurementSchemaFn             func(database, name string) error
+	DropQuotaFn                         func(database string) error
 	DropRetentionPolicyFn               func(database, name string) error
+	DropRoleFn                          func(name string) error
+	DropSecurityPolicyFn                func(database, user, role string) error
 	DropSubscriptionFn                  func(database, rp, name string) error
 	DropShardFn                         func(id uint64) error
 	DropUserFn                          func(name string) error
+	EffectivePrivilegesFn               func(username string) ([]meta.PrivilegeInfo, error)
+	GrantRoleFn                         func(name, username string) error
+	GrantRolePrivilegeFn                func(name string, p meta.PrivilegeInfo) error
 	MetaNodesFn                         func() ([]meta.NodeInfo, error)
 	RetentionPolicyFn                   func(database, name string) (rpi *meta.RetentionPolicyInfo, err error)
+	RevokeRoleFn                        func(name, username string) error
+	RevokeRolePrivilegeFn               func(name string, p meta.PrivilegeInfo) error
+	RevokeTokenFn                       func(id string) error
+	RolesFn                             func() []meta.RoleInfo
+	SecurityPoliciesFn                  func() []meta.SecurityPolicyInfo
 	SetAdminPrivilegeFn                 func(username string, admin bool) error
 	SetPrivilegeFn                      func(username, database string, p influxql.Privilege) error
+	SetQuotaFn                          func(database string, qi meta.QuotaInfo) error
 	ShardGroupsByTimeRangeFn            func(database, policy string, min, max time.Time) (a []meta.ShardGroupInfo, err error)
+	TokensFn                            func() []meta.TokenInfo
 	TruncateShardGroupsFn               func(t time.Time) error
 	UpdateRetentionPolicyFn             func(database, name string, rpu *meta.RetentionPolicyUpdate, makeDefault bool) error
 	UpdateUserFn                        func(name, password string) error
@@ -52,6 +72,14 @@ func (c *MetaClient) CreateDatabaseWithRetentionPolicy(name string, spec *meta.R
 	return c.CreateDatabaseWithRetentionPolicyFn(name, spec)
 }
 
+func (c *MetaClient) CreateDownsamplePolicy(database string, dpi meta.DownsamplePolicyInfo) error {
+	return c.CreateDownsamplePolicyFn(database, dpi)
+}
+
+func (c *MetaClient) CreateMeasurementSchema(database string, msi meta.MeasurementSchemaInfo) error {
+	return c.CreateMeasurementSchemaFn(database, msi)
+}
+
 func (c *MetaClient) CreateRetentionPolicy(database string, spec *meta.RetentionPolicySpec, makeDefault bool) (*meta.RetentionPolicyInfo, error) {
 	return c.CreateRetentionPolicyFn(database, spec, makeDefault)
 }
@@ -60,8 +88,8 @@ func (c *MetaClient) DropShard(id uint64) error {
 	return c.DropShardFn(id)
 }
 
-func (c *MetaClient) CreateSubscription(database, rp, name, mode string, destinations []string) error {
-	return c.CreateSubscriptionFn(database, rp, name, mode, destinations)
+func (c *MetaClient) CreateSubscriptionWithFilter(database, rp, name, mode string, destinations, fields []string, condition string) error {
+	return c.CreateSubscriptionWithFilterFn(database, rp, name, mode, destinations, fields, condition)
 }
 
 func (c *MetaClient) CreateUser(name, password string, admin bool) (meta.User, error) {
@@ -100,6 +128,18 @@ func (c *MetaClient) DropDatabase(name string) error {
 	return c.DropDatabaseFn(name)
 }
 
+func (c *MetaClient) DropDownsamplePolicy(database, name string) error {
+	return c.DropDownsamplePolicyFn(database, name)
+}
+
+func (c *MetaClient) DropMeasurementSchema(database, name string) error {
+	return c.DropMeasurementSchemaFn(database, name)
+}
+
+func (c *MetaClient) DropQuota(database string) error {
+	return c.DropQuotaFn(database)
+}
+
 func (c *MetaClient) DropRetentionPolicy(database, name string) error {
 	return c.DropRetentionPolicyFn(database, name)
 }
@@ -128,6 +168,10 @@ func (c *MetaClient) SetPrivilege(username, database string, p influxql.Privileg
 	return c.SetPrivilegeFn(username, database, p)
 }
 
+func (c *MetaClient) SetQuota(database string, qi meta.QuotaInfo) error {
+	return c.SetQuotaFn(database, qi)
+}
+
 func (c *MetaClient) ShardGroupsByTimeRange(database, policy string, min, max time.Time) (a []meta.ShardGroupInfo, err error) {
 	return c.ShardGroupsByTimeRangeFn(database, policy, min, max)
 }
@@ -164,3 +208,59 @@ func DefaultMetaClientDatabaseFn(name string) *meta.DatabaseInfo {
 		DefaultRetentionPolicy: DefaultRetentionPolicy,
 	}
 }
+
+func (c *MetaClient) CreateRole(name string) error {
+	return c.CreateRoleFn(name)
+}
+
+func (c *MetaClient) DropRole(name string) error {
+	return c.DropRoleFn(name)
+}
+
+func (c *MetaClient) EffectivePrivileges(username string) ([]meta.PrivilegeInfo, error) {
+	return c.EffectivePrivilegesFn(username)
+}
+
+func (c *MetaClient) GrantRole(name, username string) error {
+	return c.GrantRoleFn(name, username)
+}
+
+func (c *MetaClient) GrantRolePrivilege(name string, p meta.PrivilegeInfo) error {
+	return c.GrantRolePrivilegeFn(name, p)
+}
+
+func (c *MetaClient) RevokeRole(name, username string) error {
+	return c.RevokeRoleFn(name, username)
+}
+
+func (c *MetaClient) RevokeRolePrivilege(name string, p meta.PrivilegeInfo) error {
+	return c.RevokeRolePrivilegeFn(name, p)
+}
+
+func (c *MetaClient) Roles() []meta.RoleInfo {
+	return c.RolesFn()
+}
+
+func (c *MetaClient) CreateToken(username, description string, privileges map[string]influxql.Privilege, expiresAt time.Time) (*meta.TokenInfo, string, error) {
+	return c.CreateTokenFn(username, description, privileges, expiresAt)
+}
+
+func (c *MetaClient) CreateSecurityPolicy(pi meta.SecurityPolicyInfo) error {
+	return c.CreateSecurityPolicyFn(pi)
+}
+
+func (c *MetaClient) DropSecurityPolicy(database, user, role string) error {
+	return c.DropSecurityPolicyFn(database, user, role)
+}
+
+func (c *MetaClient) SecurityPolicies() []meta.SecurityPolicyInfo {
+	return c.SecurityPoliciesFn()
+}
+
+func (c *MetaClient) RevokeToken(id string) error {
+	return c.RevokeTokenFn(id)
+}
+
+func (c *MetaClient) Tokens() []meta.TokenInfo {
+	return c.TokensFn()
+}
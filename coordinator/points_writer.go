@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/ayang64/reflux"
+	"github.com/ayang64/reflux/logger"
 	"github.com/ayang64/reflux/models"
 	"github.com/ayang64/reflux/services/meta"
 	"github.com/ayang64/reflux/tsdb"
@@ -27,6 +28,9 @@ const (
 	statWriteErr           = "writeError"
 	statSubWriteOK         = "subWriteOk"
 	statSubWriteDrop       = "subWriteDrop"
+	statSchemaViolations   = "schemaViolations"
+	statWriteDuplicate     = "writeDuplicate"
+	statWriteQuotaExceeded = "writeQuotaExceeded"
 )
 
 var (
@@ -61,7 +65,23 @@ type PointsWriter struct {
 		WriteToShard(shardID uint64, points []models.Point) error
 	}
 
-	subPoints []chan<- *WritePointsRequest
+	// LateWrites, if set, records the points that arrive behind the windows
+	// continuous queries have already computed.
+	LateWrites *LateWriteTracker
+
+	// BatchIDs, if set, remembers the batches that were written so that a
+	// write with the WriteBatchID of a committed batch is acknowledged
+	// without writing its points again.
+	BatchIDs *BatchIDStore
+
+	subPoints  []chan<- *WritePointsRequest
+	subWriters []WriteSubscriber
+
+	schemaMu sync.RWMutex
+	schemas  map[string]*schemaCache
+
+	rateMu sync.RWMutex
+	rates  map[string]*writeRate
 
 	stats *WriteStatistics
 }
@@ -100,6 +120,10 @@ type ShardMapping struct {
 	Points  map[uint64][]models.Point  // The points associated with a shard ID
 	Shards  map[uint64]*meta.ShardInfo // The shards that have been mapped, keyed by shard ID
 	Dropped []models.Point             // Points that were dropped
+
+	// FieldTypePolicy is how the shards write values that conflict with
+	// the type of an existing field.
+	FieldTypePolicy tsdb.FieldTypePolicy
 }
 
 // NewShardMapping creates an empty ShardMapping.
@@ -125,6 +149,9 @@ func (w *PointsWriter) Open() error {
 	w.mu.Lock()
 	defer w.mu.Unlock()
 	w.closing = make(chan struct{})
+	if w.BatchIDs != nil {
+		return w.BatchIDs.Open()
+	}
 	return nil
 }
 
@@ -141,6 +168,9 @@ func (w *PointsWriter) Close() error {
 		// dropping any in-flight writes.
 		w.subPoints = nil
 	}
+	if w.BatchIDs != nil {
+		return w.BatchIDs.Close()
+	}
 	return nil
 }
 
@@ -148,6 +178,19 @@ func (w *PointsWriter) AddWriteSubscriber(c chan<- *WritePointsRequest) {
 	w.subPoints = append(w.subPoints, c)
 }
 
+// WriteSubscriber receives the points of a write before the write returns.
+type WriteSubscriber interface {
+	WritePoints(p *WritePointsRequest) error
+}
+
+// AddDurableWriteSubscriber adds a subscriber that receives the points of every
+// write that the shards accepted. Unlike the channels added with
+// AddWriteSubscriber, writes are not dropped when the subscriber is busy, and
+// a write fails if the subscriber returns an error.
+func (w *PointsWriter) AddDurableWriteSubscriber(s WriteSubscriber) {
+	w.subWriters = append(w.subWriters, s)
+}
+
 // WithLogger sets the Logger on w.
 func (w *PointsWriter) WithLogger(log *zap.Logger) {
 	w.Logger = log.With(zap.String("service", "write"))
@@ -164,6 +207,9 @@ type WriteStatistics struct {
 	WriteErr           int64
 	SubWriteOK         int64
 	SubWriteDrop       int64
+	SchemaViolations   int64
+	WriteDuplicate     int64
+	WriteQuotaExceeded int64
 }
 
 // Statistics returns statistics for periodic monitoring.
@@ -181,6 +227,9 @@ func (w *PointsWriter) Statistics(tags map[string]string) []models.Statistic {
 			statWriteErr:           atomic.LoadInt64(&w.stats.WriteErr),
 			statSubWriteOK:         atomic.LoadInt64(&w.stats.SubWriteOK),
 			statSubWriteDrop:       atomic.LoadInt64(&w.stats.SubWriteDrop),
+			statSchemaViolations:   atomic.LoadInt64(&w.stats.SchemaViolations),
+			statWriteDuplicate:     atomic.LoadInt64(&w.stats.WriteDuplicate),
+			statWriteQuotaExceeded: atomic.LoadInt64(&w.stats.WriteQuotaExceeded),
 		},
 	}}
 }
@@ -224,6 +273,9 @@ func (w *PointsWriter) MapShards(wp *WritePointsRequest) (*ShardMapping, error)
 	}
 
 	mapping := NewShardMapping(len(wp.Points))
+	if mapping.FieldTypePolicy, err = tsdb.ParseFieldTypePolicy(rp.FieldTypePolicy); err != nil {
+		return nil, err
+	}
 	for _, p := range wp.Points {
 		sg := list.ShardGroupAt(p.Time())
 		if sg == nil {
@@ -293,6 +345,7 @@ type ContextKey int
 const (
 	StatPointsWritten = ContextKey(iota)
 	StatValuesWritten
+	WriteBatchID
 )
 
 // WritePointsWithContext writes data to the underlying storage. consitencyLevel and user are only used for clustered scenarios.
@@ -312,10 +365,58 @@ func (w *PointsWriter) WritePointsPrivileged(database, retentionPolicy string, c
 // sent via context values, this stores the total points and fields written in
 // the memory pointed to by the associated wth the int64 pointers.
 //
+// If a *tsdb.RejectedPoints is stored in the tsdb.WriteRejectedPoints context
+// value, every point that is dropped is added to it with the reason.
+//
+// Writes to a database with a quota are rejected with a
+// tsdb.QuotaExceededError if they would exceed its points per second quota,
+// or if the database has reached its disk quota. Points that would create
+// series beyond its series quota are dropped.
+//
+// If a batch ID is stored in the WriteBatchID context value and the batch was
+// already written to the database, the points are not written again. The
+// batch is remembered once all of its points have been written, even if the
+// write times out first. If some of its shards fail, a retry of the batch only
+// writes the shards that failed.
+//
 func (w *PointsWriter) WritePointsPrivilegedWithContext(ctx context.Context, database, retentionPolicy string, consistencyLevel models.ConsistencyLevel, points []models.Point) error {
 	atomic.AddInt64(&w.stats.WriteReq, 1)
 	atomic.AddInt64(&w.stats.PointWriteReq, int64(len(points)))
 
+	var batch *batchWrite
+	if id, _ := ctx.Value(WriteBatchID).(string); id != "" && w.BatchIDs != nil {
+		b, ok, err := w.BatchIDs.begin(ctx, database, id)
+		if err != nil {
+			return err
+		} else if !ok {
+			atomic.AddInt64(&w.stats.WriteDuplicate, 1)
+			return nil
+		}
+		batch = b
+	}
+
+	err := w.writePoints(ctx, database, retentionPolicy, points, batch)
+	if batch != nil && !batch.tracked {
+		// The batch was not handed to the shard writes, so nothing was written
+		// unless some points were dropped.
+		_, partial := err.(tsdb.PartialWriteError)
+		if ferr := batch.finish(err == nil || partial, nil); ferr != nil {
+			w.Logger.Error("Failed to commit batch id", logger.Database(database), zap.Error(ferr))
+		}
+	}
+	return err
+}
+
+// shardWriteResult is the result of writing points to a shard.
+type shardWriteResult struct {
+	shardID uint64
+	err     error
+}
+
+// writePoints writes the points to their shards. If batch is set, it is
+// finished once all of the shard writes are done.
+func (w *PointsWriter) writePoints(ctx context.Context, database, retentionPolicy string, points []models.Point, batch *batchWrite) error {
+
 	if retentionPolicy == "" {
 		db := w.MetaClient.Database(database)
 		if db == nil {
@@ -324,22 +425,88 @@ func (w *PointsWriter) WritePointsPrivilegedWithContext(ctx context.Context, dat
 		retentionPolicy = db.DefaultRetentionPolicy
 	}
 
+	rejected, _ := ctx.Value(tsdb.WriteRejectedPoints).(*tsdb.RejectedPoints)
+
+	// Check the write against the quota of the database.
+	quota, err := w.checkQuota(database, len(points))
+	if err != nil {
+		return err
+	}
+
+	// Check the points against the schemas of their measurements.
+	var schemaErr error
+	schemas := w.measurementSchemas(database)
+	if len(schemas) > 0 {
+		if points, schemaErr = w.validateSchemas(database, schemas, points, rejected); len(points) == 0 {
+			return schemaErr
+		}
+	}
+
 	shardMappings, err := w.MapShards(&WritePointsRequest{Database: database, RetentionPolicy: retentionPolicy, Points: points})
 	if err != nil {
 		return err
 	}
+	for _, p := range shardMappings.Dropped {
+		rejected.Add(p, tsdb.RejectOutsideRetention, fmt.Sprintf("point is outside retention policy %q", retentionPolicy))
+	}
+
+	// The shards written by an earlier write of the batch are not written
+	// again.
+	if batch != nil && len(batch.written) > 0 {
+		points = points[:0:0]
+		for shardID, pts := range shardMappings.Points {
+			if batch.skip(shardID) {
+				delete(shardMappings.Points, shardID)
+				continue
+			}
+			points = append(points, pts...)
+		}
+	}
+
+	// The points rejected by each shard are collected separately when there
+	// are durable subscribers, so that they only receive the accepted points.
+	w.mu.RLock()
+	durable := len(w.subWriters) > 0
+	w.mu.RUnlock()
 
 	// Write each shard in it's own goroutine and return as soon as one fails.
-	ch := make(chan error, len(shardMappings.Points))
+	ch := make(chan shardWriteResult, len(shardMappings.Points))
 	for shardID, points := range shardMappings.Points {
 		go func(ctx context.Context, shard *meta.ShardInfo, database, retentionPolicy string, points []models.Point) {
 			var numPoints, numValues int64
 			ctx = context.WithValue(ctx, tsdb.StatPointsWritten, &numPoints)
 			ctx = context.WithValue(ctx, tsdb.StatValuesWritten, &numValues)
+			ctx = context.WithValue(ctx, tsdb.WriteFieldTypePolicy, shardMappings.FieldTypePolicy)
+			ctx = context.WithValue(ctx, tsdb.WriteMeasurementSchemas, schemas)
+			if quota != nil {
+				ctx = context.WithValue(ctx, tsdb.WriteQuota, quota)
+			}
+			shardRejected := rejected
+			if durable {
+				shardRejected = &tsdb.RejectedPoints{}
+				ctx = context.WithValue(ctx, tsdb.WriteRejectedPoints, shardRejected)
+			}
 
 			err := w.writeToShardWithContext(ctx, shard, database, retentionPolicy, points)
 			if err == tsdb.ErrShardDeletion {
 				err = tsdb.PartialWriteError{Reason: fmt.Sprintf("shard %d is pending deletion", shard.ID), Dropped: len(points)}
+				for _, p := range points {
+					shardRejected.Add(p, tsdb.RejectShardPendingDeletion, fmt.Sprintf("shard %d is pending deletion", shard.ID))
+				}
+			}
+
+			// Hand the points the shard accepted to the durable subscribers.
+			// The shard write fails if they did not receive them, so that the
+			// points are queued again when the write is retried.
+			if durable {
+				for _, r := range shardRejected.Points() {
+					rejected.Add(r.Point, r.Code, r.Reason)
+				}
+				if _, ok := err.(tsdb.PartialWriteError); err == nil || ok {
+					if serr := w.writeDurableSubscribers(database, retentionPolicy, acceptedPoints(points, shardRejected)); serr != nil {
+						err = serr
+					}
+				}
 			}
 
 			if v, ok := ctx.Value(StatPointsWritten).(*int64); ok {
@@ -350,10 +517,19 @@ func (w *PointsWriter) WritePointsPrivilegedWithContext(ctx context.Context, dat
 				atomic.AddInt64(v, numValues)
 			}
 
-			ch <- err
+			ch <- shardWriteResult{shardID: shard.ID, err: err}
 		}(ctx, shardMappings.Shards[shardID], database, retentionPolicy, points)
 	}
 
+	// A batch is finished once all of its shard writes are done rather than
+	// when the caller stops waiting for them.
+	var results <-chan shardWriteResult = ch
+	if batch != nil && len(shardMappings.Points) > 0 {
+		results = batch.track(ch, len(shardMappings.Points), func(err error) {
+			w.Logger.Error("Failed to commit batch id", logger.Database(database), zap.Error(err))
+		})
+	}
+
 	// Send points to subscriptions if possible.
 	var ok, dropped int64
 	pts := &WritePointsRequest{Database: database, RetentionPolicy: retentionPolicy, Points: points}
@@ -377,8 +553,9 @@ func (w *PointsWriter) WritePointsPrivilegedWithContext(ctx context.Context, dat
 		atomic.AddInt64(&w.stats.SubWriteDrop, dropped)
 	}
 
-	if err == nil && len(shardMappings.Dropped) > 0 {
-		err = tsdb.PartialWriteError{Reason: "points beyond retention policy", Dropped: len(shardMappings.Dropped)}
+	err = schemaErr
+	if len(shardMappings.Dropped) > 0 {
+		err = mergePartialWriteError(err, tsdb.PartialWriteError{Reason: "points beyond retention policy", Dropped: len(shardMappings.Dropped)})
 	}
 	timeout := time.NewTimer(w.WriteTimeout)
 	defer timeout.Stop()
@@ -390,15 +567,81 @@ func (w *PointsWriter) WritePointsPrivilegedWithContext(ctx context.Context, dat
 			atomic.AddInt64(&w.stats.WriteTimeout, 1)
 			// return timeout error to caller
 			return ErrTimeout
-		case err := <-ch:
-			if err != nil {
-				return err
+		case res := <-results:
+			serr := res.err
+			if serr == nil {
+				continue
+			}
+			// The points a shard did not drop are written, so wait for the
+			// other shards to track them as well.
+			perr, ok := serr.(tsdb.PartialWriteError)
+			if !ok {
+				return serr
 			}
+			err = mergePartialWriteError(err, perr)
 		}
 	}
+
+	if w.LateWrites != nil {
+		w.LateWrites.Track(database, points)
+	}
 	return err
 }
 
+// mergePartialWriteError adds the points dropped by perr to err. The reason of
+// err is kept if it is already a partial write.
+func mergePartialWriteError(err error, perr tsdb.PartialWriteError) error {
+	if cur, ok := err.(tsdb.PartialWriteError); ok {
+		cur.Dropped += perr.Dropped
+		return cur
+	}
+	return perr
+}
+
+// writeDurableSubscribers hands points to the durable subscribers and returns
+// the first error of a subscriber.
+func (w *PointsWriter) writeDurableSubscribers(database, retentionPolicy string, points []models.Point) error {
+	if len(points) == 0 {
+		return nil
+	}
+
+	pts := &WritePointsRequest{Database: database, RetentionPolicy: retentionPolicy, Points: points}
+	var ok int64
+	var err error
+	w.mu.RLock()
+	for _, sw := range w.subWriters {
+		if err = sw.WritePoints(pts); err != nil {
+			break
+		}
+		ok++
+	}
+	w.mu.RUnlock()
+	if ok > 0 {
+		atomic.AddInt64(&w.stats.SubWriteOK, ok)
+	}
+	return err
+}
+
+// acceptedPoints returns the points that are not in rejected.
+func acceptedPoints(points []models.Point, rejected *tsdb.RejectedPoints) []models.Point {
+	dropped := rejected.Points()
+	if len(dropped) == 0 {
+		return points
+	}
+
+	set := make(map[models.Point]struct{}, len(dropped))
+	for _, r := range dropped {
+		set[r.Point] = struct{}{}
+	}
+	accepted := make([]models.Point, 0, len(points))
+	for _, p := range points {
+		if _, ok := set[p]; !ok {
+			accepted = append(accepted, p)
+		}
+	}
+	return accepted
+}
+
 func (w *PointsWriter) writeToShardWithContext(ctx context.Context, shard *meta.ShardInfo, database, retentionPolicy string, points []models.Point) error {
 	atomic.AddInt64(&w.stats.PointWriteReqLocal, int64(len(points)))
 
@@ -1,6 +1,8 @@
 package coordinator_test
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"reflect"
 	"sync"
@@ -10,6 +12,7 @@ import (
 
 	"github.com/ayang64/reflux"
 	"github.com/ayang64/reflux/coordinator"
+	"github.com/ayang64/reflux/influxql"
 	"github.com/ayang64/reflux/models"
 	"github.com/ayang64/reflux/services/meta"
 	"github.com/ayang64/reflux/tsdb"
@@ -428,6 +431,199 @@ func TestPointsWriter_WritePoints_Dropped(t *testing.T) {
 	}
 }
 
+// Ensures points that violate a measurement schema are dropped unless the
+// schema is in warn only mode.
+func TestPointsWriter_WritePoints_MeasurementSchema(t *testing.T) {
+	for _, warnOnly := range []bool{false, true} {
+		ms := NewPointsWriterMetaClient()
+		ms.DatabaseFn = func(database string) *meta.DatabaseInfo {
+			return &meta.DatabaseInfo{
+				Name: database,
+				MeasurementSchemas: []meta.MeasurementSchemaInfo{{
+					Name:     "cpu",
+					Fields:   []meta.MeasurementSchemaFieldInfo{{Name: "value", Type: influxql.Float}},
+					Tags:     []meta.MeasurementSchemaTagInfo{{Key: "host", Required: true}},
+					WarnOnly: warnOnly,
+				}},
+			}
+		}
+		ms.NodeIDFn = func() uint64 { return 1 }
+
+		pr := &coordinator.WritePointsRequest{Database: "mydb", RetentionPolicy: "myrp"}
+		pr.AddPoint("cpu", 1.0, time.Now(), map[string]string{"host": "server01"})
+		pr.AddPoint("cpu", 2.0, time.Now(), nil)
+		pr.AddPoint("cpu", 3.0, time.Now(), map[string]string{"host": "server01", "region": "west"})
+		pr.AddPoint("mem", 4.0, time.Now(), nil)
+
+		var written int64
+		store := &fakeStore{
+			WriteFn: func(shardID uint64, points []models.Point) error {
+				atomic.AddInt64(&written, int64(len(points)))
+				return nil
+			},
+		}
+
+		c := coordinator.NewPointsWriter()
+		c.MetaClient = ms
+		c.TSDBStore = store
+		c.Node = &influxdb.Node{ID: 1}
+		c.Open()
+
+		err := c.WritePointsPrivileged(pr.Database, pr.RetentionPolicy, models.ConsistencyLevelOne, pr.Points)
+		c.Close()
+
+		if warnOnly {
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			} else if written != 4 {
+				t.Fatalf("unexpected points written: got %d, exp 4", written)
+			}
+			continue
+		}
+
+		perr, ok := err.(tsdb.PartialWriteError)
+		if !ok {
+			t.Fatalf("unexpected error: got %v, exp %v", err, tsdb.PartialWriteError{})
+		} else if perr.Dropped != 2 {
+			t.Fatalf("unexpected dropped: got %d, exp 2", perr.Dropped)
+		} else if exp := `measurement schema violation: point 2 (cpu): required tag key "host" is missing; point 3 (cpu,host=server01,region=west): tag key "region" is not allowed`; perr.Reason != exp {
+			t.Fatalf("unexpected reason:\ngot %s\nexp %s", perr.Reason, exp)
+		} else if written != 2 {
+			t.Fatalf("unexpected points written: got %d, exp 2", written)
+		}
+	}
+}
+
+// Ensures writes above the points per second quota of a database are
+// rejected and the disk and series quotas are passed to the store.
+func TestPointsWriter_WritePoints_Quota(t *testing.T) {
+	ms := NewPointsWriterMetaClient()
+	ms.DatabaseFn = func(database string) *meta.DatabaseInfo {
+		return &meta.DatabaseInfo{
+			Name:  database,
+			Quota: &meta.QuotaInfo{MaxSeries: 10, MaxPointsPerSecond: 2},
+		}
+	}
+	ms.NodeIDFn = func() uint64 { return 1 }
+
+	var written int64
+	store := &fakeContextStore{
+		WriteFn: func(ctx context.Context, shardID uint64, points []models.Point) error {
+			if quota, _ := ctx.Value(tsdb.WriteQuota).(*tsdb.Quota); quota == nil || *quota != (tsdb.Quota{MaxSeries: 10}) {
+				t.Errorf("unexpected quota: %v", quota)
+			}
+			atomic.AddInt64(&written, int64(len(points)))
+			return nil
+		},
+	}
+
+	c := coordinator.NewPointsWriter()
+	c.MetaClient = ms
+	c.TSDBStore = store
+	c.Node = &influxdb.Node{ID: 1}
+	c.Open()
+	defer c.Close()
+
+	pr := &coordinator.WritePointsRequest{Database: "mydb", RetentionPolicy: "myrp"}
+	pr.AddPoint("cpu", 1.0, time.Now(), nil)
+	pr.AddPoint("cpu", 2.0, time.Now(), nil)
+	pr.AddPoint("cpu", 3.0, time.Now(), nil)
+
+	// A batch larger than the quota is written when nothing else was, and
+	// the writes after it are rejected until the excess has passed.
+	if err := c.WritePointsPrivileged(pr.Database, pr.RetentionPolicy, models.ConsistencyLevelOne, pr.Points); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	} else if written != 3 {
+		t.Fatalf("unexpected points written: got %d, exp 3", written)
+	}
+
+	err := c.WritePointsPrivileged(pr.Database, pr.RetentionPolicy, models.ConsistencyLevelOne, pr.Points[:2])
+	if exp := (tsdb.QuotaExceededError{Database: "mydb", Resource: tsdb.QuotaPointsPerSecond, Limit: 2}); err != exp {
+		t.Fatalf("unexpected error: got %v, exp %v", err, exp)
+	} else if written != 3 {
+		t.Fatalf("unexpected points written: got %d, exp 3", written)
+	}
+}
+
+// Ensures durable subscribers receive the points of every write that the
+// shards accepted and fail the writes they do not receive.
+func TestPointsWriter_WritePoints_DurableSubscriber(t *testing.T) {
+	ms := NewPointsWriterMetaClient()
+	ms.DatabaseFn = func(database string) *meta.DatabaseInfo {
+		return nil
+	}
+	ms.NodeIDFn = func() uint64 { return 1 }
+
+	// The shard rejects the points with a value of -1.
+	store := &fakeContextStore{
+		WriteFn: func(ctx context.Context, shardID uint64, points []models.Point) error {
+			rejected, _ := ctx.Value(tsdb.WriteRejectedPoints).(*tsdb.RejectedPoints)
+			var dropped int
+			for _, p := range points {
+				if fields, _ := p.Fields(); fields["value"] == -1.0 {
+					rejected.Add(p, tsdb.RejectFieldTypeConflict, "conflict")
+					dropped++
+				}
+			}
+			if dropped > 0 {
+				return tsdb.PartialWriteError{Reason: "conflict", Dropped: dropped}
+			}
+			return nil
+		},
+	}
+
+	var received []models.Point
+	var subErr error
+	sub := DurableSubscriber{
+		WritePointsFn: func(p *coordinator.WritePointsRequest) error {
+			if subErr != nil {
+				return subErr
+			}
+			received = append(received, p.Points...)
+			return nil
+		},
+	}
+
+	c := coordinator.NewPointsWriter()
+	c.MetaClient = ms
+	c.TSDBStore = store
+	c.AddDurableWriteSubscriber(sub)
+	c.Node = &influxdb.Node{ID: 1}
+	c.Open()
+	defer c.Close()
+
+	pr := &coordinator.WritePointsRequest{Database: "mydb", RetentionPolicy: "myrp"}
+	pr.AddPoint("cpu", 1.0, time.Now(), nil)
+
+	// Nothing is dropped when there are more writes than a subscriber
+	// channel buffers.
+	for i := 0; i < 200; i++ {
+		if err := c.WritePointsPrivileged(pr.Database, pr.RetentionPolicy, models.ConsistencyLevelOne, pr.Points); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if len(received) != 200 {
+		t.Fatalf("unexpected points received: got %d, exp 200", len(received))
+	}
+
+	// The points the shard rejected are not received.
+	received = nil
+	pr.AddPoint("cpu", -1.0, time.Now(), nil)
+	if err := c.WritePointsPrivileged(pr.Database, pr.RetentionPolicy, models.ConsistencyLevelOne, pr.Points); err == nil {
+		t.Fatal("expected partial write error")
+	} else if _, ok := err.(tsdb.PartialWriteError); !ok {
+		t.Fatalf("unexpected error: %v", err)
+	} else if len(received) != 1 || received[0] != pr.Points[0] {
+		t.Fatalf("unexpected points received: %v", received)
+	}
+
+	// A write that a subscriber did not receive fails so that it is retried.
+	subErr = errors.New("disk full")
+	if err := c.WritePointsPrivileged(pr.Database, pr.RetentionPolicy, models.ConsistencyLevelOne, pr.Points[:1]); err != subErr {
+		t.Fatalf("unexpected error: got %v, exp %v", err, subErr)
+	}
+}
+
 type fakePointsWriter struct {
 	WritePointsIntoFn func(*coordinator.IntoWriteRequest) error
 }
@@ -561,6 +757,22 @@ func (f *fakeStore) CreateShard(database, retentionPolicy string, shardID uint64
 	return f.CreateShardfn(database, retentionPolicy, shardID, enabled)
 }
 
+type fakeContextStore struct {
+	WriteFn func(ctx context.Context, shardID uint64, points []models.Point) error
+}
+
+func (f *fakeContextStore) WriteToShard(shardID uint64, points []models.Point) error {
+	return f.WriteFn(context.Background(), shardID, points)
+}
+
+func (f *fakeContextStore) WriteToShardWithContext(ctx context.Context, shardID uint64, points []models.Point) error {
+	return f.WriteFn(ctx, shardID, points)
+}
+
+func (f *fakeContextStore) CreateShard(database, retentionPolicy string, shardID uint64, enabled bool) error {
+	return nil
+}
+
 func NewPointsWriterMetaClient() *PointsWriterMetaClient {
 	ms := &PointsWriterMetaClient{}
 	rp := NewRetentionPolicy("myp", time.Hour, 3)
@@ -624,6 +836,14 @@ func (s Subscriber) Points() chan<- *coordinator.WritePointsRequest {
 	return s.PointsFn()
 }
 
+type DurableSubscriber struct {
+	WritePointsFn func(p *coordinator.WritePointsRequest) error
+}
+
+func (s DurableSubscriber) WritePoints(p *coordinator.WritePointsRequest) error {
+	return s.WritePointsFn(p)
+}
+
 func NewRetentionPolicy(name string, duration time.Duration, nodeCount int) *meta.RetentionPolicyInfo {
 	shards := []meta.ShardInfo{}
 	owners := []meta.ShardOwner{}
@@ -681,3 +901,88 @@ func AttachShardGroupInfo(rp *meta.RetentionPolicyInfo, owners []meta.ShardOwner
 func nextShardID() uint64 {
 	return atomic.AddUint64(&shardID, 1)
 }
+
+// Ensures late points are tracked even if a shard drops some of the points.
+func TestPointsWriter_WritePoints_LateWritesPartial(t *testing.T) {
+	ms := NewPointsWriterMetaClient()
+	ms.DatabaseFn = func(database string) *meta.DatabaseInfo {
+		return nil
+	}
+	ms.NodeIDFn = func() uint64 { return 1 }
+
+	store := &fakeStore{
+		WriteFn: func(shardID uint64, points []models.Point) error {
+			return tsdb.PartialWriteError{Reason: "field type conflict", Dropped: 1}
+		},
+	}
+
+	c := coordinator.NewPointsWriter()
+	c.MetaClient = ms
+	c.TSDBStore = store
+	c.LateWrites = coordinator.NewLateWriteTracker()
+	c.Node = &influxdb.Node{ID: 1}
+	c.Open()
+	defer c.Close()
+
+	now := time.Now().UTC()
+	c.LateWrites.SetWatermark("mydb", now.Add(time.Hour))
+
+	pr := &coordinator.WritePointsRequest{Database: "mydb", RetentionPolicy: "myrp"}
+	pr.AddPoint("cpu", 1.0, now, nil)
+	pr.AddPoint("cpu", 2.0, now, nil)
+
+	err := c.WritePointsPrivileged(pr.Database, pr.RetentionPolicy, models.ConsistencyLevelOne, pr.Points)
+	if _, ok := err.(tsdb.PartialWriteError); !ok {
+		t.Fatalf("unexpected error: got %v, exp %v", err, tsdb.PartialWriteError{})
+	}
+
+	ranges := c.LateWrites.Drain()
+	if len(ranges) != 1 {
+		t.Fatalf("unexpected dirty ranges: %v", ranges)
+	} else if r := ranges[0]; r.Measurement != "cpu" || !r.Min.Equal(now) || !r.Max.Equal(now) {
+		t.Fatalf("unexpected dirty range: %v", r)
+	}
+}
+
+// Ensures the points dropped by a shard are added to the points rejected by
+// the measurement schemas.
+func TestPointsWriter_WritePoints_MeasurementSchemaPartial(t *testing.T) {
+	ms := NewPointsWriterMetaClient()
+	ms.DatabaseFn = func(database string) *meta.DatabaseInfo {
+		return &meta.DatabaseInfo{
+			Name: database,
+			MeasurementSchemas: []meta.MeasurementSchemaInfo{{
+				Name: "cpu",
+				Tags: []meta.MeasurementSchemaTagInfo{{Key: "host", Required: true}},
+			}},
+		}
+	}
+	ms.NodeIDFn = func() uint64 { return 1 }
+
+	store := &fakeStore{
+		WriteFn: func(shardID uint64, points []models.Point) error {
+			return tsdb.PartialWriteError{Reason: "field type conflict", Dropped: 1}
+		},
+	}
+
+	c := coordinator.NewPointsWriter()
+	c.MetaClient = ms
+	c.TSDBStore = store
+	c.Node = &influxdb.Node{ID: 1}
+	c.Open()
+	defer c.Close()
+
+	pr := &coordinator.WritePointsRequest{Database: "mydb", RetentionPolicy: "myrp"}
+	pr.AddPoint("cpu", 1.0, time.Now(), map[string]string{"host": "server01"})
+	pr.AddPoint("cpu", 2.0, time.Now(), nil)
+	pr.AddPoint("cpu", 3.0, time.Now(), map[string]string{"host": "server02"})
+
+	err := c.WritePointsPrivileged(pr.Database, pr.RetentionPolicy, models.ConsistencyLevelOne, pr.Points)
+	if perr, ok := err.(tsdb.PartialWriteError); !ok {
+		t.Fatalf("unexpected error: got %v, exp %v", err, tsdb.PartialWriteError{})
+	} else if perr.Dropped != 2 {
+		t.Fatalf("unexpected dropped: got %d, exp 2", perr.Dropped)
+	} else if exp := `measurement schema violation: point 2 (cpu): required tag key "host" is missing`; perr.Reason != exp {
+		t.Fatalf("unexpected reason:\ngot %s\nexp %s", perr.Reason, exp)
+	}
+}
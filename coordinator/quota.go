@@ -0,0 +1,126 @@
+package coordinator
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ayang64/reflux/tsdb"
+)
+
+// writeRate counts the points written to a database in one second windows.
+// A write larger than the quota is admitted into a window that is otherwise
+// empty, and the points over the quota are carried into the following
+// windows, so that large batches are slowed down rather than never written.
+type writeRate struct {
+	mu     sync.Mutex
+	window time.Time // start of the current window
+	used   int64     // points counted against the current window
+	max    int64     // the points allowed per window
+	n      int64     // points written in the current window
+	prev   int64     // points written in the window before it
+}
+
+// advance moves the rate to the window of now.
+func (r *writeRate) advance(now time.Time) {
+	window := now.Truncate(time.Second)
+	if window.Equal(r.window) {
+		return
+	}
+
+	// Each window that passed takes up to max of the carried points. The
+	// elapsed windows are compared by division so that they cannot overflow.
+	elapsed := int64(window.Sub(r.window) / time.Second)
+	if elapsed > 0 && r.max > 0 && elapsed <= (r.used-1)/r.max {
+		r.used -= elapsed * r.max
+	} else {
+		r.used = 0
+	}
+
+	if elapsed == 1 {
+		r.prev = r.n
+	} else {
+		r.prev = 0
+	}
+	r.window, r.n = window, 0
+}
+
+// reserve adds n points to the window of now unless it would exceed max. The
+// points are always added to a window that has none counted against it.
+func (r *writeRate) reserve(now time.Time, n, max int64) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.advance(now)
+	r.max = max
+	if r.used > 0 && r.used+n > max {
+		return false
+	}
+	r.used += n
+	r.n += n
+	return true
+}
+
+// rate returns the number of points written in the last complete window
+// before now.
+func (r *writeRate) rate(now time.Time) int64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.advance(now)
+	return r.prev
+}
+
+// checkQuota checks a write of n points against the write rate quota of a
+// database and returns the quota the shards enforce. Writes that would exceed
+// the points per second quota are rejected as a whole, unless nothing else
+// counts against the current second.
+func (w *PointsWriter) checkQuota(database string, n int) (*tsdb.Quota, error) {
+	di := w.MetaClient.Database(database)
+	if di == nil || di.Quota == nil {
+		return nil, nil
+	}
+	q := di.Quota
+
+	if q.MaxPointsPerSecond > 0 && !w.writeRate(database).reserve(time.Now(), int64(n), q.MaxPointsPerSecond) {
+		atomic.AddInt64(&w.stats.WriteQuotaExceeded, 1)
+		return nil, tsdb.QuotaExceededError{Database: database, Resource: tsdb.QuotaPointsPerSecond, Limit: q.MaxPointsPerSecond}
+	}
+
+	if q.MaxDiskBytes == 0 && q.MaxSeries == 0 {
+		return nil, nil
+	}
+	return &tsdb.Quota{MaxDiskBytes: q.MaxDiskBytes, MaxSeries: q.MaxSeries}, nil
+}
+
+// writeRate returns the write rate of a database.
+func (w *PointsWriter) writeRate(database string) *writeRate {
+	w.rateMu.RLock()
+	r := w.rates[database]
+	w.rateMu.RUnlock()
+	if r != nil {
+		return r
+	}
+
+	w.rateMu.Lock()
+	defer w.rateMu.Unlock()
+	if r = w.rates[database]; r == nil {
+		if w.rates == nil {
+			w.rates = make(map[string]*writeRate)
+		}
+		r = &writeRate{}
+		w.rates[database] = r
+	}
+	return r
+}
+
+// PointsPerSecond returns the number of points written to a database in the
+// last second. Only the writes to databases with a points per second quota
+// are counted.
+func (w *PointsWriter) PointsPerSecond(database string) int64 {
+	w.rateMu.RLock()
+	r := w.rates[database]
+	w.rateMu.RUnlock()
+	if r == nil {
+		return 0
+	}
+	return r.rate(time.Now())
+}
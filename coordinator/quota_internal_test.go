@@ -0,0 +1,36 @@
+package coordinator
+
+import (
+	"testing"
+	"time"
+)
+
+// Ensures a write larger than the quota is admitted into an empty window and
+// that its excess delays the following writes.
+func TestWriteRate_Reserve(t *testing.T) {
+	var r writeRate
+	now := time.Unix(100, 0)
+
+	for i, tt := range []struct {
+		offset time.Duration
+		n      int64
+		exp    bool
+	}{
+		{offset: 0, n: 5, exp: true},
+		{offset: 0, n: 1, exp: false},
+		{offset: time.Second, n: 1, exp: false},
+		{offset: 2 * time.Second, n: 1, exp: true},
+		{offset: 2 * time.Second, n: 1, exp: false},
+		{offset: 4 * time.Second, n: 2, exp: true},
+		{offset: 4 * time.Second, n: 1, exp: false},
+		{offset: 10 * time.Second, n: 3, exp: true},
+	} {
+		if got := r.reserve(now.Add(tt.offset), tt.n, 2); got != tt.exp {
+			t.Fatalf("%d. unexpected reservation of %d points: got %v, exp %v", i, tt.n, got, tt.exp)
+		}
+	}
+
+	if got := r.rate(now.Add(11 * time.Second)); got != 3 {
+		t.Fatalf("unexpected rate: got %d, exp 3", got)
+	}
+}
@@ -78,6 +78,10 @@ func (e *LocalShardMapper) mapShards(a *LocalShardMapping, sources influxql.Sour
 			if err := e.mapShards(a, s.Statement.Sources, tmin, tmax); err != nil {
 				return err
 			}
+		case *influxql.Join:
+			if err := e.mapShards(a, influxql.Sources{s.Left, s.Right}, tmin, tmax); err != nil {
+				return err
+			}
 		}
 	}
 	return nil
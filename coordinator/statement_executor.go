@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"regexp"
 	"sort"
 	"strconv"
 	"strings"
@@ -17,7 +18,9 @@ import (
 	"github.com/ayang64/reflux/pkg/tracing"
 	"github.com/ayang64/reflux/pkg/tracing/fields"
 	"github.com/ayang64/reflux/query"
+	"github.com/ayang64/reflux/services/audit"
 	"github.com/ayang64/reflux/services/meta"
+	"github.com/ayang64/reflux/task"
 	"github.com/ayang64/reflux/tsdb"
 	"github.com/ayang64/reflux/influxql"
 )
@@ -26,6 +29,9 @@ import (
 // when a database has not been provided.
 var ErrDatabaseNameRequired = errors.New("database name required")
 
+// ErrAuditLogDisabled is returned when listing the audit log while it is disabled.
+var ErrAuditLogDisabled = errors.New("audit log is disabled")
+
 type pointsWriter interface {
 	WritePointsInto(*IntoWriteRequest) error
 }
@@ -37,6 +43,19 @@ type StatementExecutor struct {
 	// TaskManager holds the StatementExecutor that handles task-related commands.
 	TaskManager query.StatementExecutor
 
+	// Tasks tracks long-running background work for SHOW TASKS and KILL TASK.
+	Tasks *task.Manager
+
+	// ContinuousQuerier runs the windows of BACKFILL CONTINUOUS QUERY and
+	// reports the runs of continuous queries not saved in meta yet.
+	ContinuousQuerier ContinuousQuerier
+
+	// Subscriber reports the queues of subscriptions for SHOW SUBSCRIPTIONS.
+	Subscriber SubscriptionQueues
+
+	// Quotas reports the write rates and running queries for SHOW QUOTAS.
+	Quotas QuotaUsage
+
 	// TSDB storage for local node.
 	TSDBStore TSDBStore
 
@@ -51,28 +70,64 @@ type StatementExecutor struct {
 		WritePointsInto(*IntoWriteRequest) error
 	}
 
+	// Records every statement other than SELECT, and SELECT INTO statements,
+	// and serves SHOW AUDIT LOG.
+	AuditLog AuditLog
+
 	// Select statement limits
 	MaxSelectPointN   int
 	MaxSelectSeriesN  int
 	MaxSelectBucketsN int
+	MaxJoinPointN     int
 }
 
 // ExecuteStatement executes the given statement with the given execution context.
 func (e *StatementExecutor) ExecuteStatement(stmt influxql.Statement, ctx *query.ExecutionContext) error {
-	// Select statements are handled separately so that they can be streamed.
-	if stmt, ok := stmt.(*influxql.SelectStatement); ok {
-		return e.executeSelectStatement(stmt, ctx)
+	var err error
+	if s, ok := stmt.(*influxql.SelectStatement); ok {
+		// Select statements are handled separately so that they can be streamed.
+		err = e.executeSelectStatement(s, ctx)
+	} else {
+		err = e.executeStatement(stmt, ctx)
+	}
+
+	if e.AuditLog != nil && audit.Audited(stmt) {
+		ev := audit.Event{
+			Action:    audit.ActionStatement,
+			Source:    ctx.ClientAddr,
+			Database:  ctx.Database,
+			Statement: stmt.String(),
+		}
+		if u, ok := ctx.Authorizer.(meta.User); ok && u != nil {
+			ev.User = u.ID()
+		}
+		ev.Outcome, ev.Error = audit.Outcome(err)
+		e.AuditLog.Record(ev)
 	}
+	return err
+}
+
+func (e *StatementExecutor) executeStatement(stmt influxql.Statement, ctx *query.ExecutionContext) error {
 
 	var rows models.Rows
 	var messages []*query.Message
 	var err error
 	switch stmt := stmt.(type) {
+	case *influxql.AlterFieldTypeStatement:
+		if ctx.ReadOnly {
+			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
+		}
+		err = e.executeAlterFieldTypeStatement(stmt, ctx.Database)
 	case *influxql.AlterRetentionPolicyStatement:
 		if ctx.ReadOnly {
 			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
 		}
 		err = e.executeAlterRetentionPolicyStatement(stmt)
+	case *influxql.BackfillContinuousQueryStatement:
+		if ctx.ReadOnly {
+			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
+		}
+		rows, err = e.executeBackfillContinuousQueryStatement(stmt, ctx)
 	case *influxql.CreateContinuousQueryStatement:
 		if ctx.ReadOnly {
 			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
@@ -83,23 +138,48 @@ func (e *StatementExecutor) ExecuteStatement(stmt influxql.Statement, ctx *query
 			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
 		}
 		err = e.executeCreateDatabaseStatement(stmt)
+	case *influxql.CreateDownsamplePolicyStatement:
+		if ctx.ReadOnly {
+			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
+		}
+		err = e.executeCreateDownsamplePolicyStatement(stmt)
+	case *influxql.CreateMeasurementSchemaStatement:
+		if ctx.ReadOnly {
+			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
+		}
+		err = e.executeCreateMeasurementSchemaStatement(stmt)
 	case *influxql.CreateRetentionPolicyStatement:
 		if ctx.ReadOnly {
 			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
 		}
 		err = e.executeCreateRetentionPolicyStatement(stmt)
+	case *influxql.CreateRoleStatement:
+		if ctx.ReadOnly {
+			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
+		}
+		err = e.executeCreateRoleStatement(stmt)
+	case *influxql.CreateSecurityPolicyStatement:
+		if ctx.ReadOnly {
+			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
+		}
+		err = e.executeCreateSecurityPolicyStatement(stmt)
 	case *influxql.CreateSubscriptionStatement:
 		if ctx.ReadOnly {
 			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
 		}
 		err = e.executeCreateSubscriptionStatement(stmt)
+	case *influxql.CreateTokenStatement:
+		if ctx.ReadOnly {
+			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
+		}
+		rows, err = e.executeCreateTokenStatement(stmt)
 	case *influxql.CreateUserStatement:
 		if ctx.ReadOnly {
 			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
 		}
 		err = e.executeCreateUserStatement(stmt)
 	case *influxql.DeleteSeriesStatement:
-		err = e.executeDeleteSeriesStatement(stmt, ctx.Database)
+		err = e.executeDeleteSeriesStatement(ctx, stmt, ctx.Database)
 	case *influxql.DropContinuousQueryStatement:
 		if ctx.ReadOnly {
 			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
@@ -110,6 +190,26 @@ func (e *StatementExecutor) ExecuteStatement(stmt influxql.Statement, ctx *query
 			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
 		}
 		err = e.executeDropDatabaseStatement(stmt)
+	case *influxql.DropDownsamplePolicyStatement:
+		if ctx.ReadOnly {
+			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
+		}
+		err = e.executeDropDownsamplePolicyStatement(stmt)
+	case *influxql.DropFieldStatement:
+		if ctx.ReadOnly {
+			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
+		}
+		err = e.executeDropFieldStatement(stmt, ctx.Database)
+	case *influxql.DropMeasurementSchemaStatement:
+		if ctx.ReadOnly {
+			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
+		}
+		err = e.executeDropMeasurementSchemaStatement(stmt)
+	case *influxql.DropQuotaStatement:
+		if ctx.ReadOnly {
+			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
+		}
+		err = e.executeDropQuotaStatement(stmt)
 	case *influxql.DropMeasurementStatement:
 		if ctx.ReadOnly {
 			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
@@ -119,12 +219,22 @@ func (e *StatementExecutor) ExecuteStatement(stmt influxql.Statement, ctx *query
 		if ctx.ReadOnly {
 			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
 		}
-		err = e.executeDropSeriesStatement(stmt, ctx.Database)
+		err = e.executeDropSeriesStatement(ctx, stmt, ctx.Database)
 	case *influxql.DropRetentionPolicyStatement:
 		if ctx.ReadOnly {
 			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
 		}
 		err = e.executeDropRetentionPolicyStatement(stmt)
+	case *influxql.DropRoleStatement:
+		if ctx.ReadOnly {
+			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
+		}
+		err = e.executeDropRoleStatement(stmt)
+	case *influxql.DropSecurityPolicyStatement:
+		if ctx.ReadOnly {
+			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
+		}
+		err = e.executeDropSecurityPolicyStatement(stmt)
 	case *influxql.DropShardStatement:
 		if ctx.ReadOnly {
 			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
@@ -156,6 +266,16 @@ func (e *StatementExecutor) ExecuteStatement(stmt influxql.Statement, ctx *query
 			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
 		}
 		err = e.executeGrantAdminStatement(stmt)
+	case *influxql.GrantRoleStatement:
+		if ctx.ReadOnly {
+			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
+		}
+		err = e.executeGrantRoleStatement(stmt)
+	case *influxql.KillTaskStatement:
+		if ctx.ReadOnly {
+			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
+		}
+		err = e.executeKillTaskStatement(stmt)
 	case *influxql.RevokeStatement:
 		if ctx.ReadOnly {
 			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
@@ -166,20 +286,42 @@ func (e *StatementExecutor) ExecuteStatement(stmt influxql.Statement, ctx *query
 			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
 		}
 		err = e.executeRevokeAdminStatement(stmt)
+	case *influxql.RevokeRoleStatement:
+		if ctx.ReadOnly {
+			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
+		}
+		err = e.executeRevokeRoleStatement(stmt)
+	case *influxql.RevokeTokenStatement:
+		if ctx.ReadOnly {
+			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
+		}
+		err = e.executeRevokeTokenStatement(stmt)
+	case *influxql.ShowAuditLogStatement:
+		rows, err = e.executeShowAuditLogStatement(stmt)
 	case *influxql.ShowContinuousQueriesStatement:
 		rows, err = e.executeShowContinuousQueriesStatement(stmt)
 	case *influxql.ShowDatabasesStatement:
 		rows, err = e.executeShowDatabasesStatement(stmt, ctx)
 	case *influxql.ShowDiagnosticsStatement:
 		rows, err = e.executeShowDiagnosticsStatement(stmt)
+	case *influxql.ShowDownsamplePoliciesStatement:
+		rows, err = e.executeShowDownsamplePoliciesStatement(stmt)
 	case *influxql.ShowGrantsForUserStatement:
 		rows, err = e.executeShowGrantsForUserStatement(stmt)
+	case *influxql.ShowMeasurementSchemasStatement:
+		rows, err = e.executeShowMeasurementSchemasStatement(stmt)
 	case *influxql.ShowMeasurementsStatement:
 		return e.executeShowMeasurementsStatement(stmt, ctx)
 	case *influxql.ShowMeasurementCardinalityStatement:
 		rows, err = e.executeShowMeasurementCardinalityStatement(stmt)
+	case *influxql.ShowQuotasStatement:
+		rows, err = e.executeShowQuotasStatement(stmt)
 	case *influxql.ShowRetentionPoliciesStatement:
 		rows, err = e.executeShowRetentionPoliciesStatement(stmt)
+	case *influxql.ShowRolesStatement:
+		rows, err = e.executeShowRolesStatement(stmt)
+	case *influxql.ShowSecurityPoliciesStatement:
+		rows, err = e.executeShowSecurityPoliciesStatement(stmt)
 	case *influxql.ShowSeriesCardinalityStatement:
 		rows, err = e.executeShowSeriesCardinalityStatement(stmt)
 	case *influxql.ShowShardsStatement:
@@ -190,10 +332,14 @@ func (e *StatementExecutor) ExecuteStatement(stmt influxql.Statement, ctx *query
 		rows, err = e.executeShowStatsStatement(stmt)
 	case *influxql.ShowSubscriptionsStatement:
 		rows, err = e.executeShowSubscriptionsStatement(stmt)
+	case *influxql.ShowTasksStatement:
+		rows, err = e.executeShowTasksStatement(stmt)
 	case *influxql.ShowTagKeysStatement:
 		return e.executeShowTagKeys(stmt, ctx)
 	case *influxql.ShowTagValuesStatement:
 		return e.executeShowTagValues(stmt, ctx)
+	case *influxql.ShowTokensStatement:
+		rows, err = e.executeShowTokensStatement(stmt)
 	case *influxql.ShowUsersStatement:
 		rows, err = e.executeShowUsersStatement(stmt)
 	case *influxql.SetPasswordUserStatement:
@@ -201,6 +347,11 @@ func (e *StatementExecutor) ExecuteStatement(stmt influxql.Statement, ctx *query
 			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
 		}
 		err = e.executeSetPasswordUserStatement(stmt)
+	case *influxql.SetQuotaStatement:
+		if ctx.ReadOnly {
+			messages = append(messages, query.ReadOnlyWarning(stmt.String()))
+		}
+		err = e.executeSetQuotaStatement(stmt)
 	case *influxql.ShowQueriesStatement, *influxql.KillQueryStatement:
 		// Send query related statements to the task manager.
 		return e.TaskManager.ExecuteStatement(stmt, ctx)
@@ -218,17 +369,48 @@ func (e *StatementExecutor) ExecuteStatement(stmt influxql.Statement, ctx *query
 	})
 }
 
+func (e *StatementExecutor) executeAlterFieldTypeStatement(stmt *influxql.AlterFieldTypeStatement, database string) error {
+	if dbi := e.MetaClient.Database(database); dbi == nil {
+		return query.ErrDatabaseNotFound(database)
+	}
+
+	// Locally rewrite the field as the new type.
+	return e.TSDBStore.ConvertField(database, stmt.Measurement, stmt.Name, stmt.Type)
+}
+
 func (e *StatementExecutor) executeAlterRetentionPolicyStatement(stmt *influxql.AlterRetentionPolicyStatement) error {
 	rpu := &meta.RetentionPolicyUpdate{
 		Duration:           stmt.Duration,
 		ReplicaN:           stmt.Replication,
 		ShardGroupDuration: stmt.ShardGroupDuration,
+		FieldTypePolicy:    stmt.FieldTypePolicy,
+	}
+	if rpu.FieldTypePolicy != nil {
+		if _, err := tsdb.ParseFieldTypePolicy(*rpu.FieldTypePolicy); err != nil {
+			return err
+		}
 	}
 
 	// Update the retention policy.
 	return e.MetaClient.UpdateRetentionPolicy(stmt.Database, stmt.Name, rpu, stmt.Default)
 }
 
+func (e *StatementExecutor) executeBackfillContinuousQueryStatement(q *influxql.BackfillContinuousQueryStatement, ctx *query.ExecutionContext) (models.Rows, error) {
+	if e.ContinuousQuerier == nil {
+		return nil, errors.New("continuous query service is not enabled")
+	}
+
+	windows, written, err := e.ContinuousQuerier.Backfill(ctx, q.Database, q.Name, q.StartTime, q.EndTime)
+	if err != nil {
+		return nil, err
+	}
+	return []*models.Row{{
+		Name:    "result",
+		Columns: []string{"time", "windows", "written"},
+		Values:  [][]interface{}{{time.Unix(0, 0).UTC(), windows, written}},
+	}}, nil
+}
+
 func (e *StatementExecutor) executeCreateContinuousQueryStatement(q *influxql.CreateContinuousQueryStatement) error {
 	// Verify that retention policies exist.
 	var err error
@@ -258,6 +440,34 @@ func (e *StatementExecutor) executeCreateContinuousQueryStatement(q *influxql.Cr
 	return e.MetaClient.CreateContinuousQuery(q.Database, q.Name, q.String())
 }
 
+func (e *StatementExecutor) executeCreateDownsamplePolicyStatement(stmt *influxql.CreateDownsamplePolicyStatement) error {
+	return e.MetaClient.CreateDownsamplePolicy(stmt.Database, meta.DownsamplePolicyInfo{
+		Name:                  stmt.Name,
+		SourceRetentionPolicy: stmt.SourceRetentionPolicy,
+		TargetRetentionPolicy: stmt.TargetRetentionPolicy,
+		Interval:              stmt.Interval,
+		Functions:             stmt.Functions,
+	})
+}
+
+func (e *StatementExecutor) executeCreateMeasurementSchemaStatement(stmt *influxql.CreateMeasurementSchemaStatement) error {
+	msi := meta.MeasurementSchemaInfo{
+		Name:     stmt.Name,
+		WarnOnly: stmt.WarnOnly,
+	}
+	for _, f := range stmt.Fields {
+		msi.Fields = append(msi.Fields, meta.MeasurementSchemaFieldInfo{Name: f.Name, Type: f.Type})
+	}
+	for _, t := range stmt.Tags {
+		ti := meta.MeasurementSchemaTagInfo{Key: t.Key, Required: t.Required, MaxValues: t.Limit}
+		if t.Regex != nil {
+			ti.Regex = t.Regex.Val.String()
+		}
+		msi.Tags = append(msi.Tags, ti)
+	}
+	return e.MetaClient.CreateMeasurementSchema(stmt.Database, msi)
+}
+
 func (e *StatementExecutor) executeCreateDatabaseStatement(stmt *influxql.CreateDatabaseStatement) error {
 	if !meta.ValidName(stmt.Name) {
 		// TODO This should probably be in `(*meta.Data).CreateDatabase`
@@ -300,6 +510,10 @@ func (e *StatementExecutor) executeCreateRetentionPolicyStatement(stmt *influxql
 		Duration:           &stmt.Duration,
 		ReplicaN:           &stmt.Replication,
 		ShardGroupDuration: stmt.ShardGroupDuration,
+		FieldTypePolicy:    stmt.FieldTypePolicy,
+	}
+	if _, err := tsdb.ParseFieldTypePolicy(spec.FieldTypePolicy); err != nil {
+		return err
 	}
 
 	// Create new retention policy.
@@ -308,7 +522,11 @@ func (e *StatementExecutor) executeCreateRetentionPolicyStatement(stmt *influxql
 }
 
 func (e *StatementExecutor) executeCreateSubscriptionStatement(q *influxql.CreateSubscriptionStatement) error {
-	return e.MetaClient.CreateSubscription(q.Database, q.RetentionPolicy, q.Name, q.Mode, q.Destinations)
+	var condition string
+	if q.Condition != nil {
+		condition = q.Condition.String()
+	}
+	return e.MetaClient.CreateSubscriptionWithFilter(q.Database, q.RetentionPolicy, q.Name, q.Mode, q.Destinations, q.Fields, condition)
 }
 
 func (e *StatementExecutor) executeCreateUserStatement(q *influxql.CreateUserStatement) error {
@@ -316,7 +534,7 @@ func (e *StatementExecutor) executeCreateUserStatement(q *influxql.CreateUserSta
 	return err
 }
 
-func (e *StatementExecutor) executeDeleteSeriesStatement(stmt *influxql.DeleteSeriesStatement, database string) error {
+func (e *StatementExecutor) executeDeleteSeriesStatement(ctx context.Context, stmt *influxql.DeleteSeriesStatement, database string) error {
 	if dbi := e.MetaClient.Database(database); dbi == nil {
 		return query.ErrDatabaseNotFound(database)
 	}
@@ -325,13 +543,34 @@ func (e *StatementExecutor) executeDeleteSeriesStatement(stmt *influxql.DeleteSe
 	stmt.Condition = influxql.Reduce(stmt.Condition, &influxql.NowValuer{Now: time.Now().UTC()})
 
 	// Locally delete the series.
-	return e.TSDBStore.DeleteSeries(database, stmt.Sources, stmt.Condition)
+	return e.deleteSeries(ctx, stmt.String(), database, stmt.Sources, stmt.Condition)
+}
+
+// deleteSeries deletes series from the local store as a task so that the
+// deletion can be listed and killed.
+func (e *StatementExecutor) deleteSeries(ctx context.Context, name, database string, sources []influxql.Source, condition influxql.Expr) error {
+	name = fmt.Sprintf("%s ON %s", name, influxql.QuoteIdent(database))
+	return e.Tasks.Run(ctx, name, func(ctx context.Context) error {
+		return e.TSDBStore.DeleteSeriesContext(ctx, database, sources, condition)
+	})
 }
 
 func (e *StatementExecutor) executeDropContinuousQueryStatement(q *influxql.DropContinuousQueryStatement) error {
 	return e.MetaClient.DropContinuousQuery(q.Database, q.Name)
 }
 
+func (e *StatementExecutor) executeDropMeasurementSchemaStatement(stmt *influxql.DropMeasurementSchemaStatement) error {
+	return e.MetaClient.DropMeasurementSchema(stmt.Database, stmt.Name)
+}
+
+func (e *StatementExecutor) executeDropQuotaStatement(stmt *influxql.DropQuotaStatement) error {
+	return e.MetaClient.DropQuota(stmt.Database)
+}
+
+func (e *StatementExecutor) executeDropDownsamplePolicyStatement(stmt *influxql.DropDownsamplePolicyStatement) error {
+	return e.MetaClient.DropDownsamplePolicy(stmt.Database, stmt.Name)
+}
+
 // executeDropDatabaseStatement drops a database from the cluster.
 // It does not return an error if the database was not found on any of
 // the nodes, or in the Meta store.
@@ -349,6 +588,15 @@ func (e *StatementExecutor) executeDropDatabaseStatement(stmt *influxql.DropData
 	return e.MetaClient.DropDatabase(stmt.Name)
 }
 
+func (e *StatementExecutor) executeDropFieldStatement(stmt *influxql.DropFieldStatement, database string) error {
+	if dbi := e.MetaClient.Database(database); dbi == nil {
+		return query.ErrDatabaseNotFound(database)
+	}
+
+	// Locally drop the field
+	return e.TSDBStore.DeleteField(database, stmt.Measurement, stmt.Name)
+}
+
 func (e *StatementExecutor) executeDropMeasurementStatement(stmt *influxql.DropMeasurementStatement, database string) error {
 	if dbi := e.MetaClient.Database(database); dbi == nil {
 		return query.ErrDatabaseNotFound(database)
@@ -358,7 +606,7 @@ func (e *StatementExecutor) executeDropMeasurementStatement(stmt *influxql.DropM
 	return e.TSDBStore.DeleteMeasurement(database, stmt.Name)
 }
 
-func (e *StatementExecutor) executeDropSeriesStatement(stmt *influxql.DropSeriesStatement, database string) error {
+func (e *StatementExecutor) executeDropSeriesStatement(ctx context.Context, stmt *influxql.DropSeriesStatement, database string) error {
 	if dbi := e.MetaClient.Database(database); dbi == nil {
 		return query.ErrDatabaseNotFound(database)
 	}
@@ -369,7 +617,7 @@ func (e *StatementExecutor) executeDropSeriesStatement(stmt *influxql.DropSeries
 	}
 
 	// Locally drop the series.
-	return e.TSDBStore.DeleteSeries(database, stmt.Sources, stmt.Condition)
+	return e.deleteSeries(ctx, stmt.String(), database, stmt.Sources, stmt.Condition)
 }
 
 func (e *StatementExecutor) executeDropShardStatement(stmt *influxql.DropShardStatement) error {
@@ -408,6 +656,55 @@ func (e *StatementExecutor) executeDropUserStatement(q *influxql.DropUserStateme
 	return e.MetaClient.DropUser(q.Name)
 }
 
+func (e *StatementExecutor) executeCreateRoleStatement(q *influxql.CreateRoleStatement) error {
+	return e.MetaClient.CreateRole(q.Name)
+}
+
+func (e *StatementExecutor) executeDropRoleStatement(q *influxql.DropRoleStatement) error {
+	return e.MetaClient.DropRole(q.Name)
+}
+
+func (e *StatementExecutor) executeCreateSecurityPolicyStatement(q *influxql.CreateSecurityPolicyStatement) error {
+	return e.MetaClient.CreateSecurityPolicy(meta.SecurityPolicyInfo{
+		Database:  q.Database,
+		User:      q.User,
+		Role:      q.Role,
+		Condition: q.Condition.String(),
+	})
+}
+
+func (e *StatementExecutor) executeDropSecurityPolicyStatement(q *influxql.DropSecurityPolicyStatement) error {
+	return e.MetaClient.DropSecurityPolicy(q.Database, q.User, q.Role)
+}
+
+func (e *StatementExecutor) executeCreateTokenStatement(q *influxql.CreateTokenStatement) (models.Rows, error) {
+	var privileges map[string]influxql.Privilege
+	for _, p := range q.Privileges {
+		if privileges == nil {
+			privileges = make(map[string]influxql.Privilege)
+		}
+		privileges[p.Database] |= p.Privilege
+	}
+
+	var expiresAt time.Time
+	if q.Duration > 0 {
+		expiresAt = time.Now().UTC().Add(q.Duration)
+	}
+
+	ti, token, err := e.MetaClient.CreateToken(q.User, q.Description, privileges, expiresAt)
+	if err != nil {
+		return nil, err
+	}
+	return []*models.Row{{
+		Columns: []string{"id", "token"},
+		Values:  [][]interface{}{{ti.ID, token}},
+	}}, nil
+}
+
+func (e *StatementExecutor) executeRevokeTokenStatement(q *influxql.RevokeTokenStatement) error {
+	return e.MetaClient.RevokeToken(q.ID)
+}
+
 func (e *StatementExecutor) executeExplainStatement(q *influxql.ExplainStatement, ctx *query.ExecutionContext) (models.Rows, error) {
 	opt := query.SelectOptions{
 		NodeID:      ctx.ExecutionOptions.NodeID,
@@ -507,14 +804,45 @@ CLEANUP:
 }
 
 func (e *StatementExecutor) executeGrantStatement(stmt *influxql.GrantStatement) error {
+	if stmt.Role != "" {
+		return e.MetaClient.GrantRolePrivilege(stmt.Role, rolePrivilege(stmt.Privilege, stmt.On, stmt.Measurement))
+	}
 	return e.MetaClient.SetPrivilege(stmt.User, stmt.On, stmt.Privilege)
 }
 
+func (e *StatementExecutor) executeGrantRoleStatement(stmt *influxql.GrantRoleStatement) error {
+	return e.MetaClient.GrantRole(stmt.Role, stmt.User)
+}
+
+// rolePrivilege returns the privilege of a grant or a revoke statement for a role.
+func rolePrivilege(priv influxql.Privilege, database string, m *influxql.Measurement) meta.PrivilegeInfo {
+	p := meta.PrivilegeInfo{Database: database, Privilege: priv}
+	if m != nil && m.Regex != nil {
+		p.Measurement, p.Regex = m.Regex.Val.String(), true
+	} else if m != nil {
+		p.Measurement = m.Name
+	}
+	return p
+}
+
+// privilegeMeasurement returns the measurement a privilege is limited to as it
+// is written in a statement, or an empty string.
+func privilegeMeasurement(p meta.PrivilegeInfo) string {
+	if p.Regex {
+		return "/" + p.Measurement + "/"
+	}
+	return p.Measurement
+}
+
 func (e *StatementExecutor) executeGrantAdminStatement(stmt *influxql.GrantAdminStatement) error {
 	return e.MetaClient.SetAdminPrivilege(stmt.User, true)
 }
 
 func (e *StatementExecutor) executeRevokeStatement(stmt *influxql.RevokeStatement) error {
+	if stmt.Role != "" {
+		return e.MetaClient.RevokeRolePrivilege(stmt.Role, rolePrivilege(stmt.Privilege, stmt.On, stmt.Measurement))
+	}
+
 	priv := influxql.NoPrivileges
 
 	// Revoking all privileges means there's no need to look at existing user privileges.
@@ -534,10 +862,23 @@ func (e *StatementExecutor) executeRevokeAdminStatement(stmt *influxql.RevokeAdm
 	return e.MetaClient.SetAdminPrivilege(stmt.User, false)
 }
 
+func (e *StatementExecutor) executeRevokeRoleStatement(stmt *influxql.RevokeRoleStatement) error {
+	return e.MetaClient.RevokeRole(stmt.Role, stmt.User)
+}
+
 func (e *StatementExecutor) executeSetPasswordUserStatement(q *influxql.SetPasswordUserStatement) error {
 	return e.MetaClient.UpdateUser(q.Name, q.Password)
 }
 
+func (e *StatementExecutor) executeSetQuotaStatement(stmt *influxql.SetQuotaStatement) error {
+	return e.MetaClient.SetQuota(stmt.Database, meta.QuotaInfo{
+		MaxDiskBytes:         stmt.MaxDiskBytes,
+		MaxSeries:            stmt.MaxSeries,
+		MaxPointsPerSecond:   stmt.MaxPointsPerSecond,
+		MaxConcurrentQueries: stmt.MaxConcurrentQueries,
+	})
+}
+
 func (e *StatementExecutor) executeSelectStatement(stmt *influxql.SelectStatement, ctx *query.ExecutionContext) error {
 	cur, err := e.createIterators(ctx, stmt, ctx.ExecutionOptions)
 	if err != nil {
@@ -631,6 +972,7 @@ func (e *StatementExecutor) createIterators(ctx context.Context, stmt *influxql.
 		MaxSeriesN:  e.MaxSelectSeriesN,
 		MaxPointN:   e.MaxSelectPointN,
 		MaxBucketsN: e.MaxSelectBucketsN,
+		MaxJoinN:    e.MaxJoinPointN,
 		Authorizer:  opt.Authorizer,
 	}
 
@@ -647,9 +989,24 @@ func (e *StatementExecutor) executeShowContinuousQueriesStatement(stmt *influxql
 
 	rows := []*models.Row{}
 	for _, di := range dis {
-		row := &models.Row{Columns: []string{"name", "query"}, Name: di.Name}
+		row := &models.Row{Columns: []string{"name", "query", "last_run", "last_run_duration", "points_written", "last_error"}, Name: di.Name}
 		for _, cqi := range di.ContinuousQueries {
-			row.Values = append(row.Values, []interface{}{cqi.Name, cqi.Query})
+			values := []interface{}{cqi.Name, cqi.Query, nil, nil, nil, nil}
+			run := cqi.LastRun
+			if e.ContinuousQuerier != nil {
+				if r, ok := e.ContinuousQuerier.LastRun(di.Name, cqi.Name); ok {
+					run = r
+				}
+			}
+			if !run.Time.IsZero() {
+				values[2] = run.Time.UTC().Format(time.RFC3339Nano)
+				values[3] = run.Duration.String()
+				values[4] = run.PointsWritten
+				if run.Err != "" {
+					values[5] = run.Err
+				}
+			}
+			row.Values = append(row.Values, values)
 		}
 		rows = append(rows, row)
 	}
@@ -699,14 +1056,14 @@ func (e *StatementExecutor) executeShowDiagnosticsStatement(stmt *influxql.ShowD
 }
 
 func (e *StatementExecutor) executeShowGrantsForUserStatement(q *influxql.ShowGrantsForUserStatement) (models.Rows, error) {
-	priv, err := e.MetaClient.UserPrivileges(q.Name)
+	privileges, err := e.MetaClient.EffectivePrivileges(q.Name)
 	if err != nil {
 		return nil, err
 	}
 
-	row := &models.Row{Columns: []string{"database", "privilege"}}
-	for d, p := range priv {
-		row.Values = append(row.Values, []interface{}{d, p.String()})
+	row := &models.Row{Columns: []string{"database", "privilege", "measurement"}}
+	for _, p := range privileges {
+		row.Values = append(row.Values, []interface{}{p.Database, p.Privilege.String(), privilegeMeasurement(p)})
 	}
 	return []*models.Row{row}, nil
 }
@@ -771,6 +1128,44 @@ func (e *StatementExecutor) executeShowMeasurementCardinalityStatement(stmt *inf
 	}}, nil
 }
 
+func (e *StatementExecutor) executeShowQuotasStatement(stmt *influxql.ShowQuotasStatement) (models.Rows, error) {
+	row := &models.Row{Columns: []string{
+		"database",
+		"max_disk_bytes", "disk_bytes",
+		"max_series", "series",
+		"max_points_per_second", "points_per_second",
+		"max_concurrent_queries", "queries",
+	}}
+	for _, di := range e.MetaClient.Databases() {
+		if di.Quota == nil {
+			continue
+		}
+		q := di.Quota
+
+		diskBytes, err := e.TSDBStore.DatabaseDiskSize(di.Name)
+		if err != nil {
+			return nil, err
+		}
+		series := e.TSDBStore.DatabaseSeriesCount(di.Name)
+
+		// The usage columns the executor cannot report are empty.
+		var pointsPerSecond, queries interface{}
+		if e.Quotas != nil {
+			pointsPerSecond = e.Quotas.PointsPerSecond(di.Name)
+			queries = e.Quotas.DatabaseQueries(di.Name)
+		}
+
+		row.Values = append(row.Values, []interface{}{
+			di.Name,
+			q.MaxDiskBytes, diskBytes,
+			q.MaxSeries, series,
+			q.MaxPointsPerSecond, pointsPerSecond,
+			q.MaxConcurrentQueries, queries,
+		})
+	}
+	return []*models.Row{row}, nil
+}
+
 func (e *StatementExecutor) executeShowRetentionPoliciesStatement(q *influxql.ShowRetentionPoliciesStatement) (models.Rows, error) {
 	if q.Database == "" {
 		return nil, ErrDatabaseNameRequired
@@ -778,12 +1173,67 @@ func (e *StatementExecutor) executeShowRetentionPoliciesStatement(q *influxql.Sh
 
 	di := e.MetaClient.Database(q.Database)
 	if di == nil {
-		return nil, influxdb.ErrDatabaseNotFound(q.Database)
+		return nil, query.ErrDatabaseNotFound(q.Database)
 	}
 
-	row := &models.Row{Columns: []string{"name", "duration", "shardGroupDuration", "replicaN", "default"}}
+	row := &models.Row{Columns: []string{"name", "duration", "shardGroupDuration", "replicaN", "default", "fieldTypePolicy"}}
 	for _, rpi := range di.RetentionPolicies {
-		row.Values = append(row.Values, []interface{}{rpi.Name, rpi.Duration.String(), rpi.ShardGroupDuration.String(), rpi.ReplicaN, di.DefaultRetentionPolicy == rpi.Name})
+		policy, _ := tsdb.ParseFieldTypePolicy(rpi.FieldTypePolicy)
+		row.Values = append(row.Values, []interface{}{rpi.Name, rpi.Duration.String(), rpi.ShardGroupDuration.String(), rpi.ReplicaN, di.DefaultRetentionPolicy == rpi.Name, policy.String()})
+	}
+	return []*models.Row{row}, nil
+}
+
+func (e *StatementExecutor) executeShowDownsamplePoliciesStatement(q *influxql.ShowDownsamplePoliciesStatement) (models.Rows, error) {
+	if q.Database == "" {
+		return nil, ErrDatabaseNameRequired
+	}
+
+	di := e.MetaClient.Database(q.Database)
+	if di == nil {
+		return nil, query.ErrDatabaseNotFound(q.Database)
+	}
+
+	row := &models.Row{Columns: []string{"name", "from", "to", "every", "functions"}}
+	for _, dpi := range di.DownsamplePolicies {
+		functions := dpi.Functions
+		if len(functions) == 0 {
+			functions = influxql.DefaultDownsampleFunctions
+		}
+		row.Values = append(row.Values, []interface{}{dpi.Name, dpi.SourceRetentionPolicy, dpi.TargetRetentionPolicy, dpi.Interval.String(), strings.Join(functions, ",")})
+	}
+	return []*models.Row{row}, nil
+}
+
+func (e *StatementExecutor) executeShowMeasurementSchemasStatement(q *influxql.ShowMeasurementSchemasStatement) (models.Rows, error) {
+	if q.Database == "" {
+		return nil, ErrDatabaseNameRequired
+	}
+
+	di := e.MetaClient.Database(q.Database)
+	if di == nil {
+		return nil, query.ErrDatabaseNotFound(q.Database)
+	}
+
+	row := &models.Row{Columns: []string{"name", "fields", "tags", "mode"}}
+	for _, msi := range di.MeasurementSchemas {
+		fields := make([]string, 0, len(msi.Fields))
+		for _, f := range msi.Fields {
+			fields = append(fields, (&influxql.MeasurementSchemaField{Name: f.Name, Type: f.Type}).String())
+		}
+		tags := make([]string, 0, len(msi.Tags))
+		for _, t := range msi.Tags {
+			tag := &influxql.MeasurementSchemaTag{Key: t.Key, Required: t.Required, Limit: t.MaxValues}
+			if t.Regex != "" {
+				tag.Regex = &influxql.RegexLiteral{Val: regexp.MustCompile(t.Regex)}
+			}
+			tags = append(tags, tag.String())
+		}
+		mode := "enforce"
+		if msi.WarnOnly {
+			mode = "warn"
+		}
+		row.Values = append(row.Values, []interface{}{msi.Name, strings.Join(fields, ", "), strings.Join(tags, ", "), mode})
 	}
 	return []*models.Row{row}, nil
 }
@@ -908,15 +1358,55 @@ func (e *StatementExecutor) executeShowStatsStatement(stmt *influxql.ShowStatsSt
 	return rows, nil
 }
 
+func (e *StatementExecutor) executeKillTaskStatement(stmt *influxql.KillTaskStatement) error {
+	return e.Tasks.Kill(stmt.TaskID)
+}
+
+func (e *StatementExecutor) executeShowTasksStatement(stmt *influxql.ShowTasksStatement) (models.Rows, error) {
+	now := time.Now()
+
+	tasks := e.Tasks.Tasks()
+	values := make([][]interface{}, 0, len(tasks))
+	for _, t := range tasks {
+		d := now.Sub(t.Start)
+
+		switch {
+		case d >= time.Second:
+			d = d - (d % time.Second)
+		case d >= time.Millisecond:
+			d = d - (d % time.Millisecond)
+		case d >= time.Microsecond:
+			d = d - (d % time.Microsecond)
+		}
+
+		values = append(values, []interface{}{t.ID, t.Name, d.String()})
+	}
+
+	return []*models.Row{{
+		Columns: []string{"id", "name", "duration"},
+		Values:  values,
+	}}, nil
+}
+
 func (e *StatementExecutor) executeShowSubscriptionsStatement(stmt *influxql.ShowSubscriptionsStatement) (models.Rows, error) {
 	dis := e.MetaClient.Databases()
 
 	rows := []*models.Row{}
 	for _, di := range dis {
-		row := &models.Row{Columns: []string{"retention_policy", "name", "mode", "destinations"}, Name: di.Name}
+		row := &models.Row{Columns: []string{"retention_policy", "name", "mode", "destinations", "queue_depth", "oldest_undelivered"}, Name: di.Name}
 		for _, rpi := range di.RetentionPolicies {
 			for _, si := range rpi.Subscriptions {
-				row.Values = append(row.Values, []interface{}{rpi.Name, si.Name, si.Mode, si.Destinations})
+				// The queue columns are empty for subscriptions without a queue.
+				var depth, oldest interface{}
+				if e.Subscriber != nil {
+					if n, t, ok := e.Subscriber.QueueStatus(di.Name, rpi.Name, si.Name); ok {
+						depth = n
+						if !t.IsZero() {
+							oldest = t.UTC().Format(time.RFC3339Nano)
+						}
+					}
+				}
+				row.Values = append(row.Values, []interface{}{rpi.Name, si.Name, si.Mode, si.Destinations, depth, oldest})
 			}
 		}
 		if len(row.Values) > 0 {
@@ -1101,6 +1591,104 @@ func (e *StatementExecutor) executeShowTagValues(q *influxql.ShowTagValuesStatem
 	return nil
 }
 
+func (e *StatementExecutor) executeShowRolesStatement(q *influxql.ShowRolesStatement) (models.Rows, error) {
+	users := make(map[string][]string)
+	for _, ui := range e.MetaClient.Users() {
+		for _, r := range ui.Roles {
+			users[r] = append(users[r], ui.Name)
+		}
+	}
+
+	row := &models.Row{Columns: []string{"name", "privileges", "users"}}
+	for _, ri := range e.MetaClient.Roles() {
+		privileges := make([]string, 0, len(ri.Privileges))
+		for _, p := range ri.Privileges {
+			s := p.Privilege.String() + " ON " + influxql.QuoteIdent(p.Database)
+			if p.Measurement != "" {
+				s += " MEASUREMENT " + privilegeMeasurement(p)
+			}
+			privileges = append(privileges, s)
+		}
+		row.Values = append(row.Values, []interface{}{ri.Name, privileges, users[ri.Name]})
+	}
+	return []*models.Row{row}, nil
+}
+
+func (e *StatementExecutor) executeShowAuditLogStatement(q *influxql.ShowAuditLogStatement) (models.Rows, error) {
+	if e.AuditLog == nil {
+		return nil, ErrAuditLogDisabled
+	}
+
+	events, err := e.AuditLog.Events(q.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	row := &models.Row{Columns: []string{"time", "action", "user", "source", "database", "statement", "token", "outcome", "error"}}
+	for _, ev := range events {
+		values := []interface{}{ev.Time.UTC().Format(time.RFC3339Nano), ev.Action}
+		for _, v := range []string{ev.User, ev.Source, ev.Database, ev.Statement, ev.Token} {
+			values = append(values, nilIfEmpty(v))
+		}
+		values = append(values, ev.Outcome, nilIfEmpty(ev.Error))
+		row.Values = append(row.Values, values)
+	}
+	return []*models.Row{row}, nil
+}
+
+// nilIfEmpty returns nil for an empty string so that it is shown as a missing value.
+func nilIfEmpty(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
+func (e *StatementExecutor) executeShowSecurityPoliciesStatement(q *influxql.ShowSecurityPoliciesStatement) (models.Rows, error) {
+	row := &models.Row{Columns: []string{"database", "user", "role", "condition"}}
+	for _, pi := range e.MetaClient.SecurityPolicies() {
+		if q.Database != "" && pi.Database != q.Database {
+			continue
+		}
+
+		var user, role interface{}
+		if pi.User != "" {
+			user = pi.User
+		}
+		if pi.Role != "" {
+			role = pi.Role
+		}
+		row.Values = append(row.Values, []interface{}{pi.Database, user, role, pi.Condition})
+	}
+	return []*models.Row{row}, nil
+}
+
+func (e *StatementExecutor) executeShowTokensStatement(q *influxql.ShowTokensStatement) (models.Rows, error) {
+	row := &models.Row{Columns: []string{"id", "user", "privileges", "description", "created", "expires"}}
+	for _, ti := range e.MetaClient.Tokens() {
+		if q.User != "" && ti.User != q.User {
+			continue
+		}
+
+		databases := make([]string, 0, len(ti.Privileges))
+		for database := range ti.Privileges {
+			databases = append(databases, database)
+		}
+		sort.Strings(databases)
+		privileges := make([]string, 0, len(databases))
+		for _, database := range databases {
+			privileges = append(privileges, ti.Privileges[database].String()+" ON "+influxql.QuoteIdent(database))
+		}
+
+		var expires interface{}
+		if !ti.ExpiresAt.IsZero() {
+			expires = ti.ExpiresAt.UTC().Format(time.RFC3339)
+		}
+		row.Values = append(row.Values, []interface{}{ti.ID, ti.User, privileges, ti.Description, ti.CreatedAt.UTC().Format(time.RFC3339), expires})
+	}
+	return []*models.Row{row}, nil
+}
+
 func (e *StatementExecutor) executeShowUsersStatement(q *influxql.ShowUsersStatement) (models.Rows, error) {
 	row := &models.Row{Columns: []string{"user", "admin"}}
 	for _, ui := range e.MetaClient.Users() {
@@ -1278,6 +1866,14 @@ func (e *StatementExecutor) NormalizeStatement(stmt influxql.Statement, defaultD
 			if node.Database == "" {
 				node.Database = defaultDatabase
 			}
+		case *influxql.ShowDownsamplePoliciesStatement:
+			if node.Database == "" {
+				node.Database = defaultDatabase
+			}
+		case *influxql.ShowMeasurementSchemasStatement:
+			if node.Database == "" {
+				node.Database = defaultDatabase
+			}
 		case *influxql.ShowMeasurementsStatement:
 			if node.Database == "" {
 				node.Database = defaultDatabase
@@ -1364,8 +1960,10 @@ type TSDBStore interface {
 
 	DeleteDatabase(name string) error
 	DeleteMeasurement(database, name string) error
+	DeleteField(database, name, field string) error
+	ConvertField(database, name, field string, typ influxql.DataType) error
 	DeleteRetentionPolicy(database, name string) error
-	DeleteSeries(database string, sources []influxql.Source, condition influxql.Expr) error
+	DeleteSeriesContext(ctx context.Context, database string, sources []influxql.Source, condition influxql.Expr) error
 	DeleteShard(id uint64) error
 
 	MeasurementNames(auth query.Authorizer, database string, cond influxql.Expr) ([][]byte, error)
@@ -1374,6 +1972,9 @@ type TSDBStore interface {
 
 	SeriesCardinality(database string) (int64, error)
 	MeasurementsCardinality(database string) (int64, error)
+
+	DatabaseDiskSize(database string) (int64, error)
+	DatabaseSeriesCount(database string) int64
 }
 
 var _ TSDBStore = LocalTSDBStore{}
@@ -1384,6 +1985,33 @@ type LocalTSDBStore struct {
 	*tsdb.Store
 }
 
+// ContinuousQuerier is an interface for running the historical windows of a
+// continuous query and for retrieving its most recent run. The runs are saved
+// in meta periodically, so LastRun returns a run that was not saved yet.
+type ContinuousQuerier interface {
+	Backfill(ctx context.Context, database, name string, start, end time.Time) (windows int, written int64, err error)
+	LastRun(database, name string) (meta.ContinuousQueryRunInfo, bool)
+}
+
+// AuditLog is an interface for recording and listing audit events.
+type AuditLog interface {
+	Record(e audit.Event)
+	Events(limit int) ([]audit.Event, error)
+}
+
+// SubscriptionQueues is an interface for reporting the writes queued for
+// subscriptions.
+type SubscriptionQueues interface {
+	QueueStatus(database, retentionPolicy, name string) (depth int64, oldest time.Time, ok bool)
+}
+
+// QuotaUsage is an interface for reporting the usage of the database quotas
+// that are enforced outside of the store.
+type QuotaUsage interface {
+	PointsPerSecond(database string) int64
+	DatabaseQueries(database string) int
+}
+
 // ShardIteratorCreator is an interface for creating an IteratorCreator to access a specific shard.
 type ShardIteratorCreator interface {
 	ShardIteratorCreator(id uint64) query.IteratorCreator
@@ -9,6 +9,7 @@ import (
 	"os"
 	"reflect"
 	"regexp"
+	"strings"
 	"testing"
 	"time"
 
@@ -18,7 +19,9 @@ import (
 	"github.com/ayang64/reflux/logger"
 	"github.com/ayang64/reflux/models"
 	"github.com/ayang64/reflux/query"
+	"github.com/ayang64/reflux/services/audit"
 	"github.com/ayang64/reflux/services/meta"
+	"github.com/ayang64/reflux/task"
 	"github.com/ayang64/reflux/tsdb"
 	"github.com/ayang64/reflux/influxql"
 )
@@ -445,6 +448,425 @@ func TestQueryExecutor_ExecuteQuery_ShowDatabases(t *testing.T) {
 	}
 }
 
+// Ensure continuous queries are listed with their last run, including runs
+// that were not saved in meta yet.
+func TestQueryExecutor_ExecuteQuery_ShowContinuousQueries(t *testing.T) {
+	lastRun := time.Date(2020, 1, 1, 0, 10, 0, 0, time.UTC)
+
+	qe := query.NewExecutor()
+	qe.StatementExecutor = &coordinator.StatementExecutor{
+		MetaClient: &internal.MetaClientMock{
+			DatabasesFn: func() []meta.DatabaseInfo {
+				return []meta.DatabaseInfo{{
+					Name: "db0",
+					ContinuousQueries: []meta.ContinuousQueryInfo{
+						{Name: "cq0", Query: "CREATE CONTINUOUS QUERY cq0"},
+						{
+							Name:  "cq1",
+							Query: "CREATE CONTINUOUS QUERY cq1",
+							LastRun: meta.ContinuousQueryRunInfo{
+								Time:          lastRun,
+								Duration:      1500 * time.Millisecond,
+								PointsWritten: 10,
+								Err:           "timeout",
+							},
+						},
+						{Name: "cq2", Query: "CREATE CONTINUOUS QUERY cq2"},
+					},
+				}}
+			},
+		},
+		ContinuousQuerier: &ContinuousQuerier{
+			LastRunFn: func(database, name string) (meta.ContinuousQueryRunInfo, bool) {
+				if database != "db0" || name != "cq2" {
+					return meta.ContinuousQueryRunInfo{}, false
+				}
+				return meta.ContinuousQueryRunInfo{Time: lastRun, Duration: time.Second, PointsWritten: 3}, true
+			},
+		},
+	}
+
+	results := ReadAllResults(qe.ExecuteQuery(MustParseQuery(`SHOW CONTINUOUS QUERIES`), query.ExecutionOptions{}, make(chan struct{})))
+	exp := []*query.Result{
+		{
+			StatementID: 0,
+			Series: []*models.Row{{
+				Name:    "db0",
+				Columns: []string{"name", "query", "last_run", "last_run_duration", "points_written", "last_error"},
+				Values: [][]interface{}{
+					{"cq0", "CREATE CONTINUOUS QUERY cq0", nil, nil, nil, nil},
+					{"cq1", "CREATE CONTINUOUS QUERY cq1", "2020-01-01T00:10:00Z", "1.5s", int64(10), "timeout"},
+					{"cq2", "CREATE CONTINUOUS QUERY cq2", "2020-01-01T00:10:00Z", "1s", int64(3), nil},
+				},
+			}},
+		},
+	}
+	if !reflect.DeepEqual(results, exp) {
+		t.Fatalf("unexpected results: exp %s, got %s", spew.Sdump(exp), spew.Sdump(results))
+	}
+}
+
+// Ensure downsample policies can be created, listed, and dropped.
+func TestQueryExecutor_ExecuteQuery_DownsamplePolicies(t *testing.T) {
+	var policies []meta.DownsamplePolicyInfo
+	qe := query.NewExecutor()
+	qe.StatementExecutor = &coordinator.StatementExecutor{
+		MetaClient: &internal.MetaClientMock{
+			CreateDownsamplePolicyFn: func(database string, dpi meta.DownsamplePolicyInfo) error {
+				if database != "db0" {
+					t.Errorf("unexpected database: %s", database)
+				}
+				policies = append(policies, dpi)
+				return nil
+			},
+			DropDownsamplePolicyFn: func(database, name string) error {
+				if database != "db0" || name != "dp0" {
+					t.Errorf("unexpected downsample policy: %s on %s", name, database)
+				}
+				policies = policies[1:]
+				return nil
+			},
+			DatabaseFn: func(name string) *meta.DatabaseInfo {
+				return &meta.DatabaseInfo{Name: name, DownsamplePolicies: policies}
+			},
+		},
+	}
+
+	results := ReadAllResults(qe.ExecuteQuery(MustParseQuery(`CREATE DOWNSAMPLE POLICY dp0 ON db0 FROM autogen TO rp5m EVERY 5m; CREATE DOWNSAMPLE POLICY dp1 ON db0 FROM autogen TO rp1h EVERY 1h WITH max`), query.ExecutionOptions{}, make(chan struct{})))
+	for _, r := range results {
+		if r.Err != nil {
+			t.Fatal(r.Err)
+		}
+	}
+
+	results = ReadAllResults(qe.ExecuteQuery(MustParseQuery(`SHOW DOWNSAMPLE POLICIES`), query.ExecutionOptions{Database: "db0"}, make(chan struct{})))
+	exp := []*query.Result{
+		{
+			StatementID: 0,
+			Series: []*models.Row{{
+				Columns: []string{"name", "from", "to", "every", "functions"},
+				Values: [][]interface{}{
+					{"dp0", "autogen", "rp5m", "5m0s", "mean,min,max,count"},
+					{"dp1", "autogen", "rp1h", "1h0m0s", "max"},
+				},
+			}},
+		},
+	}
+	if !reflect.DeepEqual(results, exp) {
+		t.Fatalf("unexpected results: exp %s, got %s", spew.Sdump(exp), spew.Sdump(results))
+	}
+
+	results = ReadAllResults(qe.ExecuteQuery(MustParseQuery(`DROP DOWNSAMPLE POLICY dp0 ON db0`), query.ExecutionOptions{}, make(chan struct{})))
+	if len(results) != 1 || results[0].Err != nil {
+		t.Fatalf("unexpected results: %s", spew.Sdump(results))
+	} else if len(policies) != 1 || policies[0].Name != "dp1" {
+		t.Fatalf("unexpected downsample policies: %#v", policies)
+	}
+}
+
+// Ensure a backfill is passed to the continuous query service.
+func TestQueryExecutor_ExecuteQuery_BackfillContinuousQuery(t *testing.T) {
+	e := NewQueryExecutor()
+
+	results := ReadAllResults(e.ExecuteQuery(`BACKFILL CONTINUOUS QUERY cq0 ON db0 FROM '2020-01-01T00:00:00Z' TO '2020-01-02T00:00:00Z'`, "", 0))
+	if len(results) != 1 || results[0].Err == nil {
+		t.Fatalf("expected error without a continuous query service: %s", spew.Sdump(results))
+	}
+
+	e.StatementExecutor.ContinuousQuerier = &ContinuousQuerier{
+		BackfillFn: func(ctx context.Context, database, name string, start, end time.Time) (int, int64, error) {
+			if database != "db0" || name != "cq0" {
+				t.Errorf("unexpected continuous query: %s on %s", name, database)
+			} else if exp := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC); !start.Equal(exp) {
+				t.Errorf("unexpected start time: %s", start)
+			} else if exp := time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC); !end.Equal(exp) {
+				t.Errorf("unexpected end time: %s", end)
+			}
+			return 24, 100, nil
+		},
+	}
+
+	results = ReadAllResults(e.ExecuteQuery(`BACKFILL CONTINUOUS QUERY cq0 ON db0 FROM '2020-01-01T00:00:00Z' TO '2020-01-02T00:00:00Z'`, "", 0))
+	exp := []*query.Result{
+		{
+			StatementID: 0,
+			Series: []*models.Row{{
+				Name:    "result",
+				Columns: []string{"time", "windows", "written"},
+				Values:  [][]interface{}{{time.Unix(0, 0).UTC(), 24, int64(100)}},
+			}},
+		},
+	}
+	if !reflect.DeepEqual(results, exp) {
+		t.Fatalf("unexpected results: exp %s, got %s", spew.Sdump(exp), spew.Sdump(results))
+	}
+}
+
+// Ensure the filter of a subscription is passed to the meta client.
+func TestQueryExecutor_ExecuteQuery_CreateSubscription(t *testing.T) {
+	e := NewQueryExecutor()
+	var called bool
+	e.MetaClient.CreateSubscriptionWithFilterFn = func(database, rp, name, mode string, destinations, fields []string, condition string) error {
+		called = true
+		if database != "db0" || rp != "rp0" || name != "s0" || mode != "ALL" || !reflect.DeepEqual(destinations, []string{"udp://h0:9093"}) {
+			t.Errorf("unexpected subscription: %s.%s.%s %s %v", database, rp, name, mode, destinations)
+		} else if !reflect.DeepEqual(fields, []string{"level"}) {
+			t.Errorf("unexpected fields: %v", fields)
+		} else if exp := `_name =~ /^alerts\./`; condition != exp {
+			t.Errorf("unexpected condition: got %s, exp %s", condition, exp)
+		}
+		return nil
+	}
+
+	results := ReadAllResults(e.ExecuteQuery(`CREATE SUBSCRIPTION s0 ON db0.rp0 DESTINATIONS ALL 'udp://h0:9093' FIELDS level WHERE _name =~ /^alerts\./`, "", 0))
+	if len(results) != 1 || results[0].Err != nil {
+		t.Fatalf("unexpected results: %s", spew.Sdump(results))
+	} else if !called {
+		t.Fatal("subscription not created")
+	}
+}
+
+// Ensure SHOW SUBSCRIPTIONS reports the queues of subscriptions.
+func TestQueryExecutor_ExecuteQuery_ShowSubscriptions(t *testing.T) {
+	e := NewQueryExecutor()
+	e.MetaClient.DatabasesFn = func() []meta.DatabaseInfo {
+		return []meta.DatabaseInfo{{
+			Name: "db0",
+			RetentionPolicies: []meta.RetentionPolicyInfo{{
+				Name: "rp0",
+				Subscriptions: []meta.SubscriptionInfo{
+					{Name: "s0", Mode: "ALL", Destinations: []string{"http://h0:8086"}},
+					{Name: "s1", Mode: "ANY", Destinations: []string{"udp://h1:9093"}},
+				},
+			}},
+		}}
+	}
+	e.StatementExecutor.Subscriber = &SubscriptionQueues{
+		QueueStatusFn: func(database, retentionPolicy, name string) (int64, time.Time, bool) {
+			if database != "db0" || retentionPolicy != "rp0" {
+				t.Errorf("unexpected subscription: %s.%s.%s", database, retentionPolicy, name)
+			}
+			return 3, time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), name == "s0"
+		},
+	}
+
+	results := ReadAllResults(e.ExecuteQuery(`SHOW SUBSCRIPTIONS`, "", 0))
+	exp := []*query.Result{
+		{
+			StatementID: 0,
+			Series: []*models.Row{{
+				Name:    "db0",
+				Columns: []string{"retention_policy", "name", "mode", "destinations", "queue_depth", "oldest_undelivered"},
+				Values: [][]interface{}{
+					{"rp0", "s0", "ALL", []string{"http://h0:8086"}, int64(3), "2020-01-01T00:00:00Z"},
+					{"rp0", "s1", "ANY", []string{"udp://h1:9093"}, nil, nil},
+				},
+			}},
+		},
+	}
+	if !reflect.DeepEqual(results, exp) {
+		t.Fatalf("unexpected results: exp %s, got %s", spew.Sdump(exp), spew.Sdump(results))
+	}
+}
+
+// Ensure quotas are set and dropped and SHOW QUOTAS reports their usage.
+func TestQueryExecutor_ExecuteQuery_Quotas(t *testing.T) {
+	e := NewQueryExecutor()
+	var set meta.QuotaInfo
+	e.MetaClient.SetQuotaFn = func(database string, qi meta.QuotaInfo) error {
+		if database != "db0" {
+			t.Errorf("unexpected database: %s", database)
+		}
+		set = qi
+		return nil
+	}
+	var dropped string
+	e.MetaClient.DropQuotaFn = func(database string) error {
+		dropped = database
+		return nil
+	}
+
+	results := ReadAllResults(e.ExecuteQuery(`SET QUOTA ON db0 DISK 1000 SERIES 10 QUERIES 2; DROP QUOTA ON db1`, "", 0))
+	if len(results) != 2 || results[0].Err != nil || results[1].Err != nil {
+		t.Fatalf("unexpected results: %s", spew.Sdump(results))
+	} else if exp := (meta.QuotaInfo{MaxDiskBytes: 1000, MaxSeries: 10, MaxConcurrentQueries: 2}); set != exp {
+		t.Fatalf("unexpected quota: exp %+v, got %+v", exp, set)
+	} else if dropped != "db1" {
+		t.Fatalf("unexpected dropped quota: %s", dropped)
+	}
+
+	e.MetaClient.DatabasesFn = func() []meta.DatabaseInfo {
+		return []meta.DatabaseInfo{
+			{Name: "db0", Quota: &meta.QuotaInfo{MaxDiskBytes: 1000, MaxSeries: 10, MaxPointsPerSecond: 100, MaxConcurrentQueries: 2}},
+			{Name: "db1"},
+		}
+	}
+	e.TSDBStore.DatabaseDiskSizeFn = func(database string) (int64, error) { return 500, nil }
+	e.TSDBStore.DatabaseSeriesCountFn = func(database string) int64 { return 4 }
+	e.StatementExecutor.Quotas = &QuotaUsage{
+		PointsPerSecondFn: func(database string) int64 { return 20 },
+		DatabaseQueriesFn: func(database string) int { return 1 },
+	}
+
+	results = ReadAllResults(e.ExecuteQuery(`SHOW QUOTAS`, "", 0))
+	exp := []*query.Result{
+		{
+			StatementID: 0,
+			Series: []*models.Row{{
+				Columns: []string{"database", "max_disk_bytes", "disk_bytes", "max_series", "series", "max_points_per_second", "points_per_second", "max_concurrent_queries", "queries"},
+				Values: [][]interface{}{
+					{"db0", int64(1000), int64(500), int64(10), int64(4), int64(100), int64(20), 2, 1},
+				},
+			}},
+		},
+	}
+	if !reflect.DeepEqual(results, exp) {
+		t.Fatalf("unexpected results: exp %s, got %s", spew.Sdump(exp), spew.Sdump(results))
+	}
+}
+
+// Ensure statements are recorded in the audit log and listed by SHOW AUDIT LOG.
+func TestQueryExecutor_ExecuteQuery_AuditLog(t *testing.T) {
+	e := NewQueryExecutor()
+	e.MetaClient.DropContinuousQueryFn = func(database, name string) error {
+		if name == "cq1" {
+			return meta.ErrContinuousQueryNotFound
+		}
+		return nil
+	}
+	auditLog := &AuditLog{}
+	e.StatementExecutor.AuditLog = auditLog
+
+	opt := query.ExecutionOptions{
+		Database:   "db0",
+		Authorizer: &meta.UserInfo{Name: "fred", Admin: true},
+		ClientAddr: "10.0.0.1",
+	}
+	ReadAllResults(e.Executor.ExecuteQuery(MustParseQuery(`DROP CONTINUOUS QUERY cq0 ON db0; DROP CONTINUOUS QUERY cq1 ON db0`), opt, make(chan struct{})))
+
+	exp := []audit.Event{
+		{Action: audit.ActionStatement, User: "fred", Source: "10.0.0.1", Database: "db0", Statement: "DROP CONTINUOUS QUERY cq0 ON db0", Outcome: audit.OutcomeSuccess},
+		{Action: audit.ActionStatement, User: "fred", Source: "10.0.0.1", Database: "db0", Statement: "DROP CONTINUOUS QUERY cq1 ON db0", Outcome: audit.OutcomeFailure, Error: meta.ErrContinuousQueryNotFound.Error()},
+	}
+	if !reflect.DeepEqual(auditLog.events, exp) {
+		t.Fatalf("unexpected events: exp %s, got %s", spew.Sdump(exp), spew.Sdump(auditLog.events))
+	}
+
+	for i := range auditLog.events {
+		auditLog.events[i].Time = time.Date(2020, 1, 1, 0, 0, i, 0, time.UTC)
+	}
+	results := ReadAllResults(e.ExecuteQuery(`SHOW AUDIT LOG LIMIT 1`, "", 0))
+	expResults := []*query.Result{
+		{
+			StatementID: 0,
+			Series: []*models.Row{{
+				Columns: []string{"time", "action", "user", "source", "database", "statement", "token", "outcome", "error"},
+				Values: [][]interface{}{
+					{"2020-01-01T00:00:01Z", "statement", "fred", "10.0.0.1", "db0", "DROP CONTINUOUS QUERY cq1 ON db0", nil, "failure", meta.ErrContinuousQueryNotFound.Error()},
+				},
+			}},
+		},
+	}
+	if !reflect.DeepEqual(results, expResults) {
+		t.Fatalf("unexpected results: exp %s, got %s", spew.Sdump(expResults), spew.Sdump(results))
+	}
+}
+
+// Ensure SELECT INTO statements, which write data, are recorded in the audit
+// log and other SELECT statements are not.
+func TestQueryExecutor_ExecuteQuery_AuditLog_Into(t *testing.T) {
+	e := DefaultQueryExecutor()
+	e.StatementExecutor.PointsWriter = writePointsIntoFunc(func(req *coordinator.IntoWriteRequest) error {
+		return nil
+	})
+	auditLog := &AuditLog{}
+	e.StatementExecutor.AuditLog = auditLog
+
+	e.MetaClient.ShardGroupsByTimeRangeFn = func(database, policy string, min, max time.Time) (a []meta.ShardGroupInfo, err error) {
+		return []meta.ShardGroupInfo{
+			{ID: 1, Shards: []meta.ShardInfo{
+				{ID: 100, Owners: []meta.ShardOwner{{NodeID: 0}}},
+			}},
+		}, nil
+	}
+	e.TSDBStore.ShardGroupFn = func(ids []uint64) tsdb.ShardGroup {
+		var sh MockShard
+		sh.CreateIteratorFn = func(_ context.Context, _ *influxql.Measurement, _ query.IteratorOptions) (query.Iterator, error) {
+			return &FloatIterator{
+				Points: []query.FloatPoint{{Name: "cpu", Time: int64(0 * time.Second), Value: 100}},
+			}, nil
+		}
+		sh.FieldDimensionsFn = func(measurements []string) (fields map[string]influxql.DataType, dimensions map[string]struct{}, err error) {
+			return map[string]influxql.DataType{"value": influxql.Float}, nil, nil
+		}
+		return &sh
+	}
+
+	opt := query.ExecutionOptions{
+		Database:   "db0",
+		Authorizer: &meta.UserInfo{Name: "fred", Admin: true},
+		ClientAddr: "10.0.0.1",
+	}
+	q := `SELECT max(value) FROM cpu WHERE time >= '2000-01-01T00:00:05Z' AND time < '2000-01-01T00:00:35Z'; ` +
+		`SELECT max(value) INTO cpu_max FROM cpu WHERE time >= '2000-01-01T00:00:05Z' AND time < '2000-01-01T00:00:35Z'`
+	ReadAllResults(e.Executor.ExecuteQuery(MustParseQuery(q), opt, make(chan struct{})))
+
+	if len(auditLog.events) != 1 {
+		t.Fatalf("unexpected events: %s", spew.Sdump(auditLog.events))
+	}
+	ev := auditLog.events[0]
+	if ev.Action != audit.ActionStatement || ev.User != "fred" || ev.Outcome != audit.OutcomeSuccess {
+		t.Fatalf("unexpected event: %s", spew.Sdump(ev))
+	} else if !strings.Contains(ev.Statement, "INTO") {
+		t.Fatalf("unexpected statement: %s", ev.Statement)
+	}
+}
+
+// Ensure background tasks can be listed and killed.
+func TestQueryExecutor_ExecuteQuery_ShowTasks_KillTask(t *testing.T) {
+	tasks, err := task.NewManager()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	e := NewQueryExecutor()
+	e.StatementExecutor.Tasks = tasks
+
+	started, killed := make(chan struct{}), make(chan error)
+	go func() {
+		killed <- tasks.Run(context.Background(), "tsm1 full compaction of shard 1", func(ctx context.Context) error {
+			close(started)
+			<-ctx.Done()
+			return ctx.Err()
+		})
+	}()
+	<-started
+
+	results := ReadAllResults(e.ExecuteQuery(`SHOW TASKS`, "", 0))
+	if len(results) != 1 || results[0].Err != nil {
+		t.Fatalf("unexpected results: %s", spew.Sdump(results))
+	} else if rows := results[0].Series; len(rows) != 1 || len(rows[0].Values) != 1 {
+		t.Fatalf("unexpected rows: %s", spew.Sdump(rows))
+	} else if got, exp := rows[0].Columns, []string{"id", "name", "duration"}; !reflect.DeepEqual(got, exp) {
+		t.Fatalf("unexpected columns: got %v, exp %v", got, exp)
+	} else if got, exp := rows[0].Values[0][:2], []interface{}{0, "tsm1 full compaction of shard 1"}; !reflect.DeepEqual(got, exp) {
+		t.Fatalf("unexpected task: got %v, exp %v", got, exp)
+	}
+
+	results = ReadAllResults(e.ExecuteQuery(`KILL TASK 0`, "", 0))
+	if len(results) != 1 || results[0].Err != nil {
+		t.Fatalf("unexpected results: %s", spew.Sdump(results))
+	}
+	if err := <-killed; err != context.Canceled {
+		t.Fatalf("unexpected task error: %v", err)
+	}
+
+	results = ReadAllResults(e.ExecuteQuery(`KILL TASK 0`, "", 0))
+	if len(results) != 1 || results[0].Err == nil {
+		t.Fatalf("expected error killing finished task: %s", spew.Sdump(results))
+	}
+}
+
 // QueryExecutor is a test wrapper for coordinator.QueryExecutor.
 type QueryExecutor struct {
 	*query.Executor
@@ -509,6 +931,62 @@ func (e *QueryExecutor) ExecuteQuery(q, database string, chunkSize int) <-chan *
 	}, make(chan struct{}))
 }
 
+// ContinuousQuerier is a mockable implementation of coordinator.ContinuousQuerier.
+type ContinuousQuerier struct {
+	BackfillFn func(ctx context.Context, database, name string, start, end time.Time) (int, int64, error)
+	LastRunFn  func(database, name string) (meta.ContinuousQueryRunInfo, bool)
+}
+
+func (c *ContinuousQuerier) Backfill(ctx context.Context, database, name string, start, end time.Time) (int, int64, error) {
+	return c.BackfillFn(ctx, database, name, start, end)
+}
+
+func (c *ContinuousQuerier) LastRun(database, name string) (meta.ContinuousQueryRunInfo, bool) {
+	if c.LastRunFn == nil {
+		return meta.ContinuousQueryRunInfo{}, false
+	}
+	return c.LastRunFn(database, name)
+}
+
+// AuditLog is an in-memory implementation of coordinator.AuditLog.
+type AuditLog struct {
+	events []audit.Event
+}
+
+func (l *AuditLog) Record(e audit.Event) {
+	l.events = append(l.events, e)
+}
+
+func (l *AuditLog) Events(limit int) ([]audit.Event, error) {
+	if limit > 0 && len(l.events) > limit {
+		return l.events[len(l.events)-limit:], nil
+	}
+	return l.events, nil
+}
+
+// SubscriptionQueues is a mockable implementation of coordinator.SubscriptionQueues.
+type SubscriptionQueues struct {
+	QueueStatusFn func(database, retentionPolicy, name string) (int64, time.Time, bool)
+}
+
+func (s *SubscriptionQueues) QueueStatus(database, retentionPolicy, name string) (int64, time.Time, bool) {
+	return s.QueueStatusFn(database, retentionPolicy, name)
+}
+
+// QuotaUsage is a mockable implementation of coordinator.QuotaUsage.
+type QuotaUsage struct {
+	PointsPerSecondFn func(database string) int64
+	DatabaseQueriesFn func(database string) int
+}
+
+func (u *QuotaUsage) PointsPerSecond(database string) int64 {
+	return u.PointsPerSecondFn(database)
+}
+
+func (u *QuotaUsage) DatabaseQueries(database string) int {
+	return u.DatabaseQueriesFn(database)
+}
+
 type MockShard struct {
 	Measurements             []string
 	FieldDimensionsFn        func(measurements []string) (fields map[string]influxql.DataType, dimensions map[string]struct{}, err error)
@@ -18,10 +18,10 @@ type (
 	Authorizer = influxdb.Authorizer
 )
 
-func NewController(mc MetaClient, reader influxdb.Reader, auth Authorizer, authEnabled bool, logger *zap.Logger) *Controller {
+func NewController(mc MetaClient, reader influxdb.Reader, writer influxdb.PointsWriter, auth Authorizer, authEnabled bool, logger *zap.Logger) *Controller {
 	builtin.Initialize()
 
-	storageDeps, err := influxdb.NewDependencies(mc, reader, auth, authEnabled)
+	storageDeps, err := influxdb.NewDependencies(mc, reader, writer, auth, authEnabled)
 	if err != nil {
 		panic(err)
 	}
@@ -12,10 +12,11 @@ type key int
 const dependenciesKey key = iota
 
 type StorageDependencies struct {
-	Reader      Reader
-	MetaClient  MetaClient
-	Authorizer  Authorizer
-	AuthEnabled bool
+	Reader       Reader
+	PointsWriter PointsWriter
+	MetaClient   MetaClient
+	Authorizer   Authorizer
+	AuthEnabled  bool
 }
 
 func (d StorageDependencies) Inject(ctx context.Context) context.Context {
@@ -26,6 +27,9 @@ func (d StorageDependencies) Validate() error {
 	if d.Reader == nil {
 		return errors.New("missing reader dependency")
 	}
+	if d.PointsWriter == nil {
+		return errors.New("missing points writer dependency")
+	}
 	if d.MetaClient == nil {
 		return errors.New("missing meta client dependency")
 	}
@@ -52,16 +56,18 @@ func (d Dependencies) Inject(ctx context.Context) context.Context {
 func NewDependencies(
 	mc MetaClient,
 	reader Reader,
+	writer PointsWriter,
 	auth Authorizer,
 	authEnabled bool,
 ) (Dependencies, error) {
 	fdeps := flux.NewDefaultDependencies()
 	deps := Dependencies{FluxDeps: fdeps}
 	deps.StorageDeps = StorageDependencies{
-		Reader:      reader,
-		MetaClient:  mc,
-		Authorizer:  auth,
-		AuthEnabled: authEnabled,
+		Reader:       reader,
+		PointsWriter: writer,
+		MetaClient:   mc,
+		Authorizer:   auth,
+		AuthEnabled:  authEnabled,
 	}
 	if err := deps.StorageDeps.Validate(); err != nil {
 		return Dependencies{}, err
@@ -15,10 +15,11 @@ import (
 )
 
 const (
-	ReadRangePhysKind     = "ReadRangePhysKind"
-	ReadGroupPhysKind     = "ReadGroupPhysKind"
-	ReadTagKeysPhysKind   = "ReadTagKeysPhysKind"
-	ReadTagValuesPhysKind = "ReadTagValuesPhysKind"
+	ReadRangePhysKind           = "ReadRangePhysKind"
+	ReadGroupPhysKind           = "ReadGroupPhysKind"
+	ReadWindowAggregatePhysKind = "ReadWindowAggregatePhysKind"
+	ReadTagKeysPhysKind         = "ReadTagKeysPhysKind"
+	ReadTagValuesPhysKind       = "ReadTagValuesPhysKind"
 )
 
 type ReadGroupPhysSpec struct {
@@ -46,6 +47,31 @@ func (s *ReadGroupPhysSpec) Copy() plan.ProcedureSpec {
 	return ns
 }
 
+type ReadWindowAggregatePhysSpec struct {
+	plan.DefaultCost
+	ReadRangePhysSpec
+
+	WindowEvery int64
+	Offset      int64
+
+	AggregateMethod string
+}
+
+func (s *ReadWindowAggregatePhysSpec) Kind() plan.ProcedureKind {
+	return ReadWindowAggregatePhysKind
+}
+
+func (s *ReadWindowAggregatePhysSpec) Copy() plan.ProcedureSpec {
+	ns := new(ReadWindowAggregatePhysSpec)
+	ns.ReadRangePhysSpec = *s.ReadRangePhysSpec.Copy().(*ReadRangePhysSpec)
+
+	ns.WindowEvery = s.WindowEvery
+	ns.Offset = s.Offset
+
+	ns.AggregateMethod = s.AggregateMethod
+	return ns
+}
+
 type ReadRangePhysSpec struct {
 	plan.DefaultCost
 
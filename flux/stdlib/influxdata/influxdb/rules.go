@@ -14,6 +14,7 @@ func init() {
 		PushDownRangeRule{},
 		PushDownFilterRule{},
 		PushDownGroupRule{},
+		PushDownWindowAggregateRule{},
 		PushDownReadTagKeysRule{},
 		PushDownReadTagValuesRule{},
 		SortedPivotRule{},
@@ -57,6 +58,101 @@ func (rule PushDownGroupRule) Rewrite(node plan.Node) (plan.Node, bool, error) {
 	}), true, nil
 }
 
+// PushDownWindowAggregateRule pushes down a windowed aggregate to storage.
+// It matches 'ReadRange |> window() |> agg()' where agg is one of the
+// aggregates storage can evaluate per window.
+type PushDownWindowAggregateRule struct{}
+
+func (rule PushDownWindowAggregateRule) Name() string {
+	return "PushDownWindowAggregateRule"
+}
+
+// Pattern matches any node, as the aggregate may be one of several kinds.
+// The remainder of the pattern is checked by Rewrite.
+func (rule PushDownWindowAggregateRule) Pattern() plan.Pattern {
+	return plan.Any()
+}
+
+func (rule PushDownWindowAggregateRule) Rewrite(node plan.Node) (plan.Node, bool, error) {
+	if !canPushWindowedAggregate(node.ProcedureSpec()) {
+		return node, false, nil
+	}
+
+	windowNode, ok := soleParent(node, universe.WindowKind)
+	if !ok {
+		return node, false, nil
+	}
+	fromNode, ok := soleParent(windowNode, ReadRangePhysKind)
+	if !ok {
+		return node, false, nil
+	}
+
+	window := windowNode.ProcedureSpec().(*universe.WindowProcedureSpec)
+	src := fromNode.ProcedureSpec().(*ReadRangePhysSpec)
+
+	// Storage produces windows aligned to every with the default columns
+	// and does not create empty windows.
+	if window.CreateEmpty ||
+		window.TimeColumn != execute.DefaultTimeColLabel ||
+		window.StartColumn != execute.DefaultStartColLabel ||
+		window.StopColumn != execute.DefaultStopColLabel {
+		return node, false, nil
+	}
+	every := window.Window.Every
+	if every.Months() != 0 || every.Nanoseconds() <= 0 ||
+		!window.Window.Period.Equal(every) ||
+		window.Window.Offset.Months() != 0 {
+		return node, false, nil
+	}
+
+	return plan.CreatePhysicalNode("ReadWindowAggregate", &ReadWindowAggregatePhysSpec{
+		ReadRangePhysSpec: *src.Copy().(*ReadRangePhysSpec),
+		WindowEvery:       every.Nanoseconds(),
+		Offset:            window.Window.Offset.Nanoseconds(),
+		AggregateMethod:   string(node.Kind()),
+	}), true, nil
+}
+
+// canPushWindowedAggregate reports whether spec is an aggregate or selector
+// of the _value column that storage can evaluate for each window.
+func canPushWindowedAggregate(spec plan.ProcedureSpec) bool {
+	switch spec := spec.(type) {
+	case *universe.CountProcedureSpec:
+		return isValueColumns(spec.Columns)
+	case *universe.SumProcedureSpec:
+		return isValueColumns(spec.Columns)
+	case *universe.MeanProcedureSpec:
+		return isValueColumns(spec.Columns)
+	case *universe.MinProcedureSpec:
+		return spec.Column == execute.DefaultValueColLabel
+	case *universe.MaxProcedureSpec:
+		return spec.Column == execute.DefaultValueColLabel
+	case *universe.FirstProcedureSpec:
+		return spec.Column == execute.DefaultValueColLabel
+	case *universe.LastProcedureSpec:
+		return spec.Column == execute.DefaultValueColLabel
+	default:
+		return false
+	}
+}
+
+func isValueColumns(columns []string) bool {
+	return len(columns) == 1 && columns[0] == execute.DefaultValueColLabel
+}
+
+// soleParent returns the only predecessor of node if it is of the given kind
+// and node is its only successor.
+func soleParent(node plan.Node, kind plan.ProcedureKind) (plan.Node, bool) {
+	if len(node.Predecessors()) != 1 {
+		return nil, false
+	}
+	parent := node.Predecessors()[0]
+	if parent.Kind() != kind || len(parent.Successors()) != 1 {
+		return nil, false
+	}
+	return parent, true
+}
+
 // PushDownRangeRule pushes down a range filter to storage
 type PushDownRangeRule struct{}
 
@@ -115,6 +211,16 @@ func (PushDownFilterRule) Rewrite(pn plan.Node) (plan.Node, bool, error) {
 		return pn, false, nil
 	}
 
+	// A filter that is always true, such as the default predicate of the
+	// v1 and schema functions, filters nothing and can be removed.
+	if lit, ok := bodyExpr.(*semantic.BooleanLiteral); ok && lit.Value {
+		mergedNode, err := plan.MergeToPhysicalNode(pn, fromNode, fromSpec.Copy().(*ReadRangePhysSpec))
+		if err != nil {
+			return nil, false, err
+		}
+		return mergedNode, true, nil
+	}
+
 	paramName := filterSpec.Fn.Fn.Block.Parameters.List[0].Key.Name
 
 	pushable, notPushable, err := semantic.PartitionPredicates(bodyExpr, func(e semantic.Expression) (bool, error) {
@@ -293,6 +399,12 @@ func (rule PushDownReadTagValuesRule) Rewrite(pn plan.Node) (plan.Node, bool, er
 		return pn, false, nil
 	}
 
+	// The field keys are read from the measurement fields rather than
+	// the series index so they cannot be filtered by _field.
+	if tagKey == "_field" && hasFieldExpr(fromSpec.Filter) {
+		return pn, false, nil
+	}
+
 	// The schema mutator needs to correspond to a keep call
 	// on the tag key column.
 	if len(keepSpec.Mutations) != 1 {
@@ -323,9 +435,6 @@ var invalidTagKeysForTagValues = []string{
 	execute.DefaultValueColLabel,
 	execute.DefaultStartColLabel,
 	execute.DefaultStopColLabel,
-	// TODO(jsternberg): There just doesn't seem to be a good way to do this
-	// in the 1.x line of the release.
-	"_field",
 }
 
 // isValidTagKeyForTagValues returns true if the given key can
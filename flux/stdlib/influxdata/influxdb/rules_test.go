@@ -364,6 +364,31 @@ func TestPushDownFilterRule(t *testing.T) {
 			},
 			NoChange: true,
 		},
+		{
+			Name: "always true",
+			// ReadRange -> filter(fn: (r) => true)  =>  ReadRange
+			Rules: []plan.Rule{influxdb.PushDownFilterRule{}},
+			Before: &plantest.PlanSpec{
+				Nodes: []plan.Node{
+					plan.CreatePhysicalNode("ReadRange", &influxdb.ReadRangePhysSpec{
+						Bounds: bounds,
+					}),
+					plan.CreatePhysicalNode("filter", &universe.FilterProcedureSpec{
+						Fn: makeResolvedFilterFn(&semantic.BooleanLiteral{Value: true}),
+					}),
+				},
+				Edges: [][2]int{
+					{0, 1},
+				},
+			},
+			After: &plantest.PlanSpec{
+				Nodes: []plan.Node{
+					plan.CreatePhysicalNode("merged_ReadRange_filter", &influxdb.ReadRangePhysSpec{
+						Bounds: bounds,
+					}),
+				},
+			},
+		},
 		{
 			Name:  `exists r.host`,
 			Rules: []plan.Rule{influxdb.PushDownFilterRule{}},
@@ -857,6 +882,194 @@ func TestPushDownGroupRule(t *testing.T) {
 	}
 }
 
+func TestPushDownWindowAggregateRule(t *testing.T) {
+	readRange := influxdb.ReadRangePhysSpec{
+		Bucket: "my-bucket",
+		Bounds: flux.Bounds{
+			Start: fluxTime(5),
+			Stop:  fluxTime(10),
+		},
+	}
+
+	window := func(every, period, offset time.Duration) *universe.WindowProcedureSpec {
+		return &universe.WindowProcedureSpec{
+			Window: plan.WindowSpec{
+				Every:  flux.ConvertDuration(every),
+				Period: flux.ConvertDuration(period),
+				Offset: flux.ConvertDuration(offset),
+			},
+			TimeColumn:  execute.DefaultTimeColLabel,
+			StartColumn: execute.DefaultStartColLabel,
+			StopColumn:  execute.DefaultStopColLabel,
+		}
+	}
+	minSpec := &universe.MinProcedureSpec{
+		SelectorConfig: execute.SelectorConfig{Column: execute.DefaultValueColLabel},
+	}
+	countSpec := &universe.CountProcedureSpec{
+		AggregateConfig: execute.DefaultAggregateConfig,
+	}
+
+	emptyWindow := window(time.Minute, time.Minute, 0)
+	emptyWindow.CreateEmpty = true
+	otherMinSpec := &universe.MinProcedureSpec{
+		SelectorConfig: execute.SelectorConfig{Column: "other"},
+	}
+
+	// WindowProcedureSpec.Copy does not retain the window's columns, so
+	// cases that expect no change spell out the unchanged plan rather than
+	// setting NoChange.
+	windowPlan := func(window *universe.WindowProcedureSpec, agg plan.PhysicalProcedureSpec) *plantest.PlanSpec {
+		return &plantest.PlanSpec{
+			Nodes: []plan.Node{
+				plan.CreatePhysicalNode("ReadRange", &readRange),
+				plan.CreatePhysicalNode("window", window),
+				plan.CreatePhysicalNode(plan.NodeID(agg.Kind()), agg),
+			},
+			Edges: [][2]int{
+				{0, 1},
+				{1, 2},
+			},
+		}
+	}
+
+	tests := []plantest.RuleTestCase{
+		{
+			Name: "simple",
+			// ReadRange -> window -> min  =>  ReadWindowAggregate
+			Rules: []plan.Rule{
+				influxdb.PushDownWindowAggregateRule{},
+			},
+			Before: &plantest.PlanSpec{
+				Nodes: []plan.Node{
+					plan.CreateLogicalNode("ReadRange", &readRange),
+					plan.CreateLogicalNode("window", window(time.Minute, time.Minute, time.Second)),
+					plan.CreateLogicalNode("min", minSpec),
+				},
+				Edges: [][2]int{
+					{0, 1},
+					{1, 2},
+				},
+			},
+			After: &plantest.PlanSpec{
+				Nodes: []plan.Node{
+					plan.CreatePhysicalNode("ReadWindowAggregate", &influxdb.ReadWindowAggregatePhysSpec{
+						ReadRangePhysSpec: readRange,
+						WindowEvery:       int64(time.Minute),
+						Offset:            int64(time.Second),
+						AggregateMethod:   universe.MinKind,
+					}),
+				},
+			},
+		},
+		{
+			Name: "with successor",
+			// ReadRange -> window -> count -> mean  =>  ReadWindowAggregate -> mean
+			Rules: []plan.Rule{
+				influxdb.PushDownWindowAggregateRule{},
+			},
+			Before: &plantest.PlanSpec{
+				Nodes: []plan.Node{
+					plan.CreateLogicalNode("ReadRange", &readRange),
+					plan.CreateLogicalNode("window", window(time.Minute, time.Minute, 0)),
+					plan.CreateLogicalNode("count", countSpec),
+					plan.CreatePhysicalNode("mean", &universe.MeanProcedureSpec{}),
+				},
+				Edges: [][2]int{
+					{0, 1},
+					{1, 2},
+					{2, 3},
+				},
+			},
+			After: &plantest.PlanSpec{
+				Nodes: []plan.Node{
+					plan.CreatePhysicalNode("ReadWindowAggregate", &influxdb.ReadWindowAggregatePhysSpec{
+						ReadRangePhysSpec: readRange,
+						WindowEvery:       int64(time.Minute),
+						AggregateMethod:   universe.CountKind,
+					}),
+					plan.CreatePhysicalNode("mean", &universe.MeanProcedureSpec{}),
+				},
+				Edges: [][2]int{{0, 1}},
+			},
+		},
+		{
+			Name: "sliding window",
+			// ReadRange -> window(period > every) -> min  =>  no change
+			Rules: []plan.Rule{
+				influxdb.PushDownWindowAggregateRule{},
+			},
+			Before: windowPlan(window(time.Minute, 2*time.Minute, 0), minSpec),
+			After:  windowPlan(window(time.Minute, 2*time.Minute, 0), minSpec),
+		},
+		{
+			Name: "create empty",
+			// ReadRange -> window(createEmpty: true) -> count  =>  no change
+			Rules: []plan.Rule{
+				influxdb.PushDownWindowAggregateRule{},
+			},
+			Before: windowPlan(emptyWindow, countSpec),
+			After:  windowPlan(emptyWindow, countSpec),
+		},
+		{
+			Name: "other column",
+			// ReadRange -> window -> min(column: "other")  =>  no change
+			Rules: []plan.Rule{
+				influxdb.PushDownWindowAggregateRule{},
+			},
+			Before: windowPlan(window(time.Minute, time.Minute, 0), otherMinSpec),
+			After:  windowPlan(window(time.Minute, time.Minute, 0), otherMinSpec),
+		},
+		{
+			Name: "window with multiple successors",
+			//
+			//   min    count          min    count
+			//     \    /       =>       \    /
+			//     window                window
+			//       |                     |
+			//   ReadRange             ReadRange
+			//
+			Rules: []plan.Rule{
+				influxdb.PushDownWindowAggregateRule{},
+			},
+			Before: &plantest.PlanSpec{
+				Nodes: []plan.Node{
+					plan.CreatePhysicalNode("ReadRange", &readRange),
+					plan.CreatePhysicalNode("window", window(time.Minute, time.Minute, 0)),
+					plan.CreatePhysicalNode("min", minSpec),
+					plan.CreatePhysicalNode("count", countSpec),
+				},
+				Edges: [][2]int{
+					{0, 1},
+					{1, 2},
+					{1, 3},
+				},
+			},
+			After: &plantest.PlanSpec{
+				Nodes: []plan.Node{
+					plan.CreatePhysicalNode("ReadRange", &readRange),
+					plan.CreatePhysicalNode("window", window(time.Minute, time.Minute, 0)),
+					plan.CreatePhysicalNode("min", minSpec),
+					plan.CreatePhysicalNode("count", countSpec),
+				},
+				Edges: [][2]int{
+					{0, 1},
+					{1, 2},
+					{1, 3},
+				},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Parallel()
+			plantest.PhysicalRuleTestHelper(t, &tc)
+		})
+	}
+}
+
 func TestReadTagKeysRule(t *testing.T) {
 	fromSpec := influxdb.FromProcedureSpec{
 		Bucket: "my-bucket",
@@ -1143,6 +1356,54 @@ func TestReadTagValuesRule(t *testing.T) {
 		}
 		return &s
 	}
+	keepColumn := func(column string) *universe.SchemaMutationProcedureSpec {
+		return &universe.SchemaMutationProcedureSpec{
+			Mutations: []universe.SchemaMutation{
+				&universe.KeepOpSpec{
+					Columns: []string{column},
+				},
+			},
+		}
+	}
+	// fieldFilterPlan returns a plan reading the values of _field with
+	// a filter on _field. The schema mutation cannot be copied so the
+	// plan is built for both sides of the test instead of using NoChange.
+	fieldFilterPlan := func() *plantest.PlanSpec {
+		return &plantest.PlanSpec{
+			Nodes: []plan.Node{
+				plan.CreatePhysicalNode("ReadRange", &influxdb.ReadRangePhysSpec{
+					Bucket:    "my-bucket",
+					Bounds:    rangeSpec.Bounds,
+					FilterSet: true,
+					Filter: &semantic.FunctionExpression{
+						Block: &semantic.FunctionBlock{
+							Parameters: &semantic.FunctionParameters{
+								List: []*semantic.FunctionParameter{{
+									Key: &semantic.Identifier{Name: "r"},
+								}},
+							},
+							Body: &semantic.BinaryExpression{
+								Operator: ast.EqualOperator,
+								Left: &semantic.MemberExpression{
+									Object:   &semantic.IdentifierExpression{Name: "r"},
+									Property: "_field",
+								},
+								Right: &semantic.StringLiteral{Value: "usage"},
+							},
+						},
+					},
+				}),
+				plan.CreatePhysicalNode("keep", keepColumn("_field")),
+				plan.CreatePhysicalNode("group", &groupSpec),
+				plan.CreatePhysicalNode("distinct", &universe.DistinctProcedureSpec{Column: "_field"}),
+			},
+			Edges: [][2]int{
+				{0, 1},
+				{1, 2},
+				{2, 3},
+			},
+		}
+	}
 
 	tests := []plantest.RuleTestCase{
 		{
@@ -1278,6 +1539,49 @@ func TestReadTagValuesRule(t *testing.T) {
 				},
 			},
 		},
+		{
+			Name: "field keys",
+			// from -> range -> keep -> group -> distinct(column: "_field")  =>  ReadTagValues
+			Rules: []plan.Rule{
+				influxdb.PushDownRangeRule{},
+				influxdb.PushDownReadTagValuesRule{},
+			},
+			Before: &plantest.PlanSpec{
+				Nodes: []plan.Node{
+					plan.CreateLogicalNode("from", &fromSpec),
+					plan.CreateLogicalNode("range", &rangeSpec),
+					plan.CreateLogicalNode("keep", keepColumn("_field")),
+					plan.CreateLogicalNode("group", &groupSpec),
+					plan.CreateLogicalNode("distinct", &universe.DistinctProcedureSpec{Column: "_field"}),
+				},
+				Edges: [][2]int{
+					{0, 1},
+					{1, 2},
+					{2, 3},
+					{3, 4},
+				},
+			},
+			After: &plantest.PlanSpec{
+				Nodes: []plan.Node{
+					plan.CreatePhysicalNode("ReadTagValues", &influxdb.ReadTagValuesPhysSpec{
+						ReadRangePhysSpec: influxdb.ReadRangePhysSpec{
+							Bucket: "my-bucket",
+							Bounds: rangeSpec.Bounds,
+						},
+						TagKey: "_field",
+					}),
+				},
+			},
+		},
+		{
+			Name: "field keys with field filter",
+			// ReadRange(filter: _field) -> keep -> group -> distinct(column: "_field")  =>  unchanged
+			Rules: []plan.Rule{
+				influxdb.PushDownReadTagValuesRule{},
+			},
+			Before: fieldFilterPlan(),
+			After:  fieldFilterPlan(),
+		},
 	}
 
 	for _, tc := range tests {
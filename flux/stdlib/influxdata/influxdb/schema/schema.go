@@ -0,0 +1,70 @@
+// Package schema registers the influxdata/influxdb/schema Flux package.
+//
+// The functions explore the schema of a database. Each is written so that the
+// planner rewrites it into a tag keys or tag values read, which are answered
+// from the series index instead of by scanning data. Besides the functions of
+// influxdata/influxdb/v1, it provides fieldKeys and measurementFieldKeys, which
+// are also added to influxdata/influxdb/v1.
+package schema
+
+import (
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/parser"
+)
+
+const PackagePath = "influxdata/influxdb/schema"
+
+const source = `package schema
+
+// tagValues returns the unique values for a given tag.
+// The return value is always a single table with a single column "_value".
+tagValues = (bucket, tag, predicate=(r) => true, start=-30d) =>
+    from(bucket: bucket)
+        |> range(start: start)
+        |> filter(fn: predicate)
+        |> keep(columns: [tag])
+        |> group()
+        |> distinct(column: tag)
+
+// measurementTagValues returns the unique values for a given tag in a measurement.
+measurementTagValues = (bucket, measurement, tag, start=-30d) =>
+    tagValues(bucket: bucket, tag: tag, predicate: (r) => r._measurement == measurement, start: start)
+
+// tagKeys returns the list of tag keys for all series that match the predicate.
+// The return value is always a single table with a single column "_value".
+tagKeys = (bucket, predicate=(r) => true, start=-30d) =>
+    from(bucket: bucket)
+        |> range(start: start)
+        |> filter(fn: predicate)
+        |> keys()
+        |> keep(columns: ["_value"])
+        |> distinct()
+
+// measurementTagKeys returns the list of tag keys for a measurement.
+measurementTagKeys = (bucket, measurement, start=-30d) =>
+    tagKeys(bucket: bucket, predicate: (r) => r._measurement == measurement, start: start)
+
+// fieldKeys returns the list of field keys of the measurements with series
+// matching the predicate.
+fieldKeys = (bucket, predicate=(r) => true, start=-30d) =>
+    tagValues(bucket: bucket, tag: "_field", predicate: predicate, start: start)
+
+// measurementFieldKeys returns the list of field keys for a measurement.
+measurementFieldKeys = (bucket, measurement, start=-30d) =>
+    fieldKeys(bucket: bucket, predicate: (r) => r._measurement == measurement, start: start)
+
+// measurements returns the list of measurements in a bucket.
+measurements = (bucket, start=-30d) =>
+    tagValues(bucket: bucket, tag: "_measurement", start: start)
+
+// fieldsAsCols aligns the fields of each measurement that have the same timestamp into columns.
+fieldsAsCols = (tables=<-) =>
+    tables
+        |> pivot(rowKey: ["_time"], columnKey: ["_field"], valueColumn: "_value")
+`
+
+func init() {
+	pkg := parser.ParseSource(source)
+	pkg.Path = PackagePath
+	flux.RegisterPackage(pkg)
+}
@@ -0,0 +1,36 @@
+package schema_test
+
+import (
+	"testing"
+
+	"github.com/ayang64/reflux/flux/builtin"
+	"github.com/ayang64/reflux/flux/stdlib/influxdata/influxdb/schema"
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/semantic"
+)
+
+func TestSchemaFunctions(t *testing.T) {
+	builtin.Initialize()
+
+	pkg, ok := flux.StdLib().ImportPackageObject(schema.PackagePath)
+	if !ok {
+		t.Fatalf("%s is not registered", schema.PackagePath)
+	}
+	for _, name := range []string{
+		"fieldsAsCols",
+		"fieldKeys",
+		"measurementFieldKeys",
+		"measurements",
+		"measurementTagKeys",
+		"measurementTagValues",
+		"tagKeys",
+		"tagValues",
+	} {
+		v, ok := pkg.Get(name)
+		if !ok {
+			t.Errorf("schema.%s is not registered", name)
+		} else if got := v.PolyType().Nature(); got != semantic.Function {
+			t.Errorf("schema.%s has unexpected type %s", name, got)
+		}
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 
 	"github.com/ayang64/reflux/influxql"
+	"github.com/ayang64/reflux/models"
 	"github.com/ayang64/reflux/services/meta"
 	"github.com/ayang64/reflux/tsdb/cursors"
 	"github.com/influxdata/flux"
@@ -16,6 +17,16 @@ import (
 
 type Authorizer interface {
 	AuthorizeDatabase(u meta.User, priv influxql.Privilege, database string) error
+
+	// AuthorizeWrite returns nil if the user may write to the database or to
+	// some of its measurements.
+	AuthorizeWrite(u meta.User, database string) error
+}
+
+// PointsWriter writes points to a database and retention policy on behalf of
+// user.
+type PointsWriter interface {
+	WritePoints(database, retentionPolicy string, consistencyLevel models.ConsistencyLevel, user meta.User, points []models.Point) error
 }
 
 type FromDependencies struct {
@@ -77,6 +88,17 @@ type ReadGroupSpec struct {
 	AggregateMethod string
 }
 
+type ReadWindowAggregateSpec struct {
+	ReadFilterSpec
+
+	// WindowEvery and Offset are the width and alignment of each window
+	// in nanoseconds.
+	WindowEvery int64
+	Offset      int64
+
+	AggregateMethod string
+}
+
 type ReadTagKeysSpec struct {
 	ReadFilterSpec
 }
@@ -89,6 +111,7 @@ type ReadTagValuesSpec struct {
 type Reader interface {
 	ReadFilter(ctx context.Context, spec ReadFilterSpec, alloc *memory.Allocator) (TableIterator, error)
 	ReadGroup(ctx context.Context, spec ReadGroupSpec, alloc *memory.Allocator) (TableIterator, error)
+	ReadWindowAggregate(ctx context.Context, spec ReadWindowAggregateSpec, alloc *memory.Allocator) (TableIterator, error)
 
 	ReadTagKeys(ctx context.Context, spec ReadTagKeysSpec, alloc *memory.Allocator) (TableIterator, error)
 	ReadTagValues(ctx context.Context, spec ReadTagValuesSpec, alloc *memory.Allocator) (TableIterator, error)
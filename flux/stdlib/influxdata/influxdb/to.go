@@ -1,4 +1,490 @@
 package influxdb
 
-// TODO(jsternberg): Implement the to method in influxdb 1.x.
-// This file is kept around so it shows up in the patch.
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ayang64/reflux/influxql"
+	"github.com/ayang64/reflux/models"
+	"github.com/ayang64/reflux/services/meta"
+	"github.com/ayang64/reflux/tsdb"
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/codes"
+	"github.com/influxdata/flux/compiler"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/interpreter"
+	"github.com/influxdata/flux/plan"
+	"github.com/influxdata/flux/semantic"
+	"github.com/influxdata/flux/stdlib/influxdata/influxdb"
+	"github.com/influxdata/flux/values"
+)
+
+const ToKind = "influxDBTo"
+
+// DefaultToBatchSize is the number of points buffered by to() before they
+// are written.
+const DefaultToBatchSize = 5000
+
+type ToOpSpec struct {
+	Bucket            string                       `json:"bucket"`
+	TimeColumn        string                       `json:"timeColumn"`
+	MeasurementColumn string                       `json:"measurementColumn"`
+	TagColumns        []string                     `json:"tagColumns"`
+	FieldFn           interpreter.ResolvedFunction `json:"fieldFn"`
+}
+
+// ToSignature is the signature of to(). It differs from the upstream
+// signature in declaring tagColumns as an array of strings, which the
+// upstream semantic.Array does not unify with.
+var ToSignature = flux.FunctionSignature(
+	map[string]semantic.PolyType{
+		"bucket":            semantic.String,
+		"bucketID":          semantic.String,
+		"org":               semantic.String,
+		"orgID":             semantic.String,
+		"host":              semantic.String,
+		"token":             semantic.String,
+		"timeColumn":        semantic.String,
+		"measurementColumn": semantic.String,
+		"tagColumns":        semantic.NewArrayPolyType(semantic.String),
+		"fieldFn": semantic.NewFunctionPolyType(semantic.FunctionPolySignature{
+			Parameters: map[string]semantic.PolyType{
+				"r": semantic.Tvar(1),
+			},
+			Required: semantic.LabelSet{"r"},
+			Return:   semantic.Tvar(2),
+		}),
+	},
+	nil,
+)
+
+func init() {
+	flux.ReplacePackageValue("influxdata/influxdb", influxdb.ToKind, flux.FunctionValueWithSideEffect(ToKind, createToOpSpec, ToSignature))
+	flux.RegisterOpSpec(ToKind, newToOp)
+	plan.RegisterProcedureSpecWithSideEffect(ToKind, newToProcedure, ToKind)
+	execute.RegisterTransformation(ToKind, createToTransformation)
+}
+
+// unsupportedToArgs are the arguments of to() that only apply to InfluxDB 2.x.
+var unsupportedToArgs = []string{"bucketID", "org", "orgID", "host", "token"}
+
+func createToOpSpec(args flux.Arguments, a *flux.Administration) (flux.OperationSpec, error) {
+	if err := a.AddParentFromArgs(args); err != nil {
+		return nil, err
+	}
+
+	for _, arg := range unsupportedToArgs {
+		if _, ok := args.Get(arg); ok {
+			return nil, &flux.Error{
+				Code: codes.Invalid,
+				Msg:  fmt.Sprintf("argument %q is not supported in 1.x", arg),
+			}
+		}
+	}
+
+	spec := &ToOpSpec{
+		TimeColumn:        execute.DefaultTimeColLabel,
+		MeasurementColumn: "_measurement",
+	}
+
+	if bucket, ok, err := args.GetString("bucket"); err != nil {
+		return nil, err
+	} else if !ok || bucket == "" {
+		return nil, &flux.Error{
+			Code: codes.Invalid,
+			Msg:  "must specify bucket",
+		}
+	} else {
+		spec.Bucket = bucket
+	}
+
+	if col, ok, err := args.GetString("timeColumn"); err != nil {
+		return nil, err
+	} else if ok {
+		spec.TimeColumn = col
+	}
+
+	if col, ok, err := args.GetString("measurementColumn"); err != nil {
+		return nil, err
+	} else if ok {
+		spec.MeasurementColumn = col
+	}
+
+	if cols, ok, err := args.GetArray("tagColumns", semantic.String); err != nil {
+		return nil, err
+	} else if ok {
+		spec.TagColumns = make([]string, cols.Len())
+		cols.Range(func(i int, v values.Value) {
+			spec.TagColumns[i] = v.Str()
+		})
+		sort.Strings(spec.TagColumns)
+	}
+
+	if fn, ok, err := args.GetFunction("fieldFn"); err != nil {
+		return nil, err
+	} else if ok {
+		resolved, err := interpreter.ResolveFunction(fn)
+		if err != nil {
+			return nil, err
+		}
+		spec.FieldFn = resolved
+	}
+	return spec, nil
+}
+
+func newToOp() flux.OperationSpec {
+	return new(ToOpSpec)
+}
+
+func (s *ToOpSpec) Kind() flux.OperationKind {
+	return ToKind
+}
+
+type ToProcedureSpec struct {
+	plan.DefaultCost
+	Spec *ToOpSpec
+}
+
+func newToProcedure(qs flux.OperationSpec, pa plan.Administration) (plan.ProcedureSpec, error) {
+	spec, ok := qs.(*ToOpSpec)
+	if !ok {
+		return nil, &flux.Error{
+			Code: codes.Internal,
+			Msg:  fmt.Sprintf("invalid spec type %T", qs),
+		}
+	}
+	return &ToProcedureSpec{Spec: spec}, nil
+}
+
+func (s *ToProcedureSpec) Kind() plan.ProcedureKind {
+	return ToKind
+}
+
+func (s *ToProcedureSpec) Copy() plan.ProcedureSpec {
+	ns := new(ToProcedureSpec)
+	ns.Spec = &ToOpSpec{
+		Bucket:            s.Spec.Bucket,
+		TimeColumn:        s.Spec.TimeColumn,
+		MeasurementColumn: s.Spec.MeasurementColumn,
+		TagColumns:        append([]string(nil), s.Spec.TagColumns...),
+		FieldFn:           s.Spec.FieldFn.Copy(),
+	}
+	return ns
+}
+
+func createToTransformation(id execute.DatasetID, mode execute.AccumulationMode, spec plan.ProcedureSpec, a execute.Administration) (execute.Transformation, execute.Dataset, error) {
+	s, ok := spec.(*ToProcedureSpec)
+	if !ok {
+		return nil, nil, &flux.Error{
+			Code: codes.Internal,
+			Msg:  fmt.Sprintf("invalid spec type %T", spec),
+		}
+	}
+	cache := execute.NewTableBuilderCache(a.Allocator())
+	d := execute.NewDataset(id, mode, cache)
+	deps := GetStorageDependencies(a.Context())
+	t, err := NewToTransformation(a.Context(), d, cache, s, deps)
+	if err != nil {
+		return nil, nil, err
+	}
+	return t, d, nil
+}
+
+// ToTransformation writes each table it processes to a database as points
+// and passes the table on unchanged.
+type ToTransformation struct {
+	ctx   context.Context
+	d     execute.Dataset
+	cache execute.TableBuilderCache
+	spec  *ToOpSpec
+	deps  StorageDependencies
+	user  meta.User
+
+	database        string
+	retentionPolicy string
+
+	// authorizeSeries is set if the user may only write to some of the
+	// measurements of the database.
+	authorizeSeries bool
+
+	fn     *execute.RowMapFn
+	points []models.Point
+
+	// n and unauthorized count the points written and the points skipped
+	// because the user may not write to their measurement.
+	n            int
+	unauthorized int
+}
+
+// NewToTransformation returns a ToTransformation that writes to the bucket
+// of spec. The user of ctx must be permitted to write to the database, or to
+// the measurements of the points written, when authentication is enabled.
+// Points the user may not write are skipped, like the write endpoint does,
+// and reported as a partial write when the transformation finishes.
+func NewToTransformation(ctx context.Context, d execute.Dataset, cache execute.TableBuilderCache, spec *ToProcedureSpec, deps StorageDependencies) (*ToTransformation, error) {
+	if deps.PointsWriter == nil {
+		return nil, errors.New("missing points writer dependency")
+	}
+
+	t := &ToTransformation{
+		ctx:   ctx,
+		d:     d,
+		cache: cache,
+		spec:  spec.Spec,
+		deps:  deps,
+	}
+
+	var db, rp string
+	if i := strings.IndexByte(spec.Spec.Bucket, '/'); i == -1 {
+		db = spec.Spec.Bucket
+	} else {
+		rp = spec.Spec.Bucket[i+1:]
+		db = spec.Spec.Bucket[:i]
+	}
+
+	// validate and resolve db/rp
+	di := deps.MetaClient.Database(db)
+	if di == nil {
+		return nil, fmt.Errorf("database %q of bucket %q not found", db, spec.Spec.Bucket)
+	}
+
+	if deps.AuthEnabled {
+		t.user = meta.UserFromContext(ctx)
+		if t.user == nil {
+			return nil, &meta.ErrAuthorize{
+				Database: db,
+				Message:  fmt.Sprintf("no user provided to write to database %q", db),
+			}
+		}
+		if err := deps.Authorizer.AuthorizeWrite(t.user, db); err != nil {
+			return nil, err
+		}
+		t.authorizeSeries = !t.user.AuthorizeDatabase(influxql.WritePrivilege, db)
+	}
+
+	if rp == "" {
+		rp = di.DefaultRetentionPolicy
+	}
+
+	if rpi := di.RetentionPolicy(rp); rpi == nil {
+		return nil, fmt.Errorf("retention policy %q not found on database %q", rp, db)
+	}
+	t.database, t.retentionPolicy = db, rp
+
+	if spec.Spec.FieldFn.Fn != nil {
+		fn, err := execute.NewRowMapFn(spec.Spec.FieldFn.Fn, compiler.ToScope(spec.Spec.FieldFn.Scope))
+		if err != nil {
+			return nil, err
+		}
+		t.fn = fn
+	}
+	return t, nil
+}
+
+func (t *ToTransformation) RetractTable(id execute.DatasetID, key flux.GroupKey) error {
+	return t.d.RetractTable(key)
+}
+
+func (t *ToTransformation) Process(id execute.DatasetID, tbl flux.Table) error {
+	builder, created := t.cache.TableBuilder(tbl.Key())
+	if !created {
+		return fmt.Errorf("to found duplicate table with key: %v", tbl.Key())
+	}
+	if err := execute.AddTableCols(tbl, builder); err != nil {
+		return err
+	}
+
+	cols := tbl.Cols()
+	timeIdx := execute.ColIdx(t.spec.TimeColumn, cols)
+	if timeIdx < 0 || cols[timeIdx].Type != flux.TTime {
+		return fmt.Errorf("no time column %q", t.spec.TimeColumn)
+	}
+	measurementIdx := execute.ColIdx(t.spec.MeasurementColumn, cols)
+	if measurementIdx < 0 || cols[measurementIdx].Type != flux.TString {
+		return fmt.Errorf("no measurement column %q", t.spec.MeasurementColumn)
+	}
+
+	fieldIdx, valueIdx := -1, -1
+	if t.fn != nil {
+		if err := t.fn.Prepare(cols); err != nil {
+			return err
+		}
+	} else {
+		fieldIdx = execute.ColIdx("_field", cols)
+		valueIdx = execute.ColIdx(execute.DefaultValueColLabel, cols)
+		if fieldIdx < 0 || cols[fieldIdx].Type != flux.TString || valueIdx < 0 {
+			return errors.New("to requires _field and _value columns when fieldFn is not specified")
+		}
+	}
+
+	tagIdx, err := t.tagColumns(cols)
+	if err != nil {
+		return err
+	}
+
+	return tbl.Do(func(cr flux.ColReader) error {
+		if err := execute.AppendCols(cr, builder); err != nil {
+			return err
+		}
+
+		for i := 0; i < cr.Len(); i++ {
+			if cr.Times(timeIdx).IsNull(i) || cr.Strings(measurementIdx).IsNull(i) {
+				continue
+			}
+
+			fields := make(models.Fields)
+			if t.fn != nil {
+				obj, err := t.fn.Eval(t.ctx, i, cr)
+				if err != nil {
+					return err
+				}
+				obj.Range(func(k string, v values.Value) {
+					if err == nil && !v.IsNull() {
+						fields[k], err = fieldValue(v)
+					}
+				})
+				if err != nil {
+					return err
+				}
+			} else if v := execute.ValueForRow(cr, i, valueIdx); !v.IsNull() && !cr.Strings(fieldIdx).IsNull(i) {
+				if fields[cr.Strings(fieldIdx).ValueString(i)], err = fieldValue(v); err != nil {
+					return err
+				}
+			}
+			if len(fields) == 0 {
+				continue
+			}
+
+			tags := make(map[string]string, len(tagIdx))
+			for _, j := range tagIdx {
+				if v := cr.Strings(j); !v.IsNull(i) && v.ValueString(i) != "" {
+					tags[cols[j].Label] = v.ValueString(i)
+				}
+			}
+
+			pt, err := models.NewPoint(
+				cr.Strings(measurementIdx).ValueString(i),
+				models.NewTags(tags),
+				fields,
+				time.Unix(0, cr.Times(timeIdx).Value(i)),
+			)
+			if err != nil {
+				return err
+			}
+			if t.authorizeSeries && !t.user.AuthorizeSeriesWrite(t.database, pt.Name(), pt.Tags()) {
+				t.unauthorized++
+				continue
+			}
+			t.points = append(t.points, pt)
+			if len(t.points) >= DefaultToBatchSize {
+				if err := t.flush(); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// tagColumns returns the indexes of the tag columns of cols. If tagColumns
+// was not specified, the tags are all string columns other than the
+// measurement, field, value, window bounds and fieldFn columns.
+func (t *ToTransformation) tagColumns(cols []flux.ColMeta) ([]int, error) {
+	var idx []int
+	if len(t.spec.TagColumns) > 0 {
+		for _, label := range t.spec.TagColumns {
+			j := execute.ColIdx(label, cols)
+			if j < 0 {
+				return nil, fmt.Errorf("no tag column %q", label)
+			} else if cols[j].Type != flux.TString {
+				return nil, fmt.Errorf("tag column %q is not of type string", label)
+			}
+			idx = append(idx, j)
+		}
+		return idx, nil
+	}
+
+	exclude := map[string]bool{
+		t.spec.MeasurementColumn:     true,
+		"_field":                     true,
+		execute.DefaultValueColLabel: true,
+		execute.DefaultStartColLabel: true,
+		execute.DefaultStopColLabel:  true,
+	}
+	if t.fn != nil {
+		for k := range t.fn.Type().Properties() {
+			exclude[k] = true
+		}
+	}
+	for j, c := range cols {
+		if c.Type == flux.TString && !exclude[c.Label] {
+			idx = append(idx, j)
+		}
+	}
+	return idx, nil
+}
+
+// fieldValue returns the field value for v.
+func fieldValue(v values.Value) (interface{}, error) {
+	switch v.Type().Nature() {
+	case semantic.Float:
+		return v.Float(), nil
+	case semantic.Int:
+		return v.Int(), nil
+	case semantic.UInt:
+		return v.UInt(), nil
+	case semantic.String:
+		return v.Str(), nil
+	case semantic.Bool:
+		return v.Bool(), nil
+	default:
+		return nil, fmt.Errorf("unsupported field type %v", v.Type())
+	}
+}
+
+// flush writes the buffered points.
+func (t *ToTransformation) flush() error {
+	if len(t.points) == 0 {
+		return nil
+	}
+	err := t.deps.PointsWriter.WritePoints(t.database, t.retentionPolicy, models.ConsistencyLevelAny, t.user, t.points)
+	t.n += len(t.points)
+	t.points = t.points[:0]
+	return err
+}
+
+func (t *ToTransformation) UpdateWatermark(id execute.DatasetID, pt execute.Time) error {
+	return t.d.UpdateWatermark(pt)
+}
+
+func (t *ToTransformation) UpdateProcessingTime(id execute.DatasetID, pt execute.Time) error {
+	return t.d.UpdateProcessingTime(pt)
+}
+
+func (t *ToTransformation) Finish(id execute.DatasetID, err error) {
+	if err == nil {
+		err = t.flush()
+	}
+	if err == nil {
+		err = t.unauthorizedError()
+	}
+	t.d.Finish(err)
+}
+
+// unauthorizedError returns the error reporting the points skipped because
+// the user may not write to their measurement, or nil if none were.
+func (t *ToTransformation) unauthorizedError() error {
+	if t.unauthorized == 0 {
+		return nil
+	} else if t.n == 0 {
+		return fmt.Errorf("%q user is not authorized to write to the measurements of database %q", t.user.ID(), t.database)
+	}
+	return tsdb.PartialWriteError{
+		Reason:  fmt.Sprintf("%q user is not authorized to write to measurement", t.user.ID()),
+		Dropped: t.unauthorized,
+	}
+}
@@ -1 +1,348 @@
 package influxdb_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/ayang64/reflux/flux/stdlib/influxdata/influxdb"
+	"github.com/ayang64/reflux/influxql"
+	"github.com/ayang64/reflux/models"
+	"github.com/ayang64/reflux/services/meta"
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/execute/executetest"
+	"github.com/influxdata/flux/plan"
+)
+
+type pointsWriter struct {
+	WritePointsFn func(database, retentionPolicy string, consistencyLevel models.ConsistencyLevel, user meta.User, points []models.Point) error
+}
+
+func (w *pointsWriter) WritePoints(database, retentionPolicy string, consistencyLevel models.ConsistencyLevel, user meta.User, points []models.Point) error {
+	return w.WritePointsFn(database, retentionPolicy, consistencyLevel, user, points)
+}
+
+type metaClient struct {
+	databases []meta.DatabaseInfo
+}
+
+func (c *metaClient) Databases() []meta.DatabaseInfo { return c.databases }
+
+func (c *metaClient) Database(name string) *meta.DatabaseInfo {
+	for i := range c.databases {
+		if c.databases[i].Name == name {
+			return &c.databases[i]
+		}
+	}
+	return nil
+}
+
+type authorizer struct {
+	AuthorizeDatabaseFn func(u meta.User, priv influxql.Privilege, database string) error
+	AuthorizeWriteFn    func(u meta.User, database string) error
+}
+
+func (a *authorizer) AuthorizeDatabase(u meta.User, priv influxql.Privilege, database string) error {
+	return a.AuthorizeDatabaseFn(u, priv, database)
+}
+
+func (a *authorizer) AuthorizeWrite(u meta.User, database string) error {
+	return a.AuthorizeWriteFn(u, database)
+}
+
+// measurementUser is a user that may only write to one measurement.
+type measurementUser struct {
+	meta.UserInfo
+	measurement string
+}
+
+func (u *measurementUser) AuthorizeDatabase(p influxql.Privilege, name string) bool { return false }
+
+func (u *measurementUser) AuthorizeSeriesWrite(database string, measurement []byte, tags models.Tags) bool {
+	return string(measurement) == u.measurement
+}
+
+func newToMetaClient() *metaClient {
+	return &metaClient{databases: []meta.DatabaseInfo{{
+		Name:                   "db0",
+		DefaultRetentionPolicy: "autogen",
+		RetentionPolicies: []meta.RetentionPolicyInfo{
+			{Name: "autogen"},
+			{Name: "rp0"},
+		},
+	}}}
+}
+
+// newToInput returns a table of cpu usage with a null value.
+func newToInput() *executetest.Table {
+	return &executetest.Table{
+		KeyCols: []string{"_measurement", "_field", "host"},
+		ColMeta: []flux.ColMeta{
+			{Label: "_start", Type: flux.TTime},
+			{Label: "_stop", Type: flux.TTime},
+			{Label: "_time", Type: flux.TTime},
+			{Label: "_measurement", Type: flux.TString},
+			{Label: "_field", Type: flux.TString},
+			{Label: "host", Type: flux.TString},
+			{Label: "_value", Type: flux.TFloat},
+		},
+		Data: [][]interface{}{
+			{execute.Time(0), execute.Time(100), execute.Time(10), "cpu", "usage", "a", 1.5},
+			{execute.Time(0), execute.Time(100), execute.Time(20), "cpu", "usage", "a", nil},
+			{execute.Time(0), execute.Time(100), execute.Time(30), "cpu", "usage", "a", 2.5},
+		},
+	}
+}
+
+func TestToTransformation_Process(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		bucket string
+		expRP  string
+	}{
+		{name: "default rp", bucket: "db0", expRP: "autogen"},
+		{name: "explicit rp", bucket: "db0/rp0", expRP: "rp0"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotDB, gotRP string
+			var got []string
+			deps := influxdb.StorageDependencies{
+				PointsWriter: &pointsWriter{
+					WritePointsFn: func(database, retentionPolicy string, _ models.ConsistencyLevel, _ meta.User, points []models.Point) error {
+						gotDB, gotRP = database, retentionPolicy
+						for _, p := range points {
+							got = append(got, p.String())
+						}
+						return nil
+					},
+				},
+				MetaClient: newToMetaClient(),
+			}
+
+			id := executetest.RandomDatasetID()
+			cache := execute.NewTableBuilderCache(executetest.UnlimitedAllocator)
+			cache.SetTriggerSpec(plan.DefaultTriggerSpec)
+			d := execute.NewDataset(id, execute.DiscardingMode, cache)
+			spec := &influxdb.ToProcedureSpec{Spec: &influxdb.ToOpSpec{
+				Bucket:            tt.bucket,
+				TimeColumn:        "_time",
+				MeasurementColumn: "_measurement",
+			}}
+			tr, err := influxdb.NewToTransformation(context.Background(), d, cache, spec, deps)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if err := tr.Process(id, newToInput()); err != nil {
+				t.Fatal(err)
+			}
+
+			// The input tables must be passed through unchanged.
+			tables, err := executetest.TablesFromCache(cache)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(tables) != 1 || len(tables[0].Data) != 3 {
+				t.Errorf("unexpected output tables: %v", tables)
+			}
+			tr.Finish(id, nil)
+
+			if gotDB != "db0" || gotRP != tt.expRP {
+				t.Errorf("unexpected destination: got=%s/%s exp=db0/%s", gotDB, gotRP, tt.expRP)
+			}
+			exp := []string{
+				"cpu,host=a usage=1.5 10",
+				"cpu,host=a usage=2.5 30",
+			}
+			if !reflect.DeepEqual(got, exp) {
+				t.Errorf("unexpected points: got=%v exp=%v", got, exp)
+			}
+		})
+	}
+}
+
+func TestToTransformation_TagColumns(t *testing.T) {
+	input := &executetest.Table{
+		ColMeta: []flux.ColMeta{
+			{Label: "_time", Type: flux.TTime},
+			{Label: "_measurement", Type: flux.TString},
+			{Label: "_field", Type: flux.TString},
+			{Label: "host", Type: flux.TString},
+			{Label: "region", Type: flux.TString},
+			{Label: "_value", Type: flux.TInt},
+		},
+		Data: [][]interface{}{
+			{execute.Time(10), "mem", "used", "a", "west", int64(7)},
+		},
+	}
+
+	var got []string
+	deps := influxdb.StorageDependencies{
+		PointsWriter: &pointsWriter{
+			WritePointsFn: func(_, _ string, _ models.ConsistencyLevel, _ meta.User, points []models.Point) error {
+				for _, p := range points {
+					got = append(got, p.String())
+				}
+				return nil
+			},
+		},
+		MetaClient: newToMetaClient(),
+	}
+
+	id := executetest.RandomDatasetID()
+	cache := execute.NewTableBuilderCache(executetest.UnlimitedAllocator)
+	cache.SetTriggerSpec(plan.DefaultTriggerSpec)
+	d := execute.NewDataset(id, execute.DiscardingMode, cache)
+	spec := &influxdb.ToProcedureSpec{Spec: &influxdb.ToOpSpec{
+		Bucket:            "db0",
+		TimeColumn:        "_time",
+		MeasurementColumn: "_measurement",
+		TagColumns:        []string{"region"},
+	}}
+	tr, err := influxdb.NewToTransformation(context.Background(), d, cache, spec, deps)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tr.Process(id, input); err != nil {
+		t.Fatal(err)
+	}
+	tr.Finish(id, nil)
+
+	if exp := []string{"mem,region=west used=7i 10"}; !reflect.DeepEqual(got, exp) {
+		t.Errorf("unexpected points: got=%v exp=%v", got, exp)
+	}
+}
+
+func TestNewToTransformation_Unauthorized(t *testing.T) {
+	user := &meta.UserInfo{Name: "bob"}
+	deps := influxdb.StorageDependencies{
+		PointsWriter: &pointsWriter{
+			WritePointsFn: func(_, _ string, _ models.ConsistencyLevel, _ meta.User, _ []models.Point) error {
+				t.Fatal("unexpected write")
+				return nil
+			},
+		},
+		MetaClient: newToMetaClient(),
+		Authorizer: &authorizer{
+			AuthorizeWriteFn: func(u meta.User, database string) error {
+				if u.ID() != "bob" || database != "db0" {
+					t.Errorf("unexpected authorization: user=%s database=%s", u.ID(), database)
+				}
+				return errors.New("permission denied")
+			},
+		},
+		AuthEnabled: true,
+	}
+
+	cache := execute.NewTableBuilderCache(executetest.UnlimitedAllocator)
+	cache.SetTriggerSpec(plan.DefaultTriggerSpec)
+	d := execute.NewDataset(executetest.RandomDatasetID(), execute.DiscardingMode, cache)
+	spec := &influxdb.ToProcedureSpec{Spec: &influxdb.ToOpSpec{Bucket: "db0"}}
+
+	ctx := meta.NewContextWithUser(context.Background(), user)
+	if _, err := influxdb.NewToTransformation(ctx, d, cache, spec, deps); err == nil || err.Error() != "permission denied" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, err := influxdb.NewToTransformation(context.Background(), d, cache, spec, deps)
+	if _, ok := err.(*meta.ErrAuthorize); !ok {
+		t.Fatalf("unexpected error without a user: %v", err)
+	} else if exp := `no user provided to write to database "db0"`; err.Error() != exp {
+		t.Fatalf("unexpected error without a user: got %s, exp %s", err, exp)
+	}
+}
+
+// Ensure the errors for unknown databases and retention policies name them.
+func TestNewToTransformation_NotFound(t *testing.T) {
+	deps := influxdb.StorageDependencies{
+		PointsWriter: &pointsWriter{},
+		MetaClient:   newToMetaClient(),
+	}
+	cache := execute.NewTableBuilderCache(executetest.UnlimitedAllocator)
+	cache.SetTriggerSpec(plan.DefaultTriggerSpec)
+	d := execute.NewDataset(executetest.RandomDatasetID(), execute.DiscardingMode, cache)
+
+	for _, tt := range []struct {
+		bucket string
+		err    string
+	}{
+		{bucket: "db1", err: `database "db1" of bucket "db1" not found`},
+		{bucket: "db1/rp0", err: `database "db1" of bucket "db1/rp0" not found`},
+		{bucket: "db0/rp1", err: `retention policy "rp1" not found on database "db0"`},
+	} {
+		spec := &influxdb.ToProcedureSpec{Spec: &influxdb.ToOpSpec{Bucket: tt.bucket}}
+		if _, err := influxdb.NewToTransformation(context.Background(), d, cache, spec, deps); err == nil || err.Error() != tt.err {
+			t.Errorf("%s: unexpected error: got %v, exp %s", tt.bucket, err, tt.err)
+		}
+	}
+}
+
+// Ensure a user that may only write to some measurements can only write
+// points to those measurements.
+func TestToTransformation_MeasurementPrivileges(t *testing.T) {
+	for _, tt := range []struct {
+		measurement string
+		err         string
+		n           int
+	}{
+		{measurement: "cpu", n: 2},
+		{measurement: "mem", err: `"bob" user is not authorized to write to the measurements of database "db0"`},
+		{measurement: "disk", n: 1, err: `partial write: "bob" user is not authorized to write to measurement dropped=2`},
+	} {
+		var n int
+		deps := influxdb.StorageDependencies{
+			PointsWriter: &pointsWriter{
+				WritePointsFn: func(_, _ string, _ models.ConsistencyLevel, _ meta.User, points []models.Point) error {
+					n += len(points)
+					return nil
+				},
+			},
+			MetaClient: newToMetaClient(),
+			Authorizer: &authorizer{
+				AuthorizeWriteFn: func(u meta.User, database string) error { return nil },
+			},
+			AuthEnabled: true,
+		}
+
+		id := executetest.RandomDatasetID()
+		cache := execute.NewTableBuilderCache(executetest.UnlimitedAllocator)
+		cache.SetTriggerSpec(plan.DefaultTriggerSpec)
+		d := execute.NewDataset(id, execute.DiscardingMode, cache)
+		store := executetest.NewDataStore()
+		d.AddTransformation(store)
+		spec := &influxdb.ToProcedureSpec{Spec: &influxdb.ToOpSpec{
+			Bucket:            "db0",
+			TimeColumn:        "_time",
+			MeasurementColumn: "_measurement",
+		}}
+
+		user := &measurementUser{UserInfo: meta.UserInfo{Name: "bob"}, measurement: tt.measurement}
+		ctx := meta.NewContextWithUser(context.Background(), user)
+		tr, err := influxdb.NewToTransformation(ctx, d, cache, spec, deps)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// The points of other measurements are skipped, not failed, so that
+		// nothing is left half written when Process returns.
+		input := newToInput()
+		if tt.measurement == "disk" {
+			input.Data = append(input.Data, []interface{}{execute.Time(0), execute.Time(100), execute.Time(40), "disk", "usage", "a", 3.5})
+		}
+		if err := tr.Process(id, input); err != nil {
+			t.Fatalf("%s: unexpected error: %s", tt.measurement, err)
+		}
+		tr.Finish(id, nil)
+
+		if err := store.Err(); tt.err == "" && err != nil {
+			t.Fatalf("%s: unexpected error: %s", tt.measurement, err)
+		} else if tt.err != "" && (err == nil || err.Error() != tt.err) {
+			t.Fatalf("%s: unexpected error: got %v, exp %s", tt.measurement, err, tt.err)
+		}
+		if n != tt.n {
+			t.Fatalf("%s: unexpected points written: got %d, exp %d", tt.measurement, n, tt.n)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/interpreter"
+	"github.com/influxdata/flux/parser"
+	"github.com/influxdata/flux/semantic"
+	"github.com/influxdata/flux/values"
+
+	_ "github.com/ayang64/reflux/flux/stdlib/influxdata/influxdb/schema"
+)
+
+// schemaFunctions are the functions of influxdata/influxdb/schema that the
+// upstream v1 package lacks.
+var schemaFunctions = []string{"fieldKeys", "measurementFieldKeys"}
+
+// extendPackagePath is the internal package that adds schemaFunctions to
+// influxdata/influxdb/v1.
+const extendPackagePath = "influxdata/influxdb/v1/internal/extend"
+
+// extendSource passes the v1 package and the schema functions to
+// setSchemaFunctions. Upstream Flux only accepts the builtin values declared
+// by the v1 source, so the functions are set on the evaluated package instead:
+// builtin packages are evaluated with the packages they import, which are the
+// ones every script importing influxdata/influxdb/v1 is given a copy of.
+const extendSource = `package extend
+
+import "influxdata/influxdb/v1"
+import "influxdata/influxdb/schema"
+
+builtin setSchemaFunctions
+
+setSchemaFunctions(v1: v1, fieldKeys: schema.fieldKeys, measurementFieldKeys: schema.measurementFieldKeys)
+`
+
+func init() {
+	pkg := parser.ParseSource(extendSource)
+	pkg.Path = extendPackagePath
+	flux.RegisterPackage(pkg)
+
+	params := map[string]semantic.PolyType{"v1": semantic.Tvar(1)}
+	for i, name := range schemaFunctions {
+		params[name] = semantic.Tvar(i + 2)
+	}
+	flux.RegisterPackageValue(extendPackagePath, "setSchemaFunctions", values.NewFunction(
+		"setSchemaFunctions",
+		semantic.NewFunctionPolyType(semantic.FunctionPolySignature{
+			Parameters: params,
+			Required:   semantic.LabelSet(append([]string{"v1"}, schemaFunctions...)),
+			Return:     semantic.Bool,
+		}),
+		setSchemaFunctions,
+		false,
+	))
+}
+
+// setSchemaFunctions sets the schema functions of args on the v1 package.
+func setSchemaFunctions(ctx context.Context, args values.Object) (values.Value, error) {
+	v, _ := args.Get("v1")
+	pkg, ok := v.(*interpreter.Package)
+	if !ok {
+		return nil, fmt.Errorf("v1 is not a package: %T", v)
+	}
+	for _, name := range schemaFunctions {
+		fn, ok := args.Get(name)
+		if !ok {
+			return nil, fmt.Errorf("missing schema function %q", name)
+		}
+		pkg.Set(name, fn)
+	}
+	return values.NewBool(true), nil
+}
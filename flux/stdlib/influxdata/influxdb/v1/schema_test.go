@@ -0,0 +1,60 @@
+package v1_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ayang64/reflux/flux/builtin"
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/semantic"
+)
+
+func TestSchemaFunctions(t *testing.T) {
+	builtin.Initialize()
+
+	pkg, ok := flux.StdLib().ImportPackageObject("influxdata/influxdb/v1")
+	if !ok {
+		t.Fatal("influxdata/influxdb/v1 is not registered")
+	}
+	for _, name := range []string{"databases", "fieldKeys", "measurementFieldKeys", "tagValues"} {
+		v, ok := pkg.Get(name)
+		if !ok {
+			t.Errorf("v1.%s is not registered", name)
+		} else if got := v.PolyType().Nature(); got != semantic.Function {
+			t.Errorf("v1.%s has unexpected type %s", name, got)
+		}
+	}
+
+	for _, script := range []string{
+		`import "influxdata/influxdb/v1"
+v1.fieldKeys(bucket: "db0")`,
+		`import "influxdata/influxdb/v1"
+v1.measurementFieldKeys(bucket: "db0", measurement: "cpu")`,
+	} {
+		ses, _, err := flux.Eval(context.Background(), script)
+		if err != nil {
+			t.Fatalf("%s: %s", script, err)
+		} else if len(ses) != 1 {
+			t.Fatalf("%s: unexpected side effects: %d", script, len(ses))
+		}
+
+		// The functions read the distinct _field values of the bucket.
+		to, ok := ses[0].Value.(*flux.TableObject)
+		if !ok {
+			t.Fatalf("%s: unexpected value %T", script, ses[0].Value)
+		}
+		var kinds []flux.OperationKind
+		for to != nil {
+			kinds = append(kinds, to.Kind)
+			if to.Parents.Len() == 0 {
+				break
+			}
+			to = to.Parents.Get(0).(*flux.TableObject)
+		}
+		if got, exp := kinds[0], flux.OperationKind("distinct"); got != exp {
+			t.Errorf("%s: unexpected operation: got %s, exp %s", script, got, exp)
+		} else if got, exp := kinds[len(kinds)-1], flux.OperationKind("influxDBFrom"); got != exp {
+			t.Errorf("%s: unexpected source: got %s, exp %s", script, got, exp)
+		}
+	}
+}
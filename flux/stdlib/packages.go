@@ -3,5 +3,6 @@ package stdlib
 // Import all stdlib packages
 import (
 	_ "github.com/ayang64/reflux/flux/stdlib/influxdata/influxdb"
+	_ "github.com/ayang64/reflux/flux/stdlib/influxdata/influxdb/schema"
 	_ "github.com/ayang64/reflux/flux/stdlib/influxdata/influxdb/v1"
 )
@@ -207,19 +207,32 @@ type Node interface {
 func (*Query) node()     {}
 func (Statements) node() {}
 
+func (*AlterFieldTypeStatement) node()             {}
 func (*AlterRetentionPolicyStatement) node()       {}
+func (*BackfillContinuousQueryStatement) node()    {}
 func (*CreateContinuousQueryStatement) node()      {}
 func (*CreateDatabaseStatement) node()             {}
+func (*CreateDownsamplePolicyStatement) node()     {}
+func (*CreateMeasurementSchemaStatement) node()    {}
 func (*CreateRetentionPolicyStatement) node()      {}
+func (*CreateRoleStatement) node()                 {}
+func (*CreateSecurityPolicyStatement) node()       {}
 func (*CreateSubscriptionStatement) node()         {}
+func (*CreateTokenStatement) node()                {}
 func (*CreateUserStatement) node()                 {}
 func (*Distinct) node()                            {}
 func (*DeleteSeriesStatement) node()               {}
 func (*DeleteStatement) node()                     {}
 func (*DropContinuousQueryStatement) node()        {}
 func (*DropDatabaseStatement) node()               {}
+func (*DropDownsamplePolicyStatement) node()       {}
+func (*DropFieldStatement) node()                  {}
+func (*DropMeasurementSchemaStatement) node()      {}
 func (*DropMeasurementStatement) node()            {}
+func (*DropQuotaStatement) node()                  {}
 func (*DropRetentionPolicyStatement) node()        {}
+func (*DropRoleStatement) node()                   {}
+func (*DropSecurityPolicyStatement) node()         {}
 func (*DropSeriesStatement) node()                 {}
 func (*DropShardStatement) node()                  {}
 func (*DropSubscriptionStatement) node()           {}
@@ -227,20 +240,33 @@ func (*DropUserStatement) node()                   {}
 func (*ExplainStatement) node()                    {}
 func (*GrantStatement) node()                      {}
 func (*GrantAdminStatement) node()                 {}
+func (*GrantRoleStatement) node()                  {}
 func (*KillQueryStatement) node()                  {}
+func (*KillTaskStatement) node()                   {}
 func (*RevokeStatement) node()                     {}
 func (*RevokeAdminStatement) node()                {}
+func (*RevokeRoleStatement) node()                 {}
+func (*RevokeTokenStatement) node()                {}
 func (*SelectStatement) node()                     {}
 func (*SetPasswordUserStatement) node()            {}
+func (*SetQuotaStatement) node()                   {}
+func (*ShowAuditLogStatement) node()               {}
 func (*ShowContinuousQueriesStatement) node()      {}
 func (*ShowGrantsForUserStatement) node()          {}
 func (*ShowDatabasesStatement) node()              {}
+func (*ShowDownsamplePoliciesStatement) node()     {}
 func (*ShowFieldKeyCardinalityStatement) node()    {}
 func (*ShowFieldKeysStatement) node()              {}
 func (*ShowRetentionPoliciesStatement) node()      {}
+func (*ShowRolesStatement) node()                  {}
+func (*ShowSecurityPoliciesStatement) node()       {}
 func (*ShowMeasurementCardinalityStatement) node() {}
+func (*ShowMeasurementSchemasStatement) node()     {}
 func (*ShowMeasurementsStatement) node()           {}
 func (*ShowQueriesStatement) node()                {}
+func (*ShowQuotasStatement) node()                 {}
+func (*ShowTasksStatement) node()                  {}
+func (*ShowTokensStatement) node()                 {}
 func (*ShowSeriesStatement) node()                 {}
 func (*ShowSeriesCardinalityStatement) node()      {}
 func (*ShowShardGroupsStatement) node()            {}
@@ -258,6 +284,7 @@ func (*BinaryExpr) node()      {}
 func (*BooleanLiteral) node()  {}
 func (*BoundParameter) node()  {}
 func (*Call) node()            {}
+func (*CaseExpr) node()        {}
 func (*Dimension) node()       {}
 func (Dimensions) node()       {}
 func (*DurationLiteral) node() {}
@@ -265,6 +292,7 @@ func (*IntegerLiteral) node()  {}
 func (*UnsignedLiteral) node() {}
 func (*Field) node()           {}
 func (Fields) node()           {}
+func (*Join) node()            {}
 func (*Measurement) node()     {}
 func (Measurements) node()     {}
 func (*NilLiteral) node()      {}
@@ -336,34 +364,57 @@ type ExecutionPrivilege struct {
 // ExecutionPrivileges is a list of privileges required to execute a statement.
 type ExecutionPrivileges []ExecutionPrivilege
 
+func (*AlterFieldTypeStatement) stmt()             {}
 func (*AlterRetentionPolicyStatement) stmt()       {}
+func (*BackfillContinuousQueryStatement) stmt()    {}
 func (*CreateContinuousQueryStatement) stmt()      {}
 func (*CreateDatabaseStatement) stmt()             {}
+func (*CreateDownsamplePolicyStatement) stmt()     {}
+func (*CreateMeasurementSchemaStatement) stmt()    {}
 func (*CreateRetentionPolicyStatement) stmt()      {}
+func (*CreateRoleStatement) stmt()                 {}
+func (*CreateSecurityPolicyStatement) stmt()       {}
 func (*CreateSubscriptionStatement) stmt()         {}
+func (*CreateTokenStatement) stmt()                {}
 func (*CreateUserStatement) stmt()                 {}
 func (*DeleteSeriesStatement) stmt()               {}
 func (*DeleteStatement) stmt()                     {}
 func (*DropContinuousQueryStatement) stmt()        {}
 func (*DropDatabaseStatement) stmt()               {}
+func (*DropDownsamplePolicyStatement) stmt()       {}
+func (*DropFieldStatement) stmt()                  {}
+func (*DropMeasurementSchemaStatement) stmt()      {}
 func (*DropMeasurementStatement) stmt()            {}
+func (*DropQuotaStatement) stmt()                  {}
 func (*DropRetentionPolicyStatement) stmt()        {}
+func (*DropRoleStatement) stmt()                   {}
+func (*DropSecurityPolicyStatement) stmt()         {}
 func (*DropSeriesStatement) stmt()                 {}
 func (*DropSubscriptionStatement) stmt()           {}
 func (*DropUserStatement) stmt()                   {}
 func (*ExplainStatement) stmt()                    {}
 func (*GrantStatement) stmt()                      {}
 func (*GrantAdminStatement) stmt()                 {}
+func (*GrantRoleStatement) stmt()                  {}
 func (*KillQueryStatement) stmt()                  {}
+func (*KillTaskStatement) stmt()                   {}
+func (*ShowAuditLogStatement) stmt()               {}
 func (*ShowContinuousQueriesStatement) stmt()      {}
 func (*ShowGrantsForUserStatement) stmt()          {}
 func (*ShowDatabasesStatement) stmt()              {}
+func (*ShowDownsamplePoliciesStatement) stmt()     {}
 func (*ShowFieldKeyCardinalityStatement) stmt()    {}
 func (*ShowFieldKeysStatement) stmt()              {}
 func (*ShowMeasurementCardinalityStatement) stmt() {}
+func (*ShowMeasurementSchemasStatement) stmt()     {}
 func (*ShowMeasurementsStatement) stmt()           {}
 func (*ShowQueriesStatement) stmt()                {}
+func (*ShowQuotasStatement) stmt()                 {}
+func (*ShowTasksStatement) stmt()                  {}
+func (*ShowTokensStatement) stmt()                 {}
 func (*ShowRetentionPoliciesStatement) stmt()      {}
+func (*ShowRolesStatement) stmt()                  {}
+func (*ShowSecurityPoliciesStatement) stmt()       {}
 func (*ShowSeriesStatement) stmt()                 {}
 func (*ShowSeriesCardinalityStatement) stmt()      {}
 func (*ShowShardGroupsStatement) stmt()            {}
@@ -379,8 +430,11 @@ func (*ShowTagValuesStatement) stmt()              {}
 func (*ShowUsersStatement) stmt()                  {}
 func (*RevokeStatement) stmt()                     {}
 func (*RevokeAdminStatement) stmt()                {}
+func (*RevokeRoleStatement) stmt()                 {}
+func (*RevokeTokenStatement) stmt()                {}
 func (*SelectStatement) stmt()                     {}
 func (*SetPasswordUserStatement) stmt()            {}
+func (*SetQuotaStatement) stmt()                   {}
 
 // Expr represents an expression that can be evaluated to a value.
 type Expr interface {
@@ -394,6 +448,7 @@ func (*BinaryExpr) expr()      {}
 func (*BooleanLiteral) expr()  {}
 func (*BoundParameter) expr()  {}
 func (*Call) expr()            {}
+func (*CaseExpr) expr()        {}
 func (*Distinct) expr()        {}
 func (*DurationLiteral) expr() {}
 func (*IntegerLiteral) expr()  {}
@@ -436,6 +491,7 @@ type Source interface {
 	source()
 }
 
+func (*Join) source()        {}
 func (*Measurement) source() {}
 func (*SubQuery) source()    {}
 
@@ -466,6 +522,8 @@ func (a Sources) Measurements() []*Measurement {
 			mms = append(mms, src)
 		case *SubQuery:
 			mms = append(mms, src.Statement.Sources.Measurements()...)
+		case *Join:
+			mms = append(mms, Sources{src.Left, src.Right}.Measurements()...)
 		}
 	}
 	return mms
@@ -514,6 +572,12 @@ func (a Sources) RequiredPrivileges() (ExecutionPrivileges, error) {
 				return nil, err
 			}
 			ep = append(ep, privs...)
+		case *Join:
+			privs, err := Sources{source.Left, source.Right}.RequiredPrivileges()
+			if err != nil {
+				return nil, err
+			}
+			ep = append(ep, privs...)
 		default:
 			return nil, fmt.Errorf("invalid source: %s", source)
 		}
@@ -646,6 +710,25 @@ func (s *DropDatabaseStatement) RequiredPrivileges() (ExecutionPrivileges, error
 	return ExecutionPrivileges{{Admin: true, Name: "", Privilege: AllPrivileges}}, nil
 }
 
+// DropQuotaStatement represents a command to drop the quota of a database.
+type DropQuotaStatement struct {
+	// Name of the database to drop the quota from.
+	Database string
+}
+
+// String returns a string representation of the drop quota statement.
+func (s *DropQuotaStatement) String() string {
+	var buf strings.Builder
+	_, _ = buf.WriteString("DROP QUOTA ON ")
+	_, _ = buf.WriteString(QuoteIdent(s.Database))
+	return buf.String()
+}
+
+// RequiredPrivileges returns the privilege required to execute a DropQuotaStatement.
+func (s *DropQuotaStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
+	return ExecutionPrivileges{{Admin: true, Name: "", Privilege: AllPrivileges}}, nil
+}
+
 // DropRetentionPolicyStatement represents a command to drop a retention policy from a database.
 type DropRetentionPolicyStatement struct {
 	// Name of the policy to drop.
@@ -724,6 +807,44 @@ func (s *DropUserStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
 	return ExecutionPrivileges{{Admin: true, Name: "", Privilege: AllPrivileges}}, nil
 }
 
+// CreateRoleStatement represents a command for creating a new role.
+type CreateRoleStatement struct {
+	// Name of the role to be created.
+	Name string
+}
+
+// String returns a string representation of the create role statement.
+func (s *CreateRoleStatement) String() string {
+	var buf strings.Builder
+	_, _ = buf.WriteString("CREATE ROLE ")
+	_, _ = buf.WriteString(QuoteIdent(s.Name))
+	return buf.String()
+}
+
+// RequiredPrivileges returns the privilege(s) required to execute a CreateRoleStatement.
+func (s *CreateRoleStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
+	return ExecutionPrivileges{{Admin: true, Name: "", Privilege: AllPrivileges}}, nil
+}
+
+// DropRoleStatement represents a command for dropping a role.
+type DropRoleStatement struct {
+	// Name of the role to drop.
+	Name string
+}
+
+// String returns a string representation of the drop role statement.
+func (s *DropRoleStatement) String() string {
+	var buf strings.Builder
+	_, _ = buf.WriteString("DROP ROLE ")
+	_, _ = buf.WriteString(QuoteIdent(s.Name))
+	return buf.String()
+}
+
+// RequiredPrivileges returns the privilege(s) required to execute a DropRoleStatement.
+func (s *DropRoleStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
+	return ExecutionPrivileges{{Admin: true, Name: "", Privilege: AllPrivileges}}, nil
+}
+
 // Privilege is a type of action a user can be granted the right to use.
 type Privilege int
 
@@ -764,8 +885,15 @@ type GrantStatement struct {
 	// Database to grant the privilege to.
 	On string
 
+	// Measurement limits the privilege to a measurement, or to the
+	// measurements matching a regex. Only roles are granted such privileges.
+	Measurement *Measurement
+
 	// Who to grant the privilege to.
 	User string
+
+	// Role to grant the privilege to, instead of a user.
+	Role string
 }
 
 // String returns a string representation of the grant statement.
@@ -775,8 +903,17 @@ func (s *GrantStatement) String() string {
 	_, _ = buf.WriteString(s.Privilege.String())
 	_, _ = buf.WriteString(" ON ")
 	_, _ = buf.WriteString(QuoteIdent(s.On))
+	if s.Measurement != nil {
+		_, _ = buf.WriteString(" MEASUREMENT ")
+		_, _ = buf.WriteString(s.Measurement.String())
+	}
 	_, _ = buf.WriteString(" TO ")
-	_, _ = buf.WriteString(QuoteIdent(s.User))
+	if s.Role != "" {
+		_, _ = buf.WriteString("ROLE ")
+		_, _ = buf.WriteString(QuoteIdent(s.Role))
+	} else {
+		_, _ = buf.WriteString(QuoteIdent(s.User))
+	}
 	return buf.String()
 }
 
@@ -809,6 +946,30 @@ func (s *GrantAdminStatement) RequiredPrivileges() (ExecutionPrivileges, error)
 	return ExecutionPrivileges{{Admin: true, Name: "", Privilege: AllPrivileges}}, nil
 }
 
+// GrantRoleStatement represents a command for granting a role to a user.
+type GrantRoleStatement struct {
+	// Role to be granted.
+	Role string
+
+	// Who to grant the role to.
+	User string
+}
+
+// String returns a string representation of the grant role statement.
+func (s *GrantRoleStatement) String() string {
+	var buf strings.Builder
+	_, _ = buf.WriteString("GRANT ROLE ")
+	_, _ = buf.WriteString(QuoteIdent(s.Role))
+	_, _ = buf.WriteString(" TO ")
+	_, _ = buf.WriteString(QuoteIdent(s.User))
+	return buf.String()
+}
+
+// RequiredPrivileges returns the privilege required to execute a GrantRoleStatement.
+func (s *GrantRoleStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
+	return ExecutionPrivileges{{Admin: true, Name: "", Privilege: AllPrivileges}}, nil
+}
+
 // KillQueryStatement represents a command for killing a query.
 type KillQueryStatement struct {
 	// The query to kill.
@@ -835,6 +996,22 @@ func (s *KillQueryStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
 	return ExecutionPrivileges{{Admin: true, Name: "", Privilege: AllPrivileges}}, nil
 }
 
+// KillTaskStatement represents a command for killing a background task.
+type KillTaskStatement struct {
+	// The task to kill.
+	TaskID int
+}
+
+// String returns a string representation of the kill task statement.
+func (s *KillTaskStatement) String() string {
+	return "KILL TASK " + strconv.Itoa(s.TaskID)
+}
+
+// RequiredPrivileges returns the privilege required to execute a KillTaskStatement.
+func (s *KillTaskStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
+	return ExecutionPrivileges{{Admin: true, Name: "", Privilege: AllPrivileges}}, nil
+}
+
 // SetPasswordUserStatement represents a command for changing user password.
 type SetPasswordUserStatement struct {
 	// Plain-text password.
@@ -859,6 +1036,46 @@ func (s *SetPasswordUserStatement) RequiredPrivileges() (ExecutionPrivileges, er
 	return ExecutionPrivileges{{Admin: true, Name: "", Privilege: AllPrivileges}}, nil
 }
 
+// SetQuotaStatement represents a command for setting the resource quota of
+// a database. A limit of zero means the resource is not limited.
+type SetQuotaStatement struct {
+	// Name of the database the quota applies to.
+	Database string
+
+	// Maximum size of the database on disk, in bytes.
+	MaxDiskBytes int64
+
+	// Maximum number of series in the database.
+	MaxSeries int64
+
+	// Maximum number of points written to the database per second.
+	MaxPointsPerSecond int64
+
+	// Maximum number of queries running against the database at once.
+	MaxConcurrentQueries int
+}
+
+// String returns a string representation of the set quota statement.
+func (s *SetQuotaStatement) String() string {
+	var buf strings.Builder
+	_, _ = buf.WriteString("SET QUOTA ON ")
+	_, _ = buf.WriteString(QuoteIdent(s.Database))
+	_, _ = buf.WriteString(" DISK ")
+	_, _ = buf.WriteString(strconv.FormatInt(s.MaxDiskBytes, 10))
+	_, _ = buf.WriteString(" SERIES ")
+	_, _ = buf.WriteString(strconv.FormatInt(s.MaxSeries, 10))
+	_, _ = buf.WriteString(" POINTS PER SECOND ")
+	_, _ = buf.WriteString(strconv.FormatInt(s.MaxPointsPerSecond, 10))
+	_, _ = buf.WriteString(" QUERIES ")
+	_, _ = buf.WriteString(strconv.Itoa(s.MaxConcurrentQueries))
+	return buf.String()
+}
+
+// RequiredPrivileges returns the privilege required to execute a SetQuotaStatement.
+func (s *SetQuotaStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
+	return ExecutionPrivileges{{Admin: true, Name: "", Privilege: AllPrivileges}}, nil
+}
+
 // RevokeStatement represents a command to revoke a privilege from a user.
 type RevokeStatement struct {
 	// The privilege to be revoked.
@@ -867,8 +1084,15 @@ type RevokeStatement struct {
 	// Database to revoke the privilege from.
 	On string
 
+	// Measurement the privilege is limited to, if it was granted to a role
+	// on measurements.
+	Measurement *Measurement
+
 	// Who to revoke privilege from.
 	User string
+
+	// Role to revoke the privilege from, instead of a user.
+	Role string
 }
 
 // String returns a string representation of the revoke statement.
@@ -878,8 +1102,17 @@ func (s *RevokeStatement) String() string {
 	_, _ = buf.WriteString(s.Privilege.String())
 	_, _ = buf.WriteString(" ON ")
 	_, _ = buf.WriteString(QuoteIdent(s.On))
+	if s.Measurement != nil {
+		_, _ = buf.WriteString(" MEASUREMENT ")
+		_, _ = buf.WriteString(s.Measurement.String())
+	}
 	_, _ = buf.WriteString(" FROM ")
-	_, _ = buf.WriteString(QuoteIdent(s.User))
+	if s.Role != "" {
+		_, _ = buf.WriteString("ROLE ")
+		_, _ = buf.WriteString(QuoteIdent(s.Role))
+	} else {
+		_, _ = buf.WriteString(QuoteIdent(s.User))
+	}
 	return buf.String()
 }
 
@@ -912,6 +1145,165 @@ func (s *RevokeAdminStatement) RequiredPrivileges() (ExecutionPrivileges, error)
 	return ExecutionPrivileges{{Admin: true, Name: "", Privilege: AllPrivileges}}, nil
 }
 
+// RevokeRoleStatement represents a command to revoke a role from a user.
+type RevokeRoleStatement struct {
+	// Role to be revoked.
+	Role string
+
+	// Who to revoke the role from.
+	User string
+}
+
+// String returns a string representation of the revoke role statement.
+func (s *RevokeRoleStatement) String() string {
+	var buf strings.Builder
+	_, _ = buf.WriteString("REVOKE ROLE ")
+	_, _ = buf.WriteString(QuoteIdent(s.Role))
+	_, _ = buf.WriteString(" FROM ")
+	_, _ = buf.WriteString(QuoteIdent(s.User))
+	return buf.String()
+}
+
+// RequiredPrivileges returns the privilege required to execute a RevokeRoleStatement.
+func (s *RevokeRoleStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
+	return ExecutionPrivileges{{Admin: true, Name: "", Privilege: AllPrivileges}}, nil
+}
+
+// CreateSecurityPolicyStatement represents a command for creating a security
+// policy that limits the series a user or role can read from a database.
+type CreateSecurityPolicyStatement struct {
+	// Name of the database the policy applies to.
+	Database string
+
+	// Name of the user the policy applies to.
+	User string
+
+	// Name of the role the policy applies to.
+	Role string
+
+	// Condition on the tags of a series that must be true for it to be read.
+	Condition Expr
+}
+
+// String returns a string representation of the create security policy statement.
+func (s *CreateSecurityPolicyStatement) String() string {
+	var buf strings.Builder
+	_, _ = buf.WriteString("CREATE SECURITY POLICY ON ")
+	_, _ = buf.WriteString(QuoteIdent(s.Database))
+	_, _ = buf.WriteString(securityPolicyPrincipalString(s.User, s.Role))
+	_, _ = buf.WriteString(" USING (")
+	_, _ = buf.WriteString(s.Condition.String())
+	_, _ = buf.WriteString(")")
+	return buf.String()
+}
+
+// RequiredPrivileges returns the privilege(s) required to execute a CreateSecurityPolicyStatement.
+func (s *CreateSecurityPolicyStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
+	return ExecutionPrivileges{{Admin: true, Name: "", Privilege: AllPrivileges}}, nil
+}
+
+// DropSecurityPolicyStatement represents a command for dropping a security policy.
+type DropSecurityPolicyStatement struct {
+	// Name of the database the policy applies to.
+	Database string
+
+	// Name of the user the policy applies to.
+	User string
+
+	// Name of the role the policy applies to.
+	Role string
+}
+
+// String returns a string representation of the drop security policy statement.
+func (s *DropSecurityPolicyStatement) String() string {
+	return "DROP SECURITY POLICY ON " + QuoteIdent(s.Database) + securityPolicyPrincipalString(s.User, s.Role)
+}
+
+// RequiredPrivileges returns the privilege(s) required to execute a DropSecurityPolicyStatement.
+func (s *DropSecurityPolicyStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
+	return ExecutionPrivileges{{Admin: true, Name: "", Privilege: AllPrivileges}}, nil
+}
+
+// securityPolicyPrincipalString returns the FOR clause of a security policy statement.
+func securityPolicyPrincipalString(user, role string) string {
+	if role != "" {
+		return " FOR ROLE " + QuoteIdent(role)
+	}
+	return " FOR USER " + QuoteIdent(user)
+}
+
+// CreateTokenStatement represents a command for creating an API token.
+type CreateTokenStatement struct {
+	// Name of the user the token authenticates.
+	User string
+
+	// Privileges the token is limited to. The token has every privilege of
+	// the user if there are none.
+	Privileges []*TokenPrivilege
+
+	// Duration after which the token expires. It does not expire if zero.
+	Duration time.Duration
+
+	// Description of the token.
+	Description string
+}
+
+// TokenPrivilege represents a privilege on a database that a token is limited to.
+type TokenPrivilege struct {
+	Privilege Privilege
+	Database  string
+}
+
+// String returns a string representation of the token privilege.
+func (p *TokenPrivilege) String() string {
+	return p.Privilege.String() + " ON " + QuoteIdent(p.Database)
+}
+
+// String returns a string representation of the create token statement.
+func (s *CreateTokenStatement) String() string {
+	var buf strings.Builder
+	_, _ = buf.WriteString("CREATE TOKEN FOR USER ")
+	_, _ = buf.WriteString(QuoteIdent(s.User))
+	for i, p := range s.Privileges {
+		if i == 0 {
+			_, _ = buf.WriteString(" WITH ")
+		} else {
+			_, _ = buf.WriteString(", ")
+		}
+		_, _ = buf.WriteString(p.String())
+	}
+	if s.Duration > 0 {
+		_, _ = buf.WriteString(" DURATION ")
+		_, _ = buf.WriteString(FormatDuration(s.Duration))
+	}
+	if s.Description != "" {
+		_, _ = buf.WriteString(" DESCRIPTION ")
+		_, _ = buf.WriteString(QuoteString(s.Description))
+	}
+	return buf.String()
+}
+
+// RequiredPrivileges returns the privilege(s) required to execute a CreateTokenStatement.
+func (s *CreateTokenStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
+	return ExecutionPrivileges{{Admin: true, Name: "", Privilege: AllPrivileges}}, nil
+}
+
+// RevokeTokenStatement represents a command to revoke an API token.
+type RevokeTokenStatement struct {
+	// ID of the token to revoke.
+	ID string
+}
+
+// String returns a string representation of the revoke token statement.
+func (s *RevokeTokenStatement) String() string {
+	return "REVOKE TOKEN " + QuoteString(s.ID)
+}
+
+// RequiredPrivileges returns the privilege required to execute a RevokeTokenStatement.
+func (s *RevokeTokenStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
+	return ExecutionPrivileges{{Admin: true, Name: "", Privilege: AllPrivileges}}, nil
+}
+
 // CreateRetentionPolicyStatement represents a command to create a retention policy.
 type CreateRetentionPolicyStatement struct {
 	// Name of policy to create.
@@ -931,6 +1323,9 @@ type CreateRetentionPolicyStatement struct {
 
 	// Shard Duration.
 	ShardGroupDuration time.Duration
+
+	// How values that conflict with the type of an existing field are written.
+	FieldTypePolicy string
 }
 
 // String returns a string representation of the create retention policy.
@@ -948,6 +1343,10 @@ func (s *CreateRetentionPolicyStatement) String() string {
 		_, _ = buf.WriteString(" SHARD DURATION ")
 		_, _ = buf.WriteString(FormatDuration(s.ShardGroupDuration))
 	}
+	if s.FieldTypePolicy != "" {
+		_, _ = buf.WriteString(" FIELD TYPE ")
+		_, _ = buf.WriteString(QuoteString(s.FieldTypePolicy))
+	}
 	if s.Default {
 		_, _ = buf.WriteString(" DEFAULT")
 	}
@@ -983,6 +1382,9 @@ type AlterRetentionPolicyStatement struct {
 
 	// Duration of the Shard.
 	ShardGroupDuration *time.Duration
+
+	// How values that conflict with the type of an existing field are written.
+	FieldTypePolicy *string
 }
 
 // String returns a string representation of the alter retention policy statement.
@@ -1008,6 +1410,11 @@ func (s *AlterRetentionPolicyStatement) String() string {
 		_, _ = buf.WriteString(FormatDuration(*s.ShardGroupDuration))
 	}
 
+	if s.FieldTypePolicy != nil {
+		_, _ = buf.WriteString(" FIELD TYPE ")
+		_, _ = buf.WriteString(QuoteString(*s.FieldTypePolicy))
+	}
+
 	if s.Default {
 		_, _ = buf.WriteString(" DEFAULT")
 	}
@@ -1058,6 +1465,9 @@ type SelectStatement struct {
 	// An expression evaluated on data point.
 	Condition Expr
 
+	// An expression evaluated on the aggregated results.
+	Having Expr
+
 	// Fields to sort results by.
 	SortFields SortFields
 
@@ -1125,6 +1535,7 @@ func (s *SelectStatement) Clone() *SelectStatement {
 	clone.Sources = cloneSources(s.Sources)
 	clone.SortFields = make(SortFields, 0, len(s.SortFields))
 	clone.Condition = CloneExpr(s.Condition)
+	clone.Having = CloneExpr(s.Having)
 
 	if s.Target != nil {
 		clone.Target = &Target{
@@ -1166,6 +1577,15 @@ func cloneSource(s Source) Source {
 		return s.Clone()
 	case *SubQuery:
 		return &SubQuery{Statement: s.Statement.Clone()}
+	case *Join:
+		return &Join{
+			Type:       s.Type,
+			Left:       cloneSource(s.Left),
+			LeftAlias:  s.LeftAlias,
+			Right:      cloneSource(s.Right),
+			RightAlias: s.RightAlias,
+			Condition:  CloneExpr(s.Condition),
+		}
 	default:
 		panic("unreachable")
 	}
@@ -1195,6 +1615,16 @@ func (s *SelectStatement) RewriteFields(m FieldMapper) (*SelectStatement, error)
 				return nil, err
 			}
 			src.Statement = stmt
+		case *Join:
+			for _, side := range []Source{src.Left, src.Right} {
+				if side, ok := side.(*SubQuery); ok {
+					stmt, err := side.Statement.RewriteFields(m)
+					if err != nil {
+						return nil, err
+					}
+					side.Statement = stmt
+				}
+			}
 		}
 	}
 
@@ -1214,6 +1644,7 @@ func (s *SelectStatement) RewriteFields(m FieldMapper) (*SelectStatement, error)
 	}
 	WalkFunc(other.Fields, rewrite)
 	WalkFunc(other.Condition, rewrite)
+	WalkFunc(other.Having, rewrite)
 
 	// Ignore if there are no wildcards.
 	hasFieldWildcard := other.HasFieldWildcard()
@@ -1750,6 +2181,12 @@ func (s *SelectStatement) Reduce(valuer Valuer) *SelectStatement {
 		switch source := source.(type) {
 		case *SubQuery:
 			source.Statement = source.Statement.Reduce(valuer)
+		case *Join:
+			for _, side := range []Source{source.Left, source.Right} {
+				if side, ok := side.(*SubQuery); ok {
+					side.Statement = side.Statement.Reduce(valuer)
+				}
+			}
 		}
 	}
 	return stmt
@@ -1787,6 +2224,10 @@ func (s *SelectStatement) String() string {
 	case PreviousFill:
 		_, _ = buf.WriteString(" fill(previous)")
 	}
+	if s.Having != nil {
+		_, _ = buf.WriteString(" HAVING ")
+		_, _ = buf.WriteString(s.Having.String())
+	}
 	if len(s.SortFields) > 0 {
 		_, _ = buf.WriteString(" ORDER BY ")
 		_, _ = buf.WriteString(s.SortFields.String())
@@ -1890,12 +2331,48 @@ func (s *SelectStatement) GroupByInterval() (time.Duration, error) {
 	return 0, nil
 }
 
+// GroupByMonths extracts the number of calendar months of the time interval,
+// if specified. The interval returned by GroupByInterval is zero when the
+// interval is in calendar months.
+func (s *SelectStatement) GroupByMonths() (int, error) {
+	if _, err := s.GroupByInterval(); err != nil {
+		return 0, err
+	}
+
+	for _, d := range s.Dimensions {
+		if call, ok := d.Expr.(*Call); ok && call.Name == "time" {
+			lit := call.Args[0].(*DurationLiteral)
+			if lit.Months != 0 && lit.Val != 0 {
+				return 0, errors.New("time dimension cannot mix calendar and fixed units")
+			}
+			return lit.Months, nil
+		}
+	}
+	return 0, nil
+}
+
+// GroupByCalendarWeeks returns true if the time interval is in calendar weeks.
+// The interval returned by GroupByInterval is then a whole number of weeks.
+func (s *SelectStatement) GroupByCalendarWeeks() bool {
+	for _, d := range s.Dimensions {
+		if call, ok := d.Expr.(*Call); ok && call.Name == "time" && len(call.Args) > 0 {
+			lit, ok := call.Args[0].(*DurationLiteral)
+			return ok && lit.CalendarWeeks
+		}
+	}
+	return false
+}
+
 // GroupByOffset extracts the time interval offset, if specified.
 func (s *SelectStatement) GroupByOffset() (time.Duration, error) {
 	interval, err := s.GroupByInterval()
 	if err != nil {
 		return 0, err
 	}
+	months, err := s.GroupByMonths()
+	if err != nil {
+		return 0, err
+	}
 
 	// Ignore if there are no dimensions.
 	if len(s.Dimensions) == 0 {
@@ -1907,8 +2384,16 @@ func (s *SelectStatement) GroupByOffset() (time.Duration, error) {
 			if len(call.Args) == 2 {
 				switch expr := call.Args[1].(type) {
 				case *DurationLiteral:
+					if months != 0 {
+						return expr.Val, nil
+					}
 					return expr.Val % interval, nil
 				case *TimeLiteral:
+					if months != 0 {
+						return expr.Val.Sub(TruncateMonths(expr.Val.UTC(), months)), nil
+					} else if s.GroupByCalendarWeeks() {
+						return expr.Val.Sub(TruncateWeeks(expr.Val.UTC(), int(interval/(7*24*time.Hour)))), nil
+					}
 					return expr.Val.Sub(expr.Val.Truncate(interval)), nil
 				default:
 					return 0, fmt.Errorf("invalid time dimension offset: %s", expr)
@@ -2009,6 +2494,14 @@ func walkNames(exp Expr) []string {
 		ret = append(ret, walkNames(expr.LHS)...)
 		ret = append(ret, walkNames(expr.RHS)...)
 		return ret
+	case *CaseExpr:
+		var ret []string
+		for _, w := range expr.WhenClauses {
+			ret = append(ret, walkNames(w.Condition)...)
+			ret = append(ret, walkNames(w.Result)...)
+		}
+		ret = append(ret, walkNames(expr.Else)...)
+		return ret
 	case *ParenExpr:
 		return walkNames(expr.Expr)
 	}
@@ -2026,10 +2519,14 @@ func walkRefs(exp Expr) []VarRef {
 			refs[*expr] = struct{}{}
 		case *Call:
 			for _, expr := range expr.Args {
-				if ref, ok := expr.(*VarRef); ok {
-					refs[*ref] = struct{}{}
-				}
+				walk(expr)
 			}
+		case *CaseExpr:
+			for _, w := range expr.WhenClauses {
+				walk(w.Condition)
+				walk(w.Result)
+			}
+			walk(expr.Else)
 		case *BinaryExpr:
 			walk(expr.LHS)
 			walk(expr.RHS)
@@ -2365,6 +2862,26 @@ func (s *ShowSeriesCardinalityStatement) DefaultDatabase() string {
 	return s.Database
 }
 
+// ShowAuditLogStatement represents a command for listing the audit log.
+type ShowAuditLogStatement struct {
+	// The maximum number of events to return, newest last. All events are
+	// returned if zero.
+	Limit int
+}
+
+// String returns a string representation of the ShowAuditLogStatement.
+func (s *ShowAuditLogStatement) String() string {
+	if s.Limit > 0 {
+		return fmt.Sprintf("SHOW AUDIT LOG LIMIT %d", s.Limit)
+	}
+	return "SHOW AUDIT LOG"
+}
+
+// RequiredPrivileges returns the privilege(s) required to execute a ShowAuditLogStatement.
+func (s *ShowAuditLogStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
+	return ExecutionPrivileges{{Admin: true, Name: "", Privilege: AllPrivileges}}, nil
+}
+
 // ShowContinuousQueriesStatement represents a command for listing continuous queries.
 type ShowContinuousQueriesStatement struct{}
 
@@ -2424,8 +2941,14 @@ type CreateContinuousQueryStatement struct {
 	// Interval to resample previous queries.
 	ResampleEvery time.Duration
 
+	// Calendar months of the interval to resample previous queries.
+	ResampleEveryMonths int
+
 	// Maximum duration to resample previous queries.
 	ResampleFor time.Duration
+
+	// Calendar months of the maximum duration to resample previous queries.
+	ResampleForMonths int
 }
 
 // String returns a string representation of the statement.
@@ -2433,13 +2956,13 @@ func (s *CreateContinuousQueryStatement) String() string {
 	var buf strings.Builder
 	fmt.Fprintf(&buf, "CREATE CONTINUOUS QUERY %s ON %s ", QuoteIdent(s.Name), QuoteIdent(s.Database))
 
-	if s.ResampleEvery > 0 || s.ResampleFor > 0 {
+	if s.ResampleEvery > 0 || s.ResampleEveryMonths > 0 || s.ResampleFor > 0 || s.ResampleForMonths > 0 {
 		buf.WriteString("RESAMPLE ")
-		if s.ResampleEvery > 0 {
-			fmt.Fprintf(&buf, "EVERY %s ", FormatDuration(s.ResampleEvery))
+		if s.ResampleEvery > 0 || s.ResampleEveryMonths > 0 {
+			fmt.Fprintf(&buf, "EVERY %s ", FormatCalendarDuration(s.ResampleEveryMonths, s.ResampleEvery))
 		}
-		if s.ResampleFor > 0 {
-			fmt.Fprintf(&buf, "FOR %s ", FormatDuration(s.ResampleFor))
+		if s.ResampleFor > 0 || s.ResampleForMonths > 0 {
+			fmt.Fprintf(&buf, "FOR %s ", FormatCalendarDuration(s.ResampleForMonths, s.ResampleFor))
 		}
 	}
 	fmt.Fprintf(&buf, "BEGIN %s END", s.Source.String())
@@ -2468,45 +2991,357 @@ func (s *CreateContinuousQueryStatement) RequiredPrivileges() (ExecutionPrivileg
 		}
 		ep = append(ep, p)
 	}
-
-	return ep, nil
+
+	return ep, nil
+}
+
+func (s *CreateContinuousQueryStatement) validate() error {
+	d, err := s.Source.GroupByInterval()
+	if err != nil {
+		return err
+	}
+	months, err := s.Source.GroupByMonths()
+	if err != nil {
+		return err
+	}
+	interval := DurationLiteral{Val: d, Months: months}
+
+	if s.ResampleFor != 0 || s.ResampleForMonths != 0 {
+		every := DurationLiteral{Val: s.ResampleEvery, Months: s.ResampleEveryMonths}
+		if (every.Val != 0 || every.Months != 0) && mayExceed(every, interval) {
+			interval = every
+		}
+		if resampleFor := (DurationLiteral{Val: s.ResampleFor, Months: s.ResampleForMonths}); mayExceed(interval, resampleFor) {
+			return fmt.Errorf("FOR duration must be >= GROUP BY time duration: must be a minimum of %s, got %s", interval.String(), resampleFor.String())
+		}
+	}
+	return nil
+}
+
+// mayExceed returns true if the duration a can be longer than b. Calendar
+// months are between 28 and 31 days long so durations with a different
+// number of months are compared using the longest and shortest month.
+func mayExceed(a, b DurationLiteral) bool {
+	if a.Months == b.Months {
+		return a.Val > b.Val
+	}
+	const day = 24 * time.Hour
+	return time.Duration(a.Months)*31*day+a.Val > time.Duration(b.Months)*28*day+b.Val
+}
+
+// BackfillContinuousQueryStatement represents a command for computing the
+// historical windows of a continuous query.
+type BackfillContinuousQueryStatement struct {
+	// Name of the continuous query to backfill.
+	Name string
+
+	// Name of the database the continuous query is on.
+	Database string
+
+	// Time range to backfill. The first and last windows are the ones
+	// that contain StartTime and EndTime.
+	StartTime time.Time
+	EndTime   time.Time
+}
+
+// String returns a string representation of the statement.
+func (s *BackfillContinuousQueryStatement) String() string {
+	return fmt.Sprintf("BACKFILL CONTINUOUS QUERY %s ON %s FROM %s TO %s",
+		QuoteIdent(s.Name), QuoteIdent(s.Database),
+		QuoteString(s.StartTime.UTC().Format(time.RFC3339Nano)),
+		QuoteString(s.EndTime.UTC().Format(time.RFC3339Nano)))
+}
+
+// RequiredPrivileges returns the privilege(s) required to execute a BackfillContinuousQueryStatement.
+func (s *BackfillContinuousQueryStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
+	return ExecutionPrivileges{{Admin: false, Name: s.Database, Privilege: WritePrivilege}}, nil
+}
+
+// DefaultDatabase returns the default database from the statement.
+func (s *BackfillContinuousQueryStatement) DefaultDatabase() string {
+	return s.Database
+}
+
+// DropContinuousQueryStatement represents a command for removing a continuous query.
+type DropContinuousQueryStatement struct {
+	Name     string
+	Database string
+}
+
+// String returns a string representation of the statement.
+func (s *DropContinuousQueryStatement) String() string {
+	return fmt.Sprintf("DROP CONTINUOUS QUERY %s ON %s", QuoteIdent(s.Name), QuoteIdent(s.Database))
+}
+
+// RequiredPrivileges returns the privilege(s) required to execute a DropContinuousQueryStatement
+func (s *DropContinuousQueryStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
+	return ExecutionPrivileges{{Admin: false, Name: s.Database, Privilege: WritePrivilege}}, nil
+}
+
+// DefaultDatabase returns the default database from the statement.
+func (s *DropContinuousQueryStatement) DefaultDatabase() string {
+	return s.Database
+}
+
+// DefaultDownsampleFunctions are the functions a downsample policy applies to
+// numeric fields when none are given.
+var DefaultDownsampleFunctions = []string{"mean", "min", "max", "count"}
+
+// IsDownsampleFunction returns true if the function can be applied to numeric
+// fields by a downsample policy.
+func IsDownsampleFunction(name string) bool {
+	switch name {
+	case "count", "first", "last", "max", "mean", "median", "min", "mode", "spread", "stddev", "sum":
+		return true
+	}
+	return false
+}
+
+// CreateDownsamplePolicyStatement represents a command for creating a policy
+// that rolls up every measurement in one retention policy into another.
+type CreateDownsamplePolicyStatement struct {
+	// Name of the policy to be created.
+	Name string
+
+	// Name of the database the policy is on.
+	Database string
+
+	// Retention policies the policy reads from and writes to.
+	SourceRetentionPolicy string
+	TargetRetentionPolicy string
+
+	// Interval the data is rolled up at.
+	Interval time.Duration
+
+	// Functions applied to numeric fields. If empty, the default
+	// functions are used.
+	Functions []string
+}
+
+// String returns a string representation of the statement.
+func (s *CreateDownsamplePolicyStatement) String() string {
+	var buf strings.Builder
+	_, _ = buf.WriteString("CREATE DOWNSAMPLE POLICY ")
+	_, _ = buf.WriteString(QuoteIdent(s.Name))
+	_, _ = buf.WriteString(" ON ")
+	_, _ = buf.WriteString(QuoteIdent(s.Database))
+	_, _ = buf.WriteString(" FROM ")
+	_, _ = buf.WriteString(QuoteIdent(s.SourceRetentionPolicy))
+	_, _ = buf.WriteString(" TO ")
+	_, _ = buf.WriteString(QuoteIdent(s.TargetRetentionPolicy))
+	_, _ = buf.WriteString(" EVERY ")
+	_, _ = buf.WriteString(FormatDuration(s.Interval))
+	if len(s.Functions) > 0 {
+		_, _ = buf.WriteString(" WITH ")
+		_, _ = buf.WriteString(strings.Join(s.Functions, ", "))
+	}
+	return buf.String()
+}
+
+// RequiredPrivileges returns the privilege(s) required to execute a CreateDownsamplePolicyStatement.
+func (s *CreateDownsamplePolicyStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
+	return ExecutionPrivileges{{Admin: false, Name: s.Database, Privilege: WritePrivilege}}, nil
+}
+
+// DefaultDatabase returns the default database from the statement.
+func (s *CreateDownsamplePolicyStatement) DefaultDatabase() string {
+	return s.Database
+}
+
+// DropDownsamplePolicyStatement represents a command for removing a downsample policy.
+type DropDownsamplePolicyStatement struct {
+	Name     string
+	Database string
+}
+
+// String returns a string representation of the statement.
+func (s *DropDownsamplePolicyStatement) String() string {
+	return fmt.Sprintf("DROP DOWNSAMPLE POLICY %s ON %s", QuoteIdent(s.Name), QuoteIdent(s.Database))
+}
+
+// RequiredPrivileges returns the privilege(s) required to execute a DropDownsamplePolicyStatement.
+func (s *DropDownsamplePolicyStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
+	return ExecutionPrivileges{{Admin: false, Name: s.Database, Privilege: WritePrivilege}}, nil
+}
+
+// DefaultDatabase returns the default database from the statement.
+func (s *DropDownsamplePolicyStatement) DefaultDatabase() string {
+	return s.Database
+}
+
+// ShowDownsamplePoliciesStatement represents a command for listing downsample policies.
+type ShowDownsamplePoliciesStatement struct {
+	// Name of the database to list policies for.
+	Database string
+}
+
+// String returns a string representation of the statement.
+func (s *ShowDownsamplePoliciesStatement) String() string {
+	var buf strings.Builder
+	_, _ = buf.WriteString("SHOW DOWNSAMPLE POLICIES")
+	if s.Database != "" {
+		_, _ = buf.WriteString(" ON ")
+		_, _ = buf.WriteString(QuoteIdent(s.Database))
+	}
+	return buf.String()
+}
+
+// RequiredPrivileges returns the privilege(s) required to execute a ShowDownsamplePoliciesStatement.
+func (s *ShowDownsamplePoliciesStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
+	return ExecutionPrivileges{{Admin: false, Name: s.Database, Privilege: ReadPrivilege}}, nil
+}
+
+// DefaultDatabase returns the default database from the statement.
+func (s *ShowDownsamplePoliciesStatement) DefaultDatabase() string {
+	return s.Database
+}
+
+// CreateMeasurementSchemaStatement represents a command for constraining the
+// points written to a measurement.
+type CreateMeasurementSchemaStatement struct {
+	// Name of the measurement the schema applies to.
+	Name string
+
+	// Name of the database the measurement belongs to.
+	Database string
+
+	// Fields that may be written. If empty, any field may be written.
+	Fields []*MeasurementSchemaField
+
+	// Tag keys that may be written. If empty, any tag key may be written.
+	Tags []*MeasurementSchemaTag
+
+	// WarnOnly reports points that violate the schema instead of dropping them.
+	WarnOnly bool
+}
+
+// MeasurementSchemaField represents a field declared by a measurement schema.
+type MeasurementSchemaField struct {
+	Name string
+	Type DataType
+}
+
+// String returns a string representation of the field.
+func (f *MeasurementSchemaField) String() string {
+	return fmt.Sprintf("FIELD %s %s", QuoteIdent(f.Name), f.Type)
+}
+
+// MeasurementSchemaTag represents a tag key declared by a measurement schema.
+type MeasurementSchemaTag struct {
+	Key string
+
+	// Required is true if every point must have the tag.
+	Required bool
+
+	// Regex must match every value of the tag if it is set.
+	Regex *RegexLiteral
+
+	// Limit is the number of distinct values the tag may have in a shard.
+	// Zero means there is no limit.
+	Limit int
+}
+
+// String returns a string representation of the tag.
+func (t *MeasurementSchemaTag) String() string {
+	var buf strings.Builder
+	_, _ = buf.WriteString("TAG ")
+	_, _ = buf.WriteString(QuoteIdent(t.Key))
+	if t.Required {
+		_, _ = buf.WriteString(" REQUIRED")
+	}
+	if t.Regex != nil {
+		_, _ = buf.WriteString(" =~ ")
+		_, _ = buf.WriteString(t.Regex.String())
+	}
+	if t.Limit > 0 {
+		_, _ = buf.WriteString(" LIMIT ")
+		_, _ = buf.WriteString(strconv.Itoa(t.Limit))
+	}
+	return buf.String()
+}
+
+// String returns a string representation of the statement.
+func (s *CreateMeasurementSchemaStatement) String() string {
+	var buf strings.Builder
+	_, _ = buf.WriteString("CREATE MEASUREMENT SCHEMA ")
+	_, _ = buf.WriteString(QuoteIdent(s.Name))
+	_, _ = buf.WriteString(" ON ")
+	_, _ = buf.WriteString(QuoteIdent(s.Database))
+	_, _ = buf.WriteString(" (")
+	for i, f := range s.Fields {
+		if i > 0 {
+			_, _ = buf.WriteString(", ")
+		}
+		_, _ = buf.WriteString(f.String())
+	}
+	for i, t := range s.Tags {
+		if i > 0 || len(s.Fields) > 0 {
+			_, _ = buf.WriteString(", ")
+		}
+		_, _ = buf.WriteString(t.String())
+	}
+	_, _ = buf.WriteString(")")
+	if s.WarnOnly {
+		_, _ = buf.WriteString(" WARN")
+	}
+	return buf.String()
 }
 
-func (s *CreateContinuousQueryStatement) validate() error {
-	interval, err := s.Source.GroupByInterval()
-	if err != nil {
-		return err
-	}
+// RequiredPrivileges returns the privilege(s) required to execute a CreateMeasurementSchemaStatement.
+func (s *CreateMeasurementSchemaStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
+	return ExecutionPrivileges{{Admin: true, Name: "", Privilege: AllPrivileges}}, nil
+}
 
-	if s.ResampleFor != 0 {
-		if s.ResampleEvery != 0 && s.ResampleEvery > interval {
-			interval = s.ResampleEvery
-		}
-		if interval > s.ResampleFor {
-			return fmt.Errorf("FOR duration must be >= GROUP BY time duration: must be a minimum of %s, got %s", FormatDuration(interval), FormatDuration(s.ResampleFor))
-		}
-	}
-	return nil
+// DefaultDatabase returns the default database from the statement.
+func (s *CreateMeasurementSchemaStatement) DefaultDatabase() string {
+	return s.Database
 }
 
-// DropContinuousQueryStatement represents a command for removing a continuous query.
-type DropContinuousQueryStatement struct {
+// DropMeasurementSchemaStatement represents a command for removing the schema of a measurement.
+type DropMeasurementSchemaStatement struct {
 	Name     string
 	Database string
 }
 
 // String returns a string representation of the statement.
-func (s *DropContinuousQueryStatement) String() string {
-	return fmt.Sprintf("DROP CONTINUOUS QUERY %s ON %s", QuoteIdent(s.Name), QuoteIdent(s.Database))
+func (s *DropMeasurementSchemaStatement) String() string {
+	return fmt.Sprintf("DROP MEASUREMENT SCHEMA %s ON %s", QuoteIdent(s.Name), QuoteIdent(s.Database))
 }
 
-// RequiredPrivileges returns the privilege(s) required to execute a DropContinuousQueryStatement
-func (s *DropContinuousQueryStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
-	return ExecutionPrivileges{{Admin: false, Name: s.Database, Privilege: WritePrivilege}}, nil
+// RequiredPrivileges returns the privilege(s) required to execute a DropMeasurementSchemaStatement.
+func (s *DropMeasurementSchemaStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
+	return ExecutionPrivileges{{Admin: true, Name: "", Privilege: AllPrivileges}}, nil
 }
 
 // DefaultDatabase returns the default database from the statement.
-func (s *DropContinuousQueryStatement) DefaultDatabase() string {
+func (s *DropMeasurementSchemaStatement) DefaultDatabase() string {
+	return s.Database
+}
+
+// ShowMeasurementSchemasStatement represents a command for listing measurement schemas.
+type ShowMeasurementSchemasStatement struct {
+	// Name of the database to list schemas for.
+	Database string
+}
+
+// String returns a string representation of the statement.
+func (s *ShowMeasurementSchemasStatement) String() string {
+	var buf strings.Builder
+	_, _ = buf.WriteString("SHOW MEASUREMENT SCHEMAS")
+	if s.Database != "" {
+		_, _ = buf.WriteString(" ON ")
+		_, _ = buf.WriteString(QuoteIdent(s.Database))
+	}
+	return buf.String()
+}
+
+// RequiredPrivileges returns the privilege(s) required to execute a ShowMeasurementSchemasStatement.
+func (s *ShowMeasurementSchemasStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
+	return ExecutionPrivileges{{Admin: false, Name: s.Database, Privilege: ReadPrivilege}}, nil
+}
+
+// DefaultDatabase returns the default database from the statement.
+func (s *ShowMeasurementSchemasStatement) DefaultDatabase() string {
 	return s.Database
 }
 
@@ -2658,6 +3493,60 @@ func (s *DropMeasurementStatement) RequiredPrivileges() (ExecutionPrivileges, er
 	return ExecutionPrivileges{{Admin: true, Name: "", Privilege: AllPrivileges}}, nil
 }
 
+// DropFieldStatement represents a command to drop a field from a measurement.
+type DropFieldStatement struct {
+	// Name of the field to be dropped.
+	Name string
+
+	// Measurement the field belongs to.
+	Measurement string
+}
+
+// String returns a string representation of the drop field statement.
+func (s *DropFieldStatement) String() string {
+	var buf strings.Builder
+	_, _ = buf.WriteString("DROP FIELD ")
+	_, _ = buf.WriteString(QuoteIdent(s.Name))
+	_, _ = buf.WriteString(" FROM ")
+	_, _ = buf.WriteString(QuoteIdent(s.Measurement))
+	return buf.String()
+}
+
+// RequiredPrivileges returns the privilege(s) required to execute a DropFieldStatement.
+func (s *DropFieldStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
+	return ExecutionPrivileges{{Admin: true, Name: "", Privilege: AllPrivileges}}, nil
+}
+
+// AlterFieldTypeStatement represents a command to change the type of a field
+// and rewrite its existing values as the new type.
+type AlterFieldTypeStatement struct {
+	// Name of the field to be altered.
+	Name string
+
+	// Measurement the field belongs to.
+	Measurement string
+
+	// New type of the field.
+	Type DataType
+}
+
+// String returns a string representation of the alter field type statement.
+func (s *AlterFieldTypeStatement) String() string {
+	var buf strings.Builder
+	_, _ = buf.WriteString("ALTER FIELD ")
+	_, _ = buf.WriteString(QuoteIdent(s.Name))
+	_, _ = buf.WriteString(" ON ")
+	_, _ = buf.WriteString(QuoteIdent(s.Measurement))
+	_, _ = buf.WriteString(" TYPE ")
+	_, _ = buf.WriteString(s.Type.String())
+	return buf.String()
+}
+
+// RequiredPrivileges returns the privilege(s) required to execute an AlterFieldTypeStatement.
+func (s *AlterFieldTypeStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
+	return ExecutionPrivileges{{Admin: true, Name: "", Privilege: AllPrivileges}}, nil
+}
+
 // ShowQueriesStatement represents a command for listing all running queries.
 type ShowQueriesStatement struct{}
 
@@ -2671,6 +3560,33 @@ func (s *ShowQueriesStatement) RequiredPrivileges() (ExecutionPrivileges, error)
 	return ExecutionPrivileges{{Admin: false, Name: "", Privilege: ReadPrivilege}}, nil
 }
 
+// ShowQuotasStatement represents a command for listing the quotas of all
+// databases along with their current usage.
+type ShowQuotasStatement struct{}
+
+// String returns a string representation of the show quotas statement.
+func (s *ShowQuotasStatement) String() string {
+	return "SHOW QUOTAS"
+}
+
+// RequiredPrivileges returns the privilege required to execute a ShowQuotasStatement.
+func (s *ShowQuotasStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
+	return ExecutionPrivileges{{Admin: true, Name: "", Privilege: AllPrivileges}}, nil
+}
+
+// ShowTasksStatement represents a command for listing all running background tasks.
+type ShowTasksStatement struct{}
+
+// String returns a string representation of the show tasks statement.
+func (s *ShowTasksStatement) String() string {
+	return "SHOW TASKS"
+}
+
+// RequiredPrivileges returns the privilege required to execute a ShowTasksStatement.
+func (s *ShowTasksStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
+	return ExecutionPrivileges{{Admin: true, Name: "", Privilege: AllPrivileges}}, nil
+}
+
 // ShowRetentionPoliciesStatement represents a command for listing retention policies.
 type ShowRetentionPoliciesStatement struct {
 	// Name of the database to list policies for.
@@ -2770,6 +3686,12 @@ type CreateSubscriptionStatement struct {
 	RetentionPolicy string
 	Destinations    []string
 	Mode            string
+
+	// Fields forwarded to the destinations. All fields are forwarded if empty.
+	Fields []string
+
+	// Condition on the measurement and tags of the forwarded points.
+	Condition Expr
 }
 
 // String returns a string representation of the CreateSubscriptionStatement.
@@ -2790,6 +3712,19 @@ func (s *CreateSubscriptionStatement) String() string {
 		}
 		_, _ = buf.WriteString(QuoteString(dest))
 	}
+	if len(s.Fields) > 0 {
+		_, _ = buf.WriteString(" FIELDS ")
+		for i, field := range s.Fields {
+			if i != 0 {
+				_, _ = buf.WriteString(", ")
+			}
+			_, _ = buf.WriteString(QuoteIdent(field))
+		}
+	}
+	if s.Condition != nil {
+		_, _ = buf.WriteString(" WHERE ")
+		_, _ = buf.WriteString(s.Condition.String())
+	}
 
 	return buf.String()
 }
@@ -3130,6 +4065,57 @@ func (s *ShowUsersStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
 	return ExecutionPrivileges{{Admin: true, Name: "", Privilege: AllPrivileges}}, nil
 }
 
+// ShowRolesStatement represents a command for listing roles.
+type ShowRolesStatement struct{}
+
+// String returns a string representation of the ShowRolesStatement.
+func (s *ShowRolesStatement) String() string {
+	return "SHOW ROLES"
+}
+
+// RequiredPrivileges returns the privilege(s) required to execute a ShowRolesStatement
+func (s *ShowRolesStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
+	return ExecutionPrivileges{{Admin: true, Name: "", Privilege: AllPrivileges}}, nil
+}
+
+// ShowSecurityPoliciesStatement represents a command for listing security policies.
+type ShowSecurityPoliciesStatement struct {
+	// Name of the database whose policies are listed. All policies are listed if empty.
+	Database string
+}
+
+// String returns a string representation of the ShowSecurityPoliciesStatement.
+func (s *ShowSecurityPoliciesStatement) String() string {
+	if s.Database != "" {
+		return "SHOW SECURITY POLICIES ON " + QuoteIdent(s.Database)
+	}
+	return "SHOW SECURITY POLICIES"
+}
+
+// RequiredPrivileges returns the privilege(s) required to execute a ShowSecurityPoliciesStatement
+func (s *ShowSecurityPoliciesStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
+	return ExecutionPrivileges{{Admin: true, Name: "", Privilege: AllPrivileges}}, nil
+}
+
+// ShowTokensStatement represents a command for listing API tokens.
+type ShowTokensStatement struct {
+	// Name of the user whose tokens are listed. All tokens are listed if empty.
+	User string
+}
+
+// String returns a string representation of the ShowTokensStatement.
+func (s *ShowTokensStatement) String() string {
+	if s.User != "" {
+		return "SHOW TOKENS FOR USER " + QuoteIdent(s.User)
+	}
+	return "SHOW TOKENS"
+}
+
+// RequiredPrivileges returns the privilege(s) required to execute a ShowTokensStatement
+func (s *ShowTokensStatement) RequiredPrivileges() (ExecutionPrivileges, error) {
+	return ExecutionPrivileges{{Admin: true, Name: "", Privilege: AllPrivileges}}, nil
+}
+
 // ShowFieldKeyCardinalityStatement represents a command for listing field key cardinality.
 type ShowFieldKeyCardinalityStatement struct {
 	Database      string
@@ -3266,9 +4252,7 @@ func (a Fields) Names() []string {
 			names = append(names, expr.Name)
 		case *VarRef:
 			names = append(names, expr.Val)
-		case *BinaryExpr:
-			names = append(names, walkNames(expr)...)
-		case *ParenExpr:
+		case *BinaryExpr, *CaseExpr, *ParenExpr:
 			names = append(names, walkNames(expr)...)
 		}
 	}
@@ -3304,6 +4288,8 @@ func (f *Field) Name() string {
 		return expr.Name
 	case *BinaryExpr:
 		return BinaryExprName(expr)
+	case *CaseExpr:
+		return "case"
 	case *ParenExpr:
 		f := Field{Expr: expr.Expr}
 		return f.Name()
@@ -3451,6 +4437,125 @@ func (s *SubQuery) String() string {
 	return fmt.Sprintf("(%s)", s.Statement.String())
 }
 
+// JoinType represents the kind of join between two sources.
+type JoinType int
+
+const (
+	// InnerJoin only returns rows that are present in both sources.
+	InnerJoin JoinType = iota
+	// LeftJoin returns every row of the left source and fills the
+	// columns of the right source with nulls when there is no match.
+	LeftJoin
+	// FullJoin returns the rows of both sources and fills the columns
+	// of the missing source with nulls when there is no match.
+	FullJoin
+)
+
+// String returns a string representation of the join type.
+func (t JoinType) String() string {
+	switch t {
+	case InnerJoin:
+		return "INNER"
+	case LeftJoin:
+		return "LEFT"
+	case FullJoin:
+		return "FULL"
+	}
+	return ""
+}
+
+// Join is a source that correlates the points of two sources that share
+// the same time and the same values for the tags in the join condition.
+// Fields of each side are referenced with the alias of that side as a
+// prefix, such as a.usage.
+type Join struct {
+	Type       JoinType
+	Left       Source
+	LeftAlias  string
+	Right      Source
+	RightAlias string
+	Condition  Expr
+}
+
+// String returns a string representation of the join.
+func (j *Join) String() string {
+	var buf strings.Builder
+	_, _ = buf.WriteString(j.Left.String())
+	_, _ = buf.WriteString(" AS ")
+	_, _ = buf.WriteString(QuoteIdent(j.LeftAlias))
+	_, _ = buf.WriteString(" ")
+	_, _ = buf.WriteString(j.Type.String())
+	_, _ = buf.WriteString(" JOIN ")
+	_, _ = buf.WriteString(j.Right.String())
+	_, _ = buf.WriteString(" AS ")
+	_, _ = buf.WriteString(QuoteIdent(j.RightAlias))
+	_, _ = buf.WriteString(" ON ")
+	_, _ = buf.WriteString(j.Condition.String())
+	return buf.String()
+}
+
+// SplitRef splits a reference to a field of one side of the join into
+// the source of that side and the name of the field within it.
+// A nil source is returned if the reference does not use one of the aliases.
+func (j *Join) SplitRef(name string) (Source, string) {
+	i := strings.IndexByte(name, '.')
+	if i < 0 {
+		return nil, ""
+	}
+	switch name[:i] {
+	case j.LeftAlias:
+		return j.Left, name[i+1:]
+	case j.RightAlias:
+		return j.Right, name[i+1:]
+	}
+	return nil, ""
+}
+
+// Keys returns the tag keys of the left and right sources that are compared
+// by the join condition. The condition must be a conjunction of equality
+// comparisons between a tag of each side, such as a.host = b.host.
+func (j *Join) Keys() (left, right []string, err error) {
+	var walk func(expr Expr) error
+	walk = func(expr Expr) error {
+		switch expr := expr.(type) {
+		case *ParenExpr:
+			return walk(expr.Expr)
+		case *BinaryExpr:
+			switch expr.Op {
+			case AND:
+				if err := walk(expr.LHS); err != nil {
+					return err
+				}
+				return walk(expr.RHS)
+			case EQ:
+				lhs, ok := expr.LHS.(*VarRef)
+				if !ok {
+					break
+				}
+				rhs, ok := expr.RHS.(*VarRef)
+				if !ok {
+					break
+				}
+				lsrc, lname := j.SplitRef(lhs.Val)
+				rsrc, rname := j.SplitRef(rhs.Val)
+				if lsrc == nil || rsrc == nil || lsrc == rsrc {
+					break
+				}
+				if lsrc == j.Right {
+					lname, rname = rname, lname
+				}
+				left, right = append(left, lname), append(right, rname)
+				return nil
+			}
+		}
+		return fmt.Errorf("invalid join condition: %s", expr)
+	}
+	if err := walk(j.Condition); err != nil {
+		return nil, nil, err
+	}
+	return left, right, nil
+}
+
 // VarRef represents a reference to a variable.
 type VarRef struct {
 	Val  string
@@ -3511,6 +4616,38 @@ func (c *Call) String() string {
 	return fmt.Sprintf("%s(%s)", c.Name, strings.Join(str, ", "))
 }
 
+// CaseExpr represents a CASE expression. It evaluates to the result of the
+// first WHEN clause with a true condition or to the ELSE result if there is
+// no such clause.
+type CaseExpr struct {
+	WhenClauses []*WhenClause
+	Else        Expr
+}
+
+// String returns a string representation of the expression.
+func (e *CaseExpr) String() string {
+	var buf bytes.Buffer
+	_, _ = buf.WriteString("CASE")
+	for _, w := range e.WhenClauses {
+		_, _ = buf.WriteString(" WHEN ")
+		_, _ = buf.WriteString(w.Condition.String())
+		_, _ = buf.WriteString(" THEN ")
+		_, _ = buf.WriteString(w.Result.String())
+	}
+	if e.Else != nil {
+		_, _ = buf.WriteString(" ELSE ")
+		_, _ = buf.WriteString(e.Else.String())
+	}
+	_, _ = buf.WriteString(" END")
+	return buf.String()
+}
+
+// WhenClause represents a condition and its result in a CASE expression.
+type WhenClause struct {
+	Condition Expr
+	Result    Expr
+}
+
 // Distinct represents a DISTINCT expression.
 type Distinct struct {
 	// Identifier following DISTINCT
@@ -3657,10 +4794,24 @@ func (l *TimeLiteral) String() string {
 // DurationLiteral represents a duration literal.
 type DurationLiteral struct {
 	Val time.Duration
+
+	// Months is the number of calendar months of the duration.
+	// It is set by the "mo" and "y" units.
+	Months int
+
+	// CalendarWeeks is set by the "cw" unit. The duration is a number of
+	// weeks and GROUP BY time() windows of it start on a Monday in the
+	// location of the query.
+	CalendarWeeks bool
 }
 
 // String returns a string representation of the literal.
-func (l *DurationLiteral) String() string { return FormatDuration(l.Val) }
+func (l *DurationLiteral) String() string {
+	if l.CalendarWeeks {
+		return FormatCalendarWeeks(l.Val)
+	}
+	return FormatCalendarDuration(l.Months, l.Val)
+}
 
 // NilLiteral represents a nil literal.
 // This is not available to the query language itself. It's only used internally.
@@ -3784,10 +4935,16 @@ func CloneExpr(expr Expr) Expr {
 			args[i] = CloneExpr(arg)
 		}
 		return &Call{Name: expr.Name, Args: args}
+	case *CaseExpr:
+		clauses := make([]*WhenClause, len(expr.WhenClauses))
+		for i, w := range expr.WhenClauses {
+			clauses[i] = &WhenClause{Condition: CloneExpr(w.Condition), Result: CloneExpr(w.Result)}
+		}
+		return &CaseExpr{WhenClauses: clauses, Else: CloneExpr(expr.Else)}
 	case *Distinct:
 		return &Distinct{Val: expr.Val}
 	case *DurationLiteral:
-		return &DurationLiteral{Val: expr.Val}
+		return &DurationLiteral{Val: expr.Val, Months: expr.Months, CalendarWeeks: expr.CalendarWeeks}
 	case *IntegerLiteral:
 		return &IntegerLiteral{Val: expr.Val}
 	case *UnsignedLiteral:
@@ -3855,6 +5012,13 @@ func Walk(v Visitor, node Node) {
 			Walk(v, expr)
 		}
 
+	case *CaseExpr:
+		for _, w := range n.WhenClauses {
+			Walk(v, w.Condition)
+			Walk(v, w.Result)
+		}
+		Walk(v, n.Else)
+
 	case *CreateContinuousQueryStatement:
 		Walk(v, n.Source)
 
@@ -3897,6 +5061,7 @@ func Walk(v Visitor, node Node) {
 		Walk(v, n.Dimensions)
 		Walk(v, n.Sources)
 		Walk(v, n.Condition)
+		Walk(v, n.Having)
 		Walk(v, n.SortFields)
 
 	case *ShowFieldKeyCardinalityStatement:
@@ -3950,6 +5115,10 @@ func Walk(v Visitor, node Node) {
 	case *SubQuery:
 		Walk(v, n.Statement)
 
+	case *Join:
+		Walk(v, n.Left)
+		Walk(v, n.Right)
+
 	case Statements:
 		for _, s := range n {
 			Walk(v, s)
@@ -4002,10 +5171,19 @@ func Rewrite(r Rewriter, node Node) Node {
 		} else {
 			n.Condition = nil
 		}
+		if having := Rewrite(r, n.Having); having != nil {
+			n.Having = having.(Expr)
+		} else {
+			n.Having = nil
+		}
 
 	case *SubQuery:
 		n.Statement = Rewrite(r, n.Statement).(*SelectStatement)
 
+	case *Join:
+		n.Left = Rewrite(r, n.Left).(Source)
+		n.Right = Rewrite(r, n.Right).(Source)
+
 	case Fields:
 		for i, f := range n {
 			n[i] = Rewrite(r, f).(*Field)
@@ -4033,6 +5211,17 @@ func Rewrite(r Rewriter, node Node) Node {
 		for i, expr := range n.Args {
 			n.Args[i] = Rewrite(r, expr).(Expr)
 		}
+
+	case *CaseExpr:
+		for _, w := range n.WhenClauses {
+			w.Condition = Rewrite(r, w.Condition).(Expr)
+			w.Result = Rewrite(r, w.Result).(Expr)
+		}
+		if e := Rewrite(r, n.Else); e != nil {
+			n.Else = e.(Expr)
+		} else {
+			n.Else = nil
+		}
 	}
 
 	return r.Rewrite(node)
@@ -4072,6 +5261,15 @@ func RewriteExpr(expr Expr, fn func(Expr) Expr) Expr {
 		for i, expr := range e.Args {
 			e.Args[i] = RewriteExpr(expr, fn)
 		}
+
+	case *CaseExpr:
+		for _, w := range e.WhenClauses {
+			w.Condition = RewriteExpr(w.Condition, fn)
+			w.Result = RewriteExpr(w.Result, fn)
+		}
+		if e.Else != nil {
+			e.Else = RewriteExpr(e.Else, fn)
+		}
 	}
 
 	return fn(expr)
@@ -4137,6 +5335,13 @@ func (v *ValuerEval) Eval(expr Expr) interface{} {
 			return val
 		}
 		return nil
+	case *CaseExpr:
+		for _, w := range expr.WhenClauses {
+			if v.EvalBool(w.Condition) {
+				return v.Eval(w.Result)
+			}
+		}
+		return v.Eval(expr.Else)
 	case *VarRef:
 		val, _ := v.Valuer.Value(expr.Val)
 		return val
@@ -4600,6 +5805,8 @@ func (v *TypeValuerEval) EvalType(expr Expr) (DataType, error) {
 		return v.evalCallExprType(expr)
 	case *BinaryExpr:
 		return v.evalBinaryExprType(expr)
+	case *CaseExpr:
+		return v.evalCaseExprType(expr)
 	case *ParenExpr:
 		return v.EvalType(expr.Expr)
 	case *NumberLiteral:
@@ -4651,6 +5858,28 @@ func (v *TypeValuerEval) evalVarRefExprType(expr *VarRef) (DataType, error) {
 						}
 					}
 				}
+			case *Join:
+				side, name := src.SplitRef(expr.Val)
+				if side == nil {
+					// Unprefixed references can only be the tags of the join.
+					if left, _, err := src.Keys(); err == nil {
+						for _, k := range left {
+							if k == expr.Val {
+								typ = Tag
+							}
+						}
+					}
+					continue
+				}
+				valuer := TypeValuerEval{
+					TypeMapper: v.TypeMapper,
+					Sources:    Sources{side},
+				}
+				if t, err := valuer.EvalType(&VarRef{Val: name}); err != nil {
+					return Unknown, err
+				} else if typ.LessThan(t) {
+					typ = t
+				}
 			}
 		}
 	}
@@ -4748,6 +5977,51 @@ func (v *TypeValuerEval) evalBinaryExprType(expr *BinaryExpr) (DataType, error)
 	return typ, nil
 }
 
+func (v *TypeValuerEval) evalCaseExprType(expr *CaseExpr) (DataType, error) {
+	results := make([]Expr, 0, len(expr.WhenClauses)+1)
+	for _, w := range expr.WhenClauses {
+		if _, err := v.EvalType(w.Condition); err != nil {
+			return Unknown, err
+		}
+		results = append(results, w.Result)
+	}
+	if expr.Else != nil {
+		results = append(results, expr.Else)
+	}
+
+	// All of the results must have the same type. Numeric results of
+	// different types are returned as a float.
+	var typ DataType
+	for _, e := range results {
+		t, err := v.EvalType(e)
+		if err != nil {
+			return Unknown, err
+		} else if t == Unknown || t == typ {
+			continue
+		}
+
+		switch {
+		case typ == Unknown:
+			typ = t
+		case isNumericType(typ) && isNumericType(t):
+			typ = Float
+		case (typ == String || typ == Tag) && (t == String || t == Tag):
+			typ = String
+		default:
+			return Unknown, &TypeError{
+				Expr:    expr,
+				Message: fmt.Sprintf("incompatible types: %s and %s", typ, t),
+			}
+		}
+	}
+	return typ, nil
+}
+
+// isNumericType returns true if the type is one of the numeric types.
+func isNumericType(typ DataType) bool {
+	return typ == Float || typ == Integer || typ == Unsigned
+}
+
 // TypeError is an error when two types are incompatible.
 type TypeError struct {
 	// Expr contains the expression that generated the type error.
@@ -4809,6 +6083,36 @@ func FieldDimensions(sources Sources, m FieldMapper) (fields map[string]DataType
 					dimensions[expr.Val] = struct{}{}
 				}
 			}
+		case *Join:
+			// The fields of each side are prefixed with the alias of
+			// that side. Only the tags of the left side in the join
+			// condition are dimensions of the join.
+			for _, side := range []struct {
+				src   Source
+				alias string
+			}{
+				{src: src.Left, alias: src.LeftAlias},
+				{src: src.Right, alias: src.RightAlias},
+			} {
+				f, _, err := FieldDimensions(Sources{side.src}, m)
+				if err != nil {
+					return nil, nil, err
+				}
+				for k, typ := range f {
+					k = side.alias + "." + k
+					if fields[k].LessThan(typ) {
+						fields[k] = typ
+					}
+				}
+			}
+
+			left, _, err := src.Keys()
+			if err != nil {
+				return nil, nil, err
+			}
+			for _, k := range left {
+				dimensions[k] = struct{}{}
+			}
 		}
 	}
 	return
@@ -4836,6 +6140,8 @@ func reduce(expr Expr, valuer Valuer) Expr {
 		return reduceBinaryExpr(expr, valuer)
 	case *Call:
 		return reduceCall(expr, valuer)
+	case *CaseExpr:
+		return reduceCaseExpr(expr, valuer)
 	case *ParenExpr:
 		return reduceParenExpr(expr, valuer)
 	case *VarRef:
@@ -4938,9 +6244,15 @@ func reduceBinaryExprDurationLHS(op Token, lhs *DurationLiteral, rhs Expr, loc *
 	case *DurationLiteral:
 		switch op {
 		case ADD:
-			return &DurationLiteral{Val: lhs.Val + rhs.Val}
+			return &DurationLiteral{Val: lhs.Val + rhs.Val, Months: lhs.Months + rhs.Months}
 		case SUB:
-			return &DurationLiteral{Val: lhs.Val - rhs.Val}
+			return &DurationLiteral{Val: lhs.Val - rhs.Val, Months: lhs.Months - rhs.Months}
+		}
+		if lhs.Months != 0 || rhs.Months != 0 {
+			// Calendar durations do not have a fixed length to compare.
+			break
+		}
+		switch op {
 		case EQ:
 			return &BooleanLiteral{Val: lhs.Val == rhs.Val}
 		case NEQ:
@@ -4955,6 +6267,9 @@ func reduceBinaryExprDurationLHS(op Token, lhs *DurationLiteral, rhs Expr, loc *
 			return &BooleanLiteral{Val: lhs.Val <= rhs.Val}
 		}
 	case *NumberLiteral:
+		if lhs.Months != 0 {
+			break
+		}
 		switch op {
 		case MUL:
 			return &DurationLiteral{Val: lhs.Val * time.Duration(rhs.Val)}
@@ -4967,8 +6282,11 @@ func reduceBinaryExprDurationLHS(op Token, lhs *DurationLiteral, rhs Expr, loc *
 	case *IntegerLiteral:
 		switch op {
 		case MUL:
-			return &DurationLiteral{Val: lhs.Val * time.Duration(rhs.Val)}
+			return &DurationLiteral{Val: lhs.Val * time.Duration(rhs.Val), Months: lhs.Months * int(rhs.Val)}
 		case DIV:
+			if lhs.Months != 0 {
+				break
+			}
 			if rhs.Val == 0 {
 				return &DurationLiteral{Val: 0}
 			}
@@ -4977,7 +6295,7 @@ func reduceBinaryExprDurationLHS(op Token, lhs *DurationLiteral, rhs Expr, loc *
 	case *TimeLiteral:
 		switch op {
 		case ADD:
-			return &TimeLiteral{Val: rhs.Val.Add(lhs.Val)}
+			return &TimeLiteral{Val: addDuration(rhs.Val, lhs, loc)}
 		}
 	case *StringLiteral:
 		t, err := rhs.ToTimeLiteral(loc)
@@ -5321,14 +6639,26 @@ func reduceBinaryExprStringLHS(op Token, lhs *StringLiteral, rhs Expr, loc *time
 	return &BinaryExpr{Op: op, LHS: lhs, RHS: rhs}
 }
 
+// addDuration adds a duration literal to t. Calendar months are added
+// in the location so the result is on the same day and time of the month.
+func addDuration(t time.Time, d *DurationLiteral, loc *time.Location) time.Time {
+	if d.Months != 0 {
+		if loc != nil {
+			t = t.In(loc)
+		}
+		t = t.AddDate(0, d.Months, 0)
+	}
+	return t.Add(d.Val)
+}
+
 func reduceBinaryExprTimeLHS(op Token, lhs *TimeLiteral, rhs Expr, loc *time.Location) Expr {
 	switch rhs := rhs.(type) {
 	case *DurationLiteral:
 		switch op {
 		case ADD:
-			return &TimeLiteral{Val: lhs.Val.Add(rhs.Val)}
+			return &TimeLiteral{Val: addDuration(lhs.Val, rhs, loc)}
 		case SUB:
-			return &TimeLiteral{Val: lhs.Val.Add(-rhs.Val)}
+			return &TimeLiteral{Val: addDuration(lhs.Val, &DurationLiteral{Val: -rhs.Val, Months: -rhs.Months}, loc)}
 		}
 	case *IntegerLiteral:
 		d := &DurationLiteral{Val: time.Duration(rhs.Val)}
@@ -5401,6 +6731,41 @@ func reduceCall(expr *Call, valuer Valuer) Expr {
 	return &Call{Name: expr.Name, Args: args}
 }
 
+func reduceCaseExpr(expr *CaseExpr, valuer Valuer) Expr {
+	other := &CaseExpr{}
+	chosen := false
+	for _, w := range expr.WhenClauses {
+		cond := reduce(w.Condition, valuer)
+		if lit, ok := cond.(*BooleanLiteral); ok {
+			if !lit.Val {
+				// This clause can never be chosen.
+				continue
+			}
+
+			// This clause is always chosen when it is reached so it
+			// replaces the remaining clauses.
+			other.Else = reduce(w.Result, valuer)
+			chosen = true
+			break
+		}
+		other.WhenClauses = append(other.WhenClauses, &WhenClause{
+			Condition: cond,
+			Result:    reduce(w.Result, valuer),
+		})
+	}
+	if !chosen {
+		other.Else = reduce(expr.Else, valuer)
+	}
+
+	if len(other.WhenClauses) == 0 {
+		if other.Else == nil {
+			return &NilLiteral{}
+		}
+		return other.Else
+	}
+	return other
+}
+
 func reduceParenExpr(expr *ParenExpr, valuer Valuer) Expr {
 	subexpr := reduce(expr.Expr, valuer)
 	if subexpr, ok := subexpr.(*BinaryExpr); ok {
@@ -165,6 +165,43 @@ func TestSelectStatement_GroupByInterval(t *testing.T) {
 	}
 }
 
+// Ensure the SELECT statement can extract a GROUP BY interval in calendar weeks.
+func TestSelectStatement_GroupByCalendarWeeks(t *testing.T) {
+	for _, tt := range []struct {
+		q        string
+		weeks    bool
+		interval time.Duration
+		offset   time.Duration
+	}{
+		{q: `SELECT sum(value) FROM foo GROUP BY time(1w)`, interval: 7 * 24 * time.Hour},
+		{q: `SELECT sum(value) FROM foo GROUP BY time(1cw)`, weeks: true, interval: 7 * 24 * time.Hour},
+		{q: `SELECT sum(value) FROM foo GROUP BY time(2cw, 6h)`, weeks: true, interval: 14 * 24 * time.Hour, offset: 6 * time.Hour},
+	} {
+		stmt, err := influxql.ParseStatement(tt.q)
+		if err != nil {
+			t.Fatalf("invalid statement: %q: %s", tt.q, err)
+		}
+
+		s := stmt.(*influxql.SelectStatement)
+		if weeks := s.GroupByCalendarWeeks(); weeks != tt.weeks {
+			t.Fatalf("%s: unexpected calendar weeks: %v", tt.q, weeks)
+		}
+		if d, err := s.GroupByInterval(); err != nil {
+			t.Fatalf("%s: error parsing group by interval: %s", tt.q, err)
+		} else if d != tt.interval {
+			t.Fatalf("%s: group by interval not equal:\nexp=%s\ngot=%s", tt.q, tt.interval, d)
+		}
+		if offset, err := s.GroupByOffset(); err != nil {
+			t.Fatalf("%s: error parsing group by offset: %s", tt.q, err)
+		} else if offset != tt.offset {
+			t.Fatalf("%s: group by offset not equal:\nexp=%s\ngot=%s", tt.q, tt.offset, offset)
+		}
+		if got := s.String(); got != tt.q {
+			t.Fatalf("unexpected string:\nexp=%s\ngot=%s", tt.q, got)
+		}
+	}
+}
+
 // Ensure the SELECT statement can have its start and end time set
 func TestSelectStatement_SetTimeRange(t *testing.T) {
 	q := "SELECT sum(value) from foo where time < now() GROUP BY time(10m)"
@@ -962,6 +999,24 @@ func TestParseString(t *testing.T) {
 		{
 			stmt: `DROP CONTINUOUS QUERY "my query" ON "my database"`,
 		},
+		{
+			stmt: `BACKFILL CONTINUOUS QUERY "my query" ON "my database" FROM '2020-01-01T00:00:00Z' TO '2020-01-02T00:00:00Z'`,
+		},
+		{
+			stmt: `CREATE DOWNSAMPLE POLICY "my policy" ON "my database" FROM "raw data" TO rollup EVERY 5m WITH mean, max`,
+		},
+		{
+			stmt: `DROP DOWNSAMPLE POLICY "my policy" ON "my database"`,
+		},
+		{
+			stmt: `SHOW DOWNSAMPLE POLICIES ON "my database"`,
+		},
+		{
+			stmt: `DROP FIELD "my field" FROM "my measurement"`,
+		},
+		{
+			stmt: `ALTER FIELD "my field" ON "my measurement" TYPE float`,
+		},
 		// See issues https://github.com/ayang64/reflux/issues/1647
 		// and https://github.com/ayang64/reflux/issues/4404
 		//{
@@ -973,6 +1028,9 @@ func TestParseString(t *testing.T) {
 		{
 			stmt: `CREATE SUBSCRIPTION "ugly \"subscription\" name" ON "\"my\" db"."\"my\" rp" DESTINATIONS ALL 'my host', 'my other host'`,
 		},
+		{
+			stmt: `CREATE SUBSCRIPTION sub0 ON db0.rp0 DESTINATIONS ANY 'my host' FIELDS "my field", level WHERE _name =~ /^alerts\./ AND "my tag" = 'x'`,
+		},
 		{
 			stmt: `SHOW MEASUREMENTS WITH MEASUREMENT =~ /foo/`,
 		},
@@ -1096,6 +1154,11 @@ func TestEval(t *testing.T) {
 		{in: `foo !~ /b.*/`, out: false, data: map[string]interface{}{"foo": "bar"}},
 		{in: `foo > 2 OR bar > 3`, out: true, data: map[string]interface{}{"foo": float64(4)}},
 		{in: `foo > 2 OR bar > 3`, out: true, data: map[string]interface{}{"bar": float64(4)}},
+
+		// CASE expressions.
+		{in: `CASE WHEN foo > 2 THEN 'high' WHEN foo > 1 THEN 'mid' ELSE 'low' END`, out: "mid", data: map[string]interface{}{"foo": float64(2)}},
+		{in: `CASE WHEN foo > 2 THEN 'high' ELSE 'low' END`, out: "low", data: map[string]interface{}{"foo": nil}},
+		{in: `CASE WHEN foo = 'err' THEN 1 END`, out: nil, data: map[string]interface{}{"foo": "ok"}},
 	} {
 		// Evaluate expression.
 		out := influxql.Eval(MustParseExpr(tt.in), tt.data)
@@ -1244,6 +1307,28 @@ func TestEvalType(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: `case with numeric results`,
+			in:   `CASE WHEN v1 = 'a' THEN v2 ELSE 0.5 END`,
+			typ:  influxql.Float,
+			data: EvalFixture{
+				"cpu": map[string]influxql.DataType{
+					"v1": influxql.String,
+					"v2": influxql.Integer,
+				},
+			},
+		},
+		{
+			name: `case with incompatible results`,
+			in:   `CASE WHEN v2 > 1 THEN v1 ELSE v2 END`,
+			err:  `type error: CASE WHEN v2 > 1 THEN v1 ELSE v2 END: incompatible types: string and integer`,
+			data: EvalFixture{
+				"cpu": map[string]influxql.DataType{
+					"v1": influxql.String,
+					"v2": influxql.Integer,
+				},
+			},
+		},
 	} {
 		sources := make([]influxql.Source, 0, len(tt.data))
 		for src := range tt.data {
@@ -1398,6 +1483,12 @@ func TestReduce(t *testing.T) {
 		{in: `foo = 'bar'`, out: `true`, data: map[string]interface{}{"foo": "bar"}},
 		{in: `foo = 'bar'`, out: `false`, data: map[string]interface{}{"foo": nil}},
 		{in: `foo <> 'bar'`, out: `false`, data: map[string]interface{}{"foo": nil}},
+
+		// CASE expressions.
+		{in: `CASE WHEN 1 > 2 THEN foo WHEN bar > 1 THEN 1 + 1 ELSE 3 END`, out: `CASE WHEN bar > 1 THEN 2 ELSE 3 END`},
+		{in: `CASE WHEN bar > 1 THEN 1 WHEN 2 > 1 THEN 2 ELSE 3 END`, out: `CASE WHEN bar > 1 THEN 1 ELSE 2 END`},
+		{in: `CASE WHEN 1 > 2 THEN 1 END`, out: `nil`},
+		{in: `CASE WHEN foo = 'bar' THEN 1 ELSE 2 END`, out: `1`, data: map[string]interface{}{"foo": "bar"}},
 	} {
 		// Fold expression.
 		expr := influxql.Reduce(MustParseExpr(tt.in), influxql.MultiValuer(
@@ -1730,7 +1821,9 @@ func Test_EnforceHasDefaultDatabase(t *testing.T) {
 		"CreateDatabaseStatement",
 		"CreateUserStatement",
 		"DeleteSeriesStatement",
+		"AlterFieldTypeStatement",
 		"DropDatabaseStatement",
+		"DropFieldStatement",
 		"DropMeasurementStatement",
 		"DropSeriesStatement",
 		"DropShardStatement",
@@ -1738,6 +1831,7 @@ func Test_EnforceHasDefaultDatabase(t *testing.T) {
 		"ExplainStatement",
 		"GrantAdminStatement",
 		"KillQueryStatement",
+		"KillTaskStatement",
 		"RevokeAdminStatement",
 		"SelectStatement",
 		"SetPasswordUserStatement",
@@ -1750,6 +1844,7 @@ func Test_EnforceHasDefaultDatabase(t *testing.T) {
 		"ShowShardsStatement",
 		"ShowStatsStatement",
 		"ShowSubscriptionsStatement",
+		"ShowTasksStatement",
 		"ShowUsersStatement",
 	}
 
@@ -107,6 +107,9 @@ func init() {
 		return p.parseDeleteStatement()
 	})
 	Language.Group(SHOW).With(func(show *ParseTree) {
+		show.Handle(AUDIT, func(p *Parser) (Statement, error) {
+			return p.parseShowAuditLogStatement()
+		})
 		show.Group(CONTINUOUS).Handle(QUERIES, func(p *Parser) (Statement, error) {
 			return p.parseShowContinuousQueriesStatement()
 		})
@@ -116,6 +119,9 @@ func init() {
 		show.Handle(DIAGNOSTICS, func(p *Parser) (Statement, error) {
 			return p.parseShowDiagnosticsStatement()
 		})
+		show.Group(DOWNSAMPLE).Handle(POLICIES, func(p *Parser) (Statement, error) {
+			return p.parseShowDownsamplePoliciesStatement()
+		})
 		show.Group(FIELD).With(func(field *ParseTree) {
 			field.Handle(KEY, func(p *Parser) (Statement, error) {
 				return p.parseShowFieldKeyCardinalityStatement()
@@ -133,15 +139,27 @@ func init() {
 		show.Group(MEASUREMENT).Handle(CARDINALITY, func(p *Parser) (Statement, error) {
 			return p.parseShowMeasurementCardinalityStatement(false)
 		})
+		show.Group(MEASUREMENT).Handle(SCHEMAS, func(p *Parser) (Statement, error) {
+			return p.parseShowMeasurementSchemasStatement()
+		})
 		show.Handle(MEASUREMENTS, func(p *Parser) (Statement, error) {
 			return p.parseShowMeasurementsStatement()
 		})
 		show.Handle(QUERIES, func(p *Parser) (Statement, error) {
 			return p.parseShowQueriesStatement()
 		})
+		show.Handle(QUOTAS, func(p *Parser) (Statement, error) {
+			return p.parseShowQuotasStatement()
+		})
 		show.Group(RETENTION).Handle(POLICIES, func(p *Parser) (Statement, error) {
 			return p.parseShowRetentionPoliciesStatement()
 		})
+		show.Handle(ROLES, func(p *Parser) (Statement, error) {
+			return p.parseShowRolesStatement()
+		})
+		show.Group(SECURITY).Handle(POLICIES, func(p *Parser) (Statement, error) {
+			return p.parseShowSecurityPoliciesStatement()
+		})
 		show.Handle(SERIES, func(p *Parser) (Statement, error) {
 			return p.parseShowSeriesStatement()
 		})
@@ -157,6 +175,9 @@ func init() {
 		show.Handle(SUBSCRIPTIONS, func(p *Parser) (Statement, error) {
 			return p.parseShowSubscriptionsStatement()
 		})
+		show.Handle(TASKS, func(p *Parser) (Statement, error) {
+			return p.parseShowTasksStatement()
+		})
 		show.Group(TAG).With(func(tag *ParseTree) {
 			tag.Handle(KEY, func(p *Parser) (Statement, error) {
 				return p.parseShowTagKeyCardinalityStatement()
@@ -168,6 +189,9 @@ func init() {
 				return p.parseShowTagValuesStatement()
 			})
 		})
+		show.Handle(TOKENS, func(p *Parser) (Statement, error) {
+			return p.parseShowTokensStatement()
+		})
 		show.Handle(USERS, func(p *Parser) (Statement, error) {
 			return p.parseShowUsersStatement()
 		})
@@ -179,15 +203,30 @@ func init() {
 		create.Handle(DATABASE, func(p *Parser) (Statement, error) {
 			return p.parseCreateDatabaseStatement()
 		})
+		create.Group(DOWNSAMPLE).Handle(POLICY, func(p *Parser) (Statement, error) {
+			return p.parseCreateDownsamplePolicyStatement()
+		})
+		create.Group(MEASUREMENT).Handle(SCHEMA, func(p *Parser) (Statement, error) {
+			return p.parseCreateMeasurementSchemaStatement()
+		})
 		create.Handle(USER, func(p *Parser) (Statement, error) {
 			return p.parseCreateUserStatement()
 		})
 		create.Group(RETENTION).Handle(POLICY, func(p *Parser) (Statement, error) {
 			return p.parseCreateRetentionPolicyStatement()
 		})
+		create.Handle(ROLE, func(p *Parser) (Statement, error) {
+			return p.parseCreateRoleStatement()
+		})
+		create.Group(SECURITY).Handle(POLICY, func(p *Parser) (Statement, error) {
+			return p.parseCreateSecurityPolicyStatement()
+		})
 		create.Handle(SUBSCRIPTION, func(p *Parser) (Statement, error) {
 			return p.parseCreateSubscriptionStatement()
 		})
+		create.Handle(TOKEN, func(p *Parser) (Statement, error) {
+			return p.parseCreateTokenStatement()
+		})
 	})
 	Language.Group(DROP).With(func(drop *ParseTree) {
 		drop.Group(CONTINUOUS).Handle(QUERY, func(p *Parser) (Statement, error) {
@@ -196,12 +235,32 @@ func init() {
 		drop.Handle(DATABASE, func(p *Parser) (Statement, error) {
 			return p.parseDropDatabaseStatement()
 		})
+		drop.Group(DOWNSAMPLE).Handle(POLICY, func(p *Parser) (Statement, error) {
+			return p.parseDropDownsamplePolicyStatement()
+		})
+		drop.Handle(FIELD, func(p *Parser) (Statement, error) {
+			return p.parseDropFieldStatement()
+		})
 		drop.Handle(MEASUREMENT, func(p *Parser) (Statement, error) {
+			// SCHEMA is a keyword so it cannot be the name of a measurement.
+			if tok, _, _ := p.ScanIgnoreWhitespace(); tok == SCHEMA {
+				return p.parseDropMeasurementSchemaStatement()
+			}
+			p.Unscan()
 			return p.parseDropMeasurementStatement()
 		})
+		drop.Handle(QUOTA, func(p *Parser) (Statement, error) {
+			return p.parseDropQuotaStatement()
+		})
 		drop.Group(RETENTION).Handle(POLICY, func(p *Parser) (Statement, error) {
 			return p.parseDropRetentionPolicyStatement()
 		})
+		drop.Handle(ROLE, func(p *Parser) (Statement, error) {
+			return p.parseDropRoleStatement()
+		})
+		drop.Group(SECURITY).Handle(POLICY, func(p *Parser) (Statement, error) {
+			return p.parseDropSecurityPolicyStatement()
+		})
 		drop.Handle(SERIES, func(p *Parser) (Statement, error) {
 			return p.parseDropSeriesStatement()
 		})
@@ -224,13 +283,31 @@ func init() {
 	Language.Handle(REVOKE, func(p *Parser) (Statement, error) {
 		return p.parseRevokeStatement()
 	})
-	Language.Group(ALTER, RETENTION).Handle(POLICY, func(p *Parser) (Statement, error) {
-		return p.parseAlterRetentionPolicyStatement()
+	Language.Group(ALTER).With(func(alter *ParseTree) {
+		alter.Handle(FIELD, func(p *Parser) (Statement, error) {
+			return p.parseAlterFieldTypeStatement()
+		})
+		alter.Group(RETENTION).Handle(POLICY, func(p *Parser) (Statement, error) {
+			return p.parseAlterRetentionPolicyStatement()
+		})
+	})
+	Language.Group(SET).With(func(set *ParseTree) {
+		set.Group(PASSWORD).Handle(FOR, func(p *Parser) (Statement, error) {
+			return p.parseSetPasswordUserStatement()
+		})
+		set.Handle(QUOTA, func(p *Parser) (Statement, error) {
+			return p.parseSetQuotaStatement()
+		})
 	})
-	Language.Group(SET, PASSWORD).Handle(FOR, func(p *Parser) (Statement, error) {
-		return p.parseSetPasswordUserStatement()
+	Language.Group(KILL).With(func(kill *ParseTree) {
+		kill.Handle(QUERY, func(p *Parser) (Statement, error) {
+			return p.parseKillQueryStatement()
+		})
+		kill.Handle(TASK, func(p *Parser) (Statement, error) {
+			return p.parseKillTaskStatement()
+		})
 	})
-	Language.Group(KILL).Handle(QUERY, func(p *Parser) (Statement, error) {
-		return p.parseKillQueryStatement()
+	Language.Group(BACKFILL, CONTINUOUS).Handle(QUERY, func(p *Parser) (Statement, error) {
+		return p.parseBackfillContinuousQueryStatement()
 	})
 }
@@ -144,6 +144,16 @@ func (p *Parser) parseKillQueryStatement() (*KillQueryStatement, error) {
 	return &KillQueryStatement{QueryID: qid, Host: host}, nil
 }
 
+// parseKillTaskStatement parses a string and returns a KillTaskStatement.
+// This function assumes the "KILL TASK" tokens have already been consumed.
+func (p *Parser) parseKillTaskStatement() (*KillTaskStatement, error) {
+	id, err := p.ParseInt(0, math.MaxInt32)
+	if err != nil {
+		return nil, err
+	}
+	return &KillTaskStatement{TaskID: id}, nil
+}
+
 // parseCreateSubscriptionStatement parses a string and returns a CreateSubscriptionStatement.
 // This function assumes the "CREATE SUBSCRIPTION" tokens have already been consumed.
 func (p *Parser) parseCreateSubscriptionStatement() (*CreateSubscriptionStatement, error) {
@@ -196,9 +206,60 @@ func (p *Parser) parseCreateSubscriptionStatement() (*CreateSubscriptionStatemen
 	}
 	stmt.Destinations = destinations
 
+	// FIELDS is not a keyword so that it can still be used as an identifier.
+	if tok, _, lit := p.ScanIgnoreWhitespace(); tok == IDENT && strings.ToUpper(lit) == "FIELDS" {
+		if stmt.Fields, err = p.ParseIdentList(); err != nil {
+			return nil, err
+		}
+	} else {
+		p.Unscan()
+	}
+
+	// Parse the condition on the measurement and tags of forwarded points.
+	if stmt.Condition, err = p.parseCondition(); err != nil {
+		return nil, err
+	} else if stmt.Condition != nil {
+		if err := validateSubscriptionCondition(stmt.Condition); err != nil {
+			return nil, err
+		}
+	}
+
 	return stmt, nil
 }
 
+// validateSubscriptionCondition returns an error if the condition of a
+// subscription does anything but compare the measurement name or tags with
+// strings and regular expressions.
+func validateSubscriptionCondition(expr Expr) error {
+	switch expr := expr.(type) {
+	case *ParenExpr:
+		return validateSubscriptionCondition(expr.Expr)
+	case *BinaryExpr:
+		switch expr.Op {
+		case AND, OR:
+			if err := validateSubscriptionCondition(expr.LHS); err != nil {
+				return err
+			}
+			return validateSubscriptionCondition(expr.RHS)
+		case EQ, NEQ, EQREGEX, NEQREGEX:
+			if _, ok := expr.LHS.(*VarRef); !ok {
+				break
+			}
+			switch expr.RHS.(type) {
+			case *StringLiteral:
+				if expr.Op == EQ || expr.Op == NEQ {
+					return nil
+				}
+			case *RegexLiteral:
+				if expr.Op == EQREGEX || expr.Op == NEQREGEX {
+					return nil
+				}
+			}
+		}
+	}
+	return fmt.Errorf("invalid subscription condition: %s", expr)
+}
+
 // parseCreateRetentionPolicyStatement parses a string and returns a create retention policy statement.
 // This function assumes the CREATE RETENTION POLICY tokens have already been consumed.
 func (p *Parser) parseCreateRetentionPolicyStatement() (*CreateRetentionPolicyStatement, error) {
@@ -272,6 +333,17 @@ func (p *Parser) parseCreateRetentionPolicyStatement() (*CreateRetentionPolicySt
 		p.Unscan()
 	}
 
+	// Parse optional FIELD TYPE option.
+	if tok, _, _ := p.ScanIgnoreWhitespace(); tok == FIELD {
+		policy, err := p.parseFieldTypePolicy()
+		if err != nil {
+			return nil, err
+		}
+		stmt.FieldTypePolicy = policy
+	} else {
+		p.Unscan()
+	}
+
 	// Parse optional DEFAULT token.
 	if tok, _, _ := p.ScanIgnoreWhitespace(); tok == DEFAULT {
 		stmt.Default = true
@@ -282,6 +354,44 @@ func (p *Parser) parseCreateRetentionPolicyStatement() (*CreateRetentionPolicySt
 	return stmt, nil
 }
 
+// parseAlterFieldTypeStatement parses a string and returns an AlterFieldTypeStatement.
+// This function assumes the ALTER FIELD tokens have already been consumed.
+func (p *Parser) parseAlterFieldTypeStatement() (*AlterFieldTypeStatement, error) {
+	stmt := &AlterFieldTypeStatement{}
+
+	// Parse the name of the field.
+	lit, err := p.ParseIdent()
+	if err != nil {
+		return nil, err
+	}
+	stmt.Name = lit
+
+	// Parse the measurement the field belongs to.
+	if err := p.parseTokens([]Token{ON}); err != nil {
+		return nil, err
+	}
+	if stmt.Measurement, err = p.ParseIdent(); err != nil {
+		return nil, err
+	}
+
+	// TYPE is not a keyword so that it can still be used as an identifier.
+	tok, pos, lit := p.ScanIgnoreWhitespace()
+	if tok != IDENT || strings.ToUpper(lit) != "TYPE" {
+		return nil, newParseError(tokstr(tok, lit), []string{"TYPE"}, pos)
+	}
+
+	// Parse the new type of the field.
+	tok, pos, lit = p.ScanIgnoreWhitespace()
+	if tok == IDENT {
+		switch typ := DataTypeFromString(strings.ToLower(lit)); typ {
+		case Float, Integer, Unsigned, String, Boolean:
+			stmt.Type = typ
+			return stmt, nil
+		}
+	}
+	return nil, newParseError(tokstr(tok, lit), []string{"float", "integer", "unsigned", "string", "boolean"}, pos)
+}
+
 // parseAlterRetentionPolicyStatement parses a string and returns an alter retention policy statement.
 // This function assumes the ALTER RETENTION POLICY tokens have already been consumed.
 func (p *Parser) parseAlterRetentionPolicyStatement() (*AlterRetentionPolicyStatement, error) {
@@ -355,11 +465,17 @@ Loop:
 			} else {
 				return nil, newParseError(tokstr(tok, lit), []string{"DURATION"}, pos)
 			}
+		case FIELD:
+			policy, err := p.parseFieldTypePolicy()
+			if err != nil {
+				return nil, err
+			}
+			stmt.FieldTypePolicy = &policy
 		case DEFAULT:
 			stmt.Default = true
 		default:
 			if len(found) == 0 {
-				return nil, newParseError(tokstr(tok, lit), []string{"DURATION", "REPLICATION", "SHARD", "DEFAULT"}, pos)
+				return nil, newParseError(tokstr(tok, lit), []string{"DURATION", "REPLICATION", "SHARD", "FIELD", "DEFAULT"}, pos)
 			}
 			p.Unscan()
 			break Loop
@@ -370,6 +486,26 @@ Loop:
 	return stmt, nil
 }
 
+// parseFieldTypePolicy parses the policy of a FIELD TYPE retention policy
+// option. This function assumes the FIELD token has already been consumed.
+func (p *Parser) parseFieldTypePolicy() (string, error) {
+	// TYPE is not a keyword so that it can still be used as an identifier.
+	tok, pos, lit := p.ScanIgnoreWhitespace()
+	if tok != IDENT || strings.ToUpper(lit) != "TYPE" {
+		return "", newParseError(tokstr(tok, lit), []string{"TYPE"}, pos)
+	}
+
+	// The policy may be quoted since coerce-numeric is not a valid identifier.
+	tok, pos, lit = p.ScanIgnoreWhitespace()
+	if tok == IDENT || tok == STRING {
+		switch policy := strings.ToLower(lit); policy {
+		case "strict", "coerce-numeric", "stringify":
+			return policy, nil
+		}
+	}
+	return "", newParseError(tokstr(tok, lit), []string{"strict", "coerce-numeric", "stringify"}, pos)
+}
+
 // ParseInt parses a string representing a base 10 integer and returns the number.
 // It returns an error if the parsed number is outside the range [min, max].
 func (p *Parser) ParseInt(min, max int) (int, error) {
@@ -422,6 +558,11 @@ func (p *Parser) ParseDuration() (time.Duration, error) {
 
 	d, err := ParseDuration(lit)
 	if err != nil {
+		if months, _, cerr := ParseCalendarDuration(lit); cerr == nil && months != 0 {
+			err = ErrCalendarDuration
+		} else if _, cerr := ParseCalendarWeeks(lit); cerr == nil {
+			err = ErrCalendarDuration
+		}
 		return 0, &ParseError{Message: err.Error(), Pos: pos}
 	}
 
@@ -542,6 +683,14 @@ func (p *Parser) parseStringList() ([]string, error) {
 // parseRevokeStatement parses a string and returns a revoke statement.
 // This function assumes the REVOKE token has already been consumed.
 func (p *Parser) parseRevokeStatement() (Statement, error) {
+	// Check for a role being revoked from a user or a token being revoked.
+	if tok, _, _ := p.ScanIgnoreWhitespace(); tok == ROLE {
+		return p.parseRevokeRoleStatement()
+	} else if tok == TOKEN {
+		return p.parseRevokeTokenStatement()
+	}
+	p.Unscan()
+
 	// Parse the privilege to be revoked.
 	priv, err := p.parsePrivilege()
 	if err != nil {
@@ -584,6 +733,11 @@ func (p *Parser) parseRevokeOnStatement() (*RevokeStatement, error) {
 	}
 	stmt.On = lit
 
+	// Parse optional MEASUREMENT clause.
+	if stmt.Measurement, err = p.parsePrivilegeMeasurement(); err != nil {
+		return nil, err
+	}
+
 	// Parse FROM clause.
 	tok, pos, lit := p.ScanIgnoreWhitespace()
 
@@ -592,7 +746,12 @@ func (p *Parser) parseRevokeOnStatement() (*RevokeStatement, error) {
 		return nil, newParseError(tokstr(tok, lit), []string{"FROM"}, pos)
 	}
 
-	// Parse the name of the user.
+	// Parse the name of the user or the role.
+	if stmt.Role, err = p.parsePrivilegeRole(stmt.Measurement); err != nil {
+		return nil, err
+	} else if stmt.Role != "" {
+		return stmt, nil
+	}
 	lit, err = p.ParseIdent()
 	if err != nil {
 		return nil, err
@@ -602,6 +761,41 @@ func (p *Parser) parseRevokeOnStatement() (*RevokeStatement, error) {
 	return stmt, nil
 }
 
+// parseRevokeRoleStatement parses a string and returns a revoke role statement.
+// This function assumes the REVOKE ROLE tokens have already been consumed.
+func (p *Parser) parseRevokeRoleStatement() (*RevokeRoleStatement, error) {
+	stmt := &RevokeRoleStatement{}
+
+	// Parse the name of the role.
+	lit, err := p.ParseIdent()
+	if err != nil {
+		return nil, err
+	}
+	stmt.Role = lit
+
+	// Parse FROM clause.
+	if tok, pos, lit := p.ScanIgnoreWhitespace(); tok != FROM {
+		return nil, newParseError(tokstr(tok, lit), []string{"FROM"}, pos)
+	}
+
+	// Parse the name of the user.
+	if stmt.User, err = p.ParseIdent(); err != nil {
+		return nil, err
+	}
+
+	return stmt, nil
+}
+
+// parseRevokeTokenStatement parses a string and returns a revoke token statement.
+// This function assumes the REVOKE TOKEN tokens have already been consumed.
+func (p *Parser) parseRevokeTokenStatement() (*RevokeTokenStatement, error) {
+	id, err := p.parseString()
+	if err != nil {
+		return nil, err
+	}
+	return &RevokeTokenStatement{ID: id}, nil
+}
+
 // parseRevokeAdminStatement parses a string and returns a revoke admin statement.
 // This function assumes the ALL [PRVILEGES] FROM token has already been consumed.
 func (p *Parser) parseRevokeAdminStatement() (*RevokeAdminStatement, error) {
@@ -621,6 +815,12 @@ func (p *Parser) parseRevokeAdminStatement() (*RevokeAdminStatement, error) {
 // parseGrantStatement parses a string and returns a grant statement.
 // This function assumes the GRANT token has already been consumed.
 func (p *Parser) parseGrantStatement() (Statement, error) {
+	// Check for a role being granted to a user.
+	if tok, _, _ := p.ScanIgnoreWhitespace(); tok == ROLE {
+		return p.parseGrantRoleStatement()
+	}
+	p.Unscan()
+
 	// Parse the privilege to be granted.
 	priv, err := p.parsePrivilege()
 	if err != nil {
@@ -663,6 +863,11 @@ func (p *Parser) parseGrantOnStatement() (*GrantStatement, error) {
 	}
 	stmt.On = lit
 
+	// Parse optional MEASUREMENT clause.
+	if stmt.Measurement, err = p.parsePrivilegeMeasurement(); err != nil {
+		return nil, err
+	}
+
 	// Parse TO clause.
 	tok, pos, lit := p.ScanIgnoreWhitespace()
 
@@ -671,7 +876,12 @@ func (p *Parser) parseGrantOnStatement() (*GrantStatement, error) {
 		return nil, newParseError(tokstr(tok, lit), []string{"TO"}, pos)
 	}
 
-	// Parse the name of the user.
+	// Parse the name of the user or the role.
+	if stmt.Role, err = p.parsePrivilegeRole(stmt.Measurement); err != nil {
+		return nil, err
+	} else if stmt.Role != "" {
+		return stmt, nil
+	}
 	lit, err = p.ParseIdent()
 	if err != nil {
 		return nil, err
@@ -681,6 +891,67 @@ func (p *Parser) parseGrantOnStatement() (*GrantStatement, error) {
 	return stmt, nil
 }
 
+// parseGrantRoleStatement parses a string and returns a grant role statement.
+// This function assumes the GRANT ROLE tokens have already been consumed.
+func (p *Parser) parseGrantRoleStatement() (*GrantRoleStatement, error) {
+	stmt := &GrantRoleStatement{}
+
+	// Parse the name of the role.
+	lit, err := p.ParseIdent()
+	if err != nil {
+		return nil, err
+	}
+	stmt.Role = lit
+
+	// Parse TO clause.
+	if tok, pos, lit := p.ScanIgnoreWhitespace(); tok != TO {
+		return nil, newParseError(tokstr(tok, lit), []string{"TO"}, pos)
+	}
+
+	// Parse the name of the user.
+	if stmt.User, err = p.ParseIdent(); err != nil {
+		return nil, err
+	}
+
+	return stmt, nil
+}
+
+// parsePrivilegeMeasurement parses the optional MEASUREMENT clause of a grant
+// or a revoke statement. It returns nil if there is no such clause.
+func (p *Parser) parsePrivilegeMeasurement() (*Measurement, error) {
+	if tok, _, _ := p.ScanIgnoreWhitespace(); tok != MEASUREMENT {
+		p.Unscan()
+		return nil, nil
+	}
+
+	// The measurement is either a regex or a name.
+	re, err := p.parseRegex()
+	if err != nil {
+		return nil, err
+	} else if re != nil {
+		return &Measurement{Regex: re}, nil
+	}
+	name, err := p.ParseIdent()
+	if err != nil {
+		return nil, err
+	}
+	return &Measurement{Name: name}, nil
+}
+
+// parsePrivilegeRole parses the name of a role after the TO or FROM token of a
+// grant or a revoke statement. It returns an empty name if the privilege is
+// for a user, which is only allowed if it is not limited to measurements.
+func (p *Parser) parsePrivilegeRole(m *Measurement) (string, error) {
+	tok, pos, lit := p.ScanIgnoreWhitespace()
+	if tok == ROLE {
+		return p.ParseIdent()
+	} else if m != nil {
+		return "", newParseError(tokstr(tok, lit), []string{"ROLE"}, pos)
+	}
+	p.Unscan()
+	return "", nil
+}
+
 // parseGrantAdminStatement parses a string and returns a grant admin statement.
 // This function assumes the ALL [PRVILEGES] TO tokens have already been consumed.
 func (p *Parser) parseGrantAdminStatement() (*GrantAdminStatement, error) {
@@ -755,6 +1026,11 @@ func (p *Parser) parseSelectStatement(tr targetRequirement) (*SelectStatement, e
 		return nil, err
 	}
 
+	// Parse aggregate condition: "HAVING EXPR".
+	if stmt.Having, err = p.parseHaving(); err != nil {
+		return nil, err
+	}
+
 	// Parse sort: "ORDER BY FIELD+".
 	if stmt.SortFields, err = p.parseOrderBy(); err != nil {
 		return nil, err
@@ -1124,6 +1400,18 @@ func (p *Parser) parseShowQueriesStatement() (*ShowQueriesStatement, error) {
 	return &ShowQueriesStatement{}, nil
 }
 
+// parseShowQuotasStatement parses a string and returns a ShowQuotasStatement.
+// This function assumes the "SHOW QUOTAS" tokens have been consumed.
+func (p *Parser) parseShowQuotasStatement() (*ShowQuotasStatement, error) {
+	return &ShowQuotasStatement{}, nil
+}
+
+// parseShowTasksStatement parses a string and returns a ShowTasksStatement.
+// This function assumes the "SHOW TASKS" tokens have been consumed.
+func (p *Parser) parseShowTasksStatement() (*ShowTasksStatement, error) {
+	return &ShowTasksStatement{}, nil
+}
+
 // parseShowRetentionPoliciesStatement parses a string and returns a ShowRetentionPoliciesStatement.
 // This function assumes the "SHOW RETENTION POLICIES" tokens have been consumed.
 func (p *Parser) parseShowRetentionPoliciesStatement() (*ShowRetentionPoliciesStatement, error) {
@@ -1431,6 +1719,54 @@ func (p *Parser) parseShowUsersStatement() (*ShowUsersStatement, error) {
 	return &ShowUsersStatement{}, nil
 }
 
+// parseShowRolesStatement parses a string and returns a ShowRolesStatement.
+// This function assumes the "SHOW ROLES" tokens have been consumed.
+func (p *Parser) parseShowRolesStatement() (*ShowRolesStatement, error) {
+	return &ShowRolesStatement{}, nil
+}
+
+// parseShowSecurityPoliciesStatement parses a string and returns a ShowSecurityPoliciesStatement.
+// This function assumes the "SHOW SECURITY POLICIES" tokens have been consumed.
+func (p *Parser) parseShowSecurityPoliciesStatement() (*ShowSecurityPoliciesStatement, error) {
+	stmt := &ShowSecurityPoliciesStatement{}
+
+	// Parse optional ON clause.
+	if tok, _, _ := p.ScanIgnoreWhitespace(); tok == ON {
+		ident, err := p.ParseIdent()
+		if err != nil {
+			return nil, err
+		}
+		stmt.Database = ident
+	} else {
+		p.Unscan()
+	}
+
+	return stmt, nil
+}
+
+// parseShowTokensStatement parses a string and returns a ShowTokensStatement.
+// This function assumes the "SHOW TOKENS" tokens have been consumed.
+func (p *Parser) parseShowTokensStatement() (*ShowTokensStatement, error) {
+	stmt := &ShowTokensStatement{}
+
+	// Parse optional FOR USER clause.
+	if tok, _, _ := p.ScanIgnoreWhitespace(); tok != FOR {
+		p.Unscan()
+		return stmt, nil
+	}
+	if tok, pos, lit := p.ScanIgnoreWhitespace(); tok != USER {
+		return nil, newParseError(tokstr(tok, lit), []string{"USER"}, pos)
+	}
+
+	lit, err := p.ParseIdent()
+	if err != nil {
+		return nil, err
+	}
+	stmt.User = lit
+
+	return stmt, nil
+}
+
 // parseShowSubscriptionsStatement parses a string and returns a ShowSubscriptionsStatement
 // This function assumes the "SHOW SUBSCRIPTIONS" tokens have been consumed.
 func (p *Parser) parseShowSubscriptionsStatement() (*ShowSubscriptionsStatement, error) {
@@ -1554,6 +1890,29 @@ func (p *Parser) parseDropMeasurementStatement() (*DropMeasurementStatement, err
 	return stmt, nil
 }
 
+// parseDropFieldStatement parses a string and returns a DropFieldStatement.
+// This function assumes the "DROP FIELD" tokens have already been consumed.
+func (p *Parser) parseDropFieldStatement() (*DropFieldStatement, error) {
+	stmt := &DropFieldStatement{}
+
+	// Parse the name of the field to be dropped.
+	lit, err := p.ParseIdent()
+	if err != nil {
+		return nil, err
+	}
+	stmt.Name = lit
+
+	// Parse the measurement the field belongs to.
+	if err := p.parseTokens([]Token{FROM}); err != nil {
+		return nil, err
+	}
+	if stmt.Measurement, err = p.ParseIdent(); err != nil {
+		return nil, err
+	}
+
+	return stmt, nil
+}
+
 // parseDropSeriesStatement parses a string and returns a DropSeriesStatement.
 // This function assumes the "DROP SERIES" tokens have already been consumed.
 func (p *Parser) parseDropSeriesStatement() (*DropSeriesStatement, error) {
@@ -1616,6 +1975,26 @@ func (p *Parser) parseDropShardStatement() (*DropShardStatement, error) {
 	return stmt, nil
 }
 
+// parseShowAuditLogStatement parses a string and returns a ShowAuditLogStatement.
+// This function assumes the "SHOW AUDIT" tokens have already been consumed.
+func (p *Parser) parseShowAuditLogStatement() (*ShowAuditLogStatement, error) {
+	// LOG is not a keyword so that log() can still be used as a function.
+	tok, pos, lit := p.ScanIgnoreWhitespace()
+	if tok != IDENT || strings.ToUpper(lit) != "LOG" {
+		return nil, newParseError(tokstr(tok, lit), []string{"LOG"}, pos)
+	}
+
+	stmt := &ShowAuditLogStatement{}
+
+	// Parse limit: "LIMIT <n>".
+	var err error
+	if stmt.Limit, err = p.ParseOptionalTokenAndInt(LIMIT); err != nil {
+		return nil, err
+	}
+
+	return stmt, nil
+}
+
 // parseShowContinuousQueriesStatement parses a string and returns a ShowContinuousQueriesStatement.
 // This function assumes the "SHOW CONTINUOUS" tokens have already been consumed.
 func (p *Parser) parseShowContinuousQueriesStatement() (*ShowContinuousQueriesStatement, error) {
@@ -1667,10 +2046,12 @@ func (p *Parser) parseCreateContinuousQueryStatement() (*CreateContinuousQuerySt
 	stmt.Database = ident
 
 	if tok, _, _ := p.ScanIgnoreWhitespace(); tok == RESAMPLE {
-		stmt.ResampleEvery, stmt.ResampleFor, err = p.parseResample()
+		every, maxDuration, err := p.parseResample()
 		if err != nil {
 			return nil, err
 		}
+		stmt.ResampleEvery, stmt.ResampleEveryMonths = every.Val, every.Months
+		stmt.ResampleFor, stmt.ResampleForMonths = maxDuration.Val, maxDuration.Months
 	} else {
 		p.Unscan()
 	}
@@ -1690,7 +2071,11 @@ func (p *Parser) parseCreateContinuousQueryStatement() (*CreateContinuousQuerySt
 	// validate that the statement has a non-zero group by interval if it is aggregated
 	if !source.IsRawQuery {
 		d, err := source.GroupByInterval()
-		if d == 0 || err != nil {
+		var months int
+		if err == nil {
+			months, err = source.GroupByMonths()
+		}
+		if (d == 0 && months == 0) || err != nil {
 			// rewind so we can output an error with some info
 			p.Unscan() // Unscan the whitespace
 			p.Unscan() // Unscan the last token
@@ -1922,96 +2307,730 @@ func (p *Parser) parseDropUserStatement() (*DropUserStatement, error) {
 	return stmt, nil
 }
 
-// parseExplainStatement parses a string and return an ExplainStatement.
-// This function assumes the EXPLAIN token has already been consumed.
-func (p *Parser) parseExplainStatement() (*ExplainStatement, error) {
-	stmt := &ExplainStatement{}
-
-	if tok, _, _ := p.ScanIgnoreWhitespace(); tok == ANALYZE {
-		stmt.Analyze = true
-	} else {
-		p.Unscan()
-	}
-
-	if tok, pos, lit := p.ScanIgnoreWhitespace(); tok != SELECT {
-		return nil, newParseError(tokstr(tok, lit), []string{"SELECT"}, pos)
-	}
+// parseCreateRoleStatement parses a string and returns a CreateRoleStatement.
+// This function assumes the "CREATE ROLE" tokens have already been consumed.
+func (p *Parser) parseCreateRoleStatement() (*CreateRoleStatement, error) {
+	stmt := &CreateRoleStatement{}
 
-	s, err := p.parseSelectStatement(targetNotRequired)
+	// Parse the name of the role to be created.
+	lit, err := p.ParseIdent()
 	if err != nil {
 		return nil, err
 	}
-	stmt.Statement = s
-	return stmt, nil
-}
-
-// parseShowShardGroupsStatement parses a string for "SHOW SHARD GROUPS" statement.
-// This function assumes the "SHOW SHARD GROUPS" tokens have already been consumed.
-func (p *Parser) parseShowShardGroupsStatement() (*ShowShardGroupsStatement, error) {
-	return &ShowShardGroupsStatement{}, nil
-}
+	stmt.Name = lit
 
-// parseShowShardsStatement parses a string for "SHOW SHARDS" statement.
-// This function assumes the "SHOW SHARDS" tokens have already been consumed.
-func (p *Parser) parseShowShardsStatement() (*ShowShardsStatement, error) {
-	return &ShowShardsStatement{}, nil
+	return stmt, nil
 }
 
-// parseShowStatsStatement parses a string and returns a ShowStatsStatement.
-// This function assumes the "SHOW STATS" tokens have already been consumed.
-func (p *Parser) parseShowStatsStatement() (*ShowStatsStatement, error) {
-	stmt := &ShowStatsStatement{}
-	var err error
+// parseCreateTokenStatement parses a string and returns a CreateTokenStatement.
+// This function assumes the "CREATE TOKEN" tokens have already been consumed.
+func (p *Parser) parseCreateTokenStatement() (*CreateTokenStatement, error) {
+	stmt := &CreateTokenStatement{}
 
-	if tok, _, _ := p.ScanIgnoreWhitespace(); tok == FOR {
-		stmt.Module, err = p.parseString()
-	} else {
-		p.Unscan()
+	// Parse the name of the user the token is created for.
+	if err := p.parseTokens([]Token{FOR, USER}); err != nil {
+		return nil, err
+	}
+	lit, err := p.ParseIdent()
+	if err != nil {
+		return nil, err
+	}
+	stmt.User = lit
+
+	// Parse optional WITH clause with the privileges of the token.
+	if tok, _, _ := p.ScanIgnoreWhitespace(); tok == WITH {
+		for {
+			priv, err := p.parsePrivilege()
+			if err != nil {
+				return nil, err
+			}
+			if tok, pos, lit := p.ScanIgnoreWhitespace(); tok != ON {
+				return nil, newParseError(tokstr(tok, lit), []string{"ON"}, pos)
+			}
+			database, err := p.ParseIdent()
+			if err != nil {
+				return nil, err
+			}
+			stmt.Privileges = append(stmt.Privileges, &TokenPrivilege{Privilege: priv, Database: database})
+
+			if tok, _, _ := p.ScanIgnoreWhitespace(); tok != COMMA {
+				p.Unscan()
+				break
+			}
+		}
+	} else {
+		p.Unscan()
+	}
+
+	// Parse optional DURATION clause.
+	if tok, _, _ := p.ScanIgnoreWhitespace(); tok == DURATION {
+		if stmt.Duration, err = p.ParseDuration(); err != nil {
+			return nil, err
+		}
+	} else {
+		p.Unscan()
+	}
+
+	// DESCRIPTION is not a keyword so that it can still be used as an identifier.
+	if tok, _, lit := p.ScanIgnoreWhitespace(); tok == IDENT && strings.ToUpper(lit) == "DESCRIPTION" {
+		if stmt.Description, err = p.parseString(); err != nil {
+			return nil, err
+		}
+	} else {
+		p.Unscan()
+	}
+
+	return stmt, nil
+}
+
+// parseDropRoleStatement parses a string and returns a DropRoleStatement.
+// This function assumes the "DROP ROLE" tokens have already been consumed.
+func (p *Parser) parseDropRoleStatement() (*DropRoleStatement, error) {
+	stmt := &DropRoleStatement{}
+
+	// Parse the name of the role to be dropped.
+	lit, err := p.ParseIdent()
+	if err != nil {
+		return nil, err
+	}
+	stmt.Name = lit
+
+	return stmt, nil
+}
+
+// parseSetQuotaStatement parses a string and returns a SetQuotaStatement.
+// This function assumes the "SET QUOTA" tokens have already been consumed.
+func (p *Parser) parseSetQuotaStatement() (*SetQuotaStatement, error) {
+	stmt := &SetQuotaStatement{}
+
+	// Consume the required ON token.
+	if tok, pos, lit := p.ScanIgnoreWhitespace(); tok != ON {
+		return nil, newParseError(tokstr(tok, lit), []string{"ON"}, pos)
+	}
+
+	// Parse the database name.
+	ident, err := p.ParseIdent()
+	if err != nil {
+		return nil, err
+	}
+	stmt.Database = ident
+
+	// Loop through the limits. DISK, POINTS, PER and SECOND are not keywords
+	// so that they can still be used as identifiers.
+	found := make(map[string]struct{})
+Loop:
+	for {
+		tok, pos, lit := p.ScanIgnoreWhitespace()
+		option := tok.String()
+		if tok == IDENT {
+			option = strings.ToUpper(lit)
+		}
+		if _, ok := found[option]; ok {
+			return nil, &ParseError{
+				Message: fmt.Sprintf("found duplicate %s option", option),
+				Pos:     pos,
+			}
+		}
+
+		switch {
+		case tok == IDENT && option == "DISK":
+			if stmt.MaxDiskBytes, err = p.parseQuotaLimit(); err != nil {
+				return nil, err
+			}
+		case tok == SERIES:
+			if stmt.MaxSeries, err = p.parseQuotaLimit(); err != nil {
+				return nil, err
+			}
+		case tok == IDENT && option == "POINTS":
+			for _, word := range []string{"PER", "SECOND"} {
+				if tok, pos, lit := p.ScanIgnoreWhitespace(); tok != IDENT || strings.ToUpper(lit) != word {
+					return nil, newParseError(tokstr(tok, lit), []string{word}, pos)
+				}
+			}
+			if stmt.MaxPointsPerSecond, err = p.parseQuotaLimit(); err != nil {
+				return nil, err
+			}
+		case tok == QUERIES:
+			if stmt.MaxConcurrentQueries, err = p.ParseInt(0, math.MaxInt32); err != nil {
+				return nil, err
+			}
+		default:
+			if len(found) == 0 {
+				return nil, newParseError(tokstr(tok, lit), []string{"DISK", "SERIES", "POINTS", "QUERIES"}, pos)
+			}
+			p.Unscan()
+			break Loop
+		}
+		found[option] = struct{}{}
+	}
+
+	return stmt, nil
+}
+
+// parseQuotaLimit parses the limit of a quota option.
+func (p *Parser) parseQuotaLimit() (int64, error) {
+	tok, pos, lit := p.ScanIgnoreWhitespace()
+	if tok != INTEGER {
+		return 0, newParseError(tokstr(tok, lit), []string{"integer"}, pos)
+	}
+
+	n, err := strconv.ParseInt(lit, 10, 64)
+	if err != nil {
+		return 0, &ParseError{Message: err.Error(), Pos: pos}
+	}
+	return n, nil
+}
+
+// parseDropQuotaStatement parses a string and returns a DropQuotaStatement.
+// This function assumes the "DROP QUOTA" tokens have already been consumed.
+func (p *Parser) parseDropQuotaStatement() (*DropQuotaStatement, error) {
+	stmt := &DropQuotaStatement{}
+
+	// Consume the required ON token.
+	if tok, pos, lit := p.ScanIgnoreWhitespace(); tok != ON {
+		return nil, newParseError(tokstr(tok, lit), []string{"ON"}, pos)
+	}
+
+	// Parse the database name.
+	ident, err := p.ParseIdent()
+	if err != nil {
+		return nil, err
+	}
+	stmt.Database = ident
+
+	return stmt, nil
+}
+
+// parseCreateSecurityPolicyStatement parses a string and returns a CreateSecurityPolicyStatement.
+// This function assumes the "CREATE SECURITY POLICY" tokens have already been consumed.
+func (p *Parser) parseCreateSecurityPolicyStatement() (*CreateSecurityPolicyStatement, error) {
+	stmt := &CreateSecurityPolicyStatement{}
+
+	// Parse the database and the user or role the policy applies to.
+	var err error
+	if stmt.Database, stmt.User, stmt.Role, err = p.parseSecurityPolicyTarget(); err != nil {
+		return nil, err
+	}
+
+	// USING is not a keyword so that it can still be used as an identifier.
+	if tok, pos, lit := p.ScanIgnoreWhitespace(); tok != IDENT || strings.ToUpper(lit) != "USING" {
+		return nil, newParseError(tokstr(tok, lit), []string{"USING"}, pos)
+	}
+
+	// Parse the parenthesized condition.
+	tok, pos, lit := p.ScanIgnoreWhitespace()
+	if tok != LPAREN {
+		return nil, newParseError(tokstr(tok, lit), []string{"("}, pos)
+	}
+	if stmt.Condition, err = p.ParseExpr(); err != nil {
+		return nil, err
+	}
+	if tok, pos, lit := p.ScanIgnoreWhitespace(); tok != RPAREN {
+		return nil, newParseError(tokstr(tok, lit), []string{")"}, pos)
+	}
+	if err := validateSecurityPolicyCondition(stmt.Condition); err != nil {
+		return nil, &ParseError{Message: err.Error(), Pos: pos}
+	}
+
+	return stmt, nil
+}
+
+// parseDropSecurityPolicyStatement parses a string and returns a DropSecurityPolicyStatement.
+// This function assumes the "DROP SECURITY POLICY" tokens have already been consumed.
+func (p *Parser) parseDropSecurityPolicyStatement() (*DropSecurityPolicyStatement, error) {
+	stmt := &DropSecurityPolicyStatement{}
+
+	var err error
+	if stmt.Database, stmt.User, stmt.Role, err = p.parseSecurityPolicyTarget(); err != nil {
+		return nil, err
+	}
+	return stmt, nil
+}
+
+// parseSecurityPolicyTarget parses the "ON db FOR USER|ROLE name" clause of
+// a security policy statement.
+func (p *Parser) parseSecurityPolicyTarget() (database, user, role string, err error) {
+	if tok, pos, lit := p.ScanIgnoreWhitespace(); tok != ON {
+		return "", "", "", newParseError(tokstr(tok, lit), []string{"ON"}, pos)
+	}
+	if database, err = p.ParseIdent(); err != nil {
+		return "", "", "", err
+	}
+
+	if tok, pos, lit := p.ScanIgnoreWhitespace(); tok != FOR {
+		return "", "", "", newParseError(tokstr(tok, lit), []string{"FOR"}, pos)
+	}
+	switch tok, pos, lit := p.ScanIgnoreWhitespace(); tok {
+	case USER:
+		user, err = p.ParseIdent()
+	case ROLE:
+		role, err = p.ParseIdent()
+	default:
+		return "", "", "", newParseError(tokstr(tok, lit), []string{"USER", "ROLE"}, pos)
+	}
+	if err != nil {
+		return "", "", "", err
+	}
+	return database, user, role, nil
+}
+
+// validateSecurityPolicyCondition returns an error if the condition of a
+// security policy does anything other than compare tags to strings or regular
+// expressions.
+func validateSecurityPolicyCondition(expr Expr) error {
+	switch expr := expr.(type) {
+	case *ParenExpr:
+		return validateSecurityPolicyCondition(expr.Expr)
+	case *BinaryExpr:
+		switch expr.Op {
+		case AND, OR:
+			if err := validateSecurityPolicyCondition(expr.LHS); err != nil {
+				return err
+			}
+			return validateSecurityPolicyCondition(expr.RHS)
+		case EQ, NEQ:
+			if _, ok := expr.LHS.(*VarRef); ok {
+				if _, ok := expr.RHS.(*StringLiteral); ok {
+					return nil
+				}
+			}
+		case EQREGEX, NEQREGEX:
+			if _, ok := expr.LHS.(*VarRef); ok {
+				if _, ok := expr.RHS.(*RegexLiteral); ok {
+					return nil
+				}
+			}
+		}
+	}
+	return fmt.Errorf("security policy condition must compare tags to strings or regular expressions: %s", expr)
+}
+
+// parseExplainStatement parses a string and return an ExplainStatement.
+// This function assumes the EXPLAIN token has already been consumed.
+func (p *Parser) parseExplainStatement() (*ExplainStatement, error) {
+	stmt := &ExplainStatement{}
+
+	if tok, _, _ := p.ScanIgnoreWhitespace(); tok == ANALYZE {
+		stmt.Analyze = true
+	} else {
+		p.Unscan()
+	}
+
+	if tok, pos, lit := p.ScanIgnoreWhitespace(); tok != SELECT {
+		return nil, newParseError(tokstr(tok, lit), []string{"SELECT"}, pos)
+	}
+
+	s, err := p.parseSelectStatement(targetNotRequired)
+	if err != nil {
+		return nil, err
+	}
+	stmt.Statement = s
+	return stmt, nil
+}
+
+// parseShowShardGroupsStatement parses a string for "SHOW SHARD GROUPS" statement.
+// This function assumes the "SHOW SHARD GROUPS" tokens have already been consumed.
+func (p *Parser) parseShowShardGroupsStatement() (*ShowShardGroupsStatement, error) {
+	return &ShowShardGroupsStatement{}, nil
+}
+
+// parseShowShardsStatement parses a string for "SHOW SHARDS" statement.
+// This function assumes the "SHOW SHARDS" tokens have already been consumed.
+func (p *Parser) parseShowShardsStatement() (*ShowShardsStatement, error) {
+	return &ShowShardsStatement{}, nil
+}
+
+// parseShowStatsStatement parses a string and returns a ShowStatsStatement.
+// This function assumes the "SHOW STATS" tokens have already been consumed.
+func (p *Parser) parseShowStatsStatement() (*ShowStatsStatement, error) {
+	stmt := &ShowStatsStatement{}
+	var err error
+
+	if tok, _, _ := p.ScanIgnoreWhitespace(); tok == FOR {
+		stmt.Module, err = p.parseString()
+	} else {
+		p.Unscan()
+	}
+
+	return stmt, err
+}
+
+// parseShowDiagnostics parses a string and returns a ShowDiagnosticsStatement.
+func (p *Parser) parseShowDiagnosticsStatement() (*ShowDiagnosticsStatement, error) {
+	stmt := &ShowDiagnosticsStatement{}
+	var err error
+
+	if tok, _, _ := p.ScanIgnoreWhitespace(); tok == FOR {
+		stmt.Module, err = p.parseString()
+	} else {
+		p.Unscan()
+	}
+
+	return stmt, err
+}
+
+// parseDropContinuousQueriesStatement parses a string and returns a DropContinuousQueryStatement.
+// This function assumes the "DROP CONTINUOUS" tokens have already been consumed.
+func (p *Parser) parseDropContinuousQueryStatement() (*DropContinuousQueryStatement, error) {
+	stmt := &DropContinuousQueryStatement{}
+
+	// Read the id of the query to drop.
+	ident, err := p.ParseIdent()
+	if err != nil {
+		return nil, err
+	}
+	stmt.Name = ident
+
+	// Expect an "ON" keyword.
+	if tok, pos, lit := p.ScanIgnoreWhitespace(); tok != ON {
+		return nil, newParseError(tokstr(tok, lit), []string{"ON"}, pos)
+	}
+
+	// Read the name of the database to remove the query from.
+	if ident, err = p.ParseIdent(); err != nil {
+		return nil, err
+	}
+	stmt.Database = ident
+
+	return stmt, nil
+}
+
+// parseBackfillContinuousQueryStatement parses a string and returns a BackfillContinuousQueryStatement.
+// This function assumes the "BACKFILL CONTINUOUS QUERY" tokens have already been consumed.
+func (p *Parser) parseBackfillContinuousQueryStatement() (*BackfillContinuousQueryStatement, error) {
+	stmt := &BackfillContinuousQueryStatement{}
+
+	// Read the name of the query to backfill.
+	ident, err := p.ParseIdent()
+	if err != nil {
+		return nil, err
+	}
+	stmt.Name = ident
+
+	// Expect an "ON" keyword.
+	if tok, pos, lit := p.ScanIgnoreWhitespace(); tok != ON {
+		return nil, newParseError(tokstr(tok, lit), []string{"ON"}, pos)
+	}
+
+	// Read the name of the database the query is on.
+	if ident, err = p.ParseIdent(); err != nil {
+		return nil, err
+	}
+	stmt.Database = ident
+
+	// Parse the time range.
+	if tok, pos, lit := p.ScanIgnoreWhitespace(); tok != FROM {
+		return nil, newParseError(tokstr(tok, lit), []string{"FROM"}, pos)
+	}
+	if stmt.StartTime, _, err = p.parseTimeString(); err != nil {
+		return nil, err
+	}
+	if tok, pos, lit := p.ScanIgnoreWhitespace(); tok != TO {
+		return nil, newParseError(tokstr(tok, lit), []string{"TO"}, pos)
+	}
+	end, pos, err := p.parseTimeString()
+	if err != nil {
+		return nil, err
+	} else if !end.After(stmt.StartTime) {
+		return nil, &ParseError{Message: "backfill end time must be after the start time", Pos: pos}
+	}
+	stmt.EndTime = end
+	return stmt, nil
+}
+
+// parseCreateDownsamplePolicyStatement parses a string and returns a CreateDownsamplePolicyStatement.
+// This function assumes the "CREATE DOWNSAMPLE POLICY" tokens have already been consumed.
+func (p *Parser) parseCreateDownsamplePolicyStatement() (*CreateDownsamplePolicyStatement, error) {
+	stmt := &CreateDownsamplePolicyStatement{}
+
+	// Read the name of the policy.
+	ident, err := p.ParseIdent()
+	if err != nil {
+		return nil, err
+	}
+	stmt.Name = ident
+
+	// Expect an "ON" keyword.
+	if tok, pos, lit := p.ScanIgnoreWhitespace(); tok != ON {
+		return nil, newParseError(tokstr(tok, lit), []string{"ON"}, pos)
+	}
+
+	// Read the name of the database the policy is on.
+	if ident, err = p.ParseIdent(); err != nil {
+		return nil, err
+	}
+	stmt.Database = ident
+
+	// Read the retention policies to read from and write to.
+	if tok, pos, lit := p.ScanIgnoreWhitespace(); tok != FROM {
+		return nil, newParseError(tokstr(tok, lit), []string{"FROM"}, pos)
+	}
+	if stmt.SourceRetentionPolicy, err = p.ParseIdent(); err != nil {
+		return nil, err
+	}
+	if tok, pos, lit := p.ScanIgnoreWhitespace(); tok != TO {
+		return nil, newParseError(tokstr(tok, lit), []string{"TO"}, pos)
+	}
+	if stmt.TargetRetentionPolicy, err = p.ParseIdent(); err != nil {
+		return nil, err
+	}
+
+	// Parse the interval.
+	if tok, pos, lit := p.ScanIgnoreWhitespace(); tok != EVERY {
+		return nil, newParseError(tokstr(tok, lit), []string{"EVERY"}, pos)
+	}
+	tok, pos, _ := p.ScanIgnoreWhitespace()
+	p.Unscan()
+	if tok == INF {
+		return nil, &ParseError{Message: "invalid duration INF for downsample interval", Pos: pos}
+	}
+	if stmt.Interval, err = p.ParseDuration(); err != nil {
+		return nil, err
+	} else if stmt.Interval <= 0 {
+		return nil, &ParseError{Message: "downsample interval must be positive", Pos: pos}
+	}
+
+	// Parse the optional list of functions for numeric fields.
+	if tok, _, _ := p.ScanIgnoreWhitespace(); tok != WITH {
+		p.Unscan()
+		return stmt, nil
+	}
+	for {
+		tok, pos, lit := p.ScanIgnoreWhitespace()
+		if tok != IDENT {
+			return nil, newParseError(tokstr(tok, lit), []string{"identifier"}, pos)
+		}
+		name := strings.ToLower(lit)
+		if !IsDownsampleFunction(name) {
+			return nil, &ParseError{Message: fmt.Sprintf("unsupported downsample function: %s", lit), Pos: pos}
+		}
+		stmt.Functions = append(stmt.Functions, name)
+
+		if tok, _, _ := p.ScanIgnoreWhitespace(); tok != COMMA {
+			p.Unscan()
+			return stmt, nil
+		}
+	}
+}
+
+// parseDropDownsamplePolicyStatement parses a string and returns a DropDownsamplePolicyStatement.
+// This function assumes the "DROP DOWNSAMPLE POLICY" tokens have already been consumed.
+func (p *Parser) parseDropDownsamplePolicyStatement() (*DropDownsamplePolicyStatement, error) {
+	stmt := &DropDownsamplePolicyStatement{}
+
+	// Read the name of the policy to drop.
+	ident, err := p.ParseIdent()
+	if err != nil {
+		return nil, err
+	}
+	stmt.Name = ident
+
+	// Expect an "ON" keyword.
+	if tok, pos, lit := p.ScanIgnoreWhitespace(); tok != ON {
+		return nil, newParseError(tokstr(tok, lit), []string{"ON"}, pos)
+	}
+
+	// Read the name of the database to remove the policy from.
+	if ident, err = p.ParseIdent(); err != nil {
+		return nil, err
+	}
+	stmt.Database = ident
+
+	return stmt, nil
+}
+
+// parseShowDownsamplePoliciesStatement parses a string and returns a ShowDownsamplePoliciesStatement.
+// This function assumes the "SHOW DOWNSAMPLE POLICIES" tokens have been consumed.
+func (p *Parser) parseShowDownsamplePoliciesStatement() (*ShowDownsamplePoliciesStatement, error) {
+	stmt := &ShowDownsamplePoliciesStatement{}
+
+	// Parse the optional database.
+	if tok, _, _ := p.ScanIgnoreWhitespace(); tok == ON {
+		ident, err := p.ParseIdent()
+		if err != nil {
+			return nil, err
+		}
+		stmt.Database = ident
+	} else {
+		p.Unscan()
 	}
 
-	return stmt, err
-}
-
-// parseShowDiagnostics parses a string and returns a ShowDiagnosticsStatement.
-func (p *Parser) parseShowDiagnosticsStatement() (*ShowDiagnosticsStatement, error) {
-	stmt := &ShowDiagnosticsStatement{}
-	var err error
+	return stmt, nil
+}
 
-	if tok, _, _ := p.ScanIgnoreWhitespace(); tok == FOR {
-		stmt.Module, err = p.parseString()
+// parseCreateMeasurementSchemaStatement parses a string and returns a CreateMeasurementSchemaStatement.
+// This function assumes the "CREATE MEASUREMENT SCHEMA" tokens have already been consumed.
+func (p *Parser) parseCreateMeasurementSchemaStatement() (*CreateMeasurementSchemaStatement, error) {
+	stmt := &CreateMeasurementSchemaStatement{}
+
+	// Read the name of the measurement.
+	ident, err := p.ParseIdent()
+	if err != nil {
+		return nil, err
+	}
+	stmt.Name = ident
+
+	// Read the name of the database the measurement belongs to.
+	if tok, pos, lit := p.ScanIgnoreWhitespace(); tok != ON {
+		return nil, newParseError(tokstr(tok, lit), []string{"ON"}, pos)
+	}
+	if stmt.Database, err = p.ParseIdent(); err != nil {
+		return nil, err
+	}
+
+	// Parse the list of declared fields and tags.
+	if tok, pos, lit := p.ScanIgnoreWhitespace(); tok != LPAREN {
+		return nil, newParseError(tokstr(tok, lit), []string{"("}, pos)
+	}
+	for {
+		switch tok, pos, lit := p.ScanIgnoreWhitespace(); tok {
+		case FIELD:
+			f, err := p.parseMeasurementSchemaField()
+			if err != nil {
+				return nil, err
+			}
+			stmt.Fields = append(stmt.Fields, f)
+		case TAG:
+			t, err := p.parseMeasurementSchemaTag()
+			if err != nil {
+				return nil, err
+			}
+			stmt.Tags = append(stmt.Tags, t)
+		default:
+			return nil, newParseError(tokstr(tok, lit), []string{"FIELD", "TAG"}, pos)
+		}
+
+		if tok, pos, lit := p.ScanIgnoreWhitespace(); tok == RPAREN {
+			break
+		} else if tok != COMMA {
+			return nil, newParseError(tokstr(tok, lit), []string{",", ")"}, pos)
+		}
+	}
+
+	// WARN is not a keyword so that it can still be used as an identifier.
+	if tok, _, lit := p.ScanIgnoreWhitespace(); tok == IDENT && strings.ToUpper(lit) == "WARN" {
+		stmt.WarnOnly = true
 	} else {
 		p.Unscan()
 	}
+	return stmt, nil
+}
 
-	return stmt, err
+// parseMeasurementSchemaField parses a field declared by a measurement schema.
+// This function assumes the FIELD token has already been consumed.
+func (p *Parser) parseMeasurementSchemaField() (*MeasurementSchemaField, error) {
+	ident, err := p.ParseIdent()
+	if err != nil {
+		return nil, err
+	}
+
+	tok, pos, lit := p.ScanIgnoreWhitespace()
+	if tok == IDENT {
+		switch typ := DataTypeFromString(strings.ToLower(lit)); typ {
+		case Float, Integer, Unsigned, String, Boolean:
+			return &MeasurementSchemaField{Name: ident, Type: typ}, nil
+		}
+	}
+	return nil, newParseError(tokstr(tok, lit), []string{"float", "integer", "unsigned", "string", "boolean"}, pos)
 }
 
-// parseDropContinuousQueriesStatement parses a string and returns a DropContinuousQueryStatement.
-// This function assumes the "DROP CONTINUOUS" tokens have already been consumed.
-func (p *Parser) parseDropContinuousQueryStatement() (*DropContinuousQueryStatement, error) {
-	stmt := &DropContinuousQueryStatement{}
+// parseMeasurementSchemaTag parses a tag key declared by a measurement schema.
+// This function assumes the TAG token has already been consumed.
+func (p *Parser) parseMeasurementSchemaTag() (*MeasurementSchemaTag, error) {
+	ident, err := p.ParseIdent()
+	if err != nil {
+		return nil, err
+	}
+	t := &MeasurementSchemaTag{Key: ident}
 
-	// Read the id of the query to drop.
+	// REQUIRED is not a keyword so that it can still be used as an identifier.
+	if tok, _, lit := p.ScanIgnoreWhitespace(); tok == IDENT && strings.ToUpper(lit) == "REQUIRED" {
+		t.Required = true
+	} else {
+		p.Unscan()
+	}
+
+	// Parse the optional regex every value must match.
+	if tok, _, _ := p.ScanIgnoreWhitespace(); tok == EQREGEX {
+		re, err := p.parseRegex()
+		if err != nil {
+			return nil, err
+		} else if re == nil {
+			tok, pos, lit := p.ScanIgnoreWhitespace()
+			return nil, newParseError(tokstr(tok, lit), []string{"regex"}, pos)
+		}
+		t.Regex = re
+	} else {
+		p.Unscan()
+	}
+
+	// Parse the optional limit on the number of distinct values.
+	if tok, _, _ := p.ScanIgnoreWhitespace(); tok == LIMIT {
+		if t.Limit, err = p.ParseInt(1, math.MaxInt32); err != nil {
+			return nil, err
+		}
+	} else {
+		p.Unscan()
+	}
+	return t, nil
+}
+
+// parseDropMeasurementSchemaStatement parses a string and returns a DropMeasurementSchemaStatement.
+// This function assumes the "DROP MEASUREMENT SCHEMA" tokens have already been consumed.
+func (p *Parser) parseDropMeasurementSchemaStatement() (*DropMeasurementSchemaStatement, error) {
+	stmt := &DropMeasurementSchemaStatement{}
+
+	// Read the name of the measurement.
 	ident, err := p.ParseIdent()
 	if err != nil {
 		return nil, err
 	}
 	stmt.Name = ident
 
-	// Expect an "ON" keyword.
+	// Read the name of the database the measurement belongs to.
 	if tok, pos, lit := p.ScanIgnoreWhitespace(); tok != ON {
 		return nil, newParseError(tokstr(tok, lit), []string{"ON"}, pos)
 	}
-
-	// Read the name of the database to remove the query from.
-	if ident, err = p.ParseIdent(); err != nil {
+	if stmt.Database, err = p.ParseIdent(); err != nil {
 		return nil, err
 	}
-	stmt.Database = ident
+	return stmt, nil
+}
+
+// parseShowMeasurementSchemasStatement parses a string and returns a ShowMeasurementSchemasStatement.
+// This function assumes the "SHOW MEASUREMENT SCHEMAS" tokens have been consumed.
+func (p *Parser) parseShowMeasurementSchemasStatement() (*ShowMeasurementSchemasStatement, error) {
+	stmt := &ShowMeasurementSchemasStatement{}
+
+	// Parse the optional database.
+	if tok, _, _ := p.ScanIgnoreWhitespace(); tok == ON {
+		ident, err := p.ParseIdent()
+		if err != nil {
+			return nil, err
+		}
+		stmt.Database = ident
+	} else {
+		p.Unscan()
+	}
 
 	return stmt, nil
 }
 
+// parseTimeString parses a string literal and returns the time it contains
+// and its position.
+func (p *Parser) parseTimeString() (time.Time, Pos, error) {
+	tok, pos, lit := p.ScanIgnoreWhitespace()
+	if tok != STRING {
+		return time.Time{}, pos, newParseError(tokstr(tok, lit), []string{"string"}, pos)
+	}
+
+	t, err := (&StringLiteral{Val: lit}).ToTimeLiteral(time.UTC)
+	if err != nil {
+		return time.Time{}, pos, &ParseError{Message: err.Error(), Pos: pos}
+	}
+	return t.Val, pos, nil
+}
+
 // parseFields parses a list of one or more fields.
 func (p *Parser) parseFields() (Fields, error) {
 	var fields Fields
@@ -2082,18 +3101,24 @@ type validateField struct {
 }
 
 func (c *validateField) Visit(n Node) Visitor {
-	e, ok := n.(*BinaryExpr)
-	if !ok {
-		return c
-	}
-
-	switch e.Op {
-	case EQ, NEQ, EQREGEX,
-		NEQREGEX, LT, LTE, GT, GTE,
-		AND, OR:
-		c.foundInvalid = true
-		c.badToken = e.Op
+	switch e := n.(type) {
+	case *CaseExpr:
+		// The conditions of a CASE expression may use these operators so
+		// only the results are validated.
+		for _, w := range e.WhenClauses {
+			Walk(c, w.Result)
+		}
+		Walk(c, e.Else)
 		return nil
+	case *BinaryExpr:
+		switch e.Op {
+		case EQ, NEQ, EQREGEX,
+			NEQREGEX, LT, LTE, GT, GTE,
+			AND, OR:
+			c.foundInvalid = true
+			c.badToken = e.Op
+			return nil
+		}
 	}
 	return c
 }
@@ -2123,6 +3148,13 @@ func (p *Parser) parseSources(subqueries bool) (Sources, error) {
 		if err != nil {
 			return nil, err
 		}
+
+		// Queries that allow subqueries also allow a join between two sources.
+		if subqueries {
+			if s, err = p.parseJoin(s); err != nil {
+				return nil, err
+			}
+		}
 		sources = append(sources, s)
 
 		if tok, _, _ := p.ScanIgnoreWhitespace(); tok != COMMA {
@@ -2131,9 +3163,127 @@ func (p *Parser) parseSources(subqueries bool) (Sources, error) {
 		}
 	}
 
+	// A join must be the only source of a query.
+	if len(sources) > 1 {
+		for _, s := range sources {
+			if _, ok := s.(*Join); ok {
+				return nil, errors.New("JOIN cannot be combined with other sources")
+			}
+		}
+	}
 	return sources, nil
 }
 
+// parseJoin parses the "[AS IDENT] [INNER|LEFT [OUTER]|FULL [OUTER]] JOIN
+// source [AS IDENT] ON expr" clause that may follow a source. The JOIN, INNER,
+// LEFT, FULL and OUTER keywords are matched as identifiers so they remain
+// usable as measurement and field names. If no join follows, left is returned.
+func (p *Parser) parseJoin(left Source) (Source, error) {
+	leftAlias, err := p.parseAlias()
+	if err != nil {
+		return nil, err
+	}
+
+	typ, ok, err := p.parseJoinType()
+	if err != nil {
+		return nil, err
+	} else if !ok {
+		if leftAlias != "" {
+			tok, pos, lit := p.ScanIgnoreWhitespace()
+			return nil, newParseError(tokstr(tok, lit), []string{"JOIN"}, pos)
+		}
+		return left, nil
+	}
+
+	right, err := p.parseSource(true)
+	if err != nil {
+		return nil, err
+	}
+	rightAlias, err := p.parseAlias()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.parseTokens([]Token{ON}); err != nil {
+		return nil, err
+	}
+	cond, err := p.ParseExpr()
+	if err != nil {
+		return nil, err
+	}
+
+	j := &Join{
+		Type:       typ,
+		Left:       left,
+		LeftAlias:  leftAlias,
+		Right:      right,
+		RightAlias: rightAlias,
+		Condition:  cond,
+	}
+	for _, side := range []struct {
+		src   Source
+		alias *string
+	}{
+		{src: j.Left, alias: &j.LeftAlias},
+		{src: j.Right, alias: &j.RightAlias},
+	} {
+		switch src := side.src.(type) {
+		case *Measurement:
+			if src.Regex != nil {
+				return nil, errors.New("JOIN does not support regular expression sources")
+			}
+			if *side.alias == "" {
+				*side.alias = src.Name
+			}
+		case *SubQuery:
+			if *side.alias == "" {
+				return nil, errors.New("subquery in JOIN must have an alias")
+			}
+		}
+	}
+	if j.LeftAlias == j.RightAlias {
+		return nil, fmt.Errorf("JOIN sources must have different aliases: %s", j.LeftAlias)
+	}
+	return j, nil
+}
+
+// parseJoinType parses the join keywords and returns the type of the join.
+// If the next token does not start a join, false is returned.
+func (p *Parser) parseJoinType() (JoinType, bool, error) {
+	tok, _, lit := p.ScanIgnoreWhitespace()
+	if tok != IDENT {
+		p.Unscan()
+		return 0, false, nil
+	}
+
+	var typ JoinType
+	var outer bool // true if OUTER may follow the keyword
+	switch strings.ToUpper(lit) {
+	case "JOIN":
+		return InnerJoin, true, nil
+	case "INNER":
+		typ = InnerJoin
+	case "LEFT":
+		typ, outer = LeftJoin, true
+	case "FULL":
+		typ, outer = FullJoin, true
+	case "OUTER":
+		typ = FullJoin
+	default:
+		p.Unscan()
+		return 0, false, nil
+	}
+
+	tok, pos, lit := p.ScanIgnoreWhitespace()
+	if outer && tok == IDENT && strings.ToUpper(lit) == "OUTER" {
+		tok, pos, lit = p.ScanIgnoreWhitespace()
+	}
+	if tok != IDENT || strings.ToUpper(lit) != "JOIN" {
+		return 0, false, newParseError(tokstr(tok, lit), []string{"JOIN"}, pos)
+	}
+	return typ, true, nil
+}
+
 // peekRune returns the next rune that would be read by the scanner.
 func (p *Parser) peekRune() rune {
 	r, _, _ := p.s.s.r.ReadRune()
@@ -2234,6 +3384,16 @@ func (p *Parser) parseCondition() (Expr, error) {
 	return expr, nil
 }
 
+// parseHaving parses the "HAVING" clause of the query, if it exists.
+func (p *Parser) parseHaving() (Expr, error) {
+	// Check if the HAVING token exists.
+	if tok, _, _ := p.ScanIgnoreWhitespace(); tok != HAVING {
+		p.Unscan()
+		return nil, nil
+	}
+	return p.ParseExpr()
+}
+
 // parseDimensions parses the "GROUP BY" clause of the query, if it exists.
 func (p *Parser) parseDimensions() (Dimensions, error) {
 	// If the next token is not GROUP then exit.
@@ -2629,6 +3789,8 @@ func (p *Parser) parseUnaryExpr() (Expr, error) {
 		}
 
 		return nil, newParseError(tokstr(tok0, lit), []string{"(", "identifier"}, pos)
+	case CASE:
+		return p.parseCaseExpr()
 	case STRING:
 		return &StringLiteral{Val: lit}, nil
 	case NUMBER:
@@ -2651,11 +3813,14 @@ func (p *Parser) parseUnaryExpr() (Expr, error) {
 	case TRUE, FALSE:
 		return &BooleanLiteral{Val: tok == TRUE}, nil
 	case DURATIONVAL:
-		v, err := ParseDuration(lit)
+		if v, err := ParseCalendarWeeks(lit); err == nil {
+			return &DurationLiteral{Val: v, CalendarWeeks: true}, nil
+		}
+		months, v, err := ParseCalendarDuration(lit)
 		if err != nil {
 			return nil, err
 		}
-		return &DurationLiteral{Val: v}, nil
+		return &DurationLiteral{Val: v, Months: months}, nil
 	case MUL:
 		wc := &Wildcard{}
 		if tok, _, _ := p.Scan(); tok == DOUBLECOLON {
@@ -2790,6 +3955,48 @@ func (p *Parser) parseRegex() (*RegexLiteral, error) {
 	return &RegexLiteral{Val: re}, nil
 }
 
+// parseCaseExpr parses a CASE expression.
+// This function assumes the CASE token has been consumed.
+func (p *Parser) parseCaseExpr() (*CaseExpr, error) {
+	expr := &CaseExpr{}
+	for {
+		tok, pos, lit := p.ScanIgnoreWhitespace()
+		switch {
+		case tok == WHEN:
+			cond, err := p.ParseExpr()
+			if err != nil {
+				return nil, err
+			}
+			if tok, pos, lit := p.ScanIgnoreWhitespace(); tok != THEN {
+				return nil, newParseError(tokstr(tok, lit), []string{"THEN"}, pos)
+			}
+			result, err := p.ParseExpr()
+			if err != nil {
+				return nil, err
+			}
+			expr.WhenClauses = append(expr.WhenClauses, &WhenClause{Condition: cond, Result: result})
+		case tok == ELSE && len(expr.WhenClauses) > 0:
+			result, err := p.ParseExpr()
+			if err != nil {
+				return nil, err
+			}
+			expr.Else = result
+
+			if tok, pos, lit := p.ScanIgnoreWhitespace(); tok != END {
+				return nil, newParseError(tokstr(tok, lit), []string{"END"}, pos)
+			}
+			return expr, nil
+		case tok == END && len(expr.WhenClauses) > 0:
+			return expr, nil
+		default:
+			if len(expr.WhenClauses) == 0 {
+				return nil, newParseError(tokstr(tok, lit), []string{"WHEN"}, pos)
+			}
+			return nil, newParseError(tokstr(tok, lit), []string{"WHEN", "ELSE", "END"}, pos)
+		}
+	}
+}
+
 // parseCall parses a function call.
 // This function assumes the function name and LPAREN have been consumed.
 func (p *Parser) parseCall(name string) (*Call, error) {
@@ -2851,44 +4058,44 @@ func (p *Parser) parseCall(name string) (*Call, error) {
 // parseResample parses a RESAMPLE [EVERY <duration>] [FOR <duration>].
 // This function assumes RESAMPLE has already been consumed.
 // EVERY and FOR are optional, but at least one of the two has to be used.
-func (p *Parser) parseResample() (time.Duration, time.Duration, error) {
-	var interval time.Duration
+func (p *Parser) parseResample() (DurationLiteral, DurationLiteral, error) {
+	var interval DurationLiteral
 	if tok, _, _ := p.ScanIgnoreWhitespace(); tok == EVERY {
 		tok, pos, lit := p.ScanIgnoreWhitespace()
 		if tok != DURATIONVAL {
-			return 0, 0, newParseError(tokstr(tok, lit), []string{"duration"}, pos)
+			return DurationLiteral{}, DurationLiteral{}, newParseError(tokstr(tok, lit), []string{"duration"}, pos)
 		}
 
-		d, err := ParseDuration(lit)
+		months, d, err := ParseCalendarDuration(lit)
 		if err != nil {
-			return 0, 0, &ParseError{Message: err.Error(), Pos: pos}
+			return DurationLiteral{}, DurationLiteral{}, &ParseError{Message: err.Error(), Pos: pos}
 		}
-		interval = d
+		interval = DurationLiteral{Val: d, Months: months}
 	} else {
 		p.Unscan()
 	}
 
-	var maxDuration time.Duration
+	var maxDuration DurationLiteral
 	if tok, _, _ := p.ScanIgnoreWhitespace(); tok == FOR {
 		tok, pos, lit := p.ScanIgnoreWhitespace()
 		if tok != DURATIONVAL {
-			return 0, 0, newParseError(tokstr(tok, lit), []string{"duration"}, pos)
+			return DurationLiteral{}, DurationLiteral{}, newParseError(tokstr(tok, lit), []string{"duration"}, pos)
 		}
 
-		d, err := ParseDuration(lit)
+		months, d, err := ParseCalendarDuration(lit)
 		if err != nil {
-			return 0, 0, &ParseError{Message: err.Error(), Pos: pos}
+			return DurationLiteral{}, DurationLiteral{}, &ParseError{Message: err.Error(), Pos: pos}
 		}
-		maxDuration = d
+		maxDuration = DurationLiteral{Val: d, Months: months}
 	} else {
 		p.Unscan()
 	}
 
 	// Neither EVERY or FOR were read, so read the next token again
 	// so we can return a suitable error message.
-	if interval == 0 && maxDuration == 0 {
+	if interval == (DurationLiteral{}) && maxDuration == (DurationLiteral{}) {
 		tok, pos, lit := p.ScanIgnoreWhitespace()
-		return 0, 0, newParseError(tokstr(tok, lit), []string{"EVERY", "FOR"}, pos)
+		return DurationLiteral{}, DurationLiteral{}, newParseError(tokstr(tok, lit), []string{"EVERY", "FOR"}, pos)
 	}
 	return interval, maxDuration, nil
 }
@@ -3038,6 +4245,141 @@ func ParseDuration(s string) (time.Duration, error) {
 	return d, nil
 }
 
+// ParseCalendarDuration parses a duration that may contain the calendar
+// units "mo" (months) and "y" (years) in addition to the units supported by
+// ParseDuration. The calendar units are returned as a number of months
+// because their length depends on the date they are applied to.
+func ParseCalendarDuration(s string) (months int, d time.Duration, err error) {
+	// Return an error if the string is blank or one character
+	if len(s) < 2 {
+		return 0, 0, ErrInvalidDuration
+	}
+
+	// Split string into individual runes.
+	a := []rune(s)
+
+	i := 0
+	isNegative := false
+	if a[i] == '-' {
+		isNegative = true
+		i++
+	}
+
+	// Separate the calendar units from the fixed units so the fixed units
+	// can be parsed by ParseDuration.
+	var fixed strings.Builder
+	for i < len(a) {
+		start := i
+		for ; i < len(a) && isDigit(a[i]); i++ {
+			// Scan for the digits.
+		}
+		if i >= len(a) || i == start {
+			return 0, 0, ErrInvalidDuration
+		}
+		digits := i
+		for ; i < len(a) && !isDigit(a[i]); i++ {
+			// Scan for the unit.
+		}
+
+		switch unit := string(a[digits:i]); unit {
+		case "mo", "y":
+			n, err := strconv.Atoi(string(a[start:digits]))
+			if err != nil {
+				return 0, 0, ErrInvalidDuration
+			}
+			if unit == "y" {
+				n *= 12
+			}
+			months += n
+		default:
+			_, _ = fixed.WriteString(string(a[start:i]))
+		}
+	}
+
+	if fixed.Len() > 0 {
+		if d, err = ParseDuration(fixed.String()); err != nil {
+			return 0, 0, err
+		}
+	}
+
+	if isNegative {
+		months, d = -months, -d
+	}
+	return months, d, nil
+}
+
+// FormatCalendarDuration formats a duration with a number of calendar months
+// to a string that can be parsed by ParseCalendarDuration.
+func FormatCalendarDuration(months int, d time.Duration) string {
+	if months == 0 {
+		return FormatDuration(d)
+	} else if months < 0 {
+		return "-" + FormatCalendarDuration(-months, -d)
+	}
+
+	var s string
+	if months%12 == 0 {
+		s = fmt.Sprintf("%dy", months/12)
+	} else {
+		s = fmt.Sprintf("%dmo", months)
+	}
+	if d != 0 {
+		s += FormatDuration(d)
+	}
+	return s
+}
+
+// ParseCalendarWeeks parses a duration in the calendar week unit "cw". A
+// calendar week is seven days long like the "w" unit, but GROUP BY time()
+// windows of calendar weeks start at midnight on a Monday in the location of
+// the query instead of on a Thursday in UTC.
+func ParseCalendarWeeks(s string) (time.Duration, error) {
+	const week = 7 * 24 * time.Hour
+
+	digits := strings.TrimSuffix(s, "cw")
+	if digits == s || digits == "" || !isDigit(rune(digits[0])) {
+		return 0, ErrInvalidDuration
+	}
+	n, err := strconv.ParseInt(digits, 10, 64)
+	if err != nil || n > int64(MaxTime/int64(week)) {
+		return 0, ErrInvalidDuration
+	}
+	return time.Duration(n) * week, nil
+}
+
+// FormatCalendarWeeks formats a duration to a string in the calendar week
+// unit that can be parsed by ParseCalendarWeeks.
+func FormatCalendarWeeks(d time.Duration) string {
+	return fmt.Sprintf("%dcw", d/(7*24*time.Hour))
+}
+
+// TruncateMonths returns the start of the calendar interval of n months that
+// contains t. Intervals are counted from January 1970 in the location of t.
+func TruncateMonths(t time.Time, n int) time.Time {
+	m := (t.Year()-1970)*12 + int(t.Month()) - 1
+	if r := m % n; r < 0 {
+		m -= r + n
+	} else {
+		m -= r
+	}
+	return time.Date(1970, time.Month(m+1), 1, 0, 0, 0, 0, t.Location())
+}
+
+// TruncateWeeks returns the start of the interval of n calendar weeks that
+// contains t. Intervals start at midnight on a Monday and are counted from
+// Monday, January 5, 1970 in the location of t.
+func TruncateWeeks(t time.Time, n int) time.Time {
+	// Count the days from the first Monday using the date alone so changes
+	// of the zone offset do not move the start of the day.
+	d := int(time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC).Unix()/86400) - 4
+	if r := d % (7 * n); r < 0 {
+		d -= r + 7*n
+	} else {
+		d -= r
+	}
+	return time.Date(1970, time.January, 5+d, 0, 0, 0, 0, t.Location())
+}
+
 // FormatDuration formats a duration to a string.
 func FormatDuration(d time.Duration) string {
 	if d == 0 {
@@ -3140,6 +4482,10 @@ var dateTimeStringRegexp = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}.+`)
 // ErrInvalidDuration is returned when parsing a malformed duration.
 var ErrInvalidDuration = errors.New("invalid duration")
 
+// ErrCalendarDuration is returned when a calendar duration is used where
+// only a fixed duration is allowed.
+var ErrCalendarDuration = errors.New("calendar durations (mo, y, cw) are not supported here")
+
 // ParseError represents an error that occurred during parsing.
 type ParseError struct {
 	Message  string
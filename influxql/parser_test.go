@@ -679,6 +679,26 @@ func TestParser_ParseStatement(t *testing.T) {
 			},
 		},
 
+		// SELECT statement with HAVING
+		{
+			s: `SELECT mean(value) FROM cpu GROUP BY host HAVING mean > 90 ORDER BY time DESC LIMIT 1`,
+			stmt: &influxql.SelectStatement{
+				Fields: []*influxql.Field{{Expr: &influxql.Call{
+					Name: "mean",
+					Args: []influxql.Expr{&influxql.VarRef{Val: "value"}},
+				}}},
+				Sources:    []influxql.Source{&influxql.Measurement{Name: "cpu"}},
+				Dimensions: []*influxql.Dimension{{Expr: &influxql.VarRef{Val: "host"}}},
+				Having: &influxql.BinaryExpr{
+					Op:  influxql.GT,
+					LHS: &influxql.VarRef{Val: "mean"},
+					RHS: &influxql.IntegerLiteral{Val: 90},
+				},
+				SortFields: []*influxql.SortField{{Name: "time", Ascending: false}},
+				Limit:      1,
+			},
+		},
+
 		// SELECT * FROM cpu WHERE host = 'serverC' AND region =~ /.*west.*/
 		{
 			s: `SELECT * FROM cpu WHERE host = 'serverC' AND region =~ /.*west.*/`,
@@ -1520,6 +1540,109 @@ func TestParser_ParseStatement(t *testing.T) {
 			},
 		},
 
+		// SELECT statement with a join
+		{
+			s: `SELECT a.usage / b.capacity FROM cpu AS a INNER JOIN capacity AS b ON a.host = b.host`,
+			stmt: &influxql.SelectStatement{
+				IsRawQuery: true,
+				Fields: []*influxql.Field{{
+					Expr: &influxql.BinaryExpr{
+						Op:  influxql.DIV,
+						LHS: &influxql.VarRef{Val: "a.usage"},
+						RHS: &influxql.VarRef{Val: "b.capacity"},
+					},
+				}},
+				Sources: []influxql.Source{
+					&influxql.Join{
+						Type:       influxql.InnerJoin,
+						Left:       &influxql.Measurement{Name: "cpu"},
+						LeftAlias:  "a",
+						Right:      &influxql.Measurement{Name: "capacity"},
+						RightAlias: "b",
+						Condition: &influxql.BinaryExpr{
+							Op:  influxql.EQ,
+							LHS: &influxql.VarRef{Val: "a.host"},
+							RHS: &influxql.VarRef{Val: "b.host"},
+						},
+					},
+				},
+			},
+		},
+
+		{
+			s: `SELECT mean(cpu.usage) FROM cpu left outer join capacity ON cpu.host = capacity.host GROUP BY time(1m)`,
+			stmt: &influxql.SelectStatement{
+				Fields: []*influxql.Field{{
+					Expr: &influxql.Call{
+						Name: "mean",
+						Args: []influxql.Expr{&influxql.VarRef{Val: "cpu.usage"}},
+					},
+				}},
+				Dimensions: []*influxql.Dimension{{
+					Expr: &influxql.Call{
+						Name: "time",
+						Args: []influxql.Expr{
+							&influxql.DurationLiteral{Val: time.Minute},
+						},
+					},
+				}},
+				Sources: []influxql.Source{
+					&influxql.Join{
+						Type:       influxql.LeftJoin,
+						Left:       &influxql.Measurement{Name: "cpu"},
+						LeftAlias:  "cpu",
+						Right:      &influxql.Measurement{Name: "capacity"},
+						RightAlias: "capacity",
+						Condition: &influxql.BinaryExpr{
+							Op:  influxql.EQ,
+							LHS: &influxql.VarRef{Val: "cpu.host"},
+							RHS: &influxql.VarRef{Val: "capacity.host"},
+						},
+					},
+				},
+			},
+		},
+
+		{
+			s: `SELECT a.max, b.value FROM (SELECT max(value) FROM cpu GROUP BY host) AS a FULL JOIN mem AS b ON a.host = b.host`,
+			stmt: &influxql.SelectStatement{
+				IsRawQuery: true,
+				Fields: []*influxql.Field{
+					{Expr: &influxql.VarRef{Val: "a.max"}},
+					{Expr: &influxql.VarRef{Val: "b.value"}},
+				},
+				Sources: []influxql.Source{
+					&influxql.Join{
+						Type: influxql.FullJoin,
+						Left: &influxql.SubQuery{
+							Statement: &influxql.SelectStatement{
+								Fields: []*influxql.Field{{
+									Expr: &influxql.Call{
+										Name: "max",
+										Args: []influxql.Expr{&influxql.VarRef{Val: "value"}},
+									},
+								}},
+								Dimensions: []*influxql.Dimension{{
+									Expr: &influxql.VarRef{Val: "host"},
+								}},
+								Sources: []influxql.Source{
+									&influxql.Measurement{Name: "cpu"},
+								},
+							},
+						},
+						LeftAlias:  "a",
+						Right:      &influxql.Measurement{Name: "mem"},
+						RightAlias: "b",
+						Condition: &influxql.BinaryExpr{
+							Op:  influxql.EQ,
+							LHS: &influxql.VarRef{Val: "a.host"},
+							RHS: &influxql.VarRef{Val: "b.host"},
+						},
+					},
+				},
+			},
+		},
+
 		{
 			s: `SELECT sum(mean) FROM (SELECT mean(value) FROM cpu GROUP BY time(1h)) WHERE time >= now() - 1d`,
 			stmt: &influxql.SelectStatement{
@@ -2059,6 +2182,12 @@ func TestParser_ParseStatement(t *testing.T) {
 			stmt: &influxql.ShowQueriesStatement{},
 		},
 
+		// SHOW QUOTAS
+		{
+			s:    `SHOW QUOTAS`,
+			stmt: &influxql.ShowQuotasStatement{},
+		},
+
 		// KILL QUERY 4
 		{
 			s: `KILL QUERY 4`,
@@ -2076,6 +2205,20 @@ func TestParser_ParseStatement(t *testing.T) {
 			},
 		},
 
+		// SHOW TASKS
+		{
+			s:    `SHOW TASKS`,
+			stmt: &influxql.ShowTasksStatement{},
+		},
+
+		// KILL TASK 7
+		{
+			s: `KILL TASK 7`,
+			stmt: &influxql.KillTaskStatement{
+				TaskID: 7,
+			},
+		},
+
 		// SHOW RETENTION POLICIES
 		{
 			s:    `SHOW RETENTION POLICIES`,
@@ -2833,6 +2976,16 @@ func TestParser_ParseStatement(t *testing.T) {
 			},
 		},
 
+		// SHOW AUDIT LOG
+		{
+			s:    `SHOW AUDIT LOG`,
+			stmt: &influxql.ShowAuditLogStatement{},
+		},
+		{
+			s:    `SHOW AUDIT LOG LIMIT 10`,
+			stmt: &influxql.ShowAuditLogStatement{Limit: 10},
+		},
+
 		// SHOW CONTINUOUS QUERIES statement
 		{
 			s:    `SHOW CONTINUOUS QUERIES`,
@@ -2913,6 +3066,31 @@ func TestParser_ParseStatement(t *testing.T) {
 			},
 		},
 
+		{
+			s: `CREATE CONTINUOUS QUERY myquery ON testdb RESAMPLE EVERY 1mo FOR 1y BEGIN SELECT count(field1) INTO measure1 FROM myseries GROUP BY time(1mo) END`,
+			stmt: &influxql.CreateContinuousQueryStatement{
+				Name:     "myquery",
+				Database: "testdb",
+				Source: &influxql.SelectStatement{
+					Fields:  []*influxql.Field{{Expr: &influxql.Call{Name: "count", Args: []influxql.Expr{&influxql.VarRef{Val: "field1"}}}}},
+					Target:  &influxql.Target{Measurement: &influxql.Measurement{Name: "measure1", IsTarget: true}},
+					Sources: []influxql.Source{&influxql.Measurement{Name: "myseries"}},
+					Dimensions: []*influxql.Dimension{
+						{
+							Expr: &influxql.Call{
+								Name: "time",
+								Args: []influxql.Expr{
+									&influxql.DurationLiteral{Months: 1},
+								},
+							},
+						},
+					},
+				},
+				ResampleEveryMonths: 1,
+				ResampleForMonths:   12,
+			},
+		},
+
 		{
 			s: `create continuous query "this.is-a.test" on segments begin select * into measure1 from cpu_load_short end`,
 			stmt: &influxql.CreateContinuousQueryStatement{
@@ -3104,12 +3282,132 @@ func TestParser_ParseStatement(t *testing.T) {
 			},
 		},
 
+		// SET QUOTA
+		{
+			s: `SET QUOTA ON db0 DISK 1000000 SERIES 1000 POINTS PER SECOND 500 QUERIES 4`,
+			stmt: &influxql.SetQuotaStatement{
+				Database:             "db0",
+				MaxDiskBytes:         1000000,
+				MaxSeries:            1000,
+				MaxPointsPerSecond:   500,
+				MaxConcurrentQueries: 4,
+			},
+		},
+
+		// SET QUOTA with a subset of the limits
+		{
+			s: `SET QUOTA ON db0 queries 2 series 10`,
+			stmt: &influxql.SetQuotaStatement{
+				Database:             "db0",
+				MaxSeries:            10,
+				MaxConcurrentQueries: 2,
+			},
+		},
+
+		// DROP QUOTA
+		{
+			s:    `DROP QUOTA ON db0`,
+			stmt: &influxql.DropQuotaStatement{Database: "db0"},
+		},
+
 		// DROP CONTINUOUS QUERY statement
 		{
 			s:    `DROP CONTINUOUS QUERY myquery ON foo`,
 			stmt: &influxql.DropContinuousQueryStatement{Name: "myquery", Database: "foo"},
 		},
 
+		// BACKFILL CONTINUOUS QUERY statement
+		{
+			s: `BACKFILL CONTINUOUS QUERY myquery ON foo FROM '2020-01-01T00:00:00Z' TO '2020-02-01'`,
+			stmt: &influxql.BackfillContinuousQueryStatement{
+				Name:      "myquery",
+				Database:  "foo",
+				StartTime: mustParseTime("2020-01-01T00:00:00Z"),
+				EndTime:   mustParseTime("2020-02-01T00:00:00Z"),
+			},
+		},
+
+		// CREATE DOWNSAMPLE POLICY statement
+		{
+			s: `CREATE DOWNSAMPLE POLICY dp ON db FROM autogen TO rp5m EVERY 5m`,
+			stmt: &influxql.CreateDownsamplePolicyStatement{
+				Name:                  "dp",
+				Database:              "db",
+				SourceRetentionPolicy: "autogen",
+				TargetRetentionPolicy: "rp5m",
+				Interval:              5 * time.Minute,
+			},
+		},
+		{
+			s: `CREATE DOWNSAMPLE POLICY dp ON db FROM autogen TO rp5m EVERY 1h WITH Mean, max, sum`,
+			stmt: &influxql.CreateDownsamplePolicyStatement{
+				Name:                  "dp",
+				Database:              "db",
+				SourceRetentionPolicy: "autogen",
+				TargetRetentionPolicy: "rp5m",
+				Interval:              time.Hour,
+				Functions:             []string{"mean", "max", "sum"},
+			},
+		},
+
+		// DROP DOWNSAMPLE POLICY statement
+		{
+			s:    `DROP DOWNSAMPLE POLICY dp ON db`,
+			stmt: &influxql.DropDownsamplePolicyStatement{Name: "dp", Database: "db"},
+		},
+
+		// SHOW DOWNSAMPLE POLICIES statement
+		{
+			s:    `SHOW DOWNSAMPLE POLICIES`,
+			stmt: &influxql.ShowDownsamplePoliciesStatement{},
+		},
+		{
+			s:    `SHOW DOWNSAMPLE POLICIES ON db`,
+			stmt: &influxql.ShowDownsamplePoliciesStatement{Database: "db"},
+		},
+
+		// CREATE MEASUREMENT SCHEMA statement
+		{
+			s: `CREATE MEASUREMENT SCHEMA cpu ON db (FIELD value float, TAG host REQUIRED, FIELD "count" Integer, TAG region =~ /^(us|eu)-/ LIMIT 10) warn`,
+			stmt: &influxql.CreateMeasurementSchemaStatement{
+				Name:     "cpu",
+				Database: "db",
+				Fields: []*influxql.MeasurementSchemaField{
+					{Name: "value", Type: influxql.Float},
+					{Name: "count", Type: influxql.Integer},
+				},
+				Tags: []*influxql.MeasurementSchemaTag{
+					{Key: "host", Required: true},
+					{Key: "region", Regex: &influxql.RegexLiteral{Val: regexp.MustCompile("^(us|eu)-")}, Limit: 10},
+				},
+				WarnOnly: true,
+			},
+		},
+		{
+			s: `CREATE MEASUREMENT SCHEMA cpu ON db (TAG host)`,
+			stmt: &influxql.CreateMeasurementSchemaStatement{
+				Name:     "cpu",
+				Database: "db",
+				Tags:     []*influxql.MeasurementSchemaTag{{Key: "host"}},
+			},
+		},
+
+		// DROP MEASUREMENT SCHEMA statement
+		{
+			s:    `DROP MEASUREMENT SCHEMA cpu ON db`,
+			stmt: &influxql.DropMeasurementSchemaStatement{Name: "cpu", Database: "db"},
+		},
+
+		// SHOW MEASUREMENT SCHEMAS statement
+		{
+			s:    `SHOW MEASUREMENT SCHEMAS`,
+			stmt: &influxql.ShowMeasurementSchemasStatement{},
+		},
+		{
+			s:    `SHOW MEASUREMENT SCHEMAS ON db`,
+			stmt: &influxql.ShowMeasurementSchemasStatement{Database: "db"},
+		},
+
 		// DROP DATABASE statement
 		{
 			s: `DROP DATABASE testdb`,
@@ -3118,6 +3416,12 @@ func TestParser_ParseStatement(t *testing.T) {
 			},
 		},
 
+		// DROP FIELD statement
+		{
+			s:    `DROP FIELD value FROM cpu`,
+			stmt: &influxql.DropFieldStatement{Name: "value", Measurement: "cpu"},
+		},
+
 		// DROP MEASUREMENT statement
 		{
 			s:    `DROP MEASUREMENT cpu`,
@@ -3251,6 +3555,149 @@ func TestParser_ParseStatement(t *testing.T) {
 			},
 		},
 
+		// CREATE ROLE
+		{
+			s:    `CREATE ROLE analysts`,
+			stmt: &influxql.CreateRoleStatement{Name: "analysts"},
+		},
+
+		// DROP ROLE
+		{
+			s:    `DROP ROLE analysts`,
+			stmt: &influxql.DropRoleStatement{Name: "analysts"},
+		},
+
+		// SHOW ROLES
+		{
+			s:    `SHOW ROLES`,
+			stmt: &influxql.ShowRolesStatement{},
+		},
+
+		// GRANT READ to a role
+		{
+			s: `GRANT READ ON testdb TO ROLE analysts`,
+			stmt: &influxql.GrantStatement{
+				Privilege: influxql.ReadPrivilege,
+				On:        "testdb",
+				Role:      "analysts",
+			},
+		},
+
+		// GRANT WRITE on a measurement to a role
+		{
+			s: `GRANT WRITE ON testdb MEASUREMENT cpu TO ROLE agents`,
+			stmt: &influxql.GrantStatement{
+				Privilege:   influxql.WritePrivilege,
+				On:          "testdb",
+				Measurement: &influxql.Measurement{Name: "cpu"},
+				Role:        "agents",
+			},
+		},
+
+		// REVOKE READ on a measurement regex from a role
+		{
+			s: `REVOKE READ ON testdb MEASUREMENT /^alerts/ FROM ROLE analysts`,
+			stmt: &influxql.RevokeStatement{
+				Privilege:   influxql.ReadPrivilege,
+				On:          "testdb",
+				Measurement: &influxql.Measurement{Regex: &influxql.RegexLiteral{Val: regexp.MustCompile(`^alerts`)}},
+				Role:        "analysts",
+			},
+		},
+
+		// GRANT ROLE
+		{
+			s:    `GRANT ROLE analysts TO jdoe`,
+			stmt: &influxql.GrantRoleStatement{Role: "analysts", User: "jdoe"},
+		},
+
+		// REVOKE ROLE
+		{
+			s:    `REVOKE ROLE analysts FROM jdoe`,
+			stmt: &influxql.RevokeRoleStatement{Role: "analysts", User: "jdoe"},
+		},
+
+		// CREATE TOKEN
+		{
+			s:    `CREATE TOKEN FOR USER ci`,
+			stmt: &influxql.CreateTokenStatement{User: "ci"},
+		},
+		{
+			s: `CREATE TOKEN FOR USER ci WITH WRITE ON telegraf, READ ON "my db" DURATION 90d DESCRIPTION 'build agents'`,
+			stmt: &influxql.CreateTokenStatement{
+				User: "ci",
+				Privileges: []*influxql.TokenPrivilege{
+					{Privilege: influxql.WritePrivilege, Database: "telegraf"},
+					{Privilege: influxql.ReadPrivilege, Database: "my db"},
+				},
+				Duration:    90 * 24 * time.Hour,
+				Description: "build agents",
+			},
+		},
+		{
+			s: `CREATE TOKEN FOR USER ci WITH ALL PRIVILEGES ON telegraf`,
+			stmt: &influxql.CreateTokenStatement{
+				User: "ci",
+				Privileges: []*influxql.TokenPrivilege{
+					{Privilege: influxql.AllPrivileges, Database: "telegraf"},
+				},
+			},
+		},
+
+		// SHOW TOKENS
+		{
+			s:    `SHOW TOKENS`,
+			stmt: &influxql.ShowTokensStatement{},
+		},
+		{
+			s:    `SHOW TOKENS FOR USER ci`,
+			stmt: &influxql.ShowTokensStatement{User: "ci"},
+		},
+
+		// REVOKE TOKEN
+		{
+			s:    `REVOKE TOKEN '0123456789abcdef'`,
+			stmt: &influxql.RevokeTokenStatement{ID: "0123456789abcdef"},
+		},
+
+		// CREATE SECURITY POLICY
+		{
+			s: `CREATE SECURITY POLICY ON telegraf FOR USER acme USING (customer = 'acme')`,
+			stmt: &influxql.CreateSecurityPolicyStatement{
+				Database:  "telegraf",
+				User:      "acme",
+				Condition: MustParseExpr(`customer = 'acme'`),
+			},
+		},
+		{
+			s: `CREATE SECURITY POLICY ON telegraf FOR ROLE tenants USING ((customer = 'acme' OR customer =~ /^acme-/) AND region != 'eu')`,
+			stmt: &influxql.CreateSecurityPolicyStatement{
+				Database:  "telegraf",
+				Role:      "tenants",
+				Condition: MustParseExpr(`(customer = 'acme' OR customer =~ /^acme-/) AND region != 'eu'`),
+			},
+		},
+
+		// DROP SECURITY POLICY
+		{
+			s:    `DROP SECURITY POLICY ON telegraf FOR USER acme`,
+			stmt: &influxql.DropSecurityPolicyStatement{Database: "telegraf", User: "acme"},
+		},
+		{
+			s:    `DROP SECURITY POLICY ON telegraf FOR ROLE tenants`,
+			stmt: &influxql.DropSecurityPolicyStatement{Database: "telegraf", Role: "tenants"},
+		},
+
+		// SHOW SECURITY POLICIES
+		{
+			s:    `SHOW SECURITY POLICIES`,
+			stmt: &influxql.ShowSecurityPoliciesStatement{},
+		},
+		{
+			s:    `SHOW SECURITY POLICIES ON telegraf`,
+			stmt: &influxql.ShowSecurityPoliciesStatement{Database: "telegraf"},
+		},
+
 		// CREATE RETENTION POLICY
 		{
 			s: `CREATE RETENTION POLICY policy1 ON testdb DURATION 1h REPLICATION 2`,
@@ -3316,6 +3763,31 @@ func TestParser_ParseStatement(t *testing.T) {
 			},
 		},
 
+		// CREATE RETENTION POLICY with FIELD TYPE
+		{
+			s: `CREATE RETENTION POLICY policy1 ON testdb DURATION 1h REPLICATION 2 FIELD TYPE 'coerce-numeric' DEFAULT`,
+			stmt: &influxql.CreateRetentionPolicyStatement{
+				Name:            "policy1",
+				Database:        "testdb",
+				Duration:        time.Hour,
+				Replication:     2,
+				FieldTypePolicy: "coerce-numeric",
+				Default:         true,
+			},
+		},
+
+		// ALTER FIELD TYPE
+		{
+			s:    `ALTER FIELD value ON cpu TYPE float`,
+			stmt: &influxql.AlterFieldTypeStatement{Name: "value", Measurement: "cpu", Type: influxql.Float},
+		},
+
+		// ALTER FIELD TYPE with a type in upper case
+		{
+			s:    `ALTER FIELD "type" ON cpu type STRING`,
+			stmt: &influxql.AlterFieldTypeStatement{Name: "type", Measurement: "cpu", Type: influxql.String},
+		},
+
 		// ALTER RETENTION POLICY
 		{
 			s:    `ALTER RETENTION POLICY policy1 ON testdb DURATION 1m REPLICATION 4 DEFAULT`,
@@ -3361,6 +3833,15 @@ func TestParser_ParseStatement(t *testing.T) {
 			s:    `ALTER RETENTION POLICY policy1 ON testdb REPLICATION 4 SHARD DURATION 10m`,
 			stmt: newAlterRetentionPolicyStatement("policy1", "testdb", -1, 10*time.Minute, 4, false),
 		},
+		// ALTER RETENTION POLICY with FIELD TYPE
+		{
+			s: `ALTER RETENTION POLICY policy1 ON testdb FIELD TYPE Stringify`,
+			stmt: &influxql.AlterRetentionPolicyStatement{
+				Name:            "policy1",
+				Database:        "testdb",
+				FieldTypePolicy: func(s string) *string { return &s }("stringify"),
+			},
+		},
 		// ALTER RETENTION POLICY with all options
 		{
 			s:    `ALTER RETENTION POLICY default ON testdb DURATION 0s REPLICATION 4 SHARD DURATION 10m DEFAULT`,
@@ -3421,6 +3902,30 @@ func TestParser_ParseStatement(t *testing.T) {
 				Mode:            "ANY",
 			},
 		},
+		{
+			s: `CREATE SUBSCRIPTION "name" ON "db"."rp" DESTINATIONS ALL 'http://host1:8086' FIELDS level, message WHERE _name =~ /^alerts\./ AND host != 'test'`,
+			stmt: &influxql.CreateSubscriptionStatement{
+				Name:            "name",
+				Database:        "db",
+				RetentionPolicy: "rp",
+				Destinations:    []string{"http://host1:8086"},
+				Mode:            "ALL",
+				Fields:          []string{"level", "message"},
+				Condition: &influxql.BinaryExpr{
+					Op: influxql.AND,
+					LHS: &influxql.BinaryExpr{
+						Op:  influxql.EQREGEX,
+						LHS: &influxql.VarRef{Val: "_name"},
+						RHS: &influxql.RegexLiteral{Val: regexp.MustCompile(`^alerts\.`)},
+					},
+					RHS: &influxql.BinaryExpr{
+						Op:  influxql.NEQ,
+						LHS: &influxql.VarRef{Val: "host"},
+						RHS: &influxql.StringLiteral{Val: "test"},
+					},
+				},
+			},
+		},
 
 		// DROP SUBSCRIPTION
 		{
@@ -3439,12 +3944,19 @@ func TestParser_ParseStatement(t *testing.T) {
 		},
 
 		// Errors
-		{s: ``, err: `found EOF, expected SELECT, DELETE, SHOW, CREATE, DROP, EXPLAIN, GRANT, REVOKE, ALTER, SET, KILL at line 1, char 1`},
+		{s: ``, err: `found EOF, expected SELECT, DELETE, SHOW, CREATE, DROP, EXPLAIN, GRANT, REVOKE, ALTER, SET, KILL, BACKFILL at line 1, char 1`},
 		{s: `SELECT`, err: `found EOF, expected identifier, string, number, bool at line 1, char 8`},
-		{s: `blah blah`, err: `found blah, expected SELECT, DELETE, SHOW, CREATE, DROP, EXPLAIN, GRANT, REVOKE, ALTER, SET, KILL at line 1, char 1`},
+		{s: `blah blah`, err: `found blah, expected SELECT, DELETE, SHOW, CREATE, DROP, EXPLAIN, GRANT, REVOKE, ALTER, SET, KILL, BACKFILL at line 1, char 1`},
 		{s: `SELECT field1 X`, err: `found X, expected FROM at line 1, char 15`},
 		{s: `SELECT field1 FROM "series" WHERE X +;`, err: `found ;, expected identifier, string, number, bool at line 1, char 38`},
 		{s: `SELECT field1 FROM myseries GROUP`, err: `found EOF, expected BY at line 1, char 35`},
+		{s: `SELECT a.value FROM cpu AS a`, err: `found EOF, expected JOIN at line 1, char 30`},
+		{s: `SELECT a.value FROM cpu AS a LEFT b`, err: `found b, expected JOIN at line 1, char 35`},
+		{s: `SELECT a.value FROM cpu AS a JOIN mem AS b`, err: `found EOF, expected ON at line 1, char 44`},
+		{s: `SELECT a.value FROM cpu AS a JOIN mem AS b ON a.host = b.host, disk`, err: `JOIN cannot be combined with other sources`},
+		{s: `SELECT a.value FROM cpu JOIN cpu ON cpu.host = cpu.host`, err: `JOIN sources must have different aliases: cpu`},
+		{s: `SELECT a.value FROM (SELECT value FROM cpu) JOIN mem ON a.host = mem.host`, err: `subquery in JOIN must have an alias`},
+		{s: `SELECT a.value FROM /cpu/ AS a JOIN mem AS b ON a.host = b.host`, err: `JOIN does not support regular expression sources`},
 		{s: `SELECT field1 FROM myseries LIMIT`, err: `found EOF, expected integer at line 1, char 35`},
 		{s: `SELECT field1 FROM myseries LIMIT 10.5`, err: `found 10.5, expected integer at line 1, char 35`},
 		{s: `SELECT field1 FROM myseries OFFSET`, err: `found EOF, expected integer at line 1, char 36`},
@@ -3477,6 +3989,9 @@ func TestParser_ParseStatement(t *testing.T) {
 		{s: `DELETE FROM "foo".myseries`, err: `retention policy not supported at line 1, char 1`},
 		{s: `DELETE FROM foo..myseries`, err: `database not supported at line 1, char 1`},
 		{s: `DROP MEASUREMENT`, err: `found EOF, expected identifier at line 1, char 18`},
+		{s: `DROP FIELD`, err: `found EOF, expected identifier at line 1, char 12`},
+		{s: `DROP FIELD value`, err: `found EOF, expected FROM at line 1, char 18`},
+		{s: `DROP FIELD value FROM`, err: `found EOF, expected identifier at line 1, char 23`},
 		{s: `DROP SERIES`, err: `found EOF, expected FROM, WHERE at line 1, char 13`},
 		{s: `DROP SERIES FROM`, err: `found EOF, expected identifier at line 1, char 18`},
 		{s: `DROP SERIES FROM src WHERE`, err: `found EOF, expected identifier, string, number, bool at line 1, char 28`},
@@ -3487,12 +4002,36 @@ func TestParser_ParseStatement(t *testing.T) {
 		{s: `SHOW RETENTION ON`, err: `found ON, expected POLICIES at line 1, char 16`},
 		{s: `SHOW RETENTION POLICIES ON`, err: `found EOF, expected identifier at line 1, char 28`},
 		{s: `SHOW SHARD`, err: `found EOF, expected GROUPS at line 1, char 12`},
-		{s: `SHOW FOO`, err: `found FOO, expected CONTINUOUS, DATABASES, DIAGNOSTICS, FIELD, GRANTS, MEASUREMENT, MEASUREMENTS, QUERIES, RETENTION, SERIES, SHARD, SHARDS, STATS, SUBSCRIPTIONS, TAG, USERS at line 1, char 6`},
+		{s: `SHOW FOO`, err: `found FOO, expected AUDIT, CONTINUOUS, DATABASES, DIAGNOSTICS, DOWNSAMPLE, FIELD, GRANTS, MEASUREMENT, MEASUREMENTS, QUERIES, QUOTAS, RETENTION, ROLES, SECURITY, SERIES, SHARD, SHARDS, STATS, SUBSCRIPTIONS, TASKS, TAG, TOKENS, USERS at line 1, char 6`},
 		{s: `SHOW STATS FOR`, err: `found EOF, expected string at line 1, char 16`},
 		{s: `SHOW DIAGNOSTICS FOR`, err: `found EOF, expected string at line 1, char 22`},
 		{s: `SHOW GRANTS`, err: `found EOF, expected FOR at line 1, char 13`},
 		{s: `SHOW GRANTS FOR`, err: `found EOF, expected identifier at line 1, char 17`},
 		{s: `DROP CONTINUOUS`, err: `found EOF, expected QUERY at line 1, char 17`},
+		{s: `BACKFILL`, err: `found EOF, expected CONTINUOUS at line 1, char 10`},
+		{s: `BACKFILL CONTINUOUS QUERY myquery`, err: `found EOF, expected ON at line 1, char 35`},
+		{s: `BACKFILL CONTINUOUS QUERY myquery ON foo`, err: `found EOF, expected FROM at line 1, char 42`},
+		{s: `BACKFILL CONTINUOUS QUERY myquery ON foo FROM now()`, err: `found now, expected string at line 1, char 47`},
+		{s: `BACKFILL CONTINUOUS QUERY myquery ON foo FROM 'yesterday'`, err: `invalid timestamp string at line 1, char 46`},
+		{s: `BACKFILL CONTINUOUS QUERY myquery ON foo FROM '2020-01-01'`, err: `found EOF, expected TO at line 1, char 59`},
+		{s: `BACKFILL CONTINUOUS QUERY myquery ON foo FROM '2020-01-01' TO '2019-01-01'`, err: `backfill end time must be after the start time at line 1, char 62`},
+		{s: `CREATE DOWNSAMPLE POLICY`, err: `found EOF, expected identifier at line 1, char 26`},
+		{s: `CREATE DOWNSAMPLE POLICY dp ON db FROM autogen`, err: `found EOF, expected TO at line 1, char 48`},
+		{s: `CREATE DOWNSAMPLE POLICY dp ON db FROM autogen TO rp5m`, err: `found EOF, expected EVERY at line 1, char 56`},
+		{s: `CREATE DOWNSAMPLE POLICY dp ON db FROM autogen TO rp5m EVERY`, err: `found EOF, expected duration at line 1, char 62`},
+		{s: `CREATE DOWNSAMPLE POLICY dp ON db FROM autogen TO rp5m EVERY INF`, err: `invalid duration INF for downsample interval at line 1, char 62`},
+		{s: `CREATE DOWNSAMPLE POLICY dp ON db FROM autogen TO rp5m EVERY 0s`, err: `downsample interval must be positive at line 1, char 62`},
+		{s: `CREATE DOWNSAMPLE POLICY dp ON db FROM autogen TO rp5m EVERY 1mo`, err: `calendar durations (mo, y, cw) are not supported here at line 1, char 62`},
+		{s: `CREATE DOWNSAMPLE POLICY dp ON db FROM autogen TO rp5m EVERY 5m WITH mean, percentile`, err: `unsupported downsample function: percentile at line 1, char 76`},
+		{s: `CREATE DOWNSAMPLE POLICY dp ON db FROM autogen TO rp5m EVERY 5m WITH mean,`, err: `found EOF, expected identifier at line 1, char 75`},
+		{s: `DROP DOWNSAMPLE POLICY dp`, err: `found EOF, expected ON at line 1, char 27`},
+		{s: `CREATE MEASUREMENT SCHEMA cpu ON db`, err: `found EOF, expected ( at line 1, char 37`},
+		{s: `CREATE MEASUREMENT SCHEMA cpu ON db ()`, err: `found ), expected FIELD, TAG at line 1, char 38`},
+		{s: `CREATE MEASUREMENT SCHEMA cpu ON db (FIELD value time)`, err: `found time, expected float, integer, unsigned, string, boolean at line 1, char 50`},
+		{s: `CREATE MEASUREMENT SCHEMA cpu ON db (TAG host LIMIT 0)`, err: `invalid value 0: must be 1 <= n <= 2147483647 at line 1, char 53`},
+		{s: `CREATE MEASUREMENT SCHEMA cpu ON db (TAG host =~ 'us')`, err: `found us, expected regex at line 1, char 49`},
+		{s: `CREATE MEASUREMENT SCHEMA cpu ON db (TAG host TAG region)`, err: `found TAG, expected ,, ) at line 1, char 47`},
+		{s: `DROP MEASUREMENT SCHEMA cpu`, err: `found EOF, expected ON at line 1, char 29`},
 		{s: `DROP CONTINUOUS QUERY`, err: `found EOF, expected identifier at line 1, char 23`},
 		{s: `DROP CONTINUOUS QUERY myquery`, err: `found EOF, expected ON at line 1, char 31`},
 		{s: `DROP CONTINUOUS QUERY myquery ON`, err: `found EOF, expected identifier at line 1, char 34`},
@@ -3500,8 +4039,13 @@ func TestParser_ParseStatement(t *testing.T) {
 		{s: `CREATE CONTINUOUS QUERY`, err: `found EOF, expected identifier at line 1, char 25`},
 		{s: `CREATE CONTINUOUS QUERY cq ON db RESAMPLE FOR 5s BEGIN SELECT mean(value) INTO cpu_mean FROM cpu GROUP BY time(10s) END`, err: `FOR duration must be >= GROUP BY time duration: must be a minimum of 10s, got 5s`},
 		{s: `CREATE CONTINUOUS QUERY cq ON db RESAMPLE EVERY 10s FOR 5s BEGIN SELECT mean(value) INTO cpu_mean FROM cpu GROUP BY time(5s) END`, err: `FOR duration must be >= GROUP BY time duration: must be a minimum of 10s, got 5s`},
-		{s: `DROP FOO`, err: `found FOO, expected CONTINUOUS, DATABASE, MEASUREMENT, RETENTION, SERIES, SHARD, SUBSCRIPTION, USER at line 1, char 6`},
-		{s: `CREATE FOO`, err: `found FOO, expected CONTINUOUS, DATABASE, USER, RETENTION, SUBSCRIPTION at line 1, char 8`},
+		{s: `CREATE CONTINUOUS QUERY cq ON db RESAMPLE FOR 2w BEGIN SELECT mean(value) INTO cpu_mean FROM cpu GROUP BY time(1mo) END`, err: `FOR duration must be >= GROUP BY time duration: must be a minimum of 1mo, got 2w`},
+		{s: `CREATE CONTINUOUS QUERY cq ON db BEGIN SELECT mean(value) INTO cpu_mean FROM cpu GROUP BY time(1mo1d) END`, err: `found ), expected GROUP BY time(...), time dimension cannot mix calendar and fixed units at line 1, char 101`},
+		{s: `CREATE RETENTION POLICY policy1 ON testdb DURATION 1y REPLICATION 1`, err: `calendar durations (mo, y, cw) are not supported here at line 1, char 52`},
+		{s: `CREATE RETENTION POLICY policy1 ON testdb DURATION 1h REPLICATION 1 SHARD DURATION 1mo`, err: `calendar durations (mo, y, cw) are not supported here at line 1, char 84`},
+		{s: `CREATE RETENTION POLICY policy1 ON testdb DURATION 1cw REPLICATION 1`, err: `calendar durations (mo, y, cw) are not supported here at line 1, char 52`},
+		{s: `DROP FOO`, err: `found FOO, expected CONTINUOUS, DATABASE, DOWNSAMPLE, FIELD, MEASUREMENT, QUOTA, RETENTION, ROLE, SECURITY, SERIES, SHARD, SUBSCRIPTION, USER at line 1, char 6`},
+		{s: `CREATE FOO`, err: `found FOO, expected CONTINUOUS, DATABASE, DOWNSAMPLE, MEASUREMENT, USER, RETENTION, ROLE, SECURITY, SUBSCRIPTION, TOKEN at line 1, char 8`},
 		{s: `CREATE DATABASE`, err: `found EOF, expected identifier at line 1, char 17`},
 		{s: `CREATE DATABASE "testdb" WITH`, err: `found EOF, expected DURATION, NAME, REPLICATION, SHARD at line 1, char 31`},
 		{s: `CREATE DATABASE "testdb" WITH DURATION`, err: `found EOF, expected duration at line 1, char 40`},
@@ -3532,6 +4076,10 @@ func TestParser_ParseStatement(t *testing.T) {
 		{s: `CREATE SUBSCRIPTION "name" ON "db"."rp"`, err: `found EOF, expected DESTINATIONS at line 1, char 40`},
 		{s: `CREATE SUBSCRIPTION "name" ON "db"."rp" DESTINATIONS`, err: `found EOF, expected ALL, ANY at line 1, char 54`},
 		{s: `CREATE SUBSCRIPTION "name" ON "db"."rp" DESTINATIONS ALL `, err: `found EOF, expected string at line 1, char 59`},
+		{s: `CREATE SUBSCRIPTION "name" ON "db"."rp" DESTINATIONS ALL 'udp://h:9093' FIELDS`, err: `found EOF, expected identifier at line 1, char 80`},
+		{s: `CREATE SUBSCRIPTION "name" ON "db"."rp" DESTINATIONS ALL 'udp://h:9093' WHERE value > 1`, err: `invalid subscription condition: value > 1`},
+		{s: `CREATE SUBSCRIPTION "name" ON "db"."rp" DESTINATIONS ALL 'udp://h:9093' WHERE host = 1`, err: `invalid subscription condition: host = 1`},
+		{s: `CREATE SUBSCRIPTION "name" ON "db"."rp" DESTINATIONS ALL 'udp://h:9093' WHERE now() = 'x'`, err: `invalid subscription condition: now() = 'x'`},
 		{s: `GRANT`, err: `found EOF, expected READ, WRITE, ALL [PRIVILEGES] at line 1, char 7`},
 		{s: `GRANT BOGUS`, err: `found BOGUS, expected READ, WRITE, ALL [PRIVILEGES] at line 1, char 7`},
 		{s: `GRANT READ`, err: `found EOF, expected ON at line 1, char 12`},
@@ -3564,9 +4112,34 @@ func TestParser_ParseStatement(t *testing.T) {
 		{s: `GRANT ALL PRIVILEGES ON testdb TO`, err: `found EOF, expected identifier at line 1, char 35`},
 		{s: `GRANT ALL TO`, err: `found EOF, expected identifier at line 1, char 14`},
 		{s: `GRANT ALL PRIVILEGES TO`, err: `found EOF, expected identifier at line 1, char 25`},
-		{s: `KILL`, err: `found EOF, expected QUERY at line 1, char 6`},
+		{s: `GRANT READ ON testdb MEASUREMENT cpu TO jdoe`, err: `found jdoe, expected ROLE at line 1, char 41`},
+		{s: `GRANT READ ON testdb TO ROLE`, err: `found EOF, expected identifier at line 1, char 30`},
+		{s: `GRANT ROLE analysts`, err: `found EOF, expected TO at line 1, char 21`},
+		{s: `REVOKE ROLE analysts TO jdoe`, err: `found TO, expected FROM at line 1, char 22`},
+		{s: `CREATE ROLE`, err: `found EOF, expected identifier at line 1, char 13`},
+		{s: `CREATE TOKEN ci`, err: `found ci, expected FOR at line 1, char 14`},
+		{s: `CREATE TOKEN FOR ci`, err: `found ci, expected USER at line 1, char 18`},
+		{s: `CREATE TOKEN FOR USER ci WITH READ testdb`, err: `found testdb, expected ON at line 1, char 36`},
+		{s: `CREATE TOKEN FOR USER ci WITH READ ON testdb,`, err: `found EOF, expected READ, WRITE, ALL [PRIVILEGES] at line 1, char 46`},
+		{s: `CREATE TOKEN FOR USER ci DESCRIPTION ci`, err: `found ci, expected string at line 1, char 38`},
+		{s: `SHOW TOKENS FOR ci`, err: `found ci, expected USER at line 1, char 17`},
+		{s: `REVOKE TOKEN abc`, err: `found abc, expected string at line 1, char 14`},
+		{s: `CREATE SECURITY POLICY telegraf`, err: `found telegraf, expected ON at line 1, char 24`},
+		{s: `CREATE SECURITY POLICY ON telegraf USER acme`, err: `found USER, expected FOR at line 1, char 36`},
+		{s: `CREATE SECURITY POLICY ON telegraf FOR acme`, err: `found acme, expected USER, ROLE at line 1, char 40`},
+		{s: `CREATE SECURITY POLICY ON telegraf FOR USER acme WHERE customer = 'acme'`, err: `found WHERE, expected USING at line 1, char 50`},
+		{s: `CREATE SECURITY POLICY ON telegraf FOR USER acme USING customer = 'acme'`, err: `found customer, expected ( at line 1, char 56`},
+		{s: `CREATE SECURITY POLICY ON telegraf FOR USER acme USING (customer = 'acme'`, err: `found EOF, expected ) at line 1, char 74`},
+		{s: `CREATE SECURITY POLICY ON telegraf FOR USER acme USING (value > 1)`, err: `security policy condition must compare tags to strings or regular expressions: value > 1 at line 1, char 56`},
+		{s: `DROP SECURITY POLICY ON telegraf`, err: `found EOF, expected FOR at line 1, char 34`},
+		{s: `SHOW SECURITY POLICIES ON`, err: `found EOF, expected identifier at line 1, char 27`},
+		{s: `SHOW AUDIT`, err: `found EOF, expected LOG at line 1, char 12`},
+		{s: `SHOW AUDIT LOG LIMIT`, err: `found EOF, expected integer at line 1, char 22`},
+		{s: `KILL`, err: `found EOF, expected QUERY, TASK at line 1, char 6`},
 		{s: `KILL QUERY 10s`, err: `found 10s, expected integer at line 1, char 12`},
 		{s: `KILL QUERY 4 ON 'host'`, err: `found host, expected identifier at line 1, char 16`},
+		{s: `KILL TASK`, err: `found EOF, expected integer at line 1, char 11`},
+		{s: `KILL TASK -1`, err: `found -, expected integer at line 1, char 11`},
 		{s: `REVOKE`, err: `found EOF, expected READ, WRITE, ALL [PRIVILEGES] at line 1, char 8`},
 		{s: `REVOKE BOGUS`, err: `found BOGUS, expected READ, WRITE, ALL [PRIVILEGES] at line 1, char 8`},
 		{s: `REVOKE READ`, err: `found EOF, expected ON at line 1, char 13`},
@@ -3612,22 +4185,37 @@ func TestParser_ParseStatement(t *testing.T) {
 		{s: `CREATE RETENTION POLICY policy1 ON testdb DURATION 1h REPLICATION 0`, err: `invalid value 0: must be 1 <= n <= 2147483647 at line 1, char 67`},
 		{s: `CREATE RETENTION POLICY policy1 ON testdb DURATION 1h REPLICATION bad`, err: `found bad, expected integer at line 1, char 67`},
 		{s: `CREATE RETENTION POLICY policy1 ON testdb DURATION 1h REPLICATION 2 SHARD DURATION INF`, err: `invalid duration INF for shard duration at line 1, char 84`},
-		{s: `ALTER`, err: `found EOF, expected RETENTION at line 1, char 7`},
+		{s: `ALTER`, err: `found EOF, expected FIELD, RETENTION at line 1, char 7`},
+		{s: `ALTER FIELD`, err: `found EOF, expected identifier at line 1, char 13`},
+		{s: `ALTER FIELD value`, err: `found EOF, expected ON at line 1, char 19`},
+		{s: `ALTER FIELD value ON cpu`, err: `found EOF, expected TYPE at line 1, char 26`},
+		{s: `ALTER FIELD value ON cpu TYPE`, err: `found EOF, expected float, integer, unsigned, string, boolean at line 1, char 31`},
+		{s: `ALTER FIELD value ON cpu TYPE time`, err: `found time, expected float, integer, unsigned, string, boolean at line 1, char 31`},
 		{s: `ALTER RETENTION`, err: `found EOF, expected POLICY at line 1, char 17`},
 		{s: `ALTER RETENTION POLICY`, err: `found EOF, expected identifier at line 1, char 24`},
 		{s: `ALTER RETENTION POLICY policy1`, err: `found EOF, expected ON at line 1, char 32`}, {s: `ALTER RETENTION POLICY policy1 ON`, err: `found EOF, expected identifier at line 1, char 35`},
-		{s: `ALTER RETENTION POLICY policy1 ON testdb`, err: `found EOF, expected DURATION, REPLICATION, SHARD, DEFAULT at line 1, char 42`},
+		{s: `ALTER RETENTION POLICY policy1 ON testdb`, err: `found EOF, expected DURATION, REPLICATION, SHARD, FIELD, DEFAULT at line 1, char 42`},
 		{s: `ALTER RETENTION POLICY policy1 ON testdb REPLICATION 1 REPLICATION 2`, err: `found duplicate REPLICATION option at line 1, char 56`},
 		{s: `ALTER RETENTION POLICY policy1 ON testdb DURATION 15251w`, err: `overflowed duration 15251w: choose a smaller duration or INF at line 1, char 51`},
+		{s: `ALTER RETENTION POLICY policy1 ON testdb FIELD TYPE lenient`, err: `found lenient, expected strict, coerce-numeric, stringify at line 1, char 53`},
+		{s: `ALTER RETENTION POLICY policy1 ON testdb FIELD strict`, err: `found strict, expected TYPE at line 1, char 48`},
 		{s: `ALTER RETENTION POLICY policy1 ON testdb DURATION INF SHARD DURATION INF`, err: `invalid duration INF for shard duration at line 1, char 70`},
-		{s: `SET`, err: `found EOF, expected PASSWORD at line 1, char 5`},
+		{s: `SET`, err: `found EOF, expected PASSWORD, QUOTA at line 1, char 5`},
 		{s: `SET PASSWORD`, err: `found EOF, expected FOR at line 1, char 14`},
 		{s: `SET PASSWORD something`, err: `found something, expected FOR at line 1, char 14`},
 		{s: `SET PASSWORD FOR`, err: `found EOF, expected identifier at line 1, char 18`},
 		{s: `SET PASSWORD FOR dejan`, err: `found EOF, expected = at line 1, char 24`},
 		{s: `SET PASSWORD FOR dejan =`, err: `found EOF, expected string at line 1, char 25`},
 		{s: `SET PASSWORD FOR dejan = bla`, err: `found bla, expected string at line 1, char 26`},
-		{s: `$SHOW$DATABASES`, err: `found $SHOW, expected SELECT, DELETE, SHOW, CREATE, DROP, EXPLAIN, GRANT, REVOKE, ALTER, SET, KILL at line 1, char 1`},
+		{s: `SET QUOTA`, err: `found EOF, expected ON at line 1, char 11`},
+		{s: `SET QUOTA ON db0`, err: `found EOF, expected DISK, SERIES, POINTS, QUERIES at line 1, char 18`},
+		{s: `SET QUOTA ON db0 DISK`, err: `found EOF, expected integer at line 1, char 23`},
+		{s: `SET QUOTA ON db0 DISK 1 DISK 2`, err: `found duplicate DISK option at line 1, char 25`},
+		{s: `SET QUOTA ON db0 POINTS 10`, err: `found 10, expected PER at line 1, char 25`},
+		{s: `SET QUOTA ON db0 QUERIES -1`, err: `found -, expected integer at line 1, char 26`},
+		{s: `DROP QUOTA`, err: `found EOF, expected ON at line 1, char 12`},
+		{s: `DROP QUOTA ON`, err: `found EOF, expected identifier at line 1, char 15`},
+		{s: `$SHOW$DATABASES`, err: `found $SHOW, expected SELECT, DELETE, SHOW, CREATE, DROP, EXPLAIN, GRANT, REVOKE, ALTER, SET, KILL, BACKFILL at line 1, char 1`},
 		{s: `SELECT * FROM cpu WHERE "tagkey" = $$`, err: `empty bound parameter`},
 
 		// Create a database with a bound parameter.
@@ -4047,9 +4635,23 @@ func TestParser_ParseExpr(t *testing.T) {
 			},
 		},
 
+		// Duration math with a calendar literal.
+		{
+			s: `time > now() - 1y`,
+			expr: &influxql.BinaryExpr{
+				Op:  influxql.GT,
+				LHS: &influxql.VarRef{Val: "time"},
+				RHS: &influxql.BinaryExpr{
+					Op:  influxql.SUB,
+					LHS: &influxql.Call{Name: "now"},
+					RHS: &influxql.DurationLiteral{Months: 12},
+				},
+			},
+		},
+
 		// Duration math with an invalid literal.
 		{
-			s:   `time > now() - 1y`,
+			s:   `time > now() - 1x`,
 			err: `invalid duration`,
 		},
 
@@ -4076,6 +4678,64 @@ func TestParser_ParseExpr(t *testing.T) {
 				},
 			},
 		},
+
+		// CASE expression
+		{
+			s: `CASE WHEN status = 'err' THEN 1 WHEN status = 'warn' THEN 2 ELSE 0 END`,
+			expr: &influxql.CaseExpr{
+				WhenClauses: []*influxql.WhenClause{
+					{
+						Condition: &influxql.BinaryExpr{
+							Op:  influxql.EQ,
+							LHS: &influxql.VarRef{Val: "status"},
+							RHS: &influxql.StringLiteral{Val: "err"},
+						},
+						Result: &influxql.IntegerLiteral{Val: 1},
+					},
+					{
+						Condition: &influxql.BinaryExpr{
+							Op:  influxql.EQ,
+							LHS: &influxql.VarRef{Val: "status"},
+							RHS: &influxql.StringLiteral{Val: "warn"},
+						},
+						Result: &influxql.IntegerLiteral{Val: 2},
+					},
+				},
+				Else: &influxql.IntegerLiteral{Val: 0},
+			},
+		},
+
+		// CASE expression without ELSE inside of a call
+		{
+			s: `count(CASE WHEN value > 10 THEN value * 2 END)`,
+			expr: &influxql.Call{
+				Name: "count",
+				Args: []influxql.Expr{
+					&influxql.CaseExpr{
+						WhenClauses: []*influxql.WhenClause{
+							{
+								Condition: &influxql.BinaryExpr{
+									Op:  influxql.GT,
+									LHS: &influxql.VarRef{Val: "value"},
+									RHS: &influxql.IntegerLiteral{Val: 10},
+								},
+								Result: &influxql.BinaryExpr{
+									Op:  influxql.MUL,
+									LHS: &influxql.VarRef{Val: "value"},
+									RHS: &influxql.IntegerLiteral{Val: 2},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+
+		// Invalid CASE expressions
+		{s: `CASE END`, err: `found END, expected WHEN at line 1, char 6`},
+		{s: `CASE WHEN value > 1 1 END`, err: `found 1, expected THEN at line 1, char 21`},
+		{s: `CASE WHEN value > 1 THEN 1`, err: `found EOF, expected WHEN, ELSE, END at line 1, char 27`},
+		{s: `CASE WHEN value > 1 THEN 1 ELSE 0`, err: `found EOF, expected END at line 1, char 34`},
 	}
 
 	for i, tt := range tests {
@@ -4139,6 +4799,88 @@ func TestParseDuration(t *testing.T) {
 	}
 }
 
+func TestParseCalendarDuration(t *testing.T) {
+	var tests = []struct {
+		s      string
+		months int
+		d      time.Duration
+		err    string
+	}{
+		{s: `1mo`, months: 1},
+		{s: `18mo`, months: 18},
+		{s: `2y`, months: 24},
+		{s: `1y6mo`, months: 18},
+		{s: `1mo2d`, months: 1, d: 2 * 24 * time.Hour},
+		{s: `-1y`, months: -12},
+		{s: `2w`, d: 2 * 7 * 24 * time.Hour},
+
+		{s: `mo`, err: "invalid duration"},
+		{s: `1.5y`, err: "invalid duration"},
+		{s: `1mo2x`, err: "invalid duration"},
+	}
+
+	for i, tt := range tests {
+		months, d, err := influxql.ParseCalendarDuration(tt.s)
+		if !reflect.DeepEqual(tt.err, errstring(err)) {
+			t.Errorf("%d. %q: error mismatch:\n  exp=%s\n  got=%s\n\n", i, tt.s, tt.err, err)
+		} else if tt.months != months || tt.d != d {
+			t.Errorf("%d. %q: duration mismatch: exp=%dmo %v got=%dmo %v", i, tt.s, tt.months, tt.d, months, d)
+		}
+	}
+}
+
+func TestParseCalendarWeeks(t *testing.T) {
+	var tests = []struct {
+		s   string
+		d   time.Duration
+		err string
+	}{
+		{s: `1cw`, d: 7 * 24 * time.Hour},
+		{s: `4cw`, d: 4 * 7 * 24 * time.Hour},
+
+		{s: `cw`, err: "invalid duration"},
+		{s: `-1cw`, err: "invalid duration"},
+		{s: `1w`, err: "invalid duration"},
+		{s: `1cw1d`, err: "invalid duration"},
+		{s: `100000cw`, err: "invalid duration"},
+	}
+
+	for i, tt := range tests {
+		d, err := influxql.ParseCalendarWeeks(tt.s)
+		if !reflect.DeepEqual(tt.err, errstring(err)) {
+			t.Errorf("%d. %q: error mismatch:\n  exp=%s\n  got=%s\n\n", i, tt.s, tt.err, err)
+		} else if tt.d != d {
+			t.Errorf("%d. %q: duration mismatch: exp=%v got=%v", i, tt.s, tt.d, d)
+		}
+	}
+}
+
+// Ensure calendar weeks start at midnight on a Monday in the location of the time.
+func TestTruncateWeeks(t *testing.T) {
+	loc, err := time.LoadLocation("America/Los_Angeles")
+	if err != nil {
+		t.Skip(err)
+	}
+
+	var tests = []struct {
+		t   time.Time
+		n   int
+		exp time.Time
+	}{
+		{t: time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC), n: 1, exp: time.Date(1969, 12, 29, 0, 0, 0, 0, time.UTC)},
+		{t: time.Date(1970, 1, 5, 0, 0, 0, 0, time.UTC), n: 1, exp: time.Date(1970, 1, 5, 0, 0, 0, 0, time.UTC)},
+		{t: time.Date(1970, 1, 18, 23, 0, 0, 0, time.UTC), n: 2, exp: time.Date(1970, 1, 5, 0, 0, 0, 0, time.UTC)},
+		{t: time.Date(2000, 4, 5, 12, 0, 0, 0, loc), n: 1, exp: time.Date(2000, 4, 3, 0, 0, 0, 0, loc)},
+		{t: time.Date(2000, 4, 2, 12, 0, 0, 0, loc), n: 1, exp: time.Date(2000, 3, 27, 0, 0, 0, 0, loc)},
+	}
+
+	for i, tt := range tests {
+		if got := influxql.TruncateWeeks(tt.t, tt.n); !got.Equal(tt.exp) {
+			t.Errorf("%d. %s: unexpected start: exp=%s got=%s", i, tt.t, tt.exp, got)
+		}
+	}
+}
+
 // Ensure a time duration can be formatted.
 func TestFormatDuration(t *testing.T) {
 	var tests = []struct {
@@ -4163,6 +4905,29 @@ func TestFormatDuration(t *testing.T) {
 	}
 }
 
+// Ensure a calendar duration can be formatted.
+func TestFormatCalendarDuration(t *testing.T) {
+	var tests = []struct {
+		months int
+		d      time.Duration
+		s      string
+	}{
+		{months: 1, s: `1mo`},
+		{months: 18, s: `18mo`},
+		{months: 24, s: `2y`},
+		{months: 1, d: 2 * 24 * time.Hour, s: `1mo2d`},
+		{months: -12, s: `-1y`},
+		{d: 2 * time.Hour, s: `2h`},
+	}
+
+	for i, tt := range tests {
+		s := influxql.FormatCalendarDuration(tt.months, tt.d)
+		if tt.s != s {
+			t.Errorf("%d. %dmo %v: mismatch: %s != %s", i, tt.months, tt.d, tt.s, s)
+		}
+	}
+}
+
 // Ensure a string can be quoted.
 func TestQuote(t *testing.T) {
 	for i, tt := range []struct {
@@ -108,6 +108,7 @@ func TestScanner_Scan(t *testing.T) {
 		{s: `ALTER`, tok: influxql.ALTER},
 		{s: `AS`, tok: influxql.AS},
 		{s: `ASC`, tok: influxql.ASC},
+		{s: `BACKFILL`, tok: influxql.BACKFILL},
 		{s: `BEGIN`, tok: influxql.BEGIN},
 		{s: `BY`, tok: influxql.BY},
 		{s: `CREATE`, tok: influxql.CREATE},
@@ -117,6 +118,7 @@ func TestScanner_Scan(t *testing.T) {
 		{s: `DEFAULT`, tok: influxql.DEFAULT},
 		{s: `DELETE`, tok: influxql.DELETE},
 		{s: `DESC`, tok: influxql.DESC},
+		{s: `DOWNSAMPLE`, tok: influxql.DOWNSAMPLE},
 		{s: `DROP`, tok: influxql.DROP},
 		{s: `DURATION`, tok: influxql.DURATION},
 		{s: `END`, tok: influxql.END},
@@ -155,6 +157,8 @@ func TestScanner_Scan(t *testing.T) {
 		{s: `SELECT`, tok: influxql.SELECT},
 		{s: `SERIES`, tok: influxql.SERIES},
 		{s: `TAG`, tok: influxql.TAG},
+		{s: `TASK`, tok: influxql.TASK},
+		{s: `TASKS`, tok: influxql.TASKS},
 		{s: `TO`, tok: influxql.TO},
 		{s: `USER`, tok: influxql.USER},
 		{s: `USERS`, tok: influxql.USERS},
@@ -71,9 +71,12 @@ const (
 	ANY
 	AS
 	ASC
+	AUDIT
+	BACKFILL
 	BEGIN
 	BY
 	CARDINALITY
+	CASE
 	CREATE
 	CONTINUOUS
 	DATABASE
@@ -84,8 +87,10 @@ const (
 	DESTINATIONS
 	DIAGNOSTICS
 	DISTINCT
+	DOWNSAMPLE
 	DROP
 	DURATION
+	ELSE
 	END
 	EVERY
 	EXACT
@@ -97,6 +102,7 @@ const (
 	GRANTS
 	GROUP
 	GROUPS
+	HAVING
 	IN
 	INF
 	INSERT
@@ -117,11 +123,18 @@ const (
 	PRIVILEGES
 	QUERIES
 	QUERY
+	QUOTA
+	QUOTAS
 	READ
 	REPLICATION
 	RESAMPLE
 	RETENTION
 	REVOKE
+	ROLE
+	ROLES
+	SCHEMA
+	SCHEMAS
+	SECURITY
 	SELECT
 	SERIES
 	SET
@@ -134,10 +147,16 @@ const (
 	SUBSCRIPTION
 	SUBSCRIPTIONS
 	TAG
+	TASK
+	TASKS
+	THEN
 	TO
+	TOKEN
+	TOKENS
 	USER
 	USERS
 	VALUES
+	WHEN
 	WHERE
 	WITH
 	WRITE
@@ -194,9 +213,12 @@ var tokens = [...]string{
 	ANY:           "ANY",
 	AS:            "AS",
 	ASC:           "ASC",
+	AUDIT:         "AUDIT",
+	BACKFILL:      "BACKFILL",
 	BEGIN:         "BEGIN",
 	BY:            "BY",
 	CARDINALITY:   "CARDINALITY",
+	CASE:          "CASE",
 	CREATE:        "CREATE",
 	CONTINUOUS:    "CONTINUOUS",
 	DATABASE:      "DATABASE",
@@ -207,8 +229,10 @@ var tokens = [...]string{
 	DESTINATIONS:  "DESTINATIONS",
 	DIAGNOSTICS:   "DIAGNOSTICS",
 	DISTINCT:      "DISTINCT",
+	DOWNSAMPLE:    "DOWNSAMPLE",
 	DROP:          "DROP",
 	DURATION:      "DURATION",
+	ELSE:          "ELSE",
 	END:           "END",
 	EVERY:         "EVERY",
 	EXACT:         "EXACT",
@@ -220,6 +244,7 @@ var tokens = [...]string{
 	GRANTS:        "GRANTS",
 	GROUP:         "GROUP",
 	GROUPS:        "GROUPS",
+	HAVING:        "HAVING",
 	IN:            "IN",
 	INF:           "INF",
 	INSERT:        "INSERT",
@@ -240,11 +265,18 @@ var tokens = [...]string{
 	PRIVILEGES:    "PRIVILEGES",
 	QUERIES:       "QUERIES",
 	QUERY:         "QUERY",
+	QUOTA:         "QUOTA",
+	QUOTAS:        "QUOTAS",
 	READ:          "READ",
 	REPLICATION:   "REPLICATION",
 	RESAMPLE:      "RESAMPLE",
 	RETENTION:     "RETENTION",
 	REVOKE:        "REVOKE",
+	ROLE:          "ROLE",
+	ROLES:         "ROLES",
+	SCHEMA:        "SCHEMA",
+	SCHEMAS:       "SCHEMAS",
+	SECURITY:      "SECURITY",
 	SELECT:        "SELECT",
 	SERIES:        "SERIES",
 	SET:           "SET",
@@ -257,10 +289,16 @@ var tokens = [...]string{
 	SUBSCRIPTION:  "SUBSCRIPTION",
 	SUBSCRIPTIONS: "SUBSCRIPTIONS",
 	TAG:           "TAG",
+	TASK:          "TASK",
+	TASKS:         "TASKS",
+	THEN:          "THEN",
 	TO:            "TO",
+	TOKEN:         "TOKEN",
+	TOKENS:        "TOKENS",
 	USER:          "USER",
 	USERS:         "USERS",
 	VALUES:        "VALUES",
+	WHEN:          "WHEN",
 	WHERE:         "WHERE",
 	WITH:          "WITH",
 	WRITE:         "WRITE",
@@ -13,35 +13,58 @@ type MetaClientMock struct {
 	CreateContinuousQueryFn             func(database, name, query string) error
 	CreateDatabaseFn                    func(name string) (*meta.DatabaseInfo, error)
 	CreateDatabaseWithRetentionPolicyFn func(name string, spec *meta.RetentionPolicySpec) (*meta.DatabaseInfo, error)
+	CreateDownsamplePolicyFn            func(database string, dpi meta.DownsamplePolicyInfo) error
+	CreateMeasurementSchemaFn           func(database string, msi meta.MeasurementSchemaInfo) error
 	CreateRetentionPolicyFn             func(database string, spec *meta.RetentionPolicySpec, makeDefault bool) (*meta.RetentionPolicyInfo, error)
+	CreateRoleFn                        func(name string) error
 	CreateShardGroupFn                  func(database, policy string, timestamp time.Time) (*meta.ShardGroupInfo, error)
-	CreateSubscriptionFn                func(database, rp, name, mode string, destinations []string) error
+	CreateSubscriptionWithFilterFn      func(database, rp, name, mode string, destinations, fields []string, condition string) error
 	CreateUserFn                        func(name, password string, admin bool) (meta.User, error)
 
 	DatabaseFn  func(name string) *meta.DatabaseInfo
 	DatabasesFn func() []meta.DatabaseInfo
 
-	DataFn                func() meta.Data
-	DeleteShardGroupFn    func(database string, policy string, id uint64) error
-	DropContinuousQueryFn func(database, name string) error
-	DropDatabaseFn        func(name string) error
-	DropRetentionPolicyFn func(database, name string) error
-	DropSubscriptionFn    func(database, rp, name string) error
-	DropShardFn           func(id uint64) error
-	DropUserFn            func(name string) error
+	DataFn                  func() meta.Data
+	DeleteShardGroupFn      func(database string, policy string, id uint64) error
+	DropContinuousQueryFn   func(database, name string) error
+	DropDatabaseFn          func(name string) error
+	DropDownsamplePolicyFn  func(database, name string) error
+	DropMeasurementSchemaFn func(database, name string) error
+	DropQuotaFn             func(database string) error
+	DropRetentionPolicyFn   func(database, name string) error
+	DropRoleFn              func(name string) error
+	DropSubscriptionFn      func(database, rp, name string) error
+	DropShardFn             func(id uint64) error
+	DropUserFn              func(name string) error
+	EffectivePrivilegesFn   func(username string) ([]meta.PrivilegeInfo, error)
+	GrantRoleFn             func(name, username string) error
+	GrantRolePrivilegeFn    func(name string, p meta.PrivilegeInfo) error
 
 	OpenFn func() error
 
 	PrecreateShardGroupsFn func(from, to time.Time) error
 	PruneShardGroupsFn     func() error
 
-	RetentionPolicyFn func(database, name string) (rpi *meta.RetentionPolicyInfo, err error)
+	RetentionPolicyFn     func(database, name string) (rpi *meta.RetentionPolicyInfo, err error)
+	RevokeRoleFn          func(name, username string) error
+	RevokeRolePrivilegeFn func(name string, p meta.PrivilegeInfo) error
+	RolesFn               func() []meta.RoleInfo
+
+	CreateTokenFn func(username, description string, privileges map[string]influxql.Privilege, expiresAt time.Time) (*meta.TokenInfo, string, error)
+	RevokeTokenFn func(id string) error
+	TokensFn      func() []meta.TokenInfo
+
+	CreateSecurityPolicyFn func(pi meta.SecurityPolicyInfo) error
+	DropSecurityPolicyFn   func(database, user, role string) error
+	SecurityPoliciesFn     func() []meta.SecurityPolicyInfo
 
 	AuthenticateFn           func(username, password string) (ui meta.User, err error)
+	AuthenticateTokenFn      func(token string) (meta.User, error)
 	AdminUserExistsFn        func() bool
 	SetAdminPrivilegeFn      func(username string, admin bool) error
 	SetDataFn                func(*meta.Data) error
 	SetPrivilegeFn           func(username, database string, p influxql.Privilege) error
+	SetQuotaFn               func(database string, qi meta.QuotaInfo) error
 	ShardGroupsByTimeRangeFn func(database, policy string, min, max time.Time) (a []meta.ShardGroupInfo, err error)
 	ShardOwnerFn             func(shardID uint64) (database, policy string, sgi *meta.ShardGroupInfo)
 	TruncateShardGroupsFn    func(t time.Time) error
@@ -69,6 +92,14 @@ func (c *MetaClientMock) CreateDatabaseWithRetentionPolicy(name string, spec *me
 	return c.CreateDatabaseWithRetentionPolicyFn(name, spec)
 }
 
+func (c *MetaClientMock) CreateDownsamplePolicy(database string, dpi meta.DownsamplePolicyInfo) error {
+	return c.CreateDownsamplePolicyFn(database, dpi)
+}
+
+func (c *MetaClientMock) CreateMeasurementSchema(database string, msi meta.MeasurementSchemaInfo) error {
+	return c.CreateMeasurementSchemaFn(database, msi)
+}
+
 func (c *MetaClientMock) CreateRetentionPolicy(database string, spec *meta.RetentionPolicySpec, makeDefault bool) (*meta.RetentionPolicyInfo, error) {
 	return c.CreateRetentionPolicyFn(database, spec, makeDefault)
 }
@@ -77,8 +108,8 @@ func (c *MetaClientMock) CreateShardGroup(database, policy string, timestamp tim
 	return c.CreateShardGroupFn(database, policy, timestamp)
 }
 
-func (c *MetaClientMock) CreateSubscription(database, rp, name, mode string, destinations []string) error {
-	return c.CreateSubscriptionFn(database, rp, name, mode, destinations)
+func (c *MetaClientMock) CreateSubscriptionWithFilter(database, rp, name, mode string, destinations, fields []string, condition string) error {
+	return c.CreateSubscriptionWithFilterFn(database, rp, name, mode, destinations, fields, condition)
 }
 
 func (c *MetaClientMock) CreateUser(name, password string, admin bool) (meta.User, error) {
@@ -105,6 +136,18 @@ func (c *MetaClientMock) DropDatabase(name string) error {
 	return c.DropDatabaseFn(name)
 }
 
+func (c *MetaClientMock) DropDownsamplePolicy(database, name string) error {
+	return c.DropDownsamplePolicyFn(database, name)
+}
+
+func (c *MetaClientMock) DropMeasurementSchema(database, name string) error {
+	return c.DropMeasurementSchemaFn(database, name)
+}
+
+func (c *MetaClientMock) DropQuota(database string) error {
+	return c.DropQuotaFn(database)
+}
+
 func (c *MetaClientMock) DropRetentionPolicy(database, name string) error {
 	return c.DropRetentionPolicyFn(database, name)
 }
@@ -133,6 +176,10 @@ func (c *MetaClientMock) SetPrivilege(username, database string, p influxql.Priv
 	return c.SetPrivilegeFn(username, database, p)
 }
 
+func (c *MetaClientMock) SetQuota(database string, qi meta.QuotaInfo) error {
+	return c.SetQuotaFn(database, qi)
+}
+
 func (c *MetaClientMock) ShardGroupsByTimeRange(database, policy string, min, max time.Time) (a []meta.ShardGroupInfo, err error) {
 	return c.ShardGroupsByTimeRangeFn(database, policy, min, max)
 }
@@ -164,6 +211,15 @@ func (c *MetaClientMock) UserPrivileges(username string) (map[string]influxql.Pr
 func (c *MetaClientMock) Authenticate(username, password string) (meta.User, error) {
 	return c.AuthenticateFn(username, password)
 }
+func (c *MetaClientMock) AuthenticateFrom(username, password, source string) (meta.User, error) {
+	return c.AuthenticateFn(username, password)
+}
+func (c *MetaClientMock) AuthenticateToken(token string) (meta.User, error) {
+	return c.AuthenticateTokenFn(token)
+}
+func (c *MetaClientMock) AuthenticateTokenFrom(token, source string) (meta.User, error) {
+	return c.AuthenticateTokenFn(token)
+}
 func (c *MetaClientMock) AdminUserExists() bool { return c.AdminUserExistsFn() }
 
 func (c *MetaClientMock) User(username string) (meta.User, error) { return c.UserFn(username) }
@@ -177,3 +233,59 @@ func (c *MetaClientMock) PrecreateShardGroups(from, to time.Time) error {
 	return c.PrecreateShardGroupsFn(from, to)
 }
 func (c *MetaClientMock) PruneShardGroups() error { return c.PruneShardGroupsFn() }
+
+func (c *MetaClientMock) CreateRole(name string) error {
+	return c.CreateRoleFn(name)
+}
+
+func (c *MetaClientMock) DropRole(name string) error {
+	return c.DropRoleFn(name)
+}
+
+func (c *MetaClientMock) EffectivePrivileges(username string) ([]meta.PrivilegeInfo, error) {
+	return c.EffectivePrivilegesFn(username)
+}
+
+func (c *MetaClientMock) GrantRole(name, username string) error {
+	return c.GrantRoleFn(name, username)
+}
+
+func (c *MetaClientMock) GrantRolePrivilege(name string, p meta.PrivilegeInfo) error {
+	return c.GrantRolePrivilegeFn(name, p)
+}
+
+func (c *MetaClientMock) RevokeRole(name, username string) error {
+	return c.RevokeRoleFn(name, username)
+}
+
+func (c *MetaClientMock) RevokeRolePrivilege(name string, p meta.PrivilegeInfo) error {
+	return c.RevokeRolePrivilegeFn(name, p)
+}
+
+func (c *MetaClientMock) Roles() []meta.RoleInfo {
+	return c.RolesFn()
+}
+
+func (c *MetaClientMock) CreateToken(username, description string, privileges map[string]influxql.Privilege, expiresAt time.Time) (*meta.TokenInfo, string, error) {
+	return c.CreateTokenFn(username, description, privileges, expiresAt)
+}
+
+func (c *MetaClientMock) RevokeToken(id string) error {
+	return c.RevokeTokenFn(id)
+}
+
+func (c *MetaClientMock) Tokens() []meta.TokenInfo {
+	return c.TokensFn()
+}
+
+func (c *MetaClientMock) CreateSecurityPolicy(pi meta.SecurityPolicyInfo) error {
+	return c.CreateSecurityPolicyFn(pi)
+}
+
+func (c *MetaClientMock) DropSecurityPolicy(database, user, role string) error {
+	return c.DropSecurityPolicyFn(database, user, role)
+}
+
+func (c *MetaClientMock) SecurityPolicies() []meta.SecurityPolicyInfo {
+	return c.SecurityPoliciesFn()
+}
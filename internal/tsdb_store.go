@@ -1,6 +1,7 @@
 package internal
 
 import (
+	"context"
 	"io"
 	"time"
 
@@ -17,10 +18,14 @@ type TSDBStoreMock struct {
 	BackupSeriesFileFn        func(database string, w io.Writer) error
 	ExportShardFn             func(id uint64, ExportStart time.Time, ExportEnd time.Time, w io.Writer) error
 	CloseFn                   func() error
+	ConvertFieldFn            func(database, name, field string, typ influxql.DataType) error
 	CreateShardFn             func(database, policy string, shardID uint64, enabled bool) error
 	CreateShardSnapshotFn     func(id uint64) (string, error)
+	DatabaseDiskSizeFn        func(database string) (int64, error)
+	DatabaseSeriesCountFn     func(database string) int64
 	DatabasesFn               func() []string
 	DeleteDatabaseFn          func(name string) error
+	DeleteFieldFn             func(database, name, field string) error
 	DeleteMeasurementFn       func(database, name string) error
 	DeleteRetentionPolicyFn   func(database, name string) error
 	DeleteSeriesFn            func(database string, sources []influxql.Source, condition influxql.Expr) error
@@ -60,6 +65,9 @@ func (s *TSDBStoreMock) ExportShard(id uint64, ExportStart time.Time, ExportEnd
 	return s.ExportShardFn(id, ExportStart, ExportEnd, w)
 }
 func (s *TSDBStoreMock) Close() error { return s.CloseFn() }
+func (s *TSDBStoreMock) ConvertField(database, name, field string, typ influxql.DataType) error {
+	return s.ConvertFieldFn(database, name, field, typ)
+}
 func (s *TSDBStoreMock) CreateShard(database string, retentionPolicy string, shardID uint64, enabled bool) error {
 	return s.CreateShardFn(database, retentionPolicy, shardID, enabled)
 }
@@ -76,6 +84,10 @@ func (s *TSDBStoreMock) DeleteDatabase(name string) error {
 	return s.DeleteDatabaseFn(name)
 }
 
+func (s *TSDBStoreMock) DeleteField(database, name, field string) error {
+	return s.DeleteFieldFn(database, name, field)
+}
+
 func (s *TSDBStoreMock) DeleteMeasurement(database string, name string) error {
 	return s.DeleteMeasurementFn(database, name)
 }
@@ -87,11 +99,22 @@ func (s *TSDBStoreMock) DeleteRetentionPolicy(database string, name string) erro
 func (s *TSDBStoreMock) DeleteSeries(database string, sources []influxql.Source, condition influxql.Expr) error {
 	return s.DeleteSeriesFn(database, sources, condition)
 }
+func (s *TSDBStoreMock) DeleteSeriesContext(ctx context.Context, database string, sources []influxql.Source, condition influxql.Expr) error {
+	return s.DeleteSeriesFn(database, sources, condition)
+}
 
 func (s *TSDBStoreMock) DeleteShard(shardID uint64) error {
 	return s.DeleteShardFn(shardID)
 }
 
+func (s *TSDBStoreMock) DatabaseDiskSize(database string) (int64, error) {
+	return s.DatabaseDiskSizeFn(database)
+}
+
+func (s *TSDBStoreMock) DatabaseSeriesCount(database string) int64 {
+	return s.DatabaseSeriesCountFn(database)
+}
+
 func (s *TSDBStoreMock) DiskSize() (int64, error) {
 	return s.DiskSizeFn()
 }
@@ -223,10 +223,11 @@ func (c *GroupCursor) Stats() cursors.CursorStats {
 }
 
 type StoreReader struct {
-	ReadFilterFunc func(ctx context.Context, req *datatypes.ReadFilterRequest) (reads.ResultSet, error)
-	ReadGroupFunc  func(ctx context.Context, req *datatypes.ReadGroupRequest) (reads.GroupResultSet, error)
-	TagKeysFunc    func(ctx context.Context, req *datatypes.TagKeysRequest) (cursors.StringIterator, error)
-	TagValuesFunc  func(ctx context.Context, req *datatypes.TagValuesRequest) (cursors.StringIterator, error)
+	ReadFilterFunc      func(ctx context.Context, req *datatypes.ReadFilterRequest) (reads.ResultSet, error)
+	ReadGroupFunc       func(ctx context.Context, req *datatypes.ReadGroupRequest) (reads.GroupResultSet, error)
+	WindowAggregateFunc func(ctx context.Context, req *datatypes.ReadWindowAggregateRequest) (reads.ResultSet, error)
+	TagKeysFunc         func(ctx context.Context, req *datatypes.TagKeysRequest) (cursors.StringIterator, error)
+	TagValuesFunc       func(ctx context.Context, req *datatypes.TagValuesRequest) (cursors.StringIterator, error)
 }
 
 func NewStoreReader() *StoreReader {
@@ -241,6 +242,10 @@ func (s *StoreReader) ReadGroup(ctx context.Context, req *datatypes.ReadGroupReq
 	return s.ReadGroupFunc(ctx, req)
 }
 
+func (s *StoreReader) WindowAggregate(ctx context.Context, req *datatypes.ReadWindowAggregateRequest) (reads.ResultSet, error) {
+	return s.WindowAggregateFunc(ctx, req)
+}
+
 func (s *StoreReader) TagKeys(ctx context.Context, req *datatypes.TagKeysRequest) (cursors.StringIterator, error) {
 	return s.TagKeysFunc(ctx, req)
 }
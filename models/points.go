@@ -334,16 +334,52 @@ func ParseName(buf []byte) []byte {
 // NOTE: to minimize heap allocations, the returned Points will refer to subslices of buf.
 // This can have the unintended effect preventing buf from being garbage collected.
 func ParsePointsWithPrecision(buf []byte, defaultTime time.Time, precision string) ([]Point, error) {
+	points, _, errs := ParsePointsWithLines(buf, defaultTime, precision)
+	if len(errs) > 0 {
+		failed := make([]string, len(errs))
+		for i, err := range errs {
+			failed[i] = err.Error()
+		}
+		return points, fmt.Errorf("%s", strings.Join(failed, "\n"))
+	}
+	return points, nil
+}
+
+// LineError is returned for a line of line protocol that could not be parsed.
+type LineError struct {
+	// Line is the number of the line in the buffer, starting at 1.
+	Line int
+
+	// Text is the line without any leading whitespace.
+	Text string
+
+	Err error
+}
+
+func (e *LineError) Error() string {
+	return fmt.Sprintf("unable to parse '%s': %v", e.Text, e.Err)
+}
+
+// ParsePointsWithLines is similar to ParsePointsWithPrecision, but also returns
+// the number of the line each point was parsed from and an error for each
+// line that could not be parsed. Blank lines and comments are skipped.
+func ParsePointsWithLines(buf []byte, defaultTime time.Time, precision string) ([]Point, []int, []*LineError) {
 	points := make([]Point, 0, bytes.Count(buf, []byte{'\n'})+1)
+	lines := make([]int, 0, cap(points))
 	var (
 		pos    int
+		line   = 1
 		block  []byte
-		failed []string
+		failed []*LineError
 	)
 	for pos < len(buf) {
 		pos, block = scanLine(buf, pos)
 		pos++
 
+		// A quoted field value may span several lines.
+		n := line
+		line += bytes.Count(block, []byte{'\n'}) + 1
+
 		if len(block) == 0 {
 			continue
 		}
@@ -367,16 +403,14 @@ func ParsePointsWithPrecision(buf []byte, defaultTime time.Time, precision strin
 
 		pt, err := parsePoint(block[start:], defaultTime, precision)
 		if err != nil {
-			failed = append(failed, fmt.Sprintf("unable to parse '%s': %v", string(block[start:]), err))
+			failed = append(failed, &LineError{Line: n, Text: string(block[start:]), Err: err})
 		} else {
 			points = append(points, pt)
+			lines = append(lines, n)
 		}
 
 	}
-	if len(failed) > 0 {
-		return points, fmt.Errorf("%s", strings.Join(failed, "\n"))
-	}
-	return points, nil
+	return points, lines, failed
 }
 
 func parsePoint(buf []byte, defaultTime time.Time, precision string) (Point, error) {
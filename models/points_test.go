@@ -1945,6 +1945,32 @@ cpu,host=serverA,region=us-east value=1.0 946730096789012345`,
 	}
 }
 
+func TestParsePointsWithLines(t *testing.T) {
+	batch := `# comment
+cpu value=1 1
+
+cpu value="multi
+line" 2
+cpu value= 3
+	mem value=4 4
+cpu,host value=5 5`
+
+	pts, lines, errs := models.ParsePointsWithLines([]byte(batch), time.Now().UTC(), "")
+	if len(pts) != 3 {
+		t.Fatalf("unexpected number of points: got %d, exp 3", len(pts))
+	} else if exp := []int{2, 4, 7}; !reflect.DeepEqual(lines, exp) {
+		t.Fatalf("unexpected lines: got %v, exp %v", lines, exp)
+	}
+
+	if len(errs) != 2 {
+		t.Fatalf("unexpected number of errors: got %d, exp 2", len(errs))
+	} else if errs[0].Line != 6 || errs[0].Text != "cpu value= 3" {
+		t.Fatalf("unexpected error: %+v", errs[0])
+	} else if errs[1].Line != 8 || errs[1].Text != "cpu,host value=5 5" {
+		t.Fatalf("unexpected error: %+v", errs[1])
+	}
+}
+
 func TestNewPointEscaped(t *testing.T) {
 	// commas
 	pt := models.MustNewPoint("cpu,main", models.NewTags(map[string]string{"tag,bar": "value"}), models.Fields{"name,bar": 1.0}, time.Unix(0, 0))
@@ -174,6 +174,9 @@ func (c *compiledStatement) compile(stmt *influxql.SelectStatement) error {
 	if err := c.compileFields(stmt); err != nil {
 		return err
 	}
+	if err := c.compileHaving(stmt); err != nil {
+		return err
+	}
 	if err := c.validateFields(); err != nil {
 		return err
 	}
@@ -188,6 +191,10 @@ func (c *compiledStatement) compile(stmt *influxql.SelectStatement) error {
 			if err := c.subquery(source.Statement); err != nil {
 				return err
 			}
+		case *influxql.Join:
+			if err := c.join(stmt, source); err != nil {
+				return err
+			}
 		}
 	}
 	return nil
@@ -261,6 +268,8 @@ func (c *compiledField) compileExpr(expr influxql.Expr) error {
 	case *influxql.Call:
 		if isMathFunction(expr) {
 			return c.compileMathFunction(expr)
+		} else if isStringFunction(expr) {
+			return c.compileStringFunction(expr)
 		}
 
 		// Register the function call in the list of function calls.
@@ -328,6 +337,34 @@ func (c *compiledField) compileExpr(expr influxql.Expr) error {
 			}
 			return nil
 		}
+	case *influxql.CaseExpr:
+		// Disallow wildcards in CASE expressions for the same reason as
+		// binary expressions.
+		c.AllowWildcard = false
+
+		exprs := make([]influxql.Expr, 0, 2*len(expr.WhenClauses)+1)
+		for _, w := range expr.WhenClauses {
+			exprs = append(exprs, w.Condition, w.Result)
+		}
+		if expr.Else != nil {
+			exprs = append(exprs, expr.Else)
+		}
+
+		// Compile all of the expressions that are not just literals.
+		literalsOnly := true
+		for _, e := range exprs {
+			if _, ok := e.(influxql.Literal); ok {
+				continue
+			}
+			literalsOnly = false
+			if err := c.compileExpr(e); err != nil {
+				return err
+			}
+		}
+		if literalsOnly {
+			return errors.New("field must contain at least one variable")
+		}
+		return nil
 	case *influxql.ParenExpr:
 		return c.compileExpr(expr.Expr)
 	case influxql.Literal:
@@ -353,8 +390,9 @@ func (c *compiledField) compileNestedExpr(expr influxql.Expr) error {
 }
 
 func (c *compiledField) compileSymbol(name string, field influxql.Expr) error {
-	// Must be a variable reference, wildcard, or regexp.
-	switch field.(type) {
+	// Must be a variable reference, wildcard, regexp, or an expression
+	// that is evaluated for each point.
+	switch expr := field.(type) {
 	case *influxql.VarRef:
 		return nil
 	case *influxql.Wildcard:
@@ -369,11 +407,84 @@ func (c *compiledField) compileSymbol(name string, field influxql.Expr) error {
 		}
 		c.global.OnlySelectors = false
 		return nil
+	case *influxql.CaseExpr:
+		return c.compileRowExpr(name, field)
+	case *influxql.Call:
+		if isStringFunction(expr) {
+			return c.compileRowExpr(name, expr)
+		}
+		return fmt.Errorf("expected field argument in %s()", name)
 	default:
 		return fmt.Errorf("expected field argument in %s()", name)
 	}
 }
 
+// compileRowExpr validates an expression that is evaluated for every point
+// before it is passed to the function. It may only contain variables,
+// literals, and math or string functions.
+func (c *compiledField) compileRowExpr(name string, expr influxql.Expr) error {
+	var validate func(expr influxql.Expr) error
+	validate = func(expr influxql.Expr) error {
+		switch expr := expr.(type) {
+		case *influxql.VarRef:
+			return nil
+		case *influxql.Call:
+			var err error
+			if isMathFunction(expr) {
+				err = validateMathFunction(expr)
+			} else if isStringFunction(expr) {
+				err = validateStringFunction(expr)
+			} else {
+				return fmt.Errorf("expected field argument in %s()", name)
+			}
+			if err != nil {
+				return err
+			}
+			for _, arg := range expr.Args {
+				if err := validate(arg); err != nil {
+					return err
+				}
+			}
+			return nil
+		case *influxql.CaseExpr:
+			for _, w := range expr.WhenClauses {
+				if err := validate(w.Condition); err != nil {
+					return err
+				}
+				if err := validate(w.Result); err != nil {
+					return err
+				}
+			}
+			if expr.Else != nil {
+				return validate(expr.Else)
+			}
+			return nil
+		case *influxql.BinaryExpr:
+			if err := validate(expr.LHS); err != nil {
+				return err
+			}
+			return validate(expr.RHS)
+		case *influxql.ParenExpr:
+			return validate(expr.Expr)
+		case *influxql.Wildcard, *influxql.Distinct:
+			return fmt.Errorf("expected field argument in %s()", name)
+		case influxql.Literal:
+			return nil
+		default:
+			return fmt.Errorf("expected field argument in %s()", name)
+		}
+	}
+	if err := validate(expr); err != nil {
+		return err
+	}
+
+	// The expression must reference at least one variable.
+	if len(influxql.ExprNames(expr)) == 0 {
+		return fmt.Errorf("expected field argument in %s()", name)
+	}
+	return nil
+}
+
 func (c *compiledField) compileFunction(expr *influxql.Call) error {
 	// Validate the function call and mark down some meta properties
 	// related to the function for query validation.
@@ -801,6 +912,8 @@ func (c *compiledField) compileHoltWinters(args []influxql.Expr, withFit bool) e
 		return fmt.Errorf("must use aggregate function with %s", name)
 	} else if c.global.Interval.IsZero() {
 		return fmt.Errorf("%s aggregate requires a GROUP BY interval", name)
+	} else if c.global.Interval.Months != 0 {
+		return fmt.Errorf("%s does not support calendar GROUP BY intervals", name)
 	}
 	return c.compileNestedExpr(call)
 }
@@ -869,6 +982,41 @@ func (c *compiledField) compileTopBottom(call *influxql.Call) error {
 }
 
 func (c *compiledField) compileMathFunction(expr *influxql.Call) error {
+	if err := validateMathFunction(expr); err != nil {
+		return err
+	}
+
+	// Compile all the argument expressions that are not just literals.
+	for _, arg := range expr.Args {
+		if _, ok := arg.(influxql.Literal); ok {
+			continue
+		}
+		if err := c.compileExpr(arg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *compiledField) compileStringFunction(expr *influxql.Call) error {
+	if err := validateStringFunction(expr); err != nil {
+		return err
+	}
+
+	// Compile all the argument expressions that are not just literals.
+	for _, arg := range expr.Args {
+		if _, ok := arg.(influxql.Literal); ok {
+			continue
+		}
+		if err := c.compileExpr(arg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateMathFunction verifies the number of arguments of a math function.
+func validateMathFunction(expr *influxql.Call) error {
 	// How many arguments are we expecting?
 	nargs := 1
 	switch expr.Name {
@@ -880,14 +1028,41 @@ func (c *compiledField) compileMathFunction(expr *influxql.Call) error {
 	if got := len(expr.Args); got != nargs {
 		return fmt.Errorf("invalid number of arguments for %s, expected %d, got %d", expr.Name, nargs, got)
 	}
+	return nil
+}
 
-	// Compile all the argument expressions that are not just literals.
-	for _, arg := range expr.Args {
-		if _, ok := arg.(influxql.Literal); ok {
-			continue
+// validateStringFunction verifies the number of arguments of a string
+// function and the arguments that must be literals.
+func validateStringFunction(expr *influxql.Call) error {
+	// How many arguments are we expecting?
+	min, max := 1, 1
+	switch expr.Name {
+	case "concat":
+		min, max = 2, -1
+	case "substring", "regexp_extract":
+		min, max = 2, 3
+	case "regexp_replace":
+		min, max = 3, 3
+	}
+
+	// Did we get the expected number of args?
+	if got := len(expr.Args); max == -1 && got < min {
+		return fmt.Errorf("invalid number of arguments for %s, expected at least %d, got %d", expr.Name, min, got)
+	} else if min == max && got != min {
+		return fmt.Errorf("invalid number of arguments for %s, expected %d, got %d", expr.Name, min, got)
+	} else if max != -1 && (got < min || got > max) {
+		return fmt.Errorf("invalid number of arguments for %s, expected at least %d but no more than %d, got %d", expr.Name, min, max, got)
+	}
+
+	switch expr.Name {
+	case "regexp_extract", "regexp_replace":
+		if _, ok := expr.Args[1].(*influxql.RegexLiteral); !ok {
+			return fmt.Errorf("expected regex argument in %s()", expr.Name)
 		}
-		if err := c.compileExpr(arg); err != nil {
-			return err
+		if expr.Name == "regexp_extract" && len(expr.Args) == 3 {
+			if _, ok := expr.Args[2].(*influxql.IntegerLiteral); !ok {
+				return fmt.Errorf("expected integer argument in %s()", expr.Name)
+			}
 		}
 	}
 	return nil
@@ -912,24 +1087,31 @@ func (c *compiledStatement) compileDimensions(stmt *influxql.SelectStatement) er
 				return errors.New("time dimension expected 1 or 2 arguments")
 			} else if lit, ok := expr.Args[0].(*influxql.DurationLiteral); !ok {
 				return errors.New("time dimension must have duration argument")
-			} else if c.Interval.Duration != 0 {
+			} else if !c.Interval.IsZero() {
 				return errors.New("multiple time dimensions not allowed")
+			} else if lit.Months != 0 && lit.Val != 0 {
+				return errors.New("time dimension cannot mix calendar and fixed units")
 			} else {
 				c.Interval.Duration = lit.Val
+				c.Interval.Months = lit.Months
+				c.Interval.CalendarWeeks = lit.CalendarWeeks
 				if len(expr.Args) == 2 {
 					switch lit := expr.Args[1].(type) {
 					case *influxql.DurationLiteral:
-						c.Interval.Offset = lit.Val % c.Interval.Duration
+						if c.Interval.Months != 0 {
+							c.Interval.Offset = lit.Val
+						} else {
+							c.Interval.Offset = lit.Val % c.Interval.Duration
+						}
 					case *influxql.TimeLiteral:
-						c.Interval.Offset = lit.Val.Sub(lit.Val.Truncate(c.Interval.Duration))
+						c.Interval.Offset = c.intervalOffset(lit.Val)
 					case *influxql.Call:
 						if lit.Name != "now" {
 							return errors.New("time dimension offset function must be now()")
 						} else if len(lit.Args) != 0 {
 							return errors.New("time dimension offset now() function requires no arguments")
 						}
-						now := c.Options.Now
-						c.Interval.Offset = now.Sub(now.Truncate(c.Interval.Duration))
+						c.Interval.Offset = c.intervalOffset(c.Options.Now)
 
 						// Use the evaluated offset to replace the argument. Ideally, we would
 						// use the interval assigned above, but the query engine hasn't been changed
@@ -942,7 +1124,7 @@ func (c *compiledStatement) compileDimensions(stmt *influxql.SelectStatement) er
 							if err != nil {
 								return err
 							}
-							c.Interval.Offset = t.Val.Sub(t.Val.Truncate(c.Interval.Duration))
+							c.Interval.Offset = c.intervalOffset(t.Val)
 						} else {
 							return errors.New("time dimension offset must be duration or now()")
 						}
@@ -999,6 +1181,86 @@ func (c *compiledStatement) validateFields() error {
 	return nil
 }
 
+// compileHaving validates the HAVING clause of the statement. Aggregates in
+// the clause that are not selected are compiled as if they were fields
+// because they are evaluated alongside the selected fields.
+func (c *compiledStatement) compileHaving(stmt *influxql.SelectStatement) error {
+	if stmt.Having == nil {
+		return nil
+	} else if stmt.IsRawQuery {
+		return errors.New("HAVING requires an aggregate function")
+	}
+
+	// Names in the clause outside of an aggregate refer to the columns of
+	// the result or the tags of the dimensions. These cannot be known ahead
+	// of time if either of them uses a wildcard.
+	names := make(map[string]struct{})
+	for _, name := range stmt.ColumnNames() {
+		names[name] = struct{}{}
+	}
+	known := true
+	for _, f := range stmt.Fields {
+		influxql.WalkFunc(f.Expr, func(n influxql.Node) {
+			switch n.(type) {
+			case *influxql.Wildcard, *influxql.RegexLiteral:
+				known = false
+			}
+		})
+	}
+	for _, d := range stmt.Dimensions {
+		switch expr := d.Expr.(type) {
+		case *influxql.VarRef:
+			names[expr.Val] = struct{}{}
+		case *influxql.Wildcard, *influxql.RegexLiteral:
+			known = false
+		}
+	}
+
+	var compile func(expr influxql.Expr) error
+	compile = func(expr influxql.Expr) error {
+		switch expr := expr.(type) {
+		case *influxql.BinaryExpr:
+			if err := compile(expr.LHS); err != nil {
+				return err
+			}
+			return compile(expr.RHS)
+		case *influxql.ParenExpr:
+			return compile(expr.Expr)
+		case *influxql.VarRef:
+			if _, ok := names[expr.Val]; !ok && known {
+				return fmt.Errorf("HAVING references unknown column or tag: %s", expr.Val)
+			}
+			return nil
+		case *influxql.Call:
+			if isMathFunction(expr) || isStringFunction(expr) {
+				for _, arg := range expr.Args {
+					if err := compile(arg); err != nil {
+						return err
+					}
+				}
+				return nil
+			}
+
+			// Selected aggregates have already been compiled.
+			for _, f := range stmt.Fields {
+				if f.Expr.String() == expr.String() {
+					return nil
+				}
+			}
+			field := &compiledField{
+				global: c,
+				Field:  &influxql.Field{Expr: expr},
+			}
+			return field.compileExpr(expr)
+		case *influxql.Wildcard, *influxql.RegexLiteral, *influxql.Distinct:
+			return fmt.Errorf("invalid expression in HAVING: %s", expr)
+		default:
+			return nil
+		}
+	}
+	return compile(stmt.Having)
+}
+
 // validateCondition verifies that all elements in the condition are appropriate.
 // For example, aggregate calls don't work in the condition and should throw an
 // error as an invalid expression.
@@ -1087,6 +1349,131 @@ func (c *compiledStatement) subquery(stmt *influxql.SelectStatement) error {
 	return subquery.compile(stmt)
 }
 
+// join rewrites each side of a join into a subquery that selects the fields
+// referenced by the parent statement grouped by the tags of the join
+// condition. The subqueries are then compiled using this compiledStatement
+// as the parent.
+func (c *compiledStatement) join(stmt *influxql.SelectStatement, j *influxql.Join) error {
+	left, right, err := j.Keys()
+	if err != nil {
+		return err
+	}
+
+	// The joined rows only have the tags of the join condition.
+	for _, d := range stmt.Dimensions {
+		ref, ok := d.Expr.(*influxql.VarRef)
+		if !ok {
+			continue
+		} else if !containsString(left, ref.Val) {
+			return fmt.Errorf("cannot group a join by %s: only the tags of the join condition are allowed", ref.Val)
+		}
+	}
+
+	// Find the fields of each side that are referenced by the statement.
+	refs := map[influxql.Source][]string{}
+	var wildcard bool
+	var walkErr error
+	influxql.WalkFunc(stmt.Fields, func(n influxql.Node) {
+		if _, ok := n.(*influxql.Wildcard); ok {
+			wildcard = true
+		}
+	})
+	for _, expr := range []influxql.Node{stmt.Fields, stmt.Condition} {
+		influxql.WalkFunc(expr, func(n influxql.Node) {
+			ref, ok := n.(*influxql.VarRef)
+			if !ok || walkErr != nil || ref.Val == "time" || containsString(left, ref.Val) {
+				return
+			}
+			side, name := j.SplitRef(ref.Val)
+			if side == nil {
+				walkErr = fmt.Errorf("unknown reference in join: %s", ref.Val)
+				return
+			}
+			if !containsString(refs[side], name) {
+				refs[side] = append(refs[side], name)
+			}
+		})
+	}
+	if walkErr != nil {
+		return walkErr
+	}
+
+	for _, side := range []struct {
+		src  *influxql.Source
+		keys []string
+	}{
+		{src: &j.Left, keys: left},
+		{src: &j.Right, keys: right},
+	} {
+		var sub *influxql.SelectStatement
+		switch src := (*side.src).(type) {
+		case *influxql.Measurement:
+			sub = &influxql.SelectStatement{
+				Sources:  influxql.Sources{src},
+				Location: stmt.Location,
+			}
+			if wildcard || len(refs[src]) == 0 {
+				sub.Fields = influxql.Fields{{Expr: &influxql.Wildcard{}}}
+			} else {
+				for _, name := range refs[src] {
+					sub.Fields = append(sub.Fields, &influxql.Field{Expr: &influxql.VarRef{Val: name}})
+				}
+			}
+			sub.IsRawQuery = true
+			*side.src = &influxql.SubQuery{Statement: sub}
+		case *influxql.SubQuery:
+			sub = src.Statement
+			for _, d := range sub.Dimensions {
+				if ref, ok := d.Expr.(*influxql.VarRef); ok && !containsString(side.keys, ref.Val) {
+					return fmt.Errorf("cannot group a joined subquery by %s: only the tags of the join condition are allowed", ref.Val)
+				}
+			}
+		default:
+			return fmt.Errorf("invalid join source: %s", src)
+		}
+
+		// Group each side by the tags of the join condition so the rows
+		// can be matched by their series.
+		var dims []string
+		for _, d := range sub.Dimensions {
+			if ref, ok := d.Expr.(*influxql.VarRef); ok {
+				dims = append(dims, ref.Val)
+			}
+		}
+		for _, k := range side.keys {
+			if !containsString(dims, k) {
+				sub.Dimensions = append(sub.Dimensions, &influxql.Dimension{Expr: &influxql.VarRef{Val: k}})
+			}
+		}
+
+		sub.OmitTime = true
+		if err := c.subquery(sub); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func containsString(a []string, s string) bool {
+	for _, v := range a {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// intervalOffset returns the offset of t from the start of the interval
+// that contains it.
+func (c *compiledStatement) intervalOffset(t time.Time) time.Duration {
+	if c.Interval.Months != 0 {
+		return t.Sub(influxql.TruncateMonths(t.UTC(), c.Interval.Months))
+	} else if c.Interval.CalendarWeeks {
+		return t.Sub(influxql.TruncateWeeks(t.UTC(), int(c.Interval.Duration/(7*24*time.Hour))))
+	}
+	return t.Sub(t.Truncate(c.Interval.Duration))
+}
+
 func (c *compiledStatement) Prepare(shardMapper ShardMapper, sopt SelectOptions) (PreparedStatement, error) {
 	// If this is a query with a grouping, there is a bucket limit, and the minimum time has not been specified,
 	// we need to limit the possible time range that can be used when mapping shards but not when actually executing
@@ -1098,12 +1485,28 @@ func (c *compiledStatement) Prepare(shardMapper ShardMapper, sopt SelectOptions)
 			return nil, err
 		}
 
+		months, err := c.stmt.GroupByMonths()
+		if err != nil {
+			return nil, err
+		}
+
 		offset, err := c.stmt.GroupByOffset()
 		if err != nil {
 			return nil, err
 		}
 
-		if interval > 0 {
+		if months > 0 {
+			// Calendar intervals are counted back from the last bucket.
+			opt := IteratorOptions{
+				Interval: Interval{
+					Months: months,
+					Offset: offset,
+				},
+				Location: c.stmt.Location,
+			}
+			last, _ := opt.Window(c.TimeRange.MaxTimeNano() - 1)
+			timeRange.Min = time.Unix(0, opt.Interval.AddTo(last, -(sopt.MaxBucketsN-1), opt.Location))
+		} else if interval > 0 {
 			// Determine the last bucket using the end time.
 			opt := IteratorOptions{
 				Interval: Interval{
@@ -1111,6 +1514,11 @@ func (c *compiledStatement) Prepare(shardMapper ShardMapper, sopt SelectOptions)
 					Offset:   offset,
 				},
 			}
+			if c.stmt.GroupByCalendarWeeks() {
+				// Calendar weeks start on a Monday in the location of the query.
+				opt.Interval.CalendarWeeks = true
+				opt.Location = c.stmt.Location
+			}
 			last, _ := opt.Window(c.TimeRange.MaxTimeNano() - 1)
 
 			// Determine the time difference using the number of buckets.
@@ -1125,7 +1533,14 @@ func (c *compiledStatement) Prepare(shardMapper ShardMapper, sopt SelectOptions)
 	}
 
 	// Modify the time range if there are extra intervals and an interval.
-	if !c.Interval.IsZero() && c.ExtraIntervals > 0 {
+	if c.Interval.Months != 0 && c.ExtraIntervals > 0 {
+		// AddTo clamps calendar intervals to the valid time range.
+		if c.Ascending {
+			timeRange.Min = time.Unix(0, c.Interval.AddTo(timeRange.Min.UnixNano(), -c.ExtraIntervals, c.stmt.Location)).UTC()
+		} else {
+			timeRange.Max = time.Unix(0, c.Interval.AddTo(timeRange.Max.UnixNano(), c.ExtraIntervals, c.stmt.Location)).UTC()
+		}
+	} else if !c.Interval.IsZero() && c.ExtraIntervals > 0 {
 		if c.Ascending {
 			newTime := timeRange.Min.Add(time.Duration(-c.ExtraIntervals) * c.Interval.Duration)
 			if !newTime.Before(time.Unix(0, influxql.MinTime).UTC()) {
@@ -1190,6 +1605,16 @@ func (c *compiledStatement) Prepare(shardMapper ShardMapper, sopt SelectOptions)
 				shards.Close()
 				return nil, fmt.Errorf("max-select-buckets limit exceeded: (%d/%d)", buckets, sopt.MaxBucketsN)
 			}
+		} else if opt.Interval.Months > 0 {
+			first, _ := opt.Window(opt.StartTime)
+			last, _ := opt.Window(opt.EndTime - 1)
+
+			// Calendar windows are counted by the months between them.
+			buckets := monthsBetween(first, last, opt.Location)/opt.Interval.Months + 1
+			if buckets > sopt.MaxBucketsN {
+				shards.Close()
+				return nil, fmt.Errorf("max-select-buckets limit exceeded: (%d/%d)", buckets, sopt.MaxBucketsN)
+			}
 		}
 	}
 
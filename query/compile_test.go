@@ -110,6 +110,11 @@ func TestCompile_Success(t *testing.T) {
 		`SELECT sin(value) - sin(1.3) FROM cpu`,
 		`SELECT value FROM cpu WHERE sin(value) > 0.5`,
 		`SELECT sum("out")/sum("in") FROM (SELECT derivative("out") AS "out", derivative("in") AS "in" FROM "m0" WHERE time >= now() - 5m GROUP BY "index") GROUP BY time(1m) fill(none)`,
+		`SELECT mean(value) FROM cpu GROUP BY host HAVING mean > 90`,
+		`SELECT mean(value) AS m FROM cpu GROUP BY host HAVING m > 90 AND host = 'server01'`,
+		`SELECT mean(value) FROM cpu GROUP BY time(10m) HAVING max(value) - min(value) > 10`,
+		`SELECT mean(value) FROM cpu GROUP BY time(10m) HAVING abs(mean) > 10`,
+		`SELECT mean(*) FROM cpu HAVING mean_value > 90`,
 	} {
 		t.Run(tt, func(t *testing.T) {
 			stmt, err := influxql.ParseStatement(tt)
@@ -225,6 +230,10 @@ func TestCompile_Failures(t *testing.T) {
 		{s: `SELECT percentile(max(field1), 75) FROM myseries`, err: `expected field argument in percentile()`},
 		{s: `SELECT field1 FROM foo group by time(1s)`, err: `GROUP BY requires at least one aggregate function`},
 		{s: `SELECT field1 FROM foo fill(none)`, err: `fill(none) must be used with a function`},
+		{s: `SELECT field1 FROM foo HAVING field1 > 1`, err: `HAVING requires an aggregate function`},
+		{s: `SELECT mean(value) FROM foo HAVING max > 1`, err: `HAVING references unknown column or tag: max`},
+		{s: `SELECT max(value), host FROM foo HAVING mean(value) > 1`, err: `mixing aggregate and non-aggregate queries is not supported`},
+		{s: `SELECT top(value, 2) FROM foo HAVING mean(value) > 1`, err: `selector function top() cannot be combined with other functions`},
 		{s: `SELECT field1 FROM foo fill(linear)`, err: `fill(linear) must be used with a function`},
 		{s: `SELECT count(value), value FROM foo`, err: `mixing aggregate and non-aggregate queries is not supported`},
 		{s: `SELECT count(value) FROM foo group by time`, err: `time() is a function and expects at least one argument`},
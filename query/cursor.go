@@ -173,6 +173,7 @@ func newScannerCursorBase(scan scannerFunc, fields []*influxql.Field, loc *time.
 		valuer: influxql.ValuerEval{
 			Valuer: influxql.MultiValuer(
 				MathValuer{},
+				StringValuer{},
 				influxql.MapValuer(m),
 			),
 			IntegerFloatDivision: true,
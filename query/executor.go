@@ -63,12 +63,24 @@ func ErrMaxSelectPointsLimitExceeded(n, limit int) error {
 	return fmt.Errorf("max-select-point limit exceeed: (%d/%d)", n, limit)
 }
 
+// ErrMaxJoinPointsLimitExceeded is an error when a join holds more than the
+// maximum number of rows in memory.
+func ErrMaxJoinPointsLimitExceeded(n, limit int) error {
+	return fmt.Errorf("max-join-point limit exceeded: (%d/%d)", n, limit)
+}
+
 // ErrMaxConcurrentQueriesLimitExceeded is an error when a query cannot be run
 // because the maximum number of queries has been reached.
 func ErrMaxConcurrentQueriesLimitExceeded(n, limit int) error {
 	return fmt.Errorf("max-concurrent-queries limit exceeded(%d, %d)", n, limit)
 }
 
+// ErrQueryQuotaExceeded is an error when a query cannot be run because the
+// maximum number of concurrent queries against its database has been reached.
+func ErrQueryQuotaExceeded(database string, limit int) error {
+	return fmt.Errorf("database %q exceeded its concurrent queries quota: limit %d", database, limit)
+}
+
 // Authorizer determines if certain operations are authorized.
 type Authorizer interface {
 	// AuthorizeDatabase indicates whether the given Privilege is authorized on the database with the given name.
@@ -139,6 +151,9 @@ type ExecutionOptions struct {
 	// Node to execute on.
 	NodeID uint64
 
+	// The address of the client that sent the query.
+	ClientAddr string
+
 	// Quiet suppresses non-essential output from the query executor.
 	Quiet bool
 
@@ -426,6 +441,7 @@ func (e *Executor) recover(query *influxql.Query, results chan *Result) {
 type Task struct {
 	query     string
 	database  string
+	databases []string // databases read by the query
 	status    TaskStatus
 	startTime time.Time
 	closing   chan struct{}
@@ -405,6 +405,74 @@ func TestQueryExecutor_Limit_ConcurrentQueries(t *testing.T) {
 	}
 }
 
+func TestQueryExecutor_Limit_DatabaseConcurrentQueries(t *testing.T) {
+	q, err := influxql.ParseQuery(`SELECT count(value) FROM cpu`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	qid := make(chan uint64)
+
+	e := NewQueryExecutor()
+	e.StatementExecutor = &StatementExecutor{
+		ExecuteStatementFn: func(stmt influxql.Statement, ctx *query.ExecutionContext) error {
+			qid <- ctx.QueryID
+			<-ctx.Done()
+			return ctx.Err()
+		},
+	}
+	e.TaskManager.DatabaseMaxConcurrentQueries = func(database string) int {
+		if database == "db0" {
+			return 1
+		}
+		return 0
+	}
+	defer e.Close()
+
+	// Start first query and wait for it to be executing.
+	go discardOutput(e.ExecuteQuery(q, query.ExecutionOptions{Database: "db0"}, nil))
+	<-qid
+
+	if got, exp := e.TaskManager.DatabaseQueries("db0"), 1; got != exp {
+		t.Errorf("unexpected queries: got %d, exp %d", got, exp)
+	}
+
+	// Queries against other databases are not limited.
+	go discardOutput(e.ExecuteQuery(q, query.ExecutionOptions{Database: "db1"}, nil))
+	<-qid
+
+	// Start a query that reads the same database and expect it to fail.
+	other, err := influxql.ParseQuery(`SELECT count(value) FROM db0.rp0.cpu`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	results := e.ExecuteQuery(other, query.ExecutionOptions{Database: "db1"}, nil)
+
+	select {
+	case result := <-results:
+		if result.Err == nil || result.Err.Error() != `database "db0" exceeded its concurrent queries quota: limit 1` {
+			t.Errorf("unexpected error: %s", result.Err)
+		}
+	case <-qid:
+		t.Errorf("unexpected statement execution for the third query")
+	}
+
+	// Statements that do not select from the database are not limited.
+	for _, s := range []string{`SET QUOTA ON db0 QUERIES 2`, `SHOW MEASUREMENTS`, `SELECT count(value) FROM db1.rp0.cpu`} {
+		admin, err := influxql.ParseQuery(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		results := e.ExecuteQuery(admin, query.ExecutionOptions{Database: "db0"}, nil)
+
+		select {
+		case result := <-results:
+			t.Errorf("%s: unexpected error: %s", s, result.Err)
+		case <-qid:
+		}
+	}
+}
+
 func TestQueryExecutor_Close(t *testing.T) {
 	q, err := influxql.ParseQuery(`SELECT count(value) FROM cpu`)
 	if err != nil {
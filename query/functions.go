@@ -88,6 +88,8 @@ func (m FunctionTypeMapper) CallType(name string, args []influxql.DataType) (inf
 		return influxql.Float, nil
 	case "elapsed":
 		return influxql.Integer, nil
+	case "lower", "upper", "concat", "substring", "strlen", "regexp_extract", "regexp_replace":
+		return StringTypeMapper{}.CallType(name, args)
 	default:
 		// TODO(jsternberg): Do not use default for this.
 		return args[0], nil
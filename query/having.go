@@ -0,0 +1,185 @@
+package query
+
+import (
+	"context"
+
+	"github.com/ayang64/reflux/influxql"
+)
+
+// buildHavingCursor builds a cursor for a statement with a HAVING clause.
+// The aggregated rows are filtered by the clause before the row and series
+// limits of the statement are applied.
+func buildHavingCursor(ctx context.Context, stmt *influxql.SelectStatement, ic IteratorCreator, opt IteratorOptions) (Cursor, error) {
+	stmt = stmt.Clone()
+	having := stmt.Having
+	stmt.Having = nil
+
+	// Aggregates in the clause that are not selected are appended to the
+	// fields so they are computed with the others and removed afterwards.
+	columnN := len(stmt.ColumnNames())
+	having = influxql.RewriteExpr(having, func(expr influxql.Expr) influxql.Expr {
+		call, ok := expr.(*influxql.Call)
+		if !ok || isMathFunction(call) || isStringFunction(call) {
+			return expr
+		}
+
+		columns := fieldColumnNames(stmt)
+		for i, f := range stmt.Fields {
+			if f.Expr.String() == call.String() {
+				return &influxql.VarRef{Val: columns[i]}
+			}
+		}
+		stmt.Fields = append(stmt.Fields, &influxql.Field{Expr: call})
+		columns = fieldColumnNames(stmt)
+		return &influxql.VarRef{Val: columns[len(columns)-1]}
+	})
+
+	// The limits apply to the rows that pass the filter.
+	limit, offset := opt.Limit, opt.Offset
+	slimit, soffset := opt.SLimit, opt.SOffset
+	opt.Limit, opt.Offset = 0, 0
+	opt.SLimit, opt.SOffset = 0, 0
+
+	cur, err := buildCursor(ctx, stmt, ic, opt)
+	if err != nil {
+		return nil, err
+	}
+	return newHavingCursor(cur, having, columnN, limit, offset, slimit, soffset), nil
+}
+
+// fieldColumnNames returns the name of the column for each of the fields
+// of the statement.
+func fieldColumnNames(stmt *influxql.SelectStatement) []string {
+	columns := stmt.ColumnNames()
+	if !stmt.OmitTime {
+		columns = columns[1:]
+	}
+
+	names := make([]string, len(stmt.Fields))
+	i := 0
+	for j, f := range stmt.Fields {
+		names[j] = columns[i]
+		i++
+
+		// The top() and bottom() calls add a column for each of their
+		// tag or field arguments.
+		if call, ok := f.Expr.(*influxql.Call); ok && stmt.Target == nil && (call.Name == "top" || call.Name == "bottom") {
+			for _, arg := range call.Args[1:] {
+				if _, ok := arg.(*influxql.VarRef); ok {
+					i++
+				}
+			}
+		}
+	}
+	return names
+}
+
+// havingCursor filters the rows of a cursor and limits the number of rows
+// and series that are returned after filtering.
+type havingCursor struct {
+	cur     Cursor
+	columns []influxql.VarRef
+	row     Row
+
+	filter influxql.Expr
+	fields map[string]IteratorMap
+	m      map[string]interface{}
+	valuer influxql.ValuerEval
+
+	limit, offset   int
+	slimit, soffset int
+
+	series  Series
+	seriesN int
+	rowN    int
+}
+
+func newHavingCursor(cur Cursor, filter influxql.Expr, columnN, limit, offset, slimit, soffset int) *havingCursor {
+	// Names that are not columns are tags of the series.
+	fields := make(map[string]IteratorMap)
+	for _, name := range influxql.ExprNames(filter) {
+		fields[name.Val] = TagMap(name.Val)
+		for i, col := range cur.Columns() {
+			if name.Val == col.Val {
+				fields[name.Val] = FieldMap{Index: i, Type: col.Type}
+				break
+			}
+		}
+	}
+
+	m := make(map[string]interface{})
+	return &havingCursor{
+		cur:     cur,
+		columns: cur.Columns()[:columnN],
+		filter:  filter,
+		fields:  fields,
+		m:       m,
+		valuer: influxql.ValuerEval{
+			Valuer: influxql.MultiValuer(
+				MathValuer{},
+				StringValuer{},
+				influxql.MapValuer(m),
+			),
+			IntegerFloatDivision: true,
+		},
+		limit:   limit,
+		offset:  offset,
+		slimit:  slimit,
+		soffset: soffset,
+	}
+}
+
+func (cur *havingCursor) Scan(row *Row) bool {
+	for cur.cur.Scan(&cur.row) {
+		for name, f := range cur.fields {
+			cur.m[name] = f.Value(&cur.row)
+		}
+		if !cur.valuer.EvalBool(cur.filter) {
+			continue
+		}
+
+		// Only the series with a row that passes the filter are counted.
+		if cur.seriesN == 0 || !cur.row.Series.SameSeries(cur.series) {
+			cur.series = cur.row.Series
+			cur.seriesN++
+			cur.rowN = 0
+		}
+		if cur.seriesN <= cur.soffset {
+			continue
+		} else if cur.slimit > 0 && cur.seriesN > cur.soffset+cur.slimit {
+			return false
+		}
+
+		cur.rowN++
+		if cur.rowN <= cur.offset {
+			continue
+		} else if cur.limit > 0 && cur.rowN > cur.offset+cur.limit {
+			continue
+		}
+
+		row.Time = cur.row.Time
+		row.Series = cur.row.Series
+		if len(row.Values) != len(cur.columns) {
+			row.Values = make([]interface{}, len(cur.columns))
+		}
+		copy(row.Values, cur.row.Values)
+		return true
+	}
+	return false
+}
+
+func (cur *havingCursor) Stats() IteratorStats {
+	return cur.cur.Stats()
+}
+
+func (cur *havingCursor) Err() error {
+	return cur.cur.Err()
+}
+
+func (cur *havingCursor) Columns() []influxql.VarRef {
+	return cur.columns
+}
+
+func (cur *havingCursor) Close() error {
+	return cur.cur.Close()
+}
@@ -882,6 +882,10 @@ CONSTRUCT:
 					return nil, err
 				} else if next != nil && next.Name == itr.window.name && next.Tags.ID() == itr.window.tags.ID() {
 					interval := int64(itr.opt.Interval.Duration)
+					if interval == 0 {
+						// Calendar windows are interpolated by time.
+						interval = 1
+					}
 					start := itr.window.time / interval
 					p.Value = linearFloat(start, itr.prev.Time/interval, next.Time/interval, itr.prev.Value, next.Value)
 				} else {
@@ -910,7 +914,16 @@ CONSTRUCT:
 	// Advance the expected time. Do not advance to a new window here
 	// as there may be lingering points with the same timestamp in the previous
 	// window.
-	if itr.opt.Ascending {
+	if itr.opt.Interval.Months != 0 {
+		// Calendar windows are not all the same length and already
+		// account for offset changes in the location.
+		if itr.opt.Ascending {
+			_, itr.window.time = itr.opt.Window(itr.window.time)
+		} else {
+			itr.window.time, _ = itr.opt.Window(itr.window.time - 1)
+		}
+		return p, nil
+	} else if itr.opt.Ascending {
 		itr.window.time += int64(itr.opt.Interval.Duration)
 	} else {
 		itr.window.time -= int64(itr.opt.Interval.Duration)
@@ -3595,6 +3608,10 @@ CONSTRUCT:
 					return nil, err
 				} else if next != nil && next.Name == itr.window.name && next.Tags.ID() == itr.window.tags.ID() {
 					interval := int64(itr.opt.Interval.Duration)
+					if interval == 0 {
+						// Calendar windows are interpolated by time.
+						interval = 1
+					}
 					start := itr.window.time / interval
 					p.Value = linearInteger(start, itr.prev.Time/interval, next.Time/interval, itr.prev.Value, next.Value)
 				} else {
@@ -3623,7 +3640,16 @@ CONSTRUCT:
 	// Advance the expected time. Do not advance to a new window here
 	// as there may be lingering points with the same timestamp in the previous
 	// window.
-	if itr.opt.Ascending {
+	if itr.opt.Interval.Months != 0 {
+		// Calendar windows are not all the same length and already
+		// account for offset changes in the location.
+		if itr.opt.Ascending {
+			_, itr.window.time = itr.opt.Window(itr.window.time)
+		} else {
+			itr.window.time, _ = itr.opt.Window(itr.window.time - 1)
+		}
+		return p, nil
+	} else if itr.opt.Ascending {
 		itr.window.time += int64(itr.opt.Interval.Duration)
 	} else {
 		itr.window.time -= int64(itr.opt.Interval.Duration)
@@ -6308,6 +6334,10 @@ CONSTRUCT:
 					return nil, err
 				} else if next != nil && next.Name == itr.window.name && next.Tags.ID() == itr.window.tags.ID() {
 					interval := int64(itr.opt.Interval.Duration)
+					if interval == 0 {
+						// Calendar windows are interpolated by time.
+						interval = 1
+					}
 					start := itr.window.time / interval
 					p.Value = linearUnsigned(start, itr.prev.Time/interval, next.Time/interval, itr.prev.Value, next.Value)
 				} else {
@@ -6336,7 +6366,16 @@ CONSTRUCT:
 	// Advance the expected time. Do not advance to a new window here
 	// as there may be lingering points with the same timestamp in the previous
 	// window.
-	if itr.opt.Ascending {
+	if itr.opt.Interval.Months != 0 {
+		// Calendar windows are not all the same length and already
+		// account for offset changes in the location.
+		if itr.opt.Ascending {
+			_, itr.window.time = itr.opt.Window(itr.window.time)
+		} else {
+			itr.window.time, _ = itr.opt.Window(itr.window.time - 1)
+		}
+		return p, nil
+	} else if itr.opt.Ascending {
 		itr.window.time += int64(itr.opt.Interval.Duration)
 	} else {
 		itr.window.time -= int64(itr.opt.Interval.Duration)
@@ -9035,7 +9074,16 @@ CONSTRUCT:
 	// Advance the expected time. Do not advance to a new window here
 	// as there may be lingering points with the same timestamp in the previous
 	// window.
-	if itr.opt.Ascending {
+	if itr.opt.Interval.Months != 0 {
+		// Calendar windows are not all the same length and already
+		// account for offset changes in the location.
+		if itr.opt.Ascending {
+			_, itr.window.time = itr.opt.Window(itr.window.time)
+		} else {
+			itr.window.time, _ = itr.opt.Window(itr.window.time - 1)
+		}
+		return p, nil
+	} else if itr.opt.Ascending {
 		itr.window.time += int64(itr.opt.Interval.Duration)
 	} else {
 		itr.window.time -= int64(itr.opt.Interval.Duration)
@@ -11734,7 +11782,16 @@ CONSTRUCT:
 	// Advance the expected time. Do not advance to a new window here
 	// as there may be lingering points with the same timestamp in the previous
 	// window.
-	if itr.opt.Ascending {
+	if itr.opt.Interval.Months != 0 {
+		// Calendar windows are not all the same length and already
+		// account for offset changes in the location.
+		if itr.opt.Ascending {
+			_, itr.window.time = itr.opt.Window(itr.window.time)
+		} else {
+			itr.window.time, _ = itr.opt.Window(itr.window.time - 1)
+		}
+		return p, nil
+	} else if itr.opt.Ascending {
 		itr.window.time += int64(itr.opt.Interval.Duration)
 	} else {
 		itr.window.time -= int64(itr.opt.Interval.Duration)
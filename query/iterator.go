@@ -608,6 +608,9 @@ type IteratorOptions struct {
 	// Limits on the creation of iterators.
 	MaxSeriesN int
 
+	// Limits the number of rows a join holds in memory.
+	MaxJoinN int
+
 	// If this channel is set and is closed, the iterator should try to exit
 	// and close as soon as possible.
 	InterruptCh <-chan struct{}
@@ -642,16 +645,26 @@ func newIteratorOptionsStmt(stmt *influxql.SelectStatement, sopt SelectOptions)
 	if err != nil {
 		return opt, err
 	}
+	months, err := stmt.GroupByMonths()
+	if err != nil {
+		return opt, err
+	}
 	// Set duration to zero if a negative interval has been used.
 	if interval < 0 {
 		interval = 0
-	} else if interval > 0 {
+	}
+	if months < 0 {
+		months = 0
+	}
+	if interval > 0 || months > 0 {
 		opt.Interval.Offset, err = stmt.GroupByOffset()
 		if err != nil {
 			return opt, err
 		}
 	}
 	opt.Interval.Duration = interval
+	opt.Interval.Months = months
+	opt.Interval.CalendarWeeks = interval > 0 && stmt.GroupByCalendarWeeks()
 
 	// Always request an ordered output for the top level iterators.
 	// The emitter will always emit points as ordered.
@@ -681,6 +694,7 @@ func newIteratorOptionsStmt(stmt *influxql.SelectStatement, sopt SelectOptions)
 	opt.Limit, opt.Offset = stmt.Limit, stmt.Offset
 	opt.SLimit, opt.SOffset = stmt.SLimit, stmt.SOffset
 	opt.MaxSeriesN = sopt.MaxSeriesN
+	opt.MaxJoinN = sopt.MaxJoinN
 	opt.Authorizer = sopt.Authorizer
 
 	return opt, nil
@@ -690,6 +704,7 @@ func newIteratorOptionsSubstatement(ctx context.Context, stmt *influxql.SelectSt
 	subOpt, err := newIteratorOptionsStmt(stmt, SelectOptions{
 		Authorizer: opt.Authorizer,
 		MaxSeriesN: opt.MaxSeriesN,
+		MaxJoinN:   opt.MaxJoinN,
 	})
 	if err != nil {
 		return IteratorOptions{}, err
@@ -756,7 +771,11 @@ func newIteratorOptionsSubstatement(ctx context.Context, stmt *influxql.SelectSt
 	interval, err := stmt.GroupByInterval()
 	if err != nil {
 		return IteratorOptions{}, err
-	} else if interval == 0 {
+	}
+	months, err := stmt.GroupByMonths()
+	if err != nil {
+		return IteratorOptions{}, err
+	} else if interval == 0 && months == 0 {
 		subOpt.Interval = opt.Interval
 	}
 	return subOpt, nil
@@ -789,6 +808,8 @@ func (opt IteratorOptions) StopTime() int64 {
 func (opt IteratorOptions) Window(t int64) (start, end int64) {
 	if opt.Interval.IsZero() {
 		return opt.StartTime, opt.EndTime + 1
+	} else if opt.Interval.Months != 0 || opt.Interval.CalendarWeeks {
+		return opt.calendarWindow(t)
 	}
 
 	// Subtract the offset to the time so we calculate the correct base interval.
@@ -864,6 +885,39 @@ func (opt IteratorOptions) Window(t int64) (start, end int64) {
 	return
 }
 
+// calendarWindow returns the window [start,end) of a calendar interval that
+// t falls within. The windows start at midnight on the first day of a month,
+// or on a Monday for calendar weeks, in the location of the query.
+func (opt IteratorOptions) calendarWindow(t int64) (start, end int64) {
+	loc := opt.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	t -= int64(opt.Interval.Offset)
+	var s, e time.Time
+	if opt.Interval.CalendarWeeks {
+		weeks := int(opt.Interval.Duration / (7 * 24 * time.Hour))
+		s = influxql.TruncateWeeks(time.Unix(0, t).In(loc), weeks)
+		e = s.AddDate(0, 0, 7*weeks)
+	} else {
+		s = influxql.TruncateMonths(time.Unix(0, t).In(loc), opt.Interval.Months)
+		e = s.AddDate(0, opt.Interval.Months, 0)
+	}
+
+	if s.Before(time.Unix(0, influxql.MinTime)) {
+		start = influxql.MinTime
+	} else {
+		start = s.UnixNano() + int64(opt.Interval.Offset)
+	}
+	if e.After(time.Unix(0, influxql.MaxTime)) {
+		end = influxql.MaxTime
+	} else {
+		end = e.UnixNano() + int64(opt.Interval.Offset)
+	}
+	return start, end
+}
+
 // DerivativeInterval returns the time interval for the derivative function.
 func (opt IteratorOptions) DerivativeInterval() Interval {
 	// Use the interval on the derivative() call, if specified.
@@ -1135,25 +1189,72 @@ func decodeMeasurement(pb *internal.Measurement) (*influxql.Measurement, error)
 }
 
 // Interval represents a repeating interval for a query.
+//
+// Months is set for calendar intervals. The windows of a calendar
+// interval start on the first day of a month in the location of the
+// query and are not all the same length.
+//
+// CalendarWeeks is set for intervals of calendar weeks. The duration is
+// a whole number of weeks and the windows start at midnight on a Monday
+// in the location of the query.
 type Interval struct {
-	Duration time.Duration
-	Offset   time.Duration
+	Duration      time.Duration
+	Months        int
+	CalendarWeeks bool
+	Offset        time.Duration
 }
 
 // IsZero returns true if the interval has no duration.
-func (i Interval) IsZero() bool { return i.Duration == 0 }
+func (i Interval) IsZero() bool { return i.Duration == 0 && i.Months == 0 }
+
+// AddTo returns the time n intervals after the timestamp t in the location.
+func (i Interval) AddTo(t int64, n int, loc *time.Location) int64 {
+	if i.Months == 0 {
+		return t + int64(n)*int64(i.Duration)
+	}
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	other := time.Unix(0, t).In(loc).AddDate(0, n*i.Months, 0)
+	if other.Before(time.Unix(0, influxql.MinTime)) {
+		return influxql.MinTime
+	} else if other.After(time.Unix(0, influxql.MaxTime)) {
+		return influxql.MaxTime
+	}
+	return other.UnixNano()
+}
+
+// monthsBetween returns the number of calendar months from start to end
+// in the location.
+func monthsBetween(start, end int64, loc *time.Location) int {
+	if loc == nil {
+		loc = time.UTC
+	}
+	s, e := time.Unix(0, start).In(loc), time.Unix(0, end).In(loc)
+	return (e.Year()-s.Year())*12 + int(e.Month()) - int(s.Month())
+}
 
 func encodeInterval(i Interval) *internal.Interval {
-	return &internal.Interval{
+	pb := &internal.Interval{
 		Duration: proto.Int64(i.Duration.Nanoseconds()),
 		Offset:   proto.Int64(i.Offset.Nanoseconds()),
 	}
+	if i.Months != 0 {
+		pb.Months = proto.Int64(int64(i.Months))
+	}
+	if i.CalendarWeeks {
+		pb.CalendarWeeks = proto.Bool(true)
+	}
+	return pb
 }
 
 func decodeInterval(pb *internal.Interval) Interval {
 	return Interval{
-		Duration: time.Duration(pb.GetDuration()),
-		Offset:   time.Duration(pb.GetOffset()),
+		Duration:      time.Duration(pb.GetDuration()),
+		Months:        int(pb.GetMonths()),
+		CalendarWeeks: pb.GetCalendarWeeks(),
+		Offset:        time.Duration(pb.GetOffset()),
 	}
 }
 
@@ -1258,6 +1258,71 @@ func TestIteratorOptions_Window_Location(t *testing.T) {
 	}
 }
 
+func TestIteratorOptions_Window_Calendar(t *testing.T) {
+	for _, tt := range []struct {
+		now        time.Time
+		start, end time.Time
+		interval   query.Interval
+	}{
+		{
+			now:      mustParseTime("2000-03-15T12:00:00-08:00"),
+			start:    mustParseTime("2000-03-01T00:00:00-08:00"),
+			end:      mustParseTime("2000-04-01T00:00:00-08:00"),
+			interval: query.Interval{Months: 1},
+		},
+		{
+			now:      mustParseTime("2000-04-15T12:00:00-07:00"),
+			start:    mustParseTime("2000-04-01T00:00:00-08:00"),
+			end:      mustParseTime("2000-05-01T00:00:00-07:00"),
+			interval: query.Interval{Months: 1},
+		},
+		{
+			now:      mustParseTime("2000-11-15T12:00:00-08:00"),
+			start:    mustParseTime("2000-10-01T00:00:00-07:00"),
+			end:      mustParseTime("2001-01-01T00:00:00-08:00"),
+			interval: query.Interval{Months: 3},
+		},
+		{
+			now:      mustParseTime("2000-07-01T00:00:00-07:00"),
+			start:    mustParseTime("2000-01-01T00:00:00-08:00"),
+			end:      mustParseTime("2001-01-01T00:00:00-08:00"),
+			interval: query.Interval{Months: 12},
+		},
+		{
+			now:      mustParseTime("2000-03-01T06:00:00-08:00"),
+			start:    mustParseTime("2000-02-01T12:00:00-08:00"),
+			end:      mustParseTime("2000-03-01T12:00:00-08:00"),
+			interval: query.Interval{Months: 1, Offset: 12 * time.Hour},
+		},
+		{
+			now:      mustParseTime("2000-04-05T12:00:00-07:00"),
+			start:    mustParseTime("2000-04-03T00:00:00-07:00"),
+			end:      mustParseTime("2000-04-10T00:00:00-07:00"),
+			interval: query.Interval{Duration: 7 * 24 * time.Hour, CalendarWeeks: true},
+		},
+		{
+			now:      mustParseTime("2000-04-02T12:00:00-07:00"),
+			start:    mustParseTime("2000-03-27T00:00:00-08:00"),
+			end:      mustParseTime("2000-04-03T00:00:00-07:00"),
+			interval: query.Interval{Duration: 7 * 24 * time.Hour, CalendarWeeks: true},
+		},
+	} {
+		t.Run(fmt.Sprintf("%s/%d/%s", tt.now, tt.interval.Months, tt.interval.Duration), func(t *testing.T) {
+			opt := query.IteratorOptions{
+				Location: LosAngeles,
+				Interval: tt.interval,
+			}
+			start, end := opt.Window(tt.now.UnixNano())
+			if have, want := time.Unix(0, start).In(LosAngeles), tt.start; !have.Equal(want) {
+				t.Errorf("unexpected start time: %s != %s", have, want)
+			}
+			if have, want := time.Unix(0, end).In(LosAngeles), tt.end; !have.Equal(want) {
+				t.Errorf("unexpected end time: %s != %s", have, want)
+			}
+		})
+	}
+}
+
 func TestIteratorOptions_Window_MinTime(t *testing.T) {
 	opt := query.IteratorOptions{
 		StartTime: influxql.MinTime,
@@ -0,0 +1,376 @@
+package query
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/ayang64/reflux/influxql"
+)
+
+type joinBuilder struct {
+	ic   IteratorCreator
+	join *influxql.Join
+}
+
+// buildAuxIterator constructs an auxiliary Iterator from a join.
+func (b *joinBuilder) buildAuxIterator(ctx context.Context, opt IteratorOptions) (Iterator, error) {
+	cur, err := b.buildCursor(ctx, opt)
+	if err != nil {
+		return nil, err
+	}
+
+	// Filter the cursor by a condition if one was given.
+	if opt.Condition != nil {
+		cur = newFilterCursor(cur, opt.Condition)
+	}
+
+	// Map the desired auxiliary fields from the joined columns.
+	indexes := b.mapAuxFields(cur.Columns(), opt.Aux)
+	return b.newIteratorMapper(cur, nil, indexes, opt), nil
+}
+
+func (b *joinBuilder) buildVarRefIterator(ctx context.Context, expr *influxql.VarRef, opt IteratorOptions) (Iterator, error) {
+	cur, err := b.buildCursor(ctx, opt)
+	if err != nil {
+		return nil, err
+	}
+
+	// Look for the field or tag that is driving this query.
+	driver := b.mapAuxField(cur.Columns(), expr)
+	if driver == nil {
+		// Exit immediately if there is no driver. If there is no driver, there
+		// are no results. Period.
+		cur.Close()
+		return nil, nil
+	}
+
+	// Filter the cursor by a condition if one was given.
+	if opt.Condition != nil {
+		cur = newFilterCursor(cur, opt.Condition)
+	}
+
+	indexes := b.mapAuxFields(cur.Columns(), opt.Aux)
+	return b.newIteratorMapper(cur, driver, indexes, opt), nil
+}
+
+func (b *joinBuilder) newIteratorMapper(cur Cursor, driver IteratorMap, indexes []IteratorMap, opt IteratorOptions) Iterator {
+	// The joined rows are tagged with the left tags of the join condition.
+	// Strip them to the dimensions of the query if it uses fewer.
+	itr := NewIteratorMapper(cur, driver, indexes, opt)
+	if left, _, _ := b.join.Keys(); len(opt.GetDimensions()) != len(left) {
+		itr = NewTagSubsetIterator(itr, opt)
+	}
+	return itr
+}
+
+func (b *joinBuilder) mapAuxFields(columns []influxql.VarRef, auxFields []influxql.VarRef) []IteratorMap {
+	indexes := make([]IteratorMap, len(auxFields))
+	for i, name := range auxFields {
+		m := b.mapAuxField(columns, &name)
+		if m == nil {
+			// If this field doesn't map to anything, use the NullMap so it
+			// shows up as null.
+			m = NullMap{}
+		}
+		indexes[i] = m
+	}
+	return indexes
+}
+
+func (b *joinBuilder) mapAuxField(columns []influxql.VarRef, name *influxql.VarRef) IteratorMap {
+	for i, col := range columns {
+		if col.Val == name.Val {
+			return FieldMap{
+				Index: i,
+				// Cast the result of the field into the desired type.
+				Type: name.Type,
+			}
+		}
+	}
+
+	// The tags of the join condition are available without a prefix.
+	left, _, _ := b.join.Keys()
+	for _, k := range left {
+		if k == name.Val {
+			return TagMap(k)
+		}
+	}
+	return nil
+}
+
+// buildCursor builds the cursors for both sides of the join and
+// returns a cursor over the joined rows.
+func (b *joinBuilder) buildCursor(ctx context.Context, opt IteratorOptions) (Cursor, error) {
+	left, right, err := b.join.Keys()
+	if err != nil {
+		return nil, err
+	}
+
+	lcur, err := b.buildSideCursor(ctx, b.join.Left, opt)
+	if err != nil {
+		return nil, err
+	}
+	rcur, err := b.buildSideCursor(ctx, b.join.Right, opt)
+	if err != nil {
+		lcur.Close()
+		return nil, err
+	}
+	return newJoinCursor(b.join, lcur, rcur, left, right, opt.Ascending, opt.MaxJoinN), nil
+}
+
+func (b *joinBuilder) buildSideCursor(ctx context.Context, src influxql.Source, opt IteratorOptions) (Cursor, error) {
+	// Both sides are rewritten into subqueries when the statement is compiled.
+	stmt := src.(*influxql.SubQuery).Statement
+
+	subOpt, err := newIteratorOptionsSubstatement(ctx, stmt, opt)
+	if err != nil {
+		return nil, err
+	}
+
+	// Each side is grouped by its own tags of the join condition instead of
+	// the dimensions of the parent. The series limits of the parent apply to
+	// the joined series and not to either side.
+	subOpt.Dimensions = nil
+	subOpt.GroupBy = make(map[string]struct{}, len(stmt.Dimensions))
+	for _, d := range stmt.Dimensions {
+		if ref, ok := d.Expr.(*influxql.VarRef); ok {
+			subOpt.Dimensions = append(subOpt.Dimensions, ref.Val)
+			subOpt.GroupBy[ref.Val] = struct{}{}
+		}
+	}
+	subOpt.SLimit, subOpt.SOffset = stmt.SLimit, stmt.SOffset
+	return buildCursor(ctx, stmt, b.ic, subOpt)
+}
+
+// joinCursor joins the rows of two cursors that have the same time and the
+// same values for the tags of the join condition. Both inputs are read in
+// full before the first row is returned, so the rows read and the rows
+// joined are each limited to maxN, the max-join-point setting.
+type joinCursor struct {
+	typ         influxql.JoinType
+	left, right Cursor
+	lkeys       []string
+	rkeys       []string
+	name        string
+	ascending   bool
+	maxN        int
+
+	columns []influxql.VarRef
+	rows    []Row
+	series  Series
+	joined  bool
+	err     error
+}
+
+func newJoinCursor(j *influxql.Join, left, right Cursor, lkeys, rkeys []string, ascending bool, maxN int) *joinCursor {
+	lcols, rcols := left.Columns(), right.Columns()
+	columns := make([]influxql.VarRef, 0, len(lcols)+len(rcols))
+	for _, col := range lcols {
+		columns = append(columns, influxql.VarRef{Val: j.LeftAlias + "." + col.Val, Type: col.Type})
+	}
+	for _, col := range rcols {
+		columns = append(columns, influxql.VarRef{Val: j.RightAlias + "." + col.Val, Type: col.Type})
+	}
+
+	return &joinCursor{
+		typ:       j.Type,
+		left:      left,
+		right:     right,
+		lkeys:     lkeys,
+		rkeys:     rkeys,
+		name:      j.LeftAlias + "_" + j.RightAlias,
+		ascending: ascending,
+		maxN:      maxN,
+		columns:   columns,
+	}
+}
+
+func (cur *joinCursor) Scan(row *Row) bool {
+	if !cur.joined {
+		cur.joined = true
+		if cur.err = cur.join(); cur.err != nil {
+			return false
+		}
+	}
+	if len(cur.rows) == 0 {
+		return false
+	}
+
+	*row = cur.rows[0]
+	if !row.Series.Tags.Equals(&cur.series.Tags) || cur.series.id == 0 {
+		cur.series.Name = row.Series.Name
+		cur.series.Tags = row.Series.Tags
+		cur.series.id++
+	}
+	row.Series = cur.series
+	cur.rows = cur.rows[1:]
+	return true
+}
+
+// joinSeries holds the rows of one side of the join for a set of
+// join tag values.
+type joinSeries struct {
+	tags  Tags
+	left  []Row
+	right []Row
+}
+
+func (cur *joinCursor) join() error {
+	series := make(map[string]*joinSeries)
+	var n int
+	read := func(c Cursor, keys []string, left bool) error {
+		var row Row
+		for c.Scan(&row) {
+			if n++; cur.maxN > 0 && n > cur.maxN {
+				return ErrMaxJoinPointsLimitExceeded(n, cur.maxN)
+			}
+
+			values := make([]string, len(keys))
+			for i, k := range keys {
+				values[i] = row.Series.Tags.Value(k)
+			}
+			id := strings.Join(values, "\x00")
+
+			s := series[id]
+			if s == nil {
+				// The joined rows are tagged with the names of the left keys.
+				m := make(map[string]string, len(keys))
+				for i, k := range cur.lkeys {
+					m[k] = values[i]
+				}
+				s = &joinSeries{tags: NewTags(m)}
+				series[id] = s
+			}
+
+			// The cursor reuses the values between calls to Scan.
+			r := Row{Time: row.Time, Values: make([]interface{}, len(row.Values))}
+			copy(r.Values, row.Values)
+			if left {
+				s.left = append(s.left, r)
+			} else {
+				s.right = append(s.right, r)
+			}
+		}
+		return c.Err()
+	}
+	if err := read(cur.left, cur.lkeys, true); err != nil {
+		return err
+	}
+	if err := read(cur.right, cur.rkeys, false); err != nil {
+		return err
+	}
+
+	// Output the series in the same order as the other cursors.
+	sorted := make([]*joinSeries, 0, len(series))
+	for _, s := range series {
+		sorted = append(sorted, s)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if cur.ascending {
+			return sorted[i].tags.ID() < sorted[j].tags.ID()
+		}
+		return sorted[i].tags.ID() > sorted[j].tags.ID()
+	})
+
+	for _, s := range sorted {
+		if err := cur.merge(s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// merge joins the rows of both sides of the series by time.
+func (cur *joinCursor) merge(s *joinSeries) error {
+	// Rows within a series are read in the order of the query.
+	// Compare the times in that order so the output keeps it.
+	before := func(a, b int64) bool {
+		if cur.ascending {
+			return a < b
+		}
+		return a > b
+	}
+
+	nleft := len(cur.left.Columns())
+	emit := func(ts int64, l, r *Row) error {
+		if n := len(cur.rows) + 1; cur.maxN > 0 && n > cur.maxN {
+			return ErrMaxJoinPointsLimitExceeded(n, cur.maxN)
+		}
+
+		values := make([]interface{}, len(cur.columns))
+		if l != nil {
+			copy(values[:nleft], l.Values)
+		}
+		if r != nil {
+			copy(values[nleft:], r.Values)
+		}
+		cur.rows = append(cur.rows, Row{
+			Time:   ts,
+			Series: Series{Name: cur.name, Tags: s.tags},
+			Values: values,
+		})
+		return nil
+	}
+
+	i, j := 0, 0
+	for i < len(s.left) || j < len(s.right) {
+		switch {
+		case j >= len(s.right) || (i < len(s.left) && before(s.left[i].Time, s.right[j].Time)):
+			if cur.typ != influxql.InnerJoin {
+				if err := emit(s.left[i].Time, &s.left[i], nil); err != nil {
+					return err
+				}
+			}
+			i++
+		case i >= len(s.left) || before(s.right[j].Time, s.left[i].Time):
+			if cur.typ == influxql.FullJoin {
+				if err := emit(s.right[j].Time, nil, &s.right[j]); err != nil {
+					return err
+				}
+			}
+			j++
+		default:
+			// Every pair of rows with the same time is joined.
+			ts := s.left[i].Time
+			iend, jend := i, j
+			for iend < len(s.left) && s.left[iend].Time == ts {
+				iend++
+			}
+			for jend < len(s.right) && s.right[jend].Time == ts {
+				jend++
+			}
+			for ; i < iend; i++ {
+				for k := j; k < jend; k++ {
+					if err := emit(ts, &s.left[i], &s.right[k]); err != nil {
+						return err
+					}
+				}
+			}
+			j = jend
+		}
+	}
+	return nil
+}
+
+func (cur *joinCursor) Stats() IteratorStats {
+	stats := cur.left.Stats()
+	stats.Add(cur.right.Stats())
+	return stats
+}
+
+func (cur *joinCursor) Err() error {
+	return cur.err
+}
+
+func (cur *joinCursor) Columns() []influxql.VarRef {
+	return cur.columns
+}
+
+func (cur *joinCursor) Close() error {
+	err := cur.left.Close()
+	if e := cur.right.Close(); e != nil && err == nil {
+		err = e
+	}
+	return err
+}
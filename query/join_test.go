@@ -0,0 +1,185 @@
+package query_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/ayang64/reflux/influxql"
+	"github.com/ayang64/reflux/query"
+)
+
+func TestJoin(t *testing.T) {
+	// Each measurement is returned as a raw iterator with the auxiliary
+	// fields that were requested.
+	data := map[string][]struct {
+		host   string
+		time   int64
+		values map[string]float64
+	}{
+		"cpu": {
+			{host: "a", time: 0 * Second, values: map[string]float64{"usage": 1}},
+			{host: "a", time: 5 * Second, values: map[string]float64{"usage": 2}},
+			{host: "b", time: 0 * Second, values: map[string]float64{"usage": 4}},
+		},
+		"capacity": {
+			{host: "a", time: 0 * Second, values: map[string]float64{"capacity": 10}},
+			{host: "a", time: 10 * Second, values: map[string]float64{"capacity": 20}},
+			{host: "b", time: 0 * Second, values: map[string]float64{"capacity": 8}},
+		},
+	}
+
+	for _, test := range []struct {
+		Name      string
+		Statement string
+		Rows      []query.Row
+	}{
+		{
+			Name:      "Inner",
+			Statement: `SELECT a.usage / b.capacity FROM cpu AS a INNER JOIN capacity AS b ON a.host = b.host WHERE time >= '1970-01-01T00:00:00Z' AND time < '1970-01-01T00:00:15Z'`,
+			Rows: []query.Row{
+				{Time: 0 * Second, Series: query.Series{Name: "a_b"}, Values: []interface{}{0.1}},
+				{Time: 0 * Second, Series: query.Series{Name: "a_b"}, Values: []interface{}{0.5}},
+			},
+		},
+		{
+			Name:      "Left",
+			Statement: `SELECT a.usage, b.capacity FROM cpu AS a LEFT JOIN capacity AS b ON a.host = b.host WHERE time >= '1970-01-01T00:00:00Z' AND time < '1970-01-01T00:00:15Z' GROUP BY host`,
+			Rows: []query.Row{
+				{Time: 0 * Second, Series: query.Series{Name: "a_b", Tags: ParseTags("host=a")}, Values: []interface{}{float64(1), float64(10)}},
+				{Time: 5 * Second, Series: query.Series{Name: "a_b", Tags: ParseTags("host=a")}, Values: []interface{}{float64(2), nil}},
+				{Time: 0 * Second, Series: query.Series{Name: "a_b", Tags: ParseTags("host=b")}, Values: []interface{}{float64(4), float64(8)}},
+			},
+		},
+		{
+			Name:      "FullOuter",
+			Statement: `SELECT cpu.usage, capacity.capacity FROM cpu FULL OUTER JOIN capacity ON cpu.host = capacity.host WHERE host = 'a' AND time >= '1970-01-01T00:00:00Z' AND time < '1970-01-01T00:00:15Z'`,
+			Rows: []query.Row{
+				{Time: 0 * Second, Series: query.Series{Name: "cpu_capacity"}, Values: []interface{}{float64(1), float64(10)}},
+				{Time: 5 * Second, Series: query.Series{Name: "cpu_capacity"}, Values: []interface{}{float64(2), nil}},
+				{Time: 10 * Second, Series: query.Series{Name: "cpu_capacity"}, Values: []interface{}{nil, float64(20)}},
+			},
+		},
+		{
+			Name:      "GroupByTime",
+			Statement: `SELECT sum(a.usage) FROM cpu AS a JOIN capacity AS b ON a.host = b.host WHERE time >= '1970-01-01T00:00:00Z' AND time < '1970-01-01T00:00:10Z' GROUP BY time(5s)`,
+			Rows: []query.Row{
+				{Time: 0 * Second, Series: query.Series{Name: "a_b"}, Values: []interface{}{float64(5)}},
+				{Time: 5 * Second, Series: query.Series{Name: "a_b"}, Values: []interface{}{nil}},
+			},
+		},
+	} {
+		t.Run(test.Name, func(t *testing.T) {
+			shardMapper := ShardMapper{
+				MapShardsFn: func(sources influxql.Sources, tr influxql.TimeRange) query.ShardGroup {
+					return &ShardGroup{
+						Fields: map[string]influxql.DataType{
+							"usage":    influxql.Float,
+							"capacity": influxql.Float,
+						},
+						Dimensions: []string{"host"},
+						CreateIteratorFn: func(ctx context.Context, m *influxql.Measurement, opt query.IteratorOptions) (query.Iterator, error) {
+							if got, want := opt.Dimensions, []string{"host"}; !cmp.Equal(got, want) {
+								t.Errorf("unexpected dimensions: got=%v want=%v", got, want)
+							}
+
+							var points []query.FloatPoint
+							for _, d := range data[m.Name] {
+								if d.time < opt.StartTime || d.time > opt.EndTime {
+									continue
+								}
+								aux := make([]interface{}, len(opt.Aux))
+								for i, ref := range opt.Aux {
+									if v, ok := d.values[ref.Val]; ok {
+										aux[i] = v
+									}
+								}
+								points = append(points, query.FloatPoint{
+									Name: m.Name,
+									Tags: ParseTags("host=" + d.host),
+									Time: d.time,
+									Aux:  aux,
+								})
+							}
+							return &FloatIterator{Points: points}, nil
+						},
+					}
+				},
+			}
+
+			stmt := MustParseSelectStatement(test.Statement)
+			stmt.OmitTime = true
+			cur, err := query.Select(context.Background(), stmt, &shardMapper, query.SelectOptions{})
+			if err != nil {
+				t.Fatalf("unexpected parse error: %s", err)
+			} else if a, err := ReadCursor(cur); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			} else if diff := cmp.Diff(test.Rows, a); diff != "" {
+				t.Fatalf("unexpected points:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestJoin_InvalidCondition(t *testing.T) {
+	for _, s := range []string{
+		`SELECT a.usage FROM cpu AS a JOIN capacity AS b ON a.host = 'a'`,
+		`SELECT a.usage FROM cpu AS a JOIN capacity AS b ON a.host = b.host OR a.region = b.region`,
+		`SELECT a.usage FROM cpu AS a JOIN capacity AS b ON a.host = b.host GROUP BY region`,
+		`SELECT usage FROM cpu AS a JOIN capacity AS b ON a.host = b.host`,
+	} {
+		if _, err := query.Compile(MustParseSelectStatement(s), query.CompileOptions{}); err == nil {
+			t.Errorf("expected error: %s", s)
+		}
+	}
+}
+
+// Ensures a join fails once the rows it holds in memory exceed the maximum
+// number of join points.
+func TestJoin_MaxJoinN(t *testing.T) {
+	shardMapper := ShardMapper{
+		MapShardsFn: func(sources influxql.Sources, tr influxql.TimeRange) query.ShardGroup {
+			return &ShardGroup{
+				Fields: map[string]influxql.DataType{
+					"usage":    influxql.Float,
+					"capacity": influxql.Float,
+				},
+				Dimensions: []string{"host"},
+				CreateIteratorFn: func(ctx context.Context, m *influxql.Measurement, opt query.IteratorOptions) (query.Iterator, error) {
+					// Every point has the same time, so each row of one side is
+					// joined with every row of the other.
+					points := make([]query.FloatPoint, 3)
+					for i := range points {
+						points[i] = query.FloatPoint{Name: m.Name, Tags: ParseTags("host=a"), Aux: make([]interface{}, len(opt.Aux))}
+					}
+					return &FloatIterator{Points: points}, nil
+				},
+			}
+		},
+	}
+
+	for _, test := range []struct {
+		MaxJoinN int
+		Err      string
+	}{
+		{MaxJoinN: 5, Err: "max-join-point limit exceeded: (6/5)"},
+		{MaxJoinN: 8, Err: "max-join-point limit exceeded: (9/8)"},
+		{MaxJoinN: 9},
+	} {
+		stmt := MustParseSelectStatement(`SELECT a.usage, b.capacity FROM cpu AS a JOIN capacity AS b ON a.host = b.host WHERE time >= '1970-01-01T00:00:00Z' AND time < '1970-01-01T00:00:15Z'`)
+		cur, err := query.Select(context.Background(), stmt, &shardMapper, query.SelectOptions{MaxJoinN: test.MaxJoinN})
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		rows, err := ReadCursor(cur)
+		if test.Err == "" {
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			} else if len(rows) != 9 {
+				t.Fatalf("unexpected number of rows: %d", len(rows))
+			}
+		} else if err == nil || err.Error() != test.Err {
+			t.Fatalf("unexpected error with %d max join points: got %v, exp %s", test.MaxJoinN, err, test.Err)
+		}
+	}
+}
@@ -39,6 +39,9 @@ type SelectOptions struct {
 
 	// Maximum number of buckets for a statement.
 	MaxBucketsN int
+
+	// Maximum number of rows a join reads or produces.
+	MaxJoinN int
 }
 
 // ShardMapper retrieves and maps shards into an IteratorCreator that can later be
@@ -153,7 +156,12 @@ func buildExprIterator(ctx context.Context, expr influxql.Expr, ic IteratorCreat
 	case *influxql.VarRef:
 		return b.buildVarRefIterator(ctx, expr)
 	case *influxql.Call:
+		if isStringFunction(expr) {
+			return b.buildRowExprIterator(ctx, expr)
+		}
 		return b.buildCallIterator(ctx, expr)
+	case *influxql.CaseExpr:
+		return b.buildRowExprIterator(ctx, expr)
 	default:
 		return nil, fmt.Errorf("invalid expression type: %T", expr)
 	}
@@ -190,6 +198,18 @@ func (b *exprIteratorBuilder) buildVarRefIterator(ctx context.Context, expr *inf
 				} else if input != nil {
 					inputs = append(inputs, input)
 				}
+			case *influxql.Join:
+				join := joinBuilder{
+					ic:   b.ic,
+					join: source,
+				}
+
+				input, err := join.buildVarRefIterator(ctx, expr, b.opt)
+				if err != nil {
+					return err
+				} else if input != nil {
+					inputs = append(inputs, input)
+				}
 			}
 		}
 		return nil
@@ -211,6 +231,43 @@ func (b *exprIteratorBuilder) buildVarRefIterator(ctx context.Context, expr *inf
 	return itr, nil
 }
 
+// buildRowExprIterator builds an iterator that evaluates an expression, such
+// as a CASE expression, for every point that is read from the sources.
+func (b *exprIteratorBuilder) buildRowExprIterator(ctx context.Context, expr influxql.Expr) (Iterator, error) {
+	valuer := influxql.TypeValuerEval{
+		TypeMapper: DefaultTypeMapper,
+	}
+	typ, err := valuer.EvalType(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	// The expression and the auxiliary fields of the caller are evaluated
+	// as the columns of a raw query of the same sources.
+	stmt := &influxql.SelectStatement{
+		Fields:     []*influxql.Field{{Expr: expr}},
+		Sources:    b.sources,
+		IsRawQuery: true,
+		OmitTime:   true,
+	}
+	fields := make([]IteratorMap, len(b.opt.Aux))
+	for i := range b.opt.Aux {
+		stmt.Fields = append(stmt.Fields, &influxql.Field{Expr: &b.opt.Aux[i]})
+		fields[i] = FieldMap{Index: i + 1, Type: b.opt.Aux[i].Type}
+	}
+
+	opt := b.opt
+	opt.Expr, opt.Aux = nil, nil
+	opt.Fill, opt.FillValue = influxql.NullFill, nil
+	opt.Ordered = true
+
+	cur, err := buildCursor(ctx, stmt, b.ic, opt)
+	if err != nil {
+		return nil, err
+	}
+	return NewIteratorMapper(cur, FieldMap{Index: 0, Type: typ}, fields, b.opt), nil
+}
+
 func (b *exprIteratorBuilder) buildCallIterator(ctx context.Context, expr *influxql.Call) (Iterator, error) {
 	// TODO(jsternberg): Refactor this. This section needs to die in a fire.
 	opt := b.opt
@@ -258,9 +315,9 @@ func (b *exprIteratorBuilder) buildCallIterator(ctx context.Context, expr *influ
 	case "derivative", "non_negative_derivative", "difference", "non_negative_difference", "moving_average", "exponential_moving_average", "double_exponential_moving_average", "triple_exponential_moving_average", "relative_strength_index", "triple_exponential_derivative", "kaufmans_efficiency_ratio", "kaufmans_adaptive_moving_average", "chande_momentum_oscillator", "elapsed":
 		if !opt.Interval.IsZero() {
 			if opt.Ascending {
-				opt.StartTime -= int64(opt.Interval.Duration)
+				opt.StartTime = opt.Interval.AddTo(opt.StartTime, -1, opt.Location)
 			} else {
-				opt.EndTime += int64(opt.Interval.Duration)
+				opt.EndTime = opt.Interval.AddTo(opt.EndTime, 1, opt.Location)
 			}
 		}
 		opt.Ordered = true
@@ -285,9 +342,9 @@ func (b *exprIteratorBuilder) buildCallIterator(ctx context.Context, expr *influ
 			n := expr.Args[1].(*influxql.IntegerLiteral)
 			if n.Val > 1 && !opt.Interval.IsZero() {
 				if opt.Ascending {
-					opt.StartTime -= int64(opt.Interval.Duration) * (n.Val - 1)
+					opt.StartTime = opt.Interval.AddTo(opt.StartTime, -int(n.Val-1), opt.Location)
 				} else {
-					opt.EndTime += int64(opt.Interval.Duration) * (n.Val - 1)
+					opt.EndTime = opt.Interval.AddTo(opt.EndTime, int(n.Val-1), opt.Location)
 				}
 			}
 			return newMovingAverageIterator(input, int(n.Val), opt)
@@ -295,9 +352,9 @@ func (b *exprIteratorBuilder) buildCallIterator(ctx context.Context, expr *influ
 			n := expr.Args[1].(*influxql.IntegerLiteral)
 			if n.Val > 1 && !opt.Interval.IsZero() {
 				if opt.Ascending {
-					opt.StartTime -= int64(opt.Interval.Duration) * (n.Val - 1)
+					opt.StartTime = opt.Interval.AddTo(opt.StartTime, -int(n.Val-1), opt.Location)
 				} else {
-					opt.EndTime += int64(opt.Interval.Duration) * (n.Val - 1)
+					opt.EndTime = opt.Interval.AddTo(opt.EndTime, int(n.Val-1), opt.Location)
 				}
 			}
 
@@ -329,9 +386,9 @@ func (b *exprIteratorBuilder) buildCallIterator(ctx context.Context, expr *influ
 			n := expr.Args[1].(*influxql.IntegerLiteral)
 			if n.Val > 1 && !opt.Interval.IsZero() {
 				if opt.Ascending {
-					opt.StartTime -= int64(opt.Interval.Duration) * (n.Val - 1)
+					opt.StartTime = opt.Interval.AddTo(opt.StartTime, -int(n.Val-1), opt.Location)
 				} else {
-					opt.EndTime += int64(opt.Interval.Duration) * (n.Val - 1)
+					opt.EndTime = opt.Interval.AddTo(opt.EndTime, int(n.Val-1), opt.Location)
 				}
 			}
 
@@ -350,9 +407,9 @@ func (b *exprIteratorBuilder) buildCallIterator(ctx context.Context, expr *influ
 			n := expr.Args[1].(*influxql.IntegerLiteral)
 			if n.Val > 1 && !opt.Interval.IsZero() {
 				if opt.Ascending {
-					opt.StartTime -= int64(opt.Interval.Duration) * (n.Val - 1)
+					opt.StartTime = opt.Interval.AddTo(opt.StartTime, -int(n.Val-1), opt.Location)
 				} else {
-					opt.EndTime += int64(opt.Interval.Duration) * (n.Val - 1)
+					opt.EndTime = opt.Interval.AddTo(opt.EndTime, int(n.Val-1), opt.Location)
 				}
 			}
 
@@ -383,7 +440,7 @@ func (b *exprIteratorBuilder) buildCallIterator(ctx context.Context, expr *influ
 		return newCumulativeSumIterator(input, opt)
 	case "integral":
 		opt.Ordered = true
-		input, err := buildExprIterator(ctx, expr.Args[0].(*influxql.VarRef), b.ic, b.sources, opt, false, false)
+		input, err := buildExprIterator(ctx, expr.Args[0], b.ic, b.sources, opt, false, false)
 		if err != nil {
 			return nil, err
 		}
@@ -515,33 +572,33 @@ func (b *exprIteratorBuilder) buildCallIterator(ctx context.Context, expr *influ
 			return b.callIterator(ctx, expr, opt)
 		case "median":
 			opt.Ordered = true
-			input, err := buildExprIterator(ctx, expr.Args[0].(*influxql.VarRef), b.ic, b.sources, opt, false, false)
+			input, err := buildExprIterator(ctx, expr.Args[0], b.ic, b.sources, opt, false, false)
 			if err != nil {
 				return nil, err
 			}
 			return newMedianIterator(input, opt)
 		case "mode":
-			input, err := buildExprIterator(ctx, expr.Args[0].(*influxql.VarRef), b.ic, b.sources, opt, false, false)
+			input, err := buildExprIterator(ctx, expr.Args[0], b.ic, b.sources, opt, false, false)
 			if err != nil {
 				return nil, err
 			}
 			return NewModeIterator(input, opt)
 		case "stddev":
-			input, err := buildExprIterator(ctx, expr.Args[0].(*influxql.VarRef), b.ic, b.sources, opt, false, false)
+			input, err := buildExprIterator(ctx, expr.Args[0], b.ic, b.sources, opt, false, false)
 			if err != nil {
 				return nil, err
 			}
 			return newStddevIterator(input, opt)
 		case "spread":
 			// OPTIMIZE(benbjohnson): convert to map/reduce
-			input, err := buildExprIterator(ctx, expr.Args[0].(*influxql.VarRef), b.ic, b.sources, opt, false, false)
+			input, err := buildExprIterator(ctx, expr.Args[0], b.ic, b.sources, opt, false, false)
 			if err != nil {
 				return nil, err
 			}
 			return newSpreadIterator(input, opt)
 		case "percentile":
 			opt.Ordered = true
-			input, err := buildExprIterator(ctx, expr.Args[0].(*influxql.VarRef), b.ic, b.sources, opt, false, false)
+			input, err := buildExprIterator(ctx, expr.Args[0], b.ic, b.sources, opt, false, false)
 			if err != nil {
 				return nil, err
 			}
@@ -579,28 +636,28 @@ func (b *exprIteratorBuilder) callIterator(ctx context.Context, expr *influxql.C
 		for _, source := range b.sources {
 			switch source := source.(type) {
 			case *influxql.Measurement:
-				input, err := b.ic.CreateIterator(ctx, source, opt)
-				if err != nil {
-					return err
+				// The storage engine can only compute calls of a field.
+				// Other expressions are evaluated by the query engine.
+				if _, ok := expr.Args[0].(*influxql.VarRef); !ok {
+					input, err := b.callSourceIterator(ctx, expr, source, opt)
+					if err != nil {
+						return err
+					}
+					inputs = append(inputs, input)
+					continue
 				}
-				inputs = append(inputs, input)
-			case *influxql.SubQuery:
-				// Identify the name of the field we are using.
-				arg0 := expr.Args[0].(*influxql.VarRef)
 
-				opt.Ordered = false
-				input, err := buildExprIterator(ctx, arg0, b.ic, []influxql.Source{source}, opt, b.selector, false)
+				input, err := b.ic.CreateIterator(ctx, source, opt)
 				if err != nil {
 					return err
 				}
-
-				// Wrap the result in a call iterator.
-				i, err := NewCallIterator(input, opt)
+				inputs = append(inputs, input)
+			case *influxql.SubQuery, *influxql.Join:
+				input, err := b.callSourceIterator(ctx, expr, source, opt)
 				if err != nil {
-					input.Close()
 					return err
 				}
-				inputs = append(inputs, i)
+				inputs = append(inputs, input)
 			}
 		}
 		return nil
@@ -619,9 +676,31 @@ func (b *exprIteratorBuilder) callIterator(ctx context.Context, expr *influxql.C
 	return itr, nil
 }
 
+// callSourceIterator reads the argument of the call from the source and
+// wraps it in a call iterator.
+func (b *exprIteratorBuilder) callSourceIterator(ctx context.Context, expr *influxql.Call, source influxql.Source, opt IteratorOptions) (Iterator, error) {
+	opt.Ordered = false
+	input, err := buildExprIterator(ctx, expr.Args[0], b.ic, []influxql.Source{source}, opt, b.selector, false)
+	if err != nil {
+		return nil, err
+	}
+
+	// Wrap the result in a call iterator.
+	i, err := NewCallIterator(input, opt)
+	if err != nil {
+		input.Close()
+		return nil, err
+	}
+	return i, nil
+}
+
 type QueryNow string
 
 func buildCursor(ctx context.Context, stmt *influxql.SelectStatement, ic IteratorCreator, opt IteratorOptions) (Cursor, error) {
+	if stmt.Having != nil {
+		return buildHavingCursor(ctx, stmt, ic, opt)
+	}
+
 	span := tracing.SpanFromContext(ctx)
 	if span != nil {
 		span = span.StartSpan("build_cursor")
@@ -807,6 +886,18 @@ func buildAuxIterator(ctx context.Context, ic IteratorCreator, sources influxql.
 					stmt: source.Statement,
 				}
 
+				input, err := b.buildAuxIterator(ctx, opt)
+				if err != nil {
+					return err
+				} else if input != nil {
+					inputs = append(inputs, input)
+				}
+			case *influxql.Join:
+				b := joinBuilder{
+					ic:   ic,
+					join: source,
+				}
+
 				input, err := b.buildAuxIterator(ctx, opt)
 				if err != nil {
 					return err
@@ -926,7 +1017,7 @@ func (v *valueMapper) Visit(n influxql.Node) influxql.Visitor {
 		// as stored in the symbol table.
 		switch n := n.(type) {
 		case *influxql.Call:
-			if isMathFunction(n) {
+			if isMathFunction(n) || isStringFunction(n) {
 				return v
 			}
 			v.calls[n] = struct{}{}
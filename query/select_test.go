@@ -55,6 +55,41 @@ func TestSelect(t *testing.T) {
 				{Time: 0 * Second, Series: query.Series{Name: "cpu", Tags: ParseTags("host=B")}, Values: []interface{}{float64(10)}},
 			},
 		},
+		{
+			name: "Sum_Calendar",
+			q:    `SELECT sum(value) FROM cpu WHERE time >= '1970-01-01T00:00:00Z' AND time < '1970-04-01T00:00:00Z' GROUP BY time(1mo) fill(null)`,
+			typ:  influxql.Float,
+			expr: `sum(value::float)`,
+			itrs: []query.Iterator{
+				&FloatIterator{Points: []query.FloatPoint{
+					{Name: "cpu", Time: 0 * Second, Value: 10},
+					{Name: "cpu", Time: 20 * 86400 * Second, Value: 5},
+					{Name: "cpu", Time: 69 * 86400 * Second, Value: 3},
+				}},
+			},
+			rows: []query.Row{
+				{Time: 0 * Second, Series: query.Series{Name: "cpu"}, Values: []interface{}{float64(15)}},
+				{Time: 31 * 86400 * Second, Series: query.Series{Name: "cpu"}, Values: []interface{}{nil}},
+				{Time: 59 * 86400 * Second, Series: query.Series{Name: "cpu"}, Values: []interface{}{float64(3)}},
+			},
+		},
+		{
+			name: "Sum_CalendarWeeks",
+			q:    `SELECT sum(value) FROM cpu WHERE time >= '1970-01-05T00:00:00Z' AND time < '1970-01-19T00:00:00Z' GROUP BY time(1cw) fill(null)`,
+			typ:  influxql.Float,
+			expr: `sum(value::float)`,
+			itrs: []query.Iterator{
+				&FloatIterator{Points: []query.FloatPoint{
+					{Name: "cpu", Time: 4 * 86400 * Second, Value: 10},
+					{Name: "cpu", Time: 10 * 86400 * Second, Value: 5},
+					{Name: "cpu", Time: 11 * 86400 * Second, Value: 3},
+				}},
+			},
+			rows: []query.Row{
+				{Time: 4 * 86400 * Second, Series: query.Series{Name: "cpu"}, Values: []interface{}{float64(15)}},
+				{Time: 11 * 86400 * Second, Series: query.Series{Name: "cpu"}, Values: []interface{}{float64(3)}},
+			},
+		},
 		{
 			name: "Distinct_Float",
 			q:    `SELECT distinct(value) FROM cpu WHERE time >= '1970-01-01T00:00:00Z' AND time < '1970-01-02T00:00:00Z' GROUP BY time(10s), host fill(none)`,
@@ -2834,6 +2869,47 @@ func TestSelect(t *testing.T) {
 			},
 			now: mustParseTime("1970-01-01T00:02:30Z"),
 		},
+		{
+			name: "Having_Limit",
+			q:    `SELECT mean(value) AS m FROM cpu WHERE time >= '1970-01-01T00:00:00Z' AND time < '1970-01-02T00:00:00Z' GROUP BY time(10s), host fill(none) HAVING m > 10 LIMIT 1 OFFSET 1`,
+			typ:  influxql.Float,
+			expr: `mean(value::float)`,
+			itrs: []query.Iterator{
+				&FloatIterator{Points: []query.FloatPoint{
+					{Name: "cpu", Tags: ParseTags("region=west,host=A"), Time: 0 * Second, Value: 20},
+					{Name: "cpu", Tags: ParseTags("region=west,host=A"), Time: 11 * Second, Value: 3},
+					{Name: "cpu", Tags: ParseTags("region=west,host=A"), Time: 31 * Second, Value: 100},
+				}},
+				&FloatIterator{Points: []query.FloatPoint{
+					{Name: "cpu", Tags: ParseTags("region=west,host=B"), Time: 5 * Second, Value: 10},
+				}},
+			},
+			rows: []query.Row{
+				{Time: 30 * Second, Series: query.Series{Name: "cpu", Tags: ParseTags("host=A")}, Values: []interface{}{float64(100)}},
+			},
+		},
+		{
+			name: "Having_SLimit",
+			q:    `SELECT mean(value) FROM cpu WHERE time >= '1970-01-01T00:00:00Z' AND time < '1970-01-02T00:00:00Z' GROUP BY host HAVING mean < 12 SLIMIT 1`,
+			typ:  influxql.Float,
+			expr: `mean(value::float)`,
+			itrs: []query.Iterator{
+				&FloatIterator{Points: []query.FloatPoint{
+					{Name: "cpu", Tags: ParseTags("region=west,host=A"), Time: 0 * Second, Value: 20},
+					{Name: "cpu", Tags: ParseTags("region=west,host=A"), Time: 11 * Second, Value: 3},
+					{Name: "cpu", Tags: ParseTags("region=west,host=A"), Time: 31 * Second, Value: 100},
+				}},
+				&FloatIterator{Points: []query.FloatPoint{
+					{Name: "cpu", Tags: ParseTags("region=west,host=B"), Time: 5 * Second, Value: 10},
+				}},
+				&FloatIterator{Points: []query.FloatPoint{
+					{Name: "cpu", Tags: ParseTags("region=west,host=C"), Time: 5 * Second, Value: 8},
+				}},
+			},
+			rows: []query.Row{
+				{Time: 0 * Second, Series: query.Series{Name: "cpu", Tags: ParseTags("host=B")}, Values: []interface{}{float64(10)}},
+			},
+		},
 	} {
 		t.Run(tt.name, func(t *testing.T) {
 			shardMapper := ShardMapper{
@@ -4226,3 +4302,121 @@ func ReadCursor(cur query.Cursor) ([]query.Row, error) {
 		rows = append(rows, row)
 	}
 }
+
+// Ensure a HAVING clause can filter on an aggregate that is not selected.
+func TestSelect_Having_HiddenAggregate(t *testing.T) {
+	points := []query.FloatPoint{
+		{Name: "cpu", Tags: ParseTags("host=A"), Time: 0 * Second, Value: 20},
+		{Name: "cpu", Tags: ParseTags("host=A"), Time: 5 * Second, Value: 60},
+		{Name: "cpu", Tags: ParseTags("host=B"), Time: 0 * Second, Value: 10},
+		{Name: "cpu", Tags: ParseTags("host=B"), Time: 5 * Second, Value: 30},
+	}
+
+	shardMapper := ShardMapper{
+		MapShardsFn: func(sources influxql.Sources, _ influxql.TimeRange) query.ShardGroup {
+			return &ShardGroup{
+				Fields:     map[string]influxql.DataType{"value": influxql.Float},
+				Dimensions: []string{"host"},
+				CreateIteratorFn: func(ctx context.Context, m *influxql.Measurement, opt query.IteratorOptions) (query.Iterator, error) {
+					// Each aggregate builds its own iterator so return fresh points every call.
+					itr := &FloatIterator{Points: append([]query.FloatPoint(nil), points...)}
+					if _, ok := opt.Expr.(*influxql.Call); ok {
+						return query.NewCallIterator(itr, opt)
+					}
+					return itr, nil
+				},
+			}
+		},
+	}
+
+	stmt := MustParseSelectStatement(`SELECT mean(value) FROM cpu WHERE time >= '1970-01-01T00:00:00Z' AND time < '1970-01-02T00:00:00Z' GROUP BY host HAVING max(value) > 50`)
+	stmt.OmitTime = true
+	cur, err := query.Select(context.Background(), stmt, &shardMapper, query.SelectOptions{})
+	if err != nil {
+		t.Fatalf("unexpected parse error: %s", err)
+	} else if got, want := len(cur.Columns()), 1; got != want {
+		t.Fatalf("unexpected column count: got=%d want=%d", got, want)
+	} else if a, err := ReadCursor(cur); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if diff := cmp.Diff([]query.Row{
+		{Time: 0 * Second, Series: query.Series{Name: "cpu", Tags: ParseTags("host=A")}, Values: []interface{}{float64(40)}},
+	}, a); diff != "" {
+		t.Fatalf("unexpected points:\n%s", diff)
+	}
+}
+
+func TestSelect_CaseExpr(t *testing.T) {
+	points := []map[string]interface{}{
+		{"status": "ok", "value": float64(10)},
+		{"status": "err", "value": float64(20)},
+		{"status": "err", "value": float64(5)},
+	}
+
+	shardMapper := ShardMapper{
+		MapShardsFn: func(sources influxql.Sources, _ influxql.TimeRange) query.ShardGroup {
+			return &ShardGroup{
+				Fields: map[string]influxql.DataType{
+					"status": influxql.String,
+					"value":  influxql.Float,
+				},
+				CreateIteratorFn: func(ctx context.Context, m *influxql.Measurement, opt query.IteratorOptions) (query.Iterator, error) {
+					if m.Name != "cpu" {
+						t.Fatalf("unexpected source: %s", m.Name)
+					}
+					itr := &FloatIterator{}
+					for i, p := range points {
+						aux := make([]interface{}, len(opt.Aux))
+						for j, ref := range opt.Aux {
+							aux[j] = p[ref.Val]
+						}
+						itr.Points = append(itr.Points, query.FloatPoint{Name: "cpu", Time: int64(i*5) * Second, Aux: aux})
+					}
+					return itr, nil
+				},
+			}
+		},
+	}
+
+	for _, test := range []struct {
+		Name      string
+		Statement string
+		Rows      []query.Row
+	}{
+		{
+			Name:      "Raw",
+			Statement: `SELECT upper(status), CASE WHEN value > 15 THEN 'high' ELSE 'low' END FROM cpu`,
+			Rows: []query.Row{
+				{Time: 0 * Second, Series: query.Series{Name: "cpu"}, Values: []interface{}{"OK", "low"}},
+				{Time: 5 * Second, Series: query.Series{Name: "cpu"}, Values: []interface{}{"ERR", "high"}},
+				{Time: 10 * Second, Series: query.Series{Name: "cpu"}, Values: []interface{}{"ERR", "low"}},
+			},
+		},
+		{
+			Name:      "Count",
+			Statement: `SELECT count(CASE WHEN status = 'err' THEN 1 END) FROM cpu WHERE time >= '1970-01-01T00:00:00Z' AND time < '1970-01-02T00:00:00Z'`,
+			Rows: []query.Row{
+				{Time: 0 * Second, Series: query.Series{Name: "cpu"}, Values: []interface{}{int64(2)}},
+			},
+		},
+		{
+			Name:      "Sum_StringFunction",
+			Statement: `SELECT sum(strlen(status)) FROM cpu WHERE time >= '1970-01-01T00:00:00Z' AND time < '1970-01-02T00:00:00Z'`,
+			Rows: []query.Row{
+				{Time: 0 * Second, Series: query.Series{Name: "cpu"}, Values: []interface{}{int64(8)}},
+			},
+		},
+	} {
+		t.Run(test.Name, func(t *testing.T) {
+			stmt := MustParseSelectStatement(test.Statement)
+			stmt.OmitTime = true
+			cur, err := query.Select(context.Background(), stmt, &shardMapper, query.SelectOptions{})
+			if err != nil {
+				t.Errorf("%s: parse error: %s", test.Name, err)
+			} else if a, err := ReadCursor(cur); err != nil {
+				t.Fatalf("%s: unexpected error: %s", test.Name, err)
+			} else if diff := cmp.Diff(test.Rows, a); diff != "" {
+				t.Errorf("%s: unexpected points:\n%s", test.Name, diff)
+			}
+		})
+	}
+}
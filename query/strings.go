@@ -0,0 +1,191 @@
+package query
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/ayang64/reflux/influxql"
+)
+
+func isStringFunction(call *influxql.Call) bool {
+	switch call.Name {
+	case "lower", "upper", "concat", "substring", "strlen", "regexp_extract", "regexp_replace":
+		return true
+	}
+	return false
+}
+
+type StringTypeMapper struct{}
+
+func (StringTypeMapper) MapType(measurement *influxql.Measurement, field string) influxql.DataType {
+	return influxql.Unknown
+}
+
+func (StringTypeMapper) CallType(name string, args []influxql.DataType) (influxql.DataType, error) {
+	isString := func(typ influxql.DataType) bool {
+		switch typ {
+		case influxql.String, influxql.Tag, influxql.Unknown:
+			return true
+		}
+		return false
+	}
+	isInteger := func(typ influxql.DataType) bool {
+		switch typ {
+		case influxql.Integer, influxql.Unknown:
+			return true
+		}
+		return false
+	}
+
+	switch name {
+	case "lower", "upper", "strlen", "substring", "regexp_extract", "regexp_replace":
+		var arg0 influxql.DataType
+		if len(args) > 0 {
+			arg0 = args[0]
+		}
+		if !isString(arg0) {
+			return influxql.Unknown, fmt.Errorf("invalid argument type for the first argument in %s(): %s", name, arg0)
+		}
+
+		switch name {
+		case "strlen":
+			return influxql.Integer, nil
+		case "substring":
+			for i := 1; i < len(args); i++ {
+				if !isInteger(args[i]) {
+					return influxql.Unknown, fmt.Errorf("invalid argument type for argument %d in %s(): %s", i+1, name, args[i])
+				}
+			}
+		case "regexp_extract":
+			if len(args) > 2 && !isInteger(args[2]) {
+				return influxql.Unknown, fmt.Errorf("invalid argument type for the third argument in %s(): %s", name, args[2])
+			}
+		case "regexp_replace":
+			if len(args) > 2 && !isString(args[2]) {
+				return influxql.Unknown, fmt.Errorf("invalid argument type for the third argument in %s(): %s", name, args[2])
+			}
+		}
+		return influxql.String, nil
+	case "concat":
+		for i, typ := range args {
+			if !isString(typ) {
+				return influxql.Unknown, fmt.Errorf("invalid argument type for argument %d in %s(): %s", i+1, name, typ)
+			}
+		}
+		return influxql.String, nil
+	}
+	return influxql.Unknown, nil
+}
+
+type StringValuer struct{}
+
+var _ influxql.CallValuer = StringValuer{}
+
+func (StringValuer) Value(key string) (interface{}, bool) {
+	return nil, false
+}
+
+func (v StringValuer) Call(name string, args []interface{}) (interface{}, bool) {
+	switch name {
+	case "lower", "upper", "concat", "substring", "strlen", "regexp_extract", "regexp_replace":
+	default:
+		return nil, false
+	}
+
+	// The first argument is the string for every function. If it is
+	// missing, the result is null.
+	if len(args) == 0 {
+		return nil, true
+	}
+	arg0, ok := args[0].(string)
+	if !ok {
+		return nil, true
+	}
+
+	switch name {
+	case "lower":
+		return strings.ToLower(arg0), true
+	case "upper":
+		return strings.ToUpper(arg0), true
+	case "strlen":
+		return int64(utf8.RuneCountInString(arg0)), true
+	case "concat":
+		var buf strings.Builder
+		for _, arg := range args {
+			s, ok := arg.(string)
+			if !ok {
+				return nil, true
+			}
+			buf.WriteString(s)
+		}
+		return buf.String(), true
+	case "substring":
+		if len(args) < 2 {
+			return nil, true
+		}
+		start, ok := args[1].(int64)
+		if !ok {
+			return nil, true
+		}
+		length := int64(-1)
+		if len(args) > 2 {
+			if length, ok = args[2].(int64); !ok || length < 0 {
+				return nil, true
+			}
+		}
+		return substring(arg0, start, length), true
+	case "regexp_extract":
+		if len(args) < 2 {
+			return nil, true
+		}
+		re, ok := args[1].(*regexp.Regexp)
+		if !ok {
+			return nil, true
+		}
+		group := int64(0)
+		if len(args) > 2 {
+			if group, ok = args[2].(int64); !ok {
+				return nil, true
+			}
+		}
+
+		m := re.FindStringSubmatch(arg0)
+		if m == nil || group < 0 || group >= int64(len(m)) {
+			return nil, true
+		}
+		return m[group], true
+	case "regexp_replace":
+		if len(args) < 3 {
+			return nil, true
+		}
+		re, ok := args[1].(*regexp.Regexp)
+		if !ok {
+			return nil, true
+		}
+		repl, ok := args[2].(string)
+		if !ok {
+			return nil, true
+		}
+		return re.ReplaceAllString(arg0, repl), true
+	}
+	return nil, false
+}
+
+// substring returns the characters of s starting at the 1-based position
+// start. A negative length returns the remainder of the string.
+func substring(s string, start, length int64) string {
+	runes := []rune(s)
+	end := int64(len(runes)) + 1
+	if length >= 0 && start+length < end {
+		end = start + length
+	}
+	if start < 1 {
+		start = 1
+	}
+	if start >= end {
+		return ""
+	}
+	return string(runes[start-1 : end-1])
+}
@@ -0,0 +1,96 @@
+package query_test
+
+import (
+	"testing"
+
+	"github.com/ayang64/reflux/influxql"
+	"github.com/ayang64/reflux/query"
+)
+
+func TestString_TypeMapper(t *testing.T) {
+	for _, tt := range []struct {
+		s   string
+		typ influxql.DataType
+		err bool
+	}{
+		{s: `lower(s::string)`, typ: influxql.String},
+		{s: `lower(t::tag)`, typ: influxql.String},
+		{s: `lower(f::float)`, err: true},
+		{s: `lower(b::boolean)`, err: true},
+		{s: `upper(s::string)`, typ: influxql.String},
+		{s: `upper(t::tag)`, typ: influxql.String},
+		{s: `upper(i::integer)`, err: true},
+		{s: `strlen(s::string)`, typ: influxql.Integer},
+		{s: `strlen(t::tag)`, typ: influxql.Integer},
+		{s: `strlen(f::float)`, err: true},
+		{s: `concat(s::string, t::tag)`, typ: influxql.String},
+		{s: `concat(s::string, 'x')`, typ: influxql.String},
+		{s: `concat(s::string, f::float)`, err: true},
+		{s: `substring(s::string, 2)`, typ: influxql.String},
+		{s: `substring(s::string, 2, 3)`, typ: influxql.String},
+		{s: `substring(s::string, 2.5)`, err: true},
+		{s: `substring(f::float, 2)`, err: true},
+		{s: `regexp_extract(s::string, /a(.)/)`, typ: influxql.String},
+		{s: `regexp_extract(s::string, /a(.)/, 1)`, typ: influxql.String},
+		{s: `regexp_extract(i::integer, /a(.)/)`, err: true},
+		{s: `regexp_replace(s::string, /a/, 'b')`, typ: influxql.String},
+		{s: `regexp_replace(s::string, /a/, 1)`, err: true},
+	} {
+		t.Run(tt.s, func(t *testing.T) {
+			expr := MustParseExpr(tt.s)
+
+			typmap := influxql.TypeValuerEval{
+				TypeMapper: query.StringTypeMapper{},
+			}
+			if got, err := typmap.EvalType(expr); err != nil {
+				if !tt.err {
+					t.Errorf("unexpected error: %s", err)
+				}
+			} else if tt.err {
+				t.Error("expected error")
+			} else if want := tt.typ; got != want {
+				t.Errorf("unexpected type:\n\t-: \"%s\"\n\t+: \"%s\"", want, got)
+			}
+		})
+	}
+}
+
+func TestStringValuer_Call(t *testing.T) {
+	type values map[string]interface{}
+	for _, tt := range []struct {
+		s      string
+		values values
+		exp    interface{}
+	}{
+		{s: `lower(s)`, values: values{"s": "Server01"}, exp: "server01"},
+		{s: `upper(s)`, values: values{"s": "Server01"}, exp: "SERVER01"},
+		{s: `upper(s)`, values: values{"s": int64(1)}, exp: nil},
+		{s: `strlen(s)`, values: values{"s": "héllo"}, exp: int64(5)},
+		{s: `concat(s, '-', t)`, values: values{"s": "us", "t": "west"}, exp: "us-west"},
+		{s: `concat(s, t)`, values: values{"s": "us"}, exp: nil},
+		{s: `substring(s, 2)`, values: values{"s": "server01"}, exp: "erver01"},
+		{s: `substring(s, 2, 3)`, values: values{"s": "server01"}, exp: "erv"},
+		{s: `substring(s, 0, 3)`, values: values{"s": "server01"}, exp: "se"},
+		{s: `substring(s, 10)`, values: values{"s": "server01"}, exp: ""},
+		{s: `regexp_extract(s, /server(\d+)/)`, values: values{"s": "server01"}, exp: "server01"},
+		{s: `regexp_extract(s, /server(\d+)/, 1)`, values: values{"s": "server01"}, exp: "01"},
+		{s: `regexp_extract(s, /server(\d+)/, 2)`, values: values{"s": "server01"}, exp: nil},
+		{s: `regexp_extract(s, /host(\d+)/)`, values: values{"s": "server01"}, exp: nil},
+		{s: `regexp_replace(s, /\d+/, 'XX')`, values: values{"s": "server01"}, exp: "serverXX"},
+		{s: `regexp_replace(s, /(s)(e)/, '${2}${1}')`, values: values{"s": "server01"}, exp: "esrver01"},
+	} {
+		t.Run(tt.s, func(t *testing.T) {
+			expr := MustParseExpr(tt.s)
+
+			valuer := influxql.ValuerEval{
+				Valuer: influxql.MultiValuer(
+					influxql.MapValuer(tt.values),
+					query.StringValuer{},
+				),
+			}
+			if got, want := valuer.Eval(expr), tt.exp; got != want {
+				t.Errorf("unexpected value: %v != %v", want, got)
+			}
+		})
+	}
+}
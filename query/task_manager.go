@@ -71,6 +71,10 @@ type TaskManager struct {
 	// Maximum number of concurrent queries.
 	MaxConcurrentQueries int
 
+	// Returns the maximum number of concurrent queries against a database.
+	// A limit of zero is not enforced.
+	DatabaseMaxConcurrentQueries func(database string) int
+
 	// Logger to use for all logging.
 	// Defaults to discarding all log output.
 	Logger *zap.Logger
@@ -182,10 +186,20 @@ func (t *TaskManager) AttachQuery(q *influxql.Query, opt ExecutionOptions, inter
 		return nil, nil, ErrMaxConcurrentQueriesLimitExceeded(len(t.queries), t.MaxConcurrentQueries)
 	}
 
+	databases := queryDatabases(q, opt.Database)
+	if t.DatabaseMaxConcurrentQueries != nil {
+		for _, db := range databases {
+			if limit := t.DatabaseMaxConcurrentQueries(db); limit > 0 && t.databaseQueries(db) >= limit {
+				return nil, nil, ErrQueryQuotaExceeded(db, limit)
+			}
+		}
+	}
+
 	qid := t.nextID
 	query := &Task{
 		query:     q.String(),
 		database:  opt.Database,
+		databases: databases,
 		status:    RunningTask,
 		startTime: time.Now(),
 		closing:   make(chan struct{}),
@@ -220,6 +234,63 @@ func (t *TaskManager) AttachQuery(q *influxql.Query, opt ExecutionOptions, inter
 	return ctx, func() { t.DetachQuery(qid) }, nil
 }
 
+// DatabaseQueries returns the number of running queries that read a database.
+func (t *TaskManager) DatabaseQueries(database string) int {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.databaseQueries(database)
+}
+
+// databaseQueries returns the number of running queries that read a database.
+// The caller must hold the lock.
+func (t *TaskManager) databaseQueries(database string) int {
+	var n int
+	for _, qi := range t.queries {
+		for _, db := range qi.databases {
+			if db == database {
+				n++
+				break
+			}
+		}
+	}
+	return n
+}
+
+// queryDatabases returns the databases read by the SELECT statements of q.
+// Measurements without a database are read from the default database. Other
+// statements do not read a database, so that administrative statements are
+// not limited by the quotas of the databases they manage.
+func queryDatabases(q *influxql.Query, defaultDatabase string) []string {
+	var databases []string
+	add := func(db string) {
+		if db == "" {
+			db = defaultDatabase
+		}
+		if db == "" {
+			return
+		}
+		for _, other := range databases {
+			if other == db {
+				return
+			}
+		}
+		databases = append(databases, db)
+	}
+
+	for _, stmt := range q.Statements {
+		stmt, ok := stmt.(*influxql.SelectStatement)
+		if !ok {
+			continue
+		}
+		influxql.WalkFunc(stmt.Sources, func(n influxql.Node) {
+			if m, ok := n.(*influxql.Measurement); ok {
+				add(m.Database)
+			}
+		})
+	}
+	return databases
+}
+
 // KillQuery enters a query into the killed state and closes the channel
 // from the TaskManager. This method can be used to forcefully terminate a
 // running query.
@@ -0,0 +1,101 @@
+// Package audit implements the audit log of authentication attempts and of
+// the statements that change the database or its privileges.
+package audit // import "github.com/ayang64/reflux/services/audit"
+
+import (
+	"net"
+	"time"
+
+	"github.com/ayang64/reflux/influxql"
+)
+
+// Actions of audit events.
+const (
+	// ActionStatement is the action of an executed statement.
+	ActionStatement = "statement"
+
+	// ActionLogin is the action of a password authentication.
+	ActionLogin = "login"
+
+	// ActionToken is the action of an API token authentication.
+	ActionToken = "token"
+
+	// ActionJWT is the action of a JWT bearer authentication.
+	ActionJWT = "jwt"
+)
+
+// Outcomes of audit events.
+const (
+	OutcomeSuccess = "success"
+	OutcomeFailure = "failure"
+)
+
+// Event is a single entry of the audit log.
+type Event struct {
+	Time      time.Time `json:"time"`
+	Action    string    `json:"action"`
+	User      string    `json:"user,omitempty"`
+	Source    string    `json:"source,omitempty"`
+	Database  string    `json:"database,omitempty"`
+	Statement string    `json:"statement,omitempty"`
+	Token     string    `json:"token,omitempty"`
+	Outcome   string    `json:"outcome"`
+	Error     string    `json:"error,omitempty"`
+}
+
+// Recorder records audit events.
+type Recorder interface {
+	Record(e Event)
+}
+
+// Source returns the source recorded for a client connecting from addr. The
+// port is dropped so that the connections of a client share the same source.
+func Source(addr string) string {
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		return host
+	}
+	return addr
+}
+
+// isAuthentication returns true if action is the action of an authentication.
+func isAuthentication(action string) bool {
+	return action == ActionLogin || action == ActionToken || action == ActionJWT
+}
+
+// Audited returns true if stmt is recorded in the audit log. Every statement
+// other than SELECT is, as are the SELECT statements that write INTO a
+// measurement.
+func Audited(stmt influxql.Statement) bool {
+	if stmt, ok := stmt.(*influxql.SelectStatement); ok {
+		return stmt.Target != nil
+	}
+	return true
+}
+
+// RecordDenied records the audited statements of q as failures with err, for
+// a query that user was not authorized to execute. The user is empty if the
+// request was not authenticated.
+func RecordDenied(r Recorder, q *influxql.Query, user, source, database string, err error) {
+	for _, stmt := range q.Statements {
+		if !Audited(stmt) {
+			continue
+		}
+		r.Record(Event{
+			Action:    ActionStatement,
+			User:      user,
+			Source:    source,
+			Database:  database,
+			Statement: stmt.String(),
+			Outcome:   OutcomeFailure,
+			Error:     err.Error(),
+		})
+	}
+}
+
+// Outcome returns the outcome of an action that returned err.
+func Outcome(err error) (outcome, msg string) {
+	if err != nil {
+		return OutcomeFailure, err.Error()
+	}
+	return OutcomeSuccess, ""
+}
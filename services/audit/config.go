@@ -0,0 +1,109 @@
+package audit
+
+import (
+	"errors"
+	"time"
+
+	"github.com/ayang64/reflux/monitor/diagnostics"
+	"github.com/ayang64/reflux/toml"
+)
+
+const (
+	// DefaultMaxSize is the size at which the audit log file is rotated.
+	DefaultMaxSize = 100 * 1024 * 1024
+
+	// DefaultMaxBackups is the number of rotated audit log files that are kept.
+	DefaultMaxBackups = 7
+
+	// DefaultStoreDatabase is the database the audit events are stored in.
+	DefaultStoreDatabase = "_audit"
+
+	// DefaultStoreInterval is the interval at which audit events are stored.
+	DefaultStoreInterval = 10 * time.Second
+
+	// DefaultAuthInterval is the interval within which repeated successful
+	// authentications of a user from the same source are recorded once.
+	DefaultAuthInterval = time.Minute
+)
+
+// Config represents the configuration for the audit log.
+type Config struct {
+	Enabled bool `toml:"enabled"`
+
+	// Path of the file the audit events are written to.
+	Path string `toml:"path"`
+
+	// The file is rotated once it reaches MaxSize. Only MaxBackups rotated
+	// files are kept.
+	MaxSize    toml.Size `toml:"max-size"`
+	MaxBackups int       `toml:"max-backups"`
+
+	// Successful authentications of a user from the same source are only
+	// recorded once every AuthInterval, so that clients authenticating every
+	// request do not flood the log. Failures are always recorded. A zero
+	// interval records every authentication.
+	AuthInterval toml.Duration `toml:"auth-interval"`
+
+	// Whether the audit events are also stored in the audit measurement of
+	// StoreDatabase every StoreInterval.
+	StoreEnabled  bool          `toml:"store-enabled"`
+	StoreDatabase string        `toml:"store-database"`
+	StoreInterval toml.Duration `toml:"store-interval"`
+}
+
+// NewConfig returns a new Config with defaults.
+func NewConfig() Config {
+	return Config{
+		MaxSize:       toml.Size(DefaultMaxSize),
+		MaxBackups:    DefaultMaxBackups,
+		AuthInterval:  toml.Duration(DefaultAuthInterval),
+		StoreDatabase: DefaultStoreDatabase,
+		StoreInterval: toml.Duration(DefaultStoreInterval),
+	}
+}
+
+// Validate returns an error if the Config is invalid.
+func (c Config) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+
+	if c.Path == "" {
+		return errors.New("path must be specified")
+	}
+	if c.MaxBackups < 0 {
+		return errors.New("max-backups cannot be negative")
+	}
+	if c.AuthInterval < 0 {
+		return errors.New("auth-interval cannot be negative")
+	}
+	if c.StoreEnabled {
+		if c.StoreDatabase == "" {
+			return errors.New("store-database must be specified")
+		}
+		if c.StoreInterval <= 0 {
+			return errors.New("store-interval must be greater than 0")
+		}
+	}
+	return nil
+}
+
+// Diagnostics returns a diagnostics representation of a subset of the Config.
+func (c Config) Diagnostics() (*diagnostics.Diagnostics, error) {
+	if !c.Enabled {
+		return diagnostics.RowFromMap(map[string]interface{}{
+			"enabled": false,
+		}), nil
+	}
+
+	return diagnostics.RowFromMap(map[string]interface{}{
+		"enabled":        true,
+		"path":           c.Path,
+		"max-size":       c.MaxSize,
+		"max-backups":    c.MaxBackups,
+		"auth-interval":  c.AuthInterval,
+		"store-enabled":  c.StoreEnabled,
+		"store-database": c.StoreDatabase,
+		"store-interval": c.StoreInterval,
+	}), nil
+}
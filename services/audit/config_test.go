@@ -0,0 +1,69 @@
+package audit_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/ayang64/reflux/services/audit"
+)
+
+func TestConfig_Parse(t *testing.T) {
+	// Parse configuration.
+	c := audit.NewConfig()
+	if _, err := toml.Decode(`
+enabled = true
+path = "/var/log/influxdb/audit.log"
+max-size = "10m"
+max-backups = 3
+auth-interval = "30s"
+store-enabled = true
+store-interval = "1m"
+`, &c); err != nil {
+		t.Fatal(err)
+	}
+
+	// Validate configuration.
+	if !c.Enabled {
+		t.Errorf("unexpected enabled state: %v", c.Enabled)
+	} else if c.Path != "/var/log/influxdb/audit.log" {
+		t.Errorf("unexpected path: %s", c.Path)
+	} else if c.MaxSize != 10*1024*1024 {
+		t.Errorf("unexpected max size: %d", c.MaxSize)
+	} else if c.MaxBackups != 3 {
+		t.Errorf("unexpected max backups: %d", c.MaxBackups)
+	} else if time.Duration(c.AuthInterval) != 30*time.Second {
+		t.Errorf("unexpected auth interval: %s", c.AuthInterval)
+	} else if !c.StoreEnabled {
+		t.Errorf("unexpected store enabled state: %v", c.StoreEnabled)
+	} else if c.StoreDatabase != audit.DefaultStoreDatabase {
+		t.Errorf("unexpected store database: %s", c.StoreDatabase)
+	} else if time.Duration(c.StoreInterval) != time.Minute {
+		t.Errorf("unexpected store interval: %s", c.StoreInterval)
+	}
+}
+
+func TestConfig_Validate(t *testing.T) {
+	c := audit.NewConfig()
+	if err := c.Validate(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	c.Enabled = true
+	if err := c.Validate(); err == nil || err.Error() != "path must be specified" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	c.Path = "audit.log"
+	c.AuthInterval = -1
+	if err := c.Validate(); err == nil || err.Error() != "auth-interval cannot be negative" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	c.AuthInterval = 0
+	c.StoreEnabled = true
+	c.StoreDatabase = ""
+	if err := c.Validate(); err == nil || err.Error() != "store-database must be specified" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
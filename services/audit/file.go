@@ -0,0 +1,112 @@
+package audit
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// rotatingFile is a file that is rotated once it reaches maxSize. The rotated
+// files are renamed to path.1 through path.N with path.1 being the newest.
+type rotatingFile struct {
+	path       string
+	maxSize    int64
+	maxBackups int
+
+	f    *os.File
+	size int64
+}
+
+// openRotatingFile opens the file at path for appending, creating it and its
+// directory if they do not exist. A maxSize of zero never rotates the file.
+func openRotatingFile(path string, maxSize int64, maxBackups int) (*rotatingFile, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0777); err != nil {
+		return nil, err
+	}
+
+	r := &rotatingFile{path: path, maxSize: maxSize, maxBackups: maxBackups}
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *rotatingFile) open() error {
+	f, err := os.OpenFile(r.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return err
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	r.f, r.size = f, fi.Size()
+	return nil
+}
+
+// Write appends b to the file, rotating it first if b would grow it past
+// maxSize. If the file cannot be rotated b is still appended to the current
+// file, so that no event is lost, and the rotation error is returned.
+func (r *rotatingFile) Write(b []byte) (int, error) {
+	var rerr error
+	if r.maxSize > 0 && r.size > 0 && r.size+int64(len(b)) > r.maxSize {
+		if err := r.rotate(); err != nil {
+			rerr = fmt.Errorf("rotate %s: %s", r.path, err)
+		}
+	}
+
+	n, err := r.f.Write(b)
+	r.size += int64(n)
+	if err != nil {
+		return n, err
+	}
+	return n, rerr
+}
+
+// rotate shifts the backups and opens a new file. The current file is only
+// closed once the new file is open, so it stays usable if the rotation fails.
+func (r *rotatingFile) rotate() error {
+	if r.maxBackups == 0 {
+		if err := os.Remove(r.path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	} else {
+		os.Remove(backupPath(r.path, r.maxBackups))
+		for i := r.maxBackups - 1; i > 0; i-- {
+			if err := os.Rename(backupPath(r.path, i), backupPath(r.path, i+1)); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+		}
+		if err := os.Rename(r.path, backupPath(r.path, 1)); err != nil {
+			return err
+		}
+	}
+
+	f := r.f
+	if err := r.open(); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// Close closes the file.
+func (r *rotatingFile) Close() error {
+	return r.f.Close()
+}
+
+// files returns the paths of the existing files, oldest first.
+func (r *rotatingFile) files() []string {
+	var paths []string
+	for i := r.maxBackups; i > 0; i-- {
+		if _, err := os.Stat(backupPath(r.path, i)); err == nil {
+			paths = append(paths, backupPath(r.path, i))
+		}
+	}
+	return append(paths, r.path)
+}
+
+// backupPath returns the path of the nth rotated file.
+func backupPath(path string, n int) string {
+	return fmt.Sprintf("%s.%d", path, n)
+}
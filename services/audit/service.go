@@ -0,0 +1,291 @@
+package audit
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/ayang64/reflux/models"
+	"go.uber.org/zap"
+)
+
+const (
+	// measurement is the name of the measurement audit events are stored in.
+	measurement = "audit"
+
+	// maxPendingPoints is the number of audit points held between stores.
+	// Events recorded while the buffer is full are only written to the file.
+	maxPendingPoints = 10000
+)
+
+// Service writes audit events to a rotating file and, when enabled, stores
+// them as points in a database.
+type Service struct {
+	mu      sync.Mutex
+	file    *rotatingFile
+	pending models.Points
+
+	// authSeen holds the time successful authentications were last recorded,
+	// keyed by the authentication without its time and outcome.
+	authSeen   map[Event]time.Time
+	authPruned time.Time
+
+	PointsWriter interface {
+		WritePoints(database, retentionPolicy string, points models.Points) error
+	}
+
+	Logger *zap.Logger
+	config Config
+}
+
+// NewService returns a new instance of Service.
+func NewService(c Config) *Service {
+	return &Service{
+		Logger: zap.NewNop(),
+		config: c,
+	}
+}
+
+// WithLogger sets the logger on the service.
+func (s *Service) WithLogger(log *zap.Logger) {
+	s.Logger = log.With(zap.String("service", "audit"))
+}
+
+// Open opens the audit log file.
+func (s *Service) Open() error {
+	if !s.config.Enabled {
+		return nil // Service disabled.
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.file != nil {
+		return nil // Already open.
+	}
+
+	f, err := openRotatingFile(s.config.Path, int64(s.config.MaxSize), s.config.MaxBackups)
+	if err != nil {
+		return err
+	}
+	s.file = f
+
+	s.Logger.Info("Opened audit log", zap.String("path", s.config.Path))
+	return nil
+}
+
+// Start stores the recorded events every store interval until ctx is
+// canceled.
+func (s *Service) Start(ctx context.Context) error {
+	if !s.config.Enabled || !s.config.StoreEnabled {
+		return nil
+	}
+
+	ticker := time.NewTicker(time.Duration(s.config.StoreInterval))
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			s.store()
+		case <-ctx.Done():
+			s.store()
+			return nil
+		}
+	}
+}
+
+// Close closes the audit log file.
+func (s *Service) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.file == nil {
+		return nil // Already closed.
+	}
+	err := s.file.Close()
+	s.file = nil
+	return err
+}
+
+// Record writes e to the audit log. It sets the time of the event if it is
+// not set. Successful authentications already recorded within the auth
+// interval are skipped.
+func (s *Service) Record(e Event) {
+	if e.Time.IsZero() {
+		e.Time = time.Now().UTC()
+	}
+
+	buf, err := json.Marshal(e)
+	if err != nil {
+		s.Logger.Info("Failed to encode audit event", zap.Error(err))
+		return
+	}
+	buf = append(buf, '\n')
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.file == nil || s.repeated(e) {
+		return
+	}
+
+	if _, err := s.file.Write(buf); err != nil {
+		s.Logger.Info("Failed to write audit event", zap.Error(err))
+	}
+
+	if s.config.StoreEnabled && len(s.pending) < maxPendingPoints {
+		if pt, err := eventPoint(e); err != nil {
+			s.Logger.Info("Failed to create audit point", zap.Error(err))
+		} else {
+			s.pending = append(s.pending, pt)
+		}
+	}
+}
+
+// repeated returns true if e is a successful authentication that was already
+// recorded within the auth interval. It must be called with s.mu held.
+func (s *Service) repeated(e Event) bool {
+	interval := time.Duration(s.config.AuthInterval)
+	if interval <= 0 || !isAuthentication(e.Action) || e.Outcome != OutcomeSuccess {
+		return false
+	}
+
+	// Drop the entries that have expired so that the map only grows with the
+	// number of users and sources seen within an interval.
+	if e.Time.Sub(s.authPruned) >= interval {
+		for k, t := range s.authSeen {
+			if e.Time.Sub(t) >= interval {
+				delete(s.authSeen, k)
+			}
+		}
+		s.authPruned = e.Time
+	}
+
+	key := Event{Action: e.Action, User: e.User, Source: e.Source, Token: e.Token}
+	if t, ok := s.authSeen[key]; ok && e.Time.Sub(t) < interval {
+		return true
+	}
+	if s.authSeen == nil {
+		s.authSeen = make(map[Event]time.Time)
+	}
+	s.authSeen[key] = e.Time
+	return false
+}
+
+// store writes the pending points to the store database.
+func (s *Service) store() {
+	s.mu.Lock()
+	points := s.pending
+	s.pending = nil
+	s.mu.Unlock()
+
+	if len(points) == 0 || s.PointsWriter == nil {
+		return
+	}
+	if err := s.PointsWriter.WritePoints(s.config.StoreDatabase, "", points); err != nil {
+		s.Logger.Info("Failed to store audit events", zap.Int("n", len(points)), zap.Error(err))
+	}
+}
+
+// Events returns the last limit events of the audit log, oldest first. All
+// events are returned if limit is not positive.
+func (s *Service) Events(limit int) ([]Event, error) {
+	files, err := s.openFiles()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		for _, f := range files {
+			f.Close()
+		}
+	}()
+
+	var events []Event
+	for _, f := range files {
+		scanner := bufio.NewScanner(f)
+		scanner.Buffer(nil, 1024*1024)
+		for scanner.Scan() {
+			var e Event
+			if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+				continue // Skip lines cut off by a crash.
+			}
+			events = append(events, e)
+			if limit > 0 && len(events) > limit {
+				events = events[1:]
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+	}
+	return events, nil
+}
+
+// auditFile is a file of the audit log opened for reading, limited to the
+// size it had when it was opened.
+type auditFile struct {
+	*io.LimitedReader
+	f *os.File
+}
+
+func (f auditFile) Close() error { return f.f.Close() }
+
+// openFiles opens the files of the audit log, oldest first. The files are
+// opened while the log is locked so that rotations and writes that happen
+// while the files are read do not change the events that are returned.
+func (s *Service) openFiles() ([]auditFile, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.file == nil {
+		return nil, nil
+	}
+
+	var files []auditFile
+	closeAll := func() {
+		for _, f := range files {
+			f.Close()
+		}
+	}
+	for _, path := range s.file.files() {
+		f, err := os.Open(path)
+		if os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			closeAll()
+			return nil, err
+		}
+
+		fi, err := f.Stat()
+		if err != nil {
+			f.Close()
+			closeAll()
+			return nil, err
+		}
+		files = append(files, auditFile{LimitedReader: &io.LimitedReader{R: f, N: fi.Size()}, f: f})
+	}
+	return files, nil
+}
+
+// eventPoint returns the point stored for e.
+func eventPoint(e Event) (models.Point, error) {
+	tags := map[string]string{"action": e.Action}
+	if e.User != "" {
+		tags["user"] = e.User
+	}
+
+	fields := map[string]interface{}{"outcome": e.Outcome}
+	for k, v := range map[string]string{
+		"source":    e.Source,
+		"database":  e.Database,
+		"statement": e.Statement,
+		"token":     e.Token,
+		"error":     e.Error,
+	} {
+		if v != "" {
+			fields[k] = v
+		}
+	}
+	return models.NewPoint(measurement, models.NewTags(tags), fields, e.Time)
+}
@@ -0,0 +1,300 @@
+package audit_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ayang64/reflux/models"
+	"github.com/ayang64/reflux/services/audit"
+)
+
+func TestService_Events(t *testing.T) {
+	dir, err := ioutil.TempDir("", "audit-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	c := audit.NewConfig()
+	c.Enabled = true
+	c.Path = filepath.Join(dir, "audit.log")
+	s := audit.NewService(c)
+	if err := s.Open(); err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	for i := 0; i < 3; i++ {
+		s.Record(audit.Event{Action: audit.ActionLogin, User: fmt.Sprintf("user%d", i), Outcome: audit.OutcomeSuccess})
+	}
+
+	events, err := s.Events(0)
+	if err != nil {
+		t.Fatal(err)
+	} else if len(events) != 3 {
+		t.Fatalf("unexpected number of events: %d", len(events))
+	} else if events[0].User != "user0" || events[0].Time.IsZero() {
+		t.Fatalf("unexpected event: %+v", events[0])
+	}
+
+	events, err = s.Events(2)
+	if err != nil {
+		t.Fatal(err)
+	} else if len(events) != 2 || events[0].User != "user1" || events[1].User != "user2" {
+		t.Fatalf("unexpected events: %+v", events)
+	}
+}
+
+func TestService_Rotate(t *testing.T) {
+	dir, err := ioutil.TempDir("", "audit-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	c := audit.NewConfig()
+	c.Enabled = true
+	c.Path = filepath.Join(dir, "audit.log")
+	c.MaxSize = 200
+	c.MaxBackups = 2
+	s := audit.NewService(c)
+	if err := s.Open(); err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	for i := 0; i < 20; i++ {
+		s.Record(audit.Event{Action: audit.ActionStatement, Statement: fmt.Sprintf("DROP DATABASE db%d", i), Outcome: audit.OutcomeSuccess})
+	}
+
+	if _, err := os.Stat(c.Path + ".2"); err != nil {
+		t.Fatalf("expected rotated file: %s", err)
+	} else if _, err := os.Stat(c.Path + ".3"); !os.IsNotExist(err) {
+		t.Fatalf("unexpected rotated file: %v", err)
+	}
+
+	// The oldest events were removed with the rotated files.
+	events, err := s.Events(0)
+	if err != nil {
+		t.Fatal(err)
+	} else if len(events) == 0 || len(events) >= 20 {
+		t.Fatalf("unexpected number of events: %d", len(events))
+	} else if got, exp := events[len(events)-1].Statement, "DROP DATABASE db19"; got != exp {
+		t.Fatalf("unexpected last statement: got %s, exp %s", got, exp)
+	}
+}
+
+// Ensures events are still written when the file cannot be rotated.
+func TestService_Rotate_Failure(t *testing.T) {
+	dir, err := ioutil.TempDir("", "audit-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	c := audit.NewConfig()
+	c.Enabled = true
+	c.Path = filepath.Join(dir, "audit.log")
+	c.MaxSize = 200
+	c.MaxBackups = 1
+	s := audit.NewService(c)
+	if err := s.Open(); err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	// A non-empty directory in place of the backup makes the rename fail.
+	if err := os.MkdirAll(filepath.Join(c.Path+".1", "dir"), 0777); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 10; i++ {
+		s.Record(audit.Event{Action: audit.ActionStatement, Statement: fmt.Sprintf("DROP DATABASE db%d", i), Outcome: audit.OutcomeSuccess})
+	}
+	if buf, err := ioutil.ReadFile(c.Path); err != nil {
+		t.Fatal(err)
+	} else if n := bytes.Count(buf, []byte("\n")); n != 10 {
+		t.Fatalf("unexpected number of events written: %d", n)
+	}
+
+	// The file is rotated once the backup can be replaced.
+	if err := os.RemoveAll(c.Path + ".1"); err != nil {
+		t.Fatal(err)
+	}
+	s.Record(audit.Event{Action: audit.ActionStatement, Statement: "DROP DATABASE db10", Outcome: audit.OutcomeSuccess})
+	if events, err := s.Events(0); err != nil {
+		t.Fatal(err)
+	} else if len(events) != 11 {
+		t.Fatalf("unexpected number of events: %d", len(events))
+	} else if fi, err := os.Stat(c.Path); err != nil {
+		t.Fatal(err)
+	} else if fi.Size() >= 200 {
+		t.Fatalf("file not rotated: size %d", fi.Size())
+	}
+}
+
+// Ensures events read while the log is written and rotated are consecutive.
+func TestService_Events_Concurrent(t *testing.T) {
+	dir, err := ioutil.TempDir("", "audit-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	c := audit.NewConfig()
+	c.Enabled = true
+	c.Path = filepath.Join(dir, "audit.log")
+	c.MaxSize = 1000
+	c.MaxBackups = 3
+	s := audit.NewService(c)
+	if err := s.Open(); err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 500; i++ {
+			s.Record(audit.Event{Action: audit.ActionLogin, User: fmt.Sprintf("user%d", i), Outcome: audit.OutcomeSuccess})
+		}
+	}()
+
+	for {
+		events, err := s.Events(0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := 1; i < len(events); i++ {
+			var prev, cur int
+			fmt.Sscanf(events[i-1].User, "user%d", &prev)
+			fmt.Sscanf(events[i].User, "user%d", &cur)
+			if cur != prev+1 {
+				t.Fatalf("unexpected event after %s: %s", events[i-1].User, events[i].User)
+			}
+		}
+
+		select {
+		case <-done:
+			return
+		default:
+		}
+	}
+}
+
+// Ensures repeated successful authentications are only recorded once per
+// auth interval.
+func TestService_Record_RepeatedAuthentication(t *testing.T) {
+	dir, err := ioutil.TempDir("", "audit-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	c := audit.NewConfig()
+	c.Enabled = true
+	c.Path = filepath.Join(dir, "audit.log")
+	s := audit.NewService(c)
+	if err := s.Open(); err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	now := time.Now().UTC()
+	for _, e := range []audit.Event{
+		{Time: now, Action: audit.ActionLogin, User: "fred", Source: "10.0.0.1", Outcome: audit.OutcomeSuccess},
+		{Time: now.Add(time.Second), Action: audit.ActionLogin, User: "fred", Source: "10.0.0.1", Outcome: audit.OutcomeSuccess},
+		{Time: now.Add(time.Second), Action: audit.ActionLogin, User: "fred", Source: "10.0.0.2", Outcome: audit.OutcomeSuccess},
+		{Time: now.Add(2 * time.Second), Action: audit.ActionLogin, User: "fred", Source: "10.0.0.1", Outcome: audit.OutcomeFailure},
+		{Time: now.Add(2 * time.Second), Action: audit.ActionLogin, User: "fred", Source: "10.0.0.1", Outcome: audit.OutcomeFailure},
+		{Time: now.Add(audit.DefaultAuthInterval), Action: audit.ActionLogin, User: "fred", Source: "10.0.0.1", Outcome: audit.OutcomeSuccess},
+	} {
+		s.Record(e)
+	}
+
+	events, err := s.Events(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, e := range events {
+		got = append(got, fmt.Sprintf("%s %s %s", e.Time.Sub(now), e.Source, e.Outcome))
+	}
+	exp := []string{
+		"0s 10.0.0.1 success",
+		"1s 10.0.0.2 success",
+		"2s 10.0.0.1 failure",
+		"2s 10.0.0.1 failure",
+		"1m0s 10.0.0.1 success",
+	}
+	if !reflect.DeepEqual(got, exp) {
+		t.Fatalf("unexpected events:\ngot: %q\nexp: %q", got, exp)
+	}
+}
+
+func TestService_Store(t *testing.T) {
+	dir, err := ioutil.TempDir("", "audit-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	c := audit.NewConfig()
+	c.Enabled = true
+	c.Path = filepath.Join(dir, "audit.log")
+	c.StoreEnabled = true
+	s := audit.NewService(c)
+
+	var pw PointsWriter
+	pw.WritePointsFn = func(database, rp string, points models.Points) error {
+		if database != audit.DefaultStoreDatabase {
+			t.Errorf("unexpected database: %s", database)
+		}
+		pw.points = append(pw.points, points...)
+		return nil
+	}
+	s.PointsWriter = &pw
+	if err := s.Open(); err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	s.Record(audit.Event{
+		Time:      time.Unix(0, 0),
+		Action:    audit.ActionStatement,
+		User:      "admin",
+		Statement: "DROP DATABASE db0",
+		Outcome:   audit.OutcomeFailure,
+		Error:     "database not found",
+	})
+
+	// The pending points are stored when the service stops.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := s.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(pw.points) != 1 {
+		t.Fatalf("unexpected number of points: %d", len(pw.points))
+	} else if got, exp := pw.points[0].String(), `audit,action=statement,user=admin error="database not found",outcome="failure",statement="DROP DATABASE db0" 0`; got != exp {
+		t.Fatalf("unexpected point:\ngot %s\nexp %s", got, exp)
+	}
+}
+
+// PointsWriter represents a mock impl of PointsWriter.
+type PointsWriter struct {
+	WritePointsFn func(database, retentionPolicy string, points models.Points) error
+
+	points models.Points
+}
+
+func (pw *PointsWriter) WritePoints(database, retentionPolicy string, points models.Points) error {
+	return pw.WritePointsFn(database, retentionPolicy, points)
+}
@@ -12,6 +12,17 @@ import (
 const (
 	// The default value of how often to check whether any CQs need to be run.
 	DefaultRunInterval = time.Second
+
+	// DefaultBackfillDelay is the default pause between the windows of a backfill.
+	DefaultBackfillDelay = 10 * time.Millisecond
+
+	// DefaultRunHistoryInterval is how often the runs of CQs are saved in
+	// the meta store.
+	DefaultRunHistoryInterval = time.Minute
+
+	// DefaultMaxLateReruns is the default maximum number of windows queued to
+	// be re-run because of late-arriving data.
+	DefaultMaxLateReruns = 100
 )
 
 // Config represents a configuration for the continuous query service.
@@ -31,15 +42,36 @@ type Config struct {
 	// every minute, this should be set to 1 minute. The default is set to '1s' so the interval
 	// is compatible with most aggregations.
 	RunInterval toml.Duration `toml:"run-interval"`
+
+	// BackfillDelay is how long a backfill waits between running two windows
+	// of a continuous query. It throttles backfills so they do not starve
+	// other queries. A delay of zero runs the windows back to back.
+	BackfillDelay toml.Duration `toml:"backfill-delay"`
+
+	// LateDataHorizon is how far back points may arrive and still cause the
+	// windows they fall in to be re-run. A horizon of zero disables the
+	// re-evaluation of late-arriving data.
+	LateDataHorizon toml.Duration `toml:"late-data-horizon"`
+
+	// MaxLateReruns is the maximum number of windows queued to be re-run
+	// because of late-arriving data. Windows past the limit are dropped.
+	MaxLateReruns int `toml:"max-late-reruns"`
+
+	// RunHistoryInterval is how often the runs of CQs are saved in the meta
+	// store. Runs that are not saved yet are lost if the process stops.
+	RunHistoryInterval toml.Duration `toml:"run-history-interval"`
 }
 
 // NewConfig returns a new instance of Config with defaults.
 func NewConfig() Config {
 	return Config{
-		LogEnabled:        true,
-		Enabled:           true,
-		QueryStatsEnabled: false,
-		RunInterval:       toml.Duration(DefaultRunInterval),
+		LogEnabled:         true,
+		Enabled:            true,
+		QueryStatsEnabled:  false,
+		RunInterval:        toml.Duration(DefaultRunInterval),
+		BackfillDelay:      toml.Duration(DefaultBackfillDelay),
+		MaxLateReruns:      DefaultMaxLateReruns,
+		RunHistoryInterval: toml.Duration(DefaultRunHistoryInterval),
 	}
 }
 
@@ -55,6 +87,20 @@ func (c Config) Validate() error {
 		return errors.New("run-interval must be positive")
 	}
 
+	if c.BackfillDelay < 0 {
+		return errors.New("backfill-delay must not be negative")
+	}
+
+	if c.LateDataHorizon < 0 {
+		return errors.New("late-data-horizon must not be negative")
+	} else if c.LateDataHorizon > 0 && c.MaxLateReruns <= 0 {
+		return errors.New("max-late-reruns must be positive")
+	}
+
+	if c.RunHistoryInterval <= 0 {
+		return errors.New("run-history-interval must be positive")
+	}
+
 	return nil
 }
 
@@ -67,8 +113,12 @@ func (c Config) Diagnostics() (*diagnostics.Diagnostics, error) {
 	}
 
 	return diagnostics.RowFromMap(map[string]interface{}{
-		"enabled":             true,
-		"query-stats-enabled": c.QueryStatsEnabled,
-		"run-interval":        c.RunInterval,
+		"enabled":              true,
+		"query-stats-enabled":  c.QueryStatsEnabled,
+		"run-interval":         c.RunInterval,
+		"backfill-delay":       c.BackfillDelay,
+		"late-data-horizon":    c.LateDataHorizon,
+		"max-late-reruns":      c.MaxLateReruns,
+		"run-history-interval": c.RunHistoryInterval,
 	}), nil
 }
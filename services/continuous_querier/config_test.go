@@ -14,6 +14,10 @@ func TestConfig_Parse(t *testing.T) {
 	if _, err := toml.Decode(`
 run-interval = "1m"
 enabled = true
+backfill-delay = "1s"
+late-data-horizon = "24h"
+max-late-reruns = 10
+run-history-interval = "5m"
 `, &c); err != nil {
 		t.Fatal(err)
 	}
@@ -23,6 +27,14 @@ enabled = true
 		t.Fatalf("unexpected run interval: %v", c.RunInterval)
 	} else if !c.Enabled {
 		t.Fatalf("unexpected enabled: %v", c.Enabled)
+	} else if time.Duration(c.BackfillDelay) != time.Second {
+		t.Fatalf("unexpected backfill delay: %v", c.BackfillDelay)
+	} else if time.Duration(c.LateDataHorizon) != 24*time.Hour {
+		t.Fatalf("unexpected late data horizon: %v", c.LateDataHorizon)
+	} else if c.MaxLateReruns != 10 {
+		t.Fatalf("unexpected max late reruns: %v", c.MaxLateReruns)
+	} else if time.Duration(c.RunHistoryInterval) != 5*time.Minute {
+		t.Fatalf("unexpected run history interval: %v", c.RunHistoryInterval)
 	}
 }
 
@@ -43,4 +55,29 @@ func TestConfig_Validate(t *testing.T) {
 	if err := c.Validate(); err == nil {
 		t.Fatal("expected error for negative run-interval, got nil")
 	}
+
+	c = continuous_querier.NewConfig()
+	c.BackfillDelay = -1
+	if err := c.Validate(); err == nil {
+		t.Fatal("expected error for negative backfill-delay, got nil")
+	}
+
+	c = continuous_querier.NewConfig()
+	c.LateDataHorizon = -1
+	if err := c.Validate(); err == nil {
+		t.Fatal("expected error for negative late-data-horizon, got nil")
+	}
+
+	c = continuous_querier.NewConfig()
+	c.LateDataHorizon = c.RunInterval
+	c.MaxLateReruns = 0
+	if err := c.Validate(); err == nil {
+		t.Fatal("expected error for max-late-reruns = 0, got nil")
+	}
+
+	c = continuous_querier.NewConfig()
+	c.RunHistoryInterval = 0
+	if err := c.Validate(); err == nil {
+		t.Fatal("expected error for run-history-interval = 0, got nil")
+	}
 }
@@ -0,0 +1,175 @@
+package continuous_querier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/ayang64/reflux/influxql"
+	"github.com/ayang64/reflux/logger"
+	"github.com/ayang64/reflux/models"
+	"github.com/ayang64/reflux/services/meta"
+	"go.uber.org/zap"
+)
+
+// runDownsamplePolicies runs the downsample policies of every database.
+func (s *Service) runDownsamplePolicies(ctx context.Context, now time.Time) {
+	for _, db := range s.MetaClient.Databases() {
+		for i := range db.DownsamplePolicies {
+			dpi := &db.DownsamplePolicies[i]
+			if ok, err := s.ExecuteDownsamplePolicy(ctx, db.Name, dpi, now); err != nil {
+				s.Logger.Info("Error executing downsample policy",
+					zap.String("name", dpi.Name),
+					logger.Database(db.Name),
+					zap.Error(err))
+				atomic.AddInt64(&s.stats.DownsampleFail, 1)
+			} else if ok {
+				atomic.AddInt64(&s.stats.DownsampleOK, 1)
+			}
+		}
+	}
+}
+
+// ExecuteDownsamplePolicy may roll up the windows of a downsample policy that
+// ended since it last ran. The first run rolls up the last window that ended
+// before now. This will return false if there were no errors and the policy
+// was not run.
+func (s *Service) ExecuteDownsamplePolicy(ctx context.Context, database string, dpi *meta.DownsamplePolicyInfo, now time.Time) (bool, error) {
+	if dpi.Interval <= 0 {
+		return false, errors.New("downsample policy interval must be positive")
+	}
+	interval := span{d: dpi.Interval}
+	end := truncate(now.UTC(), interval)
+
+	// Determine the windows that ended since the last run.
+	id := fmt.Sprintf("%s%s%s", database, idDelimiter, dpi.Name)
+	s.mu.Lock()
+	start, hasRun := s.lastDownsampleRuns[id]
+	if hasRun && !end.After(start) {
+		s.mu.Unlock()
+		return false, nil
+	} else if !hasRun {
+		start = interval.add(end, -1)
+	}
+	s.lastDownsampleRuns[id] = end
+	s.mu.Unlock()
+
+	var (
+		began   = time.Now()
+		written int64
+	)
+	name := fmt.Sprintf("downsample policy %s on %s", dpi.Name, database)
+	err := s.Tasks.Run(ctx, name, func(ctx context.Context) error {
+		stmts, err := s.downsampleStatements(ctx, database, dpi)
+		if err != nil {
+			return err
+		}
+
+		for _, stmt := range stmts {
+			if err := stmt.SetTimeRange(start, end); err != nil {
+				return fmt.Errorf("unable to set time range: %s", err)
+			}
+			res := s.runStatement(ctx, database, stmt)
+			if res.Err != nil {
+				return res.Err
+			}
+			if n := pointsWritten(res); n > 0 {
+				written += n
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+
+	if s.loggingEnabled {
+		s.Logger.Info("Finished downsample policy",
+			zap.String("name", dpi.Name),
+			logger.Database(database),
+			zap.Int64("written", written),
+			zap.Time("start", start),
+			zap.Time("end", end),
+			logger.DurationLiteral("duration", time.Since(began)))
+	}
+	return true, nil
+}
+
+// downsampleStatements returns a statement for every measurement in the
+// source retention policy of the downsample policy. The measurements and
+// their fields are looked up every time so that new ones are rolled up as
+// soon as they are written.
+func (s *Service) downsampleStatements(ctx context.Context, database string, dpi *meta.DownsamplePolicyInfo) ([]*influxql.SelectStatement, error) {
+	res := s.runStatement(ctx, database, &influxql.ShowFieldKeysStatement{
+		Database: database,
+		Sources: influxql.Sources{&influxql.Measurement{
+			Database:        database,
+			RetentionPolicy: dpi.SourceRetentionPolicy,
+			Regex:           &influxql.RegexLiteral{Val: regexp.MustCompile(`.`)},
+		}},
+	})
+	if res.Err != nil {
+		return nil, res.Err
+	}
+
+	stmts := make([]*influxql.SelectStatement, 0, len(res.Series))
+	for _, row := range res.Series {
+		stmt, err := newDownsampleStatement(database, dpi, row)
+		if err != nil {
+			return nil, err
+		} else if stmt != nil {
+			stmts = append(stmts, stmt)
+		}
+	}
+	return stmts, nil
+}
+
+// newDownsampleStatement returns the statement that rolls up the measurement
+// described by a row of SHOW FIELD KEYS. Numeric fields are rolled up with the
+// functions of the policy and all other fields use last. The result of each
+// function is written to a field named after the function and the field, the
+// same way a wildcard call names its fields.
+func newDownsampleStatement(database string, dpi *meta.DownsamplePolicyInfo, row *models.Row) (*influxql.SelectStatement, error) {
+	functions := dpi.Functions
+	if len(functions) == 0 {
+		functions = influxql.DefaultDownsampleFunctions
+	}
+
+	var fields []string
+	seen := make(map[string]bool)
+	for _, v := range row.Values {
+		key, _ := v[0].(string)
+		typ, _ := v[1].(string)
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		fns := []string{"last"}
+		switch influxql.DataTypeFromString(typ) {
+		case influxql.Float, influxql.Integer, influxql.Unsigned:
+			fns = functions
+		}
+		for _, fn := range fns {
+			fields = append(fields, fmt.Sprintf("%s(%s) AS %s", fn, influxql.QuoteIdent(key), influxql.QuoteIdent(fn+"_"+key)))
+		}
+	}
+	if len(fields) == 0 {
+		return nil, nil
+	}
+
+	q := fmt.Sprintf("SELECT %s INTO %s FROM %s GROUP BY time(%s), *",
+		strings.Join(fields, ", "),
+		influxql.QuoteIdent(database, dpi.TargetRetentionPolicy, row.Name),
+		influxql.QuoteIdent(database, dpi.SourceRetentionPolicy, row.Name),
+		influxql.FormatDuration(dpi.Interval))
+	stmt, err := influxql.ParseStatement(q)
+	if err != nil {
+		return nil, err
+	}
+	return stmt.(*influxql.SelectStatement), nil
+}
@@ -10,11 +10,13 @@ import (
 	"sync/atomic"
 	"time"
 
+	"github.com/ayang64/reflux/coordinator"
 	"github.com/ayang64/reflux/influxql"
 	"github.com/ayang64/reflux/logger"
 	"github.com/ayang64/reflux/models"
 	"github.com/ayang64/reflux/query"
 	"github.com/ayang64/reflux/services/meta"
+	"github.com/ayang64/reflux/task"
 	"go.uber.org/zap"
 )
 
@@ -31,8 +33,13 @@ const (
 
 // Statistics for the CQ service.
 const (
-	statQueryOK   = "queryOk"
-	statQueryFail = "queryFail"
+	statQueryOK        = "queryOk"
+	statQueryFail      = "queryFail"
+	statLateRerunOK    = "lateRerunOk"
+	statLateRerunFail  = "lateRerunFail"
+	statLateRerunDrop  = "lateRerunDrop"
+	statDownsampleOK   = "downsampleOk"
+	statDownsampleFail = "downsampleFail"
 )
 
 // ContinuousQuerier represents a service that executes continuous queries.
@@ -46,6 +53,7 @@ type metaClient interface {
 	AcquireLease(name string) (l *meta.Lease, err error)
 	Databases() []meta.DatabaseInfo
 	Database(name string) *meta.DatabaseInfo
+	SetContinuousQueryRuns(runs map[string]map[string]meta.ContinuousQueryRunInfo) error
 }
 
 // RunRequest is a request to run one or more CQs.
@@ -70,6 +78,13 @@ func (rr *RunRequest) matches(cq *meta.ContinuousQueryInfo) bool {
 	return false
 }
 
+// LateWrites tracks the points that are written behind the windows the
+// service has already computed.
+type LateWrites interface {
+	SetWatermark(database string, t time.Time)
+	Drain() []coordinator.DirtyRange
+}
+
 type Monitor interface {
 	Enabled() bool
 	WritePoints(models.Points) error
@@ -87,6 +102,8 @@ type Service struct {
 	Monitor       Monitor
 	Config        *Config
 	RunInterval   time.Duration
+	Tasks         *task.Manager // tracks CQ runs so they can be listed and killed
+	LateWrites    LateWrites    // re-runs windows that receive late points if set
 	// RunCh can be used by clients to signal service to run CQs.
 	RunCh             chan *RunRequest
 	Logger            *zap.Logger
@@ -96,21 +113,40 @@ type Service struct {
 	// lastRuns maps CQ name to last time it was run.
 	mu       sync.RWMutex
 	lastRuns map[string]time.Time
-	stop     chan struct{}
+	// watermarks maps CQ name to the time before which its windows are only
+	// computed again for late points.
+	watermarks map[string]time.Time
+	// lateReruns holds the windows queued to be re-run for late points.
+	lateReruns []lateRerun
+	// lastDownsampleRuns maps downsample policies to the end of the last
+	// window they rolled up.
+	lastDownsampleRuns map[string]time.Time
+	stop               chan struct{}
+
+	// RunHistoryInterval is how often the runs of CQs are saved in the meta
+	// store, set from the run-history-interval of the config. Until then the
+	// latest run of each CQ is only kept in memory.
+	RunHistoryInterval time.Duration
+	runMu              sync.Mutex
+	runs               map[string]map[string]meta.ContinuousQueryRunInfo
 }
 
 // NewService returns a new instance of Service.
 func NewService(c Config) *Service {
 	s := &Service{
-		Config:            &c,
-		Monitor:           nullMonitor(0),
-		RunInterval:       time.Duration(c.RunInterval),
-		RunCh:             make(chan *RunRequest),
-		loggingEnabled:    c.LogEnabled,
-		queryStatsEnabled: c.QueryStatsEnabled,
-		Logger:            zap.NewNop(),
-		stats:             &Statistics{},
-		lastRuns:          map[string]time.Time{},
+		Config:             &c,
+		Monitor:            nullMonitor(0),
+		RunInterval:        time.Duration(c.RunInterval),
+		RunCh:              make(chan *RunRequest),
+		loggingEnabled:     c.LogEnabled,
+		queryStatsEnabled:  c.QueryStatsEnabled,
+		Logger:             zap.NewNop(),
+		stats:              &Statistics{},
+		lastRuns:           map[string]time.Time{},
+		watermarks:         map[string]time.Time{},
+		lastDownsampleRuns: map[string]time.Time{},
+		RunHistoryInterval: time.Duration(c.RunHistoryInterval),
+		runs:               map[string]map[string]meta.ContinuousQueryRunInfo{},
 	}
 
 	return s
@@ -137,8 +173,13 @@ func (s *Service) WithLogger(log *zap.Logger) {
 
 // Statistics maintains the statistics for the continuous query service.
 type Statistics struct {
-	QueryOK   int64
-	QueryFail int64
+	QueryOK        int64
+	QueryFail      int64
+	LateRerunOK    int64
+	LateRerunFail  int64
+	LateRerunDrop  int64
+	DownsampleOK   int64
+	DownsampleFail int64
 }
 
 // Statistics returns statistics for periodic monitoring.
@@ -147,8 +188,13 @@ func (s *Service) Statistics(tags map[string]string) []models.Statistic {
 		Name: "cq",
 		Tags: tags,
 		Values: map[string]interface{}{
-			statQueryOK:   atomic.LoadInt64(&s.stats.QueryOK),
-			statQueryFail: atomic.LoadInt64(&s.stats.QueryFail),
+			statQueryOK:        atomic.LoadInt64(&s.stats.QueryOK),
+			statQueryFail:      atomic.LoadInt64(&s.stats.QueryFail),
+			statLateRerunOK:    atomic.LoadInt64(&s.stats.LateRerunOK),
+			statLateRerunFail:  atomic.LoadInt64(&s.stats.LateRerunFail),
+			statLateRerunDrop:  atomic.LoadInt64(&s.stats.LateRerunDrop),
+			statDownsampleOK:   atomic.LoadInt64(&s.stats.DownsampleOK),
+			statDownsampleFail: atomic.LoadInt64(&s.stats.DownsampleFail),
 		},
 	}}
 }
@@ -194,12 +240,17 @@ func (s *Service) backgroundLoop(ctx context.Context) error {
 	leaseName := "continuous_querier"
 	t := time.NewTimer(s.RunInterval)
 	defer t.Stop()
+	history := time.NewTicker(s.RunHistoryInterval)
+	defer history.Stop()
 
 	for {
 		select {
 		case <-ctx.Done():
 			s.Logger.Info("Terminating continuous query service")
+			s.saveRuns()
 			return ctx.Err()
+		case <-history.C:
+			s.saveRuns()
 		case req := <-s.RunCh:
 			if !s.hasContinuousQueries() {
 				continue
@@ -214,20 +265,23 @@ func (s *Service) backgroundLoop(ctx context.Context) error {
 				continue
 			}
 			if _, err := s.MetaClient.AcquireLease(leaseName); err == nil {
-				s.runContinuousQueries(&RunRequest{Now: time.Now()})
+				now := time.Now()
+				s.runContinuousQueries(&RunRequest{Now: now})
+				s.runLateReruns(ctx, now)
+				s.runDownsamplePolicies(ctx, now)
 			}
 			t.Reset(s.RunInterval)
 		}
 	}
 }
 
-// hasContinuousQueries returns true if any CQs exist.
+// hasContinuousQueries returns true if any CQs or downsample policies exist.
 func (s *Service) hasContinuousQueries() bool {
 	// Get list of all databases.
 	dbs := s.MetaClient.Databases()
 	// Loop through all databases executing CQs.
 	for _, db := range dbs {
-		if len(db.ContinuousQueries) > 0 {
+		if len(db.ContinuousQueries) > 0 || len(db.DownsamplePolicies) > 0 {
 			return true
 		}
 	}
@@ -284,10 +338,16 @@ func (s *Service) ExecuteContinuousQuery(dbi *meta.DatabaseInfo, cqi *meta.Conti
 	}
 
 	// Get the group by interval.
-	interval, err := cq.q.GroupByInterval()
+	d, err := cq.q.GroupByInterval()
+	if err != nil {
+		return false, err
+	}
+	months, err := cq.q.GroupByMonths()
 	if err != nil {
 		return false, err
-	} else if interval == 0 {
+	}
+	interval := span{months: months, d: d, weeks: cq.q.GroupByCalendarWeeks()}
+	if interval.isZero() {
 		return false, nil
 	}
 
@@ -305,10 +365,7 @@ func (s *Service) ExecuteContinuousQuery(dbi *meta.DatabaseInfo, cqi *meta.Conti
 		return false, nil
 	}
 
-	resampleEvery := interval
-	if cq.Resample.Every != 0 {
-		resampleEvery = cq.Resample.Every
-	}
+	resampleEvery := cq.Resample.every(interval)
 
 	// We're about to run the query so store the current time closest to the nearest interval.
 	// If all is going well, this time should be the same as nextRun.
@@ -320,21 +377,21 @@ func (s *Service) ExecuteContinuousQuery(dbi *meta.DatabaseInfo, cqi *meta.Conti
 	// time intervals were missed. The start time of the oldest interval is what
 	// we use as the start time.
 	resampleFor := interval
-	if cq.Resample.For != 0 {
-		resampleFor = cq.Resample.For
-	} else if interval < resampleEvery {
+	if cq.Resample.For != 0 || cq.Resample.ForMonths != 0 {
+		resampleFor = span{months: cq.Resample.ForMonths, d: cq.Resample.For}
+	} else if interval.less(resampleEvery) {
 		resampleFor = resampleEvery
 	}
 
 	// If the resample interval is greater than the interval of the query, use the
 	// query interval instead.
-	if interval < resampleEvery {
+	if interval.less(resampleEvery) {
 		resampleEvery = interval
 	}
 
 	// Calculate and set the time range for the query.
-	startTime := truncate(nextRun.Add(interval-resampleFor-offset-1), interval).Add(offset)
-	endTime := truncate(now.Add(interval-resampleEvery-offset), interval).Add(offset)
+	startTime := truncate(resampleFor.add(interval.add(nextRun.Add(-offset-1), 1), -1), interval).Add(offset)
+	endTime := truncate(resampleEvery.add(interval.add(now.Add(-offset), 1), -1), interval).Add(offset)
 	if !endTime.After(startTime) {
 		// Exit early since there is no time interval.
 		return false, nil
@@ -345,12 +402,9 @@ func (s *Service) ExecuteContinuousQuery(dbi *meta.DatabaseInfo, cqi *meta.Conti
 	}
 
 	var (
-		start time.Time
+		start = time.Now()
 		log   = s.Logger
 	)
-	if s.loggingEnabled || s.queryStatsEnabled {
-		start = time.Now()
-	}
 
 	if s.loggingEnabled {
 		var logEnd func()
@@ -366,21 +420,21 @@ func (s *Service) ExecuteContinuousQuery(dbi *meta.DatabaseInfo, cqi *meta.Conti
 
 	// Do the actual processing of the query & writing of results.
 	res := s.runContinuousQueryAndWriteResult(cq)
+	execDuration := time.Since(start)
 	if res.Err != nil {
+		s.recordRun(cq, start, execDuration, 0, res.Err)
 		return false, res.Err
 	}
 
-	var execDuration time.Duration
-	if s.loggingEnabled || s.queryStatsEnabled {
-		execDuration = time.Since(start)
-	}
+	written := pointsWritten(res)
+	s.recordRun(cq, start, execDuration, written, nil)
 
-	// extract number of points written from SELECT ... INTO result
-	var written int64 = -1
-	if len(res.Series) == 1 && len(res.Series[0].Values) == 1 {
-		s := res.Series[0]
-		written = s.Values[0][1].(int64)
+	// Points that arrive before the range of the next run are late.
+	watermark := truncate(resampleFor.add(cq.Resample.every(interval).add(endTime, 1), -1).Add(-offset), interval).Add(offset)
+	if watermark.After(endTime) {
+		watermark = endTime
 	}
+	s.setWatermark(id, cq, watermark)
 
 	if s.loggingEnabled {
 		log.Info("Finished continuous query",
@@ -402,19 +456,358 @@ func (s *Service) ExecuteContinuousQuery(dbi *meta.DatabaseInfo, cqi *meta.Conti
 	return true, nil
 }
 
+// Backfill runs the named continuous query once for every GROUP BY time
+// window from the window containing start up to end. The windows are run in
+// order with the configured delay between them. It returns the number of
+// windows that were run and the number of points that were written. The
+// backfill stops early if ctx is canceled or the backfill task is killed.
+func (s *Service) Backfill(ctx context.Context, database, name string, start, end time.Time) (int, int64, error) {
+	cq, err := s.lookupContinuousQuery(database, name)
+	if err != nil {
+		return 0, 0, err
+	}
+	interval, offset, err := cq.windowing()
+	if err != nil {
+		return 0, 0, err
+	}
+	windowStart, _ := window(start.In(cq.location()), interval, offset)
+
+	var (
+		windows int
+		written int64
+		began   = time.Now()
+		delay   = time.Duration(s.Config.BackfillDelay)
+	)
+	taskName := fmt.Sprintf("backfill continuous query %s on %s", cq.Info.Name, cq.Database)
+	err = s.Tasks.Run(ctx, taskName, func(ctx context.Context) error {
+		for windowStart.Before(end) {
+			_, windowEnd := window(windowStart, interval, offset)
+			if err := cq.q.SetTimeRange(windowStart, windowEnd); err != nil {
+				return fmt.Errorf("unable to set time range: %s", err)
+			}
+
+			res := s.runQuery(ctx, cq)
+			if res.Err != nil {
+				return res.Err
+			}
+			if n := pointsWritten(res); n > 0 {
+				written += n
+			}
+			windows++
+			windowStart = windowEnd
+
+			// Throttle the backfill between windows.
+			if delay > 0 && windowStart.Before(end) {
+				timer := time.NewTimer(delay)
+				select {
+				case <-ctx.Done():
+					timer.Stop()
+					return ctx.Err()
+				case <-timer.C:
+				}
+			} else if err := ctx.Err(); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	s.recordRun(cq, began, time.Since(began), written, err)
+
+	if s.loggingEnabled {
+		s.Logger.Info("Finished continuous query backfill",
+			zap.String("name", cq.Info.Name),
+			logger.Database(cq.Database),
+			zap.Int("windows", windows),
+			zap.Int64("written", written),
+			zap.Error(err))
+	}
+	return windows, written, err
+}
+
+// lookupContinuousQuery returns the named continuous query with its INTO
+// retention policy resolved so that it can be run over any time range.
+func (s *Service) lookupContinuousQuery(database, name string) (*ContinuousQuery, error) {
+	dbi := s.MetaClient.Database(database)
+	if dbi == nil {
+		return nil, query.ErrDatabaseNotFound(database)
+	}
+
+	var cqi *meta.ContinuousQueryInfo
+	for i := range dbi.ContinuousQueries {
+		if dbi.ContinuousQueries[i].Name == name {
+			cqi = &dbi.ContinuousQueries[i]
+			break
+		}
+	}
+	if cqi == nil {
+		return nil, meta.ErrContinuousQueryNotFound
+	}
+
+	cq, err := NewContinuousQuery(dbi.Name, cqi)
+	if err != nil {
+		return nil, err
+	} else if cq.q.IsRawQuery {
+		return nil, errors.New("continuous queries must be aggregate queries")
+	}
+
+	// Set the retention policy to default if it wasn't specified in the query.
+	if cq.intoRP() == "" {
+		cq.setIntoRP(dbi.DefaultRetentionPolicy)
+	}
+	return cq, nil
+}
+
+// lateRerun is a window of a continuous query queued to be re-run because
+// points arrived in it after it was computed.
+type lateRerun struct {
+	database   string
+	name       string
+	start, end time.Time
+}
+
+// setWatermark records the time before which the CQ will not compute windows
+// again on its own and moves the watermark of the databases it reads from. Points that arrive
+// before the watermark cause their windows to be re-run. s.mu must be held.
+func (s *Service) setWatermark(id string, cq *ContinuousQuery, t time.Time) {
+	if s.LateWrites == nil || s.Config.LateDataHorizon <= 0 {
+		return
+	}
+	s.watermarks[id] = t
+	for _, db := range cq.sourceDatabases() {
+		s.LateWrites.SetWatermark(db, t)
+	}
+}
+
+// runLateReruns queues the windows that received late points since the last
+// call and re-runs every queued window.
+func (s *Service) runLateReruns(ctx context.Context, now time.Time) {
+	if s.LateWrites == nil || s.Config.LateDataHorizon <= 0 {
+		return
+	}
+	s.queueLateReruns(s.LateWrites.Drain(), now)
+
+	reruns := s.lateReruns
+	s.lateReruns = nil
+	for _, r := range reruns {
+		if err := s.rerunWindow(ctx, r); err != nil {
+			s.Logger.Info("Error re-running continuous query",
+				zap.String("name", r.name),
+				logger.Database(r.database),
+				zap.Time("start", r.start),
+				zap.Time("end", r.end),
+				zap.Error(err))
+			atomic.AddInt64(&s.stats.LateRerunFail, 1)
+		} else {
+			atomic.AddInt64(&s.stats.LateRerunOK, 1)
+		}
+	}
+}
+
+// queueLateReruns queues the windows of every CQ reading from the dirty
+// ranges that were computed before the points arrived. Points older than the
+// late data horizon are ignored and windows past the queue limit are dropped.
+func (s *Service) queueLateReruns(ranges []coordinator.DirtyRange, now time.Time) {
+	if len(ranges) == 0 {
+		return
+	}
+	horizon := now.Add(-time.Duration(s.Config.LateDataHorizon))
+
+	for _, db := range s.MetaClient.Databases() {
+		for i := range db.ContinuousQueries {
+			cq, err := NewContinuousQuery(db.Name, &db.ContinuousQueries[i])
+			if err != nil {
+				continue
+			}
+			interval, offset, err := cq.windowing()
+			if err != nil {
+				continue
+			}
+
+			id := fmt.Sprintf("%s%s%s", db.Name, idDelimiter, cq.Info.Name)
+			s.mu.RLock()
+			watermark, ok := s.watermarks[id]
+			s.mu.RUnlock()
+			if !ok {
+				continue
+			}
+
+			for _, r := range ranges {
+				if !cq.readsFrom(r.Database, r.Measurement) || r.Max.Before(horizon) {
+					continue
+				}
+				min := r.Min
+				if min.Before(horizon) {
+					min = horizon
+				}
+
+				start, end := window(min.In(cq.location()), interval, offset)
+				for start.Before(watermark) && !start.After(r.Max) {
+					s.queueLateRerun(lateRerun{database: db.Name, name: cq.Info.Name, start: start, end: end})
+					start, end = window(end, interval, offset)
+				}
+			}
+		}
+	}
+}
+
+// queueLateRerun adds the window to the queue unless it is already queued or
+// the queue is full.
+func (s *Service) queueLateRerun(r lateRerun) {
+	for _, q := range s.lateReruns {
+		if q.database == r.database && q.name == r.name && q.start.Equal(r.start) {
+			return
+		}
+	}
+	if len(s.lateReruns) >= s.Config.MaxLateReruns {
+		atomic.AddInt64(&s.stats.LateRerunDrop, 1)
+		return
+	}
+	s.lateReruns = append(s.lateReruns, r)
+}
+
+// rerunWindow runs the continuous query over a single window again.
+func (s *Service) rerunWindow(ctx context.Context, r lateRerun) error {
+	cq, err := s.lookupContinuousQuery(r.database, r.name)
+	if err != nil {
+		return err
+	}
+	if err := cq.q.SetTimeRange(r.start, r.end); err != nil {
+		return fmt.Errorf("unable to set time range: %s", err)
+	}
+
+	var res *query.Result
+	name := fmt.Sprintf("re-run continuous query %s on %s", cq.Info.Name, cq.Database)
+	if err := s.Tasks.Run(ctx, name, func(ctx context.Context) error {
+		res = s.runQuery(ctx, cq)
+		return res.Err
+	}); err != nil {
+		return err
+	}
+
+	if s.loggingEnabled {
+		s.Logger.Info("Re-ran continuous query for late data",
+			zap.String("name", cq.Info.Name),
+			logger.Database(cq.Database),
+			zap.Int64("written", pointsWritten(res)),
+			zap.Time("start", r.start),
+			zap.Time("end", r.end))
+	}
+	return nil
+}
+
+// recordRun keeps the outcome of a run of the continuous query until the runs
+// are saved in the meta store.
+func (s *Service) recordRun(cq *ContinuousQuery, start time.Time, d time.Duration, written int64, err error) {
+	run := meta.ContinuousQueryRunInfo{
+		Time:     start.UTC(),
+		Duration: d,
+	}
+	if written > 0 {
+		run.PointsWritten = written
+	}
+	if err != nil {
+		run.Err = err.Error()
+	}
+
+	s.runMu.Lock()
+	defer s.runMu.Unlock()
+	if s.runs[cq.Database] == nil {
+		s.runs[cq.Database] = map[string]meta.ContinuousQueryRunInfo{}
+	}
+	s.runs[cq.Database][cq.Info.Name] = run
+}
+
+// LastRun returns the most recent run of the continuous query if it was not
+// saved in the meta store yet.
+func (s *Service) LastRun(database, name string) (meta.ContinuousQueryRunInfo, bool) {
+	s.runMu.Lock()
+	defer s.runMu.Unlock()
+	run, ok := s.runs[database][name]
+	return run, ok
+}
+
+// saveRuns saves the runs kept in memory in the meta store in one update.
+func (s *Service) saveRuns() {
+	s.runMu.Lock()
+	runs := s.runs
+	s.runs = map[string]map[string]meta.ContinuousQueryRunInfo{}
+	s.runMu.Unlock()
+	if len(runs) == 0 {
+		return
+	}
+
+	if err := s.MetaClient.SetContinuousQueryRuns(runs); err != nil {
+		s.Logger.Info("Unable to record continuous query runs", zap.Error(err))
+
+		// Keep the runs to save them with the next update unless the CQs
+		// ran again in the meantime.
+		s.runMu.Lock()
+		defer s.runMu.Unlock()
+		for database, cqs := range runs {
+			for name, run := range cqs {
+				if _, ok := s.runs[database][name]; ok {
+					continue
+				}
+				if s.runs[database] == nil {
+					s.runs[database] = map[string]meta.ContinuousQueryRunInfo{}
+				}
+				s.runs[database][name] = run
+			}
+		}
+	}
+}
+
+// pointsWritten extracts the number of points written from the result of a
+// SELECT ... INTO statement. It returns -1 if the result does not contain it.
+func pointsWritten(res *query.Result) int64 {
+	if len(res.Series) == 1 && len(res.Series[0].Values) == 1 {
+		if n, ok := res.Series[0].Values[0][1].(int64); ok {
+			return n
+		}
+	}
+	return -1
+}
+
 // runContinuousQueryAndWriteResult will run the query against the cluster and write the results back in
 func (s *Service) runContinuousQueryAndWriteResult(cq *ContinuousQuery) *query.Result {
-	// Wrap the CQ's inner SELECT statement in a Query for the Executor.
+	var res *query.Result
+	name := fmt.Sprintf("continuous query %s on %s", cq.Info.Name, cq.Database)
+	s.Tasks.Run(context.Background(), name, func(ctx context.Context) error {
+		res = s.runQuery(ctx, cq)
+		return res.Err
+	})
+	return res
+}
+
+// runQuery executes the CQ's inner SELECT statement. The query is interrupted
+// when ctx is canceled.
+func (s *Service) runQuery(ctx context.Context, cq *ContinuousQuery) *query.Result {
+	return s.runStatement(ctx, cq.Database, cq.q)
+}
+
+// runStatement executes a single statement against the database. The
+// statement is interrupted when ctx is canceled.
+func (s *Service) runStatement(ctx context.Context, database string, stmt influxql.Statement) *query.Result {
+	// Wrap the statement in a Query for the Executor.
 	q := &influxql.Query{
-		Statements: influxql.Statements([]influxql.Statement{cq.q}),
+		Statements: influxql.Statements([]influxql.Statement{stmt}),
 	}
 
+	// Interrupt the query if the context is canceled.
 	closing := make(chan struct{})
-	defer close(closing)
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-done:
+		}
+		close(closing)
+	}()
 
-	// Execute the SELECT.
+	// Execute the statement.
 	ch := s.QueryExecutor.ExecuteQuery(q, query.ExecutionOptions{
-		Database: cq.Database,
+		Database: database,
 	}, closing)
 
 	// There is only one statement, so we will only ever receive one result
@@ -438,6 +831,73 @@ type ContinuousQuery struct {
 func (cq *ContinuousQuery) intoRP() string      { return cq.q.Target.Measurement.RetentionPolicy }
 func (cq *ContinuousQuery) setIntoRP(rp string) { cq.q.Target.Measurement.RetentionPolicy = rp }
 
+// windowing returns the GROUP BY time interval and offset of the query.
+func (cq *ContinuousQuery) windowing() (span, time.Duration, error) {
+	d, err := cq.q.GroupByInterval()
+	if err != nil {
+		return span{}, 0, err
+	}
+	months, err := cq.q.GroupByMonths()
+	if err != nil {
+		return span{}, 0, err
+	}
+	interval := span{months: months, d: d, weeks: cq.q.GroupByCalendarWeeks()}
+	if interval.isZero() {
+		return span{}, 0, errors.New("continuous query must have a GROUP BY time interval")
+	}
+	offset, err := cq.q.GroupByOffset()
+	if err != nil {
+		return span{}, 0, err
+	}
+	return interval, offset, nil
+}
+
+// location returns the time zone the windows of the query are aligned in.
+func (cq *ContinuousQuery) location() *time.Location {
+	if cq.q.Location != nil {
+		return cq.q.Location
+	}
+	return time.UTC
+}
+
+// sourceDatabases returns the databases the query reads from.
+func (cq *ContinuousQuery) sourceDatabases() []string {
+	var dbs []string
+	seen := make(map[string]bool)
+	for _, m := range cq.q.Sources.Measurements() {
+		db := m.Database
+		if db == "" {
+			db = cq.Database
+		}
+		if !seen[db] {
+			seen[db] = true
+			dbs = append(dbs, db)
+		}
+	}
+	return dbs
+}
+
+// readsFrom returns true if the query reads from the measurement.
+func (cq *ContinuousQuery) readsFrom(database, measurement string) bool {
+	for _, m := range cq.q.Sources.Measurements() {
+		db := m.Database
+		if db == "" {
+			db = cq.Database
+		}
+		if db != database {
+			continue
+		}
+		if m.Regex != nil {
+			if m.Regex.Val.MatchString(measurement) {
+				return true
+			}
+		} else if m.Name == measurement {
+			return true
+		}
+	}
+	return false
+}
+
 // ResampleOptions controls the resampling intervals and duration of this continuous query.
 type ResampleOptions struct {
 	// The query will be resampled at this time interval. The first query will be
@@ -445,12 +905,54 @@ type ResampleOptions struct {
 	// interval is set to the group by interval.
 	Every time.Duration
 
+	// EveryMonths is the number of calendar months added to Every.
+	EveryMonths int
+
 	// The query will continue being resampled for this time duration. If this
 	// option is not given, the resample duration is the same as the group by
 	// interval. A bucket's time is calculated based on the bucket's start time,
 	// so a 40m resample duration with a group by interval of 10m will resample
 	// the bucket 4 times (using the default time interval).
 	For time.Duration
+
+	// ForMonths is the number of calendar months added to For.
+	ForMonths int
+}
+
+// every returns the resample interval for a query with the interval.
+func (opt ResampleOptions) every(interval span) span {
+	if opt.Every != 0 || opt.EveryMonths != 0 {
+		return span{months: opt.EveryMonths, d: opt.Every}
+	}
+	return interval
+}
+
+// span is a length of time made up of calendar months and a fixed duration.
+// If weeks is set, the duration is a whole number of calendar weeks that
+// start on a Monday.
+type span struct {
+	months int
+	d      time.Duration
+	weeks  bool
+}
+
+func (s span) isZero() bool { return s.months == 0 && s.d == 0 }
+
+// add returns the time n spans after t.
+func (s span) add(t time.Time, n int) time.Time {
+	if s.weeks {
+		return t.AddDate(0, 0, n*int(s.d/(24*time.Hour)))
+	} else if s.months != 0 {
+		t = t.AddDate(0, n*s.months, 0)
+	}
+	return t.Add(time.Duration(n) * s.d)
+}
+
+// less returns true if s is shorter than other. Months are compared
+// using their average length.
+func (s span) less(other span) bool {
+	const month = 730 * time.Hour
+	return time.Duration(s.months)*month+s.d < time.Duration(other.months)*month+other.d
 }
 
 // NewContinuousQuery returns a ContinuousQuery object with a parsed influxql.CreateContinuousQueryStatement.
@@ -469,8 +971,10 @@ func NewContinuousQuery(database string, cqi *meta.ContinuousQueryInfo) (*Contin
 		Database: database,
 		Info:     cqi,
 		Resample: ResampleOptions{
-			Every: q.ResampleEvery,
-			For:   q.ResampleFor,
+			Every:       q.ResampleEvery,
+			EveryMonths: q.ResampleEveryMonths,
+			For:         q.ResampleFor,
+			ForMonths:   q.ResampleForMonths,
 		},
 		q: q.Source,
 	}
@@ -481,28 +985,26 @@ func NewContinuousQuery(database string, cqi *meta.ContinuousQueryInfo) (*Contin
 // shouldRunContinuousQuery returns true if the CQ should be schedule to run. It will use the
 // lastRunTime of the CQ and the rules for when to run set through the query to determine
 // if this CQ should be run.
-func (cq *ContinuousQuery) shouldRunContinuousQuery(now time.Time, interval time.Duration) (bool, time.Time, error) {
+func (cq *ContinuousQuery) shouldRunContinuousQuery(now time.Time, interval span) (bool, time.Time, error) {
 	// If it's not aggregated, do not run the query.
 	if cq.q.IsRawQuery {
 		return false, cq.LastRun, errors.New("continuous queries must be aggregate queries")
 	}
 
 	// Override the query's default run interval with the resample options.
-	resampleEvery := interval
-	if cq.Resample.Every != 0 {
-		resampleEvery = cq.Resample.Every
-	}
+	resampleEvery := cq.Resample.every(interval)
 
 	// Determine if we should run the continuous query based on the last time it ran.
 	// If the query never ran, execute it using the current time.
 	if cq.HasRun {
 		// Retrieve the zone offset for the previous window.
 		_, startOffset := cq.LastRun.Add(-1).Zone()
-		nextRun := cq.LastRun.Add(resampleEvery)
+		nextRun := resampleEvery.add(cq.LastRun, 1)
 		// Retrieve the end zone offset for the end of the current interval.
-		if _, endOffset := nextRun.Add(-1).Zone(); startOffset != endOffset {
+		// Calendar months and weeks are added in the location and need no adjustment.
+		if _, endOffset := nextRun.Add(-1).Zone(); startOffset != endOffset && resampleEvery.months == 0 && !resampleEvery.weeks {
 			diff := int64(startOffset-endOffset) * int64(time.Second)
-			if abs(diff) < int64(resampleEvery) {
+			if abs(diff) < int64(resampleEvery.d) {
 				nextRun = nextRun.Add(time.Duration(diff))
 			}
 		}
@@ -530,8 +1032,16 @@ func assert(condition bool, msg string, v ...interface{}) {
 
 // truncate truncates the time based on the unix timestamp instead of the
 // Go time library. The Go time library has the start of the week on Monday
-// while the start of the week for the unix timestamp is a Thursday.
-func truncate(ts time.Time, d time.Duration) time.Time {
+// while the start of the week for the unix timestamp is a Thursday. Calendar
+// months and weeks are truncated in the location of the time.
+func truncate(ts time.Time, s span) time.Time {
+	if s.months != 0 {
+		return influxql.TruncateMonths(ts, s.months)
+	} else if s.weeks {
+		return influxql.TruncateWeeks(ts, int(s.d/(7*24*time.Hour)))
+	}
+
+	d := s.d
 	t := ts.UnixNano()
 	offset := zone(ts)
 	dt := (t + offset) % int64(d)
@@ -550,6 +1060,12 @@ func truncate(ts time.Time, d time.Duration) time.Time {
 	return ts
 }
 
+// window returns the bounds of the GROUP BY time window containing t.
+func window(t time.Time, interval span, offset time.Duration) (time.Time, time.Time) {
+	start := truncate(t.Add(-offset), interval).Add(offset)
+	return start, truncate(interval.add(start, 1).Add(-offset), interval).Add(offset)
+}
+
 func zone(ts time.Time) int64 {
 	_, offset := ts.Zone()
 	return int64(offset) * int64(time.Second)
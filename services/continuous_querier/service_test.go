@@ -1,17 +1,21 @@
 package continuous_querier
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"os"
+	"reflect"
 	"sync"
 	"testing"
 	"time"
 
+	"github.com/ayang64/reflux/coordinator"
 	"github.com/ayang64/reflux/logger"
 	"github.com/ayang64/reflux/models"
 	"github.com/ayang64/reflux/query"
 	"github.com/ayang64/reflux/services/meta"
+	"github.com/ayang64/reflux/toml"
 	"github.com/ayang64/reflux/influxql"
 )
 
@@ -20,20 +24,28 @@ var (
 	errUnexpected = errors.New("unexpected error")
 )
 
-// Test closing never opened, open, open already open, close, and close already closed.
-func TestOpenAndClose(t *testing.T) {
+// Test that the service runs until its context is canceled.
+func TestService_Start(t *testing.T) {
 	s := NewTestService(t)
 
-	if err := s.Close(); err != nil {
-		t.Error(err)
-	} else if err = s.Open(); err != nil {
-		t.Error(err)
-	} else if err = s.Open(); err != nil {
-		t.Error(err)
-	} else if err = s.Close(); err != nil {
-		t.Error(err)
-	} else if err = s.Close(); err != nil {
-		t.Error(err)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- s.Service.Start(ctx) }()
+
+	select {
+	case err := <-done:
+		t.Fatalf("service stopped before its context was canceled: %v", err)
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("service did not stop after its context was canceled")
 	}
 }
 
@@ -415,9 +427,17 @@ func TestExecuteContinuousQuery_TimeRange(t *testing.T) {
 			start: mustParseTime(t, "1999-12-30T00:00:00Z"),
 			end:   mustParseTime(t, "2000-01-06T00:00:00Z"),
 		},
+		{
+			d:     "1cw",
+			start: mustParseTime(t, "1999-12-27T00:00:00Z"),
+			end:   mustParseTime(t, "2000-01-03T00:00:00Z"),
+		},
 	} {
 		t.Run(tt.d, func(t *testing.T) {
 			d, err := influxql.ParseDuration(tt.d)
+			if err != nil {
+				d, err = influxql.ParseCalendarWeeks(tt.d)
+			}
 			if err != nil {
 				t.Fatalf("unable to parse duration: %s", err)
 			}
@@ -605,6 +625,301 @@ func TestExecuteContinuousQuery_QueryExecutor_Error(t *testing.T) {
 	if _, err := s.ExecuteContinuousQuery(&dbi, &cqi, now); err != errExpected {
 		t.Errorf("exp = %s, got = %v", errExpected, err)
 	}
+
+	// The failed run should be recorded.
+	if run, ok := s.LastRun(dbi.Name, cqi.Name); !ok {
+		t.Error("expected the run to be recorded")
+	} else if run.Err != errExpected.Error() {
+		t.Errorf("unexpected run error: exp = %s, got = %s", errExpected, run.Err)
+	}
+}
+
+func TestService_Backfill(t *testing.T) {
+	s := NewTestService(t)
+	mc := NewMetaClient(t)
+	mc.CreateDatabase("db", "")
+	mc.CreateContinuousQuery("db", "cq", `CREATE CONTINUOUS QUERY cq ON db BEGIN SELECT mean(value) INTO cpu_mean FROM cpu GROUP BY time(10m) END`)
+	s.MetaClient = mc
+	s.Config.BackfillDelay = 0
+
+	var ranges [][2]time.Time
+	s.QueryExecutor.StatementExecutor = &StatementExecutor{
+		ExecuteStatementFn: func(stmt influxql.Statement, ctx *query.ExecutionContext) error {
+			s := stmt.(*influxql.SelectStatement)
+			valuer := &influxql.NowValuer{Location: s.Location}
+			_, timeRange, err := influxql.ConditionExpr(s.Condition, valuer)
+			if err != nil {
+				t.Errorf("unexpected error parsing time range: %s", err)
+			}
+			ranges = append(ranges, [2]time.Time{timeRange.Min, timeRange.Max.Add(time.Nanosecond)})
+			ctx.Results <- &query.Result{
+				Series: []*models.Row{{
+					Name:    "result",
+					Columns: []string{"time", "written"},
+					Values:  [][]interface{}{{time.Time{}, int64(5)}},
+				}},
+			}
+			return nil
+		},
+	}
+
+	windows, written, err := s.Backfill(context.Background(), "db", "cq",
+		mustParseTime(t, "2000-01-01T00:05:00Z"), mustParseTime(t, "2000-01-01T00:30:00Z"))
+	if err != nil {
+		t.Fatal(err)
+	} else if windows != 3 {
+		t.Errorf("unexpected windows: exp = 3, got = %d", windows)
+	} else if written != 15 {
+		t.Errorf("unexpected points written: exp = 15, got = %d", written)
+	}
+
+	for i, exp := range [][2]string{
+		{"2000-01-01T00:00:00Z", "2000-01-01T00:10:00Z"},
+		{"2000-01-01T00:10:00Z", "2000-01-01T00:20:00Z"},
+		{"2000-01-01T00:20:00Z", "2000-01-01T00:30:00Z"},
+	} {
+		if i >= len(ranges) {
+			t.Fatalf("missing window %d", i)
+		} else if start, end := mustParseTime(t, exp[0]), mustParseTime(t, exp[1]); !ranges[i][0].Equal(start) || !ranges[i][1].Equal(end) {
+			t.Errorf("mismatched time range: got=(%s, %s) exp=(%s, %s)", ranges[i][0], ranges[i][1], start, end)
+		}
+	}
+
+	if run, _ := s.LastRun("db", "cq"); run.PointsWritten != 15 || run.Err != "" {
+		t.Errorf("unexpected run: %#v", run)
+	}
+
+	if _, _, err := s.Backfill(context.Background(), "db", "nope", time.Unix(0, 0), time.Unix(60, 0)); err != meta.ErrContinuousQueryNotFound {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestService_Backfill_Cancel(t *testing.T) {
+	s := NewTestService(t)
+	mc := NewMetaClient(t)
+	mc.CreateDatabase("db", "")
+	mc.CreateContinuousQuery("db", "cq", `CREATE CONTINUOUS QUERY cq ON db BEGIN SELECT mean(value) INTO cpu_mean FROM cpu GROUP BY time(1m) END`)
+	s.MetaClient = mc
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Cancel the backfill after the first window.
+	s.QueryExecutor.StatementExecutor = &StatementExecutor{
+		ExecuteStatementFn: func(stmt influxql.Statement, ectx *query.ExecutionContext) error {
+			cancel()
+			ectx.Results <- &query.Result{}
+			return nil
+		},
+	}
+
+	windows, _, err := s.Backfill(ctx, "db", "cq",
+		mustParseTime(t, "2000-01-01T00:00:00Z"), mustParseTime(t, "2000-01-02T00:00:00Z"))
+	if err != context.Canceled {
+		t.Errorf("unexpected error: %v", err)
+	} else if windows != 1 {
+		t.Errorf("unexpected windows: exp = 1, got = %d", windows)
+	}
+
+	if run, _ := s.LastRun("db", "cq"); run.Err != context.Canceled.Error() {
+		t.Errorf("unexpected run error: %q", run.Err)
+	}
+}
+
+// Ensure runs kept in memory are saved in the meta store in one update.
+func TestService_SaveRuns(t *testing.T) {
+	s := NewTestService(t)
+	mc := NewMetaClient(t)
+	mc.CreateDatabase("db", "")
+	mc.CreateContinuousQuery("db", "cq", `CREATE CONTINUOUS QUERY cq ON db BEGIN SELECT mean(value) INTO cpu_mean FROM cpu GROUP BY time(1m) END`)
+	s.MetaClient = mc
+
+	cq, err := NewContinuousQuery("db", &mc.Database("db").ContinuousQueries[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := mustParseTime(t, "2000-01-01T00:00:00Z")
+	s.recordRun(cq, start, time.Second, 5, nil)
+
+	// A failed update keeps the runs for the next one.
+	mc.Err = errExpected
+	s.saveRuns()
+	mc.Err = nil
+	if _, ok := s.LastRun("db", "cq"); !ok {
+		t.Fatal("expected the run to be kept")
+	} else if got := mc.Database("db").ContinuousQueries[0].LastRun; !got.Time.IsZero() {
+		t.Fatalf("unexpected saved run: %#v", got)
+	}
+
+	s.saveRuns()
+	if _, ok := s.LastRun("db", "cq"); ok {
+		t.Error("expected the run to be saved")
+	} else if got := mc.Database("db").ContinuousQueries[0].LastRun; got.PointsWritten != 5 || !got.Time.Equal(start) {
+		t.Errorf("unexpected saved run: %#v", got)
+	}
+}
+
+func TestService_LateReruns(t *testing.T) {
+	s := NewTestService(t)
+	mc := NewMetaClient(t)
+	mc.CreateDatabase("db", "")
+	mc.CreateContinuousQuery("db", "cq", `CREATE CONTINUOUS QUERY cq ON db BEGIN SELECT mean(value) INTO cpu_mean FROM cpu GROUP BY time(10m) END`)
+	s.MetaClient = mc
+	s.Config.LateDataHorizon = toml.Duration(24 * time.Hour)
+	s.Config.MaxLateReruns = 2
+	lw := coordinator.NewLateWriteTracker()
+	s.LateWrites = lw
+
+	var ranges [][2]time.Time
+	s.QueryExecutor.StatementExecutor = &StatementExecutor{
+		ExecuteStatementFn: func(stmt influxql.Statement, ctx *query.ExecutionContext) error {
+			s := stmt.(*influxql.SelectStatement)
+			valuer := &influxql.NowValuer{Location: s.Location}
+			_, timeRange, err := influxql.ConditionExpr(s.Condition, valuer)
+			if err != nil {
+				t.Errorf("unexpected error parsing time range: %s", err)
+			}
+			ranges = append(ranges, [2]time.Time{timeRange.Min, timeRange.Max.Add(time.Nanosecond)})
+			ctx.Results <- &query.Result{}
+			return nil
+		},
+	}
+
+	// Compute the windows that ended before now.
+	now := mustParseTime(t, "2000-01-01T01:00:00Z")
+	db := mc.Database("db")
+	if _, err := s.ExecuteContinuousQuery(db, &db.ContinuousQueries[0], now); err != nil {
+		t.Fatal(err)
+	}
+
+	write := func(name, ts string) {
+		lw.Track("db", []models.Point{models.MustNewPoint(name, nil, models.Fields{"value": 1.0}, mustParseTime(t, ts))})
+	}
+	expect := func(exp ...[2]string) {
+		t.Helper()
+		if len(ranges) != len(exp) {
+			t.Fatalf("unexpected number of re-runs: exp = %d, got = %d", len(exp), len(ranges))
+		}
+		for i := range exp {
+			if start, end := mustParseTime(t, exp[i][0]), mustParseTime(t, exp[i][1]); !ranges[i][0].Equal(start) || !ranges[i][1].Equal(end) {
+				t.Errorf("mismatched time range: got=(%s, %s) exp=(%s, %s)", ranges[i][0], ranges[i][1], start, end)
+			}
+		}
+	}
+
+	// Only the late points of the measurement the CQ reads from are re-run
+	// and the windows past the queue limit are dropped.
+	ranges = nil
+	write("cpu", "2000-01-01T00:15:00Z")
+	write("cpu", "2000-01-01T00:45:00Z")
+	write("cpu", "2000-01-01T01:02:00Z")
+	write("mem", "2000-01-01T00:55:00Z")
+	s.runLateReruns(context.Background(), now)
+	expect(
+		[2]string{"2000-01-01T00:10:00Z", "2000-01-01T00:20:00Z"},
+		[2]string{"2000-01-01T00:20:00Z", "2000-01-01T00:30:00Z"},
+	)
+	if s.stats.LateRerunOK != 2 || s.stats.LateRerunDrop != 2 {
+		t.Errorf("unexpected stats: %#v", s.stats)
+	}
+
+	// Points older than the horizon are ignored.
+	ranges = nil
+	s.Config.LateDataHorizon = toml.Duration(30 * time.Minute)
+	write("cpu", "2000-01-01T00:15:00Z")
+	write("cpu", "2000-01-01T00:45:00Z")
+	s.runLateReruns(context.Background(), now)
+	expect(
+		[2]string{"2000-01-01T00:30:00Z", "2000-01-01T00:40:00Z"},
+		[2]string{"2000-01-01T00:40:00Z", "2000-01-01T00:50:00Z"},
+	)
+}
+
+func TestService_ExecuteDownsamplePolicy(t *testing.T) {
+	s := NewTestService(t)
+	dpi := &meta.DownsamplePolicyInfo{
+		Name:                  "dp",
+		SourceRetentionPolicy: "raw",
+		TargetRetentionPolicy: "hourly",
+		Interval:              time.Hour,
+	}
+
+	var queries []string
+	var ranges [][2]time.Time
+	s.QueryExecutor.StatementExecutor = &StatementExecutor{
+		ExecuteStatementFn: func(stmt influxql.Statement, ctx *query.ExecutionContext) error {
+			s := stmt.(*influxql.SelectStatement)
+			if s.Target == nil {
+				// Field keys of the source retention policy.
+				ctx.Results <- &query.Result{
+					Series: []*models.Row{
+						{Name: "cpu", Columns: []string{"fieldKey", "fieldType"}, Values: [][]interface{}{{"value", "float"}, {"host", "string"}}},
+						{Name: "mem", Columns: []string{"fieldKey", "fieldType"}, Values: [][]interface{}{{"free", "integer"}}},
+					},
+				}
+				return nil
+			}
+
+			valuer := &influxql.NowValuer{Location: s.Location}
+			cond, timeRange, err := influxql.ConditionExpr(s.Condition, valuer)
+			if err != nil {
+				t.Errorf("unexpected error parsing time range: %s", err)
+			}
+			s.Condition = cond
+			queries = append(queries, s.String())
+			ranges = append(ranges, [2]time.Time{timeRange.Min, timeRange.Max.Add(time.Nanosecond)})
+			ctx.Results <- &query.Result{}
+			return nil
+		},
+	}
+
+	// The first run rolls up the last window that ended before now.
+	now := mustParseTime(t, "2000-01-01T03:30:00Z")
+	if ok, err := s.ExecuteDownsamplePolicy(context.Background(), "db", dpi, now); err != nil {
+		t.Fatal(err)
+	} else if !ok {
+		t.Fatal("expected downsample policy to run")
+	}
+
+	exp := []string{
+		`SELECT mean(value) AS mean_value, min(value) AS min_value, max(value) AS max_value, count(value) AS count_value, last(host) AS last_host INTO db.hourly.cpu FROM db.raw.cpu GROUP BY time(1h), *`,
+		`SELECT mean(free) AS mean_free, min(free) AS min_free, max(free) AS max_free, count(free) AS count_free INTO db.hourly.mem FROM db.raw.mem GROUP BY time(1h), *`,
+	}
+	if !reflect.DeepEqual(queries, exp) {
+		t.Fatalf("unexpected queries:\n\nexp=%v\n\ngot=%v", exp, queries)
+	}
+	for i := range ranges {
+		if start, end := mustParseTime(t, "2000-01-01T02:00:00Z"), mustParseTime(t, "2000-01-01T03:00:00Z"); !ranges[i][0].Equal(start) || !ranges[i][1].Equal(end) {
+			t.Errorf("mismatched time range: got=(%s, %s) exp=(%s, %s)", ranges[i][0], ranges[i][1], start, end)
+		}
+	}
+
+	// Nothing is rolled up until another window ends.
+	queries, ranges = nil, nil
+	if ok, err := s.ExecuteDownsamplePolicy(context.Background(), "db", dpi, now.Add(20*time.Minute)); err != nil {
+		t.Fatal(err)
+	} else if ok {
+		t.Fatal("expected downsample policy not to run")
+	}
+
+	// The policy functions are used and every window since the last run is
+	// rolled up.
+	dpi.Functions = []string{"sum"}
+	if _, err := s.ExecuteDownsamplePolicy(context.Background(), "db", dpi, now.Add(2*time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	exp = []string{
+		`SELECT sum(value) AS sum_value, last(host) AS last_host INTO db.hourly.cpu FROM db.raw.cpu GROUP BY time(1h), *`,
+		`SELECT sum(free) AS sum_free INTO db.hourly.mem FROM db.raw.mem GROUP BY time(1h), *`,
+	}
+	if !reflect.DeepEqual(queries, exp) {
+		t.Fatalf("unexpected queries:\n\nexp=%v\n\ngot=%v", exp, queries)
+	}
+	for i := range ranges {
+		if start, end := mustParseTime(t, "2000-01-01T03:00:00Z"), mustParseTime(t, "2000-01-01T05:00:00Z"); !ranges[i][0].Equal(start) || !ranges[i][1].Equal(end) {
+			t.Errorf("mismatched time range: got=(%s, %s) exp=(%s, %s)", ranges[i][0], ranges[i][1], start, end)
+		}
+	}
 }
 
 func TestService_ExecuteContinuousQuery_LogsToMonitor(t *testing.T) {
@@ -681,9 +996,17 @@ func TestService_ExecuteContinuousQuery_LogToMonitor_DisabledByDefault(t *testin
 	}
 }
 
-// NewTestService returns a new *Service with default mock object members.
-func NewTestService(t *testing.T) *Service {
-	s := NewService(NewConfig())
+// TestService is a test wrapper for Service.
+type TestService struct {
+	*Service
+
+	cancel context.CancelFunc
+	done   chan error
+}
+
+// NewTestService returns a new *TestService with default mock object members.
+func NewTestService(t *testing.T) *TestService {
+	s := &TestService{Service: NewService(NewConfig())}
 	ms := NewMetaClient(t)
 	s.MetaClient = ms
 	s.QueryExecutor = query.NewExecutor()
@@ -705,6 +1028,20 @@ func NewTestService(t *testing.T) *Service {
 	return s
 }
 
+// Open starts the service in the background.
+func (s *TestService) Open() {
+	var ctx context.Context
+	ctx, s.cancel = context.WithCancel(context.Background())
+	s.done = make(chan error, 1)
+	go func() { s.done <- s.Service.Start(ctx) }()
+}
+
+// Close stops the service and waits for it to exit.
+func (s *TestService) Close() error {
+	s.cancel()
+	return <-s.done
+}
+
 // MetaClient is a mock meta store.
 type MetaClient struct {
 	mu            sync.RWMutex
@@ -820,6 +1157,28 @@ func (ms *MetaClient) CreateContinuousQuery(database, name, query string) error
 	return nil
 }
 
+// SetContinuousQueryRuns records the last runs of CQs in the meta store.
+func (ms *MetaClient) SetContinuousQueryRuns(runs map[string]map[string]meta.ContinuousQueryRunInfo) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	if ms.Err != nil {
+		return ms.Err
+	}
+
+	for database, cqs := range runs {
+		dbi := ms.database(database)
+		if dbi == nil {
+			continue
+		}
+		for i := range dbi.ContinuousQueries {
+			if run, ok := cqs[dbi.ContinuousQueries[i].Name]; ok {
+				dbi.ContinuousQueries[i].LastRun = run
+			}
+		}
+	}
+	return nil
+}
+
 // StatementExecutor is a mock statement executor.
 type StatementExecutor struct {
 	ExecuteStatementFn func(stmt influxql.Statement, ctx *query.ExecutionContext) error
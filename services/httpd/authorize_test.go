@@ -0,0 +1,46 @@
+package httpd
+
+import (
+	"testing"
+
+	"github.com/ayang64/reflux/influxql"
+	"github.com/ayang64/reflux/models"
+)
+
+// measurementUser is a user permitted to write only to a single measurement.
+type measurementUser struct {
+	database    string
+	measurement string
+}
+
+func (u *measurementUser) ID() string                  { return "scoped" }
+func (u *measurementUser) AuthorizeUnrestricted() bool { return false }
+func (u *measurementUser) AuthorizeDatabase(p influxql.Privilege, name string) bool {
+	return p == influxql.NoPrivileges
+}
+func (u *measurementUser) AuthorizeQuery(database string, query *influxql.Query) error { return nil }
+func (u *measurementUser) AuthorizeSeriesRead(database string, measurement []byte, tags models.Tags) bool {
+	return database == u.database && string(measurement) == u.measurement
+}
+func (u *measurementUser) AuthorizeSeriesWrite(database string, measurement []byte, tags models.Tags) bool {
+	return database == u.database && string(measurement) == u.measurement
+}
+
+func TestAuthorizePoints(t *testing.T) {
+	points, err := models.ParsePointsString("cpu value=1 1\nmem value=2 2\ncpu value=3 3")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	authorized, unauthorized := authorizePoints(&measurementUser{database: "db0", measurement: "cpu"}, "db0", points)
+	if len(authorized) != 2 || string(authorized[0].Name()) != "cpu" || string(authorized[1].Name()) != "cpu" {
+		t.Fatalf("unexpected authorized points: %v", authorized)
+	} else if len(unauthorized) != 1 || string(unauthorized[0].Name()) != "mem" {
+		t.Fatalf("unexpected unauthorized points: %v", unauthorized)
+	}
+
+	authorized, unauthorized = authorizePoints(&measurementUser{database: "db0", measurement: "cpu"}, "db1", points)
+	if len(authorized) != 0 || len(unauthorized) != 3 {
+		t.Fatalf("unexpected points: authorized=%v unauthorized=%v", authorized, unauthorized)
+	}
+}
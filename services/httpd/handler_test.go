@@ -29,6 +29,7 @@ import (
 	"github.com/influxdata/flux/lang"
 	"github.com/influxdata/flux/mock"
 	"github.com/influxdata/flux/repl"
+	"github.com/ayang64/reflux/coordinator"
 	"github.com/ayang64/reflux/flux/client"
 	"github.com/ayang64/reflux/internal"
 	"github.com/ayang64/reflux/logger"
@@ -37,10 +38,12 @@ import (
 	"github.com/ayang64/reflux/monitor/diagnostics"
 	"github.com/ayang64/reflux/pkg/testing/assert"
 	"github.com/ayang64/reflux/query"
+	"github.com/ayang64/reflux/services/audit"
 	"github.com/ayang64/reflux/services/httpd"
 	"github.com/ayang64/reflux/services/meta"
 	"github.com/ayang64/reflux/storage/reads"
 	"github.com/ayang64/reflux/storage/reads/datatypes"
+	"github.com/ayang64/reflux/task"
 	"github.com/ayang64/reflux/tsdb"
 	"github.com/ayang64/reflux/influxql"
 	"github.com/prometheus/prometheus/prompb"
@@ -1461,6 +1464,51 @@ func TestHandler_Flux_Auth(t *testing.T) {
 	}
 }
 
+// Test query with an API token.
+func TestHandler_TokenAuth(t *testing.T) {
+	h := NewHandler(true)
+	h.MetaClient.AdminUserExistsFn = func() bool { return true }
+	h.MetaClient.AuthenticateTokenFn = func(token string) (meta.User, error) {
+		switch token {
+		case "0123.secret":
+			return &meta.UserInfo{Name: "ci"}, nil
+		case "4567.secret":
+			return nil, meta.ErrTokenExpired
+		}
+		return nil, meta.ErrAuthenticate
+	}
+	h.QueryAuthorizer.AuthorizeQueryFn = func(u meta.User, query *influxql.Query, database string) error {
+		if u.ID() != "ci" {
+			t.Fatalf("unexpected user: %s", u.ID())
+		}
+		return nil
+	}
+	h.StatementExecutor.ExecuteStatementFn = func(stmt influxql.Statement, ctx *query.ExecutionContext) error {
+		ctx.Results <- &query.Result{StatementID: 1, Series: models.Rows([]*models.Row{{Name: "series0"}})}
+		return nil
+	}
+
+	for _, tt := range []struct {
+		token string
+		code  int
+		body  string
+	}{
+		{token: "0123.secret", code: http.StatusOK, body: `{"results":[{"statement_id":1,"series":[{"name":"series0"}]}]}`},
+		{token: "4567.secret", code: http.StatusUnauthorized, body: `{"error":"token expired"}`},
+		{token: "0123.invalid", code: http.StatusUnauthorized, body: `{"error":"authorization failed"}`},
+	} {
+		req := MustNewJSONRequest("GET", "/query?db=foo&q=SELECT+*+FROM+bar", nil)
+		req.Header.Set("Authorization", "Token "+tt.token)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		if w.Code != tt.code {
+			t.Fatalf("%s: unexpected status: %d: %s", tt.token, w.Code, w.Body.String())
+		} else if body := strings.TrimSpace(w.Body.String()); body != tt.body {
+			t.Fatalf("%s: unexpected body: %s", tt.token, body)
+		}
+	}
+}
+
 // Ensure the handler handles ping requests correctly.
 func TestHandler_Ping(t *testing.T) {
 	h := NewHandler(false)
@@ -1666,6 +1714,146 @@ func TestHandler_Write_NegativeMaxBodySize(t *testing.T) {
 	}
 }
 
+// Ensure the lines that were not written are reported when details are requested.
+func TestHandler_Write_Details(t *testing.T) {
+	h := NewHandler(false)
+	h.MetaClient.DatabaseFn = func(name string) *meta.DatabaseInfo {
+		return &meta.DatabaseInfo{}
+	}
+	h.PointsWriter.WritePointsWithContextFn = func(ctx context.Context, _, _ string, _ models.ConsistencyLevel, _ meta.User, points []models.Point) error {
+		// The rejected points are only collected if details are requested.
+		rejected, _ := ctx.Value(tsdb.WriteRejectedPoints).(*tsdb.RejectedPoints)
+		if len(points) != 3 {
+			t.Fatalf("unexpected points: %v", points)
+		}
+		rejected.Add(points[1], tsdb.RejectFieldTypeConflict, "conflict")
+		return tsdb.PartialWriteError{Reason: "conflict", Dropped: 1}
+	}
+
+	body := "# comment\ncpu value=1 1\ncpu value=\n\nmem value=\"x\" 2\ncpu value=2 3\n"
+	for _, url := range []string{"/write?db=foo&details=true", "/api/v2/write?bucket=foo&details=true"} {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, MustNewRequest("POST", url, strings.NewReader(body)))
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("unexpected status: %d", w.Code)
+		} else if exp := `{"error":"partial write: conflict dropped=1","rejected":[{"line":3,"measurement":"cpu","code":"parse_error","message":"missing field value"},{"line":5,"measurement":"mem","code":"field_type_conflict","message":"conflict"}]}`; w.Body.String() != exp {
+			t.Fatalf("unexpected body:\ngot %s\nexp %s", w.Body.String(), exp)
+		}
+	}
+
+	// Without details the response is unchanged.
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, MustNewRequest("POST", "/write?db=foo", strings.NewReader(body)))
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("unexpected status: %d", w.Code)
+	} else if body := strings.TrimSpace(w.Body.String()); body != `{"error":"partial write: conflict dropped=1"}` {
+		t.Fatalf("unexpected body: %s", body)
+	}
+}
+
+// Ensure writes over the quota of a database are rejected with a status for
+// the exceeded resource.
+func TestHandler_Write_Quota(t *testing.T) {
+	for _, tt := range []struct {
+		resource string
+		code     int
+	}{
+		{resource: tsdb.QuotaPointsPerSecond, code: http.StatusTooManyRequests},
+		{resource: tsdb.QuotaDiskBytes, code: http.StatusForbidden},
+	} {
+		h := NewHandler(false)
+		h.MetaClient.DatabaseFn = func(name string) *meta.DatabaseInfo {
+			return &meta.DatabaseInfo{}
+		}
+		h.PointsWriter.WritePointsWithContextFn = func(_ context.Context, database, _ string, _ models.ConsistencyLevel, _ meta.User, _ []models.Point) error {
+			return tsdb.QuotaExceededError{Database: database, Resource: tt.resource, Limit: 10}
+		}
+
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, MustNewRequest("POST", "/write?db=foo", strings.NewReader("cpu value=1 1")))
+		if w.Code != tt.code {
+			t.Fatalf("unexpected status for %s quota: %d", tt.resource, w.Code)
+		} else if exp := fmt.Sprintf(`{"error":"database \"foo\" exceeded its %s quota: limit 10"}`, tt.resource); strings.TrimSpace(w.Body.String()) != exp {
+			t.Fatalf("unexpected body:\ngot %s\nexp %s", w.Body.String(), exp)
+		}
+	}
+}
+
+// Ensures the batch ID of a write is passed to the points writer.
+func TestHandler_Write_BatchID(t *testing.T) {
+	h := NewHandler(false)
+	h.MetaClient.DatabaseFn = func(name string) *meta.DatabaseInfo {
+		return &meta.DatabaseInfo{}
+	}
+	var batchID interface{}
+	h.PointsWriter.WritePointsWithContextFn = func(ctx context.Context, _, _ string, _ models.ConsistencyLevel, _ meta.User, _ []models.Point) error {
+		batchID = ctx.Value(coordinator.WriteBatchID)
+		return nil
+	}
+
+	req := MustNewRequest("POST", "/write?db=foo", strings.NewReader("cpu value=1 1"))
+	req.Header.Set("X-InfluxDB-Batch-ID", "batch0")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("unexpected status: %d", w.Code)
+	} else if batchID != "batch0" {
+		t.Fatalf("unexpected batch id: %v", batchID)
+	}
+
+	req = MustNewRequest("POST", "/write?db=foo", strings.NewReader("cpu value=1 1"))
+	req.Header.Set("X-InfluxDB-Batch-ID", strings.Repeat("x", coordinator.MaxBatchIDLength+1))
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("unexpected status: %d", w.Code)
+	}
+}
+
+// Ensures writes are authorized with the privileges of the token they are
+// made with rather than those of the user of the token.
+func TestHandler_Write_TokenAuth(t *testing.T) {
+	h := NewHandler(true)
+	h.Handler.WriteAuthorizer = meta.NewWriteAuthorizer(nil)
+	h.MetaClient.AdminUserExistsFn = func() bool { return true }
+	h.MetaClient.DatabaseFn = func(name string) *meta.DatabaseInfo {
+		return &meta.DatabaseInfo{}
+	}
+	h.MetaClient.AuthenticateTokenFn = func(token string) (meta.User, error) {
+		switch token {
+		case "0123.secret":
+			return &meta.UserInfo{Name: "ci", Privileges: map[string]influxql.Privilege{"foo": influxql.ReadPrivilege}}, nil
+		case "4567.secret":
+			return &meta.UserInfo{Name: "ci", Privileges: map[string]influxql.Privilege{"foo": influxql.WritePrivilege}}, nil
+		}
+		return nil, meta.ErrAuthenticate
+	}
+	var written int
+	h.PointsWriter.WritePointsFn = func(_, _ string, _ models.ConsistencyLevel, _ meta.User, points []models.Point) error {
+		written += len(points)
+		return nil
+	}
+
+	for _, tt := range []struct {
+		token string
+		code  int
+	}{
+		{token: "0123.secret", code: http.StatusForbidden},
+		{token: "4567.secret", code: http.StatusNoContent},
+	} {
+		req := MustNewRequest("POST", "/write?db=foo", strings.NewReader("cpu value=1 1"))
+		req.Header.Set("Authorization", "Token "+tt.token)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		if w.Code != tt.code {
+			t.Fatalf("%s: unexpected status: %d: %s", tt.token, w.Code, w.Body.String())
+		}
+	}
+	if written != 1 {
+		t.Fatalf("unexpected points written: %d", written)
+	}
+}
+
 // TestHandler_Write_V1_Precision verifies v1 writes validate precision.
 func TestHandler_Write_V1_Precision(t *testing.T) {
 	h := NewHandler(false)
@@ -2052,6 +2240,83 @@ func TestHandlerDebugVars(t *testing.T) {
 	})
 }
 
+// Ensure JWT authentications are recorded in the audit log.
+func TestHandler_AuditJWT(t *testing.T) {
+	h := NewHandler(true)
+	h.MetaClient.AdminUserExistsFn = func() bool { return true }
+	h.MetaClient.UserFn = func(username string) (meta.User, error) {
+		if username != "user1" {
+			return nil, meta.ErrUserNotFound
+		}
+		return &meta.UserInfo{Name: "user1", Admin: true}, nil
+	}
+	h.QueryAuthorizer.AuthorizeQueryFn = func(u meta.User, query *influxql.Query, database string) error {
+		return nil
+	}
+	h.StatementExecutor.ExecuteStatementFn = func(stmt influxql.Statement, ctx *query.ExecutionContext) error {
+		return nil
+	}
+
+	var events []audit.Event
+	h.Handler.AuditLog = recorderFunc(func(e audit.Event) { events = append(events, e) })
+
+	for _, username := range []string{"user1", "bad_user"} {
+		_, signedToken := MustJWTToken(username, h.Config.SharedSecret, false)
+		req := MustNewJSONRequest("GET", "/query?db=foo&q=SELECT+*+FROM+bar", nil)
+		req.RemoteAddr = "10.0.0.1:51234"
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", signedToken))
+		h.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	exp := []audit.Event{
+		{Action: audit.ActionJWT, User: "user1", Source: "10.0.0.1", Outcome: audit.OutcomeSuccess},
+		{Action: audit.ActionJWT, User: "bad_user", Source: "10.0.0.1", Outcome: audit.OutcomeFailure, Error: meta.ErrUserNotFound.Error()},
+	}
+	if !reflect.DeepEqual(events, exp) {
+		t.Fatalf("unexpected events:\ngot: %+v\nexp: %+v", events, exp)
+	}
+}
+
+// Ensure the audited statements of a query denied by the query authorizer are
+// recorded as failures.
+func TestHandler_Query_AuditDenied(t *testing.T) {
+	h := NewHandler(true)
+	h.MetaClient.AdminUserExistsFn = func() bool { return true }
+	h.MetaClient.AuthenticateFn = func(u, p string) (meta.User, error) {
+		return &meta.UserInfo{Name: u}, nil
+	}
+	h.QueryAuthorizer.AuthorizeQueryFn = func(u meta.User, query *influxql.Query, database string) error {
+		return meta.ErrAuthorize{User: u.ID(), Query: query, Database: database, Message: "DROP DATABASE"}
+	}
+	h.StatementExecutor.ExecuteStatementFn = func(stmt influxql.Statement, ctx *query.ExecutionContext) error {
+		t.Error("unexpected statement execution")
+		return nil
+	}
+
+	var events []audit.Event
+	h.Handler.AuditLog = recorderFunc(func(e audit.Event) { events = append(events, e) })
+
+	req := MustNewJSONRequest("POST", "/query?db=db0&u=bob&p=secret&q="+url.QueryEscape("SELECT * FROM cpu; DROP DATABASE db0"), nil)
+	req.RemoteAddr = "10.0.0.1:51234"
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	if w.Code != http.StatusForbidden {
+		t.Fatalf("unexpected status: %d", w.Code)
+	}
+
+	exp := []audit.Event{
+		{Action: audit.ActionStatement, User: "bob", Source: "10.0.0.1", Database: "db0", Statement: "DROP DATABASE db0", Outcome: audit.OutcomeFailure, Error: "bob not authorized to execute DROP DATABASE"},
+	}
+	if !reflect.DeepEqual(events, exp) {
+		t.Fatalf("unexpected events:\ngot: %+v\nexp: %+v", events, exp)
+	}
+}
+
+// recorderFunc is an audit.Recorder that calls itself.
+type recorderFunc func(e audit.Event)
+
+func (fn recorderFunc) Record(e audit.Event) { fn(e) }
+
 // NewHandler represents a test wrapper for httpd.Handler.
 type Handler struct {
 	*httpd.Handler
@@ -2199,13 +2464,21 @@ func (a *HandlerQueryAuthorizer) AuthorizeQuery(u meta.User, query *influxql.Que
 }
 
 type HandlerPointsWriter struct {
-	WritePointsFn func(database, retentionPolicy string, consistencyLevel models.ConsistencyLevel, user meta.User, points []models.Point) error
+	WritePointsFn            func(database, retentionPolicy string, consistencyLevel models.ConsistencyLevel, user meta.User, points []models.Point) error
+	WritePointsWithContextFn func(ctx context.Context, database, retentionPolicy string, consistencyLevel models.ConsistencyLevel, user meta.User, points []models.Point) error
 }
 
 func (h *HandlerPointsWriter) WritePoints(database, retentionPolicy string, consistencyLevel models.ConsistencyLevel, user meta.User, points []models.Point) error {
 	return h.WritePointsFn(database, retentionPolicy, consistencyLevel, user, points)
 }
 
+func (h *HandlerPointsWriter) WritePointsWithContext(ctx context.Context, database, retentionPolicy string, consistencyLevel models.ConsistencyLevel, user meta.User, points []models.Point) error {
+	if h.WritePointsWithContextFn == nil {
+		return h.WritePoints(database, retentionPolicy, consistencyLevel, user, points)
+	}
+	return h.WritePointsWithContextFn(ctx, database, retentionPolicy, consistencyLevel, user, points)
+}
+
 // MustNewRequest returns a new HTTP request. Panic on error.
 func MustNewRequest(method, urlStr string, body io.Reader) *http.Request {
 	r, err := http.NewRequest(method, urlStr, body)
@@ -2239,6 +2512,37 @@ func MustJWTToken(username, secret string, expired bool) (*jwt.Token, string) {
 }
 
 // Ensure that user supplied headers are applied to responses.
+// Ensure the handler lists running background tasks.
+func TestHandler_DebugTasks(t *testing.T) {
+	h := NewHandler(false)
+	h.Handler.Tasks, _ = task.NewManager()
+
+	started, done := make(chan struct{}), make(chan struct{})
+	go h.Handler.Tasks.Run(context.Background(), "tsm1 full compaction of shard 1", func(ctx context.Context) error {
+		close(started)
+		<-done
+		return nil
+	})
+	defer close(done)
+	<-started
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, MustNewRequest("GET", "/debug/tasks", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("unexpected status: %d", w.Code)
+	}
+
+	var tasks []struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&tasks); err != nil {
+		t.Fatal(err)
+	} else if len(tasks) != 1 || tasks[0].ID != 0 || tasks[0].Name != "tsm1 full compaction of shard 1" {
+		t.Fatalf("unexpected tasks: %+v", tasks)
+	}
+}
+
 func TestHandler_UserSuppliedHeaders(t *testing.T) {
 	endpoints := []struct {
 		method string
@@ -62,6 +62,7 @@ type Service struct {
 	unixSocketGroup    int
 	bindSocket         string
 	unixSocketListener net.Listener
+	ready              chan struct{}
 	Handler            *Handler
 	Logger             *zap.Logger
 }
@@ -76,6 +77,7 @@ func NewService(c Config) *Service {
 		limit:          c.MaxConnectionLimit,
 		tlsConfig:      c.TLS,
 		err:            make(chan error),
+		ready:          make(chan struct{}),
 		unixSocket:     c.UnixSocketEnabled,
 		unixSocketPerm: uint32(c.UnixSocketPermissions),
 		bindSocket:     c.BindSocket,
@@ -182,9 +184,18 @@ func (s *Service) Start(ctx context.Context) error {
 
 	// Begin listening for requests in a separate goroutine.
 	go s.serveTCP()
+	close(s.ready)
 
 	<-ctx.Done()
 
+	if s.ln != nil {
+		s.ln.Close()
+	}
+	if s.unixSocketListener != nil {
+		s.unixSocketListener.Close()
+	}
+	s.Handler.Close()
+
 	return nil
 }
 
@@ -194,6 +205,9 @@ func (s *Service) WithLogger(log *zap.Logger) {
 	s.Handler.Logger = s.Logger
 }
 
+// Ready returns a channel that is closed once the service is listening.
+func (s *Service) Ready() <-chan struct{} { return s.ready }
+
 // Err returns a channel for fatal errors that occur on the listener.
 func (s *Service) Err() <-chan error { return s.err }
 
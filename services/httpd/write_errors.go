@@ -0,0 +1,102 @@
+package httpd
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/ayang64/reflux/models"
+	"github.com/ayang64/reflux/tsdb"
+)
+
+// rejectedLine describes a line of a write that was not written.
+type rejectedLine struct {
+	Line        int    `json:"line"`
+	Measurement string `json:"measurement,omitempty"`
+	Code        string `json:"code"`
+	Message     string `json:"message"`
+}
+
+// writeErrors tracks the lines of a write that were not written so they can
+// be reported when the client requests details.
+type writeErrors struct {
+	// lines maps each parsed point to its line number. The write reports
+	// rejected points as the parsed points even if it rebuilt them.
+	lines    map[models.Point]int
+	rejected []rejectedLine
+}
+
+// newWriteErrors returns the errors of a write that begin with the lines that
+// could not be parsed.
+func newWriteErrors(points []models.Point, lines []int, errs []*models.LineError) *writeErrors {
+	e := &writeErrors{lines: make(map[models.Point]int, len(points))}
+	for i, p := range points {
+		e.lines[p] = lines[i]
+	}
+	for _, err := range errs {
+		e.rejected = append(e.rejected, rejectedLine{
+			Line:        err.Line,
+			Measurement: string(models.ParseName([]byte(err.Text))),
+			Code:        tsdb.RejectParseError,
+			Message:     err.Err.Error(),
+		})
+	}
+	return e
+}
+
+// joinLineErrors returns the error describing the lines that could not be parsed,
+// in the same form as models.ParsePointsWithPrecision.
+func joinLineErrors(errs []*models.LineError) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	msgs := make([]string, len(errs))
+	for i, err := range errs {
+		msgs[i] = err.Error()
+	}
+	return fmt.Errorf("%s", strings.Join(msgs, "\n"))
+}
+
+// reject adds the line of a point that was not written. It is a no-op if the
+// client did not request details.
+func (e *writeErrors) reject(p models.Point, code, message string) {
+	if e == nil {
+		return
+	}
+	e.rejected = append(e.rejected, rejectedLine{
+		Line:        e.lines[p],
+		Measurement: string(p.Name()),
+		Code:        code,
+		Message:     message,
+	})
+}
+
+// writeError writes an error response to a write. If the client requested
+// details, the response also lists the lines that were not written.
+func (h *Handler) writeError(w http.ResponseWriter, errmsg string, code int, details *writeErrors) {
+	if details == nil {
+		h.httpError(w, errmsg, code)
+		return
+	}
+
+	if details.rejected == nil {
+		details.rejected = []rejectedLine{}
+	}
+	sort.SliceStable(details.rejected, func(i, j int) bool {
+		return details.rejected[i].Line < details.rejected[j].Line
+	})
+
+	sz := math.Min(float64(len(errmsg)), 1024.0)
+	w.Header().Set("X-InfluxDB-Error", errmsg[:int(sz)])
+	w.Header().Add("Content-Type", "application/json")
+	h.writeHeader(w, code)
+
+	b, _ := json.Marshal(struct {
+		Err      string         `json:"error"`
+		Rejected []rejectedLine `json:"rejected"`
+	}{Err: errmsg, Rejected: details.rejected})
+	w.Write(b)
+}
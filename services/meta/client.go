@@ -6,6 +6,9 @@ import (
 	"bytes"
 	crand "crypto/rand"
 	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
 	"errors"
 	"io"
 	"io/ioutil"
@@ -14,6 +17,7 @@ import (
 	"os"
 	"path/filepath"
 	"sort"
+	"strings"
 	"sync"
 	"time"
 
@@ -21,6 +25,7 @@ import (
 	"github.com/ayang64/reflux/logger"
 	"github.com/ayang64/reflux/pkg/file"
 	"github.com/ayang64/reflux/influxql"
+	"github.com/ayang64/reflux/services/audit"
 	"go.uber.org/zap"
 	"golang.org/x/crypto/bcrypt"
 )
@@ -29,6 +34,12 @@ const (
 	// SaltBytes is the number of bytes used for salts.
 	SaltBytes = 32
 
+	// TokenIDBytes is the number of random bytes in the ID of a token.
+	TokenIDBytes = 8
+
+	// TokenSecretBytes is the number of random bytes in the secret of a token.
+	TokenSecretBytes = 32
+
 	metaFile = "meta.db"
 
 	// ShardGroupDeletedExpiration is the amount of time before a shard group info will be removed from cached
@@ -60,6 +71,9 @@ type Client struct {
 	path string
 
 	retentionAutoCreate bool
+
+	// auditLog records authentication attempts, if set.
+	auditLog audit.Recorder
 }
 
 type authUser struct {
@@ -374,10 +388,8 @@ func (c *Client) User(name string) (User, error) {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
-	for _, u := range c.cacheData.Users {
-		if u.Name == name {
-			return &u, nil
-		}
+	if u := c.cacheData.userWithRoles(name); u != nil {
+		return u, nil
 	}
 
 	return nil, ErrUserNotFound
@@ -539,6 +551,262 @@ func (c *Client) UserPrivilege(username, database string) (*influxql.Privilege,
 	return p, nil
 }
 
+// Roles returns a slice of RoleInfo representing the currently known roles.
+func (c *Client) Roles() []RoleInfo {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.cacheData.CloneRoles()
+}
+
+// CreateRole creates a role with the given name.
+func (c *Client) CreateRole(name string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	data := c.cacheData.Clone()
+
+	if err := data.CreateRole(name); err != nil {
+		return err
+	}
+
+	return c.commit(data)
+}
+
+// DropRole removes the role with the given name and revokes it from every user.
+func (c *Client) DropRole(name string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	data := c.cacheData.Clone()
+
+	if err := data.DropRole(name); err != nil {
+		return err
+	}
+
+	return c.commit(data)
+}
+
+// GrantRolePrivilege grants a privilege to the given role.
+func (c *Client) GrantRolePrivilege(name string, p PrivilegeInfo) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	data := c.cacheData.Clone()
+
+	if err := data.GrantRolePrivilege(name, p); err != nil {
+		return err
+	}
+
+	return c.commit(data)
+}
+
+// RevokeRolePrivilege revokes a privilege from the given role.
+func (c *Client) RevokeRolePrivilege(name string, p PrivilegeInfo) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	data := c.cacheData.Clone()
+
+	if err := data.RevokeRolePrivilege(name, p); err != nil {
+		return err
+	}
+
+	return c.commit(data)
+}
+
+// GrantRole grants the given role to a user.
+func (c *Client) GrantRole(name, username string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	data := c.cacheData.Clone()
+
+	if err := data.GrantRole(name, username); err != nil {
+		return err
+	}
+
+	return c.commit(data)
+}
+
+// RevokeRole revokes the given role from a user.
+func (c *Client) RevokeRole(name, username string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	data := c.cacheData.Clone()
+
+	if err := data.RevokeRole(name, username); err != nil {
+		return err
+	}
+
+	return c.commit(data)
+}
+
+// EffectivePrivileges returns the privileges of a user including the
+// privileges of its roles.
+func (c *Client) EffectivePrivileges(username string) ([]PrivilegeInfo, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.cacheData.EffectivePrivileges(username)
+}
+
+// Tokens returns a slice of TokenInfo representing the currently known tokens.
+func (c *Client) Tokens() []TokenInfo {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.cacheData.CloneTokens()
+}
+
+// CreateToken creates a token for a user that is limited to the given
+// privileges, or has every privilege of the user if there are none. The token
+// does not expire if expiresAt is zero. The returned token is the only copy
+// of its secret; only a hash of the secret is stored.
+func (c *Client) CreateToken(username, description string, privileges map[string]influxql.Privilege, expiresAt time.Time) (*TokenInfo, string, error) {
+	id := make([]byte, TokenIDBytes)
+	if _, err := io.ReadFull(crand.Reader, id); err != nil {
+		return nil, "", err
+	}
+	secret := make([]byte, TokenSecretBytes)
+	if _, err := io.ReadFull(crand.Reader, secret); err != nil {
+		return nil, "", err
+	}
+	encoded := base64.RawURLEncoding.EncodeToString(secret)
+
+	ti := TokenInfo{
+		ID:          hex.EncodeToString(id),
+		User:        username,
+		Hash:        hashToken(encoded),
+		Description: description,
+		CreatedAt:   time.Now().UTC(),
+		ExpiresAt:   expiresAt,
+		Privileges:  privileges,
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	data := c.cacheData.Clone()
+
+	if err := data.CreateToken(ti); err != nil {
+		return nil, "", err
+	}
+
+	if err := c.commit(data); err != nil {
+		return nil, "", err
+	}
+
+	return &ti, ti.ID + "." + encoded, nil
+}
+
+// RevokeToken removes the token with the given ID.
+func (c *Client) RevokeToken(id string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	data := c.cacheData.Clone()
+
+	if err := data.RevokeToken(id); err != nil {
+		return err
+	}
+
+	return c.commit(data)
+}
+
+// SecurityPolicies returns a list of all security policies.
+func (c *Client) SecurityPolicies() []SecurityPolicyInfo {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.cacheData.CloneSecurityPolicies()
+}
+
+// CreateSecurityPolicy creates a security policy that limits the series the
+// user or role of the policy can read from its database.
+func (c *Client) CreateSecurityPolicy(pi SecurityPolicyInfo) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	data := c.cacheData.Clone()
+
+	if err := data.CreateSecurityPolicy(pi); err != nil {
+		return err
+	}
+
+	return c.commit(data)
+}
+
+// DropSecurityPolicy removes the security policy of a user or role on a database.
+func (c *Client) DropSecurityPolicy(database, user, role string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	data := c.cacheData.Clone()
+
+	if err := data.DropSecurityPolicy(database, user, role); err != nil {
+		return err
+	}
+
+	return c.commit(data)
+}
+
+// AuthenticateToken returns the user of a token if the token matches an
+// existing entry and has not expired. The user is only authorized within the
+// privileges of the token.
+func (c *Client) AuthenticateToken(token string) (User, error) {
+	return c.AuthenticateTokenFrom(token, "")
+}
+
+// AuthenticateTokenFrom is like AuthenticateToken but records source, the
+// address of the client, in the audit log.
+func (c *Client) AuthenticateTokenFrom(token, source string) (User, error) {
+	id := token
+	if i := strings.IndexByte(token, '.'); i >= 0 {
+		id = token[:i]
+	}
+
+	u, err := c.authenticateToken(token)
+	e := audit.Event{Action: audit.ActionToken, Token: id, Source: audit.Source(source)}
+	if u != nil {
+		e.User = u.ID()
+	}
+	c.recordAuth(e, err)
+	return u, err
+}
+
+func (c *Client) authenticateToken(token string) (User, error) {
+	i := strings.IndexByte(token, '.')
+	if i < 0 {
+		return nil, ErrAuthenticate
+	}
+	id, secret := token[:i], token[i+1:]
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	ti := c.cacheData.Token(id)
+	if ti == nil || subtle.ConstantTimeCompare([]byte(hashToken(secret)), []byte(ti.Hash)) != 1 {
+		return nil, ErrAuthenticate
+	} else if ti.Expired(time.Now()) {
+		return nil, ErrTokenExpired
+	}
+
+	u := c.cacheData.tokenUser(ti)
+	if u == nil {
+		return nil, ErrUserNotFound
+	}
+	return u, nil
+}
+
+// hashToken returns the hash of the secret of a token. The secret is random,
+// so unlike passwords it does not need to be salted or stretched.
+func hashToken(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
 // AdminUserExists returns true if any user has admin privilege.
 func (c *Client) AdminUserExists() bool {
 	c.mu.RLock()
@@ -548,9 +816,21 @@ func (c *Client) AdminUserExists() bool {
 
 // Authenticate returns a UserInfo if the username and password match an existing entry.
 func (c *Client) Authenticate(username, password string) (User, error) {
+	return c.AuthenticateFrom(username, password, "")
+}
+
+// AuthenticateFrom is like Authenticate but records source, the address of
+// the client, in the audit log.
+func (c *Client) AuthenticateFrom(username, password, source string) (User, error) {
+	u, err := c.authenticate(username, password)
+	c.recordAuth(audit.Event{Action: audit.ActionLogin, User: username, Source: audit.Source(source)}, err)
+	return u, err
+}
+
+func (c *Client) authenticate(username, password string) (User, error) {
 	// Find user.
 	c.mu.RLock()
-	userInfo := c.cacheData.user(username)
+	userInfo := c.cacheData.userWithRoles(username)
 	c.mu.RUnlock()
 	if userInfo == nil {
 		return nil, ErrUserNotFound
@@ -585,6 +865,20 @@ func (c *Client) Authenticate(username, password string) (User, error) {
 	return userInfo, nil
 }
 
+// recordAuth records the authentication attempt e, which failed with err if
+// err is not nil, in the audit log if one is set.
+func (c *Client) recordAuth(e audit.Event, err error) {
+	c.mu.RLock()
+	r := c.auditLog
+	c.mu.RUnlock()
+	if r == nil {
+		return
+	}
+
+	e.Outcome, e.Error = audit.Outcome(err)
+	r.Record(e)
+}
+
 // UserCount returns the number of users stored.
 func (c *Client) UserCount() int {
 	c.mu.RLock()
@@ -892,14 +1186,155 @@ func (c *Client) DropContinuousQuery(database, name string) error {
 	return nil
 }
 
+// SetContinuousQueryRuns records the most recent runs of continuous queries,
+// keyed by database and then by name, in a single update. Runs of continuous
+// queries that no longer exist are ignored.
+func (c *Client) SetContinuousQueryRuns(runs map[string]map[string]ContinuousQueryRunInfo) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	data := c.cacheData.Clone()
+
+	for database, cqs := range runs {
+		if data.Database(database) == nil {
+			continue
+		}
+		for name, run := range cqs {
+			if err := data.SetContinuousQueryRun(database, name, run); err != nil && err != ErrContinuousQueryNotFound {
+				return err
+			}
+		}
+	}
+
+	if err := c.commit(data); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// CreateDownsamplePolicy saves a downsample policy on the given database.
+func (c *Client) CreateDownsamplePolicy(database string, dpi DownsamplePolicyInfo) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	data := c.cacheData.Clone()
+
+	if err := data.CreateDownsamplePolicy(database, dpi); err != nil {
+		return err
+	}
+
+	if err := c.commit(data); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DropDownsamplePolicy removes the downsample policy with the given name on the given database.
+func (c *Client) DropDownsamplePolicy(database, name string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	data := c.cacheData.Clone()
+
+	if err := data.DropDownsamplePolicy(database, name); err != nil {
+		return err
+	}
+
+	if err := c.commit(data); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// CreateMeasurementSchema saves a measurement schema on the given database.
+func (c *Client) CreateMeasurementSchema(database string, msi MeasurementSchemaInfo) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	data := c.cacheData.Clone()
+
+	if err := data.CreateMeasurementSchema(database, msi); err != nil {
+		return err
+	}
+
+	if err := c.commit(data); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DropMeasurementSchema removes the schema of the measurement with the given name on the given database.
+func (c *Client) DropMeasurementSchema(database, name string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	data := c.cacheData.Clone()
+
+	if err := data.DropMeasurementSchema(database, name); err != nil {
+		return err
+	}
+
+	if err := c.commit(data); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// SetQuota sets the quota of the given database, replacing any existing quota.
+func (c *Client) SetQuota(database string, qi QuotaInfo) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	data := c.cacheData.Clone()
+
+	if err := data.SetQuota(database, qi); err != nil {
+		return err
+	}
+
+	if err := c.commit(data); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DropQuota removes the quota of the given database.
+func (c *Client) DropQuota(database string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	data := c.cacheData.Clone()
+
+	if err := data.DropQuota(database); err != nil {
+		return err
+	}
+
+	if err := c.commit(data); err != nil {
+		return err
+	}
+
+	return nil
+}
+
 // CreateSubscription creates a subscription against the given database and retention policy.
 func (c *Client) CreateSubscription(database, rp, name, mode string, destinations []string) error {
+	return c.CreateSubscriptionWithFilter(database, rp, name, mode, destinations, nil, "")
+}
+
+// CreateSubscriptionWithFilter creates a subscription against the given database and
+// retention policy that forwards only the given fields of the points matching condition.
+func (c *Client) CreateSubscriptionWithFilter(database, rp, name, mode string, destinations, fields []string, condition string) error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
 	data := c.cacheData.Clone()
 
-	if err := data.CreateSubscription(database, rp, name, mode, destinations); err != nil {
+	if err := data.CreateSubscription(database, rp, name, mode, destinations, fields, condition); err != nil {
 		return err
 	}
 
@@ -993,6 +1428,13 @@ func (c *Client) WithLogger(log *zap.Logger) {
 	c.logger = log.With(zap.String("service", "metaclient"))
 }
 
+// WithAuditLog sets the recorder of authentication attempts.
+func (c *Client) WithAuditLog(r audit.Recorder) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.auditLog = r
+}
+
 // snapshot saves the current meta data to disk.
 func snapshot(path string, data *Data) error {
 	filename := filepath.Join(path, metaFile)
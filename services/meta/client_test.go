@@ -12,8 +12,10 @@ import (
 
 	"github.com/ayang64/reflux"
 
+	"github.com/ayang64/reflux/services/audit"
 	"github.com/ayang64/reflux/services/meta"
 	"github.com/ayang64/reflux/influxql"
+	"github.com/ayang64/reflux/models"
 )
 
 func TestMetaClient_CreateDatabaseOnly(t *testing.T) {
@@ -758,6 +760,251 @@ func TestMetaClient_UpdateUser(t *testing.T) {
 	}
 }
 
+func TestMetaClient_Roles(t *testing.T) {
+	t.Parallel()
+
+	d, c := newClient()
+	defer os.RemoveAll(d)
+	defer c.Close()
+
+	if _, err := c.CreateDatabase("db0"); err != nil {
+		t.Fatal(err)
+	} else if _, err := c.CreateUser("wilma", "password", false); err != nil {
+		t.Fatal(err)
+	} else if err := c.CreateRole("ops"); err != nil {
+		t.Fatal(err)
+	} else if err := c.GrantRolePrivilege("ops", meta.PrivilegeInfo{Database: "db0", Privilege: influxql.AllPrivileges, Measurement: "^cpu", Regex: true}); err != nil {
+		t.Fatal(err)
+	} else if err := c.GrantRolePrivilege("ops", meta.PrivilegeInfo{Database: "db0", Privilege: influxql.ReadPrivilege, Measurement: "mem"}); err != nil {
+		t.Fatal(err)
+	} else if err := c.GrantRole("ops", "wilma"); err != nil {
+		t.Fatal(err)
+	}
+
+	u, err := c.User("wilma")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range []struct {
+		measurement string
+		read, write bool
+	}{
+		{measurement: "cpu", read: true, write: true},
+		{measurement: "cpu_load", read: true, write: true},
+		{measurement: "mem", read: true, write: false},
+		{measurement: "disk", read: false, write: false},
+	} {
+		if got := u.AuthorizeSeriesRead("db0", []byte(tt.measurement), nil); got != tt.read {
+			t.Errorf("%s: unexpected read authorization: got %v, exp %v", tt.measurement, got, tt.read)
+		}
+		if got := u.AuthorizeSeriesWrite("db0", []byte(tt.measurement), nil); got != tt.write {
+			t.Errorf("%s: unexpected write authorization: got %v, exp %v", tt.measurement, got, tt.write)
+		}
+	}
+	if u.AuthorizeSeriesRead("db1", []byte("cpu"), nil) {
+		t.Error("expected read on another database to be denied")
+	}
+
+	// Revoking the role removes the privileges it granted.
+	if err := c.RevokeRole("ops", "wilma"); err != nil {
+		t.Fatal(err)
+	}
+	if u, err = c.User("wilma"); err != nil {
+		t.Fatal(err)
+	} else if u.AuthorizeSeriesRead("db0", []byte("cpu"), nil) {
+		t.Fatal("expected read to be denied after revoking the role")
+	}
+}
+
+func TestMetaClient_Tokens(t *testing.T) {
+	t.Parallel()
+
+	d, c := newClient()
+	defer os.RemoveAll(d)
+	defer c.Close()
+
+	if _, err := c.CreateDatabase("db0"); err != nil {
+		t.Fatal(err)
+	} else if _, err := c.CreateDatabase("db1"); err != nil {
+		t.Fatal(err)
+	} else if _, err := c.CreateUser("fred", "supersecure", true); err != nil {
+		t.Fatal(err)
+	} else if _, err := c.CreateUser("wilma", "password", false); err != nil {
+		t.Fatal(err)
+	} else if err := c.SetPrivilege("wilma", "db0", influxql.ReadPrivilege); err != nil {
+		t.Fatal(err)
+	}
+
+	// A token of an admin is limited to its privileges.
+	ti, token, err := c.CreateToken("fred", "ci", map[string]influxql.Privilege{"db0": influxql.WritePrivilege}, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	} else if strings.Contains(ti.Hash, token) || !strings.HasPrefix(token, ti.ID+".") {
+		t.Fatalf("unexpected token: %s", token)
+	}
+	u, err := c.AuthenticateToken(token)
+	if err != nil {
+		t.Fatal(err)
+	} else if u.ID() != "fred" || u.AuthorizeUnrestricted() {
+		t.Fatalf("unexpected user: %#v", u)
+	} else if !u.AuthorizeDatabase(influxql.WritePrivilege, "db0") || u.AuthorizeDatabase(influxql.ReadPrivilege, "db0") || u.AuthorizeDatabase(influxql.WritePrivilege, "db1") {
+		t.Fatal("unexpected token privileges")
+	}
+
+	// A token cannot have more privileges than its user.
+	_, token2, err := c.CreateToken("wilma", "", map[string]influxql.Privilege{"db0": influxql.AllPrivileges}, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	} else if u, err := c.AuthenticateToken(token2); err != nil {
+		t.Fatal(err)
+	} else if !u.AuthorizeDatabase(influxql.ReadPrivilege, "db0") || u.AuthorizeDatabase(influxql.WritePrivilege, "db0") {
+		t.Fatal("unexpected token privileges")
+	}
+
+	// Tokens must match and not be expired.
+	if _, err := c.AuthenticateToken(ti.ID + ".invalid"); err != meta.ErrAuthenticate {
+		t.Fatalf("unexpected error: %v", err)
+	} else if _, err := c.AuthenticateToken("invalid"); err != meta.ErrAuthenticate {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, expired, err := c.CreateToken("fred", "", nil, time.Now().Add(-time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	} else if _, err := c.AuthenticateToken(expired); err != meta.ErrTokenExpired {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, _, err := c.CreateToken("barney", "", nil, time.Time{}); err != meta.ErrUserNotFound {
+		t.Fatalf("unexpected error: %v", err)
+	} else if exp, got := 3, len(c.Tokens()); exp != got {
+		t.Fatalf("unexpected token count. got: %d exp: %d", got, exp)
+	}
+
+	// Revoked tokens and the tokens of dropped users cannot be used.
+	if err := c.RevokeToken(ti.ID); err != nil {
+		t.Fatal(err)
+	} else if err := c.RevokeToken(ti.ID); err != meta.ErrTokenNotFound {
+		t.Fatalf("unexpected error: %v", err)
+	} else if _, err := c.AuthenticateToken(token); err != meta.ErrAuthenticate {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := c.DropUser("wilma"); err != nil {
+		t.Fatal(err)
+	} else if _, err := c.AuthenticateToken(token2); err != meta.ErrAuthenticate {
+		t.Fatalf("unexpected error: %v", err)
+	} else if exp, got := 1, len(c.Tokens()); exp != got {
+		t.Fatalf("unexpected token count. got: %d exp: %d", got, exp)
+	}
+}
+
+func TestMetaClient_SecurityPolicies(t *testing.T) {
+	t.Parallel()
+
+	d, c := newClient()
+	defer os.RemoveAll(d)
+	defer c.Close()
+
+	if _, err := c.CreateDatabase("db0"); err != nil {
+		t.Fatal(err)
+	} else if _, err := c.CreateDatabase("db1"); err != nil {
+		t.Fatal(err)
+	} else if _, err := c.CreateUser("acme", "password", false); err != nil {
+		t.Fatal(err)
+	} else if err := c.SetPrivilege("acme", "db0", influxql.ReadPrivilege); err != nil {
+		t.Fatal(err)
+	} else if err := c.SetPrivilege("acme", "db1", influxql.ReadPrivilege); err != nil {
+		t.Fatal(err)
+	} else if err := c.CreateRole("eu"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.CreateSecurityPolicy(meta.SecurityPolicyInfo{Database: "db0", User: "acme", Condition: "customer = 'acme'"}); err != nil {
+		t.Fatal(err)
+	} else if err := c.CreateSecurityPolicy(meta.SecurityPolicyInfo{Database: "db0", Role: "eu", Condition: "region =~ /^eu-/ AND customer != 'globex'"}); err != nil {
+		t.Fatal(err)
+	} else if exp, got := 2, len(c.SecurityPolicies()); exp != got {
+		t.Fatalf("unexpected security policy count. got: %d exp: %d", got, exp)
+	}
+
+	authorized := func(database string, tags models.Tags) bool {
+		t.Helper()
+		u, err := c.Authenticate("acme", "password")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return u.AuthorizeSeriesRead(database, []byte("cpu"), tags)
+	}
+
+	// Only series matching the policy of the user can be read.
+	acme := models.NewTags(map[string]string{"customer": "acme", "region": "us-west"})
+	globex := models.NewTags(map[string]string{"customer": "globex", "region": "eu-west"})
+	initech := models.NewTags(map[string]string{"customer": "initech", "region": "eu-west"})
+	if !authorized("db0", acme) || authorized("db0", globex) || authorized("db0", initech) || authorized("db0", nil) {
+		t.Fatal("unexpected authorization with user policy")
+	}
+
+	// Databases without policies are not restricted.
+	if !authorized("db1", globex) {
+		t.Fatal("unexpected authorization without policy")
+	}
+
+	// Series matching any policy of the user or its roles can be read.
+	if err := c.GrantRole("eu", "acme"); err != nil {
+		t.Fatal(err)
+	} else if !authorized("db0", acme) || authorized("db0", globex) || !authorized("db0", initech) {
+		t.Fatal("unexpected authorization with role policy")
+	}
+
+	if err := c.DropSecurityPolicy("db0", "acme", ""); err != nil {
+		t.Fatal(err)
+	} else if err := c.DropSecurityPolicy("db0", "acme", ""); err != meta.ErrSecurityPolicyNotFound {
+		t.Fatalf("unexpected error: %v", err)
+	} else if authorized("db0", acme) || !authorized("db0", initech) {
+		t.Fatal("unexpected authorization after dropping user policy")
+	}
+
+	// Dropping the database drops its policies.
+	if err := c.DropDatabase("db0"); err != nil {
+		t.Fatal(err)
+	} else if exp, got := 0, len(c.SecurityPolicies()); exp != got {
+		t.Fatalf("unexpected security policy count. got: %d exp: %d", got, exp)
+	}
+}
+
+func TestMetaClient_AuditLog(t *testing.T) {
+	t.Parallel()
+
+	d, c := newClient()
+	defer os.RemoveAll(d)
+	defer c.Close()
+
+	var events []audit.Event
+	c.WithAuditLog(recorderFunc(func(e audit.Event) { events = append(events, e) }))
+
+	if _, err := c.CreateUser("fred", "supersecure", true); err != nil {
+		t.Fatal(err)
+	}
+	ti, token, err := c.CreateToken("fred", "ci", nil, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c.Authenticate("fred", "supersecure")
+	c.AuthenticateFrom("fred", "wrong", "10.0.0.1:51234")
+	c.AuthenticateTokenFrom(token, "10.0.0.2:8086")
+	c.AuthenticateToken(ti.ID + ".wrong")
+
+	exp := []audit.Event{
+		{Action: audit.ActionLogin, User: "fred", Outcome: audit.OutcomeSuccess},
+		{Action: audit.ActionLogin, User: "fred", Source: "10.0.0.1", Outcome: audit.OutcomeFailure, Error: meta.ErrAuthenticate.Error()},
+		{Action: audit.ActionToken, User: "fred", Source: "10.0.0.2", Token: ti.ID, Outcome: audit.OutcomeSuccess},
+		{Action: audit.ActionToken, Token: ti.ID, Outcome: audit.OutcomeFailure, Error: meta.ErrAuthenticate.Error()},
+	}
+	if !reflect.DeepEqual(events, exp) {
+		t.Fatalf("unexpected events:\ngot: %+v\nexp: %+v", events, exp)
+	}
+}
+
 func TestMetaClient_ContinuousQueries(t *testing.T) {
 	t.Parallel()
 
@@ -814,6 +1061,158 @@ func TestMetaClient_ContinuousQueries(t *testing.T) {
 	}
 }
 
+func TestMetaClient_ContinuousQueries_Run(t *testing.T) {
+	t.Parallel()
+
+	d, c := newClient()
+	defer os.RemoveAll(d)
+	defer c.Close()
+
+	if _, err := c.CreateDatabase("db0"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.CreateContinuousQuery("db0", "cq0", `SELECT count(value) INTO foo_count FROM foo GROUP BY time(10m)`); err != nil {
+		t.Fatal(err)
+	}
+
+	run := meta.ContinuousQueryRunInfo{
+		Time:          time.Unix(0, 100).UTC(),
+		Duration:      2 * time.Second,
+		PointsWritten: 10,
+		Err:           "timeout",
+	}
+	if err := c.SetContinuousQueryRuns(map[string]map[string]meta.ContinuousQueryRunInfo{
+		"db0": {"cq0": run, "not-a-cq": run},
+		"db1": {"cq0": run},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if got := c.Database("db0").ContinuousQueries[0].LastRun; !reflect.DeepEqual(got, run) {
+		t.Fatalf("unexpected run: got=%#v exp=%#v", got, run)
+	}
+
+	// The run must survive a round trip through the meta data.
+	data := c.Data()
+	buf, err := data.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var other meta.Data
+	if err := other.UnmarshalBinary(buf); err != nil {
+		t.Fatal(err)
+	} else if got := other.Database("db0").ContinuousQueries[0].LastRun; !reflect.DeepEqual(got, run) {
+		t.Fatalf("unexpected run after unmarshal: got=%#v exp=%#v", got, run)
+	}
+
+}
+
+func TestMetaClient_DownsamplePolicies(t *testing.T) {
+	t.Parallel()
+
+	d, c := newClient()
+	defer os.RemoveAll(d)
+	defer c.Close()
+
+	if _, err := c.CreateDatabase("db0"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.CreateRetentionPolicy("db0", &meta.RetentionPolicySpec{Name: "rp5m"}, false); err != nil {
+		t.Fatal(err)
+	}
+
+	dpi := meta.DownsamplePolicyInfo{
+		Name:                  "dp0",
+		SourceRetentionPolicy: "autogen",
+		TargetRetentionPolicy: "rp5m",
+		Interval:              5 * time.Minute,
+		Functions:             []string{"mean", "max"},
+	}
+	if err := c.CreateDownsamplePolicy("db0", dpi); err != nil {
+		t.Fatal(err)
+	}
+
+	// Creating the same policy again is a no-op.
+	if err := c.CreateDownsamplePolicy("db0", dpi); err != nil {
+		t.Fatal(err)
+	}
+
+	// Creating a different policy with the same name fails.
+	other := dpi
+	other.Interval = time.Hour
+	if err := c.CreateDownsamplePolicy("db0", other); err != meta.ErrDownsamplePolicyExists {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, tt := range []struct {
+		source, target string
+		interval       time.Duration
+		err            string
+	}{
+		{source: "autogen", target: "autogen", interval: time.Minute, err: meta.ErrDownsamplePolicySameRetentionPolicy.Error()},
+		{source: "autogen", target: "rp5m", interval: 0, err: meta.ErrDownsamplePolicyIntervalRequired.Error()},
+		{source: "nope", target: "rp5m", interval: time.Minute, err: "retention policy not found: nope"},
+	} {
+		err := c.CreateDownsamplePolicy("db0", meta.DownsamplePolicyInfo{
+			Name:                  "dp1",
+			SourceRetentionPolicy: tt.source,
+			TargetRetentionPolicy: tt.target,
+			Interval:              tt.interval,
+		})
+		if err == nil || err.Error() != tt.err {
+			t.Errorf("unexpected error: exp=%q got=%v", tt.err, err)
+		}
+	}
+
+	if got := c.Database("db0").DownsamplePolicies; len(got) != 1 || !reflect.DeepEqual(got[0], dpi) {
+		t.Fatalf("unexpected downsample policies: %#v", got)
+	}
+
+	// The policy must survive a round trip through the meta data.
+	data := c.Data()
+	buf, err := data.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var unmarshaled meta.Data
+	if err := unmarshaled.UnmarshalBinary(buf); err != nil {
+		t.Fatal(err)
+	} else if got := unmarshaled.Database("db0").DownsamplePolicies; len(got) != 1 || !reflect.DeepEqual(got[0], dpi) {
+		t.Fatalf("unexpected downsample policies after unmarshal: %#v", got)
+	}
+
+	// Renaming a retention policy updates the policies that use it.
+	rpu := &meta.RetentionPolicyUpdate{}
+	rpu.SetName("rollup")
+	if err := c.UpdateRetentionPolicy("db0", "rp5m", rpu, false); err != nil {
+		t.Fatal(err)
+	} else if got := c.Database("db0").DownsamplePolicies[0].TargetRetentionPolicy; got != "rollup" {
+		t.Fatalf("unexpected target retention policy: %s", got)
+	}
+
+	if err := c.DropDownsamplePolicy("db0", "dp0"); err != nil {
+		t.Fatal(err)
+	} else if got := c.Database("db0").DownsamplePolicies; len(got) != 0 {
+		t.Fatalf("unexpected downsample policies: %#v", got)
+	} else if err := c.DropDownsamplePolicy("db0", "dp0"); err != meta.ErrDownsamplePolicyNotFound {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Dropping a retention policy drops the policies that use it.
+	if err := c.CreateDownsamplePolicy("db0", meta.DownsamplePolicyInfo{
+		Name:                  "dp2",
+		SourceRetentionPolicy: "autogen",
+		TargetRetentionPolicy: "rollup",
+		Interval:              time.Minute,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.DropRetentionPolicy("db0", "rollup"); err != nil {
+		t.Fatal(err)
+	} else if got := c.Database("db0").DownsamplePolicies; len(got) != 0 {
+		t.Fatalf("unexpected downsample policies: %#v", got)
+	}
+}
+
 func TestMetaClient_Subscriptions_Create(t *testing.T) {
 	t.Parallel()
 
@@ -1168,3 +1567,8 @@ func isAdmin(u meta.User) bool {
 	ui := u.(*meta.UserInfo)
 	return ui.Admin
 }
+
+// recorderFunc is an audit.Recorder that calls itself.
+type recorderFunc func(e audit.Event)
+
+func (fn recorderFunc) Record(e audit.Event) { fn(e) }
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net"
 	"net/url"
+	"regexp"
 	"sort"
 	"strings"
 	"sync"
@@ -46,6 +47,10 @@ type Data struct {
 	ClusterID uint64
 	Databases []DatabaseInfo
 	Users     []UserInfo
+	Roles     []RoleInfo
+	Tokens    []TokenInfo
+
+	SecurityPolicies []SecurityPolicyInfo
 
 	// adminUserExists provides a constant time mechanism for determining
 	// if there is at least one admin user.
@@ -105,6 +110,21 @@ func (data *Data) DropDatabase(name string) error {
 			for i := range data.Users {
 				delete(data.Users[i].Privileges, name)
 			}
+
+			// Remove all role privileges associated with this database.
+			for i := range data.Roles {
+				ri := &data.Roles[i]
+				privileges := ri.Privileges[:0]
+				for _, p := range ri.Privileges {
+					if p.Database != name {
+						privileges = append(privileges, p)
+					}
+				}
+				ri.Privileges = privileges
+			}
+
+			// Remove all security policies of this database.
+			data.removeSecurityPolicies(func(pi *SecurityPolicyInfo) bool { return pi.Database == name })
 			break
 		}
 	}
@@ -155,7 +175,8 @@ func (data *Data) CreateRetentionPolicy(database string, rpi *RetentionPolicyInf
 		return influxdb.ErrDatabaseNotFound(database)
 	} else if rp := di.RetentionPolicy(rpi.Name); rp != nil {
 		// RP with that name already exists. Make sure they're the same.
-		if rp.ReplicaN != rpi.ReplicaN || rp.Duration != rpi.Duration || rp.ShardGroupDuration != rpi.ShardGroupDuration {
+		if rp.ReplicaN != rpi.ReplicaN || rp.Duration != rpi.Duration || rp.ShardGroupDuration != rpi.ShardGroupDuration ||
+			rp.FieldTypePolicy != rpi.FieldTypePolicy {
 			return ErrRetentionPolicyExists
 		}
 		// if they want to make it default, and it's not the default, it's not an identical command so it's an error
@@ -193,6 +214,15 @@ func (data *Data) DropRetentionPolicy(database, name string) error {
 		}
 	}
 
+	// Remove the downsample policies that read from or write to it.
+	policies := di.DownsamplePolicies[:0]
+	for _, dpi := range di.DownsamplePolicies {
+		if dpi.SourceRetentionPolicy != name && dpi.TargetRetentionPolicy != name {
+			policies = append(policies, dpi)
+		}
+	}
+	di.DownsamplePolicies = policies
+
 	return nil
 }
 
@@ -202,6 +232,7 @@ type RetentionPolicyUpdate struct {
 	Duration           *time.Duration
 	ReplicaN           *int
 	ShardGroupDuration *time.Duration
+	FieldTypePolicy    *string
 }
 
 // SetName sets the RetentionPolicyUpdate.Name.
@@ -216,6 +247,9 @@ func (rpu *RetentionPolicyUpdate) SetReplicaN(v int) { rpu.ReplicaN = &v }
 // SetShardGroupDuration sets the RetentionPolicyUpdate.ShardGroupDuration.
 func (rpu *RetentionPolicyUpdate) SetShardGroupDuration(v time.Duration) { rpu.ShardGroupDuration = &v }
 
+// SetFieldTypePolicy sets the RetentionPolicyUpdate.FieldTypePolicy.
+func (rpu *RetentionPolicyUpdate) SetFieldTypePolicy(v string) { rpu.FieldTypePolicy = &v }
+
 // UpdateRetentionPolicy updates an existing retention policy.
 func (data *Data) UpdateRetentionPolicy(database, name string, rpu *RetentionPolicyUpdate, makeDefault bool) error {
 	// Find database.
@@ -252,6 +286,17 @@ func (data *Data) UpdateRetentionPolicy(database, name string, rpu *RetentionPol
 	// Update fields.
 	if rpu.Name != nil {
 		rpi.Name = *rpu.Name
+
+		// Point the downsample policies at the new name.
+		for i := range di.DownsamplePolicies {
+			dpi := &di.DownsamplePolicies[i]
+			if dpi.SourceRetentionPolicy == name {
+				dpi.SourceRetentionPolicy = rpi.Name
+			}
+			if dpi.TargetRetentionPolicy == name {
+				dpi.TargetRetentionPolicy = rpi.Name
+			}
+		}
 	}
 	if rpu.Duration != nil {
 		rpi.Duration = *rpu.Duration
@@ -262,6 +307,9 @@ func (data *Data) UpdateRetentionPolicy(database, name string, rpu *RetentionPol
 	if rpu.ShardGroupDuration != nil {
 		rpi.ShardGroupDuration = normalisedShardDuration(*rpu.ShardGroupDuration, rpi.Duration)
 	}
+	if rpu.FieldTypePolicy != nil {
+		rpi.FieldTypePolicy = *rpu.FieldTypePolicy
+	}
 
 	if di.DefaultRetentionPolicy != rpi.Name && makeDefault {
 		di.DefaultRetentionPolicy = rpi.Name
@@ -460,6 +508,140 @@ func (data *Data) DropContinuousQuery(database, name string) error {
 	return nil
 }
 
+// SetContinuousQueryRun records the most recent run of a continuous query.
+func (data *Data) SetContinuousQueryRun(database, name string, run ContinuousQueryRunInfo) error {
+	di := data.Database(database)
+	if di == nil {
+		return influxdb.ErrDatabaseNotFound(database)
+	}
+
+	for i := range di.ContinuousQueries {
+		if di.ContinuousQueries[i].Name == name {
+			di.ContinuousQueries[i].LastRun = run
+			return nil
+		}
+	}
+	return ErrContinuousQueryNotFound
+}
+
+// CreateDownsamplePolicy adds a downsample policy to a database.
+func (data *Data) CreateDownsamplePolicy(database string, dpi DownsamplePolicyInfo) error {
+	di := data.Database(database)
+	if di == nil {
+		return influxdb.ErrDatabaseNotFound(database)
+	}
+
+	// Validate the policy.
+	if dpi.Name == "" {
+		return ErrDownsamplePolicyNameRequired
+	} else if dpi.Interval <= 0 {
+		return ErrDownsamplePolicyIntervalRequired
+	} else if dpi.SourceRetentionPolicy == dpi.TargetRetentionPolicy {
+		return ErrDownsamplePolicySameRetentionPolicy
+	}
+	for _, rp := range []string{dpi.SourceRetentionPolicy, dpi.TargetRetentionPolicy} {
+		if rp == "" || di.RetentionPolicy(rp) == nil {
+			return influxdb.ErrRetentionPolicyNotFound(rp)
+		}
+	}
+
+	// Ensure the name doesn't already exist with a different definition.
+	for _, other := range di.DownsamplePolicies {
+		if other.Name == dpi.Name {
+			if other.equal(dpi) {
+				return nil
+			}
+			return ErrDownsamplePolicyExists
+		}
+	}
+
+	di.DownsamplePolicies = append(di.DownsamplePolicies, dpi.clone())
+	return nil
+}
+
+// DropDownsamplePolicy removes a downsample policy.
+func (data *Data) DropDownsamplePolicy(database, name string) error {
+	di := data.Database(database)
+	if di == nil {
+		return influxdb.ErrDatabaseNotFound(database)
+	}
+
+	for i := range di.DownsamplePolicies {
+		if di.DownsamplePolicies[i].Name == name {
+			di.DownsamplePolicies = append(di.DownsamplePolicies[:i], di.DownsamplePolicies[i+1:]...)
+			return nil
+		}
+	}
+	return ErrDownsamplePolicyNotFound
+}
+
+// CreateMeasurementSchema adds a measurement schema to a database.
+func (data *Data) CreateMeasurementSchema(database string, msi MeasurementSchemaInfo) error {
+	di := data.Database(database)
+	if di == nil {
+		return influxdb.ErrDatabaseNotFound(database)
+	}
+
+	if err := msi.validate(); err != nil {
+		return err
+	}
+
+	// Ensure the measurement doesn't already have a different schema.
+	if other := di.MeasurementSchema(msi.Name); other != nil {
+		if other.equal(msi) {
+			return nil
+		}
+		return ErrMeasurementSchemaExists
+	}
+
+	di.MeasurementSchemas = append(di.MeasurementSchemas, msi.clone())
+	return nil
+}
+
+// DropMeasurementSchema removes the schema of a measurement.
+func (data *Data) DropMeasurementSchema(database, name string) error {
+	di := data.Database(database)
+	if di == nil {
+		return influxdb.ErrDatabaseNotFound(database)
+	}
+
+	for i := range di.MeasurementSchemas {
+		if di.MeasurementSchemas[i].Name == name {
+			di.MeasurementSchemas = append(di.MeasurementSchemas[:i], di.MeasurementSchemas[i+1:]...)
+			return nil
+		}
+	}
+	return ErrMeasurementSchemaNotFound
+}
+
+// SetQuota sets the quota of a database, replacing any existing quota.
+func (data *Data) SetQuota(database string, qi QuotaInfo) error {
+	di := data.Database(database)
+	if di == nil {
+		return influxdb.ErrDatabaseNotFound(database)
+	}
+
+	if err := qi.validate(); err != nil {
+		return err
+	}
+
+	di.Quota = &qi
+	return nil
+}
+
+// DropQuota removes the quota of a database.
+func (data *Data) DropQuota(database string) error {
+	di := data.Database(database)
+	if di == nil {
+		return influxdb.ErrDatabaseNotFound(database)
+	} else if di.Quota == nil {
+		return ErrQuotaNotFound
+	}
+
+	di.Quota = nil
+	return nil
+}
+
 // validateURL returns an error if the URL does not have a port or uses a scheme other than UDP or HTTP.
 func validateURL(input string) error {
 	u, err := url.Parse(input)
@@ -480,7 +662,9 @@ func validateURL(input string) error {
 }
 
 // CreateSubscription adds a named subscription to a database and retention policy.
-func (data *Data) CreateSubscription(database, rp, name, mode string, destinations []string) error {
+// The subscription forwards only the given fields of the points matching the
+// condition, or every point if they are empty.
+func (data *Data) CreateSubscription(database, rp, name, mode string, destinations, fields []string, condition string) error {
 	for _, d := range destinations {
 		if err := validateURL(d); err != nil {
 			return err
@@ -506,6 +690,8 @@ func (data *Data) CreateSubscription(database, rp, name, mode string, destinatio
 		Name:         name,
 		Mode:         mode,
 		Destinations: destinations,
+		Fields:       fields,
+		Condition:    condition,
 	})
 
 	return nil
@@ -572,6 +758,37 @@ func (data *Data) CreateUser(name, hash string, admin bool) error {
 	return nil
 }
 
+// userWithRoles returns a copy of a user that is also authorized with the
+// privileges of its roles, or nil if the user does not exist.
+func (data *Data) userWithRoles(username string) *UserInfo {
+	ui := data.user(username)
+	if ui == nil {
+		return nil
+	}
+
+	u := *ui
+	u.securityPolicies = data.securityPolicies(func(pi *SecurityPolicyInfo) bool { return pi.User == username })
+	for _, name := range u.Roles {
+		ri := data.Role(name)
+		if ri == nil {
+			continue
+		}
+		u.securityPolicies = append(u.securityPolicies, data.securityPolicies(func(pi *SecurityPolicyInfo) bool { return pi.Role == name })...)
+		for _, p := range ri.Privileges {
+			rp := rolePrivilege{PrivilegeInfo: p}
+			if p.Regex {
+				re, err := regexp.Compile(p.Measurement)
+				if err != nil {
+					continue
+				}
+				rp.regex = re
+			}
+			u.rolePrivileges = append(u.rolePrivileges, rp)
+		}
+	}
+	return &u
+}
+
 // DropUser removes an existing user by name.
 func (data *Data) DropUser(name string) error {
 	for i := range data.Users {
@@ -583,6 +800,18 @@ func (data *Data) DropUser(name string) error {
 			if wasAdmin {
 				data.adminUserExists = data.hasAdminUser()
 			}
+
+			// Revoke the tokens of the user.
+			tokens := data.Tokens[:0]
+			for _, ti := range data.Tokens {
+				if ti.User != name {
+					tokens = append(tokens, ti)
+				}
+			}
+			data.Tokens = tokens
+
+			// Remove the security policies of the user.
+			data.removeSecurityPolicies(func(pi *SecurityPolicyInfo) bool { return pi.User == name })
 			return nil
 		}
 	}
@@ -648,6 +877,350 @@ func (data *Data) SetAdminPrivilege(name string, admin bool) error {
 	return nil
 }
 
+// Role returns a role by name.
+func (data *Data) Role(name string) *RoleInfo {
+	for i := range data.Roles {
+		if data.Roles[i].Name == name {
+			return &data.Roles[i]
+		}
+	}
+	return nil
+}
+
+// CreateRole creates a new role.
+func (data *Data) CreateRole(name string) error {
+	if name == "" {
+		return ErrRoleNameRequired
+	} else if data.Role(name) != nil {
+		return ErrRoleExists
+	}
+
+	data.Roles = append(data.Roles, RoleInfo{Name: name})
+	return nil
+}
+
+// DropRole removes an existing role by name and revokes it from every user.
+func (data *Data) DropRole(name string) error {
+	for i := range data.Roles {
+		if data.Roles[i].Name == name {
+			data.Roles = append(data.Roles[:i], data.Roles[i+1:]...)
+
+			for j := range data.Users {
+				data.Users[j].removeRole(name)
+			}
+
+			// Remove the security policies of the role.
+			data.removeSecurityPolicies(func(pi *SecurityPolicyInfo) bool { return pi.Role == name })
+			return nil
+		}
+	}
+	return ErrRoleNotFound
+}
+
+// CloneRoles returns a copy of the role infos.
+func (data *Data) CloneRoles() []RoleInfo {
+	if len(data.Roles) == 0 {
+		return nil
+	}
+	roles := make([]RoleInfo, len(data.Roles))
+	for i := range data.Roles {
+		roles[i] = data.Roles[i].clone()
+	}
+	return roles
+}
+
+// GrantRolePrivilege grants a privilege to a role. The privilege is added to
+// any privilege the role already has on the same database and measurements.
+func (data *Data) GrantRolePrivilege(name string, p PrivilegeInfo) error {
+	ri := data.Role(name)
+	if ri == nil {
+		return ErrRoleNotFound
+	}
+
+	if data.Database(p.Database) == nil {
+		return influxdb.ErrDatabaseNotFound(p.Database)
+	}
+
+	if p.Regex {
+		if _, err := regexp.Compile(p.Measurement); err != nil {
+			return err
+		}
+	}
+
+	for i := range ri.Privileges {
+		if ri.Privileges[i].sameScope(p) {
+			ri.Privileges[i].Privilege |= p.Privilege
+			return nil
+		}
+	}
+	ri.Privileges = append(ri.Privileges, p)
+	return nil
+}
+
+// RevokeRolePrivilege revokes a privilege from a role. The privilege is
+// removed once the role has nothing left on its database and measurements.
+func (data *Data) RevokeRolePrivilege(name string, p PrivilegeInfo) error {
+	ri := data.Role(name)
+	if ri == nil {
+		return ErrRoleNotFound
+	}
+
+	for i := range ri.Privileges {
+		if !ri.Privileges[i].sameScope(p) {
+			continue
+		}
+		if ri.Privileges[i].Privilege &^= p.Privilege; ri.Privileges[i].Privilege == influxql.NoPrivileges {
+			ri.Privileges = append(ri.Privileges[:i], ri.Privileges[i+1:]...)
+		}
+		break
+	}
+	return nil
+}
+
+// GrantRole grants a role to a user.
+func (data *Data) GrantRole(name, username string) error {
+	if data.Role(name) == nil {
+		return ErrRoleNotFound
+	}
+
+	ui := data.user(username)
+	if ui == nil {
+		return ErrUserNotFound
+	}
+
+	for _, r := range ui.Roles {
+		if r == name {
+			return nil
+		}
+	}
+	ui.Roles = append(ui.Roles, name)
+	return nil
+}
+
+// RevokeRole revokes a role from a user.
+func (data *Data) RevokeRole(name, username string) error {
+	if data.Role(name) == nil {
+		return ErrRoleNotFound
+	}
+
+	ui := data.user(username)
+	if ui == nil {
+		return ErrUserNotFound
+	}
+
+	ui.removeRole(name)
+	return nil
+}
+
+// EffectivePrivileges returns the privileges of a user, including the
+// privileges of its roles. Privileges on the same database and measurements
+// are combined. They are sorted by database and measurement.
+func (data *Data) EffectivePrivileges(username string) ([]PrivilegeInfo, error) {
+	ui := data.userWithRoles(username)
+	if ui == nil {
+		return nil, ErrUserNotFound
+	}
+
+	var privileges []PrivilegeInfo
+	add := func(p PrivilegeInfo) {
+		for i := range privileges {
+			if privileges[i].sameScope(p) {
+				privileges[i].Privilege |= p.Privilege
+				return
+			}
+		}
+		privileges = append(privileges, p)
+	}
+	for database, p := range ui.Privileges {
+		add(PrivilegeInfo{Database: database, Privilege: p})
+	}
+	for _, rp := range ui.rolePrivileges {
+		add(rp.PrivilegeInfo)
+	}
+
+	sort.Slice(privileges, func(i, j int) bool {
+		if privileges[i].Database != privileges[j].Database {
+			return privileges[i].Database < privileges[j].Database
+		}
+		return privileges[i].Measurement < privileges[j].Measurement
+	})
+	return privileges, nil
+}
+
+// Token returns a token by its ID, or nil if the token does not exist.
+func (data *Data) Token(id string) *TokenInfo {
+	for i := range data.Tokens {
+		if data.Tokens[i].ID == id {
+			return &data.Tokens[i]
+		}
+	}
+	return nil
+}
+
+// CreateToken adds a token for an existing user.
+func (data *Data) CreateToken(ti TokenInfo) error {
+	if data.user(ti.User) == nil {
+		return ErrUserNotFound
+	} else if data.Token(ti.ID) != nil {
+		return ErrTokenExists
+	}
+
+	for database := range ti.Privileges {
+		if data.Database(database) == nil {
+			return influxdb.ErrDatabaseNotFound(database)
+		}
+	}
+
+	data.Tokens = append(data.Tokens, ti.clone())
+	return nil
+}
+
+// RevokeToken removes a token by its ID.
+func (data *Data) RevokeToken(id string) error {
+	for i := range data.Tokens {
+		if data.Tokens[i].ID == id {
+			data.Tokens = append(data.Tokens[:i], data.Tokens[i+1:]...)
+			return nil
+		}
+	}
+	return ErrTokenNotFound
+}
+
+// CloneTokens returns a copy of the token infos.
+func (data *Data) CloneTokens() []TokenInfo {
+	if len(data.Tokens) == 0 {
+		return nil
+	}
+	tokens := make([]TokenInfo, len(data.Tokens))
+	for i := range data.Tokens {
+		tokens[i] = data.Tokens[i].clone()
+	}
+	return tokens
+}
+
+// tokenUser returns a copy of the user of a token that is only authorized
+// within the privileges of the token, or nil if the user does not exist.
+func (data *Data) tokenUser(ti *TokenInfo) *UserInfo {
+	u := data.userWithRoles(ti.User)
+	if u == nil || len(ti.Privileges) == 0 {
+		return u
+	}
+
+	privileges := make(map[string]influxql.Privilege, len(ti.Privileges))
+	for database, p := range ti.Privileges {
+		if !u.Admin {
+			p &= u.Privileges[database]
+		}
+		if p != influxql.NoPrivileges {
+			privileges[database] = p
+		}
+	}
+
+	var rolePrivileges []rolePrivilege
+	if !u.Admin {
+		for _, rp := range u.rolePrivileges {
+			if rp.Privilege &= ti.Privileges[rp.Database]; rp.Privilege != influxql.NoPrivileges {
+				rolePrivileges = append(rolePrivileges, rp)
+			}
+		}
+	}
+
+	u.Admin = false
+	u.Privileges = privileges
+	u.rolePrivileges = rolePrivileges
+	return u
+}
+
+// SecurityPolicy returns the security policy of a user or role on a
+// database, or nil if the policy does not exist.
+func (data *Data) SecurityPolicy(database, user, role string) *SecurityPolicyInfo {
+	for i := range data.SecurityPolicies {
+		pi := &data.SecurityPolicies[i]
+		if pi.Database == database && pi.User == user && pi.Role == role {
+			return pi
+		}
+	}
+	return nil
+}
+
+// CreateSecurityPolicy adds a security policy for an existing user or role
+// on an existing database.
+func (data *Data) CreateSecurityPolicy(pi SecurityPolicyInfo) error {
+	if data.Database(pi.Database) == nil {
+		return influxdb.ErrDatabaseNotFound(pi.Database)
+	}
+
+	if (pi.User == "") == (pi.Role == "") {
+		return ErrSecurityPolicyPrincipalRequired
+	} else if pi.User != "" && data.user(pi.User) == nil {
+		return ErrUserNotFound
+	} else if pi.Role != "" && data.Role(pi.Role) == nil {
+		return ErrRoleNotFound
+	}
+
+	if _, err := influxql.ParseExpr(pi.Condition); err != nil {
+		return err
+	}
+
+	if data.SecurityPolicy(pi.Database, pi.User, pi.Role) != nil {
+		return ErrSecurityPolicyExists
+	}
+
+	data.SecurityPolicies = append(data.SecurityPolicies, pi)
+	return nil
+}
+
+// DropSecurityPolicy removes the security policy of a user or role on a database.
+func (data *Data) DropSecurityPolicy(database, user, role string) error {
+	for i := range data.SecurityPolicies {
+		pi := &data.SecurityPolicies[i]
+		if pi.Database == database && pi.User == user && pi.Role == role {
+			data.SecurityPolicies = append(data.SecurityPolicies[:i], data.SecurityPolicies[i+1:]...)
+			return nil
+		}
+	}
+	return ErrSecurityPolicyNotFound
+}
+
+// CloneSecurityPolicies returns a copy of the security policy infos.
+func (data *Data) CloneSecurityPolicies() []SecurityPolicyInfo {
+	if len(data.SecurityPolicies) == 0 {
+		return nil
+	}
+	policies := make([]SecurityPolicyInfo, len(data.SecurityPolicies))
+	copy(policies, data.SecurityPolicies)
+	return policies
+}
+
+// removeSecurityPolicies removes the security policies matching fn.
+func (data *Data) removeSecurityPolicies(fn func(pi *SecurityPolicyInfo) bool) {
+	policies := data.SecurityPolicies[:0]
+	for i := range data.SecurityPolicies {
+		if !fn(&data.SecurityPolicies[i]) {
+			policies = append(policies, data.SecurityPolicies[i])
+		}
+	}
+	data.SecurityPolicies = policies
+}
+
+// securityPolicies returns the compiled security policies matching fn.
+// Policies whose condition can no longer be parsed are skipped.
+func (data *Data) securityPolicies(fn func(pi *SecurityPolicyInfo) bool) []securityPolicy {
+	var policies []securityPolicy
+	for i := range data.SecurityPolicies {
+		pi := &data.SecurityPolicies[i]
+		if !fn(pi) {
+			continue
+		}
+		cond, err := influxql.ParseExpr(pi.Condition)
+		if err != nil {
+			continue
+		}
+		policies = append(policies, securityPolicy{database: pi.Database, condition: cond})
+	}
+	return policies
+}
+
 // AdminUserExists returns true if an admin user exists.
 func (data Data) AdminUserExists() bool {
 	return data.adminUserExists
@@ -685,6 +1258,9 @@ func (data *Data) Clone() *Data {
 
 	other.Databases = data.CloneDatabases()
 	other.Users = data.CloneUsers()
+	other.Roles = data.CloneRoles()
+	other.Tokens = data.CloneTokens()
+	other.SecurityPolicies = data.CloneSecurityPolicies()
 
 	return &other
 }
@@ -713,6 +1289,21 @@ func (data *Data) marshal() *internal.Data {
 		pb.Users[i] = data.Users[i].marshal()
 	}
 
+	pb.Roles = make([]*internal.RoleInfo, len(data.Roles))
+	for i := range data.Roles {
+		pb.Roles[i] = data.Roles[i].marshal()
+	}
+
+	pb.Tokens = make([]*internal.TokenInfo, len(data.Tokens))
+	for i := range data.Tokens {
+		pb.Tokens[i] = data.Tokens[i].marshal()
+	}
+
+	pb.SecurityPolicies = make([]*internal.SecurityPolicyInfo, len(data.SecurityPolicies))
+	for i := range data.SecurityPolicies {
+		pb.SecurityPolicies[i] = data.SecurityPolicies[i].marshal()
+	}
+
 	return pb
 }
 
@@ -735,6 +1326,30 @@ func (data *Data) unmarshal(pb *internal.Data) {
 		data.Users[i].unmarshal(x)
 	}
 
+	data.Roles = nil
+	if len(pb.GetRoles()) > 0 {
+		data.Roles = make([]RoleInfo, len(pb.GetRoles()))
+		for i, x := range pb.GetRoles() {
+			data.Roles[i].unmarshal(x)
+		}
+	}
+
+	data.Tokens = nil
+	if len(pb.GetTokens()) > 0 {
+		data.Tokens = make([]TokenInfo, len(pb.GetTokens()))
+		for i, x := range pb.GetTokens() {
+			data.Tokens[i].unmarshal(x)
+		}
+	}
+
+	data.SecurityPolicies = nil
+	if len(pb.GetSecurityPolicies()) > 0 {
+		data.SecurityPolicies = make([]SecurityPolicyInfo, len(pb.GetSecurityPolicies()))
+		for i, x := range pb.GetSecurityPolicies() {
+			data.SecurityPolicies[i].unmarshal(x)
+		}
+	}
+
 	// Exhaustively determine if there is an admin user. The marshalled cache
 	// value may not be correct.
 	data.adminUserExists = data.hasAdminUser()
@@ -916,6 +1531,9 @@ type DatabaseInfo struct {
 	DefaultRetentionPolicy string
 	RetentionPolicies      []RetentionPolicyInfo
 	ContinuousQueries      []ContinuousQueryInfo
+	DownsamplePolicies     []DownsamplePolicyInfo
+	MeasurementSchemas     []MeasurementSchemaInfo
+	Quota                  *QuotaInfo
 }
 
 // RetentionPolicy returns a retention policy by name.
@@ -935,6 +1553,17 @@ func (di DatabaseInfo) RetentionPolicy(name string) *RetentionPolicyInfo {
 	return nil
 }
 
+// MeasurementSchema returns the schema of a measurement or nil if the
+// measurement has no schema.
+func (di DatabaseInfo) MeasurementSchema(name string) *MeasurementSchemaInfo {
+	for i := range di.MeasurementSchemas {
+		if di.MeasurementSchemas[i].Name == name {
+			return &di.MeasurementSchemas[i]
+		}
+	}
+	return nil
+}
+
 // ShardInfos returns a list of all shards' info for the database.
 func (di DatabaseInfo) ShardInfos() []ShardInfo {
 	shards := map[uint64]*ShardInfo{}
@@ -979,6 +1608,28 @@ func (di DatabaseInfo) clone() DatabaseInfo {
 		}
 	}
 
+	// Copy downsample policies.
+	if di.DownsamplePolicies != nil {
+		other.DownsamplePolicies = make([]DownsamplePolicyInfo, len(di.DownsamplePolicies))
+		for i := range di.DownsamplePolicies {
+			other.DownsamplePolicies[i] = di.DownsamplePolicies[i].clone()
+		}
+	}
+
+	// Copy measurement schemas.
+	if di.MeasurementSchemas != nil {
+		other.MeasurementSchemas = make([]MeasurementSchemaInfo, len(di.MeasurementSchemas))
+		for i := range di.MeasurementSchemas {
+			other.MeasurementSchemas[i] = di.MeasurementSchemas[i].clone()
+		}
+	}
+
+	// Copy quota.
+	if di.Quota != nil {
+		quota := *di.Quota
+		other.Quota = &quota
+	}
+
 	return other
 }
 
@@ -997,6 +1648,20 @@ func (di DatabaseInfo) marshal() *internal.DatabaseInfo {
 	for i := range di.ContinuousQueries {
 		pb.ContinuousQueries[i] = di.ContinuousQueries[i].marshal()
 	}
+
+	pb.DownsamplePolicies = make([]*internal.DownsamplePolicyInfo, len(di.DownsamplePolicies))
+	for i := range di.DownsamplePolicies {
+		pb.DownsamplePolicies[i] = di.DownsamplePolicies[i].marshal()
+	}
+
+	pb.MeasurementSchemas = make([]*internal.MeasurementSchemaInfo, len(di.MeasurementSchemas))
+	for i := range di.MeasurementSchemas {
+		pb.MeasurementSchemas[i] = di.MeasurementSchemas[i].marshal()
+	}
+
+	if di.Quota != nil {
+		pb.Quota = di.Quota.marshal()
+	}
 	return pb
 }
 
@@ -1012,20 +1677,75 @@ func (di *DatabaseInfo) unmarshal(pb *internal.DatabaseInfo) {
 		}
 	}
 
-	if len(pb.GetContinuousQueries()) > 0 {
-		di.ContinuousQueries = make([]ContinuousQueryInfo, len(pb.GetContinuousQueries()))
-		for i, x := range pb.GetContinuousQueries() {
-			di.ContinuousQueries[i].unmarshal(x)
-		}
+	if len(pb.GetContinuousQueries()) > 0 {
+		di.ContinuousQueries = make([]ContinuousQueryInfo, len(pb.GetContinuousQueries()))
+		for i, x := range pb.GetContinuousQueries() {
+			di.ContinuousQueries[i].unmarshal(x)
+		}
+	}
+
+	if len(pb.GetDownsamplePolicies()) > 0 {
+		di.DownsamplePolicies = make([]DownsamplePolicyInfo, len(pb.GetDownsamplePolicies()))
+		for i, x := range pb.GetDownsamplePolicies() {
+			di.DownsamplePolicies[i].unmarshal(x)
+		}
+	}
+
+	if len(pb.GetMeasurementSchemas()) > 0 {
+		di.MeasurementSchemas = make([]MeasurementSchemaInfo, len(pb.GetMeasurementSchemas()))
+		for i, x := range pb.GetMeasurementSchemas() {
+			di.MeasurementSchemas[i].unmarshal(x)
+		}
+	}
+
+	if pb.Quota != nil {
+		di.Quota = &QuotaInfo{}
+		di.Quota.unmarshal(pb.GetQuota())
+	}
+}
+
+// QuotaInfo represents the limits on the resources a database may use. A zero
+// limit is unlimited.
+type QuotaInfo struct {
+	MaxDiskBytes         int64
+	MaxSeries            int64
+	MaxPointsPerSecond   int64
+	MaxConcurrentQueries int
+}
+
+// validate returns an error if any limit of the quota is negative.
+func (qi QuotaInfo) validate() error {
+	if qi.MaxDiskBytes < 0 || qi.MaxSeries < 0 || qi.MaxPointsPerSecond < 0 || qi.MaxConcurrentQueries < 0 {
+		return ErrQuotaInvalid
+	}
+	return nil
+}
+
+// marshal serializes to a protobuf representation.
+func (qi QuotaInfo) marshal() *internal.QuotaInfo {
+	return &internal.QuotaInfo{
+		MaxDiskBytes:         proto.Int64(qi.MaxDiskBytes),
+		MaxSeries:            proto.Int64(qi.MaxSeries),
+		MaxPointsPerSecond:   proto.Int64(qi.MaxPointsPerSecond),
+		MaxConcurrentQueries: proto.Int64(int64(qi.MaxConcurrentQueries)),
 	}
 }
 
+// unmarshal deserializes from a protobuf representation.
+func (qi *QuotaInfo) unmarshal(pb *internal.QuotaInfo) {
+	qi.MaxDiskBytes = pb.GetMaxDiskBytes()
+	qi.MaxSeries = pb.GetMaxSeries()
+	qi.MaxPointsPerSecond = pb.GetMaxPointsPerSecond()
+	qi.MaxConcurrentQueries = int(pb.GetMaxConcurrentQueries())
+}
+
 // RetentionPolicySpec represents the specification for a new retention policy.
 type RetentionPolicySpec struct {
 	Name               string
 	ReplicaN           *int
 	Duration           *time.Duration
 	ShardGroupDuration time.Duration
+	FieldTypePolicy    string
 }
 
 // NewRetentionPolicyInfo creates a new retention policy info from the specification.
@@ -1044,6 +1764,8 @@ func (s *RetentionPolicySpec) Matches(rpi *RetentionPolicyInfo) bool {
 		return false
 	} else if s.ReplicaN != nil && *s.ReplicaN != rpi.ReplicaN {
 		return false
+	} else if s.FieldTypePolicy != rpi.FieldTypePolicy {
+		return false
 	}
 
 	// Normalise ShardDuration before comparing to any existing retention policies.
@@ -1068,6 +1790,9 @@ func (s *RetentionPolicySpec) marshal() *internal.RetentionPolicySpec {
 	if s.ReplicaN != nil {
 		pb.ReplicaN = proto.Uint32(uint32(*s.ReplicaN))
 	}
+	if s.FieldTypePolicy != "" {
+		pb.FieldTypePolicy = proto.String(s.FieldTypePolicy)
+	}
 	return pb
 }
 
@@ -1087,6 +1812,7 @@ func (s *RetentionPolicySpec) unmarshal(pb *internal.RetentionPolicySpec) {
 		replicaN := int(pb.GetReplicaN())
 		s.ReplicaN = &replicaN
 	}
+	s.FieldTypePolicy = pb.GetFieldTypePolicy()
 }
 
 // MarshalBinary encodes RetentionPolicySpec to a binary format.
@@ -1112,6 +1838,10 @@ type RetentionPolicyInfo struct {
 	ShardGroupDuration time.Duration
 	ShardGroups        []ShardGroupInfo
 	Subscriptions      []SubscriptionInfo
+
+	// FieldTypePolicy determines how values that conflict with the type of
+	// an existing field are written. Empty means they are rejected.
+	FieldTypePolicy string
 }
 
 // NewRetentionPolicyInfo returns a new instance of RetentionPolicyInfo
@@ -1137,6 +1867,7 @@ func (rpi *RetentionPolicyInfo) Apply(spec *RetentionPolicySpec) *RetentionPolic
 		ReplicaN:           rpi.ReplicaN,
 		Duration:           rpi.Duration,
 		ShardGroupDuration: rpi.ShardGroupDuration,
+		FieldTypePolicy:    rpi.FieldTypePolicy,
 	}
 	if spec.Name != "" {
 		rp.Name = spec.Name
@@ -1147,6 +1878,9 @@ func (rpi *RetentionPolicyInfo) Apply(spec *RetentionPolicySpec) *RetentionPolic
 	if spec.Duration != nil {
 		rp.Duration = *spec.Duration
 	}
+	if spec.FieldTypePolicy != "" {
+		rp.FieldTypePolicy = spec.FieldTypePolicy
+	}
 	rp.ShardGroupDuration = normalisedShardDuration(spec.ShardGroupDuration, rp.Duration)
 	return rp
 }
@@ -1208,6 +1942,10 @@ func (rpi *RetentionPolicyInfo) marshal() *internal.RetentionPolicyInfo {
 		pb.Subscriptions[i] = sub.marshal()
 	}
 
+	if rpi.FieldTypePolicy != "" {
+		pb.FieldTypePolicy = proto.String(rpi.FieldTypePolicy)
+	}
+
 	return pb
 }
 
@@ -1217,6 +1955,7 @@ func (rpi *RetentionPolicyInfo) unmarshal(pb *internal.RetentionPolicyInfo) {
 	rpi.ReplicaN = int(pb.GetReplicaN())
 	rpi.Duration = time.Duration(pb.GetDuration())
 	rpi.ShardGroupDuration = time.Duration(pb.GetShardGroupDuration())
+	rpi.FieldTypePolicy = pb.GetFieldTypePolicy()
 
 	if len(pb.GetShardGroups()) > 0 {
 		rpi.ShardGroups = make([]ShardGroupInfo, len(pb.GetShardGroups()))
@@ -1493,6 +2232,12 @@ type SubscriptionInfo struct {
 	Name         string
 	Mode         string
 	Destinations []string
+
+	// Fields forwarded to the destinations. All fields are forwarded if empty.
+	Fields []string
+
+	// Condition on the measurement and tags of the forwarded points.
+	Condition string
 }
 
 // marshal serializes to a protobuf representation.
@@ -1506,6 +2251,12 @@ func (si SubscriptionInfo) marshal() *internal.SubscriptionInfo {
 	for i := range si.Destinations {
 		pb.Destinations[i] = si.Destinations[i]
 	}
+
+	pb.Fields = make([]string, len(si.Fields))
+	copy(pb.Fields, si.Fields)
+	if si.Condition != "" {
+		pb.Condition = proto.String(si.Condition)
+	}
 	return pb
 }
 
@@ -1518,6 +2269,12 @@ func (si *SubscriptionInfo) unmarshal(pb *internal.SubscriptionInfo) {
 		si.Destinations = make([]string, len(pb.GetDestinations()))
 		copy(si.Destinations, pb.GetDestinations())
 	}
+
+	if len(pb.GetFields()) > 0 {
+		si.Fields = make([]string, len(pb.GetFields()))
+		copy(si.Fields, pb.GetFields())
+	}
+	si.Condition = pb.GetCondition()
 }
 
 // ShardOwner represents a node that owns a shard.
@@ -1546,6 +2303,10 @@ func (so *ShardOwner) unmarshal(pb *internal.ShardOwner) {
 type ContinuousQueryInfo struct {
 	Name  string
 	Query string
+
+	// LastRun describes the most recent run of the query. It is zero if
+	// the query has not run yet.
+	LastRun ContinuousQueryRunInfo
 }
 
 // clone returns a deep copy of cqi.
@@ -1553,16 +2314,270 @@ func (cqi ContinuousQueryInfo) clone() ContinuousQueryInfo { return cqi }
 
 // marshal serializes to a protobuf representation.
 func (cqi ContinuousQueryInfo) marshal() *internal.ContinuousQueryInfo {
-	return &internal.ContinuousQueryInfo{
+	pb := &internal.ContinuousQueryInfo{
 		Name:  proto.String(cqi.Name),
 		Query: proto.String(cqi.Query),
 	}
+
+	if !cqi.LastRun.Time.IsZero() {
+		pb.LastRunTime = proto.Int64(MarshalTime(cqi.LastRun.Time))
+		pb.LastRunDuration = proto.Int64(int64(cqi.LastRun.Duration))
+		pb.LastPointsWritten = proto.Int64(cqi.LastRun.PointsWritten)
+		if cqi.LastRun.Err != "" {
+			pb.LastError = proto.String(cqi.LastRun.Err)
+		}
+	}
+	return pb
 }
 
 // unmarshal deserializes from a protobuf representation.
 func (cqi *ContinuousQueryInfo) unmarshal(pb *internal.ContinuousQueryInfo) {
 	cqi.Name = pb.GetName()
 	cqi.Query = pb.GetQuery()
+	cqi.LastRun = ContinuousQueryRunInfo{
+		Time:          UnmarshalTime(pb.GetLastRunTime()),
+		Duration:      time.Duration(pb.GetLastRunDuration()),
+		PointsWritten: pb.GetLastPointsWritten(),
+		Err:           pb.GetLastError(),
+	}
+}
+
+// ContinuousQueryRunInfo represents the outcome of a single run of a continuous query.
+type ContinuousQueryRunInfo struct {
+	// Time is when the run started.
+	Time time.Time
+
+	// Duration is how long the run took.
+	Duration time.Duration
+
+	// PointsWritten is the number of points written into the target.
+	PointsWritten int64
+
+	// Err is the error that stopped the run, if there was one.
+	Err string
+}
+
+// DownsamplePolicyInfo represents metadata about a downsample policy. A
+// downsample policy rolls up every measurement written to one retention
+// policy into another at a fixed interval.
+type DownsamplePolicyInfo struct {
+	Name                  string
+	SourceRetentionPolicy string
+	TargetRetentionPolicy string
+	Interval              time.Duration
+
+	// Functions are the aggregates applied to numeric fields. String and
+	// boolean fields always use last. If empty, the default functions are used.
+	Functions []string
+}
+
+// clone returns a deep copy of dpi.
+func (dpi DownsamplePolicyInfo) clone() DownsamplePolicyInfo {
+	other := dpi
+	if dpi.Functions != nil {
+		other.Functions = make([]string, len(dpi.Functions))
+		copy(other.Functions, dpi.Functions)
+	}
+	return other
+}
+
+// equal returns true if the policies have the same definition.
+func (dpi DownsamplePolicyInfo) equal(other DownsamplePolicyInfo) bool {
+	if dpi.Name != other.Name ||
+		dpi.SourceRetentionPolicy != other.SourceRetentionPolicy ||
+		dpi.TargetRetentionPolicy != other.TargetRetentionPolicy ||
+		dpi.Interval != other.Interval ||
+		len(dpi.Functions) != len(other.Functions) {
+		return false
+	}
+	for i := range dpi.Functions {
+		if dpi.Functions[i] != other.Functions[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// marshal serializes to a protobuf representation.
+func (dpi DownsamplePolicyInfo) marshal() *internal.DownsamplePolicyInfo {
+	return &internal.DownsamplePolicyInfo{
+		Name:                  proto.String(dpi.Name),
+		SourceRetentionPolicy: proto.String(dpi.SourceRetentionPolicy),
+		TargetRetentionPolicy: proto.String(dpi.TargetRetentionPolicy),
+		Interval:              proto.Int64(int64(dpi.Interval)),
+		Functions:             dpi.Functions,
+	}
+}
+
+// unmarshal deserializes from a protobuf representation.
+func (dpi *DownsamplePolicyInfo) unmarshal(pb *internal.DownsamplePolicyInfo) {
+	dpi.Name = pb.GetName()
+	dpi.SourceRetentionPolicy = pb.GetSourceRetentionPolicy()
+	dpi.TargetRetentionPolicy = pb.GetTargetRetentionPolicy()
+	dpi.Interval = time.Duration(pb.GetInterval())
+	dpi.Functions = pb.GetFunctions()
+}
+
+// MeasurementSchemaInfo represents metadata about the schema of a
+// measurement. If any fields are declared, only those fields may be written
+// and only with their declared types. If any tags are declared, only those
+// tag keys may be written.
+type MeasurementSchemaInfo struct {
+	Name   string
+	Fields []MeasurementSchemaFieldInfo
+	Tags   []MeasurementSchemaTagInfo
+
+	// WarnOnly reports points that violate the schema instead of dropping them.
+	WarnOnly bool
+}
+
+// MeasurementSchemaFieldInfo represents a field declared by a measurement schema.
+type MeasurementSchemaFieldInfo struct {
+	Name string
+	Type influxql.DataType
+}
+
+// MeasurementSchemaTagInfo represents a tag key declared by a measurement schema.
+type MeasurementSchemaTagInfo struct {
+	Key string
+
+	// Required is true if every point must have the tag.
+	Required bool
+
+	// Regex must match every value of the tag if it is set.
+	Regex string
+
+	// MaxValues is the number of distinct values the tag may have in a
+	// shard. Zero means there is no limit.
+	MaxValues int
+}
+
+// validate returns an error if the schema cannot be enforced.
+func (msi MeasurementSchemaInfo) validate() error {
+	if msi.Name == "" {
+		return ErrMeasurementSchemaNameRequired
+	} else if len(msi.Fields) == 0 && len(msi.Tags) == 0 {
+		return ErrMeasurementSchemaEmpty
+	}
+
+	seen := make(map[string]struct{})
+	for _, f := range msi.Fields {
+		if _, ok := seen[f.Name]; ok {
+			return fmt.Errorf("duplicate field in measurement schema: %s", f.Name)
+		}
+		seen[f.Name] = struct{}{}
+
+		switch f.Type {
+		case influxql.Float, influxql.Integer, influxql.Unsigned, influxql.String, influxql.Boolean:
+		default:
+			return fmt.Errorf("invalid type for field %s in measurement schema: %s", f.Name, f.Type)
+		}
+	}
+
+	seen = make(map[string]struct{})
+	for _, t := range msi.Tags {
+		if _, ok := seen[t.Key]; ok {
+			return fmt.Errorf("duplicate tag key in measurement schema: %s", t.Key)
+		}
+		seen[t.Key] = struct{}{}
+
+		if t.MaxValues < 0 {
+			return fmt.Errorf("invalid limit for tag key %s in measurement schema: %d", t.Key, t.MaxValues)
+		} else if _, err := regexp.Compile(t.Regex); err != nil {
+			return fmt.Errorf("invalid regex for tag key %s in measurement schema: %s", t.Key, err)
+		}
+	}
+	return nil
+}
+
+// clone returns a deep copy of msi.
+func (msi MeasurementSchemaInfo) clone() MeasurementSchemaInfo {
+	other := msi
+	if msi.Fields != nil {
+		other.Fields = make([]MeasurementSchemaFieldInfo, len(msi.Fields))
+		copy(other.Fields, msi.Fields)
+	}
+	if msi.Tags != nil {
+		other.Tags = make([]MeasurementSchemaTagInfo, len(msi.Tags))
+		copy(other.Tags, msi.Tags)
+	}
+	return other
+}
+
+// equal returns true if the schemas have the same definition.
+func (msi MeasurementSchemaInfo) equal(other MeasurementSchemaInfo) bool {
+	if msi.Name != other.Name ||
+		msi.WarnOnly != other.WarnOnly ||
+		len(msi.Fields) != len(other.Fields) ||
+		len(msi.Tags) != len(other.Tags) {
+		return false
+	}
+	for i := range msi.Fields {
+		if msi.Fields[i] != other.Fields[i] {
+			return false
+		}
+	}
+	for i := range msi.Tags {
+		if msi.Tags[i] != other.Tags[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// marshal serializes to a protobuf representation.
+func (msi MeasurementSchemaInfo) marshal() *internal.MeasurementSchemaInfo {
+	pb := &internal.MeasurementSchemaInfo{
+		Name:     proto.String(msi.Name),
+		WarnOnly: proto.Bool(msi.WarnOnly),
+	}
+
+	pb.Fields = make([]*internal.MeasurementSchemaFieldInfo, len(msi.Fields))
+	for i, f := range msi.Fields {
+		pb.Fields[i] = &internal.MeasurementSchemaFieldInfo{
+			Name: proto.String(f.Name),
+			Type: proto.String(f.Type.String()),
+		}
+	}
+
+	pb.Tags = make([]*internal.MeasurementSchemaTagInfo, len(msi.Tags))
+	for i, t := range msi.Tags {
+		pb.Tags[i] = &internal.MeasurementSchemaTagInfo{
+			Key:       proto.String(t.Key),
+			Required:  proto.Bool(t.Required),
+			Regex:     proto.String(t.Regex),
+			MaxValues: proto.Int64(int64(t.MaxValues)),
+		}
+	}
+	return pb
+}
+
+// unmarshal deserializes from a protobuf representation.
+func (msi *MeasurementSchemaInfo) unmarshal(pb *internal.MeasurementSchemaInfo) {
+	msi.Name = pb.GetName()
+	msi.WarnOnly = pb.GetWarnOnly()
+
+	if len(pb.GetFields()) > 0 {
+		msi.Fields = make([]MeasurementSchemaFieldInfo, len(pb.GetFields()))
+		for i, x := range pb.GetFields() {
+			msi.Fields[i] = MeasurementSchemaFieldInfo{
+				Name: x.GetName(),
+				Type: influxql.DataTypeFromString(x.GetType()),
+			}
+		}
+	}
+
+	if len(pb.GetTags()) > 0 {
+		msi.Tags = make([]MeasurementSchemaTagInfo, len(pb.GetTags()))
+		for i, x := range pb.GetTags() {
+			msi.Tags[i] = MeasurementSchemaTagInfo{
+				Key:       x.GetKey(),
+				Required:  x.GetRequired(),
+				Regex:     x.GetRegex(),
+				MaxValues: int(x.GetMaxValues()),
+			}
+		}
+	}
 }
 
 var _ query.Authorizer = (*UserInfo)(nil)
@@ -1580,6 +2595,17 @@ type UserInfo struct {
 
 	// Map of database name to granted privilege.
 	Privileges map[string]influxql.Privilege
+
+	// Names of the roles granted to the user.
+	Roles []string
+
+	// Privileges of the user's roles. They are only set on the users
+	// returned for authorization.
+	rolePrivileges []rolePrivilege
+
+	// Security policies of the user and its roles. They are only set on the
+	// users returned for authorization.
+	securityPolicies []securityPolicy
 }
 
 type User interface {
@@ -1597,18 +2623,76 @@ func (ui *UserInfo) AuthorizeDatabase(privilege influxql.Privilege, database str
 	if ui.Admin || privilege == influxql.NoPrivileges {
 		return true
 	}
-	p, ok := ui.Privileges[database]
-	return ok && (p == privilege || p == influxql.AllPrivileges)
+	if p, ok := ui.Privileges[database]; ok && (p == privilege || p == influxql.AllPrivileges) {
+		return true
+	}
+	for _, rp := range ui.rolePrivileges {
+		if rp.Measurement == "" && rp.Database == database && rp.allows(privilege) {
+			return true
+		}
+	}
+	return false
+}
+
+// authorizeAnyMeasurement returns true if the user is authorized for the
+// given privilege on the given database or on some of its measurements.
+func (ui *UserInfo) authorizeAnyMeasurement(privilege influxql.Privilege, database string) bool {
+	if ui.AuthorizeDatabase(privilege, database) {
+		return true
+	}
+	for _, rp := range ui.rolePrivileges {
+		if rp.Database == database && rp.allows(privilege) {
+			return true
+		}
+	}
+	return false
+}
+
+// authorizeMeasurement returns true if the user is authorized for the given
+// privilege on a measurement of the given database.
+func (ui *UserInfo) authorizeMeasurement(privilege influxql.Privilege, database string, measurement []byte) bool {
+	if ui.AuthorizeDatabase(privilege, database) {
+		return true
+	}
+	for _, rp := range ui.rolePrivileges {
+		if rp.Database == database && rp.allows(privilege) && rp.matches(measurement) {
+			return true
+		}
+	}
+	return false
 }
 
-// AuthorizeSeriesRead is used to limit access per-series (enterprise only)
+// AuthorizeSeriesRead returns true if the user is authorized to read the
+// series of the given measurement.
 func (u *UserInfo) AuthorizeSeriesRead(database string, measurement []byte, tags models.Tags) bool {
-	return true
+	return u.authorizeMeasurement(influxql.ReadPrivilege, database, measurement) && u.authorizeTags(database, tags)
+}
+
+// authorizeTags returns true if the tags of a series match any of the
+// security policies of the user on the given database. Series of databases
+// without policies are not restricted.
+func (u *UserInfo) authorizeTags(database string, tags models.Tags) bool {
+	if u.Admin {
+		return true
+	}
+
+	restricted := false
+	for _, sp := range u.securityPolicies {
+		if sp.database != database {
+			continue
+		}
+		if sp.matches(tags) {
+			return true
+		}
+		restricted = true
+	}
+	return !restricted
 }
 
-// AuthorizeSeriesWrite is used to limit access per-series (enterprise only)
+// AuthorizeSeriesWrite returns true if the user is authorized to write the
+// series of the given measurement.
 func (u *UserInfo) AuthorizeSeriesWrite(database string, measurement []byte, tags models.Tags) bool {
-	return true
+	return u.authorizeMeasurement(influxql.WritePrivilege, database, measurement)
 }
 
 // AuthorizeUnrestricted allows admins to shortcut access checks.
@@ -1627,9 +2711,24 @@ func (ui UserInfo) clone() UserInfo {
 		}
 	}
 
+	if ui.Roles != nil {
+		other.Roles = make([]string, len(ui.Roles))
+		copy(other.Roles, ui.Roles)
+	}
+
 	return other
 }
 
+// removeRole removes a role from the roles of the user.
+func (ui *UserInfo) removeRole(name string) {
+	for i, r := range ui.Roles {
+		if r == name {
+			ui.Roles = append(ui.Roles[:i], ui.Roles[i+1:]...)
+			return
+		}
+	}
+}
+
 // marshal serializes to a protobuf representation.
 func (ui UserInfo) marshal() *internal.UserInfo {
 	pb := &internal.UserInfo{
@@ -1645,6 +2744,8 @@ func (ui UserInfo) marshal() *internal.UserInfo {
 		})
 	}
 
+	pb.Roles = ui.Roles
+
 	return pb
 }
 
@@ -1658,6 +2759,179 @@ func (ui *UserInfo) unmarshal(pb *internal.UserInfo) {
 	for _, p := range pb.GetPrivileges() {
 		ui.Privileges[p.GetDatabase()] = influxql.Privilege(p.GetPrivilege())
 	}
+
+	ui.Roles = pb.GetRoles()
+}
+
+// RoleInfo represents a named set of privileges that can be granted to users.
+type RoleInfo struct {
+	Name       string
+	Privileges []PrivilegeInfo
+}
+
+// clone returns a deep copy of ri.
+func (ri RoleInfo) clone() RoleInfo {
+	other := ri
+
+	if ri.Privileges != nil {
+		other.Privileges = make([]PrivilegeInfo, len(ri.Privileges))
+		copy(other.Privileges, ri.Privileges)
+	}
+
+	return other
+}
+
+// marshal serializes to a protobuf representation.
+func (ri RoleInfo) marshal() *internal.RoleInfo {
+	pb := &internal.RoleInfo{
+		Name: proto.String(ri.Name),
+	}
+
+	for _, p := range ri.Privileges {
+		pb.Privileges = append(pb.Privileges, &internal.RolePrivilege{
+			Database:    proto.String(p.Database),
+			Privilege:   proto.Int32(int32(p.Privilege)),
+			Measurement: proto.String(p.Measurement),
+			Regex:       proto.Bool(p.Regex),
+		})
+	}
+
+	return pb
+}
+
+// unmarshal deserializes from a protobuf representation.
+func (ri *RoleInfo) unmarshal(pb *internal.RoleInfo) {
+	ri.Name = pb.GetName()
+
+	ri.Privileges = nil
+	for _, p := range pb.GetPrivileges() {
+		ri.Privileges = append(ri.Privileges, PrivilegeInfo{
+			Database:    p.GetDatabase(),
+			Privilege:   influxql.Privilege(p.GetPrivilege()),
+			Measurement: p.GetMeasurement(),
+			Regex:       p.GetRegex(),
+		})
+	}
+}
+
+// PrivilegeInfo represents a privilege on a database that may be limited to
+// some of its measurements.
+type PrivilegeInfo struct {
+	Database  string
+	Privilege influxql.Privilege
+
+	// Measurement is the name of the measurement the privilege is limited
+	// to, or a regex matching them if Regex is set. The privilege applies to
+	// every measurement of the database if it is empty.
+	Measurement string
+	Regex       bool
+}
+
+// sameScope returns true if both privileges apply to the same database and
+// measurements.
+func (pi PrivilegeInfo) sameScope(other PrivilegeInfo) bool {
+	return pi.Database == other.Database && pi.Measurement == other.Measurement && pi.Regex == other.Regex
+}
+
+// allows returns true if the privilege grants the given privilege.
+func (pi PrivilegeInfo) allows(privilege influxql.Privilege) bool {
+	return pi.Privilege == privilege || pi.Privilege == influxql.AllPrivileges
+}
+
+// rolePrivilege is a privilege a user has through one of its roles.
+type rolePrivilege struct {
+	PrivilegeInfo
+	regex *regexp.Regexp
+}
+
+// matches returns true if the privilege applies to the given measurement.
+func (rp rolePrivilege) matches(measurement []byte) bool {
+	if rp.regex != nil {
+		return rp.regex.Match(measurement)
+	}
+	return rp.Measurement == "" || rp.Measurement == string(measurement)
+}
+
+// TokenInfo represents an API token of a user. Only a hash of the token's
+// secret is stored.
+type TokenInfo struct {
+	ID          string
+	User        string
+	Hash        string
+	Description string
+	CreatedAt   time.Time
+
+	// ExpiresAt is the time after which the token can no longer be used to
+	// authenticate. The token does not expire if it is zero.
+	ExpiresAt time.Time
+
+	// Privileges limits the token to the given privileges on each database.
+	// Privileges of the user beyond these are not available to the token. The
+	// token has every privilege of its user if it is empty.
+	Privileges map[string]influxql.Privilege
+}
+
+// Expired returns true if the token has expired at the given time.
+func (ti TokenInfo) Expired(now time.Time) bool {
+	return !ti.ExpiresAt.IsZero() && !now.Before(ti.ExpiresAt)
+}
+
+// clone returns a deep copy of ti.
+func (ti TokenInfo) clone() TokenInfo {
+	other := ti
+
+	if ti.Privileges != nil {
+		other.Privileges = make(map[string]influxql.Privilege, len(ti.Privileges))
+		for k, v := range ti.Privileges {
+			other.Privileges[k] = v
+		}
+	}
+
+	return other
+}
+
+// marshal serializes to a protobuf representation.
+func (ti TokenInfo) marshal() *internal.TokenInfo {
+	pb := &internal.TokenInfo{
+		ID:          proto.String(ti.ID),
+		User:        proto.String(ti.User),
+		Hash:        proto.String(ti.Hash),
+		Description: proto.String(ti.Description),
+		CreatedAt:   proto.Int64(ti.CreatedAt.UnixNano()),
+	}
+	if !ti.ExpiresAt.IsZero() {
+		pb.ExpiresAt = proto.Int64(ti.ExpiresAt.UnixNano())
+	}
+
+	for database, p := range ti.Privileges {
+		pb.Privileges = append(pb.Privileges, &internal.UserPrivilege{
+			Database:  proto.String(database),
+			Privilege: proto.Int32(int32(p)),
+		})
+	}
+
+	return pb
+}
+
+// unmarshal deserializes from a protobuf representation.
+func (ti *TokenInfo) unmarshal(pb *internal.TokenInfo) {
+	ti.ID = pb.GetID()
+	ti.User = pb.GetUser()
+	ti.Hash = pb.GetHash()
+	ti.Description = pb.GetDescription()
+	ti.CreatedAt = time.Unix(0, pb.GetCreatedAt()).UTC()
+	ti.ExpiresAt = time.Time{}
+	if pb.ExpiresAt != nil {
+		ti.ExpiresAt = time.Unix(0, pb.GetExpiresAt()).UTC()
+	}
+
+	ti.Privileges = nil
+	if len(pb.GetPrivileges()) > 0 {
+		ti.Privileges = make(map[string]influxql.Privilege, len(pb.GetPrivileges()))
+		for _, p := range pb.GetPrivileges() {
+			ti.Privileges[p.GetDatabase()] = influxql.Privilege(p.GetPrivilege())
+		}
+	}
 }
 
 // Lease represents a lease held on a resource.
@@ -1741,3 +3015,57 @@ func ValidName(name string) bool {
 		name != ".." &&
 		!strings.ContainsAny(name, `/\`)
 }
+
+// SecurityPolicyInfo represents a condition on the tags of the series a user,
+// or the users granted a role, can read from a database.
+type SecurityPolicyInfo struct {
+	Database string
+
+	// User or Role is the name of the user or role the policy applies to.
+	// Only one of them is set.
+	User string
+	Role string
+
+	// Condition is the influxql expression the tags of a series must match.
+	Condition string
+}
+
+// marshal serializes to a protobuf representation.
+func (pi SecurityPolicyInfo) marshal() *internal.SecurityPolicyInfo {
+	return &internal.SecurityPolicyInfo{
+		Database:  proto.String(pi.Database),
+		User:      proto.String(pi.User),
+		Role:      proto.String(pi.Role),
+		Condition: proto.String(pi.Condition),
+	}
+}
+
+// unmarshal deserializes from a protobuf representation.
+func (pi *SecurityPolicyInfo) unmarshal(pb *internal.SecurityPolicyInfo) {
+	pi.Database = pb.GetDatabase()
+	pi.User = pb.GetUser()
+	pi.Role = pb.GetRole()
+	pi.Condition = pb.GetCondition()
+}
+
+// securityPolicy is a security policy whose condition has been parsed.
+type securityPolicy struct {
+	database  string
+	condition influxql.Expr
+}
+
+// matches returns true if the tags satisfy the condition of the policy.
+func (sp securityPolicy) matches(tags models.Tags) bool {
+	v := influxql.ValuerEval{Valuer: tagValuer(tags)}
+	ok, _ := v.Eval(sp.condition).(bool)
+	return ok
+}
+
+// tagValuer is an influxql.Valuer over the tags of a series. Missing tags
+// evaluate to an empty string.
+type tagValuer models.Tags
+
+// Value returns the value of the tag with the given key.
+func (v tagValuer) Value(key string) (interface{}, bool) {
+	return models.Tags(v).GetString(key), true
+}
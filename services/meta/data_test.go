@@ -143,6 +143,183 @@ func Test_Data_CreateRetentionPolicy(t *testing.T) {
 	}
 }
 
+func TestData_UpdateRetentionPolicy_FieldTypePolicy(t *testing.T) {
+	data := meta.Data{}
+	if err := data.CreateDatabase("foo"); err != nil {
+		t.Fatal(err)
+	}
+	if err := data.CreateRetentionPolicy("foo", &meta.RetentionPolicyInfo{
+		Name:     "bar",
+		ReplicaN: 1,
+		Duration: 24 * time.Hour,
+	}, false); err != nil {
+		t.Fatal(err)
+	}
+
+	rpu := &meta.RetentionPolicyUpdate{}
+	rpu.SetFieldTypePolicy("coerce-numeric")
+	if err := data.UpdateRetentionPolicy("foo", "bar", rpu, false); err != nil {
+		t.Fatal(err)
+	}
+
+	// The policy should survive a round trip through the binary format.
+	buf, err := data.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var other meta.Data
+	if err := other.UnmarshalBinary(buf); err != nil {
+		t.Fatal(err)
+	}
+
+	rp, err := other.RetentionPolicy("foo", "bar")
+	if err != nil {
+		t.Fatal(err)
+	} else if got, exp := rp.FieldTypePolicy, "coerce-numeric"; got != exp {
+		t.Fatalf("unexpected field type policy: got %q, exp %q", got, exp)
+	}
+}
+
+func TestData_CreateMeasurementSchema(t *testing.T) {
+	data := meta.Data{}
+	if err := data.CreateDatabase("foo"); err != nil {
+		t.Fatal(err)
+	}
+
+	msi := meta.MeasurementSchemaInfo{
+		Name:   "cpu",
+		Fields: []meta.MeasurementSchemaFieldInfo{{Name: "value", Type: influxql.Float}},
+		Tags: []meta.MeasurementSchemaTagInfo{
+			{Key: "host", Required: true},
+			{Key: "region", Regex: "^us-", MaxValues: 10},
+		},
+		WarnOnly: true,
+	}
+	if err := data.CreateMeasurementSchema("foo", msi); err != nil {
+		t.Fatal(err)
+	}
+
+	// Creating an identical schema is a no-op.
+	if err := data.CreateMeasurementSchema("foo", msi); err != nil {
+		t.Fatal(err)
+	}
+
+	other := msi
+	other.WarnOnly = false
+	if err := data.CreateMeasurementSchema("foo", other); err != meta.ErrMeasurementSchemaExists {
+		t.Fatalf("exp: %v, got %v", meta.ErrMeasurementSchemaExists, err)
+	}
+
+	invalid := meta.MeasurementSchemaInfo{
+		Name: "mem",
+		Tags: []meta.MeasurementSchemaTagInfo{{Key: "host", Regex: "("}},
+	}
+	if err := data.CreateMeasurementSchema("foo", invalid); err == nil {
+		t.Fatal("expected error for invalid regex")
+	}
+	if err := data.CreateMeasurementSchema("foo", meta.MeasurementSchemaInfo{Name: "mem"}); err != meta.ErrMeasurementSchemaEmpty {
+		t.Fatalf("exp: %v, got %v", meta.ErrMeasurementSchemaEmpty, err)
+	}
+
+	// The schema should survive a round trip through the binary format.
+	buf, err := data.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var clone meta.Data
+	if err := clone.UnmarshalBinary(buf); err != nil {
+		t.Fatal(err)
+	}
+	if got := clone.Database("foo").MeasurementSchema("cpu"); got == nil || !reflect.DeepEqual(*got, msi) {
+		t.Fatalf("unexpected measurement schema: %#v", got)
+	}
+
+	if err := clone.DropMeasurementSchema("foo", "cpu"); err != nil {
+		t.Fatal(err)
+	} else if err := clone.DropMeasurementSchema("foo", "cpu"); err != meta.ErrMeasurementSchemaNotFound {
+		t.Fatalf("exp: %v, got %v", meta.ErrMeasurementSchemaNotFound, err)
+	}
+}
+
+func TestData_Quota(t *testing.T) {
+	data := meta.Data{}
+	if err := data.CreateDatabase("foo"); err != nil {
+		t.Fatal(err)
+	}
+
+	qi := meta.QuotaInfo{MaxDiskBytes: 1 << 30, MaxSeries: 1000, MaxPointsPerSecond: 500, MaxConcurrentQueries: 4}
+	if err := data.SetQuota("foo", qi); err != nil {
+		t.Fatal(err)
+	} else if err := data.SetQuota("bar", qi); err == nil || err.Error() != influxdb.ErrDatabaseNotFound("bar").Error() {
+		t.Fatalf("unexpected error: %v", err)
+	} else if err := data.SetQuota("foo", meta.QuotaInfo{MaxSeries: -1}); err != meta.ErrQuotaInvalid {
+		t.Fatalf("exp: %v, got %v", meta.ErrQuotaInvalid, err)
+	}
+
+	// The quota should survive a round trip through the binary format.
+	buf, err := data.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var clone meta.Data
+	if err := clone.UnmarshalBinary(buf); err != nil {
+		t.Fatal(err)
+	}
+	if got := clone.Database("foo").Quota; got == nil || !reflect.DeepEqual(*got, qi) {
+		t.Fatalf("unexpected quota after round trip: %#v", got)
+	}
+
+	// Changing a clone leaves the original quota unchanged.
+	other := data.Clone()
+	other.Database("foo").Quota.MaxSeries = 1
+	if got := data.Database("foo").Quota.MaxSeries; got != 1000 {
+		t.Fatalf("unexpected max series: %d", got)
+	}
+
+	if err := data.DropQuota("foo"); err != nil {
+		t.Fatal(err)
+	} else if data.Database("foo").Quota != nil {
+		t.Fatal("expected quota to be dropped")
+	} else if err := data.DropQuota("foo"); err != meta.ErrQuotaNotFound {
+		t.Fatalf("exp: %v, got %v", meta.ErrQuotaNotFound, err)
+	}
+}
+
+func TestData_CreateSubscription_Filter(t *testing.T) {
+	data := meta.Data{}
+	if err := data.CreateDatabase("foo"); err != nil {
+		t.Fatal(err)
+	} else if err := data.CreateRetentionPolicy("foo", &meta.RetentionPolicyInfo{Name: "bar", ReplicaN: 1}, false); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := data.CreateSubscription("foo", "bar", "alerts", "ALL", []string{"udp://h0:9093"}, []string{"level"}, `_name =~ /^alerts\./`); err != nil {
+		t.Fatal(err)
+	}
+
+	// The filter should survive a round trip through the binary format.
+	buf, err := data.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var clone meta.Data
+	if err := clone.UnmarshalBinary(buf); err != nil {
+		t.Fatal(err)
+	}
+	exp := meta.SubscriptionInfo{
+		Name:         "alerts",
+		Mode:         "ALL",
+		Destinations: []string{"udp://h0:9093"},
+		Fields:       []string{"level"},
+		Condition:    `_name =~ /^alerts\./`,
+	}
+	if rpi, err := clone.RetentionPolicy("foo", "bar"); err != nil {
+		t.Fatal(err)
+	} else if len(rpi.Subscriptions) != 1 || !reflect.DeepEqual(rpi.Subscriptions[0], exp) {
+		t.Fatalf("unexpected subscriptions: %#v", rpi.Subscriptions)
+	}
+}
+
 func TestData_AdminUserExists(t *testing.T) {
 	data := meta.Data{}
 
@@ -247,6 +424,187 @@ func TestData_SetPrivilege(t *testing.T) {
 	}
 }
 
+func TestData_Roles(t *testing.T) {
+	data := meta.Data{}
+	if err := data.CreateDatabase("db0"); err != nil {
+		t.Fatal(err)
+	} else if err := data.CreateUser("user1", "", false); err != nil {
+		t.Fatal(err)
+	} else if err := data.SetPrivilege("user1", "db0", influxql.ReadPrivilege); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := data.CreateRole("ops"); err != nil {
+		t.Fatal(err)
+	} else if got, exp := data.CreateRole("ops"), meta.ErrRoleExists; got != exp {
+		t.Fatalf("got %v, expected %v", got, exp)
+	}
+
+	// Privileges may only be granted on existing databases with valid patterns.
+	if got, exp := data.GrantRolePrivilege("ops", meta.PrivilegeInfo{Database: "db1", Privilege: influxql.WritePrivilege}), influxdb.ErrDatabaseNotFound("db1"); got == nil || got.Error() != exp.Error() {
+		t.Fatalf("got %v, expected %v", got, exp)
+	} else if err := data.GrantRolePrivilege("ops", meta.PrivilegeInfo{Database: "db0", Privilege: influxql.WritePrivilege, Measurement: "(", Regex: true}); err == nil {
+		t.Fatal("expected error for invalid measurement pattern")
+	}
+
+	if err := data.GrantRolePrivilege("ops", meta.PrivilegeInfo{Database: "db0", Privilege: influxql.WritePrivilege, Measurement: "^cpu", Regex: true}); err != nil {
+		t.Fatal(err)
+	} else if got, exp := data.GrantRole("ops", "missing"), meta.ErrUserNotFound; got != exp {
+		t.Fatalf("got %v, expected %v", got, exp)
+	} else if err := data.GrantRole("ops", "user1"); err != nil {
+		t.Fatal(err)
+	}
+
+	// The role survives a round trip through the binary format.
+	buf, err := data.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var clone meta.Data
+	if err := clone.UnmarshalBinary(buf); err != nil {
+		t.Fatal(err)
+	}
+
+	privs, err := clone.EffectivePrivileges("user1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := []meta.PrivilegeInfo{
+		{Database: "db0", Privilege: influxql.ReadPrivilege},
+		{Database: "db0", Privilege: influxql.WritePrivilege, Measurement: "^cpu", Regex: true},
+	}
+	if !reflect.DeepEqual(privs, exp) {
+		t.Fatalf("unexpected privileges: %#v", privs)
+	}
+
+	// Dropping the role removes it from its members.
+	if err := clone.DropRole("ops"); err != nil {
+		t.Fatal(err)
+	} else if got, exp := clone.DropRole("ops"), meta.ErrRoleNotFound; got != exp {
+		t.Fatalf("got %v, expected %v", got, exp)
+	} else if u := clone.User("user1").(*meta.UserInfo); len(u.Roles) != 0 {
+		t.Fatalf("unexpected roles: %v", u.Roles)
+	}
+}
+
+func TestData_Tokens(t *testing.T) {
+	data := meta.Data{}
+	if err := data.CreateDatabase("db0"); err != nil {
+		t.Fatal(err)
+	} else if err := data.CreateUser("user1", "", false); err != nil {
+		t.Fatal(err)
+	}
+
+	ti := meta.TokenInfo{
+		ID:          "0123",
+		User:        "user1",
+		Hash:        "abcd",
+		Description: "ci",
+		CreatedAt:   time.Unix(0, 1).UTC(),
+		ExpiresAt:   time.Unix(0, 2).UTC(),
+		Privileges:  map[string]influxql.Privilege{"db0": influxql.WritePrivilege},
+	}
+	if err := data.CreateToken(ti); err != nil {
+		t.Fatal(err)
+	} else if got, exp := data.CreateToken(ti), meta.ErrTokenExists; got != exp {
+		t.Fatalf("got %v, expected %v", got, exp)
+	}
+
+	// When a database of the token does not exist, CreateToken returns an error.
+	other := ti
+	other.ID = "4567"
+	other.Privileges = map[string]influxql.Privilege{"db1": influxql.ReadPrivilege}
+	if got, exp := data.CreateToken(other), influxdb.ErrDatabaseNotFound("db1"); got == nil || got.Error() != exp.Error() {
+		t.Fatalf("got %v, expected %v", got, exp)
+	}
+
+	// The token survives a round trip through the binary format.
+	buf, err := data.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var clone meta.Data
+	if err := clone.UnmarshalBinary(buf); err != nil {
+		t.Fatal(err)
+	}
+	if got := clone.Token("0123"); got == nil || !reflect.DeepEqual(*got, ti) {
+		t.Fatalf("unexpected token: %#v", got)
+	} else if !got.Expired(time.Unix(0, 2)) || got.Expired(time.Unix(0, 1)) {
+		t.Fatal("unexpected expiry")
+	}
+
+	if err := clone.RevokeToken("0123"); err != nil {
+		t.Fatal(err)
+	} else if got, exp := clone.RevokeToken("0123"), meta.ErrTokenNotFound; got != exp {
+		t.Fatalf("got %v, expected %v", got, exp)
+	}
+}
+
+func TestData_SecurityPolicies(t *testing.T) {
+	data := meta.Data{}
+	if err := data.CreateDatabase("db0"); err != nil {
+		t.Fatal(err)
+	} else if err := data.CreateUser("user1", "", false); err != nil {
+		t.Fatal(err)
+	} else if err := data.CreateRole("tenants"); err != nil {
+		t.Fatal(err)
+	}
+
+	pi := meta.SecurityPolicyInfo{Database: "db0", User: "user1", Condition: "customer = 'acme'"}
+	if err := data.CreateSecurityPolicy(pi); err != nil {
+		t.Fatal(err)
+	} else if got, exp := data.CreateSecurityPolicy(pi), meta.ErrSecurityPolicyExists; got != exp {
+		t.Fatalf("got %v, expected %v", got, exp)
+	} else if err := data.CreateSecurityPolicy(meta.SecurityPolicyInfo{Database: "db0", Role: "tenants", Condition: "region =~ /^eu-/"}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct {
+		pi  meta.SecurityPolicyInfo
+		err error
+	}{
+		{pi: meta.SecurityPolicyInfo{Database: "db0", Condition: "a = 'b'"}, err: meta.ErrSecurityPolicyPrincipalRequired},
+		{pi: meta.SecurityPolicyInfo{Database: "db0", User: "user1", Role: "tenants", Condition: "a = 'b'"}, err: meta.ErrSecurityPolicyPrincipalRequired},
+		{pi: meta.SecurityPolicyInfo{Database: "db0", User: "user2", Condition: "a = 'b'"}, err: meta.ErrUserNotFound},
+		{pi: meta.SecurityPolicyInfo{Database: "db0", Role: "admins", Condition: "a = 'b'"}, err: meta.ErrRoleNotFound},
+	} {
+		if got := data.CreateSecurityPolicy(tt.pi); got != tt.err {
+			t.Fatalf("%#v: got %v, expected %v", tt.pi, got, tt.err)
+		}
+	}
+	if got, exp := data.CreateSecurityPolicy(meta.SecurityPolicyInfo{Database: "db1", User: "user1", Condition: "a = 'b'"}), influxdb.ErrDatabaseNotFound("db1"); got == nil || got.Error() != exp.Error() {
+		t.Fatalf("got %v, expected %v", got, exp)
+	}
+
+	// The policies survive a round trip through the binary format.
+	buf, err := data.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var clone meta.Data
+	if err := clone.UnmarshalBinary(buf); err != nil {
+		t.Fatal(err)
+	}
+	if got := clone.SecurityPolicy("db0", "user1", ""); got == nil || !reflect.DeepEqual(*got, pi) {
+		t.Fatalf("unexpected security policy: %#v", got)
+	}
+
+	// Dropping the role drops its policies.
+	if err := clone.DropRole("tenants"); err != nil {
+		t.Fatal(err)
+	} else if got := clone.SecurityPolicy("db0", "", "tenants"); got != nil {
+		t.Fatalf("unexpected security policy: %#v", got)
+	}
+
+	if err := clone.DropSecurityPolicy("db0", "user1", ""); err != nil {
+		t.Fatal(err)
+	} else if got, exp := clone.DropSecurityPolicy("db0", "user1", ""), meta.ErrSecurityPolicyNotFound; got != exp {
+		t.Fatalf("got %v, expected %v", got, exp)
+	} else if len(clone.SecurityPolicies) != 0 {
+		t.Fatalf("unexpected security policies: %#v", clone.SecurityPolicies)
+	}
+}
+
 func TestData_TruncateShardGroups(t *testing.T) {
 	data := &meta.Data{}
 
@@ -91,6 +91,41 @@ var (
 	ErrContinuousQueryNotFound = errors.New("continuous query not found")
 )
 
+var (
+	// ErrDownsamplePolicyExists is returned when creating an already existing downsample policy.
+	ErrDownsamplePolicyExists = errors.New("downsample policy already exists")
+
+	// ErrDownsamplePolicyNotFound is returned when removing a downsample policy that doesn't exist.
+	ErrDownsamplePolicyNotFound = errors.New("downsample policy not found")
+
+	// ErrDownsamplePolicyNameRequired is returned when creating a downsample policy without a name.
+	ErrDownsamplePolicyNameRequired = errors.New("downsample policy name required")
+
+	// ErrDownsamplePolicyIntervalRequired is returned when creating a downsample
+	// policy without a positive interval.
+	ErrDownsamplePolicyIntervalRequired = errors.New("downsample policy interval must be positive")
+
+	// ErrDownsamplePolicySameRetentionPolicy is returned when a downsample
+	// policy would write into the retention policy it reads from.
+	ErrDownsamplePolicySameRetentionPolicy = errors.New("downsample policy must write to a different retention policy")
+)
+
+var (
+	// ErrMeasurementSchemaExists is returned when creating a schema for a
+	// measurement that already has a different one.
+	ErrMeasurementSchemaExists = errors.New("measurement schema already exists")
+
+	// ErrMeasurementSchemaNotFound is returned when removing a measurement schema that doesn't exist.
+	ErrMeasurementSchemaNotFound = errors.New("measurement schema not found")
+
+	// ErrMeasurementSchemaNameRequired is returned when creating a measurement schema without a name.
+	ErrMeasurementSchemaNameRequired = errors.New("measurement schema name required")
+
+	// ErrMeasurementSchemaEmpty is returned when creating a measurement schema
+	// that declares no fields or tags.
+	ErrMeasurementSchemaEmpty = errors.New("measurement schema must declare a field or tag")
+)
+
 var (
 	// ErrSubscriptionExists is returned when creating an already existing subscription.
 	ErrSubscriptionExists = errors.New("subscription already exists")
@@ -117,3 +152,48 @@ var (
 	// ErrAuthenticate is returned when authentication fails.
 	ErrAuthenticate = errors.New("authentication failed")
 )
+
+var (
+	// ErrRoleExists is returned when creating an already existing role.
+	ErrRoleExists = errors.New("role already exists")
+
+	// ErrRoleNotFound is returned when mutating a role that doesn't exist.
+	ErrRoleNotFound = errors.New("role not found")
+
+	// ErrRoleNameRequired is returned when creating a role without a name.
+	ErrRoleNameRequired = errors.New("role name required")
+)
+
+var (
+	// ErrTokenExists is returned when creating a token with an existing ID.
+	ErrTokenExists = errors.New("token already exists")
+
+	// ErrTokenNotFound is returned when mutating a token that doesn't exist.
+	ErrTokenNotFound = errors.New("token not found")
+
+	// ErrTokenExpired is returned when authenticating with an expired token.
+	ErrTokenExpired = errors.New("token expired")
+)
+
+var (
+	// ErrSecurityPolicyExists is returned when creating a security policy
+	// that already exists for the user or role on a database.
+	ErrSecurityPolicyExists = errors.New("security policy already exists")
+
+	// ErrSecurityPolicyNotFound is returned when dropping a security policy
+	// that doesn't exist.
+	ErrSecurityPolicyNotFound = errors.New("security policy not found")
+
+	// ErrSecurityPolicyPrincipalRequired is returned when creating a security
+	// policy without exactly one of a user or a role.
+	ErrSecurityPolicyPrincipalRequired = errors.New("security policy requires a user or a role")
+)
+
+var (
+	// ErrQuotaNotFound is returned when dropping the quota of a database
+	// that has none.
+	ErrQuotaNotFound = errors.New("quota not found")
+
+	// ErrQuotaInvalid is returned when setting a quota with a negative limit.
+	ErrQuotaInvalid = errors.New("quota limits cannot be negative")
+)
@@ -52,6 +52,15 @@ It has these top-level messages:
 	Response
 	SetMetaNodeCommand
 	DropShardCommand
+	DownsamplePolicyInfo
+	MeasurementSchemaInfo
+	MeasurementSchemaFieldInfo
+	MeasurementSchemaTagInfo
+	RoleInfo
+	RolePrivilege
+	TokenInfo
+	SecurityPolicyInfo
+	QuotaInfo
 */
 package meta
 
@@ -203,9 +212,12 @@ type Data struct {
 	MaxShardGroupID *uint64         `protobuf:"varint,8,req,name=MaxShardGroupID" json:"MaxShardGroupID,omitempty"`
 	MaxShardID      *uint64         `protobuf:"varint,9,req,name=MaxShardID" json:"MaxShardID,omitempty"`
 	// added for 0.10.0
-	DataNodes        []*NodeInfo `protobuf:"bytes,10,rep,name=DataNodes" json:"DataNodes,omitempty"`
-	MetaNodes        []*NodeInfo `protobuf:"bytes,11,rep,name=MetaNodes" json:"MetaNodes,omitempty"`
-	XXX_unrecognized []byte      `json:"-"`
+	DataNodes        []*NodeInfo           `protobuf:"bytes,10,rep,name=DataNodes" json:"DataNodes,omitempty"`
+	MetaNodes        []*NodeInfo           `protobuf:"bytes,11,rep,name=MetaNodes" json:"MetaNodes,omitempty"`
+	Roles            []*RoleInfo           `protobuf:"bytes,12,rep,name=Roles" json:"Roles,omitempty"`
+	Tokens           []*TokenInfo          `protobuf:"bytes,13,rep,name=Tokens" json:"Tokens,omitempty"`
+	SecurityPolicies []*SecurityPolicyInfo `protobuf:"bytes,14,rep,name=SecurityPolicies" json:"SecurityPolicies,omitempty"`
+	XXX_unrecognized []byte                `json:"-"`
 }
 
 func (m *Data) Reset()                    { *m = Data{} }
@@ -290,6 +302,27 @@ func (m *Data) GetMetaNodes() []*NodeInfo {
 	return nil
 }
 
+func (m *Data) GetRoles() []*RoleInfo {
+	if m != nil {
+		return m.Roles
+	}
+	return nil
+}
+
+func (m *Data) GetTokens() []*TokenInfo {
+	if m != nil {
+		return m.Tokens
+	}
+	return nil
+}
+
+func (m *Data) GetSecurityPolicies() []*SecurityPolicyInfo {
+	if m != nil {
+		return m.SecurityPolicies
+	}
+	return nil
+}
+
 type NodeInfo struct {
 	ID               *uint64 `protobuf:"varint,1,req,name=ID" json:"ID,omitempty"`
 	Host             *string `protobuf:"bytes,2,req,name=Host" json:"Host,omitempty"`
@@ -324,11 +357,14 @@ func (m *NodeInfo) GetTCPHost() string {
 }
 
 type DatabaseInfo struct {
-	Name                   *string                `protobuf:"bytes,1,req,name=Name" json:"Name,omitempty"`
-	DefaultRetentionPolicy *string                `protobuf:"bytes,2,req,name=DefaultRetentionPolicy" json:"DefaultRetentionPolicy,omitempty"`
-	RetentionPolicies      []*RetentionPolicyInfo `protobuf:"bytes,3,rep,name=RetentionPolicies" json:"RetentionPolicies,omitempty"`
-	ContinuousQueries      []*ContinuousQueryInfo `protobuf:"bytes,4,rep,name=ContinuousQueries" json:"ContinuousQueries,omitempty"`
-	XXX_unrecognized       []byte                 `json:"-"`
+	Name                   *string                  `protobuf:"bytes,1,req,name=Name" json:"Name,omitempty"`
+	DefaultRetentionPolicy *string                  `protobuf:"bytes,2,req,name=DefaultRetentionPolicy" json:"DefaultRetentionPolicy,omitempty"`
+	RetentionPolicies      []*RetentionPolicyInfo   `protobuf:"bytes,3,rep,name=RetentionPolicies" json:"RetentionPolicies,omitempty"`
+	ContinuousQueries      []*ContinuousQueryInfo   `protobuf:"bytes,4,rep,name=ContinuousQueries" json:"ContinuousQueries,omitempty"`
+	DownsamplePolicies     []*DownsamplePolicyInfo  `protobuf:"bytes,5,rep,name=DownsamplePolicies" json:"DownsamplePolicies,omitempty"`
+	MeasurementSchemas     []*MeasurementSchemaInfo `protobuf:"bytes,6,rep,name=MeasurementSchemas" json:"MeasurementSchemas,omitempty"`
+	Quota                  *QuotaInfo               `protobuf:"bytes,7,opt,name=Quota" json:"Quota,omitempty"`
+	XXX_unrecognized       []byte                   `json:"-"`
 }
 
 func (m *DatabaseInfo) Reset()                    { *m = DatabaseInfo{} }
@@ -364,11 +400,33 @@ func (m *DatabaseInfo) GetContinuousQueries() []*ContinuousQueryInfo {
 	return nil
 }
 
+func (m *DatabaseInfo) GetDownsamplePolicies() []*DownsamplePolicyInfo {
+	if m != nil {
+		return m.DownsamplePolicies
+	}
+	return nil
+}
+
+func (m *DatabaseInfo) GetMeasurementSchemas() []*MeasurementSchemaInfo {
+	if m != nil {
+		return m.MeasurementSchemas
+	}
+	return nil
+}
+
+func (m *DatabaseInfo) GetQuota() *QuotaInfo {
+	if m != nil {
+		return m.Quota
+	}
+	return nil
+}
+
 type RetentionPolicySpec struct {
 	Name               *string `protobuf:"bytes,1,opt,name=Name" json:"Name,omitempty"`
 	Duration           *int64  `protobuf:"varint,2,opt,name=Duration" json:"Duration,omitempty"`
 	ShardGroupDuration *int64  `protobuf:"varint,3,opt,name=ShardGroupDuration" json:"ShardGroupDuration,omitempty"`
 	ReplicaN           *uint32 `protobuf:"varint,4,opt,name=ReplicaN" json:"ReplicaN,omitempty"`
+	FieldTypePolicy    *string `protobuf:"bytes,5,opt,name=FieldTypePolicy" json:"FieldTypePolicy,omitempty"`
 	XXX_unrecognized   []byte  `json:"-"`
 }
 
@@ -405,6 +463,13 @@ func (m *RetentionPolicySpec) GetReplicaN() uint32 {
 	return 0
 }
 
+func (m *RetentionPolicySpec) GetFieldTypePolicy() string {
+	if m != nil && m.FieldTypePolicy != nil {
+		return *m.FieldTypePolicy
+	}
+	return ""
+}
+
 type RetentionPolicyInfo struct {
 	Name               *string             `protobuf:"bytes,1,req,name=Name" json:"Name,omitempty"`
 	Duration           *int64              `protobuf:"varint,2,req,name=Duration" json:"Duration,omitempty"`
@@ -412,6 +477,7 @@ type RetentionPolicyInfo struct {
 	ReplicaN           *uint32             `protobuf:"varint,4,req,name=ReplicaN" json:"ReplicaN,omitempty"`
 	ShardGroups        []*ShardGroupInfo   `protobuf:"bytes,5,rep,name=ShardGroups" json:"ShardGroups,omitempty"`
 	Subscriptions      []*SubscriptionInfo `protobuf:"bytes,6,rep,name=Subscriptions" json:"Subscriptions,omitempty"`
+	FieldTypePolicy    *string             `protobuf:"bytes,7,opt,name=FieldTypePolicy" json:"FieldTypePolicy,omitempty"`
 	XXX_unrecognized   []byte              `json:"-"`
 }
 
@@ -462,6 +528,13 @@ func (m *RetentionPolicyInfo) GetSubscriptions() []*SubscriptionInfo {
 	return nil
 }
 
+func (m *RetentionPolicyInfo) GetFieldTypePolicy() string {
+	if m != nil && m.FieldTypePolicy != nil {
+		return *m.FieldTypePolicy
+	}
+	return ""
+}
+
 type ShardGroupInfo struct {
 	ID               *uint64      `protobuf:"varint,1,req,name=ID" json:"ID,omitempty"`
 	StartTime        *int64       `protobuf:"varint,2,req,name=StartTime" json:"StartTime,omitempty"`
@@ -556,6 +629,8 @@ type SubscriptionInfo struct {
 	Name             *string  `protobuf:"bytes,1,req,name=Name" json:"Name,omitempty"`
 	Mode             *string  `protobuf:"bytes,2,req,name=Mode" json:"Mode,omitempty"`
 	Destinations     []string `protobuf:"bytes,3,rep,name=Destinations" json:"Destinations,omitempty"`
+	Fields           []string `protobuf:"bytes,4,rep,name=Fields" json:"Fields,omitempty"`
+	Condition        *string  `protobuf:"bytes,5,opt,name=Condition" json:"Condition,omitempty"`
 	XXX_unrecognized []byte   `json:"-"`
 }
 
@@ -585,6 +660,20 @@ func (m *SubscriptionInfo) GetDestinations() []string {
 	return nil
 }
 
+func (m *SubscriptionInfo) GetFields() []string {
+	if m != nil {
+		return m.Fields
+	}
+	return nil
+}
+
+func (m *SubscriptionInfo) GetCondition() string {
+	if m != nil && m.Condition != nil {
+		return *m.Condition
+	}
+	return ""
+}
+
 type ShardOwner struct {
 	NodeID           *uint64 `protobuf:"varint,1,req,name=NodeID" json:"NodeID,omitempty"`
 	XXX_unrecognized []byte  `json:"-"`
@@ -603,9 +692,13 @@ func (m *ShardOwner) GetNodeID() uint64 {
 }
 
 type ContinuousQueryInfo struct {
-	Name             *string `protobuf:"bytes,1,req,name=Name" json:"Name,omitempty"`
-	Query            *string `protobuf:"bytes,2,req,name=Query" json:"Query,omitempty"`
-	XXX_unrecognized []byte  `json:"-"`
+	Name              *string `protobuf:"bytes,1,req,name=Name" json:"Name,omitempty"`
+	Query             *string `protobuf:"bytes,2,req,name=Query" json:"Query,omitempty"`
+	LastRunTime       *int64  `protobuf:"varint,3,opt,name=LastRunTime" json:"LastRunTime,omitempty"`
+	LastRunDuration   *int64  `protobuf:"varint,4,opt,name=LastRunDuration" json:"LastRunDuration,omitempty"`
+	LastPointsWritten *int64  `protobuf:"varint,5,opt,name=LastPointsWritten" json:"LastPointsWritten,omitempty"`
+	LastError         *string `protobuf:"bytes,6,opt,name=LastError" json:"LastError,omitempty"`
+	XXX_unrecognized  []byte  `json:"-"`
 }
 
 func (m *ContinuousQueryInfo) Reset()                    { *m = ContinuousQueryInfo{} }
@@ -627,11 +720,40 @@ func (m *ContinuousQueryInfo) GetQuery() string {
 	return ""
 }
 
+func (m *ContinuousQueryInfo) GetLastRunTime() int64 {
+	if m != nil && m.LastRunTime != nil {
+		return *m.LastRunTime
+	}
+	return 0
+}
+
+func (m *ContinuousQueryInfo) GetLastRunDuration() int64 {
+	if m != nil && m.LastRunDuration != nil {
+		return *m.LastRunDuration
+	}
+	return 0
+}
+
+func (m *ContinuousQueryInfo) GetLastPointsWritten() int64 {
+	if m != nil && m.LastPointsWritten != nil {
+		return *m.LastPointsWritten
+	}
+	return 0
+}
+
+func (m *ContinuousQueryInfo) GetLastError() string {
+	if m != nil && m.LastError != nil {
+		return *m.LastError
+	}
+	return ""
+}
+
 type UserInfo struct {
 	Name             *string          `protobuf:"bytes,1,req,name=Name" json:"Name,omitempty"`
 	Hash             *string          `protobuf:"bytes,2,req,name=Hash" json:"Hash,omitempty"`
 	Admin            *bool            `protobuf:"varint,3,req,name=Admin" json:"Admin,omitempty"`
 	Privileges       []*UserPrivilege `protobuf:"bytes,4,rep,name=Privileges" json:"Privileges,omitempty"`
+	Roles            []string         `protobuf:"bytes,5,rep,name=Roles" json:"Roles,omitempty"`
 	XXX_unrecognized []byte           `json:"-"`
 }
 
@@ -668,6 +790,13 @@ func (m *UserInfo) GetPrivileges() []*UserPrivilege {
 	return nil
 }
 
+func (m *UserInfo) GetRoles() []string {
+	if m != nil {
+		return m.Roles
+	}
+	return nil
+}
+
 type UserPrivilege struct {
 	Database         *string `protobuf:"bytes,1,req,name=Database" json:"Database,omitempty"`
 	Privilege        *int32  `protobuf:"varint,2,req,name=Privilege" json:"Privilege,omitempty"`
@@ -1822,6 +1951,375 @@ var E_DropShardCommand_Command = &proto.ExtensionDesc{
 	Filename:      "internal/meta.proto",
 }
 
+type DownsamplePolicyInfo struct {
+	Name                  *string  `protobuf:"bytes,1,req,name=Name" json:"Name,omitempty"`
+	SourceRetentionPolicy *string  `protobuf:"bytes,2,req,name=SourceRetentionPolicy" json:"SourceRetentionPolicy,omitempty"`
+	TargetRetentionPolicy *string  `protobuf:"bytes,3,req,name=TargetRetentionPolicy" json:"TargetRetentionPolicy,omitempty"`
+	Interval              *int64   `protobuf:"varint,4,req,name=Interval" json:"Interval,omitempty"`
+	Functions             []string `protobuf:"bytes,5,rep,name=Functions" json:"Functions,omitempty"`
+	XXX_unrecognized      []byte   `json:"-"`
+}
+
+func (m *DownsamplePolicyInfo) Reset()                    { *m = DownsamplePolicyInfo{} }
+func (m *DownsamplePolicyInfo) String() string            { return proto.CompactTextString(m) }
+func (*DownsamplePolicyInfo) ProtoMessage()               {}
+func (*DownsamplePolicyInfo) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{43} }
+
+func (m *DownsamplePolicyInfo) GetName() string {
+	if m != nil && m.Name != nil {
+		return *m.Name
+	}
+	return ""
+}
+
+func (m *DownsamplePolicyInfo) GetSourceRetentionPolicy() string {
+	if m != nil && m.SourceRetentionPolicy != nil {
+		return *m.SourceRetentionPolicy
+	}
+	return ""
+}
+
+func (m *DownsamplePolicyInfo) GetTargetRetentionPolicy() string {
+	if m != nil && m.TargetRetentionPolicy != nil {
+		return *m.TargetRetentionPolicy
+	}
+	return ""
+}
+
+func (m *DownsamplePolicyInfo) GetInterval() int64 {
+	if m != nil && m.Interval != nil {
+		return *m.Interval
+	}
+	return 0
+}
+
+func (m *DownsamplePolicyInfo) GetFunctions() []string {
+	if m != nil {
+		return m.Functions
+	}
+	return nil
+}
+
+type MeasurementSchemaInfo struct {
+	Name             *string                       `protobuf:"bytes,1,req,name=Name" json:"Name,omitempty"`
+	Fields           []*MeasurementSchemaFieldInfo `protobuf:"bytes,2,rep,name=Fields" json:"Fields,omitempty"`
+	Tags             []*MeasurementSchemaTagInfo   `protobuf:"bytes,3,rep,name=Tags" json:"Tags,omitempty"`
+	WarnOnly         *bool                         `protobuf:"varint,4,opt,name=WarnOnly" json:"WarnOnly,omitempty"`
+	XXX_unrecognized []byte                        `json:"-"`
+}
+
+func (m *MeasurementSchemaInfo) Reset()                    { *m = MeasurementSchemaInfo{} }
+func (m *MeasurementSchemaInfo) String() string            { return proto.CompactTextString(m) }
+func (*MeasurementSchemaInfo) ProtoMessage()               {}
+func (*MeasurementSchemaInfo) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{44} }
+
+func (m *MeasurementSchemaInfo) GetName() string {
+	if m != nil && m.Name != nil {
+		return *m.Name
+	}
+	return ""
+}
+
+func (m *MeasurementSchemaInfo) GetFields() []*MeasurementSchemaFieldInfo {
+	if m != nil {
+		return m.Fields
+	}
+	return nil
+}
+
+func (m *MeasurementSchemaInfo) GetTags() []*MeasurementSchemaTagInfo {
+	if m != nil {
+		return m.Tags
+	}
+	return nil
+}
+
+func (m *MeasurementSchemaInfo) GetWarnOnly() bool {
+	if m != nil && m.WarnOnly != nil {
+		return *m.WarnOnly
+	}
+	return false
+}
+
+type MeasurementSchemaFieldInfo struct {
+	Name             *string `protobuf:"bytes,1,req,name=Name" json:"Name,omitempty"`
+	Type             *string `protobuf:"bytes,2,req,name=Type" json:"Type,omitempty"`
+	XXX_unrecognized []byte  `json:"-"`
+}
+
+func (m *MeasurementSchemaFieldInfo) Reset()                    { *m = MeasurementSchemaFieldInfo{} }
+func (m *MeasurementSchemaFieldInfo) String() string            { return proto.CompactTextString(m) }
+func (*MeasurementSchemaFieldInfo) ProtoMessage()               {}
+func (*MeasurementSchemaFieldInfo) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{45} }
+
+func (m *MeasurementSchemaFieldInfo) GetName() string {
+	if m != nil && m.Name != nil {
+		return *m.Name
+	}
+	return ""
+}
+
+func (m *MeasurementSchemaFieldInfo) GetType() string {
+	if m != nil && m.Type != nil {
+		return *m.Type
+	}
+	return ""
+}
+
+type MeasurementSchemaTagInfo struct {
+	Key              *string `protobuf:"bytes,1,req,name=Key" json:"Key,omitempty"`
+	Required         *bool   `protobuf:"varint,2,opt,name=Required" json:"Required,omitempty"`
+	Regex            *string `protobuf:"bytes,3,opt,name=Regex" json:"Regex,omitempty"`
+	MaxValues        *int64  `protobuf:"varint,4,opt,name=MaxValues" json:"MaxValues,omitempty"`
+	XXX_unrecognized []byte  `json:"-"`
+}
+
+func (m *MeasurementSchemaTagInfo) Reset()                    { *m = MeasurementSchemaTagInfo{} }
+func (m *MeasurementSchemaTagInfo) String() string            { return proto.CompactTextString(m) }
+func (*MeasurementSchemaTagInfo) ProtoMessage()               {}
+func (*MeasurementSchemaTagInfo) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{46} }
+
+func (m *MeasurementSchemaTagInfo) GetKey() string {
+	if m != nil && m.Key != nil {
+		return *m.Key
+	}
+	return ""
+}
+
+func (m *MeasurementSchemaTagInfo) GetRequired() bool {
+	if m != nil && m.Required != nil {
+		return *m.Required
+	}
+	return false
+}
+
+func (m *MeasurementSchemaTagInfo) GetRegex() string {
+	if m != nil && m.Regex != nil {
+		return *m.Regex
+	}
+	return ""
+}
+
+func (m *MeasurementSchemaTagInfo) GetMaxValues() int64 {
+	if m != nil && m.MaxValues != nil {
+		return *m.MaxValues
+	}
+	return 0
+}
+
+type RoleInfo struct {
+	Name             *string          `protobuf:"bytes,1,req,name=Name" json:"Name,omitempty"`
+	Privileges       []*RolePrivilege `protobuf:"bytes,2,rep,name=Privileges" json:"Privileges,omitempty"`
+	XXX_unrecognized []byte           `json:"-"`
+}
+
+func (m *RoleInfo) Reset()                    { *m = RoleInfo{} }
+func (m *RoleInfo) String() string            { return proto.CompactTextString(m) }
+func (*RoleInfo) ProtoMessage()               {}
+func (*RoleInfo) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{47} }
+
+func (m *RoleInfo) GetName() string {
+	if m != nil && m.Name != nil {
+		return *m.Name
+	}
+	return ""
+}
+
+func (m *RoleInfo) GetPrivileges() []*RolePrivilege {
+	if m != nil {
+		return m.Privileges
+	}
+	return nil
+}
+
+type RolePrivilege struct {
+	Database         *string `protobuf:"bytes,1,req,name=Database" json:"Database,omitempty"`
+	Privilege        *int32  `protobuf:"varint,2,req,name=Privilege" json:"Privilege,omitempty"`
+	Measurement      *string `protobuf:"bytes,3,opt,name=Measurement" json:"Measurement,omitempty"`
+	Regex            *bool   `protobuf:"varint,4,opt,name=Regex" json:"Regex,omitempty"`
+	XXX_unrecognized []byte  `json:"-"`
+}
+
+func (m *RolePrivilege) Reset()                    { *m = RolePrivilege{} }
+func (m *RolePrivilege) String() string            { return proto.CompactTextString(m) }
+func (*RolePrivilege) ProtoMessage()               {}
+func (*RolePrivilege) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{48} }
+
+func (m *RolePrivilege) GetDatabase() string {
+	if m != nil && m.Database != nil {
+		return *m.Database
+	}
+	return ""
+}
+
+func (m *RolePrivilege) GetPrivilege() int32 {
+	if m != nil && m.Privilege != nil {
+		return *m.Privilege
+	}
+	return 0
+}
+
+func (m *RolePrivilege) GetMeasurement() string {
+	if m != nil && m.Measurement != nil {
+		return *m.Measurement
+	}
+	return ""
+}
+
+func (m *RolePrivilege) GetRegex() bool {
+	if m != nil && m.Regex != nil {
+		return *m.Regex
+	}
+	return false
+}
+
+type TokenInfo struct {
+	ID               *string          `protobuf:"bytes,1,req,name=ID" json:"ID,omitempty"`
+	User             *string          `protobuf:"bytes,2,req,name=User" json:"User,omitempty"`
+	Hash             *string          `protobuf:"bytes,3,req,name=Hash" json:"Hash,omitempty"`
+	Description      *string          `protobuf:"bytes,4,opt,name=Description" json:"Description,omitempty"`
+	CreatedAt        *int64           `protobuf:"varint,5,req,name=CreatedAt" json:"CreatedAt,omitempty"`
+	ExpiresAt        *int64           `protobuf:"varint,6,opt,name=ExpiresAt" json:"ExpiresAt,omitempty"`
+	Privileges       []*UserPrivilege `protobuf:"bytes,7,rep,name=Privileges" json:"Privileges,omitempty"`
+	XXX_unrecognized []byte           `json:"-"`
+}
+
+func (m *TokenInfo) Reset()                    { *m = TokenInfo{} }
+func (m *TokenInfo) String() string            { return proto.CompactTextString(m) }
+func (*TokenInfo) ProtoMessage()               {}
+func (*TokenInfo) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{49} }
+
+func (m *TokenInfo) GetID() string {
+	if m != nil && m.ID != nil {
+		return *m.ID
+	}
+	return ""
+}
+
+func (m *TokenInfo) GetUser() string {
+	if m != nil && m.User != nil {
+		return *m.User
+	}
+	return ""
+}
+
+func (m *TokenInfo) GetHash() string {
+	if m != nil && m.Hash != nil {
+		return *m.Hash
+	}
+	return ""
+}
+
+func (m *TokenInfo) GetDescription() string {
+	if m != nil && m.Description != nil {
+		return *m.Description
+	}
+	return ""
+}
+
+func (m *TokenInfo) GetCreatedAt() int64 {
+	if m != nil && m.CreatedAt != nil {
+		return *m.CreatedAt
+	}
+	return 0
+}
+
+func (m *TokenInfo) GetExpiresAt() int64 {
+	if m != nil && m.ExpiresAt != nil {
+		return *m.ExpiresAt
+	}
+	return 0
+}
+
+func (m *TokenInfo) GetPrivileges() []*UserPrivilege {
+	if m != nil {
+		return m.Privileges
+	}
+	return nil
+}
+
+type SecurityPolicyInfo struct {
+	Database         *string `protobuf:"bytes,1,req,name=Database" json:"Database,omitempty"`
+	User             *string `protobuf:"bytes,2,opt,name=User" json:"User,omitempty"`
+	Role             *string `protobuf:"bytes,3,opt,name=Role" json:"Role,omitempty"`
+	Condition        *string `protobuf:"bytes,4,req,name=Condition" json:"Condition,omitempty"`
+	XXX_unrecognized []byte  `json:"-"`
+}
+
+func (m *SecurityPolicyInfo) Reset()                    { *m = SecurityPolicyInfo{} }
+func (m *SecurityPolicyInfo) String() string            { return proto.CompactTextString(m) }
+func (*SecurityPolicyInfo) ProtoMessage()               {}
+func (*SecurityPolicyInfo) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{50} }
+
+func (m *SecurityPolicyInfo) GetDatabase() string {
+	if m != nil && m.Database != nil {
+		return *m.Database
+	}
+	return ""
+}
+
+func (m *SecurityPolicyInfo) GetUser() string {
+	if m != nil && m.User != nil {
+		return *m.User
+	}
+	return ""
+}
+
+func (m *SecurityPolicyInfo) GetRole() string {
+	if m != nil && m.Role != nil {
+		return *m.Role
+	}
+	return ""
+}
+
+func (m *SecurityPolicyInfo) GetCondition() string {
+	if m != nil && m.Condition != nil {
+		return *m.Condition
+	}
+	return ""
+}
+
+type QuotaInfo struct {
+	MaxDiskBytes         *int64 `protobuf:"varint,1,opt,name=MaxDiskBytes" json:"MaxDiskBytes,omitempty"`
+	MaxSeries            *int64 `protobuf:"varint,2,opt,name=MaxSeries" json:"MaxSeries,omitempty"`
+	MaxPointsPerSecond   *int64 `protobuf:"varint,3,opt,name=MaxPointsPerSecond" json:"MaxPointsPerSecond,omitempty"`
+	MaxConcurrentQueries *int64 `protobuf:"varint,4,opt,name=MaxConcurrentQueries" json:"MaxConcurrentQueries,omitempty"`
+	XXX_unrecognized     []byte `json:"-"`
+}
+
+func (m *QuotaInfo) Reset()                    { *m = QuotaInfo{} }
+func (m *QuotaInfo) String() string            { return proto.CompactTextString(m) }
+func (*QuotaInfo) ProtoMessage()               {}
+func (*QuotaInfo) Descriptor() ([]byte, []int) { return fileDescriptorMeta, []int{51} }
+
+func (m *QuotaInfo) GetMaxDiskBytes() int64 {
+	if m != nil && m.MaxDiskBytes != nil {
+		return *m.MaxDiskBytes
+	}
+	return 0
+}
+
+func (m *QuotaInfo) GetMaxSeries() int64 {
+	if m != nil && m.MaxSeries != nil {
+		return *m.MaxSeries
+	}
+	return 0
+}
+
+func (m *QuotaInfo) GetMaxPointsPerSecond() int64 {
+	if m != nil && m.MaxPointsPerSecond != nil {
+		return *m.MaxPointsPerSecond
+	}
+	return 0
+}
+
+func (m *QuotaInfo) GetMaxConcurrentQueries() int64 {
+	if m != nil && m.MaxConcurrentQueries != nil {
+		return *m.MaxConcurrentQueries
+	}
+	return 0
+}
+
 func init() {
 	proto.RegisterType((*Data)(nil), "meta.Data")
 	proto.RegisterType((*NodeInfo)(nil), "meta.NodeInfo")
@@ -1866,6 +2364,15 @@ func init() {
 	proto.RegisterType((*Response)(nil), "meta.Response")
 	proto.RegisterType((*SetMetaNodeCommand)(nil), "meta.SetMetaNodeCommand")
 	proto.RegisterType((*DropShardCommand)(nil), "meta.DropShardCommand")
+	proto.RegisterType((*DownsamplePolicyInfo)(nil), "meta.DownsamplePolicyInfo")
+	proto.RegisterType((*MeasurementSchemaInfo)(nil), "meta.MeasurementSchemaInfo")
+	proto.RegisterType((*MeasurementSchemaFieldInfo)(nil), "meta.MeasurementSchemaFieldInfo")
+	proto.RegisterType((*MeasurementSchemaTagInfo)(nil), "meta.MeasurementSchemaTagInfo")
+	proto.RegisterType((*RoleInfo)(nil), "meta.RoleInfo")
+	proto.RegisterType((*RolePrivilege)(nil), "meta.RolePrivilege")
+	proto.RegisterType((*TokenInfo)(nil), "meta.TokenInfo")
+	proto.RegisterType((*SecurityPolicyInfo)(nil), "meta.SecurityPolicyInfo")
+	proto.RegisterType((*QuotaInfo)(nil), "meta.QuotaInfo")
 	proto.RegisterEnum("meta.Command_Type", Command_Type_name, Command_Type_value)
 	proto.RegisterExtension(E_CreateNodeCommand_Command)
 	proto.RegisterExtension(E_DeleteNodeCommand_Command)
@@ -1901,118 +2408,158 @@ func init() {
 func init() { proto.RegisterFile("internal/meta.proto", fileDescriptorMeta) }
 
 var fileDescriptorMeta = []byte{
-	// 1808 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x59, 0x4b, 0x6f, 0x1c, 0xc7,
-	0x11, 0x46, 0xcf, 0x3e, 0xb8, 0x5b, 0x7c, 0xaa, 0xf9, 0x1a, 0x4a, 0x14, 0xb3, 0x18, 0x08, 0xca,
-	0x22, 0x08, 0x98, 0x60, 0x03, 0xe8, 0x94, 0x97, 0xc4, 0x95, 0xc4, 0x85, 0xc0, 0x47, 0x66, 0xa9,
-	0x6b, 0x80, 0x11, 0xb7, 0x25, 0x6e, 0xb2, 0x3b, 0xb3, 0x99, 0x99, 0x95, 0xc4, 0x28, 0x4c, 0x18,
-	0x5f, 0x7c, 0xb5, 0x61, 0x18, 0x3e, 0xe8, 0x66, 0x1f, 0x7c, 0x34, 0x0c, 0x03, 0x06, 0x0c, 0x9f,
-	0x7c, 0xf7, 0x1f, 0xf0, 0x7f, 0xb0, 0xcf, 0xbe, 0x1a, 0xdd, 0x3d, 0x3d, 0xdd, 0x33, 0xd3, 0x3d,
-	0x24, 0x65, 0xf9, 0x36, 0x5d, 0x55, 0xdd, 0xf5, 0x55, 0x75, 0x75, 0x75, 0x55, 0x0f, 0x2c, 0x0f,
-	0xfd, 0x98, 0x84, 0xbe, 0x37, 0xfa, 0xdd, 0x98, 0xc4, 0xde, 0xf6, 0x24, 0x0c, 0xe2, 0x00, 0x57,
-	0xe9, 0xb7, 0xf3, 0x5e, 0x05, 0xaa, 0x5d, 0x2f, 0xf6, 0x30, 0x86, 0xea, 0x11, 0x09, 0xc7, 0x36,
-	0x6a, 0x59, 0xed, 0xaa, 0xcb, 0xbe, 0xf1, 0x0a, 0xd4, 0x7a, 0xfe, 0x80, 0xbc, 0xb4, 0x2d, 0x46,
-	0xe4, 0x03, 0xbc, 0x09, 0xcd, 0x9d, 0xd1, 0x34, 0x8a, 0x49, 0xd8, 0xeb, 0xda, 0x15, 0xc6, 0x91,
-	0x04, 0x7c, 0x0b, 0x6a, 0xfb, 0xc1, 0x80, 0x44, 0x76, 0xb5, 0x55, 0x69, 0xcf, 0x76, 0x16, 0xb6,
-	0x99, 0x4a, 0x4a, 0xea, 0xf9, 0x4f, 0x03, 0x97, 0x33, 0xf1, 0xef, 0xa1, 0x49, 0xb5, 0x3e, 0xf1,
-	0x22, 0x12, 0xd9, 0x35, 0x26, 0x89, 0xb9, 0xa4, 0x20, 0x33, 0x69, 0x29, 0x44, 0xd7, 0x7d, 0x1c,
-	0x91, 0x30, 0xb2, 0xeb, 0xea, 0xba, 0x94, 0xc4, 0xd7, 0x65, 0x4c, 0x8a, 0x6d, 0xcf, 0x7b, 0xc9,
-	0xb4, 0x75, 0xed, 0x19, 0x8e, 0x2d, 0x25, 0xe0, 0x36, 0x2c, 0xee, 0x79, 0x2f, 0xfb, 0x27, 0x5e,
-	0x38, 0x78, 0x18, 0x06, 0xd3, 0x49, 0xaf, 0x6b, 0x37, 0x98, 0x4c, 0x9e, 0x8c, 0xb7, 0x00, 0x04,
-	0xa9, 0xd7, 0xb5, 0x9b, 0x4c, 0x48, 0xa1, 0xe0, 0xdf, 0x72, 0xfc, 0xdc, 0x52, 0xd0, 0x5a, 0x2a,
-	0x05, 0xa8, 0xf4, 0x1e, 0x11, 0xd2, 0xb3, 0x7a, 0xe9, 0x54, 0xc0, 0xd9, 0x85, 0x86, 0x20, 0xe3,
-	0x05, 0xb0, 0x7a, 0xdd, 0x64, 0x4f, 0xac, 0x5e, 0x97, 0xee, 0xd2, 0x6e, 0x10, 0xc5, 0x6c, 0x43,
-	0x9a, 0x2e, 0xfb, 0xc6, 0x36, 0xcc, 0x1c, 0xed, 0x1c, 0x32, 0x72, 0xa5, 0x85, 0xda, 0x4d, 0x57,
-	0x0c, 0x9d, 0xef, 0x11, 0xcc, 0xa9, 0xfe, 0xa4, 0xd3, 0xf7, 0xbd, 0x31, 0x61, 0x0b, 0x36, 0x5d,
-	0xf6, 0x8d, 0xef, 0xc0, 0x5a, 0x97, 0x3c, 0xf5, 0xa6, 0xa3, 0xd8, 0x25, 0x31, 0xf1, 0xe3, 0x61,
-	0xe0, 0x1f, 0x06, 0xa3, 0xe1, 0xf1, 0x69, 0xa2, 0xc4, 0xc0, 0xc5, 0x0f, 0xe1, 0x5a, 0x96, 0x34,
-	0x24, 0x91, 0x5d, 0x61, 0xc6, 0x6d, 0x70, 0xe3, 0x72, 0x33, 0x98, 0x9d, 0xc5, 0x39, 0x74, 0xa1,
-	0x9d, 0xc0, 0x8f, 0x87, 0xfe, 0x34, 0x98, 0x46, 0x7f, 0x9b, 0x92, 0x70, 0x98, 0x46, 0x4f, 0xb2,
-	0x50, 0x96, 0x9d, 0x2c, 0x54, 0x98, 0xe3, 0xbc, 0x8f, 0x60, 0x39, 0xa7, 0xb3, 0x3f, 0x21, 0xc7,
-	0x8a, 0xd5, 0x28, 0xb5, 0xfa, 0x3a, 0x34, 0xba, 0xd3, 0xd0, 0xa3, 0x92, 0xb6, 0xd5, 0x42, 0xed,
-	0x8a, 0x9b, 0x8e, 0xf1, 0x36, 0x60, 0x19, 0x0c, 0xa9, 0x54, 0x85, 0x49, 0x69, 0x38, 0x74, 0x2d,
-	0x97, 0x4c, 0x46, 0xc3, 0x63, 0x6f, 0xdf, 0xae, 0xb6, 0x50, 0x7b, 0xde, 0x4d, 0xc7, 0xce, 0xbb,
-	0x56, 0x01, 0x93, 0x71, 0x27, 0xb2, 0x98, 0xac, 0x4b, 0x61, 0xb2, 0x2e, 0x85, 0xc9, 0x52, 0x31,
-	0xe1, 0x3b, 0x30, 0x2b, 0x67, 0x88, 0xe3, 0xb7, 0xc2, 0x5d, 0xad, 0x9c, 0x02, 0xea, 0x65, 0x55,
-	0x10, 0xff, 0x11, 0xe6, 0xfb, 0xd3, 0x27, 0xd1, 0x71, 0x38, 0x9c, 0x50, 0x1d, 0xe2, 0x28, 0xae,
-	0x25, 0x33, 0x15, 0x16, 0x9b, 0x9b, 0x15, 0x76, 0xbe, 0x41, 0xb0, 0x90, 0x5d, 0xbd, 0x10, 0xdd,
-	0x9b, 0xd0, 0xec, 0xc7, 0x5e, 0x18, 0x1f, 0x0d, 0xc7, 0x24, 0xf1, 0x80, 0x24, 0xd0, 0x38, 0xbf,
-	0xef, 0x0f, 0x18, 0x8f, 0xdb, 0x2d, 0x86, 0x74, 0x5e, 0x97, 0x8c, 0x48, 0x4c, 0x06, 0x77, 0x63,
-	0x66, 0x6d, 0xc5, 0x95, 0x04, 0xfc, 0x6b, 0xa8, 0x33, 0xbd, 0xc2, 0xd2, 0x45, 0xc5, 0x52, 0x06,
-	0x34, 0x61, 0xe3, 0x16, 0xcc, 0x1e, 0x85, 0x53, 0xff, 0xd8, 0xe3, 0x0b, 0xd5, 0xd9, 0x86, 0xab,
-	0x24, 0x87, 0x40, 0x33, 0x9d, 0x56, 0x40, 0xbf, 0x05, 0x8d, 0x83, 0x17, 0x3e, 0x4d, 0x82, 0x91,
-	0x6d, 0xb5, 0x2a, 0xed, 0xea, 0x3d, 0xcb, 0x46, 0x6e, 0x4a, 0xc3, 0x6d, 0xa8, 0xb3, 0x6f, 0x71,
-	0x4a, 0x96, 0x14, 0x1c, 0x8c, 0xe1, 0x26, 0x7c, 0xe7, 0xef, 0xb0, 0x94, 0xf7, 0xa6, 0x36, 0x60,
-	0x30, 0x54, 0xf7, 0x82, 0x01, 0x11, 0xd9, 0x80, 0x7e, 0x63, 0x07, 0xe6, 0xba, 0x24, 0x8a, 0x87,
-	0xbe, 0xc7, 0xf7, 0x88, 0xea, 0x6a, 0xba, 0x19, 0x9a, 0x73, 0x0b, 0x40, 0x6a, 0xc5, 0x6b, 0x50,
-	0x4f, 0x12, 0x26, 0xb7, 0x25, 0x19, 0x39, 0x7f, 0x81, 0x65, 0xcd, 0xc1, 0xd3, 0x02, 0x59, 0x81,
-	0x1a, 0x13, 0x48, 0x90, 0xf0, 0x81, 0x73, 0x06, 0x0d, 0x91, 0x9f, 0x4d, 0xf0, 0x77, 0xbd, 0xe8,
-	0x24, 0x4d, 0x66, 0x5e, 0x74, 0x42, 0x57, 0xba, 0x3b, 0x18, 0x0f, 0x79, 0x68, 0x37, 0x5c, 0x3e,
-	0xc0, 0x7f, 0x00, 0x38, 0x0c, 0x87, 0xcf, 0x87, 0x23, 0xf2, 0x2c, 0xcd, 0x0d, 0xcb, 0xf2, 0x06,
-	0x48, 0x79, 0xae, 0x22, 0xe6, 0xf4, 0x60, 0x3e, 0xc3, 0x64, 0xe7, 0x2b, 0xc9, 0x86, 0x09, 0x8e,
-	0x74, 0x4c, 0x43, 0x28, 0x15, 0x64, 0x80, 0x6a, 0xae, 0x24, 0x38, 0xdf, 0xd5, 0x61, 0x66, 0x27,
-	0x18, 0x8f, 0x3d, 0x7f, 0x80, 0x6f, 0x43, 0x35, 0x3e, 0x9d, 0xf0, 0x15, 0x16, 0xc4, 0xad, 0x95,
-	0x30, 0xb7, 0x8f, 0x4e, 0x27, 0xc4, 0x65, 0x7c, 0xe7, 0x75, 0x1d, 0xaa, 0x74, 0x88, 0x57, 0xe1,
-	0xda, 0x4e, 0x48, 0xbc, 0x98, 0x50, 0xbf, 0x26, 0x82, 0x4b, 0x88, 0x92, 0x79, 0x8c, 0xaa, 0x64,
-	0x0b, 0x6f, 0xc0, 0x2a, 0x97, 0x16, 0xd0, 0x04, 0xab, 0x82, 0xd7, 0x61, 0xb9, 0x1b, 0x06, 0x93,
-	0x3c, 0xa3, 0x8a, 0x5b, 0xb0, 0xc9, 0xe7, 0xe4, 0x32, 0x8d, 0x90, 0xa8, 0xe1, 0x2d, 0xb8, 0x4e,
-	0xa7, 0x1a, 0xf8, 0x75, 0x7c, 0x0b, 0x5a, 0x7d, 0x12, 0xeb, 0x33, 0xbd, 0x90, 0x9a, 0xa1, 0x7a,
-	0x1e, 0x4f, 0x06, 0x66, 0x3d, 0x0d, 0x7c, 0x03, 0xd6, 0x39, 0x12, 0x79, 0xd2, 0x05, 0xb3, 0x49,
-	0x99, 0xdc, 0xe2, 0x22, 0x13, 0xa4, 0x0d, 0xb9, 0x98, 0x13, 0x12, 0xb3, 0xc2, 0x06, 0x03, 0x7f,
-	0x4e, 0xfa, 0x99, 0xee, 0xba, 0x20, 0xcf, 0xe3, 0x65, 0x58, 0xa4, 0xd3, 0x54, 0xe2, 0x02, 0x95,
-	0xe5, 0x96, 0xa8, 0xe4, 0x45, 0xea, 0xe1, 0x3e, 0x89, 0xd3, 0x7d, 0x17, 0x8c, 0x25, 0x8c, 0x61,
-	0x81, 0xfa, 0xc7, 0x8b, 0x3d, 0x41, 0xbb, 0x86, 0x37, 0xc1, 0xee, 0x93, 0x98, 0x05, 0x68, 0x61,
-	0x06, 0x96, 0x1a, 0xd4, 0xed, 0x5d, 0xc6, 0x37, 0x61, 0x23, 0x71, 0x90, 0x72, 0xc0, 0x05, 0x7b,
-	0x95, 0xb9, 0x28, 0x0c, 0x26, 0x3a, 0xe6, 0x1a, 0x5d, 0xd2, 0x25, 0xe3, 0xe0, 0x39, 0x39, 0x24,
-	0x12, 0xf4, 0xba, 0x8c, 0x18, 0x51, 0x42, 0x08, 0x96, 0x9d, 0x0d, 0x26, 0x95, 0xb5, 0x41, 0x59,
-	0x1c, 0x5f, 0x9e, 0x75, 0x9d, 0xb2, 0xf8, 0x3e, 0xe5, 0x17, 0xbc, 0x21, 0x59, 0xf9, 0x59, 0x9b,
-	0x78, 0x0d, 0x70, 0x9f, 0xc4, 0xf9, 0x29, 0x37, 0xf1, 0x0a, 0x2c, 0x31, 0x93, 0xe8, 0x9e, 0x0b,
-	0xea, 0xd6, 0x6f, 0x1a, 0x8d, 0xc1, 0xd2, 0xf9, 0xf9, 0xf9, 0xb9, 0xe5, 0x9c, 0x69, 0x8e, 0x47,
-	0x5a, 0xe7, 0x20, 0xa5, 0xce, 0xc1, 0x50, 0x75, 0x3d, 0x7f, 0x90, 0x14, 0xa3, 0xec, 0xbb, 0xf3,
-	0x57, 0x98, 0x39, 0x4e, 0xa6, 0xcc, 0x67, 0x4e, 0xa2, 0x4d, 0x5a, 0xa8, 0x3d, 0xdb, 0x59, 0x4f,
-	0x88, 0x79, 0x05, 0xae, 0x98, 0xe6, 0xbc, 0xd2, 0x1c, 0xc3, 0x42, 0x6a, 0x5f, 0x81, 0xda, 0x83,
-	0x20, 0x3c, 0xe6, 0x99, 0xa1, 0xe1, 0xf2, 0x41, 0x89, 0xf2, 0xa7, 0xaa, 0xf2, 0xc2, 0xf2, 0x52,
-	0xf9, 0x97, 0xc8, 0x70, 0xda, 0xb5, 0xf9, 0x72, 0x07, 0x16, 0x8b, 0x25, 0x1a, 0x2a, 0xaf, 0xb7,
-	0xf2, 0x33, 0x3a, 0x5d, 0x23, 0xe8, 0x67, 0x6c, 0xad, 0x1b, 0xaa, 0xc7, 0x72, 0xa8, 0x24, 0xf0,
-	0xb1, 0x36, 0x15, 0xe9, 0x50, 0x77, 0xee, 0x19, 0x15, 0x9e, 0xa8, 0xe0, 0x35, 0xcb, 0x49, 0x75,
-	0xdf, 0xa2, 0xf2, 0x0c, 0x57, 0x9a, 0xda, 0xb5, 0x6e, 0xb3, 0xae, 0xe8, 0xb6, 0x47, 0x46, 0x2b,
-	0x86, 0xcc, 0x0a, 0x47, 0x75, 0x9b, 0x1e, 0xa4, 0x34, 0xe7, 0x23, 0x54, 0x96, 0x8e, 0x4b, 0x8d,
-	0x11, 0x1e, 0xb6, 0x14, 0x0f, 0xf7, 0x8c, 0xd8, 0xfe, 0xc1, 0xb0, 0xb5, 0xa4, 0x87, 0x2f, 0x42,
-	0xf6, 0x09, 0xba, 0xf8, 0x22, 0xb8, 0x32, 0xbe, 0x03, 0x23, 0xbe, 0x7f, 0x32, 0x7c, 0xb7, 0x93,
-	0x42, 0xe8, 0x02, 0xbd, 0x12, 0xe5, 0x0f, 0xa8, 0xfc, 0x22, 0xba, 0x2a, 0x42, 0x5a, 0x5a, 0xee,
-	0x93, 0x17, 0x8c, 0x9c, 0xb4, 0x50, 0xc9, 0x30, 0x53, 0x93, 0x57, 0x73, 0x7d, 0x82, 0x5a, 0x63,
-	0xd7, 0xb2, 0x75, 0x7f, 0x49, 0xbc, 0x8c, 0xd4, 0x78, 0x29, 0xb3, 0x42, 0xda, 0xfb, 0x05, 0x32,
-	0x5e, 0xab, 0xa5, 0xa6, 0xae, 0x41, 0x3d, 0xd3, 0xca, 0x25, 0x23, 0x5a, 0xec, 0xd0, 0xba, 0x39,
-	0x8a, 0xbd, 0xf1, 0x24, 0xa9, 0xa5, 0x25, 0xa1, 0xf3, 0xc0, 0x08, 0x7d, 0xcc, 0xa0, 0xdf, 0x54,
-	0x43, 0xbd, 0x00, 0x48, 0xa2, 0xfe, 0x0a, 0x19, 0xef, 0xfb, 0x37, 0x42, 0xed, 0xc0, 0x5c, 0xa6,
-	0x75, 0xe7, 0x4f, 0x0f, 0x19, 0x5a, 0x09, 0x76, 0x5f, 0xc5, 0x6e, 0x80, 0x25, 0xb1, 0x7f, 0x8e,
-	0xca, 0xcb, 0x91, 0x2b, 0x47, 0x58, 0x5a, 0x21, 0x57, 0x94, 0x0a, 0xb9, 0x24, 0x4a, 0x82, 0x62,
-	0x56, 0xd1, 0x23, 0x29, 0x66, 0x95, 0xb7, 0x83, 0xb8, 0x24, 0xab, 0x4c, 0xf2, 0x59, 0xe5, 0x22,
-	0x64, 0x1f, 0x20, 0x4d, 0x69, 0xf6, 0xf3, 0x5a, 0x82, 0x92, 0xcb, 0xf7, 0x5f, 0xc5, 0x9b, 0x5f,
-	0x51, 0x2b, 0x51, 0x91, 0x42, 0x61, 0xa8, 0xbd, 0xbf, 0xfe, 0x6c, 0x54, 0x14, 0x32, 0x45, 0xab,
-	0xd2, 0x0f, 0x5a, 0x35, 0x67, 0x9a, 0x52, 0xf3, 0xb2, 0xb6, 0x97, 0x58, 0x19, 0xa9, 0x56, 0x16,
-	0x14, 0x48, 0xf5, 0x9f, 0x21, 0x6d, 0x4d, 0x4b, 0xc3, 0x81, 0xca, 0xfb, 0x12, 0x45, 0x3a, 0xce,
-	0x84, 0x8a, 0x55, 0xd6, 0x28, 0x55, 0x72, 0x8d, 0x52, 0xc9, 0x65, 0x1f, 0xab, 0x97, 0xbd, 0x06,
-	0x90, 0x44, 0x1c, 0xe4, 0x6b, 0x6d, 0xbc, 0xc5, 0xdf, 0x28, 0x19, 0xce, 0xd9, 0x0e, 0xc8, 0x87,
-	0x42, 0x97, 0xd1, 0x3b, 0x7f, 0x32, 0x6a, 0x9d, 0x32, 0xad, 0x2b, 0xf2, 0x82, 0x91, 0xab, 0x4a,
-	0x85, 0x1f, 0x22, 0x73, 0x25, 0x5f, 0xea, 0xa7, 0x34, 0x32, 0x2d, 0x35, 0x32, 0x1f, 0x1a, 0xd1,
-	0x3c, 0x67, 0x68, 0xb6, 0x52, 0x34, 0x5a, 0x8d, 0x12, 0xd7, 0xa9, 0xa6, 0x85, 0xb8, 0xcc, 0x8b,
-	0x60, 0x49, 0xd4, 0xbc, 0x28, 0x46, 0x8d, 0xb6, 0x30, 0xfd, 0x11, 0x95, 0xf4, 0x29, 0xc6, 0xc7,
-	0x2b, 0x53, 0xcc, 0xb4, 0x8b, 0x15, 0x18, 0x4f, 0x83, 0x79, 0x72, 0xfa, 0xa2, 0x51, 0x2d, 0x79,
-	0xd1, 0xa8, 0x15, 0x5f, 0x34, 0x3a, 0xbb, 0x46, 0x8b, 0x4f, 0x99, 0xc5, 0xbf, 0xca, 0xdc, 0x59,
-	0x45, 0x93, 0xa4, 0xe5, 0x5f, 0x23, 0x63, 0x0b, 0xf6, 0xcb, 0xd9, 0x5d, 0x72, 0x6f, 0xfd, 0x3b,
-	0x73, 0x6f, 0xe9, 0x81, 0x65, 0x42, 0xa6, 0xd0, 0x22, 0xa6, 0x21, 0x83, 0x64, 0xc8, 0xdc, 0x1d,
-	0x0c, 0x42, 0x11, 0x32, 0xf4, 0xbb, 0x24, 0x64, 0x5e, 0xa9, 0x21, 0x53, 0x58, 0x5c, 0xaa, 0xfe,
-	0x14, 0x19, 0xfa, 0x50, 0xea, 0xa2, 0xdd, 0xa3, 0xa3, 0x43, 0xa6, 0x33, 0x39, 0x42, 0x62, 0x9c,
-	0x3c, 0x5e, 0x2b, 0x70, 0xc4, 0x30, 0x6d, 0xf7, 0x2a, 0x4a, 0xbb, 0x67, 0x6e, 0x5e, 0xfe, 0x53,
-	0x6c, 0x5e, 0x72, 0x30, 0x32, 0xd7, 0x91, 0xbe, 0x2d, 0x7e, 0x33, 0xa4, 0x25, 0xa8, 0xce, 0xf4,
-	0x2d, 0x95, 0x16, 0xd5, 0x6b, 0x64, 0xe8, 0xc8, 0xaf, 0xfe, 0x13, 0xc0, 0x52, 0x7e, 0x02, 0x94,
-	0xa0, 0xfb, 0xaf, 0x8a, 0x4e, 0xab, 0x5a, 0x6d, 0xf8, 0xf4, 0x6f, 0x02, 0x79, 0x70, 0x25, 0xea,
-	0xfe, 0xa7, 0xaa, 0xd3, 0x2e, 0x26, 0xd5, 0xf9, 0x86, 0x77, 0x86, 0x82, 0xba, 0xfb, 0x46, 0x75,
-	0xe7, 0xa8, 0xa8, 0xcf, 0x68, 0xde, 0x03, 0x5a, 0xca, 0x47, 0x93, 0xc0, 0x8f, 0x08, 0x55, 0x71,
-	0xf0, 0x88, 0xa9, 0x68, 0xb8, 0xd6, 0xc1, 0x23, 0x9a, 0xe5, 0xef, 0x87, 0x61, 0x10, 0xb2, 0x66,
-	0xbb, 0xe9, 0xf2, 0x81, 0xfc, 0x37, 0x56, 0x61, 0xe7, 0x8a, 0x0f, 0x9c, 0x8f, 0x91, 0xee, 0x15,
-	0xe4, 0x2d, 0x9e, 0x00, 0xf3, 0x05, 0xfb, 0x7f, 0x6e, 0xaf, 0x9d, 0xde, 0x2e, 0x46, 0xe7, 0x0e,
-	0x8a, 0x2f, 0x32, 0x05, 0xbf, 0x9a, 0xf3, 0xc1, 0x3b, 0x5c, 0xcf, 0x9a, 0x92, 0x91, 0x94, 0x85,
-	0x52, 0x2d, 0x3f, 0x05, 0x00, 0x00, 0xff, 0xff, 0xc7, 0x04, 0x86, 0xd9, 0x75, 0x1c, 0x00, 0x00,
+	// 2435 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x5a, 0x4b, 0x6f, 0x24, 0x49,
+	0xf1, 0x57, 0x56, 0x77, 0xdb, 0xdd, 0xe1, 0xe7, 0xa4, 0x1f, 0x53, 0xe3, 0xf1, 0xf8, 0xdf, 0x2a,
+	0xcd, 0x7f, 0xb1, 0xd0, 0x6a, 0x40, 0x0d, 0x5a, 0x71, 0x00, 0xc4, 0x8c, 0x7b, 0x3c, 0x6e, 0x8c,
+	0x1f, 0x5b, 0xdd, 0xcb, 0x9e, 0x6b, 0xbb, 0x73, 0xec, 0x62, 0xba, 0xab, 0x7a, 0xab, 0xaa, 0x3d,
+	0x36, 0xbb, 0x03, 0x5e, 0xbe, 0xc2, 0x82, 0x38, 0xec, 0x0d, 0x84, 0xb8, 0x20, 0xa1, 0x15, 0x02,
+	0x09, 0xed, 0x89, 0x3b, 0x07, 0x4e, 0x20, 0x3e, 0x04, 0x67, 0xae, 0x28, 0x5f, 0x95, 0x59, 0x55,
+	0x59, 0x35, 0xf6, 0xb0, 0xdc, 0x2a, 0x23, 0x22, 0x33, 0x7e, 0x91, 0x19, 0x19, 0x19, 0x91, 0x59,
+	0xb0, 0xe6, 0x07, 0x09, 0x89, 0x02, 0x6f, 0xfc, 0xb5, 0x09, 0x49, 0xbc, 0x47, 0xd3, 0x28, 0x4c,
+	0x42, 0x5c, 0xa7, 0xdf, 0xce, 0x6f, 0xea, 0x50, 0xef, 0x7a, 0x89, 0x87, 0x31, 0xd4, 0x07, 0x24,
+	0x9a, 0xd8, 0xa8, 0x6d, 0xed, 0xd6, 0x5d, 0xf6, 0x8d, 0xd7, 0xa1, 0xd1, 0x0b, 0x46, 0xe4, 0xd2,
+	0xb6, 0x18, 0x91, 0x37, 0xf0, 0x36, 0xb4, 0xf6, 0xc6, 0xb3, 0x38, 0x21, 0x51, 0xaf, 0x6b, 0xd7,
+	0x18, 0x47, 0x11, 0xf0, 0x43, 0x68, 0x1c, 0x87, 0x23, 0x12, 0xdb, 0xf5, 0x76, 0x6d, 0x77, 0xa1,
+	0xb3, 0xfc, 0x88, 0xa9, 0xa4, 0xa4, 0x5e, 0xf0, 0x3c, 0x74, 0x39, 0x13, 0x7f, 0x1d, 0x5a, 0x54,
+	0xeb, 0x07, 0x5e, 0x4c, 0x62, 0xbb, 0xc1, 0x24, 0x31, 0x97, 0x94, 0x64, 0x26, 0xad, 0x84, 0xe8,
+	0xb8, 0xef, 0xc5, 0x24, 0x8a, 0xed, 0x39, 0x7d, 0x5c, 0x4a, 0xe2, 0xe3, 0x32, 0x26, 0xc5, 0x76,
+	0xe4, 0x5d, 0x32, 0x6d, 0x5d, 0x7b, 0x9e, 0x63, 0x4b, 0x09, 0x78, 0x17, 0x56, 0x8e, 0xbc, 0xcb,
+	0xfe, 0xb9, 0x17, 0x8d, 0x9e, 0x45, 0xe1, 0x6c, 0xda, 0xeb, 0xda, 0x4d, 0x26, 0x93, 0x27, 0xe3,
+	0x1d, 0x00, 0x49, 0xea, 0x75, 0xed, 0x16, 0x13, 0xd2, 0x28, 0xf8, 0x6d, 0x8e, 0x9f, 0x5b, 0x0a,
+	0x46, 0x4b, 0x95, 0x00, 0x95, 0x3e, 0x22, 0x52, 0x7a, 0xc1, 0x2c, 0x9d, 0x0a, 0x50, 0x4b, 0xdd,
+	0x70, 0x4c, 0x62, 0x7b, 0x51, 0x97, 0xa4, 0x24, 0x6e, 0x29, 0x63, 0xe2, 0xaf, 0xc0, 0xdc, 0x20,
+	0x7c, 0x41, 0x82, 0xd8, 0x5e, 0x62, 0x62, 0x2b, 0x5c, 0x8c, 0xd1, 0x98, 0x9c, 0x60, 0xe3, 0x2e,
+	0xac, 0xf6, 0xc9, 0x70, 0x16, 0xf9, 0xc9, 0xd5, 0x69, 0x38, 0xf6, 0x87, 0x3e, 0x89, 0xed, 0x65,
+	0xd6, 0xc5, 0xe6, 0x5d, 0x32, 0xdc, 0x2b, 0xd6, 0xb7, 0xd0, 0xc3, 0x39, 0x80, 0xa6, 0xc4, 0x8a,
+	0x97, 0xc1, 0xea, 0x75, 0x85, 0xa3, 0x58, 0xbd, 0x2e, 0x75, 0x9d, 0x83, 0x30, 0x4e, 0x98, 0x97,
+	0xb4, 0x5c, 0xf6, 0x8d, 0x6d, 0x98, 0x1f, 0xec, 0x9d, 0x32, 0x72, 0xad, 0x8d, 0x76, 0x5b, 0xae,
+	0x6c, 0x3a, 0xbf, 0xab, 0xc1, 0xa2, 0xbe, 0xc8, 0xb4, 0xfb, 0xb1, 0x37, 0x21, 0x6c, 0xc0, 0x96,
+	0xcb, 0xbe, 0xf1, 0x3b, 0xb0, 0xd9, 0x25, 0xcf, 0xbd, 0xd9, 0x38, 0x71, 0x49, 0x42, 0x82, 0xc4,
+	0x0f, 0x03, 0x0e, 0x4f, 0x28, 0x29, 0xe1, 0xe2, 0x67, 0x70, 0x27, 0x4b, 0xa2, 0xd6, 0xd6, 0x98,
+	0xb5, 0xf7, 0xc4, 0x3c, 0x66, 0x7b, 0x30, 0x73, 0x8b, 0x7d, 0xe8, 0x40, 0x7b, 0x61, 0x90, 0xf8,
+	0xc1, 0x2c, 0x9c, 0xc5, 0xef, 0xce, 0x48, 0xe4, 0xa7, 0x2e, 0x2d, 0x06, 0xca, 0xb2, 0xc5, 0x40,
+	0x85, 0x3e, 0xf8, 0xfb, 0x80, 0xbb, 0xe1, 0xcb, 0x20, 0xf6, 0x26, 0xd3, 0x31, 0x49, 0x21, 0x71,
+	0x97, 0xdf, 0x12, 0x2e, 0x9f, 0xe5, 0xf3, 0xa1, 0x0c, 0xbd, 0xf0, 0x21, 0xe0, 0x23, 0xe2, 0xc5,
+	0xb3, 0x88, 0x4c, 0x48, 0x90, 0xf4, 0x87, 0xe7, 0x64, 0xe2, 0xc9, 0x0d, 0x71, 0x9f, 0x8f, 0x55,
+	0xe0, 0xf3, 0xc1, 0x8a, 0xdd, 0xf0, 0xff, 0x43, 0xe3, 0xdd, 0x59, 0x98, 0x78, 0xf6, 0x7c, 0x1b,
+	0x29, 0xff, 0x61, 0x24, 0xee, 0x67, 0xec, 0xd3, 0xf9, 0x02, 0xc1, 0x5a, 0x6e, 0xce, 0xfa, 0x53,
+	0x32, 0xd4, 0x56, 0x0d, 0xa5, 0xab, 0xb6, 0x05, 0xcd, 0xee, 0x2c, 0xf2, 0xa8, 0xa4, 0x6d, 0xb5,
+	0xd1, 0x6e, 0xcd, 0x4d, 0xdb, 0xf8, 0x11, 0x60, 0xb5, 0xc3, 0x52, 0xa9, 0x1a, 0x93, 0x32, 0x70,
+	0xe8, 0x58, 0x2e, 0x99, 0x8e, 0xfd, 0xa1, 0x77, 0x6c, 0xd7, 0xdb, 0x68, 0x77, 0xc9, 0x4d, 0xdb,
+	0x74, 0x1f, 0xef, 0xfb, 0x64, 0x3c, 0x1a, 0x5c, 0x4d, 0xc5, 0x94, 0xd9, 0x0d, 0x06, 0x23, 0x4f,
+	0x76, 0x3e, 0xb7, 0x0a, 0xe8, 0x4b, 0x7d, 0x2e, 0x8b, 0xde, 0xba, 0x11, 0x7a, 0xeb, 0x46, 0xe8,
+	0xad, 0x0c, 0xfa, 0x77, 0x60, 0x41, 0xf5, 0x90, 0xae, 0xb0, 0x2e, 0xf6, 0xa2, 0x0a, 0x42, 0x74,
+	0x0d, 0x74, 0x41, 0xfc, 0x6d, 0x58, 0xea, 0xcf, 0x3e, 0x88, 0x87, 0x91, 0x3f, 0xa5, 0x3a, 0xe4,
+	0xc2, 0x6f, 0x8a, 0x9e, 0x1a, 0x8b, 0xf5, 0xcd, 0x0a, 0x9b, 0xe6, 0x6c, 0xde, 0x3c, 0x67, 0x7f,
+	0x41, 0xb0, 0x9c, 0xc5, 0x51, 0xd8, 0xf1, 0xdb, 0xd0, 0xea, 0x27, 0x5e, 0x94, 0x0c, 0xfc, 0x09,
+	0x11, 0x73, 0xa5, 0x08, 0x74, 0xef, 0x3f, 0x0d, 0x46, 0x8c, 0xc7, 0x67, 0x48, 0x36, 0x69, 0xbf,
+	0x2e, 0x19, 0x93, 0x84, 0x8c, 0x1e, 0x27, 0x6c, 0x5e, 0x6a, 0xae, 0x22, 0xd0, 0x90, 0xc6, 0xf4,
+	0xca, 0x39, 0x59, 0xd1, 0xe6, 0x84, 0x87, 0x34, 0xce, 0xc6, 0x6d, 0x58, 0x18, 0x44, 0xb3, 0x60,
+	0xe8, 0xf1, 0x81, 0xe6, 0x98, 0x13, 0xe9, 0x24, 0x87, 0x40, 0x2b, 0xed, 0x56, 0x40, 0xbf, 0x03,
+	0xcd, 0x93, 0x97, 0x01, 0x3d, 0xad, 0x62, 0xdb, 0x6a, 0xd7, 0x76, 0xeb, 0x4f, 0x2c, 0x1b, 0xb9,
+	0x29, 0x0d, 0xef, 0xc2, 0x1c, 0xfb, 0x96, 0x91, 0x63, 0x55, 0xc3, 0xc1, 0x18, 0xae, 0xe0, 0x3b,
+	0x3f, 0x47, 0xb0, 0x9a, 0x9f, 0x78, 0xa3, 0x6f, 0x61, 0xa8, 0x1f, 0x85, 0x23, 0x22, 0x43, 0x24,
+	0xfd, 0xc6, 0x0e, 0x2c, 0x76, 0x49, 0x9c, 0xf8, 0x81, 0xc7, 0x97, 0x93, 0x2a, 0x6b, 0xb9, 0x19,
+	0x1a, 0xde, 0x84, 0x39, 0xb6, 0x3c, 0x3c, 0xf6, 0xb4, 0x5c, 0xd1, 0x62, 0x67, 0x70, 0x18, 0x8c,
+	0x7c, 0xe6, 0x86, 0xdc, 0xf7, 0x15, 0xc1, 0x79, 0x08, 0xa0, 0xc0, 0xd2, 0x31, 0xc4, 0x81, 0xc8,
+	0xa7, 0x40, 0xb4, 0x9c, 0x7f, 0x20, 0x58, 0x33, 0x04, 0x31, 0x23, 0xfe, 0x75, 0x1a, 0x2c, 0x48,
+	0x24, 0xc3, 0x2f, 0x6f, 0xd0, 0x75, 0xf8, 0x81, 0x17, 0x27, 0xee, 0x2c, 0x10, 0x8b, 0xcd, 0xd6,
+	0x41, 0x23, 0x51, 0xaf, 0x13, 0xcd, 0x74, 0xd3, 0xd4, 0x99, 0x54, 0x9e, 0x8c, 0xdf, 0x86, 0x3b,
+	0x94, 0x74, 0x1a, 0xfa, 0x41, 0x12, 0xbf, 0x1f, 0xf9, 0x49, 0x42, 0xb8, 0x65, 0x35, 0xb7, 0xc8,
+	0xa0, 0xf6, 0x53, 0xe2, 0xd3, 0x28, 0x0a, 0x23, 0xb6, 0xfe, 0x2d, 0x57, 0x11, 0x9c, 0x4f, 0x11,
+	0x34, 0x65, 0x66, 0x50, 0xb6, 0x1c, 0x07, 0x5e, 0x7c, 0x9e, 0x9e, 0x58, 0x5e, 0x7c, 0x4e, 0x4d,
+	0x7c, 0x3c, 0x9a, 0xf8, 0x7c, 0x57, 0x37, 0x5d, 0xde, 0xc0, 0xdf, 0x00, 0x38, 0x8d, 0xfc, 0x0b,
+	0x7f, 0x4c, 0xce, 0xd2, 0x03, 0x60, 0x4d, 0xe5, 0x1e, 0x29, 0xcf, 0xd5, 0xc4, 0xe8, 0x50, 0xfc,
+	0x04, 0x6f, 0xb0, 0x45, 0xe3, 0x0d, 0xa7, 0x07, 0x4b, 0x99, 0x2e, 0x2c, 0xe0, 0x88, 0x83, 0x50,
+	0xa0, 0x4b, 0xdb, 0xd4, 0xc0, 0x54, 0x90, 0xc1, 0x6c, 0xb8, 0x8a, 0xe0, 0xfc, 0x73, 0x0e, 0xe6,
+	0xf7, 0xc2, 0xc9, 0xc4, 0x0b, 0x46, 0xf8, 0x2d, 0xa8, 0x27, 0x57, 0x53, 0x3e, 0xc2, 0xb2, 0xcc,
+	0xa2, 0x04, 0xf3, 0x11, 0xdd, 0xd6, 0x2e, 0xe3, 0x3b, 0x9f, 0xcd, 0x41, 0x9d, 0x36, 0xf1, 0x06,
+	0xdc, 0xd9, 0x8b, 0x88, 0x97, 0x10, 0xea, 0x07, 0x42, 0x70, 0x15, 0x51, 0x32, 0xdf, 0x8a, 0x3a,
+	0xd9, 0xc2, 0xf7, 0x60, 0x83, 0x4b, 0x4b, 0x68, 0x92, 0x55, 0xc3, 0x77, 0x61, 0xad, 0x1b, 0x85,
+	0xd3, 0x3c, 0xa3, 0x8e, 0xdb, 0xb0, 0xcd, 0xfb, 0xe4, 0x42, 0xaf, 0x94, 0x68, 0xe0, 0x1d, 0xd8,
+	0xa2, 0x5d, 0x4b, 0xf8, 0x73, 0xf8, 0x21, 0xb4, 0xfb, 0x24, 0x31, 0x1f, 0xf2, 0x52, 0x6a, 0x9e,
+	0xea, 0x79, 0x6f, 0x3a, 0x2a, 0xd7, 0xd3, 0xc4, 0xf7, 0xe1, 0x2e, 0x47, 0xa2, 0x02, 0x9a, 0x64,
+	0xb6, 0x28, 0x93, 0x5b, 0x5c, 0x64, 0x82, 0xb2, 0x21, 0xb7, 0x45, 0xa4, 0xc4, 0x82, 0xb4, 0xa1,
+	0x84, 0xbf, 0xa8, 0xe6, 0x99, 0xae, 0xba, 0x24, 0x2f, 0xe1, 0x35, 0x58, 0xa1, 0xdd, 0x74, 0xe2,
+	0x32, 0x95, 0xe5, 0x96, 0xe8, 0xe4, 0x15, 0x3a, 0xc3, 0x7d, 0x92, 0xa4, 0xeb, 0x2e, 0x19, 0xab,
+	0x18, 0xc3, 0x32, 0x9d, 0x1f, 0x2f, 0xf1, 0x24, 0xed, 0x0e, 0xde, 0x06, 0xbb, 0x4f, 0x12, 0xe6,
+	0xb6, 0x85, 0x1e, 0x58, 0x69, 0xd0, 0x97, 0x77, 0x0d, 0x3f, 0x80, 0x7b, 0x62, 0x82, 0xb4, 0x30,
+	0x26, 0xd9, 0x1b, 0x6c, 0x8a, 0xa2, 0x70, 0x6a, 0x62, 0x6e, 0xd2, 0x21, 0x5d, 0x32, 0x09, 0x2f,
+	0xc8, 0x29, 0x51, 0xa0, 0xef, 0x2a, 0x8f, 0x91, 0x29, 0xad, 0x64, 0xd9, 0x59, 0x67, 0xd2, 0x59,
+	0xf7, 0x28, 0x8b, 0xe3, 0xcb, 0xb3, 0xb6, 0x28, 0x8b, 0xaf, 0x53, 0x7e, 0xc0, 0xfb, 0x8a, 0x95,
+	0xef, 0xb5, 0x8d, 0x37, 0x01, 0xf7, 0x49, 0x92, 0xef, 0xf2, 0x00, 0xaf, 0xc3, 0x2a, 0x33, 0x89,
+	0xae, 0xb9, 0xa4, 0xee, 0x7c, 0xb5, 0xd9, 0x1c, 0xad, 0x5e, 0x5f, 0x5f, 0x5f, 0x5b, 0xce, 0x2b,
+	0xc3, 0xf6, 0x48, 0x53, 0x5c, 0xa4, 0xa5, 0xb8, 0x18, 0xea, 0xae, 0x17, 0x8c, 0x44, 0x71, 0xc4,
+	0xbe, 0x3b, 0xdf, 0x83, 0xf9, 0xa1, 0xe8, 0xb2, 0x94, 0xd9, 0x89, 0x36, 0x61, 0x59, 0xd6, 0x5d,
+	0x41, 0xcc, 0x2b, 0x70, 0x65, 0x37, 0xe7, 0x23, 0xc3, 0x36, 0x2c, 0x9c, 0x60, 0xeb, 0xd0, 0xd8,
+	0x0f, 0xa3, 0x21, 0x8f, 0x0c, 0x4d, 0x97, 0x37, 0x2a, 0x94, 0x3f, 0xd7, 0x95, 0x17, 0x86, 0x57,
+	0xca, 0xff, 0x84, 0x4a, 0x76, 0xbb, 0x31, 0x8a, 0xee, 0xc1, 0x4a, 0x31, 0x3b, 0x47, 0xd5, 0xa9,
+	0x76, 0xbe, 0x47, 0xa7, 0x5b, 0x0a, 0xfa, 0xac, 0x8d, 0x54, 0x5e, 0x6b, 0x44, 0xa5, 0x80, 0x4f,
+	0x8c, 0xa1, 0xc8, 0x84, 0xba, 0xf3, 0xa4, 0x54, 0xe1, 0xb9, 0x0e, 0xde, 0x30, 0x9c, 0x52, 0xf7,
+	0x57, 0x54, 0x1d, 0xe1, 0x2a, 0x43, 0xbb, 0x71, 0xda, 0xac, 0x5b, 0x4e, 0xdb, 0x61, 0xa9, 0x15,
+	0x3e, 0xb3, 0xc2, 0xd1, 0xa7, 0xcd, 0x0c, 0x52, 0x99, 0xf3, 0x4b, 0x54, 0x15, 0x8e, 0x2b, 0x8d,
+	0x91, 0x33, 0x6c, 0x69, 0x33, 0xdc, 0x2b, 0xc5, 0xf6, 0x23, 0x86, 0xad, 0xad, 0x66, 0xf8, 0x75,
+	0xc8, 0x7e, 0x8d, 0x5e, 0x7f, 0x10, 0xdc, 0x1a, 0xdf, 0x49, 0x29, 0xbe, 0x17, 0x0c, 0xdf, 0x5b,
+	0xb2, 0x2e, 0xae, 0xd6, 0xab, 0x50, 0xfe, 0x0b, 0x55, 0x1f, 0x44, 0xb7, 0x45, 0x48, 0x33, 0xe8,
+	0x63, 0xf2, 0xf2, 0xd8, 0x13, 0x49, 0x55, 0xcb, 0x95, 0xcd, 0x4c, 0x91, 0x52, 0xcf, 0x95, 0x58,
+	0x7a, 0xd1, 0xd1, 0xc8, 0x96, 0x4c, 0x15, 0xfe, 0x32, 0xd6, 0xfd, 0xa5, 0xca, 0x0a, 0x65, 0xef,
+	0x1f, 0x50, 0xe9, 0xb1, 0x5a, 0x69, 0xea, 0x26, 0xcc, 0x65, 0xaa, 0x78, 0xd1, 0xa2, 0xc9, 0x0e,
+	0xcd, 0x16, 0xe3, 0xc4, 0x9b, 0x4c, 0x45, 0xc9, 0xa0, 0x08, 0x9d, 0xfd, 0x52, 0xe8, 0x13, 0x06,
+	0xfd, 0x81, 0xee, 0xea, 0x05, 0x40, 0x0a, 0xf5, 0x9f, 0x51, 0xe9, 0x79, 0xff, 0x46, 0xa8, 0x1d,
+	0x58, 0xcc, 0x5c, 0x25, 0xf1, 0xab, 0xb0, 0x0c, 0xad, 0x02, 0x7b, 0xa0, 0x63, 0x2f, 0x81, 0xa5,
+	0xb0, 0x7f, 0x8e, 0xaa, 0xd3, 0x91, 0x5b, 0x7b, 0x58, 0x9a, 0xd0, 0xd7, 0xb4, 0x84, 0xbe, 0xc2,
+	0x4b, 0xc2, 0x62, 0x54, 0x31, 0x23, 0x29, 0x46, 0x95, 0x2f, 0x07, 0x71, 0x45, 0x54, 0x99, 0xe6,
+	0xa3, 0xca, 0xeb, 0x90, 0x7d, 0x8a, 0x0c, 0xa9, 0xd9, 0x7f, 0x57, 0x28, 0x54, 0x1c, 0xbe, 0x1f,
+	0x16, 0x4f, 0x7e, 0x4d, 0xad, 0x42, 0x45, 0x0a, 0x89, 0xa1, 0xf1, 0xfc, 0xfa, 0x6e, 0xa9, 0xa2,
+	0x88, 0x29, 0xda, 0x50, 0xf3, 0x60, 0x54, 0xf3, 0xca, 0x90, 0x6a, 0xde, 0xd4, 0xf6, 0x0a, 0x2b,
+	0x63, 0xdd, 0xca, 0x82, 0x02, 0xa5, 0xfe, 0xf7, 0xc8, 0x98, 0xd3, 0x52, 0x77, 0xa0, 0xf2, 0x81,
+	0x42, 0x91, 0xb6, 0x33, 0xae, 0x62, 0x55, 0x15, 0x4a, 0xb5, 0x5c, 0xa1, 0x54, 0x71, 0xd8, 0x27,
+	0xfa, 0x61, 0x6f, 0x00, 0xa4, 0x10, 0x87, 0xf9, 0x5c, 0x1b, 0xef, 0xf0, 0x3b, 0x73, 0x86, 0x73,
+	0xa1, 0x03, 0xea, 0xe2, 0xda, 0x65, 0xf4, 0xce, 0x77, 0x4a, 0xb5, 0xce, 0xda, 0x48, 0xbb, 0xec,
+	0xc9, 0x8c, 0xaa, 0x14, 0xfe, 0x02, 0x95, 0x67, 0xf2, 0x95, 0xf3, 0x94, 0x7a, 0xa6, 0xa5, 0x7b,
+	0xe6, 0xb3, 0x52, 0x34, 0x17, 0x0c, 0xcd, 0x4e, 0x8a, 0xc6, 0xa8, 0x51, 0xe1, 0xba, 0x32, 0x94,
+	0x10, 0x37, 0xb9, 0x0c, 0xae, 0xf0, 0x9a, 0x97, 0x45, 0xaf, 0x31, 0x26, 0xa6, 0xff, 0x46, 0x15,
+	0x75, 0x4a, 0xe9, 0x6d, 0x5e, 0x99, 0xcf, 0xec, 0x16, 0x33, 0x30, 0x1e, 0x06, 0xf3, 0xe4, 0xf4,
+	0xde, 0xa6, 0x5e, 0x71, 0x6f, 0xd3, 0x28, 0xde, 0xdb, 0x74, 0x0e, 0x4a, 0x2d, 0xbe, 0x62, 0x16,
+	0xff, 0x5f, 0xe6, 0xcc, 0x2a, 0x9a, 0xa4, 0x2c, 0xff, 0x02, 0x95, 0x96, 0x60, 0xff, 0x3b, 0xbb,
+	0x2b, 0xce, 0xad, 0x1f, 0x67, 0xce, 0x2d, 0x33, 0xb0, 0x8c, 0xcb, 0x14, 0x4a, 0xc4, 0xd4, 0x65,
+	0x90, 0x72, 0x99, 0xc7, 0xa3, 0x51, 0x24, 0x5d, 0x86, 0x7e, 0x57, 0xb8, 0xcc, 0x47, 0xba, 0xcb,
+	0x14, 0x06, 0x57, 0xaa, 0x7f, 0x8b, 0x4a, 0xea, 0x50, 0x3a, 0x45, 0x07, 0x83, 0xc1, 0x29, 0xd3,
+	0x29, 0xb6, 0x90, 0x6c, 0x8b, 0x77, 0x0b, 0x0d, 0x8e, 0x6c, 0xa6, 0xe5, 0x5e, 0x4d, 0x2b, 0xf7,
+	0xca, 0x8b, 0x97, 0x8f, 0x8b, 0xc5, 0x4b, 0x0e, 0x46, 0xe6, 0x38, 0x32, 0x97, 0xc5, 0x6f, 0x86,
+	0xb4, 0x02, 0xd5, 0x2b, 0x73, 0x49, 0x65, 0x44, 0xf5, 0x19, 0x2a, 0xa9, 0xc8, 0x6f, 0xff, 0xfe,
+	0x63, 0x69, 0xef, 0x3f, 0x15, 0xe8, 0x7e, 0xa2, 0xa3, 0x33, 0xaa, 0xd6, 0x0b, 0x3e, 0xf3, 0x9d,
+	0x40, 0x1e, 0x5c, 0x85, 0xba, 0x9f, 0xea, 0xea, 0x8c, 0x83, 0x29, 0x75, 0x41, 0xc9, 0x3d, 0x43,
+	0x41, 0xdd, 0xd3, 0x52, 0x75, 0xd7, 0xa8, 0xa8, 0xaf, 0xd4, 0xbc, 0x7d, 0x9a, 0xca, 0xc7, 0xd3,
+	0x30, 0x88, 0x09, 0x55, 0x71, 0x72, 0xc8, 0x54, 0x34, 0x5d, 0xeb, 0xe4, 0x90, 0x46, 0x79, 0x7e,
+	0xef, 0x69, 0xb1, 0xd2, 0x80, 0x37, 0xd4, 0x5b, 0x6d, 0x8d, 0xed, 0x2b, 0xde, 0x70, 0x7e, 0x85,
+	0x4c, 0xb7, 0x20, 0x5f, 0xe2, 0x0e, 0x28, 0x3f, 0x60, 0x3f, 0xe1, 0xf6, 0xa6, 0x8f, 0x8c, 0x49,
+	0xe9, 0xe4, 0x8e, 0x8a, 0x37, 0x32, 0x85, 0x79, 0x2d, 0x8f, 0x07, 0x3f, 0xe3, 0x7a, 0x36, 0xb5,
+	0x88, 0xa4, 0x0d, 0xa4, 0xb4, 0xfc, 0x0d, 0xc1, 0xba, 0xe9, 0xa5, 0xcd, 0x18, 0x45, 0xbf, 0x09,
+	0x1b, 0xfd, 0x70, 0x16, 0x0d, 0x89, 0xf9, 0xf9, 0xd1, 0xcc, 0xa4, 0xbd, 0x06, 0x5e, 0x74, 0x46,
+	0x12, 0x73, 0x94, 0x35, 0x33, 0xe9, 0x62, 0xf4, 0x82, 0x84, 0x44, 0x17, 0xde, 0x58, 0xbc, 0x89,
+	0xa4, 0x6d, 0x9a, 0xdd, 0xec, 0xcf, 0x82, 0xa1, 0x7e, 0xd0, 0x28, 0x82, 0xf3, 0x47, 0x04, 0x1b,
+	0xc6, 0x07, 0x3f, 0xa3, 0x4d, 0xdf, 0x4a, 0xdf, 0x12, 0xac, 0x76, 0x4d, 0x25, 0xcc, 0x85, 0x01,
+	0x98, 0x10, 0x7f, 0x6f, 0xe1, 0xf2, 0xb8, 0x03, 0xf5, 0x81, 0x77, 0x26, 0x9f, 0x43, 0x76, 0x4a,
+	0xfa, 0x0d, 0xbc, 0x33, 0xd6, 0x8b, 0xc9, 0x52, 0xab, 0xde, 0xf7, 0xa2, 0xe0, 0x24, 0x18, 0x5f,
+	0xb1, 0x42, 0xb5, 0xe9, 0xa6, 0x6d, 0xa7, 0x0b, 0x5b, 0xe5, 0x5a, 0xcb, 0x72, 0x51, 0x7a, 0x77,
+	0x2d, 0x43, 0x0c, 0xfd, 0x76, 0x3e, 0x06, 0xbb, 0x0c, 0x03, 0x5e, 0x85, 0xda, 0x21, 0xb9, 0x12,
+	0x43, 0xd0, 0x4f, 0x5e, 0x1c, 0x7f, 0x38, 0xf3, 0x23, 0x32, 0x62, 0x1b, 0xa7, 0xe9, 0xa6, 0x6d,
+	0x76, 0x5f, 0x4f, 0xce, 0xc4, 0xde, 0x69, 0xb9, 0xbc, 0x21, 0xfe, 0x25, 0xf8, 0xa1, 0x37, 0x9e,
+	0x91, 0x58, 0xd4, 0xda, 0x8a, 0xe0, 0xf4, 0xa1, 0x29, 0x9f, 0xe4, 0x8d, 0x88, 0xb3, 0x0f, 0x07,
+	0x96, 0xfe, 0x70, 0x40, 0xfb, 0x19, 0x1f, 0x0e, 0x9c, 0x4f, 0x10, 0x2c, 0x65, 0xb8, 0x6f, 0xfe,
+	0x46, 0x40, 0x1f, 0x67, 0xb4, 0xe9, 0x11, 0xa6, 0xe9, 0x24, 0x65, 0x36, 0x5f, 0x1f, 0xde, 0x70,
+	0xfe, 0x8e, 0xa0, 0x95, 0xfe, 0x45, 0xa0, 0xed, 0xc3, 0x96, 0x8c, 0xf5, 0x34, 0xdd, 0x94, 0x0b,
+	0x41, 0xbf, 0xd3, 0x42, 0xa1, 0xa6, 0x15, 0x49, 0x6d, 0x58, 0xe8, 0x92, 0x34, 0x2f, 0x60, 0x1a,
+	0x5a, 0xae, 0x4e, 0x62, 0x4f, 0x58, 0x11, 0x11, 0x4f, 0x78, 0x0d, 0x5e, 0xf4, 0xa7, 0x04, 0xca,
+	0x7d, 0x7a, 0x39, 0xf5, 0x23, 0x12, 0xa7, 0x0f, 0x7c, 0x8a, 0x90, 0x9b, 0xdc, 0xf9, 0x1b, 0xbd,
+	0xca, 0x38, 0x17, 0x80, 0x33, 0xbf, 0x35, 0xf0, 0xdd, 0xff, 0x9a, 0x32, 0x54, 0x18, 0x8b, 0x74,
+	0x63, 0xe9, 0x0a, 0x89, 0xf9, 0x64, 0xdf, 0xd9, 0xd7, 0x38, 0x9e, 0x2a, 0x2a, 0x02, 0xad, 0xdd,
+	0x5b, 0xe9, 0xb3, 0x3a, 0xcd, 0x1e, 0x8f, 0xbc, 0xcb, 0xae, 0x1f, 0xbf, 0x78, 0x72, 0x95, 0x90,
+	0x98, 0xa5, 0x41, 0x35, 0x37, 0x43, 0x13, 0x9e, 0xd7, 0xe7, 0x3f, 0x1d, 0x58, 0xa9, 0xe7, 0x71,
+	0x02, 0x7d, 0x8b, 0x3e, 0xf2, 0x2e, 0xf9, 0x7b, 0xd8, 0x29, 0x89, 0xfa, 0x64, 0x18, 0xb2, 0xa0,
+	0x4c, 0xc5, 0x0c, 0x1c, 0xdc, 0x81, 0xf5, 0x23, 0xef, 0x72, 0x2f, 0x0c, 0x86, 0xb3, 0x28, 0x22,
+	0x41, 0xa2, 0xfe, 0x66, 0xa0, 0x3d, 0x8c, 0xbc, 0xff, 0x0c, 0x00, 0x34, 0xde, 0xdc, 0xa7, 0x32,
+	0x24, 0x00, 0x00,
 }
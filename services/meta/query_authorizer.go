@@ -69,6 +69,13 @@ func (a *QueryAuthorizer) AuthorizeDatabase(u User, priv influxql.Privilege, dat
 	return nil
 }
 
+// AuthorizeWrite returns nil if u has permission to write to the database or
+// to some of its measurements. The points written to the database must then be
+// authorized with AuthorizeSeriesWrite.
+func (a *QueryAuthorizer) AuthorizeWrite(u User, database string) error {
+	return WriteAuthorizer{Client: a.Client}.AuthorizeWrite(u, database)
+}
+
 func (u *UserInfo) AuthorizeQuery(database string, query *influxql.Query) error {
 	// Admin privilege allows the user to execute all statements.
 	if u.Admin {
@@ -104,7 +111,7 @@ func (u *UserInfo) AuthorizeQuery(database string, query *influxql.Query) error
 			if db == "" {
 				db = database
 			}
-			if !u.AuthorizeDatabase(p.Privilege, db) {
+			if !u.AuthorizeDatabase(p.Privilege, db) && !u.authorizeSeriesStatement(stmt, p.Privilege, db) {
 				return &ErrAuthorize{
 					Query:    query,
 					User:     u.Name,
@@ -117,6 +124,26 @@ func (u *UserInfo) AuthorizeQuery(database string, query *influxql.Query) error
 	return nil
 }
 
+// authorizeSeriesStatement returns true if the user may execute a statement
+// that reads series with privileges on only some of the measurements of the
+// database. The series of the other measurements are filtered out with
+// AuthorizeSeriesRead when the statement is executed.
+func (u *UserInfo) authorizeSeriesStatement(stmt influxql.Statement, privilege influxql.Privilege, database string) bool {
+	if privilege != influxql.ReadPrivilege {
+		return false
+	}
+
+	switch stmt.(type) {
+	case *influxql.SelectStatement,
+		*influxql.ShowMeasurementsStatement,
+		*influxql.ShowSeriesStatement,
+		*influxql.ShowTagKeysStatement,
+		*influxql.ShowTagValuesStatement:
+		return u.authorizeAnyMeasurement(privilege, database)
+	}
+	return false
+}
+
 // ErrAuthorize represents an authorization error.
 type ErrAuthorize struct {
 	Query    *influxql.Query
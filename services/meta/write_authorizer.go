@@ -17,9 +17,18 @@ func NewWriteAuthorizer(c *Client) *WriteAuthorizer {
 }
 
 // AuthorizeWrite returns nil if the user has permission to write to the database.
-func (a WriteAuthorizer) AuthorizeWrite(username, database string) error {
-	u, err := a.Client.User(username)
-	if err != nil || u == nil || !u.AuthorizeDatabase(influxql.WritePrivilege, database) {
+// A user with permission to write to only some of the measurements of the
+// database is authorized as well, and each point must then be authorized with
+// AuthorizeSeriesWrite.
+//
+// The user is authorized as given rather than looked up by name so that the
+// privileges of a user authenticated with a token are limited to the token.
+func (a WriteAuthorizer) AuthorizeWrite(u User, database string) error {
+	if u == nil || !authorizeAnyMeasurement(u, influxql.WritePrivilege, database) {
+		var username string
+		if u != nil {
+			username = u.ID()
+		}
 		return &ErrAuthorize{
 			Database: database,
 			Message:  fmt.Sprintf("%s not authorized to write to %s", username, database),
@@ -27,3 +36,12 @@ func (a WriteAuthorizer) AuthorizeWrite(username, database string) error {
 	}
 	return nil
 }
+
+// authorizeAnyMeasurement returns true if the user has the privilege on the
+// database or on some of its measurements.
+func authorizeAnyMeasurement(u User, privilege influxql.Privilege, database string) bool {
+	if ui, ok := u.(*UserInfo); ok {
+		return ui.authorizeAnyMeasurement(privilege, database)
+	}
+	return u.AuthorizeDatabase(privilege, database)
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/ayang64/reflux/logger"
 	"github.com/ayang64/reflux/services/meta"
+	"github.com/ayang64/reflux/task"
 	"go.uber.org/zap"
 )
 
@@ -23,6 +24,9 @@ type Service struct {
 		DeleteShard(shardID uint64) error
 	}
 
+	// Tasks tracks each enforcement pass so it can be listed and killed.
+	Tasks *task.Manager
+
 	config Config
 	wg     sync.WaitGroup
 	done   chan struct{}
@@ -65,85 +69,98 @@ func (s *Service) run(ctx context.Context) error {
 			return ctx.Err()
 
 		case <-ticker.C:
-			log, logEnd := logger.NewOperation(s.logger, "Retention policy deletion check", "retention_delete_check")
-
-			type deletionInfo struct {
-				db string
-				rp string
+			if err := s.Tasks.Run(ctx, "retention policy enforcement", s.enforce); err != nil && ctx.Err() == nil {
+				s.logger.Info("Retention policy enforcement aborted and will be retried on the next check", zap.Error(err))
 			}
-			deletedShardIDs := make(map[uint64]deletionInfo)
-
-			// Mark down if an error occurred during this function so we can inform the
-			// user that we will try again on the next interval.
-			// Without the message, they may see the error message and assume they
-			// have to do it manually.
-			var retryNeeded bool
-			dbs := s.MetaClient.Databases()
-			for _, d := range dbs {
-				for _, r := range d.RetentionPolicies {
-					// Build list of already deleted shards.
-					for _, g := range r.DeletedShardGroups() {
-						for _, sh := range g.Shards {
-							deletedShardIDs[sh.ID] = deletionInfo{db: d.Name, rp: r.Name}
-						}
-					}
-
-					// Determine all shards that have expired and need to be deleted.
-					for _, g := range r.ExpiredShardGroups(time.Now().UTC()) {
-						if err := s.MetaClient.DeleteShardGroup(d.Name, r.Name, g.ID); err != nil {
-							log.Info("Failed to delete shard group",
-								logger.Database(d.Name),
-								logger.ShardGroup(g.ID),
-								logger.RetentionPolicy(r.Name),
-								zap.Error(err))
-							retryNeeded = true
-							continue
-						}
-
-						log.Info("Deleted shard group",
-							logger.Database(d.Name),
-							logger.ShardGroup(g.ID),
-							logger.RetentionPolicy(r.Name))
-
-						// Store all the shard IDs that may possibly need to be removed locally.
-						for _, sh := range g.Shards {
-							deletedShardIDs[sh.ID] = deletionInfo{db: d.Name, rp: r.Name}
-						}
-					}
+		}
+	}
+
+	return nil
+}
+
+// enforce deletes expired shard groups and the local shards that belong to
+// them. It stops early if ctx is canceled.
+func (s *Service) enforce(ctx context.Context) error {
+	log, logEnd := logger.NewOperation(s.logger, "Retention policy deletion check", "retention_delete_check")
+	defer logEnd()
+
+	type deletionInfo struct {
+		db string
+		rp string
+	}
+	deletedShardIDs := make(map[uint64]deletionInfo)
+
+	// Mark down if an error occurred during this function so we can inform the
+	// user that we will try again on the next interval.
+	// Without the message, they may see the error message and assume they
+	// have to do it manually.
+	var retryNeeded bool
+	dbs := s.MetaClient.Databases()
+	for _, d := range dbs {
+		for _, r := range d.RetentionPolicies {
+			// Build list of already deleted shards.
+			for _, g := range r.DeletedShardGroups() {
+				for _, sh := range g.Shards {
+					deletedShardIDs[sh.ID] = deletionInfo{db: d.Name, rp: r.Name}
 				}
 			}
 
-			// Remove shards if we store them locally
-			for _, id := range s.TSDBStore.ShardIDs() {
-				if info, ok := deletedShardIDs[id]; ok {
-					if err := s.TSDBStore.DeleteShard(id); err != nil {
-						log.Info("Failed to delete shard",
-							logger.Database(info.db),
-							logger.Shard(id),
-							logger.RetentionPolicy(info.rp),
-							zap.Error(err))
-						retryNeeded = true
-						continue
-					}
-					log.Info("Deleted shard",
-						logger.Database(info.db),
-						logger.Shard(id),
-						logger.RetentionPolicy(info.rp))
+			// Determine all shards that have expired and need to be deleted.
+			for _, g := range r.ExpiredShardGroups(time.Now().UTC()) {
+				if err := s.MetaClient.DeleteShardGroup(d.Name, r.Name, g.ID); err != nil {
+					log.Info("Failed to delete shard group",
+						logger.Database(d.Name),
+						logger.ShardGroup(g.ID),
+						logger.RetentionPolicy(r.Name),
+						zap.Error(err))
+					retryNeeded = true
+					continue
 				}
-			}
 
-			if err := s.MetaClient.PruneShardGroups(); err != nil {
-				log.Info("Problem pruning shard groups", zap.Error(err))
-				retryNeeded = true
+				log.Info("Deleted shard group",
+					logger.Database(d.Name),
+					logger.ShardGroup(g.ID),
+					logger.RetentionPolicy(r.Name))
+
+				// Store all the shard IDs that may possibly need to be removed locally.
+				for _, sh := range g.Shards {
+					deletedShardIDs[sh.ID] = deletionInfo{db: d.Name, rp: r.Name}
+				}
 			}
+		}
+	}
 
-			if retryNeeded {
-				log.Info("One or more errors occurred during shard deletion and will be retried on the next check", logger.DurationLiteral("check_interval", time.Duration(s.config.CheckInterval)))
+	// Remove shards if we store them locally
+	for _, id := range s.TSDBStore.ShardIDs() {
+		if info, ok := deletedShardIDs[id]; ok {
+			if err := ctx.Err(); err != nil {
+				return err
 			}
 
-			logEnd()
+			if err := s.TSDBStore.DeleteShard(id); err != nil {
+				log.Info("Failed to delete shard",
+					logger.Database(info.db),
+					logger.Shard(id),
+					logger.RetentionPolicy(info.rp),
+					zap.Error(err))
+				retryNeeded = true
+				continue
+			}
+			log.Info("Deleted shard",
+				logger.Database(info.db),
+				logger.Shard(id),
+				logger.RetentionPolicy(info.rp))
 		}
 	}
 
+	if err := s.MetaClient.PruneShardGroups(); err != nil {
+		log.Info("Problem pruning shard groups", zap.Error(err))
+		retryNeeded = true
+	}
+
+	if retryNeeded {
+		log.Info("One or more errors occurred during shard deletion and will be retried on the next check", logger.DurationLiteral("check_interval", time.Duration(s.config.CheckInterval)))
+	}
+
 	return nil
 }
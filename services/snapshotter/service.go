@@ -15,6 +15,7 @@ import (
 
 	influxdb "github.com/ayang64/reflux"
 	"github.com/ayang64/reflux/services/meta"
+	"github.com/ayang64/reflux/task"
 	"github.com/ayang64/reflux/tsdb"
 	"go.uber.org/zap"
 )
@@ -47,6 +48,9 @@ type Service struct {
 		CreateShard(database, retentionPolicy string, shardID uint64, enabled bool) error
 	}
 
+	// Tasks tracks shard backups and exports so they can be listed and killed.
+	Tasks *task.Manager
+
 	Listener net.Listener
 	Logger   *zap.Logger
 }
@@ -97,7 +101,7 @@ func (s *Service) serve(ctx context.Context) error {
 		// Handle connection in separate goroutine.
 		go func(conn net.Conn) {
 			defer conn.Close()
-			if err := s.handleConn(conn); err != nil {
+			if err := s.handleConn(ctx, conn); err != nil {
 				s.Logger.Info(err.Error())
 			}
 		}(conn)
@@ -107,7 +111,7 @@ func (s *Service) serve(ctx context.Context) error {
 }
 
 // handleConn processes conn. This is run in a separate goroutine.
-func (s *Service) handleConn(conn net.Conn) error {
+func (s *Service) handleConn(ctx context.Context, conn net.Conn) error {
 	var typ [1]byte
 
 	_, err := conn.Read(typ[:])
@@ -126,11 +130,17 @@ func (s *Service) handleConn(conn net.Conn) error {
 
 	switch RequestType(typ[0]) {
 	case RequestShardBackup:
-		if err := s.TSDBStore.BackupShard(r.ShardID, r.Since, conn); err != nil {
+		name := fmt.Sprintf("backup of shard %d to %s", r.ShardID, conn.RemoteAddr())
+		if err := s.runStream(ctx, name, conn, func() error {
+			return s.TSDBStore.BackupShard(r.ShardID, r.Since, conn)
+		}); err != nil {
 			return err
 		}
 	case RequestShardExport:
-		if err := s.TSDBStore.ExportShard(r.ShardID, r.ExportStart, r.ExportEnd, conn); err != nil {
+		name := fmt.Sprintf("export of shard %d to %s", r.ShardID, conn.RemoteAddr())
+		if err := s.runStream(ctx, name, conn, func() error {
+			return s.TSDBStore.ExportShard(r.ShardID, r.ExportStart, r.ExportEnd, conn)
+		}); err != nil {
 			return err
 		}
 	case RequestMetastoreBackup:
@@ -150,6 +160,30 @@ func (s *Service) handleConn(conn net.Conn) error {
 	return nil
 }
 
+// runStream runs f as a task that streams to conn. Killing the task closes
+// conn, which aborts the stream.
+func (s *Service) runStream(ctx context.Context, name string, conn net.Conn, f func() error) error {
+	return s.Tasks.Run(ctx, name, func(ctx context.Context) error {
+		done := make(chan struct{})
+		defer close(done)
+		go func() {
+			select {
+			case <-ctx.Done():
+				conn.Close()
+			case <-done:
+			}
+		}()
+
+		if err := f(); err != nil {
+			if ctx.Err() != nil {
+				return fmt.Errorf("%s: %s", name, ctx.Err())
+			}
+			return err
+		}
+		return nil
+	})
+}
+
 func (s *Service) updateShardsLive(conn net.Conn) error {
 	var sidBytes [8]byte
 	_, err := conn.Read(sidBytes[:])
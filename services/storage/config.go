@@ -0,0 +1,87 @@
+package storage
+
+import (
+	"errors"
+	"time"
+
+	"github.com/ayang64/reflux/monitor/diagnostics"
+	"github.com/ayang64/reflux/toml"
+)
+
+const (
+	// DefaultBindAddress is the default address the gRPC storage service binds to.
+	DefaultBindAddress = ":8082"
+
+	// DefaultMaxConcurrentRequests is the default number of read requests
+	// that may be served at once.
+	DefaultMaxConcurrentRequests = 0
+
+	// DefaultRequestTimeout is the default time a single read request may run.
+	DefaultRequestTimeout = time.Duration(0)
+)
+
+// Config represents the configuration for the gRPC storage service.
+type Config struct {
+	Enabled bool `toml:"enabled"`
+
+	// BindAddress is the address of a dedicated listener. When empty, the
+	// service is multiplexed through the shared RPC bind address instead.
+	BindAddress string `toml:"bind-address"`
+
+	AuthEnabled bool `toml:"auth-enabled"`
+
+	// Limits applied to each read request. A value of 0 disables the limit.
+	MaxConcurrentRequests int           `toml:"max-concurrent-requests"`
+	MaxSeriesPerRequest   int           `toml:"max-series-per-request"`
+	MaxPointsPerRequest   int           `toml:"max-points-per-request"`
+	RequestTimeout        toml.Duration `toml:"request-timeout"`
+}
+
+// NewConfig returns a new Config with defaults.
+func NewConfig() Config {
+	return Config{
+		BindAddress:           DefaultBindAddress,
+		MaxConcurrentRequests: DefaultMaxConcurrentRequests,
+		RequestTimeout:        toml.Duration(DefaultRequestTimeout),
+	}
+}
+
+// Validate returns an error if the Config is invalid.
+func (c Config) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+
+	if c.MaxConcurrentRequests < 0 {
+		return errors.New("max-concurrent-requests cannot be negative")
+	}
+	if c.MaxSeriesPerRequest < 0 {
+		return errors.New("max-series-per-request cannot be negative")
+	}
+	if c.MaxPointsPerRequest < 0 {
+		return errors.New("max-points-per-request cannot be negative")
+	}
+	if c.RequestTimeout < 0 {
+		return errors.New("request-timeout cannot be negative")
+	}
+	return nil
+}
+
+// Diagnostics returns a diagnostics representation of a subset of the Config.
+func (c Config) Diagnostics() (*diagnostics.Diagnostics, error) {
+	if !c.Enabled {
+		return diagnostics.RowFromMap(map[string]interface{}{
+			"enabled": false,
+		}), nil
+	}
+
+	return diagnostics.RowFromMap(map[string]interface{}{
+		"enabled":                 true,
+		"bind-address":            c.BindAddress,
+		"auth-enabled":            c.AuthEnabled,
+		"max-concurrent-requests": c.MaxConcurrentRequests,
+		"max-series-per-request":  c.MaxSeriesPerRequest,
+		"max-points-per-request":  c.MaxPointsPerRequest,
+		"request-timeout":         c.RequestTimeout,
+	}), nil
+}
@@ -0,0 +1,62 @@
+package storage_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/ayang64/reflux/services/storage"
+)
+
+func TestConfig_Parse(t *testing.T) {
+	// Parse configuration.
+	c := storage.NewConfig()
+	if _, err := toml.Decode(`
+enabled = true
+bind-address = ""
+auth-enabled = true
+max-concurrent-requests = 4
+max-series-per-request = 1000
+max-points-per-request = 100000
+request-timeout = "30s"
+`, &c); err != nil {
+		t.Fatal(err)
+	}
+
+	// Validate configuration.
+	if !c.Enabled {
+		t.Fatalf("unexpected enabled: %v", c.Enabled)
+	} else if c.BindAddress != "" {
+		t.Fatalf("unexpected bind address: %s", c.BindAddress)
+	} else if !c.AuthEnabled {
+		t.Fatalf("unexpected auth enabled: %v", c.AuthEnabled)
+	} else if c.MaxConcurrentRequests != 4 {
+		t.Fatalf("unexpected max concurrent requests: %d", c.MaxConcurrentRequests)
+	} else if c.MaxSeriesPerRequest != 1000 {
+		t.Fatalf("unexpected max series per request: %d", c.MaxSeriesPerRequest)
+	} else if c.MaxPointsPerRequest != 100000 {
+		t.Fatalf("unexpected max points per request: %d", c.MaxPointsPerRequest)
+	} else if time.Duration(c.RequestTimeout) != 30*time.Second {
+		t.Fatalf("unexpected request timeout: %s", c.RequestTimeout)
+	}
+}
+
+func TestConfig_Validate(t *testing.T) {
+	c := storage.NewConfig()
+	c.Enabled = true
+	if err := c.Validate(); err != nil {
+		t.Fatalf("unexpected validation fail from NewConfig: %s", err)
+	}
+
+	c = storage.NewConfig()
+	c.Enabled = true
+	c.MaxSeriesPerRequest = -1
+	if err := c.Validate(); err == nil {
+		t.Fatal("expected error for negative max-series-per-request, got nil")
+	}
+
+	c.Enabled = false
+	if err := c.Validate(); err != nil {
+		t.Fatalf("unexpected validation fail from disabled config: %s", err)
+	}
+}
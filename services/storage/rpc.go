@@ -0,0 +1,314 @@
+package storage
+
+import (
+	"context"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/ayang64/reflux/influxql"
+	"github.com/ayang64/reflux/services/meta"
+	"github.com/ayang64/reflux/storage/reads"
+	"github.com/ayang64/reflux/storage/reads/datatypes"
+	"github.com/ayang64/reflux/tsdb/cursors"
+	"github.com/gogo/protobuf/types"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// stringValuesBatchSize is the number of values sent in each
+// StringValuesResponse.
+const stringValuesBatchSize = 1000
+
+// rpcService implements datatypes.StorageServer on top of the service's Store.
+type rpcService struct {
+	s *Service
+}
+
+func (r *rpcService) Capabilities(ctx context.Context, _ *types.Empty) (*datatypes.CapabilitiesResponse, error) {
+	return &datatypes.CapabilitiesResponse{Caps: map[string]string{}}, nil
+}
+
+func (r *rpcService) ReadFilter(req *datatypes.ReadFilterRequest, stream datatypes.Storage_ReadFilterServer) error {
+	atomic.AddInt64(&r.s.stats.ReadFilterRequests, 1)
+	return r.serve(stream.Context(), req.ReadSource, func(ctx context.Context) error {
+		rs, err := r.s.Store.ReadFilter(ctx, req)
+		if err != nil || rs == nil {
+			return err
+		}
+		defer rs.Close()
+
+		w := reads.NewResponseWriter(stream, 0)
+		l := r.newLimiter(w)
+		w.WriteResultSet(&limitedResultSet{ResultSet: rs, l: l})
+		return r.finish(w, l)
+	})
+}
+
+func (r *rpcService) ReadGroup(req *datatypes.ReadGroupRequest, stream datatypes.Storage_ReadGroupServer) error {
+	atomic.AddInt64(&r.s.stats.ReadGroupRequests, 1)
+	return r.serve(stream.Context(), req.ReadSource, func(ctx context.Context) error {
+		rs, err := r.s.Store.ReadGroup(ctx, req)
+		if err != nil || rs == nil {
+			return err
+		}
+		defer rs.Close()
+
+		w := reads.NewResponseWriter(stream, req.Hints)
+		l := r.newLimiter(w)
+		w.WriteGroupResultSet(&limitedGroupResultSet{GroupResultSet: rs, l: l})
+		return r.finish(w, l)
+	})
+}
+
+func (r *rpcService) ReadWindowAggregate(req *datatypes.ReadWindowAggregateRequest, stream datatypes.Storage_ReadWindowAggregateServer) error {
+	atomic.AddInt64(&r.s.stats.ReadWindowAggregateRequests, 1)
+	return r.serve(stream.Context(), req.ReadSource, func(ctx context.Context) error {
+		rs, err := r.s.Store.WindowAggregate(ctx, req)
+		if err != nil || rs == nil {
+			return err
+		}
+		defer rs.Close()
+
+		w := reads.NewResponseWriter(stream, 0)
+		l := r.newLimiter(w)
+		w.WriteResultSet(&limitedResultSet{ResultSet: rs, l: l})
+		if err := rs.Err(); err != nil {
+			return status.Error(codes.InvalidArgument, err.Error())
+		}
+		return r.finish(w, l)
+	})
+}
+
+func (r *rpcService) TagKeys(req *datatypes.TagKeysRequest, stream datatypes.Storage_TagKeysServer) error {
+	atomic.AddInt64(&r.s.stats.TagKeysRequests, 1)
+	return r.serve(stream.Context(), req.TagsSource, func(ctx context.Context) error {
+		itr, err := r.s.Store.TagKeys(ctx, req)
+		if err != nil || itr == nil {
+			return err
+		}
+		return sendStringValues(stream, itr)
+	})
+}
+
+func (r *rpcService) TagValues(req *datatypes.TagValuesRequest, stream datatypes.Storage_TagValuesServer) error {
+	atomic.AddInt64(&r.s.stats.TagValuesRequests, 1)
+	return r.serve(stream.Context(), req.TagsSource, func(ctx context.Context) error {
+		itr, err := r.s.Store.TagValues(ctx, req)
+		if err != nil || itr == nil {
+			return err
+		}
+		return sendStringValues(stream, itr)
+	})
+}
+
+// serve authorizes a request against source and runs fn within the
+// service's concurrency and time limits.
+func (r *rpcService) serve(ctx context.Context, source *types.Any, fn func(ctx context.Context) error) error {
+	user, err := r.authorize(ctx, source)
+	if err != nil {
+		return err
+	} else if user != nil {
+		ctx = meta.NewContextWithUser(ctx, user)
+	}
+
+	if !r.s.acquire() {
+		return status.Error(codes.ResourceExhausted, "too many concurrent requests")
+	}
+	defer r.s.release(time.Now())
+
+	if d := time.Duration(r.s.config.RequestTimeout); d > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, d)
+		defer cancel()
+	}
+
+	if err := fn(ctx); err != nil {
+		atomic.AddInt64(&r.s.stats.RequestErrors, 1)
+		if _, ok := status.FromError(err); ok {
+			return err
+		} else if ctx.Err() == context.DeadlineExceeded {
+			return status.Error(codes.DeadlineExceeded, "request timeout exceeded")
+		}
+		return status.Error(codes.Unknown, err.Error())
+	}
+	return nil
+}
+
+// authorize checks that the credentials attached to ctx grant read access to
+// the database named by source and returns the authenticated user. A nil user
+// is returned if authentication is disabled. All requests are denied while
+// authentication is enabled and no admin user has been created.
+func (r *rpcService) authorize(ctx context.Context, source *types.Any) (meta.User, error) {
+	if !r.s.config.AuthEnabled {
+		return nil, nil
+	} else if !r.s.MetaClient.AdminUserExists() {
+		atomic.AddInt64(&r.s.stats.AuthenticationFailures, 1)
+		return nil, status.Error(codes.Unauthenticated, "no admin user exists, create one before reading with authentication enabled")
+	}
+
+	token, ok := credentials(ctx)
+	if !ok {
+		atomic.AddInt64(&r.s.stats.AuthenticationFailures, 1)
+		return nil, status.Error(codes.Unauthenticated, "credentials required")
+	}
+
+	user, err := r.authenticate(ctx, token)
+	if err != nil {
+		atomic.AddInt64(&r.s.stats.AuthenticationFailures, 1)
+		return nil, status.Error(codes.Unauthenticated, "authorization failed")
+	}
+
+	if source == nil {
+		return nil, status.Error(codes.InvalidArgument, ErrMissingReadSource.Error())
+	}
+	src, err := GetReadSource(*source)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if !user.AuthorizeDatabase(influxql.ReadPrivilege, src.Database) {
+		return nil, status.Errorf(codes.PermissionDenied, "%s not authorized to read from %s", user.ID(), src.Database)
+	}
+	return user, nil
+}
+
+// authenticate returns the user of token, which is either username:password
+// or an API token. Tokens are authenticated on every request, so that revoked
+// tokens and changed users take effect immediately; the audit log skips the
+// repeated successful authentications of a connection.
+func (r *rpcService) authenticate(ctx context.Context, token string) (meta.User, error) {
+	if i := strings.IndexByte(token, ':'); i >= 0 {
+		return r.s.MetaClient.AuthenticateFrom(token[:i], token[i+1:], peerAddr(ctx))
+	}
+	return r.s.MetaClient.AuthenticateTokenFrom(token, peerAddr(ctx))
+}
+
+// peerAddr returns the address of the client of the RPC of ctx.
+func peerAddr(ctx context.Context) string {
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		return p.Addr.String()
+	}
+	return ""
+}
+
+// credentials returns the token of the incoming AuthorizationKey metadata of
+// ctx.
+func credentials(ctx context.Context) (token string, ok bool) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, v := range md.Get(AuthorizationKey) {
+		if strings.HasPrefix(v, "Token ") {
+			return strings.TrimPrefix(v, "Token "), true
+		}
+	}
+	return "", false
+}
+
+// finish flushes w and returns the first error raised while writing or
+// limiting the response.
+func (r *rpcService) finish(w *reads.ResponseWriter, l *limiter) error {
+	w.Flush()
+	atomic.AddInt64(&r.s.stats.PointsTransmitted, int64(w.WrittenN()))
+	if l.err != nil {
+		return l.err
+	}
+	return w.Err()
+}
+
+// sendStringValues streams the values of itr in batches.
+func sendStringValues(stream interface {
+	Send(*datatypes.StringValuesResponse) error
+}, itr cursors.StringIterator) error {
+	res := &datatypes.StringValuesResponse{}
+	for itr.Next() {
+		res.Values = append(res.Values, []byte(itr.Value()))
+		if len(res.Values) >= stringValuesBatchSize {
+			if err := stream.Send(res); err != nil {
+				return err
+			}
+			res.Values = res.Values[:0]
+		}
+	}
+	if len(res.Values) > 0 {
+		return stream.Send(res)
+	}
+	return nil
+}
+
+// limiter enforces the per-request series and point limits. The point limit
+// is checked between series, so a response may exceed MaxPointsPerRequest by
+// at most the points of one series before it is aborted.
+type limiter struct {
+	w         *reads.ResponseWriter
+	maxSeries int
+	maxPoints int
+	series    int
+	err       error
+}
+
+func (r *rpcService) newLimiter(w *reads.ResponseWriter) *limiter {
+	return &limiter{
+		w:         w,
+		maxSeries: r.s.config.MaxSeriesPerRequest,
+		maxPoints: r.s.config.MaxPointsPerRequest,
+	}
+}
+
+// add records that another series is about to be written and reports
+// whether the request is still within its limits.
+func (l *limiter) add() bool {
+	l.series++
+	if l.maxSeries > 0 && l.series > l.maxSeries {
+		l.err = status.Errorf(codes.ResourceExhausted, "max-series-per-request limit exceeded: (%d/%d)", l.series, l.maxSeries)
+		return false
+	}
+	if n := l.w.WrittenN(); l.maxPoints > 0 && n > l.maxPoints {
+		l.err = status.Errorf(codes.ResourceExhausted, "max-points-per-request limit exceeded: (%d/%d)", n, l.maxPoints)
+		return false
+	}
+	return true
+}
+
+// limitedResultSet stops a ResultSet once the request's limits are exceeded.
+type limitedResultSet struct {
+	reads.ResultSet
+	l *limiter
+}
+
+func (rs *limitedResultSet) Next() bool {
+	if rs.l.err != nil || !rs.ResultSet.Next() {
+		return false
+	}
+	return rs.l.add()
+}
+
+// limitedGroupResultSet stops a GroupResultSet once the request's limits are
+// exceeded.
+type limitedGroupResultSet struct {
+	reads.GroupResultSet
+	l *limiter
+}
+
+func (rs *limitedGroupResultSet) Next() reads.GroupCursor {
+	if rs.l.err != nil {
+		return nil
+	}
+	gc := rs.GroupResultSet.Next()
+	if gc == nil {
+		return nil
+	}
+	return &limitedGroupCursor{GroupCursor: gc, l: rs.l}
+}
+
+type limitedGroupCursor struct {
+	reads.GroupCursor
+	l *limiter
+}
+
+func (gc *limitedGroupCursor) Next() bool {
+	if gc.l.err != nil || !gc.GroupCursor.Next() {
+		return false
+	}
+	return gc.l.add()
+}
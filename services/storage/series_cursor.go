@@ -37,6 +37,11 @@ type indexSeriesCursor struct {
 	eof             bool
 	hasFieldExpr    bool
 	hasValueExpr    bool
+
+	// auth limits the series to those the user of the request may read. It
+	// is nil if the series are not limited.
+	auth     query.Authorizer
+	database string
 }
 
 func newIndexSeriesCursor(ctx context.Context, predicate *datatypes.Predicate, shards []*tsdb.Shard) (*indexSeriesCursor, error) {
@@ -57,11 +62,14 @@ func newIndexSeriesCursor(ctx context.Context, predicate *datatypes.Predicate, s
 
 	opt := query.IteratorOptions{
 		Aux:        []influxql.VarRef{{Val: "key"}},
-		Authorizer: query.OpenAuthorizer,
+		Authorizer: authorizer(ctx),
 		Ascending:  true,
 		Ordered:    true,
 	}
 	p := &indexSeriesCursor{row: reads.SeriesRow{Query: queries}}
+	if !query.AuthorizerIsOpen(opt.Authorizer) && len(shards) > 0 {
+		p.auth, p.database = opt.Authorizer, shards[0].Database()
+	}
 
 	if root := predicate.GetRoot(); root != nil {
 		if p.cond, err = reads.NodeToExpr(root, measurementRemap); err != nil {
@@ -173,6 +181,10 @@ func (c *indexSeriesCursor) Next() *reads.SeriesRow {
 				return nil
 			}
 
+			if c.auth != nil && !c.auth.AuthorizeSeriesRead(c.database, sr.Name, sr.Tags) {
+				continue
+			}
+
 			c.row.Name = sr.Name
 			c.row.SeriesTags = sr.Tags
 			c.tags = copyTags(c.tags, sr.Tags)
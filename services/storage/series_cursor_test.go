@@ -5,6 +5,7 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/ayang64/reflux/models"
+	"github.com/ayang64/reflux/tsdb"
 	"github.com/ayang64/reflux/influxql"
 )
 
@@ -93,3 +94,58 @@ func TestSeriesCursorValuer(t *testing.T) {
 		})
 	}
 }
+
+// seriesSliceCursor is a tsdb.SeriesCursor over a fixed list of series keys.
+type seriesSliceCursor struct {
+	keys []string
+}
+
+func (c *seriesSliceCursor) Close() error { return nil }
+
+func (c *seriesSliceCursor) Next() (*tsdb.SeriesCursorRow, error) {
+	if len(c.keys) == 0 {
+		return nil, nil
+	}
+	name, tags := models.ParseKeyBytes([]byte(c.keys[0]))
+	c.keys = c.keys[1:]
+	return &tsdb.SeriesCursorRow{Name: name, Tags: tags}, nil
+}
+
+// tenantAuthorizer only authorizes reading the series of a single customer.
+type tenantAuthorizer struct {
+	database string
+	customer string
+}
+
+func (a *tenantAuthorizer) AuthorizeDatabase(p influxql.Privilege, name string) bool { return true }
+func (a *tenantAuthorizer) AuthorizeQuery(database string, query *influxql.Query) error {
+	return nil
+}
+func (a *tenantAuthorizer) AuthorizeSeriesRead(database string, measurement []byte, tags models.Tags) bool {
+	return database == a.database && tags.GetString("customer") == a.customer
+}
+func (a *tenantAuthorizer) AuthorizeSeriesWrite(database string, measurement []byte, tags models.Tags) bool {
+	return false
+}
+
+func TestIndexSeriesCursor_Authorizer(t *testing.T) {
+	c := &indexSeriesCursor{
+		sqry: &seriesSliceCursor{keys: []string{
+			"cpu,customer=acme,host=a",
+			"cpu,customer=globex,host=b",
+			"cpu,host=c",
+			"cpu,customer=acme,host=d",
+		}},
+		fields:   measurementFields{"cpu": {{n: "value", nb: []byte("value")}}},
+		auth:     &tenantAuthorizer{database: "db0", customer: "acme"},
+		database: "db0",
+	}
+
+	var got []string
+	for row := c.Next(); row != nil; row = c.Next() {
+		got = append(got, row.Tags.GetString("host"))
+	}
+	if exp := []string{"a", "d"}; !cmp.Equal(got, exp) {
+		t.Fatalf("unexpected series, -got/+exp\n%s", cmp.Diff(got, exp))
+	}
+}
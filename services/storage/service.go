@@ -0,0 +1,199 @@
+package storage
+
+import (
+	"context"
+	"net"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ayang64/reflux/models"
+	"github.com/ayang64/reflux/services/meta"
+	"github.com/ayang64/reflux/storage/reads"
+	"github.com/ayang64/reflux/storage/reads/datatypes"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+)
+
+// MuxHeader is the header byte used when the service is multiplexed through
+// the shared RPC listener.
+const MuxHeader = 4
+
+// AuthorizationKey is the gRPC metadata key holding a client's credentials,
+// formatted as "Token username:password" or as "Token " followed by an API
+// token.
+const AuthorizationKey = "authorization"
+
+// statistics gathered by the gRPC storage service.
+const (
+	statRequestsActive              = "reqActive"
+	statReadFilterRequests          = "readFilterReq"
+	statReadGroupRequests           = "readGroupReq"
+	statReadWindowAggregateRequests = "readWindowAggregateReq"
+	statTagKeysRequests             = "tagKeysReq"
+	statTagValuesRequests           = "tagValuesReq"
+	statRequestErrors               = "reqErr"
+	statRequestsRejected            = "reqRejected"
+	statAuthenticationFailures      = "authFail"
+	statRequestDuration             = "reqDurationNs"
+	statPointsTransmitted           = "pointsTx"
+)
+
+// Service serves the storage/reads gRPC Storage API.
+type Service struct {
+	config Config
+
+	mu     sync.Mutex
+	ln     net.Listener
+	ready  chan struct{}
+	limit  chan struct{}
+	server *grpc.Server
+
+	// Listener is used instead of opening BindAddress when the service is
+	// multiplexed through the shared RPC listener.
+	Listener net.Listener
+
+	MetaClient interface {
+		AuthenticateFrom(username, password, source string) (meta.User, error)
+		AuthenticateTokenFrom(token, source string) (meta.User, error)
+		AdminUserExists() bool
+	}
+
+	Store reads.Store
+
+	Logger      *zap.Logger
+	stats       *Statistics
+	defaultTags models.StatisticTags
+}
+
+// NewService returns a new instance of Service.
+func NewService(c Config) *Service {
+	s := &Service{
+		config:      c,
+		ready:       make(chan struct{}),
+		Logger:      zap.NewNop(),
+		stats:       &Statistics{},
+		defaultTags: models.StatisticTags{"bind": c.BindAddress},
+	}
+	if c.MaxConcurrentRequests > 0 {
+		s.limit = make(chan struct{}, c.MaxConcurrentRequests)
+	}
+	return s
+}
+
+// Start serves gRPC requests until ctx is canceled.
+func (s *Service) Start(ctx context.Context) error {
+	s.Logger.Info("Starting gRPC storage service", zap.Bool("authentication", s.config.AuthEnabled))
+
+	ln := s.Listener
+	if ln == nil {
+		var err error
+		if ln, err = net.Listen("tcp", s.config.BindAddress); err != nil {
+			return err
+		}
+	}
+
+	server := grpc.NewServer()
+	datatypes.RegisterStorageServer(server, &rpcService{s: s})
+
+	s.mu.Lock()
+	s.ln = ln
+	s.server = server
+	s.mu.Unlock()
+
+	s.Logger.Info("Listening on gRPC", zap.Stringer("addr", ln.Addr()))
+	close(s.ready)
+
+	go func() {
+		<-ctx.Done()
+		server.Stop()
+	}()
+
+	if err := server.Serve(ln); err != nil && ctx.Err() == nil {
+		if strings.Contains(err.Error(), "use of closed network connection") {
+			return nil
+		}
+		return err
+	}
+	return nil
+}
+
+// WithLogger sets the logger on the service.
+func (s *Service) WithLogger(log *zap.Logger) {
+	s.Logger = log.With(zap.String("service", "storage"))
+}
+
+// Ready returns a channel that is closed once the service is listening.
+func (s *Service) Ready() <-chan struct{} { return s.ready }
+
+// Addr returns the listener's address. Returns nil if the service is not
+// listening.
+func (s *Service) Addr() net.Addr {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.ln != nil {
+		return s.ln.Addr()
+	}
+	return nil
+}
+
+// Statistics maintains statistics for the gRPC storage service.
+type Statistics struct {
+	RequestsActive              int64
+	ReadFilterRequests          int64
+	ReadGroupRequests           int64
+	ReadWindowAggregateRequests int64
+	TagKeysRequests             int64
+	TagValuesRequests           int64
+	RequestErrors               int64
+	RequestsRejected            int64
+	AuthenticationFailures      int64
+	RequestDuration             int64
+	PointsTransmitted           int64
+}
+
+// Statistics returns statistics for periodic monitoring.
+func (s *Service) Statistics(tags map[string]string) []models.Statistic {
+	return []models.Statistic{{
+		Name: "storage",
+		Tags: s.defaultTags.Merge(tags),
+		Values: map[string]interface{}{
+			statRequestsActive:              atomic.LoadInt64(&s.stats.RequestsActive),
+			statReadFilterRequests:          atomic.LoadInt64(&s.stats.ReadFilterRequests),
+			statReadGroupRequests:           atomic.LoadInt64(&s.stats.ReadGroupRequests),
+			statReadWindowAggregateRequests: atomic.LoadInt64(&s.stats.ReadWindowAggregateRequests),
+			statTagKeysRequests:             atomic.LoadInt64(&s.stats.TagKeysRequests),
+			statTagValuesRequests:           atomic.LoadInt64(&s.stats.TagValuesRequests),
+			statRequestErrors:               atomic.LoadInt64(&s.stats.RequestErrors),
+			statRequestsRejected:            atomic.LoadInt64(&s.stats.RequestsRejected),
+			statAuthenticationFailures:      atomic.LoadInt64(&s.stats.AuthenticationFailures),
+			statRequestDuration:             atomic.LoadInt64(&s.stats.RequestDuration),
+			statPointsTransmitted:           atomic.LoadInt64(&s.stats.PointsTransmitted),
+		},
+	}}
+}
+
+// acquire reserves a request slot, returning false if the service is already
+// serving MaxConcurrentRequests requests.
+func (s *Service) acquire() bool {
+	if s.limit != nil {
+		select {
+		case s.limit <- struct{}{}:
+		default:
+			atomic.AddInt64(&s.stats.RequestsRejected, 1)
+			return false
+		}
+	}
+	atomic.AddInt64(&s.stats.RequestsActive, 1)
+	return true
+}
+
+// release frees a slot reserved by acquire and records the request duration.
+func (s *Service) release(start time.Time) {
+	atomic.AddInt64(&s.stats.RequestsActive, -1)
+	atomic.AddInt64(&s.stats.RequestDuration, time.Since(start).Nanoseconds())
+	if s.limit != nil {
+		<-s.limit
+	}
+}
@@ -0,0 +1,490 @@
+package storage_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/ayang64/reflux/influxql"
+	"github.com/ayang64/reflux/internal"
+	"github.com/ayang64/reflux/models"
+	"github.com/ayang64/reflux/services/meta"
+	"github.com/ayang64/reflux/services/storage"
+	"github.com/ayang64/reflux/storage/reads"
+	"github.com/ayang64/reflux/storage/reads/datatypes"
+	"github.com/ayang64/reflux/tcp"
+	"github.com/ayang64/reflux/tsdb"
+	"github.com/ayang64/reflux/tsdb/cursors"
+	"github.com/gogo/protobuf/types"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestService_ReadFilter(t *testing.T) {
+	s := NewService(storage.NewConfig())
+	s.Store.ReadFilterFn = func(ctx context.Context, req *datatypes.ReadFilterRequest) (reads.ResultSet, error) {
+		if src, err := storage.GetReadSource(*req.ReadSource); err != nil {
+			t.Fatal(err)
+		} else if src.Database != "db0" {
+			t.Errorf("unexpected database: %s", src.Database)
+		}
+		return NewResultSet(3, 2), nil
+	}
+	s.Open(t)
+	defer s.Close()
+
+	series, points, err := ReadFilter(s.Client(t), context.Background(), "db0")
+	if err != nil {
+		t.Fatal(err)
+	} else if series != 3 {
+		t.Fatalf("unexpected series count: %d", series)
+	} else if points != 6 {
+		t.Fatalf("unexpected point count: %d", points)
+	}
+}
+
+func TestService_ReadFilter_MaxSeriesPerRequest(t *testing.T) {
+	c := storage.NewConfig()
+	c.MaxSeriesPerRequest = 2
+	s := NewService(c)
+	s.Store.ReadFilterFn = func(ctx context.Context, req *datatypes.ReadFilterRequest) (reads.ResultSet, error) {
+		return NewResultSet(3, 2), nil
+	}
+	s.Open(t)
+	defer s.Close()
+
+	if _, _, err := ReadFilter(s.Client(t), context.Background(), "db0"); status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// A result set within the limit is returned in full.
+	s.Store.ReadFilterFn = func(ctx context.Context, req *datatypes.ReadFilterRequest) (reads.ResultSet, error) {
+		return NewResultSet(2, 2), nil
+	}
+	if series, _, err := ReadFilter(s.Client(t), context.Background(), "db0"); err != nil {
+		t.Fatal(err)
+	} else if series != 2 {
+		t.Fatalf("unexpected series count: %d", series)
+	}
+}
+
+func TestService_ReadWindowAggregate(t *testing.T) {
+	s := NewService(storage.NewConfig())
+	s.Store.WindowAggregateFn = func(ctx context.Context, req *datatypes.ReadWindowAggregateRequest) (reads.ResultSet, error) {
+		if req.WindowEvery != 10 {
+			t.Errorf("unexpected window every: %d", req.WindowEvery)
+		} else if len(req.Aggregate) != 1 || req.Aggregate[0].Type != datatypes.AggregateTypeMax {
+			t.Errorf("unexpected aggregate: %v", req.Aggregate)
+		}
+		return NewResultSet(2, 3), nil
+	}
+	s.Open(t)
+	defer s.Close()
+
+	stream, err := s.Client(t).ReadWindowAggregate(context.Background(), &datatypes.ReadWindowAggregateRequest{
+		ReadSource:  ReadSource(t, "db0"),
+		WindowEvery: 10,
+		Aggregate:   []*datatypes.Aggregate{{Type: datatypes.AggregateTypeMax}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var series, points int
+	for {
+		res, err := stream.Recv()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatal(err)
+		}
+		for _, frame := range res.Frames {
+			switch f := frame.Data.(type) {
+			case *datatypes.ReadResponse_Frame_Series:
+				series++
+			case *datatypes.ReadResponse_Frame_IntegerPoints:
+				points += len(f.IntegerPoints.Values)
+			}
+		}
+	}
+	if series != 2 {
+		t.Fatalf("unexpected series count: %d", series)
+	} else if points != 6 {
+		t.Fatalf("unexpected point count: %d", points)
+	}
+}
+
+func TestService_TagKeys(t *testing.T) {
+	s := NewService(storage.NewConfig())
+	s.Store.TagKeysFn = func(ctx context.Context, req *datatypes.TagKeysRequest) (cursors.StringIterator, error) {
+		return cursors.NewStringSliceIterator([]string{"_f", "_m", "host"}), nil
+	}
+	s.Open(t)
+	defer s.Close()
+
+	stream, err := s.Client(t).TagKeys(context.Background(), &datatypes.TagKeysRequest{TagsSource: ReadSource(t, "db0")})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var keys []string
+	for {
+		res, err := stream.Recv()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatal(err)
+		}
+		for _, v := range res.Values {
+			keys = append(keys, string(v))
+		}
+	}
+	if got, exp := fmt.Sprint(keys), "[_f _m host]"; got != exp {
+		t.Fatalf("unexpected keys: got=%s exp=%s", got, exp)
+	}
+}
+
+func TestService_Authentication(t *testing.T) {
+	c := storage.NewConfig()
+	c.AuthEnabled = true
+	s := NewService(c)
+	s.MetaClient.AuthenticateFn = func(username, password string) (meta.User, error) {
+		if username != "alice" || password != "secret" {
+			return nil, errors.New("invalid credentials")
+		}
+		return &meta.UserInfo{
+			Name:       "alice",
+			Privileges: map[string]influxql.Privilege{"db0": influxql.ReadPrivilege},
+		}, nil
+	}
+	s.Store.ReadFilterFn = func(ctx context.Context, req *datatypes.ReadFilterRequest) (reads.ResultSet, error) {
+		return NewResultSet(1, 1), nil
+	}
+	s.Open(t)
+	defer s.Close()
+
+	client := s.Client(t)
+	withToken := func(token string) context.Context {
+		return metadata.AppendToOutgoingContext(context.Background(), storage.AuthorizationKey, token)
+	}
+
+	if _, _, err := ReadFilter(client, context.Background(), "db0"); status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("unexpected error without credentials: %v", err)
+	}
+	if _, _, err := ReadFilter(client, withToken("Token alice:wrong"), "db0"); status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("unexpected error with bad password: %v", err)
+	}
+	if _, _, err := ReadFilter(client, withToken("Token alice:secret"), "db1"); status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("unexpected error for unauthorized database: %v", err)
+	}
+	if series, _, err := ReadFilter(client, withToken("Token alice:secret"), "db0"); err != nil {
+		t.Fatal(err)
+	} else if series != 1 {
+		t.Fatalf("unexpected series count: %d", series)
+	}
+}
+
+// Ensures requests are denied while authentication is enabled and no users
+// exist, rather than being served without credentials.
+func TestService_Authentication_NoUsers(t *testing.T) {
+	c := storage.NewConfig()
+	c.AuthEnabled = true
+	s := NewService(c)
+	s.Store.ReadFilterFn = func(ctx context.Context, req *datatypes.ReadFilterRequest) (reads.ResultSet, error) {
+		t.Error("unexpected read")
+		return nil, nil
+	}
+	s.Open(t)
+	defer s.Close()
+
+	client := s.Client(t)
+	if _, _, err := ReadFilter(client, context.Background(), "db0"); status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("unexpected error without credentials: %v", err)
+	}
+	ctx := metadata.AppendToOutgoingContext(context.Background(), storage.AuthorizationKey, "Token alice:secret")
+	if _, _, err := ReadFilter(client, ctx, "db0"); status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("unexpected error with credentials: %v", err)
+	}
+}
+
+// Ensures API tokens are accepted and authenticated on every request, so a
+// token revoked between two requests on the same connection is denied.
+func TestService_Authentication_Token(t *testing.T) {
+	c := storage.NewConfig()
+	c.AuthEnabled = true
+	s := NewService(c)
+	var revoked bool
+	s.MetaClient.AuthenticateFn = func(username, password string) (meta.User, error) {
+		return nil, errors.New("unexpected password authentication")
+	}
+	s.MetaClient.AuthenticateTokenFn = func(token string) (meta.User, error) {
+		if token != "abc.secret" || revoked {
+			return nil, meta.ErrAuthenticate
+		}
+		return &meta.UserInfo{
+			Name:       "alice",
+			Privileges: map[string]influxql.Privilege{"db0": influxql.ReadPrivilege},
+		}, nil
+	}
+	s.Store.ReadFilterFn = func(ctx context.Context, req *datatypes.ReadFilterRequest) (reads.ResultSet, error) {
+		return NewResultSet(1, 1), nil
+	}
+	s.Open(t)
+	defer s.Close()
+
+	withToken := func(token string) context.Context {
+		return metadata.AppendToOutgoingContext(context.Background(), storage.AuthorizationKey, token)
+	}
+
+	client := s.Client(t)
+	if _, _, err := ReadFilter(client, withToken("Token abc.wrong"), "db0"); status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("unexpected error with bad token: %v", err)
+	}
+	if _, _, err := ReadFilter(client, withToken("Token abc.secret"), "db0"); err != nil {
+		t.Fatal(err)
+	}
+
+	revoked = true
+	if _, _, err := ReadFilter(client, withToken("Token abc.secret"), "db0"); status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("unexpected error with revoked token: %v", err)
+	}
+}
+
+func TestService_Authentication_User(t *testing.T) {
+	c := storage.NewConfig()
+	c.AuthEnabled = true
+	s := NewService(c)
+	s.MetaClient.AuthenticateFn = func(username, password string) (meta.User, error) {
+		return &meta.UserInfo{Name: username, Admin: true}, nil
+	}
+
+	var users []string
+	addUser := func(ctx context.Context) {
+		if user := meta.UserFromContext(ctx); user != nil {
+			users = append(users, user.ID())
+		} else {
+			users = append(users, "")
+		}
+	}
+	s.Store.ReadFilterFn = func(ctx context.Context, req *datatypes.ReadFilterRequest) (reads.ResultSet, error) {
+		addUser(ctx)
+		return NewResultSet(1, 1), nil
+	}
+	s.Store.TagKeysFn = func(ctx context.Context, req *datatypes.TagKeysRequest) (cursors.StringIterator, error) {
+		addUser(ctx)
+		return cursors.NewStringSliceIterator(nil), nil
+	}
+	s.Open(t)
+	defer s.Close()
+
+	client := s.Client(t)
+	ctx := metadata.AppendToOutgoingContext(context.Background(), storage.AuthorizationKey, "Token alice:secret")
+	if _, _, err := ReadFilter(client, ctx, "db0"); err != nil {
+		t.Fatal(err)
+	}
+
+	stream, err := client.TagKeys(ctx, &datatypes.TagKeysRequest{TagsSource: ReadSource(t, "db0")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for {
+		if _, err := stream.Recv(); err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if got, exp := fmt.Sprint(users), "[alice alice]"; got != exp {
+		t.Fatalf("unexpected users: got=%s exp=%s", got, exp)
+	}
+}
+
+func TestService_Mux(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	mux := tcp.NewMux()
+	go mux.Serve(ln)
+
+	c := storage.NewConfig()
+	c.BindAddress = ""
+	s := NewService(c)
+	s.Service.Listener = mux.Listen(storage.MuxHeader)
+	s.Store.ReadFilterFn = func(ctx context.Context, req *datatypes.ReadFilterRequest) (reads.ResultSet, error) {
+		return NewResultSet(2, 1), nil
+	}
+	s.Open(t)
+	defer s.Close()
+
+	conn, err := grpc.Dial(ln.Addr().String(), grpc.WithInsecure(), grpc.WithContextDialer(func(ctx context.Context, addr string) (net.Conn, error) {
+		return tcp.Dial("tcp", addr, storage.MuxHeader)
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	if series, _, err := ReadFilter(datatypes.NewStorageClient(conn), context.Background(), "db0"); err != nil {
+		t.Fatal(err)
+	} else if series != 2 {
+		t.Fatalf("unexpected series count: %d", series)
+	}
+}
+
+// Service is a test wrapper for storage.Service.
+type Service struct {
+	*storage.Service
+
+	MetaClient *MetaClient
+	Store      *internal.StorageStoreMock
+
+	cancel context.CancelFunc
+	done   chan error
+	conns  []*grpc.ClientConn
+}
+
+// NewService returns a new instance of Service bound to a random port.
+func NewService(c storage.Config) *Service {
+	c.Enabled = true
+	if c.BindAddress != "" {
+		c.BindAddress = "127.0.0.1:0"
+	}
+
+	s := &Service{
+		Service:    storage.NewService(c),
+		MetaClient: &MetaClient{},
+		Store:      internal.NewStorageStoreMock(),
+	}
+	s.Service.MetaClient = s.MetaClient
+	s.Service.Store = s.Store
+	return s
+}
+
+// Open starts the service and waits for it to listen.
+func (s *Service) Open(t *testing.T) {
+	var ctx context.Context
+	ctx, s.cancel = context.WithCancel(context.Background())
+	s.done = make(chan error, 1)
+	go func() { s.done <- s.Service.Start(ctx) }()
+
+	select {
+	case <-s.Ready():
+	case err := <-s.done:
+		t.Fatalf("service did not start listening: %v", err)
+	}
+}
+
+// Close closes any clients, stops the service and waits for it to exit.
+func (s *Service) Close() error {
+	for _, conn := range s.conns {
+		conn.Close()
+	}
+	s.cancel()
+	return <-s.done
+}
+
+// Client returns a Storage client connected to the service.
+func (s *Service) Client(t *testing.T) datatypes.StorageClient {
+	conn, err := grpc.Dial(s.Addr().String(), grpc.WithInsecure())
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.conns = append(s.conns, conn)
+	return datatypes.NewStorageClient(conn)
+}
+
+type MetaClient struct {
+	AuthenticateFn      func(username, password string) (meta.User, error)
+	AuthenticateTokenFn func(token string) (meta.User, error)
+}
+
+func (c *MetaClient) AuthenticateFrom(username, password, source string) (meta.User, error) {
+	return c.AuthenticateFn(username, password)
+}
+
+func (c *MetaClient) AuthenticateTokenFrom(token, source string) (meta.User, error) {
+	return c.AuthenticateTokenFn(token)
+}
+
+func (c *MetaClient) AdminUserExists() bool {
+	return c.AuthenticateFn != nil || c.AuthenticateTokenFn != nil
+}
+
+// ReadSource returns a marshaled ReadSource for database.
+func ReadSource(t *testing.T, database string) *types.Any {
+	any, err := types.MarshalAny(&storage.ReadSource{Database: database})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return any
+}
+
+// ReadFilter reads all series of database and returns the number of series
+// and points received.
+func ReadFilter(c datatypes.StorageClient, ctx context.Context, database string) (series, points int, err error) {
+	source, err := types.MarshalAny(&storage.ReadSource{Database: database})
+	if err != nil {
+		return 0, 0, err
+	}
+
+	stream, err := c.ReadFilter(ctx, &datatypes.ReadFilterRequest{ReadSource: source})
+	if err != nil {
+		return 0, 0, err
+	}
+	for {
+		res, err := stream.Recv()
+		if err == io.EOF {
+			return series, points, nil
+		} else if err != nil {
+			return 0, 0, err
+		}
+		for _, frame := range res.Frames {
+			switch f := frame.Data.(type) {
+			case *datatypes.ReadResponse_Frame_Series:
+				series++
+			case *datatypes.ReadResponse_Frame_IntegerPoints:
+				points += len(f.IntegerPoints.Values)
+			}
+		}
+	}
+}
+
+// NewResultSet returns a result set of n integer series, each holding
+// pointsN points.
+func NewResultSet(n, pointsN int) *internal.StorageResultsMock {
+	rs := internal.NewStorageResultsMock()
+	var i int
+	rs.NextFn = func() bool {
+		i++
+		return i <= n
+	}
+	rs.TagsFn = func() models.Tags {
+		return models.NewTags(map[string]string{"_m": "cpu", "host": fmt.Sprintf("server%02d", i)})
+	}
+	rs.CursorFn = func() tsdb.Cursor {
+		cur := internal.NewIntegerArrayCursorMock()
+		var done bool
+		cur.NextFn = func() *tsdb.IntegerArray {
+			if done {
+				return tsdb.NewIntegerArrayLen(0)
+			}
+			done = true
+			a := tsdb.NewIntegerArrayLen(pointsN)
+			for j := range a.Timestamps {
+				a.Timestamps[j], a.Values[j] = int64(j+1), int64(j)
+			}
+			return a
+		}
+		return cur
+	}
+	return rs
+}
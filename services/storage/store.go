@@ -142,6 +142,40 @@ func (s *Store) ReadFilter(ctx context.Context, req *datatypes.ReadFilterRequest
 	return reads.NewFilteredResultSet(ctx, req, cur), nil
 }
 
+func (s *Store) WindowAggregate(ctx context.Context, req *datatypes.ReadWindowAggregateRequest) (reads.ResultSet, error) {
+	if req.ReadSource == nil {
+		return nil, errors.New("missing read source")
+	}
+
+	source, err := GetReadSource(*req.ReadSource)
+	if err != nil {
+		return nil, err
+	}
+
+	database, rp, start, end, err := s.validateArgs(source.Database, source.RetentionPolicy, req.Range.Start, req.Range.End)
+	if err != nil {
+		return nil, err
+	}
+
+	shardIDs, err := s.findShardIDs(database, rp, false, start, end)
+	if err != nil {
+		return nil, err
+	}
+	if len(shardIDs) == 0 {
+		return nil, nil
+	}
+
+	cur, err := newIndexSeriesCursor(ctx, req.Predicate, s.TSDBStore.Shards(shardIDs))
+	if cur == nil || err != nil {
+		return nil, err
+	}
+
+	req.Range.Start = start
+	req.Range.End = end
+
+	return reads.NewWindowAggregateResultSet(ctx, req, cur)
+}
+
 func (s *Store) ReadGroup(ctx context.Context, req *datatypes.ReadGroupRequest) (reads.GroupResultSet, error) {
 	if req.ReadSource == nil {
 		return nil, errors.New("missing read source")
@@ -226,8 +260,7 @@ func (s *Store) TagKeys(ctx context.Context, req *datatypes.TagKeysRequest) (cur
 		}
 	}
 
-	// TODO(jsternberg): Use a real authorizer.
-	auth := query.OpenAuthorizer
+	auth := authorizer(ctx)
 	keys, err := s.TSDBStore.TagKeys(auth, shardIDs, expr)
 	if err != nil {
 		return nil, err
@@ -259,6 +292,8 @@ func (s *Store) TagValues(ctx context.Context, req *datatypes.TagValuesRequest)
 				MeasurementsSource: req.TagsSource,
 				Predicate:          req.Predicate,
 			})
+		case "_field":
+			return s.fieldKeys(ctx, req)
 		}
 	}
 
@@ -322,8 +357,7 @@ func (s *Store) TagValues(ctx context.Context, req *datatypes.TagValuesRequest)
 		expr = tagKeyExpr
 	}
 
-	// TODO(jsternberg): Use a real authorizer.
-	auth := query.OpenAuthorizer
+	auth := authorizer(ctx)
 	values, err := s.TSDBStore.TagValues(auth, shardIDs, expr)
 	if err != nil {
 		return nil, err
@@ -344,6 +378,84 @@ func (s *Store) TagValues(ctx context.Context, req *datatypes.TagValuesRequest)
 	return cursors.NewStringSliceIterator(names), nil
 }
 
+// fieldKeys returns the field keys of the measurements matching the
+// predicate of req in the shards overlapping the requested time range.
+func (s *Store) fieldKeys(ctx context.Context, req *datatypes.TagValuesRequest) (cursors.StringIterator, error) {
+	if req.TagsSource == nil {
+		return nil, errors.New("missing read source")
+	}
+
+	source, err := GetReadSource(*req.TagsSource)
+	if err != nil {
+		return nil, err
+	}
+
+	database, rp, start, end, err := s.validateArgs(source.Database, source.RetentionPolicy, req.Range.Start, req.Range.End)
+	if err != nil {
+		return nil, err
+	}
+
+	shardIDs, err := s.findShardIDs(database, rp, false, start, end)
+	if err != nil {
+		return nil, err
+	}
+	if len(shardIDs) == 0 {
+		return nil, nil
+	}
+
+	var expr influxql.Expr
+	if root := req.Predicate.GetRoot(); root != nil {
+		var err error
+		expr, err = reads.NodeToExpr(root, measurementRemap)
+		if err != nil {
+			return nil, err
+		}
+
+		if found := reads.HasFieldValueKey(expr); found {
+			return nil, errors.New("field values unsupported")
+		}
+		if hasFieldKeyRef(expr) {
+			return nil, errors.New("field key predicates unsupported")
+		}
+		expr = influxql.Reduce(influxql.CloneExpr(expr), nil)
+		if reads.IsTrueBooleanLiteral(expr) {
+			expr = nil
+		}
+	}
+
+	auth := authorizer(ctx)
+	names, err := s.TSDBStore.MeasurementNames(auth, database, expr)
+	if err != nil {
+		return nil, err
+	}
+
+	shards := tsdb.Shards(s.TSDBStore.Shards(shardIDs))
+	m := make(map[string]struct{})
+	for _, name := range names {
+		for _, key := range shards.FieldKeysByMeasurement(name) {
+			m[key] = struct{}{}
+		}
+	}
+
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return cursors.NewStringSliceIterator(keys), nil
+}
+
+// hasFieldKeyRef returns true if expr refers to the _field key.
+func hasFieldKeyRef(expr influxql.Expr) bool {
+	found := false
+	influxql.WalkFunc(expr, func(n influxql.Node) {
+		if ref, ok := n.(*influxql.VarRef); ok && ref.Val == fieldKey {
+			found = true
+		}
+	})
+	return found
+}
+
 type MeasurementNamesRequest struct {
 	MeasurementsSource *types.Any
 	Predicate          *datatypes.Predicate
@@ -367,7 +479,7 @@ func (s *Store) MeasurementNames(ctx context.Context, req *MeasurementNamesReque
 	var expr influxql.Expr
 	if root := req.Predicate.GetRoot(); root != nil {
 		var err error
-		expr, err = reads.NodeToExpr(root, nil)
+		expr, err = reads.NodeToExpr(root, measurementRemap)
 		if err != nil {
 			return nil, err
 		}
@@ -381,8 +493,7 @@ func (s *Store) MeasurementNames(ctx context.Context, req *MeasurementNamesReque
 		}
 	}
 
-	// TODO(jsternberg): Use a real authorizer.
-	auth := query.OpenAuthorizer
+	auth := authorizer(ctx)
 	values, err := s.TSDBStore.MeasurementNames(auth, database, expr)
 	if err != nil {
 		return nil, err
@@ -401,6 +512,15 @@ func (s *Store) MeasurementNames(ctx context.Context, req *MeasurementNamesReque
 	return cursors.NewStringSliceIterator(names), nil
 }
 
+// authorizer returns the user of the request, or an open authorizer if the
+// request is not authenticated.
+func authorizer(ctx context.Context) query.Authorizer {
+	if user := meta.UserFromContext(ctx); user != nil {
+		return user
+	}
+	return query.OpenAuthorizer
+}
+
 func (s *Store) GetSource(db, rp string) proto.Message {
 	return &ReadSource{Database: db, RetentionPolicy: rp}
 }
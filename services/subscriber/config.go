@@ -21,6 +21,18 @@ const (
 
 	// DefaultWriteBufferSize is the default write buffer size for a Config.
 	DefaultWriteBufferSize = 1000
+
+	// DefaultQueueMaxSize is the default maximum size of the queue of each
+	// subscription.
+	DefaultQueueMaxSize = 1024 * 1024 * 1024
+
+	// DefaultQueueRetryInterval is the default time to wait before retrying
+	// a queued write that failed.
+	DefaultQueueRetryInterval = time.Second
+
+	// DefaultQueueMaxRetryInterval is the default maximum time to wait
+	// between retries of a queued write.
+	DefaultQueueMaxRetryInterval = time.Minute
 )
 
 // Config represents a configuration of the subscriber service.
@@ -44,6 +56,22 @@ type Config struct {
 	// The number of in-flight writes buffered in the write channel.
 	WriteBufferSize int `toml:"write-buffer-size"`
 
+	// Whether writes are queued on disk until each subscription receives them.
+	// A write does not succeed until it is synced to the queues.
+	QueueEnabled bool `toml:"queue-enabled"`
+
+	// The directory where the queue of each subscription is stored.
+	QueueDir string `toml:"queue-dir"`
+
+	// The maximum size of the queue of each subscription. Writes are dropped
+	// once a queue is full.
+	QueueMaxSize toml.Size `toml:"queue-max-size"`
+
+	// The time to wait before retrying a queued write that failed. It doubles
+	// with each failure up to QueueMaxRetryInterval.
+	QueueRetryInterval    toml.Duration `toml:"queue-retry-interval"`
+	QueueMaxRetryInterval toml.Duration `toml:"queue-max-retry-interval"`
+
 	// TLS is a base tls config to use for https clients.
 	TLS *tls.Config `toml:"-"`
 }
@@ -57,6 +85,10 @@ func NewConfig() Config {
 		CaCerts:            "",
 		WriteConcurrency:   DefaultWriteConcurrency,
 		WriteBufferSize:    DefaultWriteBufferSize,
+
+		QueueMaxSize:          DefaultQueueMaxSize,
+		QueueRetryInterval:    toml.Duration(DefaultQueueRetryInterval),
+		QueueMaxRetryInterval: toml.Duration(DefaultQueueMaxRetryInterval),
 	}
 }
 
@@ -82,6 +114,21 @@ func (c Config) Validate() error {
 		return errors.New("write-concurrency must be greater than 0")
 	}
 
+	if c.QueueEnabled {
+		if c.QueueDir == "" {
+			return errors.New("queue-dir must be specified")
+		}
+		if c.QueueMaxSize == 0 {
+			return errors.New("queue-max-size must be greater than 0")
+		}
+		if c.QueueRetryInterval <= 0 {
+			return errors.New("queue-retry-interval must be greater than 0")
+		}
+		if c.QueueMaxRetryInterval < c.QueueRetryInterval {
+			return errors.New("queue-max-retry-interval must not be less than queue-retry-interval")
+		}
+	}
+
 	return nil
 }
 
@@ -103,5 +150,7 @@ func (c Config) Diagnostics() (*diagnostics.Diagnostics, error) {
 		"http-timeout":      c.HTTPTimeout,
 		"write-concurrency": c.WriteConcurrency,
 		"write-buffer-size": c.WriteBufferSize,
+		"queue-enabled":     c.QueueEnabled,
+		"queue-max-size":    c.QueueMaxSize,
 	}), nil
 }
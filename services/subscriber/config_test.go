@@ -109,3 +109,37 @@ write-concurrency = 10
 		t.Errorf("Expected Validation to succeed. Instead was: %v", err)
 	}
 }
+
+func TestConfig_ParseQueue(t *testing.T) {
+	// Parse configuration.
+	c := subscriber.NewConfig()
+	if _, err := toml.Decode(`
+queue-enabled = true
+queue-dir = "/var/lib/influxdb/subscriber"
+queue-max-size = "10m"
+queue-retry-interval = "5s"
+`, &c); err != nil {
+		t.Fatal(err)
+	}
+
+	// Validate configuration.
+	if !c.QueueEnabled {
+		t.Errorf("unexpected queue enabled state: %v", c.QueueEnabled)
+	}
+	if c.QueueMaxSize != 10*1024*1024 {
+		t.Errorf("QueueMaxSize: expected %d. got %d", 10*1024*1024, c.QueueMaxSize)
+	}
+	if err := c.Validate(); err != nil {
+		t.Errorf("Expected Validation to succeed. Instead was: %v", err)
+	}
+
+	c.QueueMaxRetryInterval = c.QueueRetryInterval / 2
+	if err := c.Validate(); err == nil || err.Error() != "queue-max-retry-interval must not be less than queue-retry-interval" {
+		t.Errorf("unexpected validation error: %v", err)
+	}
+
+	c.QueueDir = ""
+	if err := c.Validate(); err == nil || err.Error() != "queue-dir must be specified" {
+		t.Errorf("unexpected validation error: %v", err)
+	}
+}
@@ -0,0 +1,117 @@
+package subscriber
+
+import (
+	"github.com/ayang64/reflux/coordinator"
+	"github.com/ayang64/reflux/influxql"
+	"github.com/ayang64/reflux/models"
+	"github.com/ayang64/reflux/services/meta"
+)
+
+// subscriptionFilter selects the points and the fields forwarded to a
+// subscription.
+type subscriptionFilter struct {
+	condition influxql.Expr
+	fields    map[string]struct{}
+}
+
+// newSubscriptionFilter returns the filter of a subscription. It returns nil
+// if the subscription forwards every point.
+func newSubscriptionFilter(si meta.SubscriptionInfo) (*subscriptionFilter, error) {
+	if si.Condition == "" && len(si.Fields) == 0 {
+		return nil, nil
+	}
+
+	f := &subscriptionFilter{}
+	if si.Condition != "" {
+		expr, err := influxql.ParseExpr(si.Condition)
+		if err != nil {
+			return nil, err
+		}
+		f.condition = expr
+	}
+	if len(si.Fields) > 0 {
+		f.fields = make(map[string]struct{}, len(si.Fields))
+		for _, name := range si.Fields {
+			f.fields[name] = struct{}{}
+		}
+	}
+	return f, nil
+}
+
+// apply returns the request with only the points and fields forwarded to the
+// subscription, or nil if none are. A nil filter returns the request as is.
+func (f *subscriptionFilter) apply(wr *coordinator.WritePointsRequest) *coordinator.WritePointsRequest {
+	if f == nil {
+		return wr
+	}
+
+	points := make([]models.Point, 0, len(wr.Points))
+	for _, p := range wr.Points {
+		if f.condition != nil {
+			eval := influxql.ValuerEval{Valuer: &pointValuer{p: p}}
+			if !eval.EvalBool(f.condition) {
+				continue
+			}
+		}
+		if f.fields != nil {
+			if p = f.project(p); p == nil {
+				continue
+			}
+		}
+		points = append(points, p)
+	}
+	if len(points) == 0 {
+		return nil
+	}
+
+	return &coordinator.WritePointsRequest{
+		Database:        wr.Database,
+		RetentionPolicy: wr.RetentionPolicy,
+		Points:          points,
+	}
+}
+
+// project returns the point with only the forwarded fields, or nil if it has
+// none of them.
+func (f *subscriptionFilter) project(p models.Point) models.Point {
+	fields, err := p.Fields()
+	if err != nil {
+		return nil
+	}
+
+	n := len(fields)
+	for k := range fields {
+		if _, ok := f.fields[k]; !ok {
+			delete(fields, k)
+		}
+	}
+	if len(fields) == 0 {
+		return nil
+	} else if len(fields) == n {
+		return p
+	}
+
+	pt, err := models.NewPoint(string(p.Name()), p.Tags(), fields, p.Time())
+	if err != nil {
+		return nil
+	}
+	return pt
+}
+
+// pointValuer looks up the measurement name and the tags of a point. A tag
+// the point does not have is empty, as it is for the index.
+type pointValuer struct {
+	p    models.Point
+	tags models.Tags
+}
+
+// Value returns the measurement name for "_name" and the tag value otherwise.
+func (v *pointValuer) Value(key string) (interface{}, bool) {
+	if key == "_name" {
+		return string(v.p.Name()), true
+	}
+	if v.tags == nil {
+		v.tags = v.p.Tags()
+	}
+	return v.tags.GetString(key), true
+}
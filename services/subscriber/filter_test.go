@@ -0,0 +1,61 @@
+package subscriber
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ayang64/reflux/coordinator"
+	"github.com/ayang64/reflux/models"
+	"github.com/ayang64/reflux/services/meta"
+)
+
+// Ensures a subscription only forwards the selected points and fields.
+func TestSubscriptionFilter_Apply(t *testing.T) {
+	f, err := newSubscriptionFilter(meta.SubscriptionInfo{
+		Fields:    []string{"level", "message"},
+		Condition: `_name =~ /^alerts\./ AND host != 'test'`,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ts := time.Unix(0, 1)
+	wr := &coordinator.WritePointsRequest{
+		Database:        "db0",
+		RetentionPolicy: "rp0",
+		Points: []models.Point{
+			models.MustNewPoint("alerts.cpu", models.NewTags(map[string]string{"host": "h0"}), models.Fields{"level": int64(2), "value": 1.0}, ts),
+			models.MustNewPoint("alerts.cpu", models.NewTags(map[string]string{"host": "test"}), models.Fields{"level": int64(2)}, ts),
+			models.MustNewPoint("alerts.mem", nil, models.Fields{"message": "low"}, ts),
+			models.MustNewPoint("alerts.mem", nil, models.Fields{"value": 1.0}, ts),
+			models.MustNewPoint("cpu", nil, models.Fields{"level": int64(1)}, ts),
+		},
+	}
+
+	got := f.apply(wr)
+	if got == nil {
+		t.Fatal("expected points")
+	} else if got.Database != "db0" || got.RetentionPolicy != "rp0" {
+		t.Fatalf("unexpected request: %v", got)
+	}
+	var lines []string
+	for _, p := range got.Points {
+		lines = append(lines, p.String())
+	}
+	if exp := []string{`alerts.cpu,host=h0 level=2i 1`, `alerts.mem message="low" 1`}; !reflect.DeepEqual(lines, exp) {
+		t.Fatalf("unexpected points:\ngot %v\nexp %v", lines, exp)
+	}
+
+	// A request without any selected points is not forwarded.
+	if got := f.apply(&coordinator.WritePointsRequest{Points: wr.Points[4:]}); got != nil {
+		t.Fatalf("unexpected request: %v", got)
+	}
+
+	// A subscription without a filter forwards every point.
+	if f, err := newSubscriptionFilter(meta.SubscriptionInfo{}); err != nil {
+		t.Fatal(err)
+	} else if got := f.apply(wr); got != wr {
+		t.Fatalf("unexpected request: %v", got)
+	}
+}
@@ -0,0 +1,463 @@
+package subscriber
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ayang64/reflux/models"
+	"github.com/ayang64/reflux/pkg/file"
+)
+
+var (
+	// ErrQueueFull is returned when points are appended to a subscription
+	// queue that has reached its maximum size.
+	ErrQueueFull = errors.New("subscription queue is full")
+
+	// errQueueClosed is returned when a closed queue is used.
+	errQueueClosed = errors.New("subscription queue is closed")
+
+	// errQueueEmpty is returned when there is nothing to deliver.
+	errQueueEmpty = errors.New("subscription queue is empty")
+)
+
+const (
+	// queueSegmentSize is the size at which a new segment is started.
+	queueSegmentSize = 10 * 1024 * 1024
+
+	// queueHeaderSize is the size of the length and the enqueue time that
+	// precede each record.
+	queueHeaderSize = 12
+
+	// queuePositionFile is the name of the file holding the replay position.
+	queuePositionFile = "position"
+)
+
+// queueSegment is a file of records in a queue.
+type queueSegment struct {
+	id   uint64
+	path string
+	size int64
+}
+
+// queue is an on-disk queue of the points written to a subscription. Points
+// are appended to segment files and delivered in order from the replay
+// position, which is saved after every delivery so that a restart resumes
+// where delivery stopped. Segments are removed once they are delivered.
+//
+// Appends and the replay position are synced to disk before they return, so
+// a record is delivered at least once even if the process crashes. The sync
+// of an append happens outside of mu and is shared by the appends that are
+// waiting for it, so concurrent writes do not each wait for their own sync.
+type queue struct {
+	mu          sync.Mutex
+	dir         string
+	maxSize     int64
+	segmentSize int64
+
+	segments []*queueSegment // oldest first
+	tail     *os.File        // the last segment, opened for appending
+	headFile *os.File        // the first segment, opened for reading
+	head     int64           // offset of the next record in the first segment
+
+	size   int64     // the size of all segments
+	depth  int64     // the number of undelivered records
+	oldest time.Time // the enqueue time of the record at the head
+
+	written uint64 // the number of records appended, protected by mu
+
+	syncMu sync.Mutex
+	synced uint64 // the number of appended records synced to disk
+
+	notify  chan struct{}
+	closing chan struct{}
+	closed  bool
+}
+
+// openQueue opens the queue stored in dir, creating it if it does not exist.
+// A maxSize of zero does not limit the size of the queue.
+func openQueue(dir string, maxSize int64) (*queue, error) {
+	if err := os.MkdirAll(dir, 0777); err != nil {
+		return nil, err
+	}
+
+	q := &queue{
+		dir:         dir,
+		maxSize:     maxSize,
+		segmentSize: queueSegmentSize,
+		notify:      make(chan struct{}, 1),
+		closing:     make(chan struct{}),
+	}
+	if maxSize > 0 && q.segmentSize > maxSize/4 {
+		q.segmentSize = maxSize / 4
+	}
+
+	if err := q.load(); err != nil {
+		q.close()
+		return nil, err
+	}
+	return q, nil
+}
+
+// load reads the segments and the replay position from disk.
+func (q *queue) load() error {
+	fis, err := ioutil.ReadDir(q.dir)
+	if err != nil {
+		return err
+	}
+	for _, fi := range fis {
+		id, err := strconv.ParseUint(fi.Name(), 10, 64)
+		if err != nil || fi.IsDir() {
+			continue
+		}
+		q.segments = append(q.segments, &queueSegment{id: id, path: fi.Name(), size: fi.Size()})
+	}
+	sort.Slice(q.segments, func(i, j int) bool { return q.segments[i].id < q.segments[j].id })
+	for _, seg := range q.segments {
+		seg.path = filepath.Join(q.dir, seg.path)
+	}
+
+	// Remove the segments that were delivered before the position was saved.
+	id, offset, err := q.readPosition()
+	if err != nil {
+		return err
+	}
+	for len(q.segments) > 0 && q.segments[0].id < id {
+		if err := os.Remove(q.segments[0].path); err != nil {
+			return err
+		}
+		q.segments = q.segments[1:]
+	}
+	if len(q.segments) > 0 && q.segments[0].id == id && offset <= q.segments[0].size {
+		q.head = offset
+	}
+
+	// Count the undelivered records. A record at the end of the last segment
+	// that was not completely written is removed.
+	for i, seg := range q.segments {
+		var start int64
+		if i == 0 {
+			start = q.head
+		}
+		n, end, err := countRecords(seg.path, start, seg.size)
+		if err != nil {
+			return err
+		}
+		if end < seg.size && i == len(q.segments)-1 {
+			if err := os.Truncate(seg.path, end); err != nil {
+				return err
+			}
+			seg.size = end
+		}
+		q.depth += n
+		q.size += seg.size
+	}
+
+	if len(q.segments) == 0 {
+		q.segments = append(q.segments, &queueSegment{
+			id:   id + 1,
+			path: filepath.Join(q.dir, strconv.FormatUint(id+1, 10)),
+		})
+	}
+	tail := q.segments[len(q.segments)-1]
+	if q.tail, err = os.OpenFile(tail.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0666); err != nil {
+		return err
+	} else if err := file.SyncDir(q.dir); err != nil {
+		return err
+	}
+
+	if q.depth > 0 {
+		if _, q.oldest, err = q.next(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// countRecords returns the number of complete records in a segment from
+// start and the offset after the last of them.
+func countRecords(path string, start, size int64) (int64, int64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer f.Close()
+
+	var n int64
+	var hdr [queueHeaderSize]byte
+	for start+queueHeaderSize <= size {
+		if _, err := f.ReadAt(hdr[:], start); err != nil {
+			return 0, 0, err
+		}
+		end := start + queueHeaderSize + int64(binary.BigEndian.Uint32(hdr[:4]))
+		if end > size {
+			break
+		}
+		n, start = n+1, end
+	}
+	return n, start, nil
+}
+
+// readPosition returns the segment and the offset of the replay position.
+func (q *queue) readPosition() (uint64, int64, error) {
+	b, err := ioutil.ReadFile(filepath.Join(q.dir, queuePositionFile))
+	if os.IsNotExist(err) {
+		return 0, 0, nil
+	} else if err != nil {
+		return 0, 0, err
+	} else if len(b) != 16 {
+		return 0, 0, fmt.Errorf("invalid queue position in %s", q.dir)
+	}
+	return binary.BigEndian.Uint64(b[:8]), int64(binary.BigEndian.Uint64(b[8:])), nil
+}
+
+// writePosition saves the replay position.
+func (q *queue) writePosition() error {
+	var b [16]byte
+	binary.BigEndian.PutUint64(b[:8], q.segments[0].id)
+	binary.BigEndian.PutUint64(b[8:], uint64(q.head))
+
+	path := filepath.Join(q.dir, queuePositionFile)
+	f, err := os.Create(path + ".tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(b[:]); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if err := file.RenameFile(path+".tmp", path); err != nil {
+		return err
+	}
+	return file.SyncDir(q.dir)
+}
+
+// append adds points to the end of the queue.
+func (q *queue) append(points []models.Point, t time.Time) error {
+	var b []byte
+	for _, p := range points {
+		b = p.AppendString(b)
+		b = append(b, '\n')
+	}
+
+	q.mu.Lock()
+	if q.closed {
+		q.mu.Unlock()
+		return errQueueClosed
+	}
+
+	n := int64(queueHeaderSize + len(b))
+	if q.maxSize > 0 && q.size+n > q.maxSize {
+		q.mu.Unlock()
+		return ErrQueueFull
+	}
+
+	tail := q.segments[len(q.segments)-1]
+	if tail.size >= q.segmentSize {
+		if err := q.roll(); err != nil {
+			q.mu.Unlock()
+			return err
+		}
+		tail = q.segments[len(q.segments)-1]
+	}
+
+	rec := make([]byte, n)
+	binary.BigEndian.PutUint32(rec[:4], uint32(len(b)))
+	binary.BigEndian.PutUint64(rec[4:12], uint64(t.UnixNano()))
+	copy(rec[queueHeaderSize:], b)
+	if _, err := q.tail.Write(rec); err != nil {
+		q.mu.Unlock()
+		return err
+	}
+	tail.size += n
+	q.size += n
+	q.written++
+	seq := q.written
+
+	if q.depth++; q.depth == 1 {
+		q.oldest = t
+	}
+	select {
+	case q.notify <- struct{}{}:
+	default:
+	}
+	q.mu.Unlock()
+
+	return q.sync(seq)
+}
+
+// sync returns once the first seq appended records are synced to disk. A
+// single sync covers every record appended before it starts.
+func (q *queue) sync(seq uint64) error {
+	q.syncMu.Lock()
+	defer q.syncMu.Unlock()
+	if q.synced >= seq {
+		return nil
+	}
+
+	q.mu.Lock()
+	f, n := q.tail, q.written
+	q.mu.Unlock()
+
+	if err := f.Sync(); err != nil {
+		// A segment is synced before it is rolled, so the error only matters
+		// if f is still the tail of an open queue.
+		q.mu.Lock()
+		closed, rolled := q.closed, f != q.tail
+		q.mu.Unlock()
+		if closed {
+			return errQueueClosed
+		} else if !rolled {
+			return err
+		}
+	}
+	q.synced = n
+	return nil
+}
+
+// roll starts a new segment. The records appended to the last segment are
+// synced before it is closed.
+func (q *queue) roll() error {
+	if err := q.tail.Sync(); err != nil {
+		return err
+	} else if err := q.tail.Close(); err != nil {
+		return err
+	}
+
+	id := q.segments[len(q.segments)-1].id + 1
+	seg := &queueSegment{id: id, path: filepath.Join(q.dir, strconv.FormatUint(id, 10))}
+	f, err := os.OpenFile(seg.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0666)
+	if err != nil {
+		return err
+	} else if err := file.SyncDir(q.dir); err != nil {
+		f.Close()
+		return err
+	}
+	q.segments = append(q.segments, seg)
+	q.tail = f
+	return nil
+}
+
+// next returns the length and the enqueue time of the record at the head,
+// removing the first segment if it was completely delivered.
+func (q *queue) next() (int64, time.Time, error) {
+	for q.head >= q.segments[0].size && len(q.segments) > 1 {
+		if q.headFile != nil {
+			q.headFile.Close()
+			q.headFile = nil
+		}
+		if err := os.Remove(q.segments[0].path); err != nil {
+			return 0, time.Time{}, err
+		}
+		q.size -= q.segments[0].size
+		q.segments, q.head = q.segments[1:], 0
+	}
+	if q.depth == 0 {
+		return 0, time.Time{}, errQueueEmpty
+	}
+
+	if q.headFile == nil {
+		f, err := os.Open(q.segments[0].path)
+		if err != nil {
+			return 0, time.Time{}, err
+		}
+		q.headFile = f
+	}
+
+	var hdr [queueHeaderSize]byte
+	if _, err := q.headFile.ReadAt(hdr[:], q.head); err != nil {
+		return 0, time.Time{}, err
+	}
+	return int64(binary.BigEndian.Uint32(hdr[:4])), time.Unix(0, int64(binary.BigEndian.Uint64(hdr[4:]))), nil
+}
+
+// peek returns the points at the head of the queue. It returns errQueueEmpty
+// if every record was delivered.
+func (q *queue) peek() ([]models.Point, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.closed {
+		return nil, errQueueClosed
+	}
+
+	n, _, err := q.next()
+	if err != nil {
+		return nil, err
+	}
+	b := make([]byte, n)
+	if _, err := q.headFile.ReadAt(b, q.head+queueHeaderSize); err != nil {
+		return nil, err
+	}
+	return models.ParsePoints(b)
+}
+
+// advance moves the head of the queue past the record returned by peek and
+// saves the replay position.
+func (q *queue) advance() error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.closed {
+		return errQueueClosed
+	}
+
+	n, _, err := q.next()
+	if err != nil {
+		return err
+	}
+	q.head += queueHeaderSize + n
+	q.depth--
+
+	// Save the position before moving to the next record so that the
+	// segment it is in is only removed after the position is saved.
+	if err := q.writePosition(); err != nil {
+		return err
+	}
+
+	q.oldest = time.Time{}
+	if q.depth > 0 {
+		_, q.oldest, err = q.next()
+	}
+	return err
+}
+
+// status returns the number of undelivered records and the enqueue time of
+// the oldest of them.
+func (q *queue) status() (int64, time.Time) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.depth, q.oldest
+}
+
+// close closes the files of the queue and stops waiting for records.
+func (q *queue) close() error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.closed {
+		return nil
+	}
+	q.closed = true
+	close(q.closing)
+
+	var err error
+	if q.tail != nil {
+		err = q.tail.Close()
+	}
+	if q.headFile != nil {
+		q.headFile.Close()
+	}
+	return err
+}
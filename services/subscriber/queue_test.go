@@ -0,0 +1,231 @@
+package subscriber
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ayang64/reflux/coordinator"
+	"github.com/ayang64/reflux/models"
+	"go.uber.org/zap"
+)
+
+func mustOpenQueue(t *testing.T, dir string, maxSize int64) *queue {
+	q, err := openQueue(dir, maxSize)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return q
+}
+
+func queuePoints(v float64) []models.Point {
+	return []models.Point{models.MustNewPoint("cpu", nil, models.Fields{"value": v}, time.Unix(0, 1))}
+}
+
+// Ensures the undelivered points of a queue are delivered in order after the
+// queue is reopened.
+func TestQueue_Reopen(t *testing.T) {
+	dir, err := ioutil.TempDir("", "subscriber_queue")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	q := mustOpenQueue(t, dir, 0)
+	q.segmentSize = 1
+	for i := 1; i <= 3; i++ {
+		if err := q.append(queuePoints(float64(i)), time.Unix(int64(i), 0)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := q.peek(); err != nil {
+		t.Fatal(err)
+	} else if err := q.advance(); err != nil {
+		t.Fatal(err)
+	}
+	q.close()
+
+	q = mustOpenQueue(t, dir, 0)
+	defer q.close()
+	if depth, oldest := q.status(); depth != 2 || !oldest.Equal(time.Unix(2, 0)) {
+		t.Fatalf("unexpected status: depth=%d oldest=%s", depth, oldest)
+	}
+
+	for i := 2; i <= 3; i++ {
+		points, err := q.peek()
+		if err != nil {
+			t.Fatal(err)
+		} else if exp := queuePoints(float64(i))[0].String(); len(points) != 1 || points[0].String() != exp {
+			t.Fatalf("unexpected points: got %v, exp %s", points, exp)
+		} else if err := q.advance(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := q.peek(); err != errQueueEmpty {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Only the last segment is kept once everything was delivered.
+	if fis, err := ioutil.ReadDir(dir); err != nil {
+		t.Fatal(err)
+	} else if len(fis) != 2 || fis[0].Name() != "3" || fis[1].Name() != queuePositionFile {
+		t.Fatalf("unexpected files: %v", fis)
+	}
+}
+
+// Ensures a record that was not completely written is discarded.
+func TestQueue_Truncated(t *testing.T) {
+	dir, err := ioutil.TempDir("", "subscriber_queue")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	q := mustOpenQueue(t, dir, 0)
+	if err := q.append(queuePoints(1), time.Unix(1, 0)); err != nil {
+		t.Fatal(err)
+	}
+	q.close()
+
+	f, err := os.OpenFile(filepath.Join(dir, "1"), os.O_WRONLY|os.O_APPEND, 0666)
+	if err != nil {
+		t.Fatal(err)
+	} else if _, err := f.Write([]byte{0, 0, 1}); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	q = mustOpenQueue(t, dir, 0)
+	defer q.close()
+	if depth, _ := q.status(); depth != 1 {
+		t.Fatalf("unexpected depth: %d", depth)
+	}
+	if err := q.append(queuePoints(2), time.Unix(2, 0)); err != nil {
+		t.Fatal(err)
+	} else if depth, _ := q.status(); depth != 2 {
+		t.Fatalf("unexpected depth: %d", depth)
+	}
+}
+
+// Ensures concurrent appends are all synced, including the appends to a
+// segment that is rolled while they wait for their sync.
+func TestQueue_ConcurrentAppend(t *testing.T) {
+	dir, err := ioutil.TempDir("", "subscriber_queue")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	q := mustOpenQueue(t, dir, 0)
+	q.segmentSize = 200
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 8)
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				if err := q.append(queuePoints(float64(j)), time.Now()); err != nil {
+					errs <- err
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Fatal(err)
+	}
+	if q.synced != 400 {
+		t.Fatalf("unexpected synced records: %d", q.synced)
+	}
+	q.close()
+
+	q = mustOpenQueue(t, dir, 0)
+	defer q.close()
+	if depth, _ := q.status(); depth != 400 {
+		t.Fatalf("unexpected depth: %d", depth)
+	}
+}
+
+// Ensures points are not queued beyond the maximum size.
+func TestQueue_Full(t *testing.T) {
+	dir, err := ioutil.TempDir("", "subscriber_queue")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	q := mustOpenQueue(t, dir, 40)
+	defer q.close()
+	if err := q.append(queuePoints(1), time.Now()); err != nil {
+		t.Fatal(err)
+	} else if err := q.append(queuePoints(2), time.Now()); err != ErrQueueFull {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+type queuePointsWriter func(p *coordinator.WritePointsRequest) error
+
+func (fn queuePointsWriter) WritePoints(p *coordinator.WritePointsRequest) error { return fn(p) }
+
+// Ensures a queued write is retried until it is delivered.
+func TestChanWriter_Deliver(t *testing.T) {
+	dir, err := ioutil.TempDir("", "subscriber_queue")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	var attempts, written, failures int64
+	delivered := make(chan *coordinator.WritePointsRequest, 2)
+	cw := chanWriter{
+		writeRequests: make(chan *coordinator.WritePointsRequest),
+		pw: queuePointsWriter(func(p *coordinator.WritePointsRequest) error {
+			if atomic.AddInt64(&attempts, 1) <= 2 {
+				return errors.New("destination down")
+			}
+			delivered <- p
+			return nil
+		}),
+		pointsWritten: &written,
+		failures:      &failures,
+		logger:        zap.NewNop(),
+		queue:         mustOpenQueue(t, dir, 0),
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		cw.deliver(subEntry{db: "db0", rp: "rp0", name: "s0"}, time.Millisecond, 2*time.Millisecond)
+	}()
+
+	for i := 1; i <= 2; i++ {
+		if err := cw.queue.append(queuePoints(float64(i)), time.Now()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i := 1; i <= 2; i++ {
+		select {
+		case p := <-delivered:
+			if p.Database != "db0" || p.RetentionPolicy != "rp0" || p.Points[0].String() != queuePoints(float64(i))[0].String() {
+				t.Fatalf("unexpected request: %v", p)
+			}
+		case <-time.After(10 * time.Second):
+			t.Fatal("timed out waiting for delivery")
+		}
+	}
+
+	cw.Close()
+	<-done
+	if failures != 2 || written != 2 {
+		t.Fatalf("unexpected stats: failures=%d written=%d", failures, written)
+	}
+}
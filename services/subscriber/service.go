@@ -7,6 +7,8 @@ import (
 	"errors"
 	"fmt"
 	"net/url"
+	"os"
+	"path/filepath"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -68,6 +70,8 @@ func NewService(c Config) *Service {
 		closed: true,
 		stats:  &Statistics{},
 		conf:   c,
+		update: make(chan struct{}),
+		points: make(chan *coordinator.WritePointsRequest, 100),
 	}
 	s.NewPointsWriter = s.newPointsWriter
 	return s
@@ -87,9 +91,6 @@ func (s *Service) Start(ctx context.Context) error {
 
 	s.closed = false
 
-	s.update = make(chan struct{})
-	s.points = make(chan *coordinator.WritePointsRequest, 100)
-
 	go s.waitForMetaUpdates(ctx)
 
 	return s.run(ctx)
@@ -159,7 +160,6 @@ func (s *Service) waitForMetaUpdates(ctx context.Context) error {
 			return ctx.Err()
 		}
 	}
-	return nil
 }
 
 // Update will start new and stop deleted subscriptions.
@@ -217,15 +217,56 @@ func (s *Service) Points() chan<- *coordinator.WritePointsRequest {
 	return s.points
 }
 
+// WritePoints appends the points of a write to the queues of the matching
+// subscriptions. Queued subscriptions receive writes here rather than through
+// Points so that a write is on disk before it returns. Writes are only
+// dropped once a queue is full.
+func (s *Service) WritePoints(p *coordinator.WritePointsRequest) error {
+	type queuedWrite struct {
+		name   string
+		queue  *queue
+		points []models.Point
+	}
+
+	// The queues are appended to, and synced, after the subscriptions are
+	// unlocked so that slow disks do not hold up subscription updates.
+	var writes []queuedWrite
+	s.subMu.RLock()
+	for se, cw := range s.subs {
+		if cw.queue == nil || p.Database != se.db || p.RetentionPolicy != se.rp {
+			continue
+		}
+
+		// Only queue the points and fields the subscription selects.
+		if wr := cw.filter.apply(p); wr != nil {
+			writes = append(writes, queuedWrite{name: se.name, queue: cw.queue, points: wr.Points})
+		}
+	}
+	s.subMu.RUnlock()
+
+	for _, w := range writes {
+		if err := w.queue.append(w.points, time.Now()); err == ErrQueueFull {
+			s.Logger.Info("Failed to queue points for subscription", zap.String("name", w.name), zap.Error(err))
+			atomic.AddInt64(&s.stats.WriteFailures, 1)
+		} else if err == errQueueClosed {
+			continue // The subscription was dropped.
+		} else if err != nil {
+			return fmt.Errorf("queue points for subscription %s: %s", w.name, err)
+		}
+	}
+	return nil
+}
+
 // run read points from the points channel and writes them to the subscriptions.
 func (s *Service) run(ctx context.Context) error {
 	var wg sync.WaitGroup
-	s.subs = make(map[subEntry]chanWriter)
 	// Perform initial update
 	s.updateSubs(&wg)
 	for {
 		select {
 		case <-ctx.Done():
+			// Stop delivering queued writes.
+			s.close(&wg)
 			return ctx.Err()
 
 		case <-s.update:
@@ -237,9 +278,16 @@ func (s *Service) run(ctx context.Context) error {
 				return fmt.Errorf("not sure why we returned")
 			}
 			for se, cw := range s.subs {
-				if p.Database == se.db && p.RetentionPolicy == se.rp {
+				// Queued subscriptions receive writes through WritePoints.
+				if cw.queue == nil && p.Database == se.db && p.RetentionPolicy == se.rp {
+					// Only forward the points and fields the subscription selects.
+					wr := cw.filter.apply(p)
+					if wr == nil {
+						continue
+					}
+
 					select {
-					case cw.writeRequests <- p:
+					case cw.writeRequests <- wr:
 					default:
 						atomic.AddInt64(&s.stats.WriteFailures, 1)
 					}
@@ -291,19 +339,42 @@ func (s *Service) updateSubs(wg *sync.WaitGroup) {
 					s.Logger.Info("Subscription creation failed", zap.String("name", si.Name), zap.Error(err))
 					continue
 				}
+				filter, err := newSubscriptionFilter(si)
+				if err != nil {
+					atomic.AddInt64(&s.stats.CreateFailures, 1)
+					s.Logger.Info("Subscription creation failed", zap.String("name", si.Name), zap.Error(err))
+					continue
+				}
 				cw := chanWriter{
 					writeRequests: make(chan *coordinator.WritePointsRequest, s.conf.WriteBufferSize),
 					pw:            sub,
 					pointsWritten: &s.stats.PointsWritten,
 					failures:      &s.stats.WriteFailures,
 					logger:        s.Logger,
+					filter:        filter,
 				}
-				for i := 0; i < s.conf.WriteConcurrency; i++ {
+				if s.conf.QueueEnabled {
+					// Queued writes are delivered in order by a single writer.
+					q, err := openQueue(s.queueDir(se), int64(s.conf.QueueMaxSize))
+					if err != nil {
+						atomic.AddInt64(&s.stats.CreateFailures, 1)
+						s.Logger.Info("Subscription queue creation failed", zap.String("name", si.Name), zap.Error(err))
+						continue
+					}
+					cw.queue = q
 					wg.Add(1)
 					go func() {
 						defer wg.Done()
-						cw.Run()
+						cw.deliver(se, time.Duration(s.conf.QueueRetryInterval), time.Duration(s.conf.QueueMaxRetryInterval))
 					}()
+				} else {
+					for i := 0; i < s.conf.WriteConcurrency; i++ {
+						wg.Add(1)
+						go func() {
+							defer wg.Done()
+							cw.Run()
+						}()
+					}
 				}
 				s.subs[se] = cw
 				s.Logger.Info("Added new subscription",
@@ -319,6 +390,13 @@ func (s *Service) updateSubs(wg *sync.WaitGroup) {
 			// Close the chanWriter
 			s.subs[se].Close()
 
+			// Remove the queue of the deleted subscription.
+			if s.subs[se].queue != nil {
+				if err := os.RemoveAll(s.queueDir(se)); err != nil {
+					s.Logger.Info("Failed to remove subscription queue", zap.String("name", se.name), zap.Error(err))
+				}
+			}
+
 			// Remove it from the set
 			delete(s.subs, se)
 			s.Logger.Info("Deleted old subscription",
@@ -328,6 +406,26 @@ func (s *Service) updateSubs(wg *sync.WaitGroup) {
 	}
 }
 
+// queueDir returns the directory of the queue of a subscription.
+func (s *Service) queueDir(se subEntry) string {
+	return filepath.Join(s.conf.QueueDir, se.db, se.rp, se.name)
+}
+
+// QueueStatus returns the number of queued writes of a subscription that were
+// not delivered yet and the time the oldest of them was queued. It returns
+// false if the subscription does not have a queue.
+func (s *Service) QueueStatus(database, retentionPolicy, name string) (int64, time.Time, bool) {
+	s.subMu.RLock()
+	defer s.subMu.RUnlock()
+
+	cw, ok := s.subs[subEntry{db: database, rp: retentionPolicy, name: name}]
+	if !ok || cw.queue == nil {
+		return 0, time.Time{}, false
+	}
+	depth, oldest := cw.queue.status()
+	return depth, oldest, true
+}
+
 // newPointsWriter returns a new PointsWriter from the given URL.
 func (s *Service) newPointsWriter(u url.URL) (PointsWriter, error) {
 	switch u.Scheme {
@@ -352,11 +450,20 @@ type chanWriter struct {
 	pointsWritten *int64
 	failures      *int64
 	logger        *zap.Logger
+
+	// queue holds the writes until they are delivered if it is not nil.
+	queue *queue
+
+	// filter selects the points written to the subscription.
+	filter *subscriptionFilter
 }
 
 // Close closes the chanWriter.
 func (c chanWriter) Close() {
 	close(c.writeRequests)
+	if c.queue != nil {
+		c.queue.close()
+	}
 }
 
 func (c chanWriter) Run() {
@@ -371,6 +478,58 @@ func (c chanWriter) Run() {
 	}
 }
 
+// deliver writes the queued points to the PointsWriter in order until the
+// queue is closed. A write that fails is retried, waiting twice as long after
+// each failure up to maxRetry.
+func (c chanWriter) deliver(se subEntry, retry, maxRetry time.Duration) {
+	backoff := retry
+	for {
+		points, err := c.queue.peek()
+		if err == errQueueEmpty {
+			select {
+			case <-c.queue.notify:
+				continue
+			case <-c.queue.closing:
+				return
+			}
+		} else if err == errQueueClosed {
+			return
+		} else if err != nil {
+			c.logger.Info("Failed to read subscription queue", zap.String("name", se.name), zap.Error(err))
+			select {
+			case <-time.After(backoff):
+				continue
+			case <-c.queue.closing:
+				return
+			}
+		}
+
+		wr := &coordinator.WritePointsRequest{Database: se.db, RetentionPolicy: se.rp, Points: points}
+		if err := c.pw.WritePoints(wr); err != nil {
+			c.logger.Info(err.Error())
+			atomic.AddInt64(c.failures, 1)
+
+			select {
+			case <-time.After(backoff):
+			case <-c.queue.closing:
+				return
+			}
+			if backoff *= 2; backoff > maxRetry {
+				backoff = maxRetry
+			}
+			continue
+		}
+		atomic.AddInt64(c.pointsWritten, int64(len(points)))
+		backoff = retry
+
+		if err := c.queue.advance(); err == errQueueClosed {
+			return
+		} else if err != nil {
+			c.logger.Info("Failed to advance subscription queue", zap.String("name", se.name), zap.Error(err))
+		}
+	}
+}
+
 // Statistics returns statistics for periodic monitoring.
 func (c chanWriter) Statistics(tags map[string]string) []models.Statistic {
 	if m, ok := c.pw.(monitor.Reporter); ok {
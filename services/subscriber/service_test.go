@@ -1,7 +1,11 @@
 package subscriber_test
 
 import (
+	"context"
+	"fmt"
+	"io/ioutil"
 	"net/url"
+	"os"
 	"testing"
 	"time"
 
@@ -67,7 +71,7 @@ func TestService_IgnoreNonMatch(t *testing.T) {
 		return sub, nil
 	}
 
-	s := subscriber.NewService(subscriber.NewConfig())
+	s := NewTestService()
 	s.MetaClient = ms
 	s.NewPointsWriter = newPointsWriter
 	s.Open()
@@ -142,7 +146,7 @@ func TestService_ModeALL(t *testing.T) {
 		return sub, nil
 	}
 
-	s := subscriber.NewService(subscriber.NewConfig())
+	s := NewTestService()
 	s.MetaClient = ms
 	s.NewPointsWriter = newPointsWriter
 	s.Open()
@@ -220,7 +224,7 @@ func TestService_ModeANY(t *testing.T) {
 		return sub, nil
 	}
 
-	s := subscriber.NewService(subscriber.NewConfig())
+	s := NewTestService()
 	s.MetaClient = ms
 	s.NewPointsWriter = newPointsWriter
 	s.Open()
@@ -308,7 +312,7 @@ func TestService_Multiple(t *testing.T) {
 		return sub, nil
 	}
 
-	s := subscriber.NewService(subscriber.NewConfig())
+	s := NewTestService()
 	s.MetaClient = ms
 	s.NewPointsWriter = newPointsWriter
 	s.Open()
@@ -398,7 +402,7 @@ func TestService_WaitForDataChanged(t *testing.T) {
 		return nil
 	}
 
-	s := subscriber.NewService(subscriber.NewConfig())
+	s := NewTestService()
 	s.MetaClient = ms
 	// Explicitly closed below for testing
 	s.Open()
@@ -443,3 +447,114 @@ func TestService_WaitForDataChanged(t *testing.T) {
 
 	close(dataChanged)
 }
+
+// Ensures queued subscriptions receive every write, even when there are more
+// writes than a subscription buffers while its destination is down.
+func TestService_WritePoints_Queue(t *testing.T) {
+	dir, err := ioutil.TempDir("", "subscriber_queue")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	dataChanged := make(chan struct{})
+	ms := MetaClient{}
+	ms.WaitForDataChangedFn = func() chan struct{} {
+		return dataChanged
+	}
+	ms.DatabasesFn = func() []meta.DatabaseInfo {
+		return []meta.DatabaseInfo{
+			{
+				Name: "db0",
+				RetentionPolicies: []meta.RetentionPolicyInfo{
+					{
+						Name: "rp0",
+						Subscriptions: []meta.SubscriptionInfo{
+							{Name: "s0", Mode: "ANY", Destinations: []string{"udp://h0:9093"}},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	const n = 300
+	release := make(chan struct{})
+	prs := make(chan *coordinator.WritePointsRequest, n)
+	created := make(chan struct{})
+	newPointsWriter := func(u url.URL) (subscriber.PointsWriter, error) {
+		defer close(created)
+		sub := Subscription{}
+		sub.WritePointsFn = func(p *coordinator.WritePointsRequest) error {
+			<-release
+			prs <- p
+			return nil
+		}
+		return sub, nil
+	}
+
+	c := subscriber.NewConfig()
+	c.QueueEnabled = true
+	c.QueueDir = dir
+	s := &TestService{Service: subscriber.NewService(c)}
+	s.MetaClient = ms
+	s.NewPointsWriter = newPointsWriter
+	s.Open()
+	defer s.Close()
+
+	select {
+	case <-created:
+	case <-time.After(testTimeout):
+		t.Fatal("expected subscription")
+	}
+
+	for i := 0; i < n; i++ {
+		pr := &coordinator.WritePointsRequest{Database: "db0", RetentionPolicy: "rp0"}
+		pr.AddPoint("cpu", float64(i), time.Unix(0, 1), nil)
+		if err := s.WritePoints(pr); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if depth, _, ok := s.QueueStatus("db0", "rp0", "s0"); !ok || depth < n-1 {
+		t.Fatalf("unexpected queue depth: %d", depth)
+	}
+
+	close(release)
+	for i := 0; i < n; i++ {
+		select {
+		case pr := <-prs:
+			if v := pr.Points[0].String(); v != fmt.Sprintf("cpu value=%d 1", i) {
+				t.Fatalf("unexpected point %d: %s", i, v)
+			}
+		case <-time.After(testTimeout):
+			t.Fatalf("expected write %d", i)
+		}
+	}
+}
+
+// TestService runs a subscriber service until it is closed.
+type TestService struct {
+	*subscriber.Service
+
+	cancel context.CancelFunc
+	done   chan error
+}
+
+// NewTestService returns a new *TestService with the default configuration.
+func NewTestService() *TestService {
+	return &TestService{Service: subscriber.NewService(subscriber.NewConfig())}
+}
+
+// Open starts the service in the background.
+func (s *TestService) Open() {
+	var ctx context.Context
+	ctx, s.cancel = context.WithCancel(context.Background())
+	s.done = make(chan error, 1)
+	go func() { s.done <- s.Service.Start(ctx) }()
+}
+
+// Close stops the service and waits for it to exit.
+func (s *TestService) Close() error {
+	s.cancel()
+	return <-s.done
+}
@@ -0,0 +1,214 @@
+package wire
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/ayang64/reflux/query"
+)
+
+// ClientConfig is used to configure a Client.
+type ClientConfig struct {
+	// Addr is the address of the wire service, for example "localhost:9999".
+	Addr string
+
+	// Username and Password are sent in the handshake when the server
+	// requires authentication.
+	Username string
+	Password string
+
+	// Timeout bounds how long it may take to connect and complete the
+	// handshake. Zero means no timeout.
+	Timeout time.Duration
+
+	// MaxFrameSize is the largest frame the client accepts from the server.
+	// Zero means no limit.
+	MaxFrameSize int
+}
+
+// Client executes queries against a wire service. A Client holds a single
+// connection and executes one query at a time; it is safe for concurrent use.
+type Client struct {
+	config ClientConfig
+	conn   net.Conn
+	r      *bufio.Reader
+
+	mu  sync.Mutex // serializes queries
+	wmu sync.Mutex // serializes frames written to conn
+	w   *bufio.Writer
+}
+
+// NewClient connects to the wire service described by conf and performs the
+// handshake.
+func NewClient(conf ClientConfig) (*Client, error) {
+	conn, err := net.DialTimeout("tcp", conf.Addr, conf.Timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Client{
+		config: conf,
+		conn:   conn,
+		r:      bufio.NewReader(conn),
+		w:      bufio.NewWriter(conn),
+	}
+
+	if conf.Timeout > 0 {
+		conn.SetDeadline(time.Now().Add(conf.Timeout))
+	}
+	if err := c.handshake(); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	conn.SetDeadline(time.Time{})
+
+	return c, nil
+}
+
+// Close closes the connection to the server.
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+func (c *Client) handshake() error {
+	buf, err := (&Handshake{
+		Version:  Version,
+		Username: c.config.Username,
+		Password: c.config.Password,
+	}).MarshalBinary()
+	if err != nil {
+		return err
+	}
+	if err := c.writeFrame(FrameHandshake, buf); err != nil {
+		return err
+	}
+
+	typ, payload, err := ReadFrame(c.r, c.config.MaxFrameSize)
+	if err != nil {
+		return err
+	}
+	switch typ {
+	case FrameReady:
+		return nil
+	case FrameError:
+		return errors.New(string(payload))
+	default:
+		return fmt.Errorf("wire: unexpected %s frame during handshake", typ)
+	}
+}
+
+// Query executes q and returns every result. The results for each statement
+// are returned in the order they were received; chunked results are not
+// merged.
+func (c *Client) Query(ctx context.Context, q Query) ([]*query.Result, error) {
+	var results []*query.Result
+	if err := c.QueryStream(ctx, q, func(r *query.Result) error {
+		results = append(results, r)
+		return nil
+	}); err != nil {
+		return results, err
+	}
+	return results, nil
+}
+
+// QueryStream executes q and calls fn for every result as it arrives. If fn
+// returns an error or ctx is canceled, the query is aborted on the server and
+// that error is returned.
+func (c *Client) QueryStream(ctx context.Context, q Query, fn func(*query.Result) error) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	buf, err := q.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	if err := c.writeFrame(FrameQuery, buf); err != nil {
+		return err
+	}
+
+	// Abort the query on the server if the context is canceled before the
+	// query is done. The abort is not sent once the query is done, and the
+	// goroutine sending it exits before the next query can be sent, so that
+	// it is never taken for an abort of the next query.
+	var amu sync.Mutex
+	var aborted, done bool
+	abort := func() {
+		amu.Lock()
+		defer amu.Unlock()
+		if !aborted && !done {
+			aborted = true
+			c.writeFrame(FrameAbort, nil)
+		}
+	}
+	finish := func() {
+		amu.Lock()
+		done = true
+		amu.Unlock()
+	}
+	stop, exited := make(chan struct{}), make(chan struct{})
+	go func() {
+		defer close(exited)
+		select {
+		case <-ctx.Done():
+			abort()
+		case <-stop:
+		}
+	}()
+	defer func() {
+		finish()
+		close(stop)
+		<-exited
+	}()
+
+	var qerr error
+	for {
+		typ, payload, err := ReadFrame(c.r, c.config.MaxFrameSize)
+		if err != nil {
+			if qerr != nil {
+				return qerr
+			}
+			return err
+		}
+
+		switch typ {
+		case FrameResult:
+			if qerr != nil {
+				continue
+			}
+			r, err := UnmarshalResult(payload)
+			if err != nil {
+				return err
+			}
+			if err := fn(r); err != nil {
+				qerr = err
+				abort()
+			}
+		case FrameError:
+			if qerr == nil {
+				qerr = errors.New(string(payload))
+			}
+		case FrameDone:
+			finish()
+			if qerr == nil {
+				qerr = ctx.Err()
+			}
+			return qerr
+		default:
+			return fmt.Errorf("wire: unexpected %s frame", typ)
+		}
+	}
+}
+
+func (c *Client) writeFrame(typ FrameType, payload []byte) error {
+	c.wmu.Lock()
+	defer c.wmu.Unlock()
+	if err := WriteFrame(c.w, typ, payload); err != nil {
+		return err
+	}
+	return c.w.Flush()
+}
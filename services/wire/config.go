@@ -0,0 +1,78 @@
+package wire
+
+import (
+	"errors"
+
+	"github.com/ayang64/reflux/monitor/diagnostics"
+	"github.com/ayang64/reflux/toml"
+)
+
+const (
+	// DefaultBindAddress is the default address the wire service binds to.
+	DefaultBindAddress = ":9999"
+
+	// DefaultChunkSize is the number of rows sent in each result frame when
+	// the client does not request a chunk size.
+	DefaultChunkSize = 10000
+
+	// DefaultMaxFrameSize is the largest frame a client may send.
+	DefaultMaxFrameSize = 16 * 1024 * 1024
+)
+
+// Config represents the configuration for the wire query service.
+type Config struct {
+	Enabled            bool      `toml:"enabled"`
+	BindAddress        string    `toml:"bind-address"`
+	AuthEnabled        bool      `toml:"auth-enabled"`
+	MaxConnectionLimit int       `toml:"max-connection-limit"`
+	MaxFrameSize       toml.Size `toml:"max-frame-size"`
+	ChunkSize          int       `toml:"chunk-size"`
+}
+
+// NewConfig returns a new Config with defaults.
+func NewConfig() Config {
+	return Config{
+		BindAddress:  DefaultBindAddress,
+		MaxFrameSize: toml.Size(DefaultMaxFrameSize),
+		ChunkSize:    DefaultChunkSize,
+	}
+}
+
+// Validate returns an error if the Config is invalid.
+func (c Config) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+
+	if c.BindAddress == "" {
+		return errors.New("bind-address must be specified")
+	}
+	if c.MaxConnectionLimit < 0 {
+		return errors.New("max-connection-limit cannot be negative")
+	}
+	if c.MaxFrameSize == 0 {
+		return errors.New("max-frame-size must be positive")
+	}
+	if c.ChunkSize < 0 {
+		return errors.New("chunk-size cannot be negative")
+	}
+	return nil
+}
+
+// Diagnostics returns a diagnostics representation of a subset of the Config.
+func (c Config) Diagnostics() (*diagnostics.Diagnostics, error) {
+	if !c.Enabled {
+		return diagnostics.RowFromMap(map[string]interface{}{
+			"enabled": false,
+		}), nil
+	}
+
+	return diagnostics.RowFromMap(map[string]interface{}{
+		"enabled":              true,
+		"bind-address":         c.BindAddress,
+		"auth-enabled":         c.AuthEnabled,
+		"max-connection-limit": c.MaxConnectionLimit,
+		"max-frame-size":       c.MaxFrameSize,
+		"chunk-size":           c.ChunkSize,
+	}), nil
+}
@@ -0,0 +1,65 @@
+package wire_test
+
+import (
+	"testing"
+
+	"github.com/BurntSushi/toml"
+	"github.com/ayang64/reflux/services/wire"
+)
+
+func TestConfig_Parse(t *testing.T) {
+	// Parse configuration.
+	c := wire.NewConfig()
+	if _, err := toml.Decode(`
+enabled = true
+bind-address = ":8087"
+auth-enabled = true
+max-connection-limit = 10
+max-frame-size = "1m"
+chunk-size = 500
+`, &c); err != nil {
+		t.Fatal(err)
+	}
+
+	// Validate configuration.
+	if !c.Enabled {
+		t.Fatalf("unexpected enabled: %v", c.Enabled)
+	} else if c.BindAddress != ":8087" {
+		t.Fatalf("unexpected bind address: %s", c.BindAddress)
+	} else if !c.AuthEnabled {
+		t.Fatalf("unexpected auth enabled: %v", c.AuthEnabled)
+	} else if c.MaxConnectionLimit != 10 {
+		t.Fatalf("unexpected max connection limit: %d", c.MaxConnectionLimit)
+	} else if c.MaxFrameSize != 1<<20 {
+		t.Fatalf("unexpected max frame size: %d", c.MaxFrameSize)
+	} else if c.ChunkSize != 500 {
+		t.Fatalf("unexpected chunk size: %d", c.ChunkSize)
+	}
+}
+
+func TestConfig_Validate(t *testing.T) {
+	c := wire.NewConfig()
+	c.Enabled = true
+	if err := c.Validate(); err != nil {
+		t.Fatalf("unexpected validation fail from NewConfig: %s", err)
+	}
+
+	c = wire.NewConfig()
+	c.Enabled = true
+	c.BindAddress = ""
+	if err := c.Validate(); err == nil {
+		t.Fatal("expected error for empty bind-address, got nil")
+	}
+
+	c = wire.NewConfig()
+	c.Enabled = true
+	c.MaxFrameSize = 0
+	if err := c.Validate(); err == nil {
+		t.Fatal("expected error for max-frame-size = 0, got nil")
+	}
+
+	c.Enabled = false
+	if err := c.Validate(); err != nil {
+		t.Fatalf("unexpected validation fail from disabled config: %s", err)
+	}
+}
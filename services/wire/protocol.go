@@ -0,0 +1,521 @@
+package wire
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/ayang64/reflux/models"
+	"github.com/ayang64/reflux/query"
+)
+
+// Version is the version of the wire protocol spoken by this package. It is
+// sent by the client in the initial handshake.
+const Version = 1
+
+// FrameType identifies the contents of a frame.
+type FrameType uint8
+
+// Frames sent from the client to the server.
+const (
+	// FrameHandshake opens a connection and carries the protocol version and
+	// the credentials of the user.
+	FrameHandshake FrameType = iota + 1
+
+	// FrameQuery submits an InfluxQL query for execution.
+	FrameQuery
+
+	// FrameAbort stops the query that is currently executing.
+	FrameAbort
+)
+
+// Frames sent from the server to the client.
+const (
+	// FrameReady acknowledges a successful handshake.
+	FrameReady FrameType = iota + 0x10
+
+	// FrameResult carries a single query.Result.
+	FrameResult
+
+	// FrameDone marks the end of the results for a query.
+	FrameDone
+
+	// FrameError carries an error that is not associated with a statement,
+	// such as a failed handshake or a query that could not be parsed.
+	FrameError
+)
+
+func (t FrameType) String() string {
+	switch t {
+	case FrameHandshake:
+		return "handshake"
+	case FrameQuery:
+		return "query"
+	case FrameAbort:
+		return "abort"
+	case FrameReady:
+		return "ready"
+	case FrameResult:
+		return "result"
+	case FrameDone:
+		return "done"
+	case FrameError:
+		return "error"
+	default:
+		return fmt.Sprintf("unknown(%d)", uint8(t))
+	}
+}
+
+// frameHeaderSize is the size of the type and length prefix of each frame.
+const frameHeaderSize = 5
+
+// ErrFrameTooLarge is returned when a frame exceeds the maximum allowed size.
+var ErrFrameTooLarge = errors.New("wire: frame too large")
+
+// ReadFrame reads a single frame from r. Frames larger than max bytes are
+// rejected; a max of zero places no limit on the frame size.
+func ReadFrame(r io.Reader, max int) (FrameType, []byte, error) {
+	var hdr [frameHeaderSize]byte
+	if _, err := io.ReadFull(r, hdr[:]); err != nil {
+		return 0, nil, err
+	}
+
+	sz := binary.BigEndian.Uint32(hdr[1:])
+	if max > 0 && int64(sz) > int64(max) {
+		return 0, nil, ErrFrameTooLarge
+	}
+
+	buf := make([]byte, sz)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return 0, nil, err
+	}
+	return FrameType(hdr[0]), buf, nil
+}
+
+// WriteFrame writes a single frame with the given type and payload to w.
+func WriteFrame(w io.Writer, typ FrameType, payload []byte) error {
+	if uint64(len(payload)) > math.MaxUint32 {
+		return ErrFrameTooLarge
+	}
+
+	var hdr [frameHeaderSize]byte
+	hdr[0] = byte(typ)
+	binary.BigEndian.PutUint32(hdr[1:], uint32(len(payload)))
+	if _, err := w.Write(hdr[:]); err != nil {
+		return err
+	}
+	_, err := w.Write(payload)
+	return err
+}
+
+// Handshake is the first frame sent by a client.
+type Handshake struct {
+	Version  uint8
+	Username string
+	Password string
+}
+
+// MarshalBinary encodes the handshake.
+func (h *Handshake) MarshalBinary() ([]byte, error) {
+	var e encoder
+	e.buf = append(e.buf, h.Version)
+	e.string(h.Username)
+	e.string(h.Password)
+	return e.buf, nil
+}
+
+// UnmarshalBinary decodes the handshake.
+func (h *Handshake) UnmarshalBinary(data []byte) error {
+	d := decoder{buf: data}
+	h.Version = d.byte()
+	h.Username = d.string()
+	h.Password = d.string()
+	return d.finish()
+}
+
+// Query is a request to execute an InfluxQL query.
+type Query struct {
+	// Command is the InfluxQL query text.
+	Command string
+
+	// Database and RetentionPolicy are the defaults for the query.
+	Database        string
+	RetentionPolicy string
+
+	// ChunkSize is the maximum number of rows in each result frame.
+	// The server default is used when it is zero.
+	ChunkSize int
+
+	// Params are bound to the query's parameter placeholders.
+	Params map[string]interface{}
+}
+
+// MarshalBinary encodes the query.
+func (q *Query) MarshalBinary() ([]byte, error) {
+	var e encoder
+	e.string(q.Command)
+	e.string(q.Database)
+	e.string(q.RetentionPolicy)
+	e.uvarint(uint64(q.ChunkSize))
+
+	keys := make([]string, 0, len(q.Params))
+	for k := range q.Params {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	e.uvarint(uint64(len(keys)))
+	for _, k := range keys {
+		e.string(k)
+		e.value(q.Params[k])
+	}
+	return e.buf, nil
+}
+
+// UnmarshalBinary decodes the query.
+func (q *Query) UnmarshalBinary(data []byte) error {
+	d := decoder{buf: data}
+	q.Command = d.string()
+	q.Database = d.string()
+	q.RetentionPolicy = d.string()
+	q.ChunkSize = int(d.uvarint())
+
+	n := d.count()
+	if n > 0 {
+		q.Params = make(map[string]interface{}, n)
+	}
+	for i := 0; i < n && d.err == nil; i++ {
+		k := d.string()
+		q.Params[k] = d.value()
+	}
+	return d.finish()
+}
+
+// result flags.
+const (
+	resultPartial = 1 << iota
+	resultError
+)
+
+// MarshalResult encodes r in the binary format used by result frames.
+func MarshalResult(r *query.Result) ([]byte, error) {
+	var e encoder
+	e.uvarint(uint64(r.StatementID))
+
+	var flags byte
+	if r.Partial {
+		flags |= resultPartial
+	}
+	if r.Err != nil {
+		flags |= resultError
+	}
+	e.buf = append(e.buf, flags)
+	if r.Err != nil {
+		e.string(r.Err.Error())
+	}
+
+	e.uvarint(uint64(len(r.Messages)))
+	for _, m := range r.Messages {
+		e.string(m.Level)
+		e.string(m.Text)
+	}
+
+	e.uvarint(uint64(len(r.Series)))
+	for _, row := range r.Series {
+		e.row(row)
+	}
+	return e.buf, nil
+}
+
+// UnmarshalResult decodes a result encoded with MarshalResult.
+func UnmarshalResult(data []byte) (*query.Result, error) {
+	d := decoder{buf: data}
+	r := &query.Result{StatementID: int(d.uvarint())}
+
+	flags := d.byte()
+	r.Partial = flags&resultPartial != 0
+	if flags&resultError != 0 {
+		r.Err = errors.New(d.string())
+	}
+
+	if n := d.count(); n > 0 {
+		r.Messages = make([]*query.Message, n)
+		for i := range r.Messages {
+			r.Messages[i] = &query.Message{Level: d.string(), Text: d.string()}
+		}
+	}
+
+	if n := d.count(); n > 0 {
+		r.Series = make(models.Rows, n)
+		for i := range r.Series {
+			r.Series[i] = d.row()
+		}
+	}
+
+	if err := d.finish(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Value types used when encoding row values and query parameters.
+const (
+	valueNil byte = iota
+	valueFloat
+	valueInteger
+	valueUnsigned
+	valueString
+	valueBoolean
+	valueTime
+)
+
+// encoder appends the primitive types of the protocol to a buffer.
+type encoder struct {
+	buf     []byte
+	scratch [binary.MaxVarintLen64]byte
+}
+
+func (e *encoder) uvarint(v uint64) {
+	n := binary.PutUvarint(e.scratch[:], v)
+	e.buf = append(e.buf, e.scratch[:n]...)
+}
+
+func (e *encoder) varint(v int64) {
+	n := binary.PutVarint(e.scratch[:], v)
+	e.buf = append(e.buf, e.scratch[:n]...)
+}
+
+func (e *encoder) string(s string) {
+	e.uvarint(uint64(len(s)))
+	e.buf = append(e.buf, s...)
+}
+
+func (e *encoder) row(row *models.Row) {
+	e.string(row.Name)
+
+	keys := make([]string, 0, len(row.Tags))
+	for k := range row.Tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	e.uvarint(uint64(len(keys)))
+	for _, k := range keys {
+		e.string(k)
+		e.string(row.Tags[k])
+	}
+
+	e.uvarint(uint64(len(row.Columns)))
+	for _, c := range row.Columns {
+		e.string(c)
+	}
+
+	e.uvarint(uint64(len(row.Values)))
+	for _, values := range row.Values {
+		e.uvarint(uint64(len(values)))
+		for _, v := range values {
+			e.value(v)
+		}
+	}
+
+	if row.Partial {
+		e.buf = append(e.buf, 1)
+	} else {
+		e.buf = append(e.buf, 0)
+	}
+}
+
+func (e *encoder) value(v interface{}) {
+	switch v := v.(type) {
+	case nil:
+		e.buf = append(e.buf, valueNil)
+	case float64:
+		e.buf = append(e.buf, valueFloat)
+		binary.BigEndian.PutUint64(e.scratch[:8], math.Float64bits(v))
+		e.buf = append(e.buf, e.scratch[:8]...)
+	case float32:
+		e.value(float64(v))
+	case int64:
+		e.buf = append(e.buf, valueInteger)
+		e.varint(v)
+	case int:
+		e.value(int64(v))
+	case int32:
+		e.value(int64(v))
+	case uint64:
+		e.buf = append(e.buf, valueUnsigned)
+		e.uvarint(v)
+	case uint:
+		e.value(uint64(v))
+	case uint32:
+		e.value(uint64(v))
+	case string:
+		e.buf = append(e.buf, valueString)
+		e.string(v)
+	case bool:
+		e.buf = append(e.buf, valueBoolean)
+		if v {
+			e.buf = append(e.buf, 1)
+		} else {
+			e.buf = append(e.buf, 0)
+		}
+	case time.Time:
+		e.buf = append(e.buf, valueTime)
+		e.varint(v.UnixNano())
+	case fmt.Stringer:
+		e.value(v.String())
+	default:
+		e.value(fmt.Sprint(v))
+	}
+}
+
+// errShortBuffer is returned when a payload ends before it has been fully
+// decoded.
+var errShortBuffer = errors.New("wire: short buffer")
+
+// decoder reads the primitive types of the protocol from a buffer. The first
+// error encountered is sticky and every subsequent read returns a zero value.
+type decoder struct {
+	buf []byte
+	err error
+}
+
+func (d *decoder) finish() error {
+	if d.err == nil && len(d.buf) > 0 {
+		d.err = fmt.Errorf("wire: %d unexpected trailing bytes", len(d.buf))
+	}
+	return d.err
+}
+
+func (d *decoder) byte() byte {
+	if d.err != nil {
+		return 0
+	}
+	if len(d.buf) < 1 {
+		d.err = errShortBuffer
+		return 0
+	}
+	b := d.buf[0]
+	d.buf = d.buf[1:]
+	return b
+}
+
+func (d *decoder) uvarint() uint64 {
+	if d.err != nil {
+		return 0
+	}
+	v, n := binary.Uvarint(d.buf)
+	if n <= 0 {
+		d.err = errShortBuffer
+		return 0
+	}
+	d.buf = d.buf[n:]
+	return v
+}
+
+func (d *decoder) varint() int64 {
+	if d.err != nil {
+		return 0
+	}
+	v, n := binary.Varint(d.buf)
+	if n <= 0 {
+		d.err = errShortBuffer
+		return 0
+	}
+	d.buf = d.buf[n:]
+	return v
+}
+
+// count reads a length prefix and verifies that it could possibly be
+// satisfied by the remaining bytes, so a corrupt frame cannot cause a large
+// allocation.
+func (d *decoder) count() int {
+	n := d.uvarint()
+	if d.err == nil && n > uint64(len(d.buf)) {
+		d.err = errShortBuffer
+		return 0
+	}
+	return int(n)
+}
+
+func (d *decoder) string() string {
+	n := d.count()
+	if d.err != nil {
+		return ""
+	}
+	s := string(d.buf[:n])
+	d.buf = d.buf[n:]
+	return s
+}
+
+func (d *decoder) row() *models.Row {
+	row := &models.Row{Name: d.string()}
+
+	if n := d.count(); n > 0 {
+		row.Tags = make(map[string]string, n)
+		for i := 0; i < n && d.err == nil; i++ {
+			k := d.string()
+			row.Tags[k] = d.string()
+		}
+	}
+
+	if n := d.count(); n > 0 {
+		row.Columns = make([]string, n)
+		for i := range row.Columns {
+			row.Columns[i] = d.string()
+		}
+	}
+
+	if n := d.count(); n > 0 {
+		row.Values = make([][]interface{}, n)
+		for i := 0; i < n && d.err == nil; i++ {
+			values := make([]interface{}, d.count())
+			for j := range values {
+				values[j] = d.value()
+			}
+			row.Values[i] = values
+		}
+	}
+
+	row.Partial = d.byte() != 0
+	return row
+}
+
+func (d *decoder) value() interface{} {
+	switch typ := d.byte(); typ {
+	case valueNil:
+		return nil
+	case valueFloat:
+		if d.err != nil {
+			return nil
+		}
+		if len(d.buf) < 8 {
+			d.err = errShortBuffer
+			return nil
+		}
+		v := math.Float64frombits(binary.BigEndian.Uint64(d.buf))
+		d.buf = d.buf[8:]
+		return v
+	case valueInteger:
+		return d.varint()
+	case valueUnsigned:
+		return d.uvarint()
+	case valueString:
+		return d.string()
+	case valueBoolean:
+		return d.byte() != 0
+	case valueTime:
+		return time.Unix(0, d.varint()).UTC()
+	default:
+		if d.err == nil {
+			d.err = fmt.Errorf("wire: unknown value type %d", typ)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,485 @@
+// Package wire implements a framed binary protocol for executing InfluxQL
+// queries and streaming their results without the overhead of the HTTP and
+// JSON encoding used by the /query endpoint.
+//
+// Every frame consists of a one byte FrameType, a four byte big-endian payload
+// length and the payload itself. A client opens a connection with a
+// FrameHandshake. It may then send any number of FrameQuery frames, one at a
+// time; each is answered by zero or more FrameResult frames followed by a
+// FrameDone. A FrameAbort sent while a query is running stops it.
+package wire // import "github.com/ayang64/reflux/services/wire"
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ayang64/reflux/influxql"
+	"github.com/ayang64/reflux/logger"
+	"github.com/ayang64/reflux/models"
+	"github.com/ayang64/reflux/query"
+	"github.com/ayang64/reflux/services/audit"
+	"github.com/ayang64/reflux/services/meta"
+	"go.uber.org/zap"
+)
+
+// statistics gathered by the wire service.
+const (
+	statConnectionsActive      = "connectionsActive"
+	statConnectionsHandled     = "connectionsHandled"
+	statConnectionsRejected    = "connectionsRejected"
+	statAuthenticationFailures = "authFail"
+	statQueryRequests          = "queryReq"
+	statQueryRequestErrors     = "queryReqErr"
+	statQueryRequestDuration   = "queryReqDurationNs"
+	statFramesTransmitted      = "framesTx"
+	statBytesTransmitted       = "bytesTx"
+)
+
+// Service serves InfluxQL queries over the wire protocol.
+type Service struct {
+	config Config
+
+	ln     net.Listener
+	ready  chan struct{}
+	wg     sync.WaitGroup
+	connMu sync.Mutex
+	conns  map[net.Conn]struct{}
+
+	MetaClient interface {
+		AuthenticateFrom(username, password, source string) (meta.User, error)
+		AdminUserExists() bool
+	}
+
+	QueryAuthorizer interface {
+		AuthorizeQuery(u meta.User, query *influxql.Query, database string) error
+	}
+
+	QueryExecutor *query.Executor
+
+	// AuditLog records the statements of queries denied by QueryAuthorizer.
+	AuditLog audit.Recorder
+
+	Logger      *zap.Logger
+	stats       *Statistics
+	defaultTags models.StatisticTags
+}
+
+// NewService returns a new instance of Service.
+func NewService(c Config) *Service {
+	if c.ChunkSize == 0 {
+		c.ChunkSize = DefaultChunkSize
+	}
+	return &Service{
+		config:      c,
+		ready:       make(chan struct{}),
+		conns:       make(map[net.Conn]struct{}),
+		Logger:      zap.NewNop(),
+		stats:       &Statistics{},
+		defaultTags: models.StatisticTags{"bind": c.BindAddress},
+	}
+}
+
+// Start opens the listener and serves connections until ctx is canceled.
+func (s *Service) Start(ctx context.Context) error {
+	s.Logger.Info("Starting wire service", zap.Bool("authentication", s.config.AuthEnabled))
+
+	ln, err := net.Listen("tcp", s.config.BindAddress)
+	if err != nil {
+		return err
+	}
+	s.connMu.Lock()
+	s.ln = ln
+	s.connMu.Unlock()
+
+	s.Logger.Info("Listening on wire protocol", zap.Stringer("addr", ln.Addr()))
+	close(s.ready)
+
+	go func() {
+		<-ctx.Done()
+		ln.Close()
+	}()
+
+	err = s.serve(ctx, ln)
+
+	// Close any open connections so their handlers return, then wait for them.
+	s.connMu.Lock()
+	for conn := range s.conns {
+		conn.Close()
+	}
+	s.connMu.Unlock()
+	s.wg.Wait()
+
+	return err
+}
+
+// WithLogger sets the logger on the service.
+func (s *Service) WithLogger(log *zap.Logger) {
+	s.Logger = log.With(zap.String("service", "wire"))
+}
+
+// Ready returns a channel that is closed once the service is listening.
+func (s *Service) Ready() <-chan struct{} { return s.ready }
+
+// Addr returns the listener's address. Returns nil if the service is not
+// listening.
+func (s *Service) Addr() net.Addr {
+	s.connMu.Lock()
+	defer s.connMu.Unlock()
+	if s.ln != nil {
+		return s.ln.Addr()
+	}
+	return nil
+}
+
+// Statistics maintains statistics for the wire service.
+type Statistics struct {
+	ConnectionsActive      int64
+	ConnectionsHandled     int64
+	ConnectionsRejected    int64
+	AuthenticationFailures int64
+	QueryRequests          int64
+	QueryRequestErrors     int64
+	QueryRequestDuration   int64
+	FramesTransmitted      int64
+	BytesTransmitted       int64
+}
+
+// Statistics returns statistics for periodic monitoring.
+func (s *Service) Statistics(tags map[string]string) []models.Statistic {
+	return []models.Statistic{{
+		Name: "wire",
+		Tags: s.defaultTags.Merge(tags),
+		Values: map[string]interface{}{
+			statConnectionsActive:      atomic.LoadInt64(&s.stats.ConnectionsActive),
+			statConnectionsHandled:     atomic.LoadInt64(&s.stats.ConnectionsHandled),
+			statConnectionsRejected:    atomic.LoadInt64(&s.stats.ConnectionsRejected),
+			statAuthenticationFailures: atomic.LoadInt64(&s.stats.AuthenticationFailures),
+			statQueryRequests:          atomic.LoadInt64(&s.stats.QueryRequests),
+			statQueryRequestErrors:     atomic.LoadInt64(&s.stats.QueryRequestErrors),
+			statQueryRequestDuration:   atomic.LoadInt64(&s.stats.QueryRequestDuration),
+			statFramesTransmitted:      atomic.LoadInt64(&s.stats.FramesTransmitted),
+			statBytesTransmitted:       atomic.LoadInt64(&s.stats.BytesTransmitted),
+		},
+	}}
+}
+
+// serve accepts connections from ln until it is closed.
+func (s *Service) serve(ctx context.Context, ln net.Listener) error {
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			} else if strings.Contains(err.Error(), "use of closed network connection") {
+				return err
+			}
+			s.Logger.Info("Error accepting wire connection", zap.Error(err))
+			continue
+		}
+
+		if !s.track(conn) {
+			atomic.AddInt64(&s.stats.ConnectionsRejected, 1)
+			WriteFrame(conn, FrameError, []byte("too many connections"))
+			conn.Close()
+			continue
+		}
+
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			defer s.untrack(conn)
+
+			atomic.AddInt64(&s.stats.ConnectionsHandled, 1)
+			if err := s.handleConn(ctx, conn); err != nil {
+				s.Logger.Info("Wire connection closed with error",
+					zap.Stringer("remote_addr", conn.RemoteAddr()), zap.Error(err))
+			}
+		}()
+	}
+}
+
+// track records an open connection. It returns false if the connection limit
+// has been reached.
+func (s *Service) track(conn net.Conn) bool {
+	s.connMu.Lock()
+	defer s.connMu.Unlock()
+	if s.config.MaxConnectionLimit > 0 && len(s.conns) >= s.config.MaxConnectionLimit {
+		return false
+	}
+	s.conns[conn] = struct{}{}
+	atomic.AddInt64(&s.stats.ConnectionsActive, 1)
+	return true
+}
+
+func (s *Service) untrack(conn net.Conn) {
+	conn.Close()
+	s.connMu.Lock()
+	delete(s.conns, conn)
+	s.connMu.Unlock()
+	atomic.AddInt64(&s.stats.ConnectionsActive, -1)
+}
+
+// frame is a frame read from a client connection.
+type frame struct {
+	typ     FrameType
+	payload []byte
+}
+
+// conn holds the state of a single client connection.
+type conn struct {
+	s    *Service
+	w    *bufio.Writer
+	user meta.User
+	addr string
+
+	// frames receives every frame read from the client. It is closed when
+	// the client disconnects or sends a frame that cannot be read, in which
+	// case err holds the reason.
+	frames chan frame
+	err    error
+}
+
+// handleConn performs the handshake and then executes queries sent by the
+// client until it disconnects.
+func (s *Service) handleConn(ctx context.Context, nc net.Conn) error {
+	c := &conn{
+		s:      s,
+		w:      bufio.NewWriter(nc),
+		frames: make(chan frame),
+	}
+	if addr := nc.RemoteAddr(); addr != nil {
+		if host, _, err := net.SplitHostPort(addr.String()); err == nil {
+			c.addr = host
+		}
+	}
+
+	// Read frames in a separate goroutine so a running query can notice an
+	// abort or a disconnect while it is streaming results.
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		defer close(c.frames)
+		r := bufio.NewReader(nc)
+		for {
+			typ, payload, err := ReadFrame(r, int(s.config.MaxFrameSize))
+			if err != nil {
+				if err != io.EOF {
+					c.err = err
+				}
+				return
+			}
+			select {
+			case c.frames <- frame{typ: typ, payload: payload}:
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	if err := c.handshake(); err != nil {
+		return err
+	}
+
+	for {
+		var f frame
+		var ok bool
+		select {
+		case f, ok = <-c.frames:
+		case <-ctx.Done():
+			return nil
+		}
+		if !ok {
+			return c.err
+		}
+
+		switch f.typ {
+		case FrameQuery:
+			var q Query
+			if err := q.UnmarshalBinary(f.payload); err != nil {
+				return c.writeError(fmt.Errorf("invalid query frame: %s", err))
+			}
+			if err := c.executeQuery(ctx, &q); err != nil {
+				return err
+			}
+		case FrameAbort:
+			// The query this abort was meant for has already finished.
+		default:
+			return c.writeError(fmt.Errorf("unexpected %s frame", f.typ))
+		}
+	}
+}
+
+// handshake reads the client's handshake and authenticates the user.
+func (c *conn) handshake() error {
+	f, ok := <-c.frames
+	if !ok {
+		return c.err
+	}
+	if f.typ != FrameHandshake {
+		return c.writeError(fmt.Errorf("expected handshake, got %s frame", f.typ))
+	}
+
+	var h Handshake
+	if err := h.UnmarshalBinary(f.payload); err != nil {
+		return c.writeError(fmt.Errorf("invalid handshake: %s", err))
+	}
+	if h.Version != Version {
+		return c.writeError(fmt.Errorf("unsupported protocol version %d", h.Version))
+	}
+
+	if c.s.config.AuthEnabled && c.s.MetaClient.AdminUserExists() {
+		if h.Username == "" {
+			atomic.AddInt64(&c.s.stats.AuthenticationFailures, 1)
+			return c.writeError(errors.New("username required"))
+		}
+
+		user, err := c.s.MetaClient.AuthenticateFrom(h.Username, h.Password, c.addr)
+		if err != nil {
+			atomic.AddInt64(&c.s.stats.AuthenticationFailures, 1)
+			return c.writeError(errors.New("authorization failed"))
+		}
+		c.user = user
+	}
+
+	return c.writeFrame(FrameReady, nil)
+}
+
+// executeQuery parses, authorizes and executes q, streaming each result to
+// the client. The returned error is only non-nil if the connection is no
+// longer usable.
+func (c *conn) executeQuery(ctx context.Context, q *Query) error {
+	s := c.s
+	atomic.AddInt64(&s.stats.QueryRequests, 1)
+	defer func(start time.Time) {
+		atomic.AddInt64(&s.stats.QueryRequestDuration, time.Since(start).Nanoseconds())
+	}(time.Now())
+
+	p := influxql.NewParser(strings.NewReader(q.Command))
+	p.SetParams(q.Params)
+	parsed, err := p.ParseQuery()
+	if err != nil {
+		atomic.AddInt64(&s.stats.QueryRequestErrors, 1)
+		return c.writeQueryError(fmt.Errorf("error parsing query: %s", err))
+	}
+
+	opts := query.ExecutionOptions{
+		Database:        q.Database,
+		RetentionPolicy: q.RetentionPolicy,
+		ChunkSize:       q.ChunkSize,
+		ClientAddr:      c.addr,
+	}
+	if opts.ChunkSize <= 0 {
+		opts.ChunkSize = s.config.ChunkSize
+	}
+
+	if s.config.AuthEnabled {
+		if err := s.QueryAuthorizer.AuthorizeQuery(c.user, parsed, q.Database); err != nil {
+			if err, ok := err.(meta.ErrAuthorize); ok {
+				s.Logger.Info("Unauthorized request",
+					zap.String("user", err.User),
+					zap.Stringer("query", err.Query),
+					logger.Database(err.Database))
+			}
+			if s.AuditLog != nil {
+				var id string
+				if c.user != nil {
+					id = c.user.ID()
+				}
+				audit.RecordDenied(s.AuditLog, parsed, id, c.addr, q.Database, err)
+			}
+			atomic.AddInt64(&s.stats.QueryRequestErrors, 1)
+			return c.writeQueryError(fmt.Errorf("error authorizing query: %s", err))
+		}
+		// The current user determines the authorized actions.
+		opts.Authorizer = c.user
+	} else {
+		// Auth is disabled, so allow everything.
+		opts.Authorizer = query.OpenAuthorizer
+	}
+
+	// closing signals the executor to kill the query and done tells it that
+	// results are no longer being read.
+	closing := make(chan struct{})
+	var closeOnce sync.Once
+	abort := func() { closeOnce.Do(func() { close(closing) }) }
+	defer abort()
+
+	done := make(chan struct{})
+	defer close(done)
+	opts.AbortCh = done
+
+	results := s.QueryExecutor.ExecuteQueryWithContext(ctx, parsed, opts, closing)
+
+	frames := c.frames
+	for {
+		select {
+		case r, ok := <-results:
+			if !ok {
+				return c.writeFrame(FrameDone, nil)
+			}
+			if r == nil {
+				continue
+			}
+			if r.Err != nil {
+				atomic.AddInt64(&s.stats.QueryRequestErrors, 1)
+			}
+
+			buf, err := MarshalResult(r)
+			if err != nil {
+				return err
+			}
+			if err := c.writeFrame(FrameResult, buf); err != nil {
+				return err
+			}
+		case f, ok := <-frames:
+			if !ok {
+				// The client went away; stop the query.
+				abort()
+				if c.err != nil {
+					return c.err
+				}
+				return io.EOF
+			}
+			if f.typ != FrameAbort {
+				abort()
+				return c.writeError(fmt.Errorf("unexpected %s frame while a query is running", f.typ))
+			}
+			// Keep reading results so the client sees how the query ended.
+			abort()
+			frames = nil
+		}
+	}
+}
+
+// writeQueryError sends an error for a query that could not be started and
+// completes it.
+func (c *conn) writeQueryError(err error) error {
+	if err := c.writeFrame(FrameError, []byte(err.Error())); err != nil {
+		return err
+	}
+	return c.writeFrame(FrameDone, nil)
+}
+
+// writeError sends a fatal error to the client. The returned error is meant
+// to be returned by the connection handler.
+func (c *conn) writeError(err error) error {
+	c.writeFrame(FrameError, []byte(err.Error()))
+	return err
+}
+
+func (c *conn) writeFrame(typ FrameType, payload []byte) error {
+	if err := WriteFrame(c.w, typ, payload); err != nil {
+		return err
+	}
+	atomic.AddInt64(&c.s.stats.FramesTransmitted, 1)
+	atomic.AddInt64(&c.s.stats.BytesTransmitted, int64(frameHeaderSize+len(payload)))
+	return c.w.Flush()
+}
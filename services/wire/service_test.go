@@ -0,0 +1,367 @@
+package wire_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ayang64/reflux/influxql"
+	"github.com/ayang64/reflux/models"
+	"github.com/ayang64/reflux/query"
+	"github.com/ayang64/reflux/services/meta"
+	"github.com/ayang64/reflux/services/wire"
+)
+
+func TestService_Query(t *testing.T) {
+	s := NewService(wire.NewConfig())
+	s.StatementExecutor.ExecuteStatementFn = func(stmt influxql.Statement, ctx *query.ExecutionContext) error {
+		if ctx.Database != "db0" {
+			t.Errorf("unexpected database: %s", ctx.Database)
+		}
+		return ctx.Send(&query.Result{
+			Series: models.Rows{{
+				Name:    "cpu",
+				Tags:    map[string]string{"host": "server01"},
+				Columns: []string{"time", "value", "n", "ok", "s"},
+				Values: [][]interface{}{
+					{time.Unix(0, 10).UTC(), 2.5, int64(-3), true, "x"},
+					{time.Unix(0, 20).UTC(), nil, int64(4), false, ""},
+				},
+			}},
+		})
+	}
+	s.Open(t)
+	defer s.Close()
+
+	c := s.Client(t, wire.ClientConfig{})
+	defer c.Close()
+
+	results, err := c.Query(context.Background(), wire.Query{
+		Command:  `SELECT * FROM cpu WHERE host = $host`,
+		Database: "db0",
+		Params:   map[string]interface{}{"host": "server01"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exp := []*query.Result{{
+		Series: models.Rows{{
+			Name:    "cpu",
+			Tags:    map[string]string{"host": "server01"},
+			Columns: []string{"time", "value", "n", "ok", "s"},
+			Values: [][]interface{}{
+				{time.Unix(0, 10).UTC(), 2.5, int64(-3), true, "x"},
+				{time.Unix(0, 20).UTC(), nil, int64(4), false, ""},
+			},
+		}},
+	}}
+	if !reflect.DeepEqual(results, exp) {
+		t.Fatalf("unexpected results:\n\ngot=%#v\n\nexp=%#v", results, exp)
+	}
+
+	// The connection can be reused for a second query.
+	if _, err := c.Query(context.Background(), wire.Query{Command: `SELECT * FROM cpu`, Database: "db0"}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestService_Query_ParseError(t *testing.T) {
+	s := NewService(wire.NewConfig())
+	s.Open(t)
+	defer s.Close()
+
+	c := s.Client(t, wire.ClientConfig{})
+	defer c.Close()
+
+	if _, err := c.Query(context.Background(), wire.Query{Command: `SELECT`}); err == nil || !strings.HasPrefix(err.Error(), "error parsing query") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestService_Query_StatementError(t *testing.T) {
+	s := NewService(wire.NewConfig())
+	s.StatementExecutor.ExecuteStatementFn = func(stmt influxql.Statement, ctx *query.ExecutionContext) error {
+		return errors.New("marker")
+	}
+	s.Open(t)
+	defer s.Close()
+
+	c := s.Client(t, wire.ClientConfig{})
+	defer c.Close()
+
+	results, err := c.Query(context.Background(), wire.Query{Command: `SELECT * FROM cpu`})
+	if err != nil {
+		t.Fatal(err)
+	} else if len(results) != 1 || results[0].Err == nil || results[0].Err.Error() != "marker" {
+		t.Fatalf("unexpected results: %#v", results)
+	}
+}
+
+func TestService_Query_Abort(t *testing.T) {
+	started := make(chan struct{})
+	s := NewService(wire.NewConfig())
+	s.StatementExecutor.ExecuteStatementFn = func(stmt influxql.Statement, ctx *query.ExecutionContext) error {
+		close(started)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(5 * time.Second):
+			return errors.New("query was not aborted")
+		}
+	}
+	s.Open(t)
+	defer s.Close()
+
+	c := s.Client(t, wire.ClientConfig{})
+	defer c.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-started
+		cancel()
+	}()
+
+	if _, err := c.Query(ctx, wire.Query{Command: `SELECT * FROM cpu`}); err != context.Canceled {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+// Ensures a context canceled as a query finishes does not abort the next
+// query on the connection.
+func TestService_Query_AbortFinished(t *testing.T) {
+	s := NewService(wire.NewConfig())
+	s.StatementExecutor.ExecuteStatementFn = func(stmt influxql.Statement, ctx *query.ExecutionContext) error {
+		if !strings.Contains(stmt.String(), "slow") {
+			return ctx.Send(&query.Result{})
+		}
+		select {
+		case <-ctx.Done():
+			return errors.New("query was aborted")
+		case <-time.After(10 * time.Millisecond):
+			return ctx.Send(&query.Result{})
+		}
+	}
+	s.Open(t)
+	defer s.Close()
+
+	c := s.Client(t, wire.ClientConfig{})
+	defer c.Close()
+
+	for i := 0; i < 20; i++ {
+		ctx, cancel := context.WithCancel(context.Background())
+		c.QueryStream(ctx, wire.Query{Command: `SELECT * FROM cpu`}, func(*query.Result) error {
+			cancel()
+			return nil
+		})
+
+		results, err := c.Query(context.Background(), wire.Query{Command: `SELECT * FROM slow`})
+		if err != nil {
+			t.Fatal(err)
+		} else if len(results) != 1 || results[0].Err != nil {
+			t.Fatalf("unexpected results: %#v", results)
+		}
+	}
+}
+
+func TestService_Query_KillQuery(t *testing.T) {
+	qid := make(chan uint64, 1)
+	s := NewService(wire.NewConfig())
+	s.StatementExecutor.ExecuteStatementFn = func(stmt influxql.Statement, ctx *query.ExecutionContext) error {
+		qid <- ctx.QueryID
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	s.Open(t)
+	defer s.Close()
+
+	c := s.Client(t, wire.ClientConfig{})
+	defer c.Close()
+
+	go func() {
+		if err := s.QueryExecutor.TaskManager.KillQuery(<-qid); err != nil {
+			t.Error(err)
+		}
+	}()
+
+	results, err := c.Query(context.Background(), wire.Query{Command: `SELECT * FROM cpu`})
+	if err != nil {
+		t.Fatal(err)
+	} else if len(results) != 1 || results[0].Err == nil || results[0].Err.Error() != query.ErrQueryInterrupted.Error() {
+		t.Fatalf("unexpected results: %#v", results)
+	}
+}
+
+func TestService_Authentication(t *testing.T) {
+	config := wire.NewConfig()
+	config.AuthEnabled = true
+
+	s := NewService(config)
+	s.MetaClient.AuthenticateFn = func(username, password string) (meta.User, error) {
+		if username != "admin" || password != "secret" {
+			return nil, meta.ErrAuthenticate
+		}
+		return &meta.UserInfo{Name: "admin", Admin: true}, nil
+	}
+	s.Open(t)
+	defer s.Close()
+
+	if _, err := wire.NewClient(wire.ClientConfig{Addr: s.Addr().String()}); err == nil || err.Error() != "username required" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := wire.NewClient(wire.ClientConfig{Addr: s.Addr().String(), Username: "admin", Password: "wrong"}); err == nil || err.Error() != "authorization failed" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	c := s.Client(t, wire.ClientConfig{Username: "admin", Password: "secret"})
+	defer c.Close()
+	if _, err := c.Query(context.Background(), wire.Query{Command: `SHOW DATABASES`}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestService_MaxConnectionLimit(t *testing.T) {
+	config := wire.NewConfig()
+	config.MaxConnectionLimit = 1
+
+	s := NewService(config)
+	s.Open(t)
+	defer s.Close()
+
+	c := s.Client(t, wire.ClientConfig{})
+	defer c.Close()
+
+	if _, err := wire.NewClient(wire.ClientConfig{Addr: s.Addr().String()}); err == nil || err.Error() != "too many connections" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestMarshalResult(t *testing.T) {
+	r := &query.Result{
+		StatementID: 3,
+		Partial:     true,
+		Err:         errors.New("marker"),
+		Messages:    []*query.Message{{Level: query.WarningLevel, Text: "be careful"}},
+		Series: models.Rows{{
+			Name:    "cpu",
+			Columns: []string{"time", "u"},
+			Values:  [][]interface{}{{time.Unix(1, 0).UTC(), uint64(1 << 63)}},
+			Partial: true,
+		}},
+	}
+
+	buf, err := wire.MarshalResult(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := wire.UnmarshalResult(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(r, other) {
+		t.Fatalf("unexpected result:\n\ngot=%#v\n\nexp=%#v", other, r)
+	}
+
+	// Truncated payloads must be rejected.
+	for i := 0; i < len(buf); i++ {
+		if _, err := wire.UnmarshalResult(buf[:i]); err == nil {
+			t.Fatalf("expected error decoding %d of %d bytes", i, len(buf))
+		}
+	}
+}
+
+// Service is a test wrapper for wire.Service.
+type Service struct {
+	*wire.Service
+
+	StatementExecutor *StatementExecutor
+	MetaClient        *MetaClient
+
+	cancel context.CancelFunc
+	done   chan error
+}
+
+// NewService returns a new instance of Service bound to a random port.
+func NewService(c wire.Config) *Service {
+	c.Enabled = true
+	c.BindAddress = "127.0.0.1:0"
+
+	s := &Service{
+		Service:           wire.NewService(c),
+		StatementExecutor: &StatementExecutor{},
+		MetaClient:        &MetaClient{},
+	}
+	s.StatementExecutor.ExecuteStatementFn = func(stmt influxql.Statement, ctx *query.ExecutionContext) error {
+		return nil
+	}
+	s.Service.MetaClient = s.MetaClient
+	s.Service.QueryAuthorizer = openQueryAuthorizer{}
+	s.Service.QueryExecutor = query.NewExecutor()
+	s.Service.QueryExecutor.StatementExecutor = s.StatementExecutor
+	return s
+}
+
+// Open starts the service and waits for it to listen.
+func (s *Service) Open(t *testing.T) {
+	var ctx context.Context
+	ctx, s.cancel = context.WithCancel(context.Background())
+	s.done = make(chan error, 1)
+	go func() { s.done <- s.Service.Start(ctx) }()
+
+	select {
+	case <-s.Ready():
+	case err := <-s.done:
+		t.Fatalf("service did not start listening: %v", err)
+	}
+}
+
+// Close stops the service and waits for it to exit.
+func (s *Service) Close() error {
+	s.cancel()
+	return <-s.done
+}
+
+// Client returns a client connected to the service.
+func (s *Service) Client(t *testing.T, c wire.ClientConfig) *wire.Client {
+	c.Addr = s.Addr().String()
+	c.Timeout = 5 * time.Second
+	client, err := wire.NewClient(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
+
+// StatementExecutor is a mock statement executor.
+type StatementExecutor struct {
+	ExecuteStatementFn func(stmt influxql.Statement, ctx *query.ExecutionContext) error
+}
+
+func (e *StatementExecutor) ExecuteStatement(stmt influxql.Statement, ctx *query.ExecutionContext) error {
+	return e.ExecuteStatementFn(stmt, ctx)
+}
+
+// MetaClient is a mock meta client.
+type MetaClient struct {
+	AuthenticateFn func(username, password string) (meta.User, error)
+}
+
+func (c *MetaClient) AuthenticateFrom(username, password, source string) (meta.User, error) {
+	return c.AuthenticateFn(username, password)
+}
+
+func (c *MetaClient) AdminUserExists() bool { return c.AuthenticateFn != nil }
+
+type openQueryAuthorizer struct{}
+
+func (openQueryAuthorizer) AuthorizeQuery(u meta.User, q *influxql.Query, database string) error {
+	if u == nil {
+		return fmt.Errorf("no user")
+	}
+	return nil
+}
@@ -9,6 +9,7 @@ package reads
 import (
 	"errors"
 
+	"github.com/ayang64/reflux/storage/reads/datatypes"
 	"github.com/ayang64/reflux/tsdb/cursors"
 )
 
@@ -237,6 +238,218 @@ func (c *integerFloatCountArrayCursor) Next() *cursors.IntegerArray {
 	}
 }
 
+type floatWindowAggregateArrayCursor struct {
+	cursors.FloatArrayCursor
+	agg    datatypes.Aggregate_AggregateType
+	every  int64
+	offset int64
+	res    *cursors.FloatArray
+	tmp    *cursors.FloatArray
+}
+
+func newFloatWindowAggregateArrayCursor(cur cursors.FloatArrayCursor, agg datatypes.Aggregate_AggregateType, every, offset int64) *floatWindowAggregateArrayCursor {
+	return &floatWindowAggregateArrayCursor{
+		FloatArrayCursor: cur,
+		agg:              agg,
+		every:            every,
+		offset:           offset,
+		res:              cursors.NewFloatArrayLen(MaxPointsPerBlock),
+		tmp:              &cursors.FloatArray{},
+	}
+}
+
+func (c *floatWindowAggregateArrayCursor) Stats() cursors.CursorStats {
+	return c.FloatArrayCursor.Stats()
+}
+
+func (c *floatWindowAggregateArrayCursor) next() *cursors.FloatArray {
+	if c.tmp.Len() > 0 {
+		return c.tmp
+	}
+	return c.FloatArrayCursor.Next()
+}
+
+func (c *floatWindowAggregateArrayCursor) Next() *cursors.FloatArray {
+	c.res.Timestamps = c.res.Timestamps[:cap(c.res.Timestamps)]
+	c.res.Values = c.res.Values[:cap(c.res.Values)]
+
+	var pos int
+	a := c.next()
+	for pos < MaxPointsPerBlock && a.Len() > 0 {
+		stop := windowStop(a.Timestamps[0], c.every, c.offset)
+		ts, acc := a.Timestamps[0], a.Values[0]
+		i := 1
+	WINDOW:
+		for {
+			for ; i < a.Len(); i++ {
+				if a.Timestamps[i] >= stop {
+					break WINDOW
+				}
+				switch c.agg {
+				case datatypes.AggregateTypeSum:
+					acc += a.Values[i]
+				case datatypes.AggregateTypeMin:
+					if a.Values[i] < acc {
+						ts, acc = a.Timestamps[i], a.Values[i]
+					}
+				case datatypes.AggregateTypeMax:
+					if a.Values[i] > acc {
+						ts, acc = a.Timestamps[i], a.Values[i]
+					}
+				case datatypes.AggregateTypeLast:
+					ts, acc = a.Timestamps[i], a.Values[i]
+				}
+			}
+			a, i = c.FloatArrayCursor.Next(), 0
+			if a.Len() == 0 {
+				break
+			}
+		}
+		c.tmp.Timestamps = a.Timestamps[i:]
+		c.tmp.Values = a.Values[i:]
+
+		if c.agg == datatypes.AggregateTypeSum && c.every > 0 {
+			ts = stop
+		}
+		c.res.Timestamps[pos] = ts
+		c.res.Values[pos] = acc
+		pos++
+
+		a = c.next()
+	}
+
+	c.res.Timestamps = c.res.Timestamps[:pos]
+	c.res.Values = c.res.Values[:pos]
+
+	return c.res
+}
+
+type integerFloatWindowCountArrayCursor struct {
+	cursors.FloatArrayCursor
+	every  int64
+	offset int64
+	res    *cursors.IntegerArray
+	tmp    *cursors.FloatArray
+}
+
+func (c *integerFloatWindowCountArrayCursor) Stats() cursors.CursorStats {
+	return c.FloatArrayCursor.Stats()
+}
+
+func (c *integerFloatWindowCountArrayCursor) next() *cursors.FloatArray {
+	if c.tmp.Len() > 0 {
+		return c.tmp
+	}
+	return c.FloatArrayCursor.Next()
+}
+
+func (c *integerFloatWindowCountArrayCursor) Next() *cursors.IntegerArray {
+	c.res.Timestamps = c.res.Timestamps[:cap(c.res.Timestamps)]
+	c.res.Values = c.res.Values[:cap(c.res.Values)]
+
+	var pos int
+	a := c.next()
+	for pos < MaxPointsPerBlock && a.Len() > 0 {
+		stop := windowStop(a.Timestamps[0], c.every, c.offset)
+		ts := a.Timestamps[0]
+		var acc int64
+		i := 0
+	WINDOW:
+		for {
+			for ; i < a.Len(); i++ {
+				if a.Timestamps[i] >= stop {
+					break WINDOW
+				}
+				acc++
+			}
+			a, i = c.FloatArrayCursor.Next(), 0
+			if a.Len() == 0 {
+				break
+			}
+		}
+		c.tmp.Timestamps = a.Timestamps[i:]
+		c.tmp.Values = a.Values[i:]
+
+		if c.every > 0 {
+			ts = stop
+		}
+		c.res.Timestamps[pos] = ts
+		c.res.Values[pos] = acc
+		pos++
+
+		a = c.next()
+	}
+
+	c.res.Timestamps = c.res.Timestamps[:pos]
+	c.res.Values = c.res.Values[:pos]
+
+	return c.res
+}
+
+type floatFloatWindowMeanArrayCursor struct {
+	cursors.FloatArrayCursor
+	every  int64
+	offset int64
+	res    *cursors.FloatArray
+	tmp    *cursors.FloatArray
+}
+
+func (c *floatFloatWindowMeanArrayCursor) Stats() cursors.CursorStats {
+	return c.FloatArrayCursor.Stats()
+}
+
+func (c *floatFloatWindowMeanArrayCursor) next() *cursors.FloatArray {
+	if c.tmp.Len() > 0 {
+		return c.tmp
+	}
+	return c.FloatArrayCursor.Next()
+}
+
+func (c *floatFloatWindowMeanArrayCursor) Next() *cursors.FloatArray {
+	c.res.Timestamps = c.res.Timestamps[:cap(c.res.Timestamps)]
+	c.res.Values = c.res.Values[:cap(c.res.Values)]
+
+	var pos int
+	a := c.next()
+	for pos < MaxPointsPerBlock && a.Len() > 0 {
+		stop := windowStop(a.Timestamps[0], c.every, c.offset)
+		ts := a.Timestamps[0]
+		var sum float64
+		var n int64
+		i := 0
+	WINDOW:
+		for {
+			for ; i < a.Len(); i++ {
+				if a.Timestamps[i] >= stop {
+					break WINDOW
+				}
+				sum += float64(a.Values[i])
+				n++
+			}
+			a, i = c.FloatArrayCursor.Next(), 0
+			if a.Len() == 0 {
+				break
+			}
+		}
+		c.tmp.Timestamps = a.Timestamps[i:]
+		c.tmp.Values = a.Values[i:]
+
+		if c.every > 0 {
+			ts = stop
+		}
+		c.res.Timestamps[pos] = ts
+		c.res.Values[pos] = sum / float64(n)
+		pos++
+
+		a = c.next()
+	}
+
+	c.res.Timestamps = c.res.Timestamps[:pos]
+	c.res.Values = c.res.Values[:pos]
+
+	return c.res
+}
+
 type floatEmptyArrayCursor struct {
 	res cursors.FloatArray
 }
@@ -466,6 +679,218 @@ func (c *integerIntegerCountArrayCursor) Next() *cursors.IntegerArray {
 	}
 }
 
+type integerWindowAggregateArrayCursor struct {
+	cursors.IntegerArrayCursor
+	agg    datatypes.Aggregate_AggregateType
+	every  int64
+	offset int64
+	res    *cursors.IntegerArray
+	tmp    *cursors.IntegerArray
+}
+
+func newIntegerWindowAggregateArrayCursor(cur cursors.IntegerArrayCursor, agg datatypes.Aggregate_AggregateType, every, offset int64) *integerWindowAggregateArrayCursor {
+	return &integerWindowAggregateArrayCursor{
+		IntegerArrayCursor: cur,
+		agg:                agg,
+		every:              every,
+		offset:             offset,
+		res:                cursors.NewIntegerArrayLen(MaxPointsPerBlock),
+		tmp:                &cursors.IntegerArray{},
+	}
+}
+
+func (c *integerWindowAggregateArrayCursor) Stats() cursors.CursorStats {
+	return c.IntegerArrayCursor.Stats()
+}
+
+func (c *integerWindowAggregateArrayCursor) next() *cursors.IntegerArray {
+	if c.tmp.Len() > 0 {
+		return c.tmp
+	}
+	return c.IntegerArrayCursor.Next()
+}
+
+func (c *integerWindowAggregateArrayCursor) Next() *cursors.IntegerArray {
+	c.res.Timestamps = c.res.Timestamps[:cap(c.res.Timestamps)]
+	c.res.Values = c.res.Values[:cap(c.res.Values)]
+
+	var pos int
+	a := c.next()
+	for pos < MaxPointsPerBlock && a.Len() > 0 {
+		stop := windowStop(a.Timestamps[0], c.every, c.offset)
+		ts, acc := a.Timestamps[0], a.Values[0]
+		i := 1
+	WINDOW:
+		for {
+			for ; i < a.Len(); i++ {
+				if a.Timestamps[i] >= stop {
+					break WINDOW
+				}
+				switch c.agg {
+				case datatypes.AggregateTypeSum:
+					acc += a.Values[i]
+				case datatypes.AggregateTypeMin:
+					if a.Values[i] < acc {
+						ts, acc = a.Timestamps[i], a.Values[i]
+					}
+				case datatypes.AggregateTypeMax:
+					if a.Values[i] > acc {
+						ts, acc = a.Timestamps[i], a.Values[i]
+					}
+				case datatypes.AggregateTypeLast:
+					ts, acc = a.Timestamps[i], a.Values[i]
+				}
+			}
+			a, i = c.IntegerArrayCursor.Next(), 0
+			if a.Len() == 0 {
+				break
+			}
+		}
+		c.tmp.Timestamps = a.Timestamps[i:]
+		c.tmp.Values = a.Values[i:]
+
+		if c.agg == datatypes.AggregateTypeSum && c.every > 0 {
+			ts = stop
+		}
+		c.res.Timestamps[pos] = ts
+		c.res.Values[pos] = acc
+		pos++
+
+		a = c.next()
+	}
+
+	c.res.Timestamps = c.res.Timestamps[:pos]
+	c.res.Values = c.res.Values[:pos]
+
+	return c.res
+}
+
+type integerIntegerWindowCountArrayCursor struct {
+	cursors.IntegerArrayCursor
+	every  int64
+	offset int64
+	res    *cursors.IntegerArray
+	tmp    *cursors.IntegerArray
+}
+
+func (c *integerIntegerWindowCountArrayCursor) Stats() cursors.CursorStats {
+	return c.IntegerArrayCursor.Stats()
+}
+
+func (c *integerIntegerWindowCountArrayCursor) next() *cursors.IntegerArray {
+	if c.tmp.Len() > 0 {
+		return c.tmp
+	}
+	return c.IntegerArrayCursor.Next()
+}
+
+func (c *integerIntegerWindowCountArrayCursor) Next() *cursors.IntegerArray {
+	c.res.Timestamps = c.res.Timestamps[:cap(c.res.Timestamps)]
+	c.res.Values = c.res.Values[:cap(c.res.Values)]
+
+	var pos int
+	a := c.next()
+	for pos < MaxPointsPerBlock && a.Len() > 0 {
+		stop := windowStop(a.Timestamps[0], c.every, c.offset)
+		ts := a.Timestamps[0]
+		var acc int64
+		i := 0
+	WINDOW:
+		for {
+			for ; i < a.Len(); i++ {
+				if a.Timestamps[i] >= stop {
+					break WINDOW
+				}
+				acc++
+			}
+			a, i = c.IntegerArrayCursor.Next(), 0
+			if a.Len() == 0 {
+				break
+			}
+		}
+		c.tmp.Timestamps = a.Timestamps[i:]
+		c.tmp.Values = a.Values[i:]
+
+		if c.every > 0 {
+			ts = stop
+		}
+		c.res.Timestamps[pos] = ts
+		c.res.Values[pos] = acc
+		pos++
+
+		a = c.next()
+	}
+
+	c.res.Timestamps = c.res.Timestamps[:pos]
+	c.res.Values = c.res.Values[:pos]
+
+	return c.res
+}
+
+type floatIntegerWindowMeanArrayCursor struct {
+	cursors.IntegerArrayCursor
+	every  int64
+	offset int64
+	res    *cursors.FloatArray
+	tmp    *cursors.IntegerArray
+}
+
+func (c *floatIntegerWindowMeanArrayCursor) Stats() cursors.CursorStats {
+	return c.IntegerArrayCursor.Stats()
+}
+
+func (c *floatIntegerWindowMeanArrayCursor) next() *cursors.IntegerArray {
+	if c.tmp.Len() > 0 {
+		return c.tmp
+	}
+	return c.IntegerArrayCursor.Next()
+}
+
+func (c *floatIntegerWindowMeanArrayCursor) Next() *cursors.FloatArray {
+	c.res.Timestamps = c.res.Timestamps[:cap(c.res.Timestamps)]
+	c.res.Values = c.res.Values[:cap(c.res.Values)]
+
+	var pos int
+	a := c.next()
+	for pos < MaxPointsPerBlock && a.Len() > 0 {
+		stop := windowStop(a.Timestamps[0], c.every, c.offset)
+		ts := a.Timestamps[0]
+		var sum float64
+		var n int64
+		i := 0
+	WINDOW:
+		for {
+			for ; i < a.Len(); i++ {
+				if a.Timestamps[i] >= stop {
+					break WINDOW
+				}
+				sum += float64(a.Values[i])
+				n++
+			}
+			a, i = c.IntegerArrayCursor.Next(), 0
+			if a.Len() == 0 {
+				break
+			}
+		}
+		c.tmp.Timestamps = a.Timestamps[i:]
+		c.tmp.Values = a.Values[i:]
+
+		if c.every > 0 {
+			ts = stop
+		}
+		c.res.Timestamps[pos] = ts
+		c.res.Values[pos] = sum / float64(n)
+		pos++
+
+		a = c.next()
+	}
+
+	c.res.Timestamps = c.res.Timestamps[:pos]
+	c.res.Values = c.res.Values[:pos]
+
+	return c.res
+}
+
 type integerEmptyArrayCursor struct {
 	res cursors.IntegerArray
 }
@@ -695,6 +1120,218 @@ func (c *integerUnsignedCountArrayCursor) Next() *cursors.IntegerArray {
 	}
 }
 
+type unsignedWindowAggregateArrayCursor struct {
+	cursors.UnsignedArrayCursor
+	agg    datatypes.Aggregate_AggregateType
+	every  int64
+	offset int64
+	res    *cursors.UnsignedArray
+	tmp    *cursors.UnsignedArray
+}
+
+func newUnsignedWindowAggregateArrayCursor(cur cursors.UnsignedArrayCursor, agg datatypes.Aggregate_AggregateType, every, offset int64) *unsignedWindowAggregateArrayCursor {
+	return &unsignedWindowAggregateArrayCursor{
+		UnsignedArrayCursor: cur,
+		agg:                 agg,
+		every:               every,
+		offset:              offset,
+		res:                 cursors.NewUnsignedArrayLen(MaxPointsPerBlock),
+		tmp:                 &cursors.UnsignedArray{},
+	}
+}
+
+func (c *unsignedWindowAggregateArrayCursor) Stats() cursors.CursorStats {
+	return c.UnsignedArrayCursor.Stats()
+}
+
+func (c *unsignedWindowAggregateArrayCursor) next() *cursors.UnsignedArray {
+	if c.tmp.Len() > 0 {
+		return c.tmp
+	}
+	return c.UnsignedArrayCursor.Next()
+}
+
+func (c *unsignedWindowAggregateArrayCursor) Next() *cursors.UnsignedArray {
+	c.res.Timestamps = c.res.Timestamps[:cap(c.res.Timestamps)]
+	c.res.Values = c.res.Values[:cap(c.res.Values)]
+
+	var pos int
+	a := c.next()
+	for pos < MaxPointsPerBlock && a.Len() > 0 {
+		stop := windowStop(a.Timestamps[0], c.every, c.offset)
+		ts, acc := a.Timestamps[0], a.Values[0]
+		i := 1
+	WINDOW:
+		for {
+			for ; i < a.Len(); i++ {
+				if a.Timestamps[i] >= stop {
+					break WINDOW
+				}
+				switch c.agg {
+				case datatypes.AggregateTypeSum:
+					acc += a.Values[i]
+				case datatypes.AggregateTypeMin:
+					if a.Values[i] < acc {
+						ts, acc = a.Timestamps[i], a.Values[i]
+					}
+				case datatypes.AggregateTypeMax:
+					if a.Values[i] > acc {
+						ts, acc = a.Timestamps[i], a.Values[i]
+					}
+				case datatypes.AggregateTypeLast:
+					ts, acc = a.Timestamps[i], a.Values[i]
+				}
+			}
+			a, i = c.UnsignedArrayCursor.Next(), 0
+			if a.Len() == 0 {
+				break
+			}
+		}
+		c.tmp.Timestamps = a.Timestamps[i:]
+		c.tmp.Values = a.Values[i:]
+
+		if c.agg == datatypes.AggregateTypeSum && c.every > 0 {
+			ts = stop
+		}
+		c.res.Timestamps[pos] = ts
+		c.res.Values[pos] = acc
+		pos++
+
+		a = c.next()
+	}
+
+	c.res.Timestamps = c.res.Timestamps[:pos]
+	c.res.Values = c.res.Values[:pos]
+
+	return c.res
+}
+
+type integerUnsignedWindowCountArrayCursor struct {
+	cursors.UnsignedArrayCursor
+	every  int64
+	offset int64
+	res    *cursors.IntegerArray
+	tmp    *cursors.UnsignedArray
+}
+
+func (c *integerUnsignedWindowCountArrayCursor) Stats() cursors.CursorStats {
+	return c.UnsignedArrayCursor.Stats()
+}
+
+func (c *integerUnsignedWindowCountArrayCursor) next() *cursors.UnsignedArray {
+	if c.tmp.Len() > 0 {
+		return c.tmp
+	}
+	return c.UnsignedArrayCursor.Next()
+}
+
+func (c *integerUnsignedWindowCountArrayCursor) Next() *cursors.IntegerArray {
+	c.res.Timestamps = c.res.Timestamps[:cap(c.res.Timestamps)]
+	c.res.Values = c.res.Values[:cap(c.res.Values)]
+
+	var pos int
+	a := c.next()
+	for pos < MaxPointsPerBlock && a.Len() > 0 {
+		stop := windowStop(a.Timestamps[0], c.every, c.offset)
+		ts := a.Timestamps[0]
+		var acc int64
+		i := 0
+	WINDOW:
+		for {
+			for ; i < a.Len(); i++ {
+				if a.Timestamps[i] >= stop {
+					break WINDOW
+				}
+				acc++
+			}
+			a, i = c.UnsignedArrayCursor.Next(), 0
+			if a.Len() == 0 {
+				break
+			}
+		}
+		c.tmp.Timestamps = a.Timestamps[i:]
+		c.tmp.Values = a.Values[i:]
+
+		if c.every > 0 {
+			ts = stop
+		}
+		c.res.Timestamps[pos] = ts
+		c.res.Values[pos] = acc
+		pos++
+
+		a = c.next()
+	}
+
+	c.res.Timestamps = c.res.Timestamps[:pos]
+	c.res.Values = c.res.Values[:pos]
+
+	return c.res
+}
+
+type floatUnsignedWindowMeanArrayCursor struct {
+	cursors.UnsignedArrayCursor
+	every  int64
+	offset int64
+	res    *cursors.FloatArray
+	tmp    *cursors.UnsignedArray
+}
+
+func (c *floatUnsignedWindowMeanArrayCursor) Stats() cursors.CursorStats {
+	return c.UnsignedArrayCursor.Stats()
+}
+
+func (c *floatUnsignedWindowMeanArrayCursor) next() *cursors.UnsignedArray {
+	if c.tmp.Len() > 0 {
+		return c.tmp
+	}
+	return c.UnsignedArrayCursor.Next()
+}
+
+func (c *floatUnsignedWindowMeanArrayCursor) Next() *cursors.FloatArray {
+	c.res.Timestamps = c.res.Timestamps[:cap(c.res.Timestamps)]
+	c.res.Values = c.res.Values[:cap(c.res.Values)]
+
+	var pos int
+	a := c.next()
+	for pos < MaxPointsPerBlock && a.Len() > 0 {
+		stop := windowStop(a.Timestamps[0], c.every, c.offset)
+		ts := a.Timestamps[0]
+		var sum float64
+		var n int64
+		i := 0
+	WINDOW:
+		for {
+			for ; i < a.Len(); i++ {
+				if a.Timestamps[i] >= stop {
+					break WINDOW
+				}
+				sum += float64(a.Values[i])
+				n++
+			}
+			a, i = c.UnsignedArrayCursor.Next(), 0
+			if a.Len() == 0 {
+				break
+			}
+		}
+		c.tmp.Timestamps = a.Timestamps[i:]
+		c.tmp.Values = a.Values[i:]
+
+		if c.every > 0 {
+			ts = stop
+		}
+		c.res.Timestamps[pos] = ts
+		c.res.Values[pos] = sum / float64(n)
+		pos++
+
+		a = c.next()
+	}
+
+	c.res.Timestamps = c.res.Timestamps[:pos]
+	c.res.Values = c.res.Values[:pos]
+
+	return c.res
+}
+
 type unsignedEmptyArrayCursor struct {
 	res cursors.UnsignedArray
 }
@@ -884,6 +1521,140 @@ func (c *integerStringCountArrayCursor) Next() *cursors.IntegerArray {
 	}
 }
 
+type stringWindowAggregateArrayCursor struct {
+	cursors.StringArrayCursor
+	agg    datatypes.Aggregate_AggregateType
+	every  int64
+	offset int64
+	res    *cursors.StringArray
+	tmp    *cursors.StringArray
+}
+
+func newStringWindowAggregateArrayCursor(cur cursors.StringArrayCursor, agg datatypes.Aggregate_AggregateType, every, offset int64) *stringWindowAggregateArrayCursor {
+	return &stringWindowAggregateArrayCursor{
+		StringArrayCursor: cur,
+		agg:               agg,
+		every:             every,
+		offset:            offset,
+		res:               cursors.NewStringArrayLen(MaxPointsPerBlock),
+		tmp:               &cursors.StringArray{},
+	}
+}
+
+func (c *stringWindowAggregateArrayCursor) Stats() cursors.CursorStats {
+	return c.StringArrayCursor.Stats()
+}
+
+func (c *stringWindowAggregateArrayCursor) next() *cursors.StringArray {
+	if c.tmp.Len() > 0 {
+		return c.tmp
+	}
+	return c.StringArrayCursor.Next()
+}
+
+func (c *stringWindowAggregateArrayCursor) Next() *cursors.StringArray {
+	c.res.Timestamps = c.res.Timestamps[:cap(c.res.Timestamps)]
+	c.res.Values = c.res.Values[:cap(c.res.Values)]
+
+	var pos int
+	a := c.next()
+	for pos < MaxPointsPerBlock && a.Len() > 0 {
+		stop := windowStop(a.Timestamps[0], c.every, c.offset)
+		ts, acc := a.Timestamps[0], a.Values[0]
+		i := 1
+	WINDOW:
+		for {
+			for ; i < a.Len(); i++ {
+				if a.Timestamps[i] >= stop {
+					break WINDOW
+				}
+				switch c.agg {
+				case datatypes.AggregateTypeLast:
+					ts, acc = a.Timestamps[i], a.Values[i]
+				}
+			}
+			a, i = c.StringArrayCursor.Next(), 0
+			if a.Len() == 0 {
+				break
+			}
+		}
+		c.tmp.Timestamps = a.Timestamps[i:]
+		c.tmp.Values = a.Values[i:]
+		c.res.Timestamps[pos] = ts
+		c.res.Values[pos] = acc
+		pos++
+
+		a = c.next()
+	}
+
+	c.res.Timestamps = c.res.Timestamps[:pos]
+	c.res.Values = c.res.Values[:pos]
+
+	return c.res
+}
+
+type integerStringWindowCountArrayCursor struct {
+	cursors.StringArrayCursor
+	every  int64
+	offset int64
+	res    *cursors.IntegerArray
+	tmp    *cursors.StringArray
+}
+
+func (c *integerStringWindowCountArrayCursor) Stats() cursors.CursorStats {
+	return c.StringArrayCursor.Stats()
+}
+
+func (c *integerStringWindowCountArrayCursor) next() *cursors.StringArray {
+	if c.tmp.Len() > 0 {
+		return c.tmp
+	}
+	return c.StringArrayCursor.Next()
+}
+
+func (c *integerStringWindowCountArrayCursor) Next() *cursors.IntegerArray {
+	c.res.Timestamps = c.res.Timestamps[:cap(c.res.Timestamps)]
+	c.res.Values = c.res.Values[:cap(c.res.Values)]
+
+	var pos int
+	a := c.next()
+	for pos < MaxPointsPerBlock && a.Len() > 0 {
+		stop := windowStop(a.Timestamps[0], c.every, c.offset)
+		ts := a.Timestamps[0]
+		var acc int64
+		i := 0
+	WINDOW:
+		for {
+			for ; i < a.Len(); i++ {
+				if a.Timestamps[i] >= stop {
+					break WINDOW
+				}
+				acc++
+			}
+			a, i = c.StringArrayCursor.Next(), 0
+			if a.Len() == 0 {
+				break
+			}
+		}
+		c.tmp.Timestamps = a.Timestamps[i:]
+		c.tmp.Values = a.Values[i:]
+
+		if c.every > 0 {
+			ts = stop
+		}
+		c.res.Timestamps[pos] = ts
+		c.res.Values[pos] = acc
+		pos++
+
+		a = c.next()
+	}
+
+	c.res.Timestamps = c.res.Timestamps[:pos]
+	c.res.Values = c.res.Values[:pos]
+
+	return c.res
+}
+
 type stringEmptyArrayCursor struct {
 	res cursors.StringArray
 }
@@ -1073,6 +1844,140 @@ func (c *integerBooleanCountArrayCursor) Next() *cursors.IntegerArray {
 	}
 }
 
+type booleanWindowAggregateArrayCursor struct {
+	cursors.BooleanArrayCursor
+	agg    datatypes.Aggregate_AggregateType
+	every  int64
+	offset int64
+	res    *cursors.BooleanArray
+	tmp    *cursors.BooleanArray
+}
+
+func newBooleanWindowAggregateArrayCursor(cur cursors.BooleanArrayCursor, agg datatypes.Aggregate_AggregateType, every, offset int64) *booleanWindowAggregateArrayCursor {
+	return &booleanWindowAggregateArrayCursor{
+		BooleanArrayCursor: cur,
+		agg:                agg,
+		every:              every,
+		offset:             offset,
+		res:                cursors.NewBooleanArrayLen(MaxPointsPerBlock),
+		tmp:                &cursors.BooleanArray{},
+	}
+}
+
+func (c *booleanWindowAggregateArrayCursor) Stats() cursors.CursorStats {
+	return c.BooleanArrayCursor.Stats()
+}
+
+func (c *booleanWindowAggregateArrayCursor) next() *cursors.BooleanArray {
+	if c.tmp.Len() > 0 {
+		return c.tmp
+	}
+	return c.BooleanArrayCursor.Next()
+}
+
+func (c *booleanWindowAggregateArrayCursor) Next() *cursors.BooleanArray {
+	c.res.Timestamps = c.res.Timestamps[:cap(c.res.Timestamps)]
+	c.res.Values = c.res.Values[:cap(c.res.Values)]
+
+	var pos int
+	a := c.next()
+	for pos < MaxPointsPerBlock && a.Len() > 0 {
+		stop := windowStop(a.Timestamps[0], c.every, c.offset)
+		ts, acc := a.Timestamps[0], a.Values[0]
+		i := 1
+	WINDOW:
+		for {
+			for ; i < a.Len(); i++ {
+				if a.Timestamps[i] >= stop {
+					break WINDOW
+				}
+				switch c.agg {
+				case datatypes.AggregateTypeLast:
+					ts, acc = a.Timestamps[i], a.Values[i]
+				}
+			}
+			a, i = c.BooleanArrayCursor.Next(), 0
+			if a.Len() == 0 {
+				break
+			}
+		}
+		c.tmp.Timestamps = a.Timestamps[i:]
+		c.tmp.Values = a.Values[i:]
+		c.res.Timestamps[pos] = ts
+		c.res.Values[pos] = acc
+		pos++
+
+		a = c.next()
+	}
+
+	c.res.Timestamps = c.res.Timestamps[:pos]
+	c.res.Values = c.res.Values[:pos]
+
+	return c.res
+}
+
+type integerBooleanWindowCountArrayCursor struct {
+	cursors.BooleanArrayCursor
+	every  int64
+	offset int64
+	res    *cursors.IntegerArray
+	tmp    *cursors.BooleanArray
+}
+
+func (c *integerBooleanWindowCountArrayCursor) Stats() cursors.CursorStats {
+	return c.BooleanArrayCursor.Stats()
+}
+
+func (c *integerBooleanWindowCountArrayCursor) next() *cursors.BooleanArray {
+	if c.tmp.Len() > 0 {
+		return c.tmp
+	}
+	return c.BooleanArrayCursor.Next()
+}
+
+func (c *integerBooleanWindowCountArrayCursor) Next() *cursors.IntegerArray {
+	c.res.Timestamps = c.res.Timestamps[:cap(c.res.Timestamps)]
+	c.res.Values = c.res.Values[:cap(c.res.Values)]
+
+	var pos int
+	a := c.next()
+	for pos < MaxPointsPerBlock && a.Len() > 0 {
+		stop := windowStop(a.Timestamps[0], c.every, c.offset)
+		ts := a.Timestamps[0]
+		var acc int64
+		i := 0
+	WINDOW:
+		for {
+			for ; i < a.Len(); i++ {
+				if a.Timestamps[i] >= stop {
+					break WINDOW
+				}
+				acc++
+			}
+			a, i = c.BooleanArrayCursor.Next(), 0
+			if a.Len() == 0 {
+				break
+			}
+		}
+		c.tmp.Timestamps = a.Timestamps[i:]
+		c.tmp.Values = a.Values[i:]
+
+		if c.every > 0 {
+			ts = stop
+		}
+		c.res.Timestamps[pos] = ts
+		c.res.Values[pos] = acc
+		pos++
+
+		a = c.next()
+	}
+
+	c.res.Timestamps = c.res.Timestamps[:pos]
+	c.res.Values = c.res.Values[:pos]
+
+	return c.res
+}
+
 type booleanEmptyArrayCursor struct {
 	res cursors.BooleanArray
 }
@@ -3,6 +3,7 @@ package reads
 import (
 	"context"
 	"fmt"
+	"math"
 
 	"github.com/ayang64/reflux/storage/reads/datatypes"
 	"github.com/ayang64/reflux/tsdb/cursors"
@@ -32,6 +33,101 @@ func newAggregateArrayCursor(ctx context.Context, agg *datatypes.Aggregate, curs
 	}
 }
 
+// newWindowAggregateArrayCursor returns a cursor that evaluates agg over
+// consecutive windows of every nanoseconds, shifted by offset. An every of 0
+// evaluates agg over the entire cursor.
+//
+// Aggregates are timestamped with the stop time of their window, or the time
+// of the first point when every is 0. Selectors retain the time of the
+// selected point.
+//
+// An error is returned if agg cannot be evaluated over the values of cursor,
+// such as the sum of a string field.
+func newWindowAggregateArrayCursor(ctx context.Context, agg *datatypes.Aggregate, every, offset int64, cursor cursors.Cursor) (cursors.Cursor, error) {
+	if cursor == nil {
+		return nil, nil
+	}
+
+	switch agg.Type {
+	case datatypes.AggregateTypeCount:
+		return newWindowCountArrayCursor(cursor, every, offset), nil
+	case datatypes.AggregateTypeMean, datatypes.AggregateTypeSum, datatypes.AggregateTypeMin, datatypes.AggregateTypeMax:
+		switch cursor.(type) {
+		case cursors.StringArrayCursor:
+			return nil, fmt.Errorf("unsupported aggregate %s for string field", agg.Type)
+		case cursors.BooleanArrayCursor:
+			return nil, fmt.Errorf("unsupported aggregate %s for boolean field", agg.Type)
+		}
+		if agg.Type == datatypes.AggregateTypeMean {
+			return newWindowMeanArrayCursor(cursor, every, offset), nil
+		}
+	case datatypes.AggregateTypeFirst, datatypes.AggregateTypeLast:
+	default:
+		return nil, fmt.Errorf("unsupported window aggregate %s", agg.Type)
+	}
+
+	switch cur := cursor.(type) {
+	case cursors.FloatArrayCursor:
+		return newFloatWindowAggregateArrayCursor(cur, agg.Type, every, offset), nil
+	case cursors.IntegerArrayCursor:
+		return newIntegerWindowAggregateArrayCursor(cur, agg.Type, every, offset), nil
+	case cursors.UnsignedArrayCursor:
+		return newUnsignedWindowAggregateArrayCursor(cur, agg.Type, every, offset), nil
+	case cursors.StringArrayCursor:
+		return newStringWindowAggregateArrayCursor(cur, agg.Type, every, offset), nil
+	case cursors.BooleanArrayCursor:
+		return newBooleanWindowAggregateArrayCursor(cur, agg.Type, every, offset), nil
+	default:
+		panic(fmt.Sprintf("unreachable: %T", cur))
+	}
+}
+
+func newWindowCountArrayCursor(cur cursors.Cursor, every, offset int64) cursors.Cursor {
+	res := cursors.NewIntegerArrayLen(MaxPointsPerBlock)
+	switch cur := cur.(type) {
+	case cursors.FloatArrayCursor:
+		return &integerFloatWindowCountArrayCursor{FloatArrayCursor: cur, every: every, offset: offset, res: res, tmp: &cursors.FloatArray{}}
+	case cursors.IntegerArrayCursor:
+		return &integerIntegerWindowCountArrayCursor{IntegerArrayCursor: cur, every: every, offset: offset, res: res, tmp: &cursors.IntegerArray{}}
+	case cursors.UnsignedArrayCursor:
+		return &integerUnsignedWindowCountArrayCursor{UnsignedArrayCursor: cur, every: every, offset: offset, res: res, tmp: &cursors.UnsignedArray{}}
+	case cursors.StringArrayCursor:
+		return &integerStringWindowCountArrayCursor{StringArrayCursor: cur, every: every, offset: offset, res: res, tmp: &cursors.StringArray{}}
+	case cursors.BooleanArrayCursor:
+		return &integerBooleanWindowCountArrayCursor{BooleanArrayCursor: cur, every: every, offset: offset, res: res, tmp: &cursors.BooleanArray{}}
+	default:
+		panic(fmt.Sprintf("unreachable: %T", cur))
+	}
+}
+
+func newWindowMeanArrayCursor(cur cursors.Cursor, every, offset int64) cursors.Cursor {
+	res := cursors.NewFloatArrayLen(MaxPointsPerBlock)
+	switch cur := cur.(type) {
+	case cursors.FloatArrayCursor:
+		return &floatFloatWindowMeanArrayCursor{FloatArrayCursor: cur, every: every, offset: offset, res: res, tmp: &cursors.FloatArray{}}
+	case cursors.IntegerArrayCursor:
+		return &floatIntegerWindowMeanArrayCursor{IntegerArrayCursor: cur, every: every, offset: offset, res: res, tmp: &cursors.IntegerArray{}}
+	case cursors.UnsignedArrayCursor:
+		return &floatUnsignedWindowMeanArrayCursor{UnsignedArrayCursor: cur, every: every, offset: offset, res: res, tmp: &cursors.UnsignedArray{}}
+	default:
+		panic(fmt.Sprintf("unreachable: %T", cur))
+	}
+}
+
+// windowStop returns the exclusive upper bound of the window of every
+// nanoseconds, shifted by offset, that contains ts. If every is 0, the window
+// is unbounded.
+func windowStop(ts, every, offset int64) int64 {
+	if every <= 0 {
+		return math.MaxInt64
+	}
+	r := (ts - offset) % every
+	if r < 0 {
+		r += every
+	}
+	return ts - r + every
+}
+
 func newSumArrayCursor(cur cursors.Cursor) cursors.Cursor {
 	switch cur := cur.(type) {
 	case cursors.FloatArrayCursor:
@@ -0,0 +1,143 @@
+package reads
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/ayang64/reflux/storage/reads/datatypes"
+	"github.com/ayang64/reflux/tsdb/cursors"
+)
+
+// floatArrayCursor returns each of its arrays in turn.
+type floatArrayCursor struct {
+	arrays []*cursors.FloatArray
+}
+
+func (c *floatArrayCursor) Next() *cursors.FloatArray {
+	if len(c.arrays) == 0 {
+		return &cursors.FloatArray{}
+	}
+	a := c.arrays[0]
+	c.arrays = c.arrays[1:]
+	return a
+}
+
+func (c *floatArrayCursor) Close()                     {}
+func (c *floatArrayCursor) Err() error                 { return nil }
+func (c *floatArrayCursor) Stats() cursors.CursorStats { return cursors.CursorStats{} }
+
+// newFloatArrayCursor returns a cursor of the points of ts and vs, split
+// into arrays of at most n points.
+func newFloatArrayCursor(n int, ts []int64, vs []float64) *floatArrayCursor {
+	c := &floatArrayCursor{}
+	for len(ts) > 0 {
+		if n > len(ts) {
+			n = len(ts)
+		}
+		c.arrays = append(c.arrays, &cursors.FloatArray{Timestamps: ts[:n], Values: vs[:n]})
+		ts, vs = ts[n:], vs[n:]
+	}
+	return c
+}
+
+func TestWindowAggregateArrayCursor_Float(t *testing.T) {
+	ts := []int64{1, 2, 5, 9, 10, 11, 25, 29}
+	vs := []float64{4, 2, 8, 1, 3, 7, 6, 5}
+
+	tests := []struct {
+		agg    datatypes.Aggregate_AggregateType
+		every  int64
+		offset int64
+		expTs  []int64
+		expVs  interface{}
+	}{
+		{agg: datatypes.AggregateTypeCount, every: 10, expTs: []int64{10, 20, 30}, expVs: []int64{4, 2, 2}},
+		{agg: datatypes.AggregateTypeSum, every: 10, expTs: []int64{10, 20, 30}, expVs: []float64{15, 10, 11}},
+		{agg: datatypes.AggregateTypeMean, every: 10, expTs: []int64{10, 20, 30}, expVs: []float64{3.75, 5, 5.5}},
+		{agg: datatypes.AggregateTypeMin, every: 10, expTs: []int64{9, 10, 29}, expVs: []float64{1, 3, 5}},
+		{agg: datatypes.AggregateTypeMax, every: 10, expTs: []int64{5, 11, 25}, expVs: []float64{8, 7, 6}},
+		{agg: datatypes.AggregateTypeFirst, every: 10, expTs: []int64{1, 10, 25}, expVs: []float64{4, 3, 6}},
+		{agg: datatypes.AggregateTypeLast, every: 10, expTs: []int64{9, 11, 29}, expVs: []float64{1, 7, 5}},
+		{agg: datatypes.AggregateTypeSum, every: 10, offset: 5, expTs: []int64{5, 15, 35}, expVs: []float64{6, 19, 11}},
+		{agg: datatypes.AggregateTypeMax, expTs: []int64{5}, expVs: []float64{8}},
+		{agg: datatypes.AggregateTypeMean, expTs: []int64{1}, expVs: []float64{4.5}},
+	}
+
+	for _, tt := range tests {
+		// Split the points across arrays so windows span array boundaries.
+		for _, n := range []int{1, 3, len(ts)} {
+			cur, err := newWindowAggregateArrayCursor(context.Background(), &datatypes.Aggregate{Type: tt.agg}, tt.every, tt.offset, newFloatArrayCursor(n, ts, vs))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var gotTs []int64
+			var gotVs interface{}
+			switch cur := cur.(type) {
+			case cursors.FloatArrayCursor:
+				var fs []float64
+				for a := cur.Next(); a.Len() > 0; a = cur.Next() {
+					gotTs = append(gotTs, a.Timestamps...)
+					fs = append(fs, a.Values...)
+				}
+				gotVs = fs
+			case cursors.IntegerArrayCursor:
+				var is []int64
+				for a := cur.Next(); a.Len() > 0; a = cur.Next() {
+					gotTs = append(gotTs, a.Timestamps...)
+					is = append(is, a.Values...)
+				}
+				gotVs = is
+			default:
+				t.Fatalf("%s: unexpected cursor type %T", tt.agg, cur)
+			}
+
+			if !reflect.DeepEqual(gotTs, tt.expTs) {
+				t.Errorf("%s every=%d offset=%d n=%d: unexpected timestamps: got=%v exp=%v", tt.agg, tt.every, tt.offset, n, gotTs, tt.expTs)
+			}
+			if !reflect.DeepEqual(gotVs, tt.expVs) {
+				t.Errorf("%s every=%d offset=%d n=%d: unexpected values: got=%v exp=%v", tt.agg, tt.every, tt.offset, n, gotVs, tt.expVs)
+			}
+		}
+	}
+}
+
+// Ensures aggregates of numbers return an error for string and boolean fields
+// instead of skipping the series.
+func TestWindowAggregateArrayCursor_Unsupported(t *testing.T) {
+	type stringArrayCursor struct{ cursors.StringArrayCursor }
+	type booleanArrayCursor struct{ cursors.BooleanArrayCursor }
+
+	for _, agg := range []datatypes.Aggregate_AggregateType{
+		datatypes.AggregateTypeSum,
+		datatypes.AggregateTypeMin,
+		datatypes.AggregateTypeMax,
+		datatypes.AggregateTypeMean,
+	} {
+		for _, cur := range []cursors.Cursor{&stringArrayCursor{}, &booleanArrayCursor{}} {
+			if _, err := newWindowAggregateArrayCursor(context.Background(), &datatypes.Aggregate{Type: agg}, 10, 0, cur); err == nil {
+				t.Errorf("%s of %T: expected error", agg, cur)
+			}
+		}
+	}
+}
+
+func TestWindowStop(t *testing.T) {
+	for _, tt := range []struct {
+		ts, every, offset, exp int64
+	}{
+		{ts: 0, every: 10, exp: 10},
+		{ts: 9, every: 10, exp: 10},
+		{ts: 10, every: 10, exp: 20},
+		{ts: -1, every: 10, exp: 0},
+		{ts: -10, every: 10, exp: 0},
+		{ts: 4, every: 10, offset: 5, exp: 5},
+		{ts: 5, every: 10, offset: 5, exp: 15},
+		{ts: 5, every: 10, offset: -5, exp: 15},
+	} {
+		if got := windowStop(tt.ts, tt.every, tt.offset); got != tt.exp {
+			t.Errorf("windowStop(%d, %d, %d) = %d, exp %d", tt.ts, tt.every, tt.offset, got, tt.exp)
+		}
+	}
+}
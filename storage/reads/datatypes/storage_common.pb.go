@@ -99,18 +99,33 @@ const (
 	AggregateTypeNone  Aggregate_AggregateType = 0
 	AggregateTypeSum   Aggregate_AggregateType = 1
 	AggregateTypeCount Aggregate_AggregateType = 2
+	AggregateTypeMin   Aggregate_AggregateType = 3
+	AggregateTypeMax   Aggregate_AggregateType = 4
+	AggregateTypeFirst Aggregate_AggregateType = 5
+	AggregateTypeLast  Aggregate_AggregateType = 6
+	AggregateTypeMean  Aggregate_AggregateType = 7
 )
 
 var Aggregate_AggregateType_name = map[int32]string{
 	0: "NONE",
 	1: "SUM",
 	2: "COUNT",
+	3: "MIN",
+	4: "MAX",
+	5: "FIRST",
+	6: "LAST",
+	7: "MEAN",
 }
 
 var Aggregate_AggregateType_value = map[string]int32{
 	"NONE":  0,
 	"SUM":   1,
 	"COUNT": 2,
+	"MIN":   3,
+	"MAX":   4,
+	"FIRST": 5,
+	"LAST":  6,
+	"MEAN":  7,
 }
 
 func (x Aggregate_AggregateType) String() string {
@@ -1244,6 +1259,54 @@ func (m *StringValuesResponse) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_StringValuesResponse proto.InternalMessageInfo
 
+// ReadWindowAggregateRequest is the request message for Storage.ReadWindowAggregate.
+type ReadWindowAggregateRequest struct {
+	ReadSource  *types.Any     `protobuf:"bytes,1,opt,name=read_source,json=readSource,proto3" json:"read_source,omitempty"`
+	Range       TimestampRange `protobuf:"bytes,2,opt,name=range,proto3" json:"range"`
+	Predicate   *Predicate     `protobuf:"bytes,3,opt,name=predicate,proto3" json:"predicate,omitempty"`
+	WindowEvery int64          `protobuf:"varint,4,opt,name=window_every,json=windowEvery,proto3" json:"window_every,omitempty"`
+	Aggregate   []*Aggregate   `protobuf:"bytes,5,rep,name=aggregate,proto3" json:"aggregate,omitempty"`
+	Offset      int64          `protobuf:"varint,6,opt,name=offset,proto3" json:"offset,omitempty"`
+}
+
+func (m *ReadWindowAggregateRequest) Reset()         { *m = ReadWindowAggregateRequest{} }
+func (m *ReadWindowAggregateRequest) String() string { return proto.CompactTextString(m) }
+func (*ReadWindowAggregateRequest) ProtoMessage()    {}
+func (*ReadWindowAggregateRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_715e4bf4cdf1f73d, []int{10}
+}
+
+func (m *ReadWindowAggregateRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+
+func (m *ReadWindowAggregateRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_ReadWindowAggregateRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalTo(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+
+func (m *ReadWindowAggregateRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReadWindowAggregateRequest.Merge(m, src)
+}
+
+func (m *ReadWindowAggregateRequest) XXX_Size() int {
+	return m.Size()
+}
+
+func (m *ReadWindowAggregateRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReadWindowAggregateRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReadWindowAggregateRequest proto.InternalMessageInfo
+
 func init() {
 	proto.RegisterEnum("influxdata.platform.storage.ReadGroupRequest_Group", ReadGroupRequest_Group_name, ReadGroupRequest_Group_value)
 	proto.RegisterEnum("influxdata.platform.storage.ReadGroupRequest_HintFlags", ReadGroupRequest_HintFlags_name, ReadGroupRequest_HintFlags_value)
@@ -1269,106 +1332,115 @@ func init() {
 	proto.RegisterType((*TagKeysRequest)(nil), "influxdata.platform.storage.TagKeysRequest")
 	proto.RegisterType((*TagValuesRequest)(nil), "influxdata.platform.storage.TagValuesRequest")
 	proto.RegisterType((*StringValuesResponse)(nil), "influxdata.platform.storage.StringValuesResponse")
+	proto.RegisterType((*ReadWindowAggregateRequest)(nil), "influxdata.platform.storage.ReadWindowAggregateRequest")
 }
 
 func init() { proto.RegisterFile("storage_common.proto", fileDescriptor_715e4bf4cdf1f73d) }
 
 var fileDescriptor_715e4bf4cdf1f73d = []byte{
-	// 1498 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xdc, 0x57, 0xcb, 0x6f, 0xdb, 0xc8,
-	0x19, 0x17, 0xf5, 0x34, 0x3f, 0xc9, 0x32, 0x3d, 0x51, 0x5d, 0x87, 0x69, 0x24, 0x56, 0x28, 0x52,
-	0x17, 0x49, 0xe4, 0xd4, 0x49, 0xd1, 0x20, 0x6d, 0x0f, 0x96, 0x23, 0x5b, 0x6a, 0x6c, 0xc9, 0xa0,
-	0xe4, 0x00, 0xe9, 0x45, 0x18, 0xdb, 0x63, 0x86, 0x88, 0x44, 0xb2, 0x24, 0x15, 0x58, 0x40, 0x2f,
-	0xbd, 0x05, 0x3a, 0xb5, 0x97, 0x1e, 0x0a, 0x08, 0x58, 0x60, 0x8f, 0x7b, 0xdf, 0xbf, 0x21, 0x87,
-	0x05, 0x36, 0xc7, 0x3d, 0x09, 0xbb, 0x0a, 0xb0, 0xc0, 0xfe, 0x0b, 0x7b, 0x5a, 0xcc, 0x0c, 0x47,
-	0xa2, 0x6c, 0xc3, 0x96, 0xf6, 0xb4, 0xc8, 0x6d, 0xe6, 0x7b, 0xfc, 0xbe, 0x07, 0xbf, 0xc7, 0x10,
-	0x72, 0x9e, 0x6f, 0xbb, 0xd8, 0x20, 0xed, 0x13, 0xbb, 0xdb, 0xb5, 0xad, 0x92, 0xe3, 0xda, 0xbe,
-	0x8d, 0xee, 0x98, 0xd6, 0x59, 0xa7, 0x77, 0x7e, 0x8a, 0x7d, 0x5c, 0x72, 0x3a, 0xd8, 0x3f, 0xb3,
-	0xdd, 0x6e, 0x29, 0x90, 0x54, 0x73, 0x86, 0x6d, 0xd8, 0x4c, 0x6e, 0x93, 0x9e, 0xb8, 0x8a, 0x7a,
-	0xc7, 0xb0, 0x6d, 0xa3, 0x43, 0x36, 0xd9, 0xed, 0xb8, 0x77, 0xb6, 0x49, 0xba, 0x8e, 0xdf, 0x0f,
-	0x98, 0xb7, 0x2f, 0x32, 0xb1, 0x25, 0x58, 0x2b, 0x8e, 0x4b, 0x4e, 0xcd, 0x13, 0xec, 0x13, 0x4e,
-	0x28, 0xfe, 0x20, 0xc1, 0xaa, 0x4e, 0xf0, 0xe9, 0xae, 0xd9, 0xf1, 0x89, 0xab, 0x93, 0x7f, 0xf6,
-	0x88, 0xe7, 0xa3, 0x0a, 0xa4, 0x5d, 0x82, 0x4f, 0xdb, 0x9e, 0xdd, 0x73, 0x4f, 0xc8, 0xba, 0xa4,
-	0x49, 0x1b, 0xe9, 0xad, 0x5c, 0x89, 0xe3, 0x96, 0x04, 0x6e, 0x69, 0xdb, 0xea, 0x97, 0xb3, 0xe3,
-	0x51, 0x01, 0x28, 0x42, 0x93, 0xc9, 0xea, 0xe0, 0x4e, 0xce, 0x68, 0x0f, 0x12, 0x2e, 0xb6, 0x0c,
-	0xb2, 0x1e, 0x65, 0x00, 0xf7, 0x4b, 0xd7, 0x04, 0x5a, 0x6a, 0x99, 0x5d, 0xe2, 0xf9, 0xb8, 0xeb,
-	0xe8, 0x54, 0xa5, 0x1c, 0x7f, 0x3f, 0x2a, 0x44, 0x74, 0xae, 0x8f, 0x9e, 0x83, 0x3c, 0x71, 0x7c,
-	0x3d, 0xc6, 0xc0, 0xee, 0x5d, 0x0b, 0x76, 0x28, 0xa4, 0xf5, 0xa9, 0x62, 0xf1, 0xab, 0x04, 0x28,
-	0xd4, 0xd3, 0x3d, 0xd7, 0xee, 0x39, 0x9f, 0x74, 0xa8, 0xe8, 0x01, 0x80, 0x41, 0xa3, 0x6c, 0xbf,
-	0x21, 0x7d, 0x6f, 0x3d, 0xae, 0xc5, 0x36, 0xe4, 0xf2, 0xf2, 0x78, 0x54, 0x90, 0x59, 0xec, 0x2f,
-	0x48, 0xdf, 0xd3, 0x65, 0x43, 0x1c, 0x51, 0x0d, 0x12, 0xec, 0xb2, 0x9e, 0xd0, 0xa4, 0x8d, 0xec,
-	0xd6, 0xe3, 0x6b, 0xed, 0x5d, 0xcc, 0x60, 0x89, 0x5f, 0x38, 0x02, 0x75, 0x1f, 0x1b, 0x86, 0x4b,
-	0x0c, 0xea, 0x7e, 0x72, 0x0e, 0xf7, 0xb7, 0x85, 0xb4, 0x3e, 0x55, 0x44, 0x0f, 0x20, 0xf1, 0xda,
-	0xb4, 0x7c, 0x6f, 0x3d, 0xa5, 0x49, 0x1b, 0xa9, 0xf2, 0xda, 0x78, 0x54, 0x48, 0x54, 0x29, 0xe1,
-	0xc7, 0x51, 0x41, 0xa6, 0x87, 0xdd, 0x0e, 0x36, 0x3c, 0x9d, 0x0b, 0x15, 0xf7, 0x20, 0xc1, 0x7c,
-	0x40, 0x77, 0x01, 0xf6, 0xf4, 0xc6, 0xd1, 0x61, 0xbb, 0xde, 0xa8, 0x57, 0x94, 0x88, 0xba, 0x3c,
-	0x18, 0x6a, 0x3c, 0xe2, 0xba, 0x6d, 0x11, 0x74, 0x1b, 0x96, 0x38, 0xbb, 0xfc, 0x4a, 0x89, 0xaa,
-	0xe9, 0xc1, 0x50, 0x4b, 0x31, 0x66, 0xb9, 0xaf, 0xc6, 0xdf, 0x7d, 0x9e, 0x8f, 0x14, 0xbf, 0x90,
-	0x60, 0x8a, 0x8e, 0xee, 0x80, 0x5c, 0xad, 0xd5, 0x5b, 0x02, 0x2c, 0x33, 0x18, 0x6a, 0x4b, 0x94,
-	0xcb, 0xb0, 0x7e, 0x07, 0xd9, 0x80, 0xd9, 0x3e, 0x6c, 0xd4, 0xea, 0xad, 0xa6, 0x22, 0xa9, 0xca,
-	0x60, 0xa8, 0x65, 0xb8, 0xc4, 0xa1, 0x4d, 0x3d, 0x0b, 0x4b, 0x35, 0x2b, 0x7a, 0xad, 0xd2, 0x54,
-	0xa2, 0x61, 0xa9, 0x26, 0x71, 0x4d, 0xe2, 0xa1, 0x4d, 0xc8, 0x31, 0xa9, 0xe6, 0x4e, 0xb5, 0x72,
-	0xb0, 0xdd, 0xde, 0xde, 0xdf, 0x6f, 0xb7, 0x6a, 0x07, 0x15, 0x25, 0xae, 0xfe, 0x6a, 0x30, 0xd4,
-	0x56, 0xa9, 0x6c, 0xf3, 0xe4, 0x35, 0xe9, 0xe2, 0xed, 0x4e, 0x87, 0x96, 0x4e, 0xe0, 0xed, 0xd7,
-	0x12, 0xc8, 0x93, 0xec, 0xa1, 0x2a, 0xc4, 0xfd, 0xbe, 0xc3, 0x0b, 0x38, 0xbb, 0xf5, 0x64, 0xbe,
-	0x9c, 0x4f, 0x4f, 0xad, 0xbe, 0x43, 0x74, 0x86, 0x50, 0x3c, 0x87, 0xe5, 0x19, 0x32, 0x2a, 0x40,
-	0x3c, 0xc8, 0x01, 0xf3, 0x67, 0x86, 0xc9, 0x92, 0x71, 0x17, 0x62, 0xcd, 0xa3, 0x03, 0x45, 0x52,
-	0x73, 0x83, 0xa1, 0xa6, 0xcc, 0xf0, 0x9b, 0xbd, 0x2e, 0xfa, 0x2d, 0x24, 0x76, 0x1a, 0x47, 0xf5,
-	0x96, 0x12, 0x55, 0xd7, 0x06, 0x43, 0x0d, 0xcd, 0x08, 0xec, 0xd8, 0x3d, 0xcb, 0x0f, 0x22, 0x7a,
-	0x08, 0xb1, 0x16, 0x36, 0x90, 0x02, 0xb1, 0x37, 0xa4, 0xcf, 0x22, 0xc9, 0xe8, 0xf4, 0x88, 0x72,
-	0x90, 0x78, 0x8b, 0x3b, 0x3d, 0xde, 0x5d, 0x19, 0x9d, 0x5f, 0x8a, 0xff, 0xcd, 0x42, 0x86, 0x56,
-	0xa3, 0x4e, 0x3c, 0xc7, 0xb6, 0x3c, 0x82, 0x0e, 0x20, 0x79, 0xe6, 0xe2, 0x2e, 0xf1, 0xd6, 0x25,
-	0x2d, 0xb6, 0x91, 0xde, 0xda, 0xbc, 0xb1, 0x90, 0x85, 0x6a, 0x69, 0x97, 0xea, 0x05, 0x9d, 0x18,
-	0x80, 0xa8, 0xef, 0x92, 0x90, 0x60, 0x74, 0xb4, 0x2f, 0x1a, 0x24, 0xc5, 0x2a, 0xfa, 0xc9, 0xfc,
-	0xb8, 0xac, 0xc0, 0x18, 0x48, 0x35, 0x22, 0x7a, 0xa4, 0x01, 0x49, 0x8f, 0x7d, 0xf9, 0x60, 0xda,
-	0xfc, 0x69, 0x7e, 0x38, 0x5e, 0x31, 0x02, 0x2f, 0x80, 0x41, 0x0e, 0x64, 0xce, 0x3a, 0x36, 0xf6,
-	0xdb, 0x0e, 0x2b, 0xbb, 0x60, 0x06, 0x3d, 0x5b, 0x20, 0x7a, 0xaa, 0xcd, 0x6b, 0x96, 0x27, 0x62,
-	0x65, 0x3c, 0x2a, 0xa4, 0x43, 0xd4, 0x6a, 0x44, 0x4f, 0x9f, 0x4d, 0xaf, 0xe8, 0x1c, 0xb2, 0xa6,
-	0xe5, 0x13, 0x83, 0xb8, 0xc2, 0x26, 0x1f, 0x55, 0x7f, 0x9d, 0xdf, 0x66, 0x8d, 0xeb, 0x87, 0xad,
-	0xae, 0x8e, 0x47, 0x85, 0xe5, 0x19, 0x7a, 0x35, 0xa2, 0x2f, 0x9b, 0x61, 0x02, 0xfa, 0x17, 0xac,
-	0xf4, 0x2c, 0xcf, 0x34, 0x2c, 0x72, 0x2a, 0x4c, 0xc7, 0x99, 0xe9, 0xbf, 0xcd, 0x6f, 0xfa, 0x28,
-	0x00, 0x08, 0xdb, 0x46, 0xe3, 0x51, 0x21, 0x3b, 0xcb, 0xa8, 0x46, 0xf4, 0x6c, 0x6f, 0x86, 0x42,
-	0xe3, 0x3e, 0xb6, 0xed, 0x0e, 0xc1, 0x96, 0x30, 0x9e, 0x58, 0x34, 0xee, 0x32, 0xd7, 0xbf, 0x14,
-	0xf7, 0x0c, 0x9d, 0xc6, 0x7d, 0x1c, 0x26, 0x20, 0x1f, 0x96, 0x3d, 0xdf, 0x35, 0x2d, 0x43, 0x18,
-	0xe6, 0xc3, 0xf5, 0x2f, 0x0b, 0xd4, 0x0e, 0x53, 0x0f, 0xdb, 0x55, 0xc6, 0xa3, 0x42, 0x26, 0x4c,
-	0xae, 0x46, 0xf4, 0x8c, 0x17, 0xba, 0x97, 0x93, 0x10, 0xa7, 0xc8, 0xea, 0x39, 0xc0, 0xb4, 0x92,
-	0xd1, 0x3d, 0x58, 0xf2, 0xb1, 0xc1, 0x77, 0x0b, 0xed, 0xb4, 0x4c, 0x39, 0x3d, 0x1e, 0x15, 0x52,
-	0x2d, 0x6c, 0xb0, 0xcd, 0x92, 0xf2, 0xf9, 0x01, 0x95, 0x01, 0x39, 0xd8, 0xf5, 0x4d, 0xdf, 0xb4,
-	0x2d, 0x2a, 0xdd, 0x7e, 0x8b, 0x3b, 0xb4, 0x3a, 0xa9, 0x46, 0x6e, 0x3c, 0x2a, 0x28, 0x87, 0x82,
-	0xfb, 0x82, 0xf4, 0x5f, 0xe2, 0x8e, 0xa7, 0x2b, 0xce, 0x05, 0x8a, 0xfa, 0x7f, 0x09, 0xd2, 0xa1,
-	0xaa, 0x47, 0xcf, 0x20, 0xee, 0x63, 0x43, 0x74, 0xb8, 0x76, 0xfd, 0x9e, 0xc5, 0x46, 0xd0, 0xd2,
-	0x4c, 0x07, 0x35, 0x40, 0xa6, 0x82, 0x6d, 0x36, 0x28, 0xa3, 0x6c, 0x50, 0x6e, 0xcd, 0x9f, 0xbf,
-	0xe7, 0xd8, 0xc7, 0x6c, 0x4c, 0x2e, 0x9d, 0x06, 0x27, 0xf5, 0xef, 0xa0, 0x5c, 0x6c, 0x1d, 0x94,
-	0x07, 0xf0, 0xc5, 0x7e, 0xe7, 0x6e, 0x2a, 0x7a, 0x88, 0x82, 0xd6, 0x20, 0xc9, 0xc6, 0x17, 0x4f,
-	0x84, 0xa4, 0x07, 0x37, 0x75, 0x1f, 0xd0, 0xe5, 0x96, 0x58, 0x10, 0x2d, 0x36, 0x41, 0x3b, 0x80,
-	0x5b, 0x57, 0x54, 0xf9, 0x82, 0x70, 0xf1, 0xb0, 0x73, 0x97, 0xeb, 0x76, 0x41, 0xb4, 0xa5, 0x09,
-	0xda, 0x0b, 0x58, 0xbd, 0x54, 0x8c, 0x0b, 0x82, 0xc9, 0x02, 0xac, 0xd8, 0x04, 0x99, 0x01, 0x04,
-	0xab, 0x2a, 0x19, 0x2c, 0xda, 0x88, 0x7a, 0x6b, 0x30, 0xd4, 0x56, 0x26, 0xac, 0x60, 0xd7, 0x16,
-	0x20, 0x39, 0xd9, 0xd7, 0xb3, 0x02, 0xdc, 0x97, 0x60, 0x13, 0x7d, 0x29, 0xc1, 0x92, 0xf8, 0xde,
-	0xe8, 0x37, 0x90, 0xd8, 0xdd, 0x6f, 0x6c, 0xb7, 0x94, 0x88, 0xba, 0x3a, 0x18, 0x6a, 0xcb, 0x82,
-	0xc1, 0x3e, 0x3d, 0xd2, 0x20, 0x55, 0xab, 0xb7, 0x2a, 0x7b, 0x15, 0x5d, 0x40, 0x0a, 0x7e, 0xf0,
-	0x39, 0x51, 0x11, 0x96, 0x8e, 0xea, 0xcd, 0xda, 0x5e, 0xbd, 0xf2, 0x5c, 0x89, 0xf2, 0x1d, 0x29,
-	0x44, 0xc4, 0x37, 0xa2, 0x28, 0xe5, 0x46, 0x63, 0xbf, 0xb2, 0x5d, 0x57, 0x62, 0xb3, 0x28, 0x41,
-	0xde, 0x51, 0x1e, 0x92, 0xcd, 0x96, 0x5e, 0xab, 0xef, 0x29, 0x71, 0x15, 0x0d, 0x86, 0x5a, 0x56,
-	0x08, 0xf0, 0x54, 0x06, 0x8e, 0x7f, 0x26, 0x41, 0x6e, 0x07, 0x3b, 0xf8, 0xd8, 0xec, 0x98, 0xbe,
-	0x49, 0xbc, 0xc9, 0x6e, 0x6c, 0x40, 0xfc, 0x04, 0x3b, 0xa2, 0x6f, 0xae, 0x1f, 0x1b, 0x57, 0x01,
-	0x50, 0xa2, 0x57, 0xb1, 0x7c, 0xb7, 0xaf, 0x33, 0x20, 0xf5, 0xcf, 0x20, 0x4f, 0x48, 0xe1, 0x95,
-	0x2d, 0x5f, 0xb1, 0xb2, 0xe5, 0x60, 0x65, 0x3f, 0x8b, 0x3e, 0x95, 0x8a, 0x4f, 0x21, 0x3b, 0xfb,
-	0x00, 0xa6, 0xb2, 0x9e, 0x8f, 0x5d, 0x9f, 0xe9, 0xc7, 0x74, 0x7e, 0xa1, 0x98, 0xc4, 0x3a, 0x65,
-	0xfa, 0x31, 0x9d, 0x1e, 0x8b, 0xdf, 0x4b, 0x90, 0x15, 0x43, 0x66, 0xfa, 0x7c, 0xa7, 0xad, 0x3d,
-	0xf7, 0xf3, 0xbd, 0x85, 0x0d, 0x4f, 0x3c, 0xdf, 0xfd, 0xc9, 0xf9, 0x97, 0xf6, 0xa7, 0xf2, 0xef,
-	0x28, 0x28, 0x2d, 0x6c, 0xbc, 0x64, 0x15, 0xfe, 0x49, 0x87, 0x8a, 0x7e, 0x0d, 0xa9, 0x60, 0x97,
-	0xb0, 0x3d, 0x2e, 0xeb, 0x49, 0xbe, 0x3d, 0x8a, 0x25, 0xc8, 0xf1, 0xca, 0x16, 0x59, 0x08, 0x0a,
-	0x79, 0x3a, 0x07, 0xd8, 0xea, 0x11, 0x73, 0x60, 0xeb, 0x7f, 0x71, 0x48, 0x35, 0xb9, 0x25, 0x64,
-	0x02, 0x4c, 0x7f, 0x6a, 0x51, 0xe9, 0xc6, 0x19, 0x3f, 0xf3, 0xf7, 0xab, 0xfe, 0x61, 0xee, 0x9d,
-	0xf0, 0x48, 0x42, 0x06, 0xc8, 0x93, 0x3f, 0x22, 0xf4, 0x70, 0xa1, 0x3f, 0xa7, 0xc5, 0x0c, 0xbd,
-	0x01, 0xb1, 0x60, 0xd1, 0xfd, 0x9b, 0xb6, 0x5e, 0xa8, 0x43, 0xd4, 0x3f, 0x5e, 0x2b, 0x7c, 0x55,
-	0x8a, 0x1f, 0x49, 0xc8, 0x06, 0x79, 0x52, 0x7f, 0x37, 0x44, 0x75, 0xb1, 0x4e, 0x7f, 0x9e, 0xc1,
-	0x57, 0x90, 0x09, 0x4f, 0x1d, 0xb4, 0x76, 0xa9, 0xae, 0x2b, 0x5d, 0xc7, 0xef, 0xdf, 0x00, 0x7e,
-	0xd5, 0xe0, 0x2a, 0xff, 0xfe, 0xfd, 0x77, 0xf9, 0xc8, 0xfb, 0x71, 0x5e, 0xfa, 0x30, 0xce, 0x4b,
-	0xdf, 0x8e, 0xf3, 0xd2, 0x7f, 0x3e, 0xe6, 0x23, 0x1f, 0x3e, 0xe6, 0x23, 0xdf, 0x7c, 0xcc, 0x47,
-	0xfe, 0xc1, 0x5e, 0x04, 0xf4, 0x41, 0xe0, 0x1d, 0x27, 0x99, 0xad, 0xc7, 0x3f, 0x05, 0x00, 0x00,
-	0xff, 0xff, 0x7f, 0x0e, 0xbb, 0xbb, 0xa6, 0x11, 0x00, 0x00,
+	// 1629 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xdc, 0x58, 0xcf, 0x6f, 0x1b, 0x4f,
+	0x15, 0xf7, 0xfa, 0x67, 0xf6, 0xd9, 0x71, 0x36, 0x53, 0x13, 0xf2, 0xdd, 0x52, 0x7b, 0x6b, 0xa1,
+	0x12, 0xd4, 0xd6, 0x29, 0x69, 0x51, 0xab, 0x02, 0x07, 0x3b, 0x75, 0x62, 0x53, 0xff, 0x88, 0xd6,
+	0x4e, 0xa1, 0x5c, 0xac, 0x49, 0x3c, 0xde, 0xae, 0x6a, 0xef, 0x9a, 0xdd, 0x75, 0x1b, 0x0b, 0x2e,
+	0xdc, 0x2a, 0x9f, 0x40, 0xdc, 0x40, 0x96, 0x90, 0x38, 0x72, 0xe7, 0x6f, 0xe8, 0x81, 0x43, 0x8f,
+	0x9c, 0x2c, 0x70, 0x25, 0x24, 0xce, 0xbd, 0x71, 0x42, 0x33, 0xb3, 0x6b, 0xaf, 0x13, 0x2b, 0xb1,
+	0x39, 0xa1, 0xde, 0x66, 0xde, 0x8f, 0xcf, 0x9b, 0xf7, 0xf6, 0xfd, 0x98, 0x59, 0x48, 0xd9, 0x8e,
+	0x69, 0x61, 0x8d, 0xb4, 0xce, 0xcd, 0x5e, 0xcf, 0x34, 0x72, 0x7d, 0xcb, 0x74, 0x4c, 0x74, 0x5b,
+	0x37, 0x3a, 0xdd, 0xc1, 0x45, 0x1b, 0x3b, 0x38, 0xd7, 0xef, 0x62, 0xa7, 0x63, 0x5a, 0xbd, 0x9c,
+	0x2b, 0x29, 0xa7, 0x34, 0x53, 0x33, 0x99, 0xdc, 0x3e, 0x5d, 0x71, 0x15, 0xf9, 0xb6, 0x66, 0x9a,
+	0x5a, 0x97, 0xec, 0xb3, 0xdd, 0xd9, 0xa0, 0xb3, 0x4f, 0x7a, 0x7d, 0x67, 0xe8, 0x32, 0xbf, 0xb9,
+	0xcc, 0xc4, 0x86, 0xc7, 0xda, 0xea, 0x5b, 0xa4, 0xad, 0x9f, 0x63, 0x87, 0x70, 0x42, 0xf6, 0xdf,
+	0x02, 0x6c, 0xab, 0x04, 0xb7, 0x8f, 0xf4, 0xae, 0x43, 0x2c, 0x95, 0xfc, 0x72, 0x40, 0x6c, 0x07,
+	0x15, 0x21, 0x6e, 0x11, 0xdc, 0x6e, 0xd9, 0xe6, 0xc0, 0x3a, 0x27, 0xbb, 0x82, 0x22, 0xec, 0xc5,
+	0x0f, 0x52, 0x39, 0x8e, 0x9b, 0xf3, 0x70, 0x73, 0x79, 0x63, 0x58, 0x48, 0x4e, 0x27, 0x19, 0xa0,
+	0x08, 0x0d, 0x26, 0xab, 0x82, 0x35, 0x5b, 0xa3, 0x63, 0x88, 0x58, 0xd8, 0xd0, 0xc8, 0x6e, 0x90,
+	0x01, 0xdc, 0xcf, 0x5d, 0xe3, 0x68, 0xae, 0xa9, 0xf7, 0x88, 0xed, 0xe0, 0x5e, 0x5f, 0xa5, 0x2a,
+	0x85, 0xf0, 0xc7, 0x49, 0x26, 0xa0, 0x72, 0x7d, 0xf4, 0x02, 0xc4, 0xd9, 0xc1, 0x77, 0x43, 0x0c,
+	0xec, 0xde, 0xb5, 0x60, 0x27, 0x9e, 0xb4, 0x3a, 0x57, 0xcc, 0xfe, 0x2d, 0x02, 0x12, 0x3d, 0xe9,
+	0xb1, 0x65, 0x0e, 0xfa, 0x5f, 0xb5, 0xab, 0xe8, 0x01, 0x80, 0x46, 0xbd, 0x6c, 0xbd, 0x25, 0x43,
+	0x7b, 0x37, 0xac, 0x84, 0xf6, 0xc4, 0xc2, 0xe6, 0x74, 0x92, 0x11, 0x99, 0xef, 0x2f, 0xc9, 0xd0,
+	0x56, 0x45, 0xcd, 0x5b, 0xa2, 0x32, 0x44, 0xd8, 0x66, 0x37, 0xa2, 0x08, 0x7b, 0xc9, 0x83, 0xc7,
+	0xd7, 0xda, 0xbb, 0x1c, 0xc1, 0x1c, 0xdf, 0x70, 0x04, 0x7a, 0x7c, 0xac, 0x69, 0x16, 0xd1, 0xe8,
+	0xf1, 0xa3, 0x2b, 0x1c, 0x3f, 0xef, 0x49, 0xab, 0x73, 0x45, 0xf4, 0x00, 0x22, 0x6f, 0x74, 0xc3,
+	0xb1, 0x77, 0x63, 0x8a, 0xb0, 0x17, 0x2b, 0xec, 0x4c, 0x27, 0x99, 0x48, 0x89, 0x12, 0xfe, 0x33,
+	0xc9, 0x88, 0x74, 0x71, 0xd4, 0xc5, 0x9a, 0xad, 0x72, 0xa1, 0xec, 0x31, 0x44, 0xd8, 0x19, 0xd0,
+	0x1d, 0x80, 0x63, 0xb5, 0x7e, 0x7a, 0xd2, 0xaa, 0xd5, 0x6b, 0x45, 0x29, 0x20, 0x6f, 0x8e, 0xc6,
+	0x0a, 0xf7, 0xb8, 0x66, 0x1a, 0x04, 0x7d, 0x03, 0x1b, 0x9c, 0x5d, 0x78, 0x2d, 0x05, 0xe5, 0xf8,
+	0x68, 0xac, 0xc4, 0x18, 0xb3, 0x30, 0x94, 0xc3, 0x1f, 0xfe, 0x9c, 0x0e, 0x64, 0xff, 0x22, 0xc0,
+	0x1c, 0x1d, 0xdd, 0x06, 0xb1, 0x54, 0xae, 0x35, 0x3d, 0xb0, 0xc4, 0x68, 0xac, 0x6c, 0x50, 0x2e,
+	0xc3, 0xfa, 0x2e, 0x24, 0x5d, 0x66, 0xeb, 0xa4, 0x5e, 0xae, 0x35, 0x1b, 0x92, 0x20, 0x4b, 0xa3,
+	0xb1, 0x92, 0xe0, 0x12, 0x27, 0x26, 0x3d, 0x99, 0x5f, 0xaa, 0x51, 0x54, 0xcb, 0xc5, 0x86, 0x14,
+	0xf4, 0x4b, 0x35, 0x88, 0xa5, 0x13, 0x1b, 0xed, 0x43, 0x8a, 0x49, 0x35, 0x0e, 0x4b, 0xc5, 0x6a,
+	0xbe, 0x95, 0xaf, 0x54, 0x5a, 0xcd, 0x72, 0xb5, 0x28, 0x85, 0xe5, 0x6f, 0x8d, 0xc6, 0xca, 0x36,
+	0x95, 0x6d, 0x9c, 0xbf, 0x21, 0x3d, 0x9c, 0xef, 0x76, 0x69, 0xea, 0xb8, 0xa7, 0xfd, 0x12, 0x04,
+	0x71, 0x16, 0x3d, 0x54, 0x82, 0xb0, 0x33, 0xec, 0xf3, 0x04, 0x4e, 0x1e, 0x3c, 0x59, 0x2d, 0xe6,
+	0xf3, 0x55, 0x73, 0xd8, 0x27, 0x2a, 0x43, 0xc8, 0xfe, 0x31, 0x08, 0x9b, 0x0b, 0x74, 0x94, 0x81,
+	0xb0, 0x1b, 0x04, 0x76, 0xa0, 0x05, 0x26, 0x8b, 0xc6, 0x1d, 0x08, 0x35, 0x4e, 0xab, 0x92, 0x20,
+	0xa7, 0x46, 0x63, 0x45, 0x5a, 0xe0, 0x37, 0x06, 0x3d, 0x74, 0x17, 0x22, 0x87, 0xf5, 0xd3, 0x5a,
+	0x53, 0x0a, 0xca, 0x3b, 0xa3, 0xb1, 0x82, 0x16, 0x04, 0x0e, 0xcd, 0x81, 0xe1, 0x50, 0x84, 0x6a,
+	0xb9, 0x26, 0x85, 0x96, 0x20, 0x54, 0x75, 0x83, 0xb1, 0xf3, 0x3f, 0x97, 0xc2, 0xcb, 0xd8, 0xf8,
+	0x82, 0x1a, 0x38, 0x2a, 0xab, 0x8d, 0xa6, 0x14, 0x59, 0x62, 0xe0, 0x48, 0xb7, 0x6c, 0x87, 0xfa,
+	0x50, 0xc9, 0x37, 0x9a, 0x52, 0x74, 0x89, 0x0f, 0x15, 0xcc, 0x05, 0xaa, 0xc5, 0x7c, 0x4d, 0x8a,
+	0x2d, 0x11, 0xa8, 0x12, 0x6c, 0xb8, 0x51, 0x7f, 0x08, 0xa1, 0x26, 0xd6, 0x90, 0x04, 0xa1, 0xb7,
+	0x64, 0xc8, 0xa2, 0x9d, 0x50, 0xe9, 0x12, 0xa5, 0x20, 0xf2, 0x0e, 0x77, 0x07, 0xbc, 0x03, 0x24,
+	0x54, 0xbe, 0xc9, 0xfe, 0x2e, 0x09, 0x09, 0x5a, 0x31, 0x2a, 0xb1, 0xfb, 0xa6, 0x61, 0x13, 0x54,
+	0x85, 0x68, 0xc7, 0xc2, 0x3d, 0x62, 0xef, 0x0a, 0x4a, 0x68, 0x2f, 0x7e, 0xb0, 0x7f, 0x63, 0xb1,
+	0x79, 0xaa, 0xb9, 0x23, 0xaa, 0xe7, 0x76, 0x0b, 0x17, 0x44, 0xfe, 0x10, 0x85, 0x08, 0xa3, 0xa3,
+	0x8a, 0x57, 0xc4, 0x31, 0x56, 0x75, 0x4f, 0x56, 0xc7, 0x65, 0x45, 0xc0, 0x40, 0x4a, 0x01, 0xaf,
+	0x8e, 0xeb, 0x10, 0xb5, 0x59, 0x76, 0xba, 0x1d, 0xf1, 0x87, 0xab, 0xc3, 0xf1, 0xac, 0xf6, 0xf0,
+	0x5c, 0x18, 0xd4, 0x87, 0x44, 0xa7, 0x6b, 0x62, 0xa7, 0xd5, 0x67, 0xa5, 0xe1, 0xf6, 0xc9, 0xe7,
+	0x6b, 0x78, 0x4f, 0xb5, 0x79, 0x5d, 0xf1, 0x40, 0x6c, 0x4d, 0x27, 0x99, 0xb8, 0x8f, 0x5a, 0x0a,
+	0xa8, 0xf1, 0xce, 0x7c, 0x8b, 0x2e, 0x20, 0xa9, 0x1b, 0x0e, 0xd1, 0x88, 0xe5, 0xd9, 0xe4, 0xed,
+	0xf4, 0xc7, 0xab, 0xdb, 0x2c, 0x73, 0x7d, 0xbf, 0xd5, 0xed, 0xe9, 0x24, 0xb3, 0xb9, 0x40, 0x2f,
+	0x05, 0xd4, 0x4d, 0xdd, 0x4f, 0x40, 0xbf, 0x86, 0xad, 0x81, 0x61, 0xeb, 0x9a, 0x41, 0xda, 0x9e,
+	0xe9, 0x30, 0x33, 0xfd, 0x93, 0xd5, 0x4d, 0x9f, 0xba, 0x00, 0x7e, 0xdb, 0x68, 0x3a, 0xc9, 0x24,
+	0x17, 0x19, 0xa5, 0x80, 0x9a, 0x1c, 0x2c, 0x50, 0xa8, 0xdf, 0x67, 0xa6, 0xd9, 0x25, 0xd8, 0xf0,
+	0x8c, 0x47, 0xd6, 0xf5, 0xbb, 0xc0, 0xf5, 0xaf, 0xf8, 0xbd, 0x40, 0xa7, 0x7e, 0x9f, 0xf9, 0x09,
+	0xc8, 0x81, 0x4d, 0xdb, 0xb1, 0x74, 0x43, 0xf3, 0x0c, 0xf3, 0x01, 0xf0, 0xa3, 0x35, 0x72, 0x87,
+	0xa9, 0xfb, 0xed, 0x4a, 0xd3, 0x49, 0x26, 0xe1, 0x27, 0x97, 0x02, 0x6a, 0xc2, 0xf6, 0xed, 0x0b,
+	0x51, 0x08, 0x53, 0x64, 0xf9, 0x02, 0x60, 0x9e, 0xc9, 0xe8, 0x1e, 0x6c, 0x38, 0x58, 0xe3, 0xf3,
+	0x8f, 0x56, 0x5a, 0xa2, 0x10, 0x9f, 0x4e, 0x32, 0xb1, 0x26, 0xd6, 0xd8, 0xf4, 0x8b, 0x39, 0x7c,
+	0x81, 0x0a, 0x80, 0xfa, 0xd8, 0x72, 0x74, 0x47, 0x37, 0x0d, 0x2a, 0xdd, 0x7a, 0x87, 0xbb, 0x34,
+	0x3b, 0xa9, 0x46, 0x6a, 0x3a, 0xc9, 0x48, 0x27, 0x1e, 0xf7, 0x25, 0x19, 0xbe, 0xc2, 0x5d, 0x5b,
+	0x95, 0xfa, 0x97, 0x28, 0xf2, 0x1f, 0x04, 0x88, 0xfb, 0xb2, 0x1e, 0x3d, 0x87, 0xb0, 0x83, 0x35,
+	0xaf, 0xc2, 0x95, 0xeb, 0xef, 0x02, 0x58, 0x73, 0x4b, 0x9a, 0xe9, 0xa0, 0x3a, 0x88, 0x54, 0xb0,
+	0xc5, 0x9a, 0x79, 0x90, 0x35, 0xf3, 0x83, 0xd5, 0xe3, 0xf7, 0x02, 0x3b, 0x98, 0xb5, 0xf2, 0x8d,
+	0xb6, 0xbb, 0x92, 0x7f, 0x0a, 0xd2, 0xe5, 0xd2, 0x41, 0x69, 0x00, 0xc7, 0xbb, 0x83, 0xf0, 0x63,
+	0x4a, 0xaa, 0x8f, 0x82, 0x76, 0x20, 0xca, 0xda, 0x17, 0x0f, 0x84, 0xa0, 0xba, 0x3b, 0xb9, 0x02,
+	0xe8, 0x6a, 0x49, 0xac, 0x89, 0x16, 0x9a, 0xa1, 0x55, 0xe1, 0xd6, 0x92, 0x2c, 0x5f, 0x13, 0x2e,
+	0xec, 0x3f, 0xdc, 0xd5, 0xbc, 0x5d, 0x13, 0x6d, 0x63, 0x86, 0xf6, 0x12, 0xb6, 0xaf, 0x24, 0xe3,
+	0x9a, 0x60, 0xa2, 0x07, 0x96, 0x6d, 0x80, 0xc8, 0x00, 0xdc, 0x69, 0x1a, 0x75, 0x2f, 0x03, 0x01,
+	0xf9, 0xd6, 0x68, 0xac, 0x6c, 0xcd, 0x58, 0xee, 0x7d, 0x20, 0x03, 0xd1, 0xd9, 0x9d, 0x62, 0x51,
+	0x80, 0x9f, 0xc5, 0x9d, 0x44, 0x7f, 0x15, 0x60, 0xc3, 0xfb, 0xde, 0xe8, 0x3b, 0x10, 0x39, 0xaa,
+	0xd4, 0xf3, 0x4d, 0x29, 0x20, 0x6f, 0x8f, 0xc6, 0xca, 0xa6, 0xc7, 0x60, 0x9f, 0x1e, 0x29, 0x10,
+	0x2b, 0xd7, 0x9a, 0xc5, 0xe3, 0xa2, 0xea, 0x41, 0x7a, 0x7c, 0xf7, 0x73, 0xa2, 0x2c, 0x6c, 0x9c,
+	0xd6, 0x1a, 0xe5, 0xe3, 0x5a, 0xf1, 0x85, 0x14, 0xe4, 0x53, 0xd6, 0x13, 0xf1, 0xbe, 0x11, 0x45,
+	0x29, 0xd4, 0xeb, 0x15, 0x3a, 0x24, 0x43, 0x8b, 0x28, 0x6e, 0xdc, 0x51, 0x1a, 0xa2, 0x8d, 0xa6,
+	0x5a, 0xae, 0x1d, 0x4b, 0x61, 0x19, 0x8d, 0xc6, 0x4a, 0xd2, 0x13, 0xe0, 0xa1, 0x74, 0x0f, 0xfe,
+	0x27, 0x01, 0x52, 0x87, 0xb8, 0x8f, 0xcf, 0xf4, 0xae, 0xee, 0xe8, 0xc4, 0x9e, 0xcd, 0xc6, 0x3a,
+	0x84, 0xcf, 0x71, 0xdf, 0xab, 0x9b, 0xeb, 0xdb, 0xc6, 0x32, 0x00, 0x4a, 0xb4, 0x8b, 0x86, 0x63,
+	0x0d, 0x55, 0x06, 0x24, 0x3f, 0x05, 0x71, 0x46, 0xf2, 0x8f, 0x6c, 0x71, 0xc9, 0xc8, 0x16, 0xdd,
+	0x91, 0xfd, 0x3c, 0xf8, 0x4c, 0xc8, 0x3e, 0x83, 0xe4, 0xe2, 0x25, 0x9d, 0xca, 0xda, 0x0e, 0xb6,
+	0x1c, 0xa6, 0x1f, 0x52, 0xf9, 0x86, 0x62, 0x12, 0xa3, 0xcd, 0xf4, 0x43, 0x2a, 0x5d, 0x66, 0xff,
+	0x25, 0x40, 0xd2, 0x6b, 0x32, 0xf3, 0x27, 0x06, 0x2d, 0xed, 0x95, 0x9f, 0x18, 0x4d, 0xac, 0xd9,
+	0xde, 0x13, 0xc3, 0x99, 0xad, 0xff, 0xdf, 0x5e, 0x53, 0xbf, 0x09, 0x82, 0xd4, 0xc4, 0xda, 0x2b,
+	0x96, 0xe1, 0x5f, 0xb5, 0xab, 0xe8, 0xdb, 0x10, 0x73, 0x67, 0x09, 0x9b, 0xe3, 0xa2, 0x1a, 0xe5,
+	0xd3, 0x23, 0x9b, 0x83, 0x14, 0xcf, 0x6c, 0x2f, 0x0a, 0x6e, 0x22, 0xcf, 0xfb, 0x00, 0x1b, 0x3d,
+	0xb3, 0x3e, 0xf0, 0x25, 0x08, 0x32, 0xed, 0xd7, 0x3f, 0xd3, 0x8d, 0xb6, 0xf9, 0x7e, 0xfe, 0xf4,
+	0xf9, 0xaa, 0xdf, 0xa2, 0x77, 0x21, 0xf1, 0x9e, 0xf9, 0xdb, 0x22, 0xef, 0x88, 0xc5, 0x43, 0x18,
+	0x52, 0xe3, 0x9c, 0x56, 0xa4, 0xa4, 0xc5, 0x57, 0x63, 0x44, 0x09, 0xdd, 0x68, 0x68, 0xe9, 0xab,
+	0x71, 0x07, 0xa2, 0x66, 0xa7, 0x63, 0x13, 0x87, 0xdd, 0x3b, 0x42, 0xaa, 0xbb, 0x3b, 0xf8, 0x7d,
+	0x04, 0x62, 0x0d, 0xae, 0x88, 0x74, 0x80, 0xf9, 0xef, 0x0e, 0x94, 0xbb, 0x71, 0xb2, 0x2e, 0xfc,
+	0x17, 0x91, 0xbf, 0xbf, 0xf2, 0x24, 0x7e, 0x24, 0x20, 0x0d, 0xc4, 0xd9, 0x5b, 0x19, 0x3d, 0x5c,
+	0xeb, 0x4d, 0xbd, 0x9e, 0xa1, 0xb7, 0xe0, 0x5d, 0x6b, 0xd0, 0xfd, 0x9b, 0xee, 0x1a, 0xbe, 0xbe,
+	0x24, 0xff, 0xe0, 0x5a, 0xe1, 0x65, 0x89, 0xfd, 0x48, 0x40, 0x26, 0x88, 0xb3, 0xaa, 0xbf, 0xc1,
+	0xab, 0xcb, 0xdd, 0xe1, 0x7f, 0x33, 0xf8, 0x1a, 0x12, 0xfe, 0x5e, 0x8f, 0x76, 0xae, 0xd4, 0x43,
+	0x91, 0xfe, 0xfb, 0xba, 0x01, 0x7c, 0xe9, 0xbc, 0xf9, 0x15, 0xdc, 0x5a, 0x52, 0x8d, 0xe8, 0xe9,
+	0x8d, 0xc1, 0x5f, 0x5e, 0xbf, 0x6b, 0x7d, 0xb5, 0xc2, 0xf7, 0x3e, 0xfe, 0x33, 0x1d, 0xf8, 0x38,
+	0x4d, 0x0b, 0x9f, 0xa6, 0x69, 0xe1, 0x1f, 0xd3, 0xb4, 0xf0, 0xdb, 0xcf, 0xe9, 0xc0, 0xa7, 0xcf,
+	0xe9, 0xc0, 0xdf, 0x3f, 0xa7, 0x03, 0xbf, 0x60, 0x97, 0x40, 0x7a, 0x07, 0xb4, 0xcf, 0xa2, 0xcc,
+	0xd1, 0xc7, 0xff, 0x1d, 0x00, 0x90, 0x4d, 0xb3, 0x1f, 0x3d, 0x14, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -1395,6 +1467,8 @@ type StorageClient interface {
 	TagValues(ctx context.Context, in *TagValuesRequest, opts ...grpc.CallOption) (Storage_TagValuesClient, error)
 	// Capabilities returns a map of keys and values identifying the capabilities supported by the storage engine
 	Capabilities(ctx context.Context, in *types.Empty, opts ...grpc.CallOption) (*CapabilitiesResponse, error)
+	// ReadWindowAggregate performs a windowed aggregate operation at storage
+	ReadWindowAggregate(ctx context.Context, in *ReadWindowAggregateRequest, opts ...grpc.CallOption) (Storage_ReadWindowAggregateClient, error)
 }
 
 type storageClient struct {
@@ -1542,6 +1616,38 @@ func (c *storageClient) Capabilities(ctx context.Context, in *types.Empty, opts
 	return out, nil
 }
 
+func (c *storageClient) ReadWindowAggregate(ctx context.Context, in *ReadWindowAggregateRequest, opts ...grpc.CallOption) (Storage_ReadWindowAggregateClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Storage_serviceDesc.Streams[4], "/influxdata.platform.storage.Storage/ReadWindowAggregate", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &storageReadWindowAggregateClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Storage_ReadWindowAggregateClient interface {
+	Recv() (*ReadResponse, error)
+	grpc.ClientStream
+}
+
+type storageReadWindowAggregateClient struct {
+	grpc.ClientStream
+}
+
+func (x *storageReadWindowAggregateClient) Recv() (*ReadResponse, error) {
+	m := new(ReadResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // StorageServer is the server API for Storage service.
 type StorageServer interface {
 	// ReadFilter performs a filter operation at storage
@@ -1554,6 +1660,8 @@ type StorageServer interface {
 	TagValues(*TagValuesRequest, Storage_TagValuesServer) error
 	// Capabilities returns a map of keys and values identifying the capabilities supported by the storage engine
 	Capabilities(context.Context, *types.Empty) (*CapabilitiesResponse, error)
+	// ReadWindowAggregate performs a windowed aggregate operation at storage
+	ReadWindowAggregate(*ReadWindowAggregateRequest, Storage_ReadWindowAggregateServer) error
 }
 
 func RegisterStorageServer(s *grpc.Server, srv StorageServer) {
@@ -1662,6 +1770,27 @@ func _Storage_Capabilities_Handler(srv interface{}, ctx context.Context, dec fun
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Storage_ReadWindowAggregate_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ReadWindowAggregateRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(StorageServer).ReadWindowAggregate(m, &storageReadWindowAggregateServer{stream})
+}
+
+type Storage_ReadWindowAggregateServer interface {
+	Send(*ReadResponse) error
+	grpc.ServerStream
+}
+
+type storageReadWindowAggregateServer struct {
+	grpc.ServerStream
+}
+
+func (x *storageReadWindowAggregateServer) Send(m *ReadResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 var _Storage_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "influxdata.platform.storage.Storage",
 	HandlerType: (*StorageServer)(nil),
@@ -1692,6 +1821,11 @@ var _Storage_serviceDesc = grpc.ServiceDesc{
 			Handler:       _Storage_TagValues_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "ReadWindowAggregate",
+			Handler:       _Storage_ReadWindowAggregate_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "storage_common.proto",
 }
@@ -2501,6 +2635,74 @@ func (m *StringValuesResponse) MarshalTo(dAtA []byte) (int, error) {
 	return i, nil
 }
 
+func (m *ReadWindowAggregateRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ReadWindowAggregateRequest) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.ReadSource != nil {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintStorageCommon(dAtA, i, uint64(m.ReadSource.Size()))
+		n27, err := m.ReadSource.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n27
+	}
+	dAtA[i] = 0x12
+	i++
+	i = encodeVarintStorageCommon(dAtA, i, uint64(m.Range.Size()))
+	n28, err := m.Range.MarshalTo(dAtA[i:])
+	if err != nil {
+		return 0, err
+	}
+	i += n28
+	if m.Predicate != nil {
+		dAtA[i] = 0x1a
+		i++
+		i = encodeVarintStorageCommon(dAtA, i, uint64(m.Predicate.Size()))
+		n29, err := m.Predicate.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n29
+	}
+	if m.WindowEvery != 0 {
+		dAtA[i] = 0x20
+		i++
+		i = encodeVarintStorageCommon(dAtA, i, uint64(m.WindowEvery))
+	}
+	if len(m.Aggregate) > 0 {
+		for _, msg := range m.Aggregate {
+			dAtA[i] = 0x2a
+			i++
+			i = encodeVarintStorageCommon(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	if m.Offset != 0 {
+		dAtA[i] = 0x30
+		i++
+		i = encodeVarintStorageCommon(dAtA, i, uint64(m.Offset))
+	}
+	return i, nil
+}
+
 func encodeVarintStorageCommon(dAtA []byte, offset int, v uint64) int {
 	for v >= 1<<7 {
 		dAtA[offset] = uint8(v&0x7f | 0x80)
@@ -2926,6 +3128,37 @@ func (m *StringValuesResponse) Size() (n int) {
 	return n
 }
 
+func (m *ReadWindowAggregateRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.ReadSource != nil {
+		l = m.ReadSource.Size()
+		n += 1 + l + sovStorageCommon(uint64(l))
+	}
+	l = m.Range.Size()
+	n += 1 + l + sovStorageCommon(uint64(l))
+	if m.Predicate != nil {
+		l = m.Predicate.Size()
+		n += 1 + l + sovStorageCommon(uint64(l))
+	}
+	if m.WindowEvery != 0 {
+		n += 1 + sovStorageCommon(uint64(m.WindowEvery))
+	}
+	if len(m.Aggregate) > 0 {
+		for _, e := range m.Aggregate {
+			l = e.Size()
+			n += 1 + l + sovStorageCommon(uint64(l))
+		}
+	}
+	if m.Offset != 0 {
+		n += 1 + sovStorageCommon(uint64(m.Offset))
+	}
+	return n
+}
+
 func sovStorageCommon(x uint64) (n int) {
 	for {
 		n++
@@ -5710,6 +5943,237 @@ func (m *StringValuesResponse) Unmarshal(dAtA []byte) error {
 	return nil
 }
 
+func (m *ReadWindowAggregateRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowStorageCommon
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ReadWindowAggregateRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ReadWindowAggregateRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ReadSource", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStorageCommon
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthStorageCommon
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthStorageCommon
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.ReadSource == nil {
+				m.ReadSource = &types.Any{}
+			}
+			if err := m.ReadSource.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Range", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStorageCommon
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthStorageCommon
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthStorageCommon
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if err := m.Range.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Predicate", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStorageCommon
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthStorageCommon
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthStorageCommon
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Predicate == nil {
+				m.Predicate = &Predicate{}
+			}
+			if err := m.Predicate.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field WindowEvery", wireType)
+			}
+			m.WindowEvery = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStorageCommon
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.WindowEvery |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Aggregate", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStorageCommon
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthStorageCommon
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthStorageCommon
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Aggregate = append(m.Aggregate, &Aggregate{})
+			if err := m.Aggregate[len(m.Aggregate)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Offset", wireType)
+			}
+			m.Offset = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStorageCommon
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Offset |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipStorageCommon(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthStorageCommon
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthStorageCommon
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+
 func skipStorageCommon(dAtA []byte) (n int, err error) {
 	l := len(dAtA)
 	iNdEx := 0
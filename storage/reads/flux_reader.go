@@ -51,6 +51,15 @@ func (r *storeReader) ReadGroup(ctx context.Context, spec influxdb.ReadGroupSpec
 	}, nil
 }
 
+func (r *storeReader) ReadWindowAggregate(ctx context.Context, spec influxdb.ReadWindowAggregateSpec, alloc *memory.Allocator) (influxdb.TableIterator, error) {
+	return &windowAggregateIterator{
+		ctx:   ctx,
+		s:     r.s,
+		spec:  spec,
+		alloc: alloc,
+	}, nil
+}
+
 func (r *storeReader) ReadTagKeys(ctx context.Context, spec influxdb.ReadTagKeysSpec, alloc *memory.Allocator) (influxdb.TableIterator, error) {
 	var predicate *datatypes.Predicate
 	if spec.Predicate != nil {
@@ -363,6 +372,208 @@ READ:
 	return rs.Err()
 }
 
+// windowAggregateIterator produces a table for each window of each series,
+// holding the single row of that window's aggregate.
+type windowAggregateIterator struct {
+	ctx   context.Context
+	s     Store
+	spec  influxdb.ReadWindowAggregateSpec
+	stats cursors.CursorStats
+	alloc *memory.Allocator
+}
+
+func (wai *windowAggregateIterator) Statistics() cursors.CursorStats { return wai.stats }
+
+func (wai *windowAggregateIterator) Do(f func(flux.Table) error) error {
+	src := wai.s.GetSource(
+		wai.spec.Database,
+		wai.spec.RetentionPolicy,
+	)
+
+	// Setup read request
+	any, err := types.MarshalAny(src)
+	if err != nil {
+		return err
+	}
+
+	var predicate *datatypes.Predicate
+	if wai.spec.Predicate != nil {
+		p, err := toStoragePredicate(wai.spec.Predicate)
+		if err != nil {
+			return err
+		}
+		predicate = p
+	}
+
+	agg, err := determineAggregateMethod(wai.spec.AggregateMethod)
+	if err != nil {
+		return err
+	}
+
+	var req datatypes.ReadWindowAggregateRequest
+	req.ReadSource = any
+	req.Predicate = predicate
+	req.Range.Start = int64(wai.spec.Bounds.Start)
+	req.Range.End = int64(wai.spec.Bounds.Stop)
+	req.WindowEvery = wai.spec.WindowEvery
+	req.Offset = wai.spec.Offset
+	req.Aggregate = []*datatypes.Aggregate{{Type: agg}}
+
+	rs, err := wai.s.WindowAggregate(wai.ctx, &req)
+	if err != nil {
+		return err
+	}
+
+	if rs == nil {
+		return nil
+	}
+	return wai.handleRead(f, rs, agg)
+}
+
+func (wai *windowAggregateIterator) handleRead(f func(flux.Table) error, rs ResultSet, agg datatypes.Aggregate_AggregateType) error {
+	defer rs.Close()
+
+	// Selectors retain the time of the selected point, whereas aggregates
+	// are timestamped with the stop of their window.
+	selector := agg == datatypes.AggregateTypeMin || agg == datatypes.AggregateTypeMax ||
+		agg == datatypes.AggregateTypeFirst || agg == datatypes.AggregateTypeLast
+
+	for rs.Next() {
+		cur := rs.Cursor()
+		if cur == nil {
+			// no data for series key + field combination
+			continue
+		}
+
+		tags := rs.Tags()
+		err := eachPoint(cur, func(ts int64, v values.Value) error {
+			if err := wai.ctx.Err(); err != nil {
+				return err
+			}
+			stop := ts
+			if selector {
+				stop = windowStop(ts, wai.spec.WindowEvery, wai.spec.Offset)
+			}
+			tbl, err := wai.newTable(tags, stop, ts, v, selector)
+			if err != nil {
+				return err
+			}
+			return f(tbl)
+		})
+
+		stats := cur.Stats()
+		wai.stats.ScannedValues += stats.ScannedValues
+		wai.stats.ScannedBytes += stats.ScannedBytes
+		cur.Close()
+
+		if err != nil {
+			return err
+		}
+	}
+	return rs.Err()
+}
+
+// newTable returns a table holding the single aggregate value v of the
+// window of series tags ending at stop. The window is clamped to the bounds
+// of the read.
+func (wai *windowAggregateIterator) newTable(tags models.Tags, stop, ts int64, v values.Value, selector bool) (flux.Table, error) {
+	bnds := execute.Bounds{
+		Start: execute.Time(stop - wai.spec.WindowEvery),
+		Stop:  execute.Time(stop),
+	}
+	if bnds.Start < wai.spec.Bounds.Start {
+		bnds.Start = wai.spec.Bounds.Start
+	}
+	if bnds.Stop > wai.spec.Bounds.Stop {
+		bnds.Stop = wai.spec.Bounds.Stop
+	}
+
+	key := defaultGroupKeyForSeries(tags, bnds)
+	b := execute.NewColListTableBuilder(key, wai.alloc)
+	if selector {
+		cols, _ := determineTableColsForSeries(tags, flux.ColumnType(v.Type()))
+		for _, c := range cols {
+			if _, err := b.AddCol(c); err != nil {
+				return nil, err
+			}
+		}
+		if err := execute.AppendKeyValues(key, b); err != nil {
+			return nil, err
+		}
+		if err := b.AppendTime(timeColIdx, execute.Time(ts)); err != nil {
+			return nil, err
+		}
+		if err := b.AppendValue(valueColIdx, v); err != nil {
+			return nil, err
+		}
+		return b.Table()
+	}
+
+	if err := execute.AddTableKeyCols(key, b); err != nil {
+		return nil, err
+	}
+	j, err := b.AddCol(flux.ColMeta{Label: execute.DefaultValueColLabel, Type: flux.ColumnType(v.Type())})
+	if err != nil {
+		return nil, err
+	}
+	if err := execute.AppendKeyValues(key, b); err != nil {
+		return nil, err
+	}
+	if err := b.AppendValue(j, v); err != nil {
+		return nil, err
+	}
+	return b.Table()
+}
+
+// eachPoint calls fn for each point read from cur.
+func eachPoint(cur cursors.Cursor, fn func(ts int64, v values.Value) error) error {
+	switch cur := cur.(type) {
+	case cursors.FloatArrayCursor:
+		for a := cur.Next(); a.Len() > 0; a = cur.Next() {
+			for i, ts := range a.Timestamps {
+				if err := fn(ts, values.NewFloat(a.Values[i])); err != nil {
+					return err
+				}
+			}
+		}
+	case cursors.IntegerArrayCursor:
+		for a := cur.Next(); a.Len() > 0; a = cur.Next() {
+			for i, ts := range a.Timestamps {
+				if err := fn(ts, values.NewInt(a.Values[i])); err != nil {
+					return err
+				}
+			}
+		}
+	case cursors.UnsignedArrayCursor:
+		for a := cur.Next(); a.Len() > 0; a = cur.Next() {
+			for i, ts := range a.Timestamps {
+				if err := fn(ts, values.NewUInt(a.Values[i])); err != nil {
+					return err
+				}
+			}
+		}
+	case cursors.StringArrayCursor:
+		for a := cur.Next(); a.Len() > 0; a = cur.Next() {
+			for i, ts := range a.Timestamps {
+				if err := fn(ts, values.NewString(a.Values[i])); err != nil {
+					return err
+				}
+			}
+		}
+	case cursors.BooleanArrayCursor:
+		for a := cur.Next(); a.Len() > 0; a = cur.Next() {
+			for i, ts := range a.Timestamps {
+				if err := fn(ts, values.NewBool(a.Values[i])); err != nil {
+					return err
+				}
+			}
+		}
+	default:
+		panic(fmt.Sprintf("unreachable: %T", cur))
+	}
+	return cur.Err()
+}
+
 func determineAggregateMethod(agg string) (datatypes.Aggregate_AggregateType, error) {
 	if agg == "" {
 		return datatypes.AggregateTypeNone, nil
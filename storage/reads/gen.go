@@ -1 +1,3 @@
 package reads
+
+//go:generate tmpl -data=@array_cursor.gen.go.tmpldata array_cursor.gen.go.tmpl
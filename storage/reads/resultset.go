@@ -2,6 +2,8 @@ package reads
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"math"
 
 	"github.com/ayang64/reflux/models"
@@ -30,6 +32,35 @@ func NewFilteredResultSet(ctx context.Context, req *datatypes.ReadFilterRequest,
 	}
 }
 
+// NewWindowAggregateResultSet returns a ResultSet that evaluates the
+// aggregate of req over windows of req.WindowEvery for each series.
+func NewWindowAggregateResultSet(ctx context.Context, req *datatypes.ReadWindowAggregateRequest, cur SeriesCursor) (ResultSet, error) {
+	if len(req.Aggregate) != 1 {
+		return nil, errors.New("window aggregate requires exactly one aggregate")
+	}
+	if req.WindowEvery < 0 {
+		return nil, errors.New("window every cannot be negative")
+	}
+	switch req.Aggregate[0].Type {
+	case datatypes.AggregateTypeCount, datatypes.AggregateTypeSum, datatypes.AggregateTypeMean,
+		datatypes.AggregateTypeMin, datatypes.AggregateTypeMax,
+		datatypes.AggregateTypeFirst, datatypes.AggregateTypeLast:
+	default:
+		return nil, fmt.Errorf("unsupported window aggregate %s", req.Aggregate[0].Type)
+	}
+
+	return &windowAggregateResultSet{
+		resultSet: resultSet{
+			ctx: ctx,
+			agg: req.Aggregate[0],
+			cur: cur,
+			mb:  newMultiShardArrayCursors(ctx, req.Range.Start, req.Range.End, true, math.MaxInt64),
+		},
+		every:  req.WindowEvery,
+		offset: req.Offset,
+	}, nil
+}
+
 func (r *resultSet) Err() error { return nil }
 
 // Close closes the result set. Close is idempotent.
@@ -65,6 +96,31 @@ func (r *resultSet) Cursor() cursors.Cursor {
 	return cur
 }
 
+// windowAggregateResultSet is a resultSet that aggregates each cursor over
+// fixed windows of time. It stops at the first series whose field the
+// aggregate cannot be evaluated over, and Err returns why.
+type windowAggregateResultSet struct {
+	resultSet
+	every  int64
+	offset int64
+	err    error
+}
+
+func (r *windowAggregateResultSet) Err() error { return r.err }
+
+func (r *windowAggregateResultSet) Next() bool {
+	return r.err == nil && r.resultSet.Next()
+}
+
+func (r *windowAggregateResultSet) Cursor() cursors.Cursor {
+	cur, err := newWindowAggregateArrayCursor(r.ctx, r.agg, r.every, r.offset, r.mb.createCursor(r.row))
+	if err != nil {
+		r.err = err
+		return nil
+	}
+	return cur
+}
+
 func (r *resultSet) Tags() models.Tags {
 	return r.row.Tags
 }
@@ -3,11 +3,14 @@ package task
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strings"
 	"sync"
 	"time"
 )
 
+// Manager tracks long-running background work and allows it to be listed and
+// killed.
 type Manager struct {
 	mu sync.RWMutex
 	id int
@@ -15,20 +18,34 @@ type Manager struct {
 }
 
 func (m *Manager) String() string {
-	m.mu.Lock()
-	defer m.mu.Unlock()
-
 	b := &strings.Builder{}
 
 	fmt.Fprintf(b, "%s\t%s\t%s\n", "task id", "duration", "name")
 	fmt.Fprintf(b, "%s\t%s\t%s\n", "=======", "========", "====")
-	for id, t := range m.m {
-		fmt.Fprintf(b, "%d\t%s\t%s\n", id, time.Since(t.Start), t.Name)
+	for _, t := range m.Tasks() {
+		fmt.Fprintf(b, "%d\t%s\t%s\n", t.ID, time.Since(t.Start), t.Name)
 	}
 
 	return b.String()
 }
 
+// Tasks returns a snapshot of the running tasks ordered by ID.
+func (m *Manager) Tasks() []Task {
+	if m == nil {
+		return nil
+	}
+
+	m.mu.RLock()
+	tasks := make([]Task, 0, len(m.m))
+	for _, t := range m.m {
+		tasks = append(tasks, *t)
+	}
+	m.mu.RUnlock()
+
+	sort.Slice(tasks, func(i, j int) bool { return tasks[i].ID < tasks[j].ID })
+	return tasks
+}
+
 func NewManager(opts ...func(*Manager) error) (*Manager, error) {
 	m := Manager{
 		m: map[int]*Task{},
@@ -67,7 +84,12 @@ func (m *Manager) getTask(id int) (*Task, error) {
 	return t, nil
 }
 
+// Kill cancels the context of the running task with the given id.
 func (m *Manager) Kill(id int) error {
+	if m == nil {
+		return fmt.Errorf("could not kill task: no such task id %d", id)
+	}
+
 	t, err := m.getTask(id)
 	if err != nil {
 		return fmt.Errorf("could not kill task: %w", err)
@@ -78,15 +100,22 @@ func (m *Manager) Kill(id int) error {
 	return nil
 }
 
+// Run registers a task with the given name and calls f with a context that is
+// canceled when the task is killed. The task is removed once f returns. If m is
+// nil, f is called without being tracked.
 func (m *Manager) Run(ctx context.Context, name string, f func(context.Context) error) error {
-	return func() error {
-		t := m.NewTask(name)
-		m.addTask(t)
+	if m == nil {
+		return f(ctx)
+	}
+
+	t := m.NewTask(name)
+	ctx = t.init(ctx)
+	defer t.cancel()
 
-		defer m.removeTask(t)
+	m.addTask(t)
+	defer m.removeTask(t)
 
-		return t.run(ctx, f)
-	}()
+	return f(ctx)
 }
 
 func (m *Manager) removeTask(t *Task) error {
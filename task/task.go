@@ -5,6 +5,7 @@ import (
 	"time"
 )
 
+// Task is a unit of background work registered with a Manager.
 type Task struct {
 	ID     int
 	Name   string
@@ -12,9 +13,10 @@ type Task struct {
 	cancel func()
 }
 
-func (t *Task) run(ctx context.Context, f func(context.Context) error) error {
+// init prepares t to run and returns the context the task should observe.
+func (t *Task) init(ctx context.Context) context.Context {
 	ctx, cancel := context.WithCancel(ctx)
 	t.cancel = cancel
 	t.Start = time.Now()
-	return f(ctx)
+	return ctx
 }
@@ -52,3 +52,39 @@ func TestTask(t *testing.T) {
 		}
 	}
 }
+
+func TestManager_Kill(t *testing.T) {
+	mgr, err := NewManager()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	started, errCh := make(chan struct{}), make(chan error)
+	go func() {
+		errCh <- mgr.Run(context.Background(), "runaway", func(ctx context.Context) error {
+			close(started)
+			<-ctx.Done()
+			return ctx.Err()
+		})
+	}()
+	<-started
+
+	tasks := mgr.Tasks()
+	if len(tasks) != 1 || tasks[0].Name != "runaway" {
+		t.Fatalf("unexpected tasks: %+v", tasks)
+	}
+
+	if err := mgr.Kill(tasks[0].ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := <-errCh; err != context.Canceled {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if tasks := mgr.Tasks(); len(tasks) != 0 {
+		t.Fatalf("unexpected tasks after kill: %+v", tasks)
+	}
+	if err := mgr.Kill(tasks[0].ID); err == nil {
+		t.Fatal("expected error killing finished task")
+	}
+}
@@ -456,7 +456,7 @@ func init() {
 			{
 				name:    "show retention policy should succeed",
 				command: `SHOW RETENTION POLICIES ON db0`,
-				exp:     `{"results":[{"statement_id":0,"series":[{"columns":["name","duration","shardGroupDuration","replicaN","default"],"values":[["rp0","1h0m0s","1h0m0s",1,false]]}]}]}`,
+				exp:     `{"results":[{"statement_id":0,"series":[{"columns":["name","duration","shardGroupDuration","replicaN","default","fieldTypePolicy"],"values":[["rp0","1h0m0s","1h0m0s",1,false,"strict"]]}]}]}`,
 			},
 			{
 				name:    "alter retention policy should succeed",
@@ -467,12 +467,12 @@ func init() {
 			{
 				name:    "show retention policy should have new altered information",
 				command: `SHOW RETENTION POLICIES ON db0`,
-				exp:     `{"results":[{"statement_id":0,"series":[{"columns":["name","duration","shardGroupDuration","replicaN","default"],"values":[["rp0","2h0m0s","1h0m0s",3,true]]}]}]}`,
+				exp:     `{"results":[{"statement_id":0,"series":[{"columns":["name","duration","shardGroupDuration","replicaN","default","fieldTypePolicy"],"values":[["rp0","2h0m0s","1h0m0s",3,true,"strict"]]}]}]}`,
 			},
 			{
 				name:    "show retention policy should still show policy",
 				command: `SHOW RETENTION POLICIES ON db0`,
-				exp:     `{"results":[{"statement_id":0,"series":[{"columns":["name","duration","shardGroupDuration","replicaN","default"],"values":[["rp0","2h0m0s","1h0m0s",3,true]]}]}]}`,
+				exp:     `{"results":[{"statement_id":0,"series":[{"columns":["name","duration","shardGroupDuration","replicaN","default","fieldTypePolicy"],"values":[["rp0","2h0m0s","1h0m0s",3,true,"strict"]]}]}]}`,
 			},
 			{
 				name:    "create a second non-default retention policy",
@@ -483,7 +483,7 @@ func init() {
 			{
 				name:    "show retention policy should show both",
 				command: `SHOW RETENTION POLICIES ON db0`,
-				exp:     `{"results":[{"statement_id":0,"series":[{"columns":["name","duration","shardGroupDuration","replicaN","default"],"values":[["rp0","2h0m0s","1h0m0s",3,true],["rp2","1h0m0s","1h0m0s",1,false]]}]}]}`,
+				exp:     `{"results":[{"statement_id":0,"series":[{"columns":["name","duration","shardGroupDuration","replicaN","default","fieldTypePolicy"],"values":[["rp0","2h0m0s","1h0m0s",3,true,"strict"],["rp2","1h0m0s","1h0m0s",1,false,"strict"]]}]}]}`,
 			},
 			{
 				name:    "dropping non-default retention policy succeed",
@@ -506,7 +506,7 @@ func init() {
 			{
 				name:    "show retention policy should show both with custom shard",
 				command: `SHOW RETENTION POLICIES ON db0`,
-				exp:     `{"results":[{"statement_id":0,"series":[{"columns":["name","duration","shardGroupDuration","replicaN","default"],"values":[["rp0","2h0m0s","1h0m0s",3,true],["rp3","1h0m0s","1h0m0s",1,false]]}]}]}`,
+				exp:     `{"results":[{"statement_id":0,"series":[{"columns":["name","duration","shardGroupDuration","replicaN","default","fieldTypePolicy"],"values":[["rp0","2h0m0s","1h0m0s",3,true,"strict"],["rp3","1h0m0s","1h0m0s",1,false,"strict"]]}]}]}`,
 			},
 			{
 				name:    "dropping non-default custom shard retention policy succeed",
@@ -517,7 +517,7 @@ func init() {
 			{
 				name:    "show retention policy should show just default",
 				command: `SHOW RETENTION POLICIES ON db0`,
-				exp:     `{"results":[{"statement_id":0,"series":[{"columns":["name","duration","shardGroupDuration","replicaN","default"],"values":[["rp0","2h0m0s","1h0m0s",3,true]]}]}]}`,
+				exp:     `{"results":[{"statement_id":0,"series":[{"columns":["name","duration","shardGroupDuration","replicaN","default","fieldTypePolicy"],"values":[["rp0","2h0m0s","1h0m0s",3,true,"strict"]]}]}]}`,
 			},
 			{
 				name:    "Ensure retention policy with unacceptable retention cannot be created",
@@ -565,7 +565,7 @@ func init() {
 			{
 				name:    "show retention policy: validate normalized shard group durations are working",
 				command: `SHOW RETENTION POLICIES ON db0`,
-				exp:     `{"results":[{"statement_id":0,"series":[{"columns":["name","duration","shardGroupDuration","replicaN","default"],"values":[["rpinf","0s","168h0m0s",1,false],["rpzero","1h0m0s","1h0m0s",1,false],["rponesecond","2h0m0s","1h0m0s",1,false]]}]}]}`,
+				exp:     `{"results":[{"statement_id":0,"series":[{"columns":["name","duration","shardGroupDuration","replicaN","default","fieldTypePolicy"],"values":[["rpinf","0s","168h0m0s",1,false,"strict"],["rpzero","1h0m0s","1h0m0s",1,false,"strict"],["rponesecond","2h0m0s","1h0m0s",1,false,"strict"]]}]}]}`,
 			},
 		},
 	}
@@ -581,7 +581,7 @@ func init() {
 			{
 				name:    "show retention policies should return auto-created policy",
 				command: `SHOW RETENTION POLICIES ON db0`,
-				exp:     `{"results":[{"statement_id":0,"series":[{"columns":["name","duration","shardGroupDuration","replicaN","default"],"values":[["autogen","0s","168h0m0s",1,true]]}]}]}`,
+				exp:     `{"results":[{"statement_id":0,"series":[{"columns":["name","duration","shardGroupDuration","replicaN","default","fieldTypePolicy"],"values":[["autogen","0s","168h0m0s",1,true,"strict"]]}]}]}`,
 			},
 		},
 	}
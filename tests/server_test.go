@@ -9,6 +9,7 @@ import (
 	"net/http"
 	"net/url"
 	"os"
+	"path/filepath"
 	"reflect"
 	"strconv"
 	"strings"
@@ -479,6 +480,225 @@ func TestServer_ShowDatabases_WithAuth(t *testing.T) {
 	}
 }
 
+// Ensure security policies limit the series a user can read.
+func TestServer_SecurityPolicies(t *testing.T) {
+	t.Parallel()
+	c := NewConfig()
+	c.HTTPD.AuthEnabled = true
+	s := OpenServer(c)
+	defer s.Close()
+
+	if _, ok := s.(*RemoteServer); ok {
+		t.Skip("Skipping.  Cannot enable auth on remote server")
+	}
+
+	adminParams := map[string][]string{"u": {"admin"}, "p": {"admin"}, "db": {"db0"}}
+	tenantParams := map[string][]string{"u": {"acme"}, "p": {"acme"}, "db": {"db0"}}
+
+	if _, err := s.QueryWithParams(`CREATE USER admin WITH PASSWORD 'admin' WITH ALL PRIVILEGES`, nil); err != nil {
+		t.Fatal(err)
+	} else if err := s.CreateDatabaseAndRetentionPolicy("db0", NewRetentionPolicySpec("rp0", 1, 0), true); err != nil {
+		t.Fatal(err)
+	}
+	s.MustWrite("db0", "rp0", strings.Join([]string{
+		fmt.Sprintf(`cpu,customer=acme,host=a value=1 %d`, mustParseTime(time.RFC3339Nano, "2000-01-01T00:00:00Z").UnixNano()),
+		fmt.Sprintf(`cpu,customer=globex,host=b value=2 %d`, mustParseTime(time.RFC3339Nano, "2000-01-01T00:00:00Z").UnixNano()),
+	}, "\n"), adminParams)
+
+	test := Test{
+		queries: []*Query{
+			{
+				name:    "create tenant",
+				command: `CREATE USER acme WITH PASSWORD 'acme'; GRANT READ ON db0 TO acme; CREATE SECURITY POLICY ON db0 FOR USER acme USING (customer = 'acme')`,
+				params:  adminParams,
+				exp:     `{"results":[{"statement_id":0},{"statement_id":1},{"statement_id":2}]}`,
+			},
+			{
+				name:    "show security policies",
+				command: `SHOW SECURITY POLICIES ON db0`,
+				params:  adminParams,
+				exp:     `{"results":[{"statement_id":0,"series":[{"columns":["database","user","role","condition"],"values":[["db0","acme",null,"customer = 'acme'"]]}]}]}`,
+			},
+			{
+				name:    "select as admin",
+				command: `SELECT value FROM cpu`,
+				params:  adminParams,
+				exp:     `{"results":[{"statement_id":0,"series":[{"name":"cpu","columns":["time","value"],"values":[["2000-01-01T00:00:00Z",1],["2000-01-01T00:00:00Z",2]]}]}]}`,
+			},
+			{
+				name:    "select as tenant",
+				command: `SELECT value FROM cpu`,
+				params:  tenantParams,
+				exp:     `{"results":[{"statement_id":0,"series":[{"name":"cpu","columns":["time","value"],"values":[["2000-01-01T00:00:00Z",1]]}]}]}`,
+			},
+			{
+				name:    "show series as tenant",
+				command: `SHOW SERIES`,
+				params:  tenantParams,
+				exp:     `{"results":[{"statement_id":0,"series":[{"columns":["key"],"values":[["cpu,customer=acme,host=a"]]}]}]}`,
+			},
+			{
+				name:    "show tag values as tenant",
+				command: `SHOW TAG VALUES WITH KEY = "customer"`,
+				params:  tenantParams,
+				exp:     `{"results":[{"statement_id":0,"series":[{"name":"cpu","columns":["key","value"],"values":[["customer","acme"]]}]}]}`,
+			},
+			{
+				name:    "drop security policy",
+				command: `DROP SECURITY POLICY ON db0 FOR USER acme`,
+				params:  adminParams,
+				exp:     `{"results":[{"statement_id":0}]}`,
+			},
+			{
+				name:    "show series as tenant without policy",
+				command: `SHOW SERIES`,
+				params:  tenantParams,
+				exp:     `{"results":[{"statement_id":0,"series":[{"columns":["key"],"values":[["cpu,customer=acme,host=a"],["cpu,customer=globex,host=b"]]}]}]}`,
+			},
+		},
+	}
+
+	for _, query := range test.queries {
+		t.Run(query.name, func(t *testing.T) {
+			if err := query.Execute(s); err != nil {
+				t.Error(fmt.Sprintf("command: %s - err: %s", query.command, query.Error(err)))
+			} else if !query.success() {
+				t.Error(query.failureMessage())
+			}
+		})
+	}
+}
+
+// Ensure logins and statements are recorded in the audit log.
+func TestServer_AuditLog(t *testing.T) {
+	t.Parallel()
+	c := NewConfig()
+	c.HTTPD.AuthEnabled = true
+	c.Audit.Enabled = true
+	c.Audit.Path = filepath.Join(c.rootPath, "audit.log")
+	s := OpenServer(c)
+	defer s.Close()
+
+	if _, ok := s.(*RemoteServer); ok {
+		t.Skip("Skipping.  Cannot enable auth on remote server")
+	}
+
+	adminParams := map[string][]string{"u": {"admin"}, "p": {"admin"}}
+	if _, err := s.QueryWithParams(`CREATE USER admin WITH PASSWORD 'admin' WITH ALL PRIVILEGES`, nil); err != nil {
+		t.Fatal(err)
+	} else if _, err := s.QueryWithParams(`CREATE DATABASE db0`, adminParams); err != nil {
+		t.Fatal(err)
+	}
+	s.QueryWithParams(`SHOW DATABASES`, map[string][]string{"u": {"admin"}, "p": {"wrong"}})
+
+	results, err := s.QueryWithParams(`SHOW AUDIT LOG LIMIT 4`, adminParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var resp struct {
+		Results []struct {
+			Series []struct {
+				Columns []string        `json:"columns"`
+				Values  [][]interface{} `json:"values"`
+			} `json:"series"`
+		} `json:"results"`
+	}
+	if err := json.Unmarshal([]byte(results), &resp); err != nil {
+		t.Fatal(err)
+	} else if len(resp.Results) != 1 || len(resp.Results[0].Series) != 1 {
+		t.Fatalf("unexpected results: %s", results)
+	}
+
+	// Compare the action, user, source, statement and outcome of each event.
+	// The login of SHOW AUDIT LOG repeats an earlier one and is not recorded.
+	var got [][]interface{}
+	for _, values := range resp.Results[0].Series[0].Values {
+		got = append(got, []interface{}{values[1], values[2], values[3], values[5], values[7]})
+	}
+	exp := [][]interface{}{
+		{"statement", nil, "127.0.0.1", "CREATE USER admin WITH PASSWORD [REDACTED] WITH ALL PRIVILEGES", "success"},
+		{"login", "admin", "127.0.0.1", nil, "success"},
+		{"statement", "admin", "127.0.0.1", "CREATE DATABASE db0", "success"},
+		{"login", "admin", "127.0.0.1", nil, "failure"},
+	}
+	if !reflect.DeepEqual(got, exp) {
+		t.Fatalf("unexpected events:\ngot: %v\nexp: %v", got, exp)
+	}
+}
+
+// Ensure the quotas of a database are enforced on writes and reported by
+// SHOW QUOTAS.
+func TestServer_Quotas(t *testing.T) {
+	t.Parallel()
+	s := OpenServer(NewConfig())
+	defer s.Close()
+
+	if _, ok := s.(*RemoteServer); ok {
+		t.Skip("Skipping.  Cannot read the status code of writes to a remote server")
+	}
+
+	if err := s.CreateDatabaseAndRetentionPolicy("db0", NewRetentionPolicySpec("rp0", 1, 0), true); err != nil {
+		t.Fatal(err)
+	} else if _, err := s.Query(`SET QUOTA ON db0 SERIES 2 POINTS PER SECOND 5`); err != nil {
+		t.Fatal(err)
+	}
+
+	// A write larger than the points per second quota is admitted while
+	// nothing else counts against it. Points that would create series beyond
+	// the series quota are dropped.
+	var points []string
+	for i := 0; i < 12; i++ {
+		points = append(points, fmt.Sprintf("cpu,host=server%02d value=%d %d", i%3, i, i))
+	}
+	_, err := s.Write("db0", "rp0", strings.Join(points, "\n"), nil)
+	if werr, ok := err.(WriteError); !ok || werr.StatusCode() != http.StatusBadRequest {
+		t.Fatalf("unexpected error: %v", err)
+	} else if exp := `partial write: database \"db0\" exceeded its series quota: limit 2 dropped=4`; !strings.Contains(werr.Body(), exp) {
+		t.Fatalf("unexpected body: %s", werr.Body())
+	}
+
+	// Its excess is carried into the following seconds, so further writes
+	// are rejected until it is drained.
+	_, err = s.Write("db0", "rp0", points[0], nil)
+	if werr, ok := err.(WriteError); !ok || werr.StatusCode() != http.StatusTooManyRequests {
+		t.Fatalf("unexpected error: %v", err)
+	} else if exp := `database \"db0\" exceeded its points per second quota: limit 5`; !strings.Contains(werr.Body(), exp) {
+		t.Fatalf("unexpected body: %s", werr.Body())
+	}
+
+	results, err := s.Query(`SHOW QUOTAS`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var resp struct {
+		Results []struct {
+			Series []struct {
+				Columns []string        `json:"columns"`
+				Values  [][]interface{} `json:"values"`
+			} `json:"series"`
+		} `json:"results"`
+	}
+	if err := json.Unmarshal([]byte(results), &resp); err != nil {
+		t.Fatal(err)
+	} else if len(resp.Results) != 1 || len(resp.Results[0].Series) != 1 || len(resp.Results[0].Series[0].Values) != 1 {
+		t.Fatalf("unexpected results: %s", results)
+	}
+
+	// Compare the columns that do not depend on timing or the size on disk.
+	values := resp.Results[0].Series[0].Values[0]
+	got := []interface{}{values[0], values[1], values[3], values[4], values[5], values[7], values[8]}
+	exp := []interface{}{"db0", 0.0, 2.0, 2.0, 5.0, 0.0, 0.0}
+	if !reflect.DeepEqual(got, exp) {
+		t.Fatalf("unexpected quota:\ngot: %v\nexp: %v", got, exp)
+	}
+
+	if _, err := s.Query(`DROP QUOTA ON db0`); err != nil {
+		t.Fatal(err)
+	} else if _, err := s.Write("db0", "rp0", strings.Join(points, "\n"), nil); err != nil {
+		t.Fatal(err)
+	}
+}
+
 // Ensure user commands work.
 func TestServer_UserCommands(t *testing.T) {
 	t.Parallel()
@@ -805,7 +1025,7 @@ func TestServer_Query_DefaultDBAndRP(t *testing.T) {
 		{
 			name:    "default rp exists",
 			command: `show retention policies ON db0`,
-			exp:     `{"results":[{"statement_id":0,"series":[{"columns":["name","duration","shardGroupDuration","replicaN","default"],"values":[["autogen","0s","168h0m0s",1,false],["rp0","0s","168h0m0s",1,true]]}]}]}`,
+			exp:     `{"results":[{"statement_id":0,"series":[{"columns":["name","duration","shardGroupDuration","replicaN","default","fieldTypePolicy"],"values":[["autogen","0s","168h0m0s",1,false,"strict"],["rp0","0s","168h0m0s",1,true,"strict"]]}]}]}`,
 		},
 		{
 			name:    "default rp",
@@ -8584,7 +8804,7 @@ func TestServer_ContinuousQuery(t *testing.T) {
 		{
 			name:    `show continuous queries`,
 			command: `SHOW CONTINUOUS QUERIES`,
-			exp:     `{"results":[{"statement_id":0,"series":[{"name":"db0","columns":["name","query"],"values":[["cq1","CREATE CONTINUOUS QUERY cq1 ON db0 BEGIN SELECT count(value) INTO db0.rp1.:MEASUREMENT FROM db0.rp0./[cg]pu/ GROUP BY time(5s) END"],["cq2","CREATE CONTINUOUS QUERY cq2 ON db0 BEGIN SELECT count(value) INTO db0.rp2.:MEASUREMENT FROM db0.rp0./[cg]pu/ GROUP BY time(5s), * END"]]}]}]}`,
+			exp:     `{"results":[{"statement_id":0,"series":[{"name":"db0","columns":["name","query","last_run","last_run_duration","points_written","last_error"],"values":[["cq1","CREATE CONTINUOUS QUERY cq1 ON db0 BEGIN SELECT count(value) INTO db0.rp1.:MEASUREMENT FROM db0.rp0./[cg]pu/ GROUP BY time(5s) END",null,null,null,null],["cq2","CREATE CONTINUOUS QUERY cq2 ON db0 BEGIN SELECT count(value) INTO db0.rp2.:MEASUREMENT FROM db0.rp0./[cg]pu/ GROUP BY time(5s), * END",null,null,null,null]]}]}]}`,
 		},
 	}...)
 
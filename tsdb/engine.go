@@ -15,6 +15,7 @@ import (
 	"github.com/ayang64/reflux/pkg/estimator"
 	"github.com/ayang64/reflux/pkg/limiter"
 	"github.com/ayang64/reflux/query"
+	"github.com/ayang64/reflux/task"
 	"github.com/ayang64/reflux/influxql"
 	"go.uber.org/zap"
 )
@@ -68,6 +69,8 @@ type Engine interface {
 	MeasurementFields(measurement []byte) *MeasurementFields
 	ForEachMeasurementName(fn func(name []byte) error) error
 	DeleteMeasurement(name []byte) error
+	DeleteField(name []byte, field string) error
+	ConvertField(name []byte, field string, typ influxql.DataType) error
 
 	HasTagKey(name, key []byte) (bool, error)
 	MeasurementTagKeysByExpr(name []byte, expr influxql.Expr) (map[string]struct{}, error)
@@ -192,6 +195,10 @@ type EngineOptions struct {
 	OnNewEngine func(Engine)
 
 	FileStoreObserver FileStoreObserver
+
+	// TaskManager tracks long-running engine work such as compactions.
+	// If nil, that work is not tracked.
+	TaskManager *task.Manager
 }
 
 // NewEngineOptions constructs an EngineOptions object with safe default values.
@@ -14,6 +14,7 @@ package tsm1
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"io"
 	"math"
@@ -21,6 +22,7 @@ import (
 	"path/filepath"
 	"runtime"
 	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -889,8 +891,9 @@ func (c *Compactor) WriteSnapshot(cache *Cache) ([]string, error) {
 	return files, err
 }
 
-// compact writes multiple smaller TSM files into 1 or more larger files.
-func (c *Compactor) compact(fast bool, tsmFiles []string) ([]string, error) {
+// compact writes multiple smaller TSM files into 1 or more larger files. If
+// wrap is not nil, the blocks are read through the iterator it returns.
+func (c *Compactor) compact(ctx context.Context, fast bool, tsmFiles []string, wrap func(KeyIterator) KeyIterator) ([]string, error) {
 	size := c.Size
 	if size <= 0 {
 		size = tsdb.DefaultMaxPointsPerBlock
@@ -900,6 +903,16 @@ func (c *Compactor) compact(fast bool, tsmFiles []string) ([]string, error) {
 	intC := c.compactionsInterrupt
 	c.mu.RUnlock()
 
+	// Abort the compaction if either compactions are disabled or the context
+	// is canceled.
+	if err := ctx.Err(); err != nil {
+		return nil, errCompactionAborted{err}
+	} else if done := ctx.Done(); done != nil {
+		var stop func()
+		intC, stop = mergeInterrupt(intC, done)
+		defer stop()
+	}
+
 	// The new compacted files need to added to the max generation in the
 	// set.  We need to find that max generation as well as the max sequence
 	// number to ensure we write to the next unique location.
@@ -948,12 +961,21 @@ func (c *Compactor) compact(fast bool, tsmFiles []string) ([]string, error) {
 	if err != nil {
 		return nil, err
 	}
+	if wrap != nil {
+		tsm = wrap(tsm)
+	}
 
 	return c.writeNewFiles(maxGeneration, maxSequence, tsmFiles, tsm, true)
 }
 
 // CompactFull writes multiple smaller TSM files into 1 or more larger files.
 func (c *Compactor) CompactFull(tsmFiles []string) ([]string, error) {
+	return c.CompactFullContext(context.Background(), tsmFiles)
+}
+
+// CompactFullContext is like CompactFull but aborts the compaction when ctx is
+// canceled.
+func (c *Compactor) CompactFullContext(ctx context.Context, tsmFiles []string) ([]string, error) {
 	c.mu.RLock()
 	enabled := c.compactionsEnabled
 	c.mu.RUnlock()
@@ -967,7 +989,7 @@ func (c *Compactor) CompactFull(tsmFiles []string) ([]string, error) {
 	}
 	defer c.remove(tsmFiles)
 
-	files, err := c.compact(false, tsmFiles)
+	files, err := c.compact(ctx, false, tsmFiles, nil)
 
 	// See if we were disabled while writing a snapshot
 	c.mu.RLock()
@@ -986,6 +1008,12 @@ func (c *Compactor) CompactFull(tsmFiles []string) ([]string, error) {
 
 // CompactFast writes multiple smaller TSM files into 1 or more larger files.
 func (c *Compactor) CompactFast(tsmFiles []string) ([]string, error) {
+	return c.CompactFastContext(context.Background(), tsmFiles)
+}
+
+// CompactFastContext is like CompactFast but aborts the compaction when ctx is
+// canceled.
+func (c *Compactor) CompactFastContext(ctx context.Context, tsmFiles []string) ([]string, error) {
 	c.mu.RLock()
 	enabled := c.compactionsEnabled
 	c.mu.RUnlock()
@@ -999,7 +1027,7 @@ func (c *Compactor) CompactFast(tsmFiles []string) ([]string, error) {
 	}
 	defer c.remove(tsmFiles)
 
-	files, err := c.compact(true, tsmFiles)
+	files, err := c.compact(ctx, true, tsmFiles, nil)
 
 	// See if we were disabled while writing a snapshot
 	c.mu.RLock()
@@ -1016,6 +1044,40 @@ func (c *Compactor) CompactFast(tsmFiles []string) ([]string, error) {
 	return files, err
 }
 
+// ConvertField writes tsmFiles into new files in which the blocks of the keys
+// matched by match are converted to the block type typ. The files must make up
+// whole generations. Unlike the other compactions, it does not check whether
+// compactions are enabled so that the caller can disable them while the files
+// are rewritten.
+func (c *Compactor) ConvertField(tsmFiles []string, match func(key []byte) bool, typ byte) ([]string, error) {
+	if !c.add(tsmFiles) {
+		return nil, errCompactionInProgress{}
+	}
+	defer c.remove(tsmFiles)
+
+	return c.compact(context.Background(), false, tsmFiles, func(iter KeyIterator) KeyIterator {
+		return &convertKeyIterator{KeyIterator: iter, match: match, typ: typ}
+	})
+}
+
+// mergeInterrupt returns a channel that is closed when either intC or done is
+// closed. The returned function must be called to release resources once the
+// channel is no longer needed.
+func mergeInterrupt(intC chan struct{}, done <-chan struct{}) (chan struct{}, func()) {
+	merged := make(chan struct{})
+	stop := make(chan struct{})
+	go func() {
+		select {
+		case <-intC:
+			close(merged)
+		case <-done:
+			close(merged)
+		case <-stop:
+		}
+	}()
+	return merged, func() { close(stop) }
+}
+
 // removeTmpFiles is responsible for cleaning up a compaction that
 // was started, but then abandoned before the temporary files were dealt with.
 func (c *Compactor) removeTmpFiles(files []string) error {
@@ -1234,6 +1296,91 @@ type KeyIterator interface {
 	// be required to store all the series and entries in the KeyIterator.
 	EstimatedIndexSize() int
 }
+
+// convertKeyIterator converts the blocks of the keys matched by match to the
+// block type typ as they are read from the underlying iterator.
+type convertKeyIterator struct {
+	KeyIterator
+	match  func(key []byte) bool
+	typ    byte
+	values []Value
+}
+
+// Read returns the key, time range, and converted data for the next block.
+func (k *convertKeyIterator) Read() ([]byte, int64, int64, []byte, error) {
+	key, minTime, maxTime, block, err := k.KeyIterator.Read()
+	if err != nil || !k.match(key) {
+		return key, minTime, maxTime, block, err
+	}
+
+	if typ, err := BlockType(block); err != nil {
+		return nil, 0, 0, nil, err
+	} else if typ == k.typ {
+		return key, minTime, maxTime, block, nil
+	}
+
+	k.values, err = DecodeBlock(block, k.values[:0])
+	if err != nil {
+		return nil, 0, 0, nil, err
+	}
+	for i, v := range k.values {
+		if k.values[i], err = convertValue(v, k.typ); err != nil {
+			return nil, 0, 0, nil, err
+		}
+	}
+
+	block, err = Values(k.values).Encode(nil)
+	if err != nil {
+		return nil, 0, 0, nil, err
+	}
+	return key, minTime, maxTime, block, nil
+}
+
+// convertValue returns v as a value of the block type typ.
+func convertValue(v Value, typ byte) (Value, error) {
+	t := v.UnixNano()
+	switch typ {
+	case BlockFloat64:
+		switch x := v.Value().(type) {
+		case int64:
+			return NewFloatValue(t, float64(x)), nil
+		case uint64:
+			return NewFloatValue(t, float64(x)), nil
+		case bool:
+			if x {
+				return NewFloatValue(t, 1), nil
+			}
+			return NewFloatValue(t, 0), nil
+		}
+	case BlockInteger:
+		if x, ok := v.Value().(bool); ok {
+			if x {
+				return NewIntegerValue(t, 1), nil
+			}
+			return NewIntegerValue(t, 0), nil
+		}
+	case BlockUnsigned:
+		if x, ok := v.Value().(bool); ok {
+			if x {
+				return NewUnsignedValue(t, 1), nil
+			}
+			return NewUnsignedValue(t, 0), nil
+		}
+	case BlockString:
+		switch x := v.Value().(type) {
+		case float64:
+			return NewStringValue(t, strconv.FormatFloat(x, 'f', -1, 64)), nil
+		case int64:
+			return NewStringValue(t, strconv.FormatInt(x, 10)), nil
+		case uint64:
+			return NewStringValue(t, strconv.FormatUint(x, 10)), nil
+		case bool:
+			return NewStringValue(t, strconv.FormatBool(x)), nil
+		}
+	}
+	return nil, fmt.Errorf("cannot convert %T value to %s", v.Value(), BlockTypeToInfluxQLDataType(typ))
+}
+
 type TSMErrors []error
 
 func (t TSMErrors) Error() string {
@@ -1,6 +1,7 @@
 package tsm1_test
 
 import (
+	"context"
 	"fmt"
 	"math"
 	"os"
@@ -243,6 +244,43 @@ func TestCompactor_CompactFull(t *testing.T) {
 	}
 }
 
+// Ensures that a full compaction is aborted when its context is canceled.
+func TestCompactor_CompactFullContext_Canceled(t *testing.T) {
+	dir := MustTempDir()
+	defer os.RemoveAll(dir)
+
+	f1 := MustWriteTSM(dir, 1, map[string][]tsm1.Value{
+		"cpu,host=A#!~#value": {tsm1.NewValue(1, 1.1)},
+	})
+	f2 := MustWriteTSM(dir, 2, map[string][]tsm1.Value{
+		"cpu,host=B#!~#value": {tsm1.NewValue(1, 2.1)},
+	})
+
+	fs := &fakeFileStore{}
+	defer fs.Close()
+	compactor := tsm1.NewCompactor()
+	compactor.Dir = dir
+	compactor.FileStore = fs
+	compactor.Open()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	files, err := compactor.CompactFullContext(ctx, []string{f1, f2})
+	if err == nil {
+		t.Fatal("expected error compacting with canceled context")
+	} else if len(files) > 0 {
+		t.Fatalf("no files should be compacted: got %v", len(files))
+	}
+
+	// The compaction can still run with a live context.
+	if files, err = compactor.CompactFullContext(context.Background(), []string{f1, f2}); err != nil {
+		t.Fatalf("unexpected error compacting: %v", err)
+	} else if len(files) != 1 {
+		t.Fatalf("files length mismatch: got %v, exp 1", len(files))
+	}
+}
+
 // Ensures that a compaction will properly merge multiple TSM files
 func TestCompactor_DecodeError(t *testing.T) {
 	dir := MustTempDir()
@@ -14,6 +14,7 @@ import (
 	"path/filepath"
 	"regexp"
 	"runtime"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -31,6 +32,7 @@ import (
 	intar "github.com/ayang64/reflux/pkg/tar"
 	"github.com/ayang64/reflux/pkg/tracing"
 	"github.com/ayang64/reflux/query"
+	"github.com/ayang64/reflux/task"
 	"github.com/ayang64/reflux/tsdb"
 	_ "github.com/ayang64/reflux/tsdb/index"
 	"github.com/ayang64/reflux/tsdb/index/inmem"
@@ -192,6 +194,15 @@ type Engine struct {
 	// Limiter for concurrent compactions.
 	compactionLimiter limiter.Fixed
 
+	// Tracks running compactions so they can be listed and killed.
+	tasks *task.Manager
+
+	// Groups of compactions that were killed. They are not started again
+	// until points are written to the shard after killedAt.
+	killedMu     sync.Mutex
+	killedGroups map[string]struct{}
+	killedAt     time.Time
+
 	scheduler *scheduler
 
 	// provides access to the total set of series IDs
@@ -257,6 +268,7 @@ func NewEngine(id uint64, idx tsdb.Index, path string, walPath string, sfile *ts
 		formatFileName:                DefaultFormatFileName,
 		stats:                         stats,
 		compactionLimiter:             opt.CompactionLimiter,
+		tasks:                         opt.TaskManager,
 		scheduler:                     newScheduler(stats, opt.CompactionLimiter.Capacity()),
 		seriesIDSets:                  opt.SeriesIDSets,
 	}
@@ -1857,6 +1869,187 @@ func (e *Engine) DeleteMeasurement(name []byte) error {
 	return e.DeleteSeriesRange(tsdb.NewSeriesIteratorAdapter(e.sfile, itr), math.MinInt64, math.MaxInt64)
 }
 
+// DeleteField deletes the values of a field of a measurement and removes the
+// field from the fields index. The measurement is deleted when it has no
+// fields left.
+func (e *Engine) DeleteField(name []byte, field string) error {
+	mf := e.fieldset.Fields(name)
+	if mf == nil || !mf.HasField(field) {
+		return nil
+	}
+
+	// Disable and abort running compactions so that the tombstones are not
+	// removed before they are applied, as for series deletes.
+	e.disableLevelCompactions(true)
+	defer e.enableLevelCompactions(true)
+
+	match := fieldKeyMatcher(name, field)
+	encodedName := models.EscapeMeasurement(name)
+
+	// Tombstone the field in every TSM file.
+	if err := e.FileStore.Apply(func(r TSMFile) error {
+		var keys [][]byte
+		n := r.KeyCount()
+		for i := r.Seek(encodedName); i < n; i++ {
+			key, _ := r.KeyAt(i)
+			if !bytes.HasPrefix(key, encodedName) {
+				break
+			} else if match(key) {
+				keys = append(keys, key)
+			}
+		}
+		if len(keys) == 0 {
+			return nil
+		}
+
+		batch := r.BatchDelete()
+		if err := batch.DeleteRange(keys, math.MinInt64, math.MaxInt64); err != nil {
+			batch.Rollback()
+			return err
+		}
+		return batch.Commit()
+	}); err != nil {
+		return err
+	}
+
+	// Remove the field from the cache and the WAL.
+	var keys [][]byte
+	_ = e.Cache.ApplyEntryFn(func(k []byte, _ *entry) error {
+		if match(k) {
+			keys = append(keys, k)
+		}
+		return nil
+	})
+	bytesutil.Sort(keys)
+	e.Cache.DeleteRange(keys, math.MinInt64, math.MaxInt64)
+	if e.WALEnabled && len(keys) > 0 {
+		if _, err := e.WAL.DeleteRange(keys, math.MinInt64, math.MaxInt64); err != nil {
+			return err
+		}
+	}
+
+	mf.DeleteField(field)
+	if mf.FieldN() == 0 {
+		return e.DeleteMeasurement(name)
+	}
+	return e.fieldset.Save()
+}
+
+// ConvertField rewrites the values of a field of a measurement as typ and
+// changes the type of the field in the fields index. The cache is written to
+// TSM files first and every generation that holds the field is rewritten by
+// the compactor.
+func (e *Engine) ConvertField(name []byte, field string, typ influxql.DataType) error {
+	mf := e.fieldset.Fields(name)
+	if mf == nil || !mf.HasField(field) {
+		return tsdb.ErrFieldNotFound
+	}
+	if f := mf.Field(field); f.Type == typ {
+		return nil
+	} else if !tsdb.CanConvertFieldType(f.Type, typ) {
+		return tsdb.ErrFieldTypeConversion
+	}
+
+	var blockType byte
+	switch typ {
+	case influxql.Float:
+		blockType = BlockFloat64
+	case influxql.Integer:
+		blockType = BlockInteger
+	case influxql.Unsigned:
+		blockType = BlockUnsigned
+	case influxql.String:
+		blockType = BlockString
+	default:
+		return tsdb.ErrFieldTypeConversion
+	}
+
+	if err := e.WriteSnapshot(); err != nil {
+		return err
+	}
+
+	// Stop level compactions so that the files are not compacted while they
+	// are rewritten.
+	e.disableLevelCompactions(true)
+	defer e.enableLevelCompactions(true)
+
+	// Find the generations that hold the field. Every file of a generation is
+	// rewritten so that the new files get unique sequence numbers.
+	match := fieldKeyMatcher(name, field)
+	encodedName := models.EscapeMeasurement(name)
+	var mu sync.Mutex
+	files := make(map[int][]string)
+	convert := make(map[int]bool)
+	if err := e.FileStore.Apply(func(r TSMFile) error {
+		gen, _, err := e.FileStore.ParseFileName(r.Path())
+		if err != nil {
+			return err
+		}
+
+		var found bool
+		n := r.KeyCount()
+		for i := r.Seek(encodedName); i < n && !found; i++ {
+			key, _ := r.KeyAt(i)
+			if !bytes.HasPrefix(key, encodedName) {
+				break
+			}
+			found = match(key)
+		}
+
+		mu.Lock()
+		files[gen] = append(files[gen], r.Path())
+		convert[gen] = convert[gen] || found
+		mu.Unlock()
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	gens := make([]int, 0, len(convert))
+	for gen, ok := range convert {
+		if ok {
+			gens = append(gens, gen)
+		}
+	}
+	sort.Ints(gens)
+
+	for _, gen := range gens {
+		group := files[gen]
+		sort.Strings(group)
+
+		newFiles, err := e.Compactor.ConvertField(group, match, blockType)
+		if err != nil {
+			return err
+		}
+		if err := e.FileStore.ReplaceWithCallback(group, newFiles, nil); err != nil {
+			for _, file := range newFiles {
+				os.Remove(file)
+			}
+			return err
+		}
+	}
+
+	if err := mf.SetFieldType(field, typ); err != nil {
+		return err
+	}
+	return e.fieldset.Save()
+}
+
+// fieldKeyMatcher returns a function that reports whether a composite key
+// holds the values of the field of the measurement.
+func fieldKeyMatcher(name []byte, field string) func(key []byte) bool {
+	encodedName := models.EscapeMeasurement(name)
+	sep := len(encodedName)
+	return func(key []byte) bool {
+		if len(key) <= sep || !bytes.HasPrefix(key, encodedName) ||
+			(key[sep] != ',' && key[sep] != keyFieldSeparator[0]) {
+			return false
+		}
+		_, f := SeriesAndFieldFromCompositeKey(key)
+		return string(f) == field
+	}
+}
+
 // ForEachMeasurementName iterates over each measurement name in the engine.
 func (e *Engine) ForEachMeasurementName(fn func(name []byte) error) error {
 	return e.index.ForEachMeasurementName(fn)
@@ -2086,6 +2279,12 @@ func (e *Engine) compact(wg *sync.WaitGroup) {
 				atomic.StoreInt64(&e.stats.TSMOptimizeCompactionsQueue, int64(len(level4Groups)))
 			}
 
+			// Don't restart the compactions that were killed.
+			level1Groups = e.skipKilledCompactions(level1Groups)
+			level2Groups = e.skipKilledCompactions(level2Groups)
+			level3Groups = e.skipKilledCompactions(level3Groups)
+			level4Groups = e.skipKilledCompactions(level4Groups)
+
 			// Update the level plan queue stats
 			atomic.StoreInt64(&e.stats.TSMCompactionsQueue[0], int64(len(level1Groups)))
 			atomic.StoreInt64(&e.stats.TSMCompactionsQueue[1], int64(len(level2Groups)))
@@ -2146,7 +2345,7 @@ func (e *Engine) compactHiPriorityLevel(grp CompactionGroup, level int, fast boo
 			defer atomic.AddInt64(&e.stats.TSMCompactionsActive[level-1], -1)
 
 			defer e.compactionLimiter.Release()
-			s.Apply()
+			e.runCompaction(s)
 			// Release the files in the compaction plan
 			e.CompactionPlan.Release([]CompactionGroup{s.group})
 		}()
@@ -2173,7 +2372,7 @@ func (e *Engine) compactLoPriorityLevel(grp CompactionGroup, level int, fast boo
 			defer wg.Done()
 			defer atomic.AddInt64(&e.stats.TSMCompactionsActive[level-1], -1)
 			defer e.compactionLimiter.Release()
-			s.Apply()
+			e.runCompaction(s)
 			// Release the files in the compaction plan
 			e.CompactionPlan.Release([]CompactionGroup{s.group})
 		}()
@@ -2198,7 +2397,7 @@ func (e *Engine) compactFull(grp CompactionGroup, wg *sync.WaitGroup) bool {
 			defer wg.Done()
 			defer atomic.AddInt64(&e.stats.TSMFullCompactionsActive, -1)
 			defer e.compactionLimiter.Release()
-			s.Apply()
+			e.runCompaction(s)
 			// Release the files in the compaction plan
 			e.CompactionPlan.Release([]CompactionGroup{s.group})
 		}()
@@ -2207,6 +2406,64 @@ func (e *Engine) compactFull(grp CompactionGroup, wg *sync.WaitGroup) bool {
 	return false
 }
 
+// runCompaction applies s as a task so that it can be listed and killed.
+func (e *Engine) runCompaction(s *compactionStrategy) {
+	name := fmt.Sprintf("tsm1 level %d compaction of shard %d", s.level, e.id)
+	if s.level == 4 {
+		name = fmt.Sprintf("tsm1 full compaction of shard %d", e.id)
+		if s.fast {
+			name = fmt.Sprintf("tsm1 optimize compaction of shard %d", e.id)
+		}
+	}
+	e.tasks.Run(context.Background(), name, func(ctx context.Context) error {
+		s.Apply(ctx)
+		if err := ctx.Err(); err != nil {
+			e.compactionKilled(s.group)
+			return err
+		}
+		return nil
+	})
+}
+
+// compactionKilled records that the compaction of group was killed so that it
+// is not started again until new points are written to the shard.
+func (e *Engine) compactionKilled(group CompactionGroup) {
+	e.killedMu.Lock()
+	defer e.killedMu.Unlock()
+	if e.killedGroups == nil {
+		e.killedGroups = make(map[string]struct{})
+	}
+	e.killedGroups[strings.Join(group, ",")] = struct{}{}
+	e.killedAt = e.Cache.LastWriteTime()
+}
+
+// skipKilledCompactions releases the groups whose compaction was killed and
+// returns the others. The killed groups are forgotten once new points are
+// written to the shard.
+func (e *Engine) skipKilledCompactions(groups []CompactionGroup) []CompactionGroup {
+	e.killedMu.Lock()
+	defer e.killedMu.Unlock()
+	if len(e.killedGroups) == 0 {
+		return groups
+	} else if e.Cache.LastWriteTime().After(e.killedAt) {
+		e.killedGroups = nil
+		return groups
+	}
+
+	var killed []CompactionGroup
+	n := 0
+	for _, group := range groups {
+		if _, ok := e.killedGroups[strings.Join(group, ",")]; ok {
+			killed = append(killed, group)
+			continue
+		}
+		groups[n] = group
+		n++
+	}
+	e.CompactionPlan.Release(killed)
+	return groups[:n]
+}
+
 // compactionStrategy holds the details of what to do in a compaction.
 type compactionStrategy struct {
 	group CompactionGroup
@@ -2226,15 +2483,16 @@ type compactionStrategy struct {
 	engine *Engine
 }
 
-// Apply concurrently compacts all the groups in a compaction strategy.
-func (s *compactionStrategy) Apply() {
+// Apply concurrently compacts all the groups in a compaction strategy. The
+// compaction is aborted if ctx is canceled.
+func (s *compactionStrategy) Apply(ctx context.Context) {
 	start := time.Now()
-	s.compactGroup()
+	s.compactGroup(ctx)
 	atomic.AddInt64(s.durationStat, time.Since(start).Nanoseconds())
 }
 
 // compactGroup executes the compaction strategy against a single CompactionGroup.
-func (s *compactionStrategy) compactGroup() {
+func (s *compactionStrategy) compactGroup(ctx context.Context) {
 	group := s.group
 	log, logEnd := logger.NewOperation(s.logger, "TSM compaction", "tsm1_compact_group", logger.Shard(s.engine.id))
 	defer logEnd()
@@ -2250,14 +2508,14 @@ func (s *compactionStrategy) compactGroup() {
 	)
 
 	if s.fast {
-		files, err = s.compactor.CompactFast(group)
+		files, err = s.compactor.CompactFastContext(ctx, group)
 	} else {
-		files, err = s.compactor.CompactFull(group)
+		files, err = s.compactor.CompactFullContext(ctx, group)
 	}
 
 	if err != nil {
 		_, inProgress := err.(errCompactionInProgress)
-		if err == errCompactionsDisabled || inProgress {
+		if err == errCompactionsDisabled || inProgress || ctx.Err() != nil {
 			log.Info("Aborted compaction", zap.Error(err))
 
 			if _, ok := err.(errCompactionInProgress); ok {
@@ -5,11 +5,13 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/ayang64/reflux/logger"
 	"github.com/ayang64/reflux/models"
+	"github.com/ayang64/reflux/pkg/limiter"
 	"github.com/ayang64/reflux/tsdb"
 	"github.com/ayang64/reflux/tsdb/index/inmem"
 )
@@ -93,6 +95,97 @@ func TestEngine_ConcurrentShardSnapshots(t *testing.T) {
 	realEngineStruct.Cache.snapshotting = false
 }
 
+// Ensures a killed full compaction is not started again until new points are
+// written to the shard.
+func TestEngine_KilledCompaction(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "shard_test")
+	if err != nil {
+		t.Fatalf("error creating temporary directory: %s", err.Error())
+	}
+	defer os.RemoveAll(tmpDir)
+
+	sfile := NewSeriesFile(tmpDir)
+	defer sfile.Close()
+
+	opts := tsdb.NewEngineOptions()
+	opts.Config.WALDir = filepath.Join(tmpDir, "wal")
+	opts.InmemIndex = inmem.NewIndex(filepath.Base(tmpDir), sfile)
+	opts.SeriesIDSets = seriesIDSets([]*tsdb.SeriesIDSet{})
+	opts.CompactionLimiter = limiter.NewFixed(1)
+
+	sh := tsdb.NewShard(1, filepath.Join(tmpDir, "shard"), filepath.Join(tmpDir, "wal"), sfile, opts)
+	if err := sh.Open(); err != nil {
+		t.Fatalf("error opening shard: %s", err.Error())
+	}
+	defer sh.Close()
+
+	write := func(ts int64) {
+		t.Helper()
+		if err := sh.WritePoints([]models.Point{models.MustNewPoint(
+			"cpu",
+			models.NewTags(map[string]string{"host": "server"}),
+			map[string]interface{}{"value": 1.0},
+			time.Unix(ts, 0),
+		)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(0)
+
+	ei, err := sh.Engine()
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := ei.(*Engine)
+	if err := e.WriteSnapshot(); err != nil {
+		t.Fatal(err)
+	}
+
+	var group CompactionGroup
+	for _, f := range e.FileStore.Files() {
+		group = append(group, f.Path())
+	}
+	planner := &fullPlanner{group: group}
+
+	e.SetCompactionsEnabled(false)
+	e.CompactionPlan = planner
+	e.compactionKilled(group)
+	e.SetCompactionsEnabled(true)
+
+	// Give the planner a few chances to start the compaction.
+	time.Sleep(2500 * time.Millisecond)
+	if n := atomic.LoadInt64(&e.stats.TSMFullCompactions) + atomic.LoadInt64(&e.stats.TSMFullCompactionErrors); n != 0 {
+		t.Fatalf("killed compaction started again %d times", n)
+	} else if atomic.LoadInt64(&planner.released) == 0 {
+		t.Fatal("expected the killed group to be released")
+	}
+
+	// The compaction is started again once points are written.
+	write(1)
+	for deadline := time.Now().Add(10 * time.Second); atomic.LoadInt64(&e.stats.TSMFullCompactions)+atomic.LoadInt64(&e.stats.TSMFullCompactionErrors) == 0; {
+		if time.Now().After(deadline) {
+			t.Fatal("compaction was not started after a write")
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
+
+// fullPlanner plans a full compaction of the same group every time.
+type fullPlanner struct {
+	group    CompactionGroup
+	released int64
+}
+
+func (p *fullPlanner) Plan(lastWrite time.Time) []CompactionGroup { return []CompactionGroup{p.group} }
+func (p *fullPlanner) PlanLevel(level int) []CompactionGroup      { return nil }
+func (p *fullPlanner) PlanOptimize() []CompactionGroup            { return nil }
+func (p *fullPlanner) FullyCompacted() bool                       { return false }
+func (p *fullPlanner) ForceFull()                                 {}
+func (p *fullPlanner) SetFileStore(fs *FileStore)                 {}
+func (p *fullPlanner) Release(groups []CompactionGroup) {
+	atomic.AddInt64(&p.released, int64(len(groups)))
+}
+
 // NewSeriesFile returns a new instance of SeriesFile with a temporary file path.
 func NewSeriesFile(tmpDir string) *tsdb.SeriesFile {
 	dir, err := ioutil.TempDir(tmpDir, "tsdb-series-file-")
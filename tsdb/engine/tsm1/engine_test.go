@@ -1242,6 +1242,156 @@ func TestEngine_DeleteSeries(t *testing.T) {
 	}
 }
 
+func TestEngine_DeleteField(t *testing.T) {
+	for _, index := range tsdb.RegisteredIndexes() {
+		t.Run(index, func(t *testing.T) {
+			p1 := MustParsePointString("cpu,host=A value=1i,count=1i 1000000000")
+			p2 := MustParsePointString("cpu,host=B value=2i 2000000000")
+			p3 := MustParsePointString("mem,host=A value=3i 1000000000") // Should not be deleted
+
+			e, err := NewEngine(index)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// mock the planner so compactions don't run during the test
+			e.CompactionPlan = &mockPlanner{}
+			if err := e.Open(); err != nil {
+				t.Fatal(err)
+			}
+			defer e.Close()
+
+			for _, p := range []models.Point{p1, p2, p3} {
+				if err := e.CreateSeriesIfNotExists(p.Key(), p.Name(), p.Tags()); err != nil {
+					t.Fatalf("create series index error: %v", err)
+				}
+			}
+			for _, f := range []struct{ name, field string }{{"cpu", "value"}, {"cpu", "count"}, {"mem", "value"}} {
+				if err := e.MeasurementFields([]byte(f.name)).CreateFieldIfNotExists([]byte(f.field), influxql.Integer); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			// Leave the second point in the cache.
+			if err := e.WritePoints([]models.Point{p1, p3}); err != nil {
+				t.Fatalf("failed to write points: %s", err.Error())
+			}
+			if err := e.WriteSnapshot(); err != nil {
+				t.Fatalf("failed to snapshot: %s", err.Error())
+			}
+			if err := e.WritePoints([]models.Point{p2}); err != nil {
+				t.Fatalf("failed to write points: %s", err.Error())
+			}
+
+			if err := e.DeleteField([]byte("cpu"), "value"); err != nil {
+				t.Fatalf("failed to delete field: %v", err)
+			}
+
+			keys := e.FileStore.Keys()
+			if _, ok := keys["cpu,host=A#!~#value"]; ok || len(keys) != 2 {
+				t.Fatalf("unexpected keys after delete: %v", keys)
+			}
+			if n := e.Cache.Values([]byte("cpu,host=B#!~#value")).Len(); n != 0 {
+				t.Fatalf("unexpected values in cache: %d", n)
+			}
+			if fields := e.MeasurementFieldSet().Fields([]byte("cpu")).FieldKeys(); !reflect.DeepEqual(fields, []string{"count"}) {
+				t.Fatalf("unexpected fields: %v", fields)
+			}
+
+			// Dropping the last field drops the measurement.
+			if err := e.DeleteField([]byte("cpu"), "count"); err != nil {
+				t.Fatalf("failed to delete field: %v", err)
+			}
+			if mf := e.MeasurementFieldSet().Fields([]byte("cpu")); mf != nil {
+				t.Fatalf("unexpected fields: %v", mf.FieldKeys())
+			}
+			if ok, err := e.MeasurementExists([]byte("cpu")); err != nil {
+				t.Fatal(err)
+			} else if ok {
+				t.Fatal("measurement was not deleted")
+			}
+			if ok, err := e.MeasurementExists([]byte("mem")); err != nil {
+				t.Fatal(err)
+			} else if !ok {
+				t.Fatal("wrong measurement deleted")
+			}
+		})
+	}
+}
+
+func TestEngine_ConvertField(t *testing.T) {
+	for _, index := range tsdb.RegisteredIndexes() {
+		t.Run(index, func(t *testing.T) {
+			p1 := MustParsePointString("cpu,host=A value=1i 1000000000")
+			p2 := MustParsePointString("cpu,host=B value=2i 2000000000")
+			p3 := MustParsePointString("mem,host=A value=3i 1000000000") // Should not be converted
+
+			e, err := NewEngine(index)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// mock the planner so compactions don't run during the test
+			e.CompactionPlan = &mockPlanner{}
+			if err := e.Open(); err != nil {
+				t.Fatal(err)
+			}
+			defer e.Close()
+
+			for _, p := range []models.Point{p1, p2, p3} {
+				if err := e.CreateSeriesIfNotExists(p.Key(), p.Name(), p.Tags()); err != nil {
+					t.Fatalf("create series index error: %v", err)
+				}
+				if err := e.MeasurementFields(p.Name()).CreateFieldIfNotExists([]byte("value"), influxql.Integer); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			// Leave the second point in the cache.
+			if err := e.WritePoints([]models.Point{p1, p3}); err != nil {
+				t.Fatalf("failed to write points: %s", err.Error())
+			}
+			if err := e.WriteSnapshot(); err != nil {
+				t.Fatalf("failed to snapshot: %s", err.Error())
+			}
+			if err := e.WritePoints([]models.Point{p2}); err != nil {
+				t.Fatalf("failed to write points: %s", err.Error())
+			}
+
+			if err := e.ConvertField([]byte("cpu"), "value", influxql.Boolean); err != tsdb.ErrFieldTypeConversion {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if err := e.ConvertField([]byte("cpu"), "value", influxql.Float); err != nil {
+				t.Fatalf("failed to convert field: %v", err)
+			}
+
+			for _, tt := range []struct {
+				key string
+				ts  int64
+				exp interface{}
+			}{
+				{"cpu,host=A#!~#value", 1000000000, float64(1)},
+				{"cpu,host=B#!~#value", 2000000000, float64(2)},
+				{"mem,host=A#!~#value", 1000000000, int64(3)},
+			} {
+				values, err := e.FileStore.Read([]byte(tt.key), tt.ts)
+				if err != nil {
+					t.Fatal(err)
+				} else if len(values) != 1 || values[0].Value() != tt.exp {
+					t.Fatalf("unexpected values for %s: %v", tt.key, values)
+				}
+			}
+
+			if got := e.MeasurementFieldSet().Fields([]byte("cpu")).Field("value").Type; got != influxql.Float {
+				t.Fatalf("unexpected field type: %s", got)
+			}
+			if got := e.MeasurementFieldSet().Fields([]byte("mem")).Field("value").Type; got != influxql.Integer {
+				t.Fatalf("unexpected field type: %s", got)
+			}
+		})
+	}
+}
+
 func TestEngine_DeleteSeriesRange(t *testing.T) {
 	for _, index := range tsdb.RegisteredIndexes() {
 		t.Run(index, func(t *testing.T) {
@@ -3,6 +3,8 @@ package tsdb
 import (
 	"bytes"
 	"fmt"
+	"math"
+	"strconv"
 
 	"github.com/ayang64/reflux/models"
 	"github.com/ayang64/reflux/influxql"
@@ -50,6 +52,158 @@ func (defaultFieldValidator) Validate(mf *MeasurementFields, point models.Point)
 	return nil
 }
 
+// FieldTypePolicy determines how a value is written when its type conflicts
+// with the type of an existing field.
+type FieldTypePolicy int
+
+const (
+	// FieldTypeStrict rejects points with values that conflict with an
+	// existing field.
+	FieldTypeStrict FieldTypePolicy = iota
+
+	// FieldTypeCoerceNumeric writes integers to float fields and floats to
+	// integer fields when no precision is lost.
+	FieldTypeCoerceNumeric
+
+	// FieldTypeStringify coerces numbers like FieldTypeCoerceNumeric and also
+	// writes any value to a string field as its string form.
+	FieldTypeStringify
+)
+
+// ParseFieldTypePolicy returns the policy with the given name. An empty name
+// is the strict policy.
+func ParseFieldTypePolicy(s string) (FieldTypePolicy, error) {
+	switch s {
+	case "", "strict":
+		return FieldTypeStrict, nil
+	case "coerce-numeric":
+		return FieldTypeCoerceNumeric, nil
+	case "stringify":
+		return FieldTypeStringify, nil
+	default:
+		return FieldTypeStrict, fmt.Errorf("invalid field type policy: %q", s)
+	}
+}
+
+// String returns the name of the policy.
+func (p FieldTypePolicy) String() string {
+	switch p {
+	case FieldTypeCoerceNumeric:
+		return "coerce-numeric"
+	case FieldTypeStringify:
+		return "stringify"
+	default:
+		return "strict"
+	}
+}
+
+// coerceFields returns the point with the values that conflict with the type
+// of an existing field converted according to the policy, and the number of
+// values that were converted. Values that cannot be converted are left as they
+// are so that the field validator can reject them.
+func coerceFields(policy FieldTypePolicy, mf *MeasurementFields, point models.Point) (models.Point, int) {
+	var converted models.Fields
+	iter := point.FieldIterator()
+	for iter.Next() {
+		if bytes.Equal(iter.FieldKey(), timeBytes) {
+			continue
+		}
+
+		f := mf.FieldBytes(iter.FieldKey())
+		if f == nil || f.Type == dataTypeFromModelsFieldType(iter.Type()) {
+			continue
+		}
+
+		if v, ok := coerceValue(policy, iter, f.Type); ok {
+			if converted == nil {
+				converted = make(models.Fields)
+			}
+			converted[string(iter.FieldKey())] = v
+		}
+	}
+	if len(converted) == 0 {
+		return point, 0
+	}
+
+	fields, err := point.Fields()
+	if err != nil {
+		return point, 0
+	}
+	for k, v := range converted {
+		fields[k] = v
+	}
+
+	pt, err := models.NewPoint(string(point.Name()), point.Tags(), fields, point.Time())
+	if err != nil {
+		return point, 0
+	}
+	return pt, len(converted)
+}
+
+// coerceValue converts the current value of iter to typ if the policy allows it.
+func coerceValue(policy FieldTypePolicy, iter models.FieldIterator, typ influxql.DataType) (interface{}, bool) {
+	if policy == FieldTypeStrict {
+		return nil, false
+	}
+
+	switch typ {
+	case influxql.Float:
+		switch iter.Type() {
+		case models.Integer:
+			v, err := iter.IntegerValue()
+			return float64(v), err == nil
+		case models.Unsigned:
+			v, err := iter.UnsignedValue()
+			return float64(v), err == nil
+		}
+	case influxql.Integer:
+		switch iter.Type() {
+		case models.Float:
+			v, err := iter.FloatValue()
+			if err == nil && v == math.Trunc(v) && v >= math.MinInt64 && v < math.MaxInt64 {
+				return int64(v), true
+			}
+		case models.Unsigned:
+			v, err := iter.UnsignedValue()
+			if err == nil && v <= math.MaxInt64 {
+				return int64(v), true
+			}
+		}
+	case influxql.Unsigned:
+		switch iter.Type() {
+		case models.Float:
+			v, err := iter.FloatValue()
+			if err == nil && v == math.Trunc(v) && v >= 0 && v < math.MaxUint64 {
+				return uint64(v), true
+			}
+		case models.Integer:
+			v, err := iter.IntegerValue()
+			if err == nil && v >= 0 {
+				return uint64(v), true
+			}
+		}
+	case influxql.String:
+		if policy != FieldTypeStringify {
+			break
+		}
+		switch iter.Type() {
+		case models.Float:
+			v, err := iter.FloatValue()
+			return strconv.FormatFloat(v, 'f', -1, 64), err == nil
+		case models.Integer:
+			v, err := iter.IntegerValue()
+			return strconv.FormatInt(v, 10), err == nil
+		case models.Unsigned:
+			v, err := iter.UnsignedValue()
+			return strconv.FormatUint(v, 10), err == nil
+		case models.Boolean:
+			v, err := iter.BooleanValue()
+			return strconv.FormatBool(v), err == nil
+		}
+	}
+	return nil, false
+}
+
 // dataTypeFromModelsFieldType returns the influxql.DataType that corresponds to the
 // passed in field type. If there is no good match, it returns Unknown.
 func dataTypeFromModelsFieldType(fieldType models.FieldType) influxql.DataType {
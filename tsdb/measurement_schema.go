@@ -0,0 +1,177 @@
+package tsdb
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/ayang64/reflux/influxql"
+	"github.com/ayang64/reflux/models"
+)
+
+// ErrMeasurementSchemaViolation is returned when a point does not match the
+// schema of its measurement.
+var ErrMeasurementSchemaViolation = errors.New("measurement schema violation")
+
+// MeasurementSchema constrains the points written to a measurement.
+type MeasurementSchema struct {
+	// Fields maps the fields that may be written to their types. If nil,
+	// any field may be written.
+	Fields map[string]influxql.DataType
+
+	// Tags are the tag keys that may be written. If nil, any tag key may be
+	// written.
+	Tags []TagSchema
+
+	// WarnOnly reports points that violate the schema instead of dropping them.
+	WarnOnly bool
+}
+
+// TagSchema constrains the values of a tag key.
+type TagSchema struct {
+	Key string
+
+	// Required is true if every point must have the tag.
+	Required bool
+
+	// Values must match every value of the tag if it is set.
+	Values *regexp.Regexp
+
+	// MaxValues is the number of distinct values the tag may have in a
+	// shard. Zero means there is no limit.
+	MaxValues int
+}
+
+// MeasurementSchemas maps the names of measurements to their schemas.
+type MeasurementSchemas map[string]*MeasurementSchema
+
+// tag returns the schema of a tag key or nil if the key is not declared.
+func (s *MeasurementSchema) tag(key []byte) *TagSchema {
+	for i := range s.Tags {
+		if s.Tags[i].Key == string(key) {
+			return &s.Tags[i]
+		}
+	}
+	return nil
+}
+
+// Validate returns an error describing the first way the point does not
+// match the schema. The number of distinct values of each tag is checked by
+// the shard the point is written to.
+func (s *MeasurementSchema) Validate(p models.Point) error {
+	if s.Tags != nil {
+		tags := p.Tags()
+		for _, t := range tags {
+			ts := s.tag(t.Key)
+			if ts == nil {
+				return fmt.Errorf("tag key %q is not allowed", t.Key)
+			} else if ts.Values != nil && !ts.Values.Match(t.Value) {
+				return fmt.Errorf("value %q of tag key %q does not match /%s/", t.Value, t.Key, ts.Values)
+			}
+		}
+		for _, ts := range s.Tags {
+			if ts.Required && tags.Get([]byte(ts.Key)) == nil {
+				return fmt.Errorf("required tag key %q is missing", ts.Key)
+			}
+		}
+	}
+
+	if s.Fields != nil {
+		iter := p.FieldIterator()
+		for iter.Next() {
+			typ, ok := s.Fields[string(iter.FieldKey())]
+			if !ok {
+				return fmt.Errorf("field %q is not allowed", iter.FieldKey())
+			} else if got := dataTypeFromModelsFieldType(iter.Type()); got != typ {
+				return fmt.Errorf("field %q is type %s, schema requires %s", iter.FieldKey(), got, typ)
+			}
+		}
+	}
+	return nil
+}
+
+// batchTagValues are the distinct values of a tag key of a measurement while a
+// batch of points is written.
+type batchTagValues struct {
+	n     int                 // the number of values in the index
+	added map[string]struct{} // the values added by the batch
+}
+
+// tagValueSet tracks the tag values of a batch of points by measurement and
+// tag key.
+type tagValueSet map[string]*batchTagValues
+
+// checkTagValueLimits returns a violation if the tags would add a value to a
+// tag key that already has as many distinct values as the schema allows.
+// The values of each tag key are counted in the index once per batch, and the
+// values added by earlier points of the batch are tracked in added.
+func (s *Shard) checkTagValueLimits(schema *MeasurementSchema, name []byte, tags models.Tags, added tagValueSet) (string, error) {
+	var newTags []*batchTagValues
+	var newValues []string
+	for _, t := range tags {
+		ts := schema.tag(t.Key)
+		if ts == nil || ts.MaxValues <= 0 {
+			continue
+		}
+
+		k := string(name) + "\x00" + string(t.Key)
+		tv := added[k]
+		if tv.has(t.Value) {
+			continue
+		} else if ok, err := s.index.HasTagValue(name, t.Key, t.Value); err != nil {
+			return "", err
+		} else if ok {
+			continue
+		}
+
+		if tv == nil {
+			n, err := tagValueN(s.index, name, t.Key)
+			if err != nil {
+				return "", err
+			}
+			tv = &batchTagValues{n: n, added: make(map[string]struct{})}
+			added[k] = tv
+		}
+		if tv.n+len(tv.added) >= ts.MaxValues {
+			return fmt.Sprintf("tag key %q already has %d distinct values", t.Key, ts.MaxValues), nil
+		}
+		newTags = append(newTags, tv)
+		newValues = append(newValues, string(t.Value))
+	}
+
+	for i, tv := range newTags {
+		tv.added[newValues[i]] = struct{}{}
+	}
+	return "", nil
+}
+
+// has returns true if the value was added by the batch.
+func (tv *batchTagValues) has(value []byte) bool {
+	if tv == nil {
+		return false
+	}
+	_, ok := tv.added[string(value)]
+	return ok
+}
+
+// tagValueN returns the number of distinct values of a tag key.
+func tagValueN(index Index, name, key []byte) (int, error) {
+	itr, err := index.TagValueIterator(name, key)
+	if err != nil {
+		return 0, err
+	} else if itr == nil {
+		return 0, nil
+	}
+	defer itr.Close()
+
+	var n int
+	for {
+		v, err := itr.Next()
+		if err != nil {
+			return 0, err
+		} else if v == nil {
+			return n, nil
+		}
+		n++
+	}
+}
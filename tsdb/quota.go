@@ -0,0 +1,122 @@
+package tsdb
+
+import (
+	"fmt"
+
+	"github.com/ayang64/reflux/models"
+)
+
+// Resources limited by the quota of a database.
+const (
+	QuotaDiskBytes       = "disk"
+	QuotaSeries          = "series"
+	QuotaPointsPerSecond = "points per second"
+	QuotaQueries         = "concurrent queries"
+)
+
+// Quota limits the resources a database may use. It is passed to the store in
+// the WriteQuota context value. A limit of zero is not enforced.
+type Quota struct {
+	// Maximum size of the shards of the database on disk, in bytes. Writes
+	// are rejected once the database has reached it.
+	MaxDiskBytes int64
+
+	// Maximum number of series in the series file of the database. Points
+	// that would create a series beyond it are dropped.
+	MaxSeries int64
+}
+
+// QuotaExceededError is returned when a database has exceeded a resource
+// limited by its quota.
+type QuotaExceededError struct {
+	Database string
+	Resource string
+	Limit    int64
+}
+
+// Error returns the string representation of the error.
+func (e QuotaExceededError) Error() string {
+	return fmt.Sprintf("database %q exceeded its %s quota: limit %d", e.Database, e.Resource, e.Limit)
+}
+
+// DatabaseDiskSize returns the size on disk of the shards of a database in
+// bytes.
+func (s *Store) DatabaseDiskSize(database string) (int64, error) {
+	var size int64
+
+	s.mu.RLock()
+	shards := s.filterShards(byDatabase(database))
+	s.mu.RUnlock()
+
+	for _, sh := range shards {
+		sz, err := sh.DiskSize()
+		if err != nil {
+			return 0, err
+		}
+		size += sz
+	}
+	return size, nil
+}
+
+// DatabaseSeriesCount returns the number of series in the series file of a
+// database. This is the count the series quota is enforced against.
+func (s *Store) DatabaseSeriesCount(database string) int64 {
+	sfile := s.seriesFile(database)
+	if sfile == nil {
+		return 0
+	}
+	return int64(sfile.SeriesCount())
+}
+
+// checkQuota returns the points that may be written to sh under quota. Points
+// that would create a series beyond the series quota are added to rejected and
+// counted in the returned PartialWriteError. An error is returned if the
+// database has reached its disk quota.
+func (s *Store) checkQuota(sh *Shard, quota *Quota, points []models.Point, rejected *RejectedPoints) ([]models.Point, *PartialWriteError, error) {
+	database := sh.Database()
+
+	if quota.MaxDiskBytes > 0 {
+		size, err := s.DatabaseDiskSize(database)
+		if err != nil {
+			return nil, nil, err
+		} else if size >= quota.MaxDiskBytes {
+			return nil, nil, QuotaExceededError{Database: database, Resource: QuotaDiskBytes, Limit: quota.MaxDiskBytes}
+		}
+	}
+
+	if quota.MaxSeries <= 0 || sh.sfile == nil {
+		return points, nil, nil
+	}
+
+	// Fast path: the points cannot create enough series to exceed the quota.
+	n := int64(sh.sfile.SeriesCount())
+	if n+int64(len(points)) <= quota.MaxSeries {
+		return points, nil, nil
+	}
+
+	var (
+		buf     []byte
+		perr    *PartialWriteError
+		created = make(map[string]struct{})
+	)
+	j := 0
+	for _, p := range points {
+		key := string(p.Key())
+		if _, ok := created[key]; !ok && !sh.sfile.HasSeries(p.Name(), p.Tags(), buf) {
+			if n >= quota.MaxSeries {
+				if perr == nil {
+					err := QuotaExceededError{Database: database, Resource: QuotaSeries, Limit: quota.MaxSeries}
+					perr = &PartialWriteError{Reason: err.Error()}
+				}
+				perr.Dropped++
+				rejected.Add(p, RejectSeriesQuota, perr.Reason)
+				continue
+			}
+			created[key] = struct{}{}
+			n++
+		}
+		points[j] = p
+		j++
+	}
+	return points[:j], perr, nil
+}
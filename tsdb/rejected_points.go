@@ -0,0 +1,98 @@
+package tsdb
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/ayang64/reflux/models"
+)
+
+// Codes describing why a point was rejected by a write.
+const (
+	RejectParseError           = "parse_error"
+	RejectUnauthorized         = "unauthorized"
+	RejectOutsideRetention     = "outside_retention_policy"
+	RejectSchemaViolation      = "schema_violation"
+	RejectFieldTypeConflict    = "field_type_conflict"
+	RejectMaxValuesPerTag      = "max_values_per_tag"
+	RejectMaxSeriesPerDatabase = "max_series_per_database"
+	RejectInvalidPoint         = "invalid_point"
+	RejectShardPendingDeletion = "shard_pending_deletion"
+	RejectSeriesQuota          = "series_quota"
+	RejectDiskQuota            = "disk_quota"
+	RejectWriteRateQuota       = "points_per_second_quota"
+)
+
+// RejectedPoint is a point that was not written and the reason why.
+type RejectedPoint struct {
+	Point  models.Point
+	Code   string
+	Reason string
+}
+
+// RejectedPoints collects the points rejected by a write. It is passed to the
+// shards written to in the WriteRejectedPoints context value and is safe for
+// concurrent use. Add is a no-op on a nil RejectedPoints so that writes that
+// don't collect rejections need no checks.
+//
+// Rejected points are reported as the points that were written so that the
+// caller can find them. Points that are rebuilt during the write must be
+// registered with Rebuilt.
+type RejectedPoints struct {
+	mu      sync.Mutex
+	points  []RejectedPoint
+	rebuilt map[models.Point]models.Point
+}
+
+// Add records a rejected point.
+func (r *RejectedPoints) Add(p models.Point, code, reason string) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	if orig, ok := r.rebuilt[p]; ok {
+		p = orig
+	}
+	r.points = append(r.points, RejectedPoint{Point: p, Code: code, Reason: reason})
+	r.mu.Unlock()
+}
+
+// Rebuilt records that p was built from orig, so that orig is reported if p
+// is rejected.
+func (r *RejectedPoints) Rebuilt(orig, p models.Point) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	if o, ok := r.rebuilt[orig]; ok {
+		orig = o
+	}
+	if r.rebuilt == nil {
+		r.rebuilt = make(map[models.Point]models.Point)
+	}
+	r.rebuilt[p] = orig
+	r.mu.Unlock()
+}
+
+// Points returns the rejected points in the order they were added.
+func (r *RejectedPoints) Points() []RejectedPoint {
+	if r == nil {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]RejectedPoint(nil), r.points...)
+}
+
+// seriesRejectCode returns the code of a series the index refused to create
+// from the reason it gave.
+func seriesRejectCode(reason string) string {
+	switch {
+	case strings.HasPrefix(reason, "max-values-per-tag"):
+		return RejectMaxValuesPerTag
+	case strings.HasPrefix(reason, "max-series-per-database"):
+		return RejectMaxSeriesPerDatabase
+	default:
+		return RejectInvalidPoint
+	}
+}
@@ -41,6 +41,8 @@ const (
 	statWritePointsDropped = "writePointsDropped"
 	statWritePointsOK      = "writePointsOk"
 	statWriteValuesOK      = "writeValuesOk"
+	statWriteValuesCoerced = "writeValuesCoerced"
+	statSchemaViolations   = "schemaViolations"
 	statWriteBytes         = "writeBytes"
 	statDiskBytes          = "diskBytes"
 )
@@ -55,6 +57,10 @@ var (
 	// ErrFieldNotFound is returned when a field cannot be found.
 	ErrFieldNotFound = errors.New("field not found")
 
+	// ErrFieldTypeConversion is returned when the values of a field cannot be
+	// rewritten as another type.
+	ErrFieldTypeConversion = errors.New("field type conversion not supported")
+
 	// ErrFieldUnmappedID is returned when the system is presented, during decode, with a field ID
 	// there is no mapping for.
 	ErrFieldUnmappedID = errors.New("field ID not mapped")
@@ -246,6 +252,8 @@ type ShardStatistics struct {
 	WritePointsDropped int64
 	WritePointsOK      int64
 	WriteValuesOK      int64
+	WriteValuesCoerced int64
+	SchemaViolations   int64
 	BytesWritten       int64
 	DiskBytes          int64
 }
@@ -284,6 +292,8 @@ func (s *Shard) Statistics(tags map[string]string) []models.Statistic {
 			statWritePointsDropped: atomic.LoadInt64(&s.stats.WritePointsDropped),
 			statWritePointsOK:      atomic.LoadInt64(&s.stats.WritePointsOK),
 			statWriteValuesOK:      atomic.LoadInt64(&s.stats.WriteValuesOK),
+			statWriteValuesCoerced: atomic.LoadInt64(&s.stats.WriteValuesCoerced),
+			statSchemaViolations:   atomic.LoadInt64(&s.stats.SchemaViolations),
 			statWriteBytes:         atomic.LoadInt64(&s.stats.BytesWritten),
 			statDiskBytes:          atomic.LoadInt64(&s.stats.DiskBytes),
 		},
@@ -503,6 +513,10 @@ type ConetextKey int
 const (
 	StatPointsWritten = ConetextKey(iota)
 	StatValuesWritten
+	WriteFieldTypePolicy
+	WriteMeasurementSchemas
+	WriteRejectedPoints
+	WriteQuota
 )
 
 // WritePointsWithContext() will write the raw data points and any new metadata
@@ -513,6 +527,16 @@ const (
 // StatPointsWritten and the number of values written in the int64 pointer
 // stored in the StatValuesWritten context values.
 //
+// A FieldTypePolicy stored in the WriteFieldTypePolicy context value
+// determines how values that conflict with the type of an existing field are
+// written. Without one, the points with those values are dropped.
+//
+// The MeasurementSchemas stored in the WriteMeasurementSchemas context value
+// limit the number of distinct values the tags of their measurements may have.
+//
+// Every point that is dropped is added with the reason to the *RejectedPoints
+// stored in the WriteRejectedPoints context value.
+//
 func (s *Shard) WritePointsWithContext(ctx context.Context, points []models.Point) error {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
@@ -525,7 +549,7 @@ func (s *Shard) WritePointsWithContext(ctx context.Context, points []models.Poin
 	var writeError error
 	atomic.AddInt64(&s.stats.WriteReq, 1)
 
-	points, fieldsToCreate, err := s.validateSeriesAndFields(points)
+	points, fieldsToCreate, err := s.validateSeriesAndFields(ctx, points)
 	if err != nil {
 		if _, ok := err.(PartialWriteError); !ok {
 			return err
@@ -584,7 +608,12 @@ func (s *Shard) WritePointsWithContext(ctx context.Context, points []models.Poin
 }
 
 // validateSeriesAndFields checks which series and fields are new and whose metadata should be saved and indexed.
-func (s *Shard) validateSeriesAndFields(points []models.Point) ([]models.Point, []*FieldCreate, error) {
+// Values that conflict with the type of an existing field are converted if the field type policy of the write allows it.
+func (s *Shard) validateSeriesAndFields(ctx context.Context, points []models.Point) ([]models.Point, []*FieldCreate, error) {
+	policy, _ := ctx.Value(WriteFieldTypePolicy).(FieldTypePolicy)
+	schemas, _ := ctx.Value(WriteMeasurementSchemas).(MeasurementSchemas)
+	rejected, _ := ctx.Value(WriteRejectedPoints).(*RejectedPoints)
+
 	var (
 		fieldsToCreate []*FieldCreate
 		err            error
@@ -600,6 +629,9 @@ func (s *Shard) validateSeriesAndFields(points []models.Point) ([]models.Point,
 	// Check if keys should be unicode validated.
 	validateKeys := s.options.Config.ValidateKeys
 
+	// Track the tag values added by the points for the limits of the schemas.
+	added := make(tagValueSet)
+
 	var j int
 	for i, p := range points {
 		tags := p.Tags()
@@ -607,23 +639,48 @@ func (s *Shard) validateSeriesAndFields(points []models.Point) ([]models.Point,
 		// Drop any series w/ a "time" tag, these are illegal
 		if v := tags.Get(timeBytes); v != nil {
 			dropped++
+			r := fmt.Sprintf(
+				"invalid tag key: input tag \"%s\" on measurement \"%s\" is invalid",
+				"time", string(p.Name()))
 			if reason == "" {
-				reason = fmt.Sprintf(
-					"invalid tag key: input tag \"%s\" on measurement \"%s\" is invalid",
-					"time", string(p.Name()))
+				reason = r
 			}
+			rejected.Add(p, RejectInvalidPoint, r)
 			continue
 		}
 
 		// Drop any series with invalid unicode characters in the key.
 		if validateKeys && !models.ValidKeyTokens(string(p.Name()), tags) {
 			dropped++
+			r := fmt.Sprintf("key contains invalid unicode: \"%s\"", string(p.Key()))
 			if reason == "" {
-				reason = fmt.Sprintf("key contains invalid unicode: \"%s\"", string(p.Key()))
+				reason = r
 			}
+			rejected.Add(p, RejectInvalidPoint, r)
 			continue
 		}
 
+		// Drop any series that would add too many values to a tag of the measurement.
+		if schema := schemas[string(p.Name())]; schema != nil {
+			violation, err := s.checkTagValueLimits(schema, p.Name(), tags, added)
+			if err != nil {
+				return nil, nil, err
+			} else if violation != "" {
+				atomic.AddInt64(&s.stats.SchemaViolations, 1)
+				if !schema.WarnOnly {
+					dropped++
+					if reason == "" {
+						reason = fmt.Sprintf("%s: %s: %s", ErrMeasurementSchemaViolation, p.Key(), violation)
+					}
+					rejected.Add(p, RejectSchemaViolation, violation)
+					continue
+				}
+				s.logger.Warn("Point violates measurement schema",
+					zap.String("key", string(p.Key())),
+					zap.String("violation", violation))
+			}
+		}
+
 		keys[j] = p.Key()
 		names[j] = p.Name()
 		tagsSlice[j] = tags
@@ -640,6 +697,10 @@ func (s *Shard) validateSeriesAndFields(points []models.Point) ([]models.Point,
 	// Add new series. Check for partial writes.
 	var droppedKeys [][]byte
 	if err := engine.CreateSeriesListIfNotExists(keys, names, tagsSlice); err != nil {
+		// The inmem index returns the error by value.
+		if perr, ok := err.(PartialWriteError); ok {
+			err = &perr
+		}
 		switch err := err.(type) {
 		// TODO(jmw): why is this a *PartialWriteError when everything else is not a pointer?
 		// Maybe we can just change it to be consistent if we change it also in all
@@ -667,23 +728,37 @@ func (s *Shard) validateSeriesAndFields(points []models.Point) ([]models.Point,
 			break
 		}
 		if !validField {
+			r := fmt.Sprintf(
+				"invalid field name: input field \"%s\" on measurement \"%s\" is invalid",
+				"time", string(p.Name()))
 			if reason == "" {
-				reason = fmt.Sprintf(
-					"invalid field name: input field \"%s\" on measurement \"%s\" is invalid",
-					"time", string(p.Name()))
+				reason = r
 			}
+			rejected.Add(p, RejectInvalidPoint, r)
 			dropped++
 			continue
 		}
 
 		// Skip any points whos keys have been dropped. Dropped has already been incremented for them.
-		if len(droppedKeys) > 0 && bytesutil.Contains(droppedKeys, keys[i]) {
+		// The index may have reordered keys, so the key is read from the point.
+		if len(droppedKeys) > 0 && bytesutil.Contains(droppedKeys, p.Key()) {
+			rejected.Add(p, seriesRejectCode(reason), reason)
 			continue
 		}
 
 		name := p.Name()
 		mf := engine.MeasurementFields(name)
 
+		// Convert values that conflict with existing fields before validating them.
+		var coerced int
+		if policy != FieldTypeStrict {
+			if pt, n := coerceFields(policy, mf, p); n > 0 {
+				rejected.Rebuilt(p, pt)
+				p, points[i], coerced = pt, pt, n
+				iter = p.FieldIterator()
+			}
+		}
+
 		// Check with the field validator.
 		if err := s.options.FieldValidator.Validate(mf, p); err != nil {
 			switch err := err.(type) {
@@ -693,11 +768,13 @@ func (s *Shard) validateSeriesAndFields(points []models.Point) ([]models.Point,
 				}
 				dropped += err.Dropped
 				atomic.AddInt64(&s.stats.WritePointsDropped, int64(err.Dropped))
+				rejected.Add(p, RejectFieldTypeConflict, err.Reason)
 			default:
 				return nil, nil, err
 			}
 			continue
 		}
+		atomic.AddInt64(&s.stats.WriteValuesCoerced, int64(coerced))
 
 		points[j] = points[i]
 		j++
@@ -793,6 +870,26 @@ func (s *Shard) DeleteMeasurement(name []byte) error {
 	return engine.DeleteMeasurement(name)
 }
 
+// DeleteField deletes the values of a field and removes the field from the
+// measurement.
+func (s *Shard) DeleteField(name []byte, field string) error {
+	engine, err := s.Engine()
+	if err != nil {
+		return err
+	}
+	return engine.DeleteField(name, field)
+}
+
+// ConvertField rewrites the values of a field as typ and changes the type of
+// the field on the measurement.
+func (s *Shard) ConvertField(name []byte, field string, typ influxql.DataType) error {
+	engine, err := s.Engine()
+	if err != nil {
+		return err
+	}
+	return engine.ConvertField(name, field, typ)
+}
+
 // SeriesN returns the unique number of series in the shard.
 func (s *Shard) SeriesN() int64 {
 	engine, err := s.Engine()
@@ -1577,6 +1674,48 @@ func (m *MeasurementFields) CreateFieldIfNotExists(name []byte, typ influxql.Dat
 	return nil
 }
 
+// DeleteField removes the field for name. Returns false if there is no field
+// for name.
+func (m *MeasurementFields) DeleteField(name string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	fields := m.fields.Load().(map[string]*Field)
+	if fields[name] == nil {
+		return false
+	}
+
+	fieldsUpdate := make(map[string]*Field, len(fields)-1)
+	for k, v := range fields {
+		if k != name {
+			fieldsUpdate[k] = v
+		}
+	}
+	m.fields.Store(fieldsUpdate)
+	return true
+}
+
+// SetFieldType changes the type of the field for name. Returns
+// ErrFieldNotFound if there is no field for name.
+func (m *MeasurementFields) SetFieldType(name string, typ influxql.DataType) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	fields := m.fields.Load().(map[string]*Field)
+	f := fields[name]
+	if f == nil {
+		return ErrFieldNotFound
+	}
+
+	fieldsUpdate := make(map[string]*Field, len(fields))
+	for k, v := range fields {
+		fieldsUpdate[k] = v
+	}
+	fieldsUpdate[name] = &Field{ID: f.ID, Name: f.Name, Type: typ}
+	m.fields.Store(fieldsUpdate)
+	return nil
+}
+
 func (m *MeasurementFields) FieldN() int {
 	n := len(m.fields.Load().(map[string]*Field))
 	return n
@@ -1841,6 +1980,21 @@ type Field struct {
 	Type influxql.DataType `json:"type,omitempty"`
 }
 
+// CanConvertFieldType returns true if every value of a field of type from can
+// be rewritten as type to. Numbers and booleans can become floats, booleans
+// can become integers and any value can become a string.
+func CanConvertFieldType(from, to influxql.DataType) bool {
+	switch to {
+	case influxql.Float:
+		return from == influxql.Integer || from == influxql.Unsigned || from == influxql.Boolean
+	case influxql.Integer, influxql.Unsigned:
+		return from == influxql.Boolean
+	case influxql.String:
+		return from == influxql.Float || from == influxql.Integer || from == influxql.Unsigned || from == influxql.Boolean
+	}
+	return false
+}
+
 // NewFieldKeysIterator returns an iterator that can be iterated over to
 // retrieve field keys.
 func NewFieldKeysIterator(sh *Shard, opt query.IteratorOptions) (query.Iterator, error) {
@@ -400,6 +400,197 @@ func TestShardWriteAddNewField(t *testing.T) {
 	}
 }
 
+func TestShard_WritePoints_FieldTypePolicy(t *testing.T) {
+	tmpDir, _ := ioutil.TempDir("", "shard_test")
+	defer os.RemoveAll(tmpDir)
+	tmpShard := filepath.Join(tmpDir, "shard")
+	tmpWal := filepath.Join(tmpDir, "wal")
+
+	sfile := MustOpenSeriesFile()
+	defer sfile.Close()
+
+	opts := tsdb.NewEngineOptions()
+	opts.Config.WALDir = filepath.Join(tmpDir, "wal")
+	opts.InmemIndex = inmem.NewIndex(filepath.Base(tmpDir), sfile.SeriesFile)
+
+	sh := tsdb.NewShard(1, tmpShard, tmpWal, sfile.SeriesFile, opts)
+	if err := sh.Open(); err != nil {
+		t.Fatalf("error opening shard: %s", err.Error())
+	}
+	defer sh.Close()
+
+	write := func(policy tsdb.FieldTypePolicy, fields map[string]interface{}) error {
+		pt := models.MustNewPoint(
+			"cpu",
+			models.NewTags(map[string]string{"host": "server"}),
+			fields,
+			time.Unix(1, 2),
+		)
+		ctx := context.WithValue(context.Background(), tsdb.WriteFieldTypePolicy, policy)
+		return sh.WritePointsWithContext(ctx, []models.Point{pt})
+	}
+
+	if err := write(tsdb.FieldTypeStrict, map[string]interface{}{"value": 1.0, "count": int64(1), "state": "ok"}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct {
+		policy tsdb.FieldTypePolicy
+		fields map[string]interface{}
+		ok     bool
+	}{
+		{policy: tsdb.FieldTypeStrict, fields: map[string]interface{}{"value": int64(2)}},
+		{policy: tsdb.FieldTypeCoerceNumeric, fields: map[string]interface{}{"value": int64(2)}, ok: true},
+		{policy: tsdb.FieldTypeCoerceNumeric, fields: map[string]interface{}{"value": uint64(2), "count": 2.0}, ok: true},
+		{policy: tsdb.FieldTypeCoerceNumeric, fields: map[string]interface{}{"count": 2.5}},
+		{policy: tsdb.FieldTypeCoerceNumeric, fields: map[string]interface{}{"state": true}},
+		{policy: tsdb.FieldTypeStringify, fields: map[string]interface{}{"state": true}, ok: true},
+		{policy: tsdb.FieldTypeStringify, fields: map[string]interface{}{"value": "1.5"}},
+	} {
+		err := write(tt.policy, tt.fields)
+		if tt.ok && err != nil {
+			t.Fatalf("%s %v: unexpected error: %s", tt.policy, tt.fields, err)
+		} else if _, ok := err.(tsdb.PartialWriteError); !tt.ok && !ok {
+			t.Fatalf("%s %v: expected partial write error, got %v", tt.policy, tt.fields, err)
+		}
+	}
+
+	stats := sh.Statistics(nil)
+	if got, exp := stats[0].Values["writeValuesCoerced"], int64(4); got != exp {
+		t.Fatalf("got %v coerced values, exp %d", got, exp)
+	}
+	if got, exp := stats[0].Values["writePointsDropped"], int64(4); got != exp {
+		t.Fatalf("got %v dropped points, exp %d", got, exp)
+	}
+
+	// A point that is rejected after some of its values were converted is
+	// reported as the point that was written.
+	pt := models.MustNewPoint(
+		"cpu",
+		models.NewTags(map[string]string{"host": "server"}),
+		map[string]interface{}{"value": int64(3), "count": 2.5},
+		time.Unix(1, 2),
+	)
+	var rejected tsdb.RejectedPoints
+	ctx := context.WithValue(context.Background(), tsdb.WriteFieldTypePolicy, tsdb.FieldTypeCoerceNumeric)
+	ctx = context.WithValue(ctx, tsdb.WriteRejectedPoints, &rejected)
+	if _, ok := sh.WritePointsWithContext(ctx, []models.Point{pt}).(tsdb.PartialWriteError); !ok {
+		t.Fatal("expected partial write error")
+	} else if got := rejected.Points(); len(got) != 1 || got[0].Point != pt || got[0].Code != tsdb.RejectFieldTypeConflict {
+		t.Fatalf("unexpected rejected points: %+v", got)
+	}
+}
+
+func TestShard_WritePoints_MeasurementSchemaMaxValues(t *testing.T) {
+	tmpDir, _ := ioutil.TempDir("", "shard_test")
+	defer os.RemoveAll(tmpDir)
+	tmpShard := filepath.Join(tmpDir, "shard")
+	tmpWal := filepath.Join(tmpDir, "wal")
+
+	sfile := MustOpenSeriesFile()
+	defer sfile.Close()
+
+	opts := tsdb.NewEngineOptions()
+	opts.Config.WALDir = filepath.Join(tmpDir, "wal")
+	opts.InmemIndex = inmem.NewIndex(filepath.Base(tmpDir), sfile.SeriesFile)
+
+	sh := tsdb.NewShard(1, tmpShard, tmpWal, sfile.SeriesFile, opts)
+	if err := sh.Open(); err != nil {
+		t.Fatalf("error opening shard: %s", err.Error())
+	}
+	defer sh.Close()
+
+	schemas := tsdb.MeasurementSchemas{
+		"cpu": {Tags: []tsdb.TagSchema{{Key: "host", MaxValues: 2}}},
+	}
+	write := func(hosts ...string) error {
+		var points []models.Point
+		for _, host := range hosts {
+			points = append(points, models.MustNewPoint(
+				"cpu",
+				models.NewTags(map[string]string{"host": host}),
+				map[string]interface{}{"value": 1.0},
+				time.Unix(1, 2),
+			))
+		}
+		ctx := context.WithValue(context.Background(), tsdb.WriteMeasurementSchemas, schemas)
+		return sh.WritePointsWithContext(ctx, points)
+	}
+
+	// The third distinct value in a single batch is dropped.
+	if err := write("a", "b", "c"); err == nil {
+		t.Fatal("expected error")
+	} else if perr, ok := err.(tsdb.PartialWriteError); !ok || perr.Dropped != 1 {
+		t.Fatalf("expected partial write error dropping 1 point, got %v", err)
+	}
+
+	// Existing values may still be written; new values may not.
+	if err := write("a", "b"); err != nil {
+		t.Fatal(err)
+	} else if _, ok := write("d").(tsdb.PartialWriteError); !ok {
+		t.Fatal("expected partial write error")
+	}
+
+	stats := sh.Statistics(nil)
+	if got, exp := stats[0].Values["schemaViolations"], int64(2); got != exp {
+		t.Fatalf("got %v schema violations, exp %d", got, exp)
+	}
+}
+
+func TestShard_WritePoints_RejectedPoints(t *testing.T) {
+	tmpDir, _ := ioutil.TempDir("", "shard_test")
+	defer os.RemoveAll(tmpDir)
+	tmpShard := filepath.Join(tmpDir, "shard")
+	tmpWal := filepath.Join(tmpDir, "wal")
+
+	sfile := MustOpenSeriesFile()
+	defer sfile.Close()
+
+	opts := tsdb.NewEngineOptions()
+	opts.Config.WALDir = filepath.Join(tmpDir, "wal")
+	opts.Config.MaxValuesPerTag = 1
+	opts.InmemIndex = inmem.NewIndex(filepath.Base(tmpDir), sfile.SeriesFile)
+
+	sh := tsdb.NewShard(1, tmpShard, tmpWal, sfile.SeriesFile, opts)
+	if err := sh.Open(); err != nil {
+		t.Fatalf("error opening shard: %s", err.Error())
+	}
+	defer sh.Close()
+
+	if err := sh.WritePoints([]models.Point{
+		models.MustNewPoint("cpu", models.NewTags(map[string]string{"host": "a"}), map[string]interface{}{"value": 1.0}, time.Unix(1, 2)),
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	points := []models.Point{
+		models.MustNewPoint("cpu", models.NewTags(map[string]string{"host": "a"}), map[string]interface{}{"value": 2.0}, time.Unix(2, 2)),
+		models.MustNewPoint("cpu", models.NewTags(map[string]string{"time": "now"}), map[string]interface{}{"value": 1.0}, time.Unix(2, 2)),
+		models.MustNewPoint("cpu", models.NewTags(map[string]string{"host": "b"}), map[string]interface{}{"value": 1.0}, time.Unix(2, 2)),
+		models.MustNewPoint("cpu", models.NewTags(map[string]string{"host": "a"}), map[string]interface{}{"value": "x"}, time.Unix(3, 2)),
+	}
+	exp := []string{tsdb.RejectInvalidPoint, tsdb.RejectMaxValuesPerTag, tsdb.RejectFieldTypeConflict}
+	want := []models.Point{points[1], points[2], points[3]}
+
+	var rejected tsdb.RejectedPoints
+	ctx := context.WithValue(context.Background(), tsdb.WriteRejectedPoints, &rejected)
+	if err, ok := sh.WritePointsWithContext(ctx, points).(tsdb.PartialWriteError); !ok {
+		t.Fatal("expected partial write error")
+	} else if err.Dropped != 3 {
+		t.Fatalf("unexpected dropped: %v", err)
+	}
+
+	got := rejected.Points()
+	if len(got) != len(exp) {
+		t.Fatalf("unexpected rejected points: %v", got)
+	}
+	for i := range got {
+		if got[i].Code != exp[i] || got[i].Point != want[i] || got[i].Reason == "" {
+			t.Fatalf("unexpected rejected point %d: %+v", i, got[i])
+		}
+	}
+}
+
 // Tests concurrently writing to the same shard with different field types which
 // can trigger a panic when the shard is snapshotted to TSM files.
 func TestShard_WritePoints_FieldConflictConcurrent(t *testing.T) {
@@ -969,6 +969,90 @@ func (s *Store) DeleteMeasurement(database, name string) error {
 	})
 }
 
+// DeleteField removes a field of a measurement and its values from all shards
+// of a database.
+func (s *Store) DeleteField(database, name, field string) error {
+	s.mu.RLock()
+	shards := s.filterShards(byDatabase(database))
+	epochs := s.epochsForShards(shards)
+	s.mu.RUnlock()
+
+	return s.walkShards(shards, func(sh *Shard) error {
+		if shardField(sh, name, field) == nil {
+			return nil
+		}
+
+		// Writes to the measurement wait until the field is gone so that
+		// they cannot recreate it with the old type.
+		guard := newGuard(influxql.MinTime, influxql.MaxTime, []string{name}, nil)
+		waiter := epochs[sh.id].WaitDelete(guard)
+		waiter.Wait()
+		defer waiter.Done()
+
+		return sh.DeleteField([]byte(name), field)
+	})
+}
+
+// ConvertField changes the type of a field of a measurement in all shards of
+// a database and rewrites the values of the field as the new type. Nothing is
+// changed if the values of the field cannot be converted in any shard.
+func (s *Store) ConvertField(database, name, field string, typ influxql.DataType) error {
+	s.mu.RLock()
+	shards := s.filterShards(byDatabase(database))
+	epochs := s.epochsForShards(shards)
+	s.mu.RUnlock()
+
+	// Check every shard before rewriting any of them.
+	var found bool
+	for _, sh := range shards {
+		f := shardField(sh, name, field)
+		if f == nil {
+			continue
+		} else if f.Type != typ && !CanConvertFieldType(f.Type, typ) {
+			return fmt.Errorf("%s: field %q on measurement %q is type %s, cannot convert to %s",
+				ErrFieldTypeConversion, field, name, f.Type, typ)
+		}
+		found = true
+	}
+	if !found {
+		return ErrFieldNotFound
+	}
+
+	// Limit to 1 conversion at a time since every shard is rewritten on disk.
+	limit := limiter.NewFixed(1)
+	return s.walkShards(shards, func(sh *Shard) error {
+		if shardField(sh, name, field) == nil {
+			return nil
+		}
+
+		limit.Take()
+		defer limit.Release()
+
+		// Writes to the measurement wait until the values are rewritten
+		// so that no values of the old type are written in the meantime.
+		guard := newGuard(influxql.MinTime, influxql.MaxTime, []string{name}, nil)
+		waiter := epochs[sh.id].WaitDelete(guard)
+		waiter.Wait()
+		defer waiter.Done()
+
+		return sh.ConvertField([]byte(name), field, typ)
+	})
+}
+
+// shardField returns the field of the measurement in the shard, or nil if the
+// shard has no such field.
+func shardField(sh *Shard, name, field string) *Field {
+	engine, err := sh.Engine()
+	if err != nil {
+		return nil
+	}
+	mf := engine.MeasurementFieldSet().Fields([]byte(name))
+	if mf == nil {
+		return nil
+	}
+	return mf.Field(field)
+}
+
 // filterShards returns a slice of shards where fn returns true
 // for the shard. If the provided predicate is nil then all shards are returned.
 // filterShards should be called under a lock.
@@ -1263,6 +1347,12 @@ func (s *Store) ShardRelativePath(id uint64) (string, error) {
 // DeleteSeries loops through the local shards and deletes the series data for
 // the passed in series keys.
 func (s *Store) DeleteSeries(database string, sources []influxql.Source, condition influxql.Expr) error {
+	return s.DeleteSeriesContext(context.Background(), database, sources, condition)
+}
+
+// DeleteSeriesContext is like DeleteSeries but stops deleting once ctx is
+// canceled. Series already deleted when ctx is canceled remain deleted.
+func (s *Store) DeleteSeriesContext(ctx context.Context, database string, sources []influxql.Source, condition influxql.Expr) error {
 	// Expand regex expressions in the FROM clause.
 	a, err := s.ExpandSources(sources)
 	if err != nil {
@@ -1330,6 +1420,10 @@ func (s *Store) DeleteSeries(database string, sources []influxql.Source, conditi
 		limit.Take()
 		defer limit.Release()
 
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
 		// install our guard and wait for any prior deletes to finish. the
 		// guard ensures future deletes that could conflict wait for us.
 		waiter := epochs[sh.id].WaitDelete(newGuard(min, max, names, condition))
@@ -1344,6 +1438,10 @@ func (s *Store) DeleteSeries(database string, sources []influxql.Source, conditi
 		indexSet := IndexSet{Indexes: []Index{index}, SeriesFile: sfile}
 		// Find matching series keys for each measurement.
 		for _, name := range names {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
 			itr, err := indexSet.MeasurementSeriesByExprIterator([]byte(name), condition)
 			if err != nil {
 				return err
@@ -1376,6 +1474,14 @@ func (s *Store) WriteToShard(shardID uint64, points []models.Point) error {
 	return s.WriteToShardWithContext(context.Background(), shardID, points)
 }
 
+// WriteToShardWithContext writes a list of points to a shard identified by its
+// ID. The context values described by Shard.WritePointsWithContext are passed
+// to the shard.
+//
+// A *Quota stored in the WriteQuota context value limits the disk size and
+// the number of series of the database of the shard. Writes are rejected with
+// a QuotaExceededError once the database has reached its disk quota, and
+// points that would create series beyond the series quota are dropped.
 func (s *Store) WriteToShardWithContext(ctx context.Context, shardID uint64, points []models.Point) error {
 	s.mu.RLock()
 
@@ -1407,13 +1513,34 @@ func (s *Store) WriteToShardWithContext(ctx context.Context, shardID uint64, poi
 		}
 	}
 
+	// Drop the points over the quota of the database.
+	var quotaErr *PartialWriteError
+	if quota, _ := ctx.Value(WriteQuota).(*Quota); quota != nil {
+		rejected, _ := ctx.Value(WriteRejectedPoints).(*RejectedPoints)
+
+		var err error
+		if points, quotaErr, err = s.checkQuota(sh, quota, points, rejected); err != nil {
+			return err
+		}
+	}
+
 	// Ensure snapshot compactions are enabled since the shard might have been cold
 	// and disabled by the monitor.
 	if sh.IsIdle() {
 		sh.SetCompactionsEnabled(true)
 	}
 
-	return sh.WritePointsWithContext(ctx, points)
+	err := sh.WritePointsWithContext(ctx, points)
+	if quotaErr == nil {
+		return err
+	} else if perr, ok := err.(PartialWriteError); ok {
+		// Report the points dropped by the shard along with the quota.
+		quotaErr.Dropped += perr.Dropped
+		quotaErr.DroppedKeys = perr.DroppedKeys
+	} else if err != nil {
+		return err
+	}
+	return *quotaErr
 }
 
 // MeasurementNames returns a slice of all measurements. Measurements accepts an
@@ -107,6 +107,83 @@ func TestStore_DeleteRetentionPolicy(t *testing.T) {
 	}
 }
 
+// Ensure the store can change the type of a field and drop it across shards.
+func TestStore_ConvertField_DeleteField(t *testing.T) {
+	t.Parallel()
+
+	test := func(index string) {
+		s := MustOpenStore(index)
+		defer s.Close()
+
+		for id := 1; id <= 2; id++ {
+			if err := s.CreateShard("db0", "rp0", uint64(id), true); err != nil {
+				t.Fatal(err)
+			}
+		}
+		s.MustWriteToShardString(1, `cpu,host=a value=1i,ok=true 10`, `mem value=1i 10`)
+		s.MustWriteToShardString(2, `cpu,host=a value=2.5 20`)
+
+		fieldType := func(id uint64, name, field string) influxql.DataType {
+			if f := s.Shard(id).MeasurementFields([]byte(name)).Field(field); f != nil {
+				return f.Type
+			}
+			return influxql.Unknown
+		}
+
+		// A conversion that is not possible in one shard changes nothing.
+		if err := s.ConvertField("db0", "cpu", "value", influxql.Boolean); err == nil || !strings.HasPrefix(err.Error(), tsdb.ErrFieldTypeConversion.Error()) {
+			t.Fatalf("unexpected error: %v", err)
+		} else if got, exp := fieldType(1, "cpu", "value"), influxql.Integer; got != exp {
+			t.Fatalf("unexpected type: got %s, exp %s", got, exp)
+		}
+
+		if err := s.ConvertField("db0", "cpu", "missing", influxql.Float); err != tsdb.ErrFieldNotFound {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		// The field of the other measurement keeps its type.
+		if err := s.ConvertField("db0", "cpu", "value", influxql.Float); err != nil {
+			t.Fatal(err)
+		}
+		for _, tt := range []struct {
+			id    uint64
+			name  string
+			field string
+			typ   influxql.DataType
+		}{
+			{1, "cpu", "value", influxql.Float},
+			{2, "cpu", "value", influxql.Float},
+			{1, "cpu", "ok", influxql.Boolean},
+			{1, "mem", "value", influxql.Integer},
+		} {
+			if got := fieldType(tt.id, tt.name, tt.field); got != tt.typ {
+				t.Errorf("shard %d: unexpected type of %s.%s: got %s, exp %s", tt.id, tt.name, tt.field, got, tt.typ)
+			}
+		}
+
+		// Writes of the new type are accepted.
+		s.MustWriteToShardString(1, `cpu,host=a value=3.5 30`)
+
+		// Dropping the field allows it to be written with any type.
+		if err := s.DeleteField("db0", "cpu", "value"); err != nil {
+			t.Fatal(err)
+		}
+		for id := uint64(1); id <= 2; id++ {
+			if got := fieldType(id, "cpu", "value"); got != influxql.Unknown {
+				t.Errorf("shard %d: field was not dropped, has type %s", id, got)
+			}
+		}
+		if got, exp := fieldType(1, "cpu", "ok"), influxql.Boolean; got != exp {
+			t.Errorf("unexpected type of remaining field: got %s, exp %s", got, exp)
+		}
+		s.MustWriteToShardString(1, `cpu,host=a value="text" 40`)
+	}
+
+	for _, index := range tsdb.RegisteredIndexes() {
+		t.Run(index, func(t *testing.T) { test(index) })
+	}
+}
+
 // Ensure the store can create a new shard.
 func TestStore_CreateShard(t *testing.T) {
 	t.Parallel()
@@ -382,6 +459,74 @@ func TestStore_WriteMixedShards(t *testing.T) {
 	}
 }
 
+func TestStore_WriteToShard_Quota(t *testing.T) {
+	t.Parallel()
+
+	mustParse := func(data string) []models.Point {
+		points, err := models.ParsePointsWithPrecision([]byte(data), time.Time{}, "s")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return points
+	}
+
+	test := func(index string) {
+		s := MustOpenStore(index)
+		defer s.Close()
+
+		if err := s.CreateShard("db0", "rp0", 1, true); err != nil {
+			t.Fatal(err)
+		}
+
+		var rejected tsdb.RejectedPoints
+		ctx := context.WithValue(context.Background(), tsdb.WriteQuota, &tsdb.Quota{MaxSeries: 2})
+		ctx = context.WithValue(ctx, tsdb.WriteRejectedPoints, &rejected)
+
+		// Points of existing series and of new series below the quota are written.
+		points := mustParse("cpu,host=a v=1 10\ncpu,host=b v=1 10\ncpu,host=a v=2 20")
+		if err := s.WriteToShardWithContext(ctx, 1, points); err != nil {
+			t.Fatal(err)
+		}
+
+		points = mustParse("cpu,host=a v=3 30\ncpu,host=c v=1 30\ncpu,host=d v=1 30")
+		err := s.WriteToShardWithContext(ctx, 1, points)
+		if perr, ok := err.(tsdb.PartialWriteError); !ok {
+			t.Fatalf("unexpected error: %v", err)
+		} else if got, exp := perr.Dropped, 2; got != exp {
+			t.Fatalf("got dropped %d, expected %d", got, exp)
+		} else if got, exp := perr.Reason, `database "db0" exceeded its series quota: limit 2`; got != exp {
+			t.Fatalf("got reason %q, expected %q", got, exp)
+		}
+
+		if got, exp := len(rejected.Points()), 2; got != exp {
+			t.Fatalf("got %d rejected points, expected %d", got, exp)
+		} else if got, exp := rejected.Points()[0].Code, tsdb.RejectSeriesQuota; got != exp {
+			t.Fatalf("got code %q, expected %q", got, exp)
+		}
+		if got, exp := s.DatabaseSeriesCount("db0"), int64(2); got != exp {
+			t.Fatalf("got %d series, expected %d", got, exp)
+		}
+
+		// Writes are rejected once the database has reached its disk quota.
+		size, err := s.DatabaseDiskSize("db0")
+		if err != nil {
+			t.Fatal(err)
+		} else if size == 0 {
+			t.Fatal("expected database to use disk")
+		}
+
+		ctx = context.WithValue(context.Background(), tsdb.WriteQuota, &tsdb.Quota{MaxDiskBytes: size})
+		err = s.WriteToShardWithContext(ctx, 1, mustParse("cpu,host=a v=4 40"))
+		if exp := (tsdb.QuotaExceededError{Database: "db0", Resource: tsdb.QuotaDiskBytes, Limit: size}); err != exp {
+			t.Fatalf("got error %v, expected %v", err, exp)
+		}
+	}
+
+	for _, index := range tsdb.RegisteredIndexes() {
+		t.Run(index, func(t *testing.T) { test(index) })
+	}
+}
+
 // Ensure the store does not return an error when delete from a non-existent db.
 func TestStore_DeleteSeries_NonExistentDB(t *testing.T) {
 	t.Parallel()